@@ -5,8 +5,12 @@ import (
 	"os"
 
 	"github.com/steveyegge/gastown/internal/cmd"
+	"github.com/steveyegge/gastown/internal/sandbox"
 )
 
 func main() {
+	// gt sandbox exec re-executes gt for its setup stages; they must run
+	// before any CLI initialization.
+	sandbox.RunStageIfRequested()
 	os.Exit(cmd.Execute())
 }
@@ -139,6 +139,16 @@ func waitForNewestJSONL(ctx context.Context, projectDir string, since time.Time)
 	}
 }
 
+// NewestClaudeCodeSession returns the most recently modified Claude Code
+// conversation log for an agent working in workDir.
+func NewestClaudeCodeSession(workDir string) (string, bool) {
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		return "", false
+	}
+	return newestJSONLIn(projectDir, time.Time{})
+}
+
 // newestJSONLIn returns the most recently modified .jsonl file in dir whose
 // modification time is >= since (skip if since is zero).
 func newestJSONLIn(dir string, since time.Time) (string, bool) {
@@ -277,6 +287,7 @@ type ccContent struct {
 
 	// tool_result (content is a string in the simple case)
 	Content string `json:"content,omitempty"`
+	IsError bool   `json:"is_error,omitempty"`
 }
 
 // parseClaudeCodeLine parses one JSONL line and returns 0 or more AgentEvents.
@@ -331,6 +342,7 @@ func parseClaudeCodeLine(line, sessionID, agentType, nativeSessionID string) []A
 			Role:            entry.Message.Role,
 			Content:         content,
 			Timestamp:       ts,
+			IsError:         c.Type == "tool_result" && c.IsError,
 		})
 	}
 
@@ -70,6 +70,17 @@ func TestParseClaudeCodeLine_ToolUse(t *testing.T) {
 	}
 }
 
+func TestParseClaudeCodeLine_ToolResultError(t *testing.T) {
+	line := `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"exit status 1","is_error":true},{"type":"tool_result","content":"ok"}]}}`
+	events := parseClaudeCodeLine(line, "s1", "claudecode", "test-uuid")
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if !events[0].IsError || events[1].IsError {
+		t.Errorf("IsError = %v, %v; want true, false", events[0].IsError, events[1].IsError)
+	}
+}
+
 func TestParseClaudeCodeLine_SkipsUnknownTypes(t *testing.T) {
 	line := `{"type":"summary","content":"some summary"}`
 	events := parseClaudeCodeLine(line, "s1", "claudecode", "test-uuid")
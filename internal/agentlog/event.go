@@ -21,6 +21,7 @@ type AgentEvent struct {
 	Role            string    // "assistant" or "user"
 	Content         string    // text content; empty for "usage" events
 	Timestamp       time.Time // original timestamp from the conversation log
+	IsError         bool      // tool_result only: the tool reported a failure
 
 	// Token usage fields — non-zero only for EventType == "usage".
 	// One "usage" event is emitted per assistant turn (not per content block).
@@ -0,0 +1,242 @@
+package agentlog
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Thrash kinds reported by ThrashDetector.
+const (
+	// ThrashRepeatedCall is the same tool call (same tool, same input)
+	// issued over and over.
+	ThrashRepeatedCall = "repeated_call"
+
+	// ThrashOscillation is a file edited back and forth: an edit that
+	// undoes an earlier one, or a write restoring earlier content.
+	ThrashOscillation = "edit_oscillation"
+
+	// ThrashRepeatedError is the same tool error coming back again and again.
+	ThrashRepeatedError = "repeated_error"
+)
+
+// ThrashLimits are the detector thresholds. A zero limit disables that kind.
+type ThrashLimits struct {
+	RepeatedCalls  int // identical tool calls within the window
+	Oscillations   int // edit reversals on one file
+	RepeatedErrors int // identical tool errors within the window
+	Window         int // tool calls / errors remembered (default 40)
+}
+
+// DefaultThrashWindow is the number of recent tool calls and errors the
+// detector keeps when ThrashLimits.Window is unset.
+const DefaultThrashWindow = 40
+
+// ThrashFinding is one detected loop.
+type ThrashFinding struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"` // the call, file, or error signature
+	Count   int    `json:"count"`
+}
+
+// Hint returns a short, targeted message to nudge the agent out of the loop.
+func (f ThrashFinding) Hint() string {
+	switch f.Kind {
+	case ThrashRepeatedCall:
+		return fmt.Sprintf("You have made the same tool call %d times (%s). Its result will not change — step back and try a different approach.",
+			f.Count, clip(oneLine(f.Subject), 120))
+	case ThrashOscillation:
+		return fmt.Sprintf("You have edited and reverted %s %d times. Stop toggling: decide which version is right and why before editing it again.",
+			f.Subject, f.Count)
+	case ThrashRepeatedError:
+		return fmt.Sprintf("The same error has come back %d times: %q. Retrying will not fix it — read the error, find the root cause, or ask for help.",
+			f.Count, f.Subject)
+	}
+	return fmt.Sprintf("You appear to be stuck in a loop (%s). Step back and change approach.", f.Kind)
+}
+
+// ThrashDetector consumes a stream of AgentEvents and reports loops.
+// Each (kind, subject) pair is reported once, when it first reaches its
+// limit. The zero value is not usable; call NewThrashDetector.
+type ThrashDetector struct {
+	limits ThrashLimits
+
+	calls      []string // recent tool call keys, oldest first
+	callCounts map[string]int
+	errs       []string // recent error signatures, oldest first
+	errCounts  map[string]int
+
+	edits     map[string][]fileEdit // per-file edit history
+	reversals map[string]int
+
+	reported map[string]bool
+	findings []ThrashFinding
+}
+
+// fileEdit is one edit to a file, reduced to hashes of its before and
+// after text. A Write has no before text.
+type fileEdit struct {
+	before, after [sha256.Size]byte
+	write         bool
+}
+
+// NewThrashDetector returns a detector with the given limits.
+func NewThrashDetector(limits ThrashLimits) *ThrashDetector {
+	if limits.Window <= 0 {
+		limits.Window = DefaultThrashWindow
+	}
+	return &ThrashDetector{
+		limits:     limits,
+		callCounts: make(map[string]int),
+		errCounts:  make(map[string]int),
+		edits:      make(map[string][]fileEdit),
+		reversals:  make(map[string]int),
+		reported:   make(map[string]bool),
+	}
+}
+
+// Observe feeds one event to the detector and returns the findings it
+// triggered, if any.
+func (d *ThrashDetector) Observe(ev AgentEvent) []ThrashFinding {
+	var found []ThrashFinding
+	switch {
+	case ev.EventType == "tool_use":
+		name, input := splitToolUse(ev.Content)
+		key := toolCallKey(name, input)
+		n := push(&d.calls, d.callCounts, key, d.limits.Window)
+		if f, ok := d.check(ThrashRepeatedCall, key, n, d.limits.RepeatedCalls); ok {
+			found = append(found, f)
+		}
+		if file, n, ok := d.observeEdit(name, input); ok {
+			if f, ok := d.check(ThrashOscillation, file, n, d.limits.Oscillations); ok {
+				found = append(found, f)
+			}
+		}
+	case ev.EventType == "tool_result" && ev.IsError:
+		sig := ErrorSignature(ev.Content)
+		if sig == "" {
+			break
+		}
+		n := push(&d.errs, d.errCounts, sig, d.limits.Window)
+		if f, ok := d.check(ThrashRepeatedError, sig, n, d.limits.RepeatedErrors); ok {
+			found = append(found, f)
+		}
+	}
+	d.findings = append(d.findings, found...)
+	return found
+}
+
+// Findings returns everything reported so far, in detection order.
+func (d *ThrashDetector) Findings() []ThrashFinding {
+	return d.findings
+}
+
+func (d *ThrashDetector) check(kind, subject string, count, limit int) (ThrashFinding, bool) {
+	if limit <= 0 || count < limit {
+		return ThrashFinding{}, false
+	}
+	id := kind + "\x00" + subject
+	if d.reported[id] {
+		return ThrashFinding{}, false
+	}
+	d.reported[id] = true
+	return ThrashFinding{Kind: kind, Subject: subject, Count: count}, true
+}
+
+// push appends key to a bounded window, keeping counts in step, and
+// returns key's count within the window.
+func push(window *[]string, counts map[string]int, key string, size int) int {
+	*window = append(*window, key)
+	counts[key]++
+	if len(*window) > size {
+		old := (*window)[0]
+		*window = (*window)[1:]
+		if counts[old]--; counts[old] == 0 {
+			delete(counts, old)
+		}
+	}
+	return counts[key]
+}
+
+// observeEdit records file edits and returns the file's reversal count
+// when this edit undoes an earlier one.
+func (d *ThrashDetector) observeEdit(name string, input map[string]any) (string, int, bool) {
+	file, _ := input["file_path"].(string)
+	if file == "" {
+		return "", 0, false
+	}
+	var e fileEdit
+	switch name {
+	case "Edit":
+		oldText, _ := input["old_string"].(string)
+		newText, _ := input["new_string"].(string)
+		e = fileEdit{before: sha256.Sum256([]byte(oldText)), after: sha256.Sum256([]byte(newText))}
+	case "Write":
+		content, _ := input["content"].(string)
+		e = fileEdit{after: sha256.Sum256([]byte(content)), write: true}
+	default:
+		return "", 0, false
+	}
+
+	history := d.edits[file]
+	reverted := false
+	for i, prev := range history {
+		switch {
+		case e.write && prev.write:
+			// Restoring content written before an intervening write.
+			reverted = prev.after == e.after && i < len(history)-1
+		case !e.write && !prev.write:
+			reverted = prev.before == e.after && prev.after == e.before
+		}
+		if reverted {
+			break
+		}
+	}
+	d.edits[file] = append(history, e)
+	if !reverted {
+		return "", 0, false
+	}
+	d.reversals[file]++
+	return file, d.reversals[file], true
+}
+
+// splitToolUse parses a tool_use event's "Name: {json input}" content.
+func splitToolUse(content string) (string, map[string]any) {
+	name, raw, _ := strings.Cut(content, ": ")
+	var input map[string]any
+	_ = json.Unmarshal([]byte(raw), &input)
+	return name, input
+}
+
+// toolCallKey identifies a tool call for repetition. Shell calls are keyed
+// by command alone so a reworded description does not hide a repeat.
+func toolCallKey(name string, input map[string]any) string {
+	if cmd, ok := input["command"].(string); ok {
+		return name + ": " + strings.TrimSpace(cmd)
+	}
+	if input == nil {
+		return name
+	}
+	raw, _ := json.Marshal(input) // map keys marshal sorted
+	return name + ": " + string(raw)
+}
+
+// digitsRe matches runs of digits, which vary between otherwise identical
+// errors (line numbers, PIDs, durations).
+var digitsRe = regexp.MustCompile(`[0-9]+`)
+
+// maxErrorSignature caps the length of an error signature.
+const maxErrorSignature = 160
+
+// ErrorSignature reduces a tool error message to a key for grouping
+// repeats: its first line with digit runs replaced by "N".
+func ErrorSignature(msg string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	line = digitsRe.ReplaceAllString(strings.TrimSpace(line), "N")
+	if len(line) > maxErrorSignature {
+		line = strings.ToValidUTF8(line[:maxErrorSignature], "")
+	}
+	return line
+}
@@ -0,0 +1,109 @@
+package agentlog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func toolUse(t *testing.T, name string, input map[string]any) AgentEvent {
+	t.Helper()
+	raw, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return AgentEvent{EventType: "tool_use", Content: name + ": " + string(raw)}
+}
+
+func observeAll(d *ThrashDetector, events ...AgentEvent) []ThrashFinding {
+	var found []ThrashFinding
+	for _, ev := range events {
+		found = append(found, d.Observe(ev)...)
+	}
+	return found
+}
+
+func TestThrashDetector_RepeatedCall(t *testing.T) {
+	d := NewThrashDetector(ThrashLimits{RepeatedCalls: 3})
+	var events []AgentEvent
+	for i, desc := range []string{"run tests", "rerun tests", "try again", "once more"} {
+		events = append(events,
+			toolUse(t, "Bash", map[string]any{"command": "go test ./...", "description": desc}),
+			toolUse(t, "Read", map[string]any{"file_path": "/src/a.go", "offset": i}))
+	}
+
+	found := observeAll(d, events...)
+	if len(found) != 1 {
+		t.Fatalf("findings = %+v, want exactly one (reported once)", found)
+	}
+	if found[0].Kind != ThrashRepeatedCall || found[0].Subject != "Bash: go test ./..." || found[0].Count != 3 {
+		t.Errorf("finding = %+v", found[0])
+	}
+	if !strings.Contains(found[0].Hint(), "same tool call 3 times") {
+		t.Errorf("hint = %q", found[0].Hint())
+	}
+}
+
+func TestThrashDetector_WindowForgetsOldCalls(t *testing.T) {
+	d := NewThrashDetector(ThrashLimits{RepeatedCalls: 2, Window: 3})
+	same := toolUse(t, "Bash", map[string]any{"command": "make"})
+	found := observeAll(d,
+		same,
+		toolUse(t, "Read", map[string]any{"file_path": "a"}),
+		toolUse(t, "Read", map[string]any{"file_path": "b"}),
+		toolUse(t, "Read", map[string]any{"file_path": "c"}),
+		same,
+	)
+	if len(found) != 0 {
+		t.Errorf("findings = %+v, want none once the first call left the window", found)
+	}
+}
+
+func TestThrashDetector_EditOscillation(t *testing.T) {
+	d := NewThrashDetector(ThrashLimits{Oscillations: 2})
+	fwd := toolUse(t, "Edit", map[string]any{"file_path": "/src/a.go", "old_string": "x := 1", "new_string": "x := 2"})
+	back := toolUse(t, "Edit", map[string]any{"file_path": "/src/a.go", "old_string": "x := 2", "new_string": "x := 1"})
+
+	if found := observeAll(d, fwd, back); len(found) != 0 {
+		t.Fatalf("one reversal reported: %+v", found)
+	}
+	found := observeAll(d, fwd)
+	if len(found) != 1 || found[0].Kind != ThrashOscillation || found[0].Subject != "/src/a.go" || found[0].Count != 2 {
+		t.Fatalf("findings = %+v", found)
+	}
+}
+
+func TestThrashDetector_WriteRevert(t *testing.T) {
+	d := NewThrashDetector(ThrashLimits{Oscillations: 1})
+	v1 := toolUse(t, "Write", map[string]any{"file_path": "/src/b.go", "content": "package b // v1"})
+	v2 := toolUse(t, "Write", map[string]any{"file_path": "/src/b.go", "content": "package b // v2"})
+
+	if found := observeAll(d, v1, v1); len(found) != 0 {
+		t.Fatalf("rewriting identical content reported as a revert: %+v", found)
+	}
+	found := observeAll(d, v2, v1)
+	if len(found) != 1 || found[0].Kind != ThrashOscillation {
+		t.Fatalf("findings = %+v", found)
+	}
+}
+
+func TestThrashDetector_RepeatedError(t *testing.T) {
+	d := NewThrashDetector(ThrashLimits{RepeatedErrors: 3})
+	found := observeAll(d,
+		AgentEvent{EventType: "tool_result", IsError: true, Content: "a_test.go:12: want 3, got 4"},
+		AgentEvent{EventType: "tool_result", Content: "a_test.go:12: want 3, got 4"},
+		AgentEvent{EventType: "tool_result", IsError: true, Content: "a_test.go:12: want 3, got 5"},
+		AgentEvent{EventType: "tool_result", IsError: true, Content: "a_test.go:14: want 3, got 6\nFAIL"},
+	)
+	if len(found) != 1 || found[0].Kind != ThrashRepeatedError || found[0].Subject != "a_test.go:N: want N, got N" {
+		t.Fatalf("findings = %+v", found)
+	}
+}
+
+func TestThrashDetector_DisabledLimits(t *testing.T) {
+	d := NewThrashDetector(ThrashLimits{})
+	same := toolUse(t, "Bash", map[string]any{"command": "make"})
+	if found := observeAll(d, same, same, same, same, same, same); len(found) != 0 {
+		t.Errorf("zero limits reported %+v", found)
+	}
+}
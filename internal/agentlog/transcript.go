@@ -0,0 +1,145 @@
+package agentlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Per-entry caps used when condensing a transcript. Assistant text carries
+// the reasoning worth asking about, so it gets the most room; tool results
+// are mostly noise once the call that produced them is known.
+const (
+	condenseTextLimit       = 1500
+	condenseToolUseLimit    = 300
+	condenseToolResultLimit = 200
+)
+
+// ReadClaudeCodeSession reads a finished Claude Code JSONL conversation log
+// in one pass, returning the same events Watch would have streamed.
+func ReadClaudeCodeSession(path, sessionID string) ([]AgentEvent, error) {
+	return ReadClaudeCodeSessionTail(path, sessionID, 0)
+}
+
+// ReadClaudeCodeSessionTail is ReadClaudeCodeSession limited to the last
+// maxBytes of the log (0 reads it all). The partial line at the cut is
+// skipped.
+func ReadClaudeCodeSessionTail(path, sessionID string, maxBytes int64) ([]AgentEvent, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: caller-located session log
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReaderSize(f, 256*1024)
+	if fi, err := f.Stat(); err == nil && maxBytes > 0 && fi.Size() > maxBytes {
+		if _, err := f.Seek(fi.Size()-maxBytes, io.SeekStart); err != nil {
+			return nil, err
+		}
+		reader.Reset(f)
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, nil
+		}
+	}
+
+	nativeID := nativeSessionIDFromPath(path)
+	var events []AgentEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			events = append(events, parseClaudeCodeLine(line, sessionID, "claudecode", nativeID)...)
+		}
+		if err != nil {
+			break
+		}
+	}
+	return events, nil
+}
+
+// CondenseTranscript renders events as a plain-text transcript of at most
+// roughly budget bytes, for handing a session's context to another agent.
+// Thinking and usage events are dropped and long entries are clipped. When
+// the result is still over budget, the opening entries (which usually state
+// the task) and as many of the most recent ones as fit are kept, and the
+// middle is elided.
+func CondenseTranscript(events []AgentEvent, budget int) string {
+	var entries []string
+	for _, ev := range events {
+		if e := condenseEvent(ev); e != "" {
+			entries = append(entries, e)
+		}
+	}
+
+	total := 0
+	for _, e := range entries {
+		total += len(e) + 1
+	}
+	if budget <= 0 || total <= budget {
+		return strings.Join(entries, "\n")
+	}
+
+	const keepHead = 3
+	head := entries
+	if len(head) > keepHead {
+		head = head[:keepHead]
+	}
+	used := 0
+	for _, e := range head {
+		used += len(e) + 1
+	}
+	start := len(entries)
+	for start > len(head) && used+len(entries[start-1])+1 <= budget {
+		start--
+		used += len(entries[start]) + 1
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(head, "\n"))
+	if omitted := start - len(head); omitted > 0 {
+		fmt.Fprintf(&b, "\n[… %d entries omitted …]", omitted)
+	}
+	if start < len(entries) {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(entries[start:], "\n"))
+	}
+	return b.String()
+}
+
+// condenseEvent renders one event as a transcript entry, or "" to drop it.
+func condenseEvent(ev AgentEvent) string {
+	content := strings.TrimSpace(ev.Content)
+	if content == "" {
+		return ""
+	}
+	switch ev.EventType {
+	case "text":
+		speaker := "Assistant"
+		if ev.Role == "user" {
+			speaker = "User"
+		}
+		return speaker + ": " + clip(content, condenseTextLimit)
+	case "tool_use":
+		return "[tool] " + clip(oneLine(content), condenseToolUseLimit)
+	case "tool_result":
+		return "[result] " + clip(oneLine(content), condenseToolResultLimit)
+	default:
+		return ""
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func clip(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + " …"
+}
@@ -0,0 +1,77 @@
+package agentlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadClaudeCodeSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc-123.jsonl")
+	lines := []string{
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Looking at billing"}]}}`,
+		`{"type":"summary","summary":"ignored"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ReadClaudeCodeSession(path, "gt-gastown-Toast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].NativeSessionID != "abc-123" || events[1].EventType != "tool_use" {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestReadClaudeCodeSessionTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc-123.jsonl")
+	first := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"early"}]}}`
+	last := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"late"}]}}`
+	if err := os.WriteFile(path, []byte(first+"\n"+last+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ReadClaudeCodeSessionTail(path, "gt-gastown-Toast", int64(len(last)+10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Content != "late" {
+		t.Errorf("events = %+v, want only the last line", events)
+	}
+}
+
+func TestCondenseTranscript(t *testing.T) {
+	events := []AgentEvent{
+		{EventType: "text", Role: "user", Content: "Fix the billing rounding bug"},
+		{EventType: "thinking", Role: "assistant", Content: "hmm"},
+		{EventType: "tool_use", Role: "assistant", Content: "Bash: {\"command\":\n\"go test\"}"},
+		{EventType: "usage", Role: "assistant"},
+	}
+	got := CondenseTranscript(events, 0)
+	want := "User: Fix the billing rounding bug\n[tool] Bash: {\"command\": \"go test\"}"
+	if got != want {
+		t.Errorf("CondenseTranscript = %q, want %q", got, want)
+	}
+
+	// Over budget: the opening entries and the most recent ones survive.
+	var many []AgentEvent
+	for i := 0; i < 100; i++ {
+		many = append(many, AgentEvent{EventType: "text", Role: "assistant", Content: strings.Repeat("x", 50)})
+	}
+	many[0].Content = "the task"
+	many[99].Content = "the end"
+	got = CondenseTranscript(many, 1000)
+	if len(got) > 1100 {
+		t.Errorf("condensed transcript is %d bytes, budget 1000", len(got))
+	}
+	for _, want := range []string{"the task", "entries omitted", "the end"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("condensed transcript missing %q", want)
+		}
+	}
+}
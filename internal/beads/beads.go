@@ -195,6 +195,9 @@ type Issue struct {
 	Labels      []string `json:"labels,omitempty"`
 	Ephemeral   bool     `json:"ephemeral,omitempty"` // Wisp/ephemeral issues, not synced to git
 
+	// EstimatedMinutes is the author's effort estimate (bd create --estimate).
+	EstimatedMinutes int `json:"estimated_minutes,omitempty"`
+
 	// Content fields (parsed from bd show --json)
 	AcceptanceCriteria string `json:"acceptance_criteria,omitempty"`
 
@@ -240,6 +243,8 @@ func HasLabel(issue *Issue, label string) bool {
 
 // HasUncheckedCriteria checks if an issue has acceptance criteria with unchecked items.
 // Returns the count of unchecked items (0 means all checked or no criteria).
+// Machine-checkable criteria (see ParseAcceptanceChecks) are not counted:
+// gt done verifies those itself.
 func HasUncheckedCriteria(issue *Issue) int {
 	if issue == nil || issue.AcceptanceCriteria == "" {
 		return 0
@@ -247,6 +252,9 @@ func HasUncheckedCriteria(issue *Issue) int {
 	count := 0
 	for _, line := range strings.Split(issue.AcceptanceCriteria, "\n") {
 		trimmed := strings.TrimSpace(line)
+		if _, isCheck := parseAcceptanceLine(trimmed); isCheck {
+			continue
+		}
 		if strings.HasPrefix(trimmed, "- [ ] ") {
 			count++
 		}
@@ -490,6 +498,11 @@ type Beads struct {
 	// for closing the store.
 	store beadsdk.Storage
 
+	// stores optionally maps store names ("hq", rig names) to in-process
+	// stores for every database in the town, so routed operations can use
+	// the target database's store. See SetStores.
+	stores map[string]beadsdk.Storage
+
 	// Lazy-cached town root for routing resolution.
 	// Populated on first call to getTownRoot() to avoid filesystem walk on every operation.
 	townRoot     string
@@ -554,12 +567,17 @@ func (b *Beads) ForAgentBead() *Beads {
 		return b
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
+	store := b.store
+	if len(b.stores) > 0 {
+		store = b.stores["hq"]
+	}
 	return &Beads{
 		workDir:    townRoot,
 		beadsDir:   townBeadsDir,
 		isolated:   b.isolated,
 		serverPort: b.serverPort,
-		store:      b.store,
+		store:      store,
+		stores:     b.stores,
 		townRoot:   townRoot,
 		noRoute:    true,
 	}
@@ -644,14 +662,7 @@ func (b *Beads) forIssueID(id string) *Beads {
 	if resolved == "" || resolved == b.getResolvedBeadsDir() {
 		return b
 	}
-	return &Beads{
-		workDir:    b.workDir,
-		beadsDir:   resolved,
-		isolated:   b.isolated,
-		serverPort: b.serverPort,
-		store:      b.store,
-		townRoot:   b.townRoot,
-	}
+	return b.withBeadsDir(b.workDir, resolved)
 }
 
 // Init initializes a new beads database in the working directory.
@@ -1056,6 +1067,37 @@ func (b *Beads) listIssues(opts ListOptions) ([]*Issue, error) {
 	return issues, nil
 }
 
+// listByLabels returns beads carrying all of labels, as bd list --label does.
+// An empty status gives bd list's default view (closed and pinned hidden).
+func (b *Beads) listByLabels(status string, labels ...string) ([]*Issue, error) {
+	if b.store != nil {
+		return b.storeListLabeled(status, labels...)
+	}
+
+	args := []string{"list"}
+	for _, label := range labels {
+		args = append(args, "--label="+label)
+	}
+	if status != "" {
+		args = append(args, "--status="+status)
+	}
+	args = append(args, "--json")
+
+	out, err := b.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 || !isJSONBytes(out) {
+		return nil, nil
+	}
+
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	}
+	return issues, nil
+}
+
 // ListIssueStatuses returns durable issues matching any of the supplied
 // statuses with one bd query. Summary paths use this to avoid multiplying bd
 // subprocesses by status and polecat count.
@@ -1216,8 +1258,9 @@ func isJSONBytes(b []byte) bool {
 // ListMergeRequests returns merge-request beads from both the issues table
 // and the wisps table. MRs are created as ephemeral (wisps) by gt mq submit,
 // but bd list only queries the issues table. This method queries the wisps
-// table via bd sql --json, then hydrates each MR with bd show detail so
-// dependency readiness fields are consistent for display and selection.
+// table via bd sql --json (or the store's wisp search when an in-process store
+// is set), then hydrates each MR with bd show detail so dependency readiness
+// fields are consistent for display and selection.
 func (b *Beads) ListMergeRequests(opts ListOptions) ([]*Issue, error) {
 	// 1. Query issues table (bd list) — don't use Ephemeral since bd query
 	// can't parse colons in label values like "gt:merge-request".
@@ -1233,7 +1276,28 @@ func (b *Beads) ListMergeRequests(opts ListOptions) ([]*Issue, error) {
 		seen[issue.ID] = true
 	}
 
-	// 2. Query wisps table via SQL for merge-request wisps with full data
+	// 2. Query wisps table for merge-request wisps with full data. Like the
+	// SQL path, a failed wisp query degrades to issues-table results.
+	if b.store != nil {
+		wispOpts := ListOptions{Label: "gt:merge-request", Status: "open", Priority: -1, Ephemeral: true}
+		if opts.Label != "" {
+			wispOpts.Label = opts.Label
+		}
+		if opts.Status != "" {
+			wispOpts.Status = strings.ToLower(opts.Status)
+		}
+		if wisps, wispErr := b.storeList(wispOpts); wispErr == nil {
+			for _, wisp := range wisps {
+				if !seen[wisp.ID] {
+					wisp.Ephemeral = true
+					issueResults = append(issueResults, wisp)
+				}
+			}
+		}
+		issueResults = filterMergeRequestsByRig(issueResults, opts.Rig)
+		return b.hydrateMergeRequestDetails(issueResults)
+	}
+
 	statusFilter := "w.status = 'open'"
 	if opts.Status != "" && strings.EqualFold(opts.Status, "all") {
 		statusFilter = "1=1"
@@ -1498,7 +1562,7 @@ func (b *Beads) Show(id string) (*Issue, error) {
 	if !b.noRoute {
 		targetDir := ResolveRoutingTarget(b.getTownRoot(), id, b.getResolvedBeadsDir())
 		if targetDir != b.getResolvedBeadsDir() {
-			target := NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+			target := b.withBeadsDir(filepath.Dir(targetDir), targetDir)
 			return target.Show(id)
 		}
 	}
@@ -1527,14 +1591,21 @@ func (b *Beads) Show(id string) (*Issue, error) {
 
 // FindLatestIssueByTitleAndAssignee finds the newest issue matching the given title and assignee.
 func (b *Beads) FindLatestIssueByTitleAndAssignee(title, assignee string) (*Issue, error) {
-	out, err := b.run("list", "--json", "--limit", "0", "--title", title, "--assignee", assignee)
-	if err != nil {
-		return nil, fmt.Errorf("bd list: %w", err)
-	}
-
 	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	if b.store != nil {
+		found, err := b.storeFindByTitleAndAssignee(title, assignee)
+		if err != nil {
+			return nil, err
+		}
+		issues = found
+	} else {
+		out, err := b.run("list", "--json", "--limit", "0", "--title", title, "--assignee", assignee)
+		if err != nil {
+			return nil, fmt.Errorf("bd list: %w", err)
+		}
+		if err := json.Unmarshal(out, &issues); err != nil {
+			return nil, fmt.Errorf("parsing bd list output: %w", err)
+		}
 	}
 	if len(issues) == 0 {
 		return nil, ErrNotFound
@@ -1577,7 +1648,7 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 			for targetDir, groupIDs := range groups {
 				target := b
 				if targetDir != fallbackDir {
-					target = NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+					target = b.withBeadsDir(filepath.Dir(targetDir), targetDir)
 				}
 				issues, err := target.showMultipleLocal(groupIDs)
 				if err != nil {
@@ -1659,16 +1730,10 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 		return nil, err
 	}
 	if targetDir != "" && targetDir != b.getResolvedBeadsDir() {
-		bdForCreate := &Beads{
-			workDir:    b.workDir,
-			beadsDir:   targetDir,
-			serverPort: b.serverPort,
-			isolated:   b.isolated,
-		}
-		return bdForCreate.Create(opts)
+		return b.withBeadsDir(b.workDir, targetDir).Create(opts)
 	}
 
-	if b.store != nil && !opts.Ephemeral {
+	if b.store != nil {
 		return b.storeCreate(opts)
 	}
 
@@ -1734,13 +1799,11 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 		return nil, err
 	}
 	if targetDir != "" && targetDir != b.getResolvedBeadsDir() {
-		bdForCreate := &Beads{
-			workDir:    b.workDir,
-			beadsDir:   targetDir,
-			serverPort: b.serverPort,
-			isolated:   b.isolated,
-		}
-		return bdForCreate.CreateWithID(id, opts)
+		return b.withBeadsDir(b.workDir, targetDir).CreateWithID(id, opts)
+	}
+
+	if b.store != nil {
+		return b.storeCreateWithID(id, opts)
 	}
 
 	args := []string{"create", "--json", "--id=" + id}
@@ -1946,6 +2009,10 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 }
 
 func (b *Beads) deleteBead(id string) error {
+	if b.store != nil {
+		return b.storeDelete(id)
+	}
+
 	_, err := b.run("delete", id, "--force")
 	return err
 }
@@ -2026,6 +2093,16 @@ func (b *Beads) ForceCloseWithReason(reason string, ids ...string) error {
 	return err
 }
 
+// reopen reopens a closed issue, recording reason.
+func (b *Beads) reopen(id, reason string) error {
+	if b.store != nil {
+		return b.storeReopen(id, reason)
+	}
+
+	_, err := b.run("reopen", id, "--reason="+reason)
+	return err
+}
+
 // Release moves an in_progress issue back to open status.
 // This is used to recover stuck steps when a worker dies mid-task.
 // It clears the assignee so the step can be claimed by another worker.
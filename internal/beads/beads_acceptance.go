@@ -0,0 +1,99 @@
+package beads
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Acceptance check kinds. Each is written as one "kind: `argument`" line in
+// a bead's acceptance criteria (bd create/update --acceptance), optionally as
+// a list item or checkbox. The backticks are required: they keep prose such
+// as "run: the migration by hand" from being mistaken for a check. Lines that
+// are not checks are left to humans.
+const (
+	// AcceptanceRun is a shell command that must exit zero.
+	//   run: `go test ./internal/billing/...`
+	AcceptanceRun = "run"
+
+	// AcceptanceExists is a path or glob that must match at least one file.
+	//   exists: `docs/billing.md`
+	AcceptanceExists = "exists"
+
+	// AcceptanceGrep is a path or glob and a regexp that must match a line
+	// in at least one of the files.
+	//   grep: `internal/billing/*.go func RoundHalfEven`
+	AcceptanceGrep = "grep"
+
+	// AcceptanceNoGrep is a path or glob and a regexp that must not match
+	// any line in any of the files.
+	//   !grep: `internal/billing/*.go TODO`
+	AcceptanceNoGrep = "!grep"
+)
+
+// AcceptanceCheck is one machine-checkable acceptance criterion.
+type AcceptanceCheck struct {
+	Kind    string // One of the Acceptance* kinds
+	Arg     string // Command for run; path or glob otherwise
+	Pattern string // Regexp for grep and !grep
+	Line    string // The criterion as written, for reports
+	Problem string // Why the line could not be parsed; the check fails if set
+}
+
+// checkboxPrefixRe matches list and checkbox markers before a criterion.
+var checkboxPrefixRe = regexp.MustCompile(`^(?:[-*]\s+)?(?:\[[ xX]\]\s+)?`)
+
+// parseAcceptanceLine parses one acceptance criteria line. ok is false when
+// the line is not a machine check.
+func parseAcceptanceLine(line string) (AcceptanceCheck, bool) {
+	text := strings.TrimSpace(line)
+	text = checkboxPrefixRe.ReplaceAllString(text, "")
+	colonIdx := strings.Index(text, ":")
+	if colonIdx == -1 {
+		return AcceptanceCheck{}, false
+	}
+	kind := strings.TrimSpace(text[:colonIdx])
+	value := strings.TrimSpace(text[colonIdx+1:])
+	if len(value) < 2 || !strings.HasPrefix(value, "`") || !strings.HasSuffix(value, "`") {
+		return AcceptanceCheck{}, false
+	}
+	value = strings.TrimSpace(value[1 : len(value)-1])
+
+	check := AcceptanceCheck{Kind: kind, Line: text}
+	switch kind {
+	case AcceptanceRun, AcceptanceExists:
+		check.Arg = value
+		if value == "" {
+			check.Problem = "missing argument"
+		}
+	case AcceptanceGrep, AcceptanceNoGrep:
+		path, pattern, _ := strings.Cut(value, " ")
+		check.Arg = path
+		check.Pattern = strings.TrimSpace(pattern)
+		switch {
+		case check.Arg == "" || check.Pattern == "":
+			check.Problem = "want \"" + kind + ": `<path> <regexp>`\""
+		default:
+			if _, err := regexp.Compile(check.Pattern); err != nil {
+				check.Problem = "bad regexp: " + err.Error()
+			}
+		}
+	default:
+		return AcceptanceCheck{}, false
+	}
+	return check, true
+}
+
+// ParseAcceptanceChecks extracts the machine-checkable criteria from an
+// issue's acceptance criteria, in the order written.
+func ParseAcceptanceChecks(issue *Issue) []AcceptanceCheck {
+	if issue == nil || issue.AcceptanceCriteria == "" {
+		return nil
+	}
+	var checks []AcceptanceCheck
+	for _, line := range strings.Split(issue.AcceptanceCriteria, "\n") {
+		if check, ok := parseAcceptanceLine(line); ok {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
@@ -0,0 +1,64 @@
+package beads
+
+import "testing"
+
+func TestParseAcceptanceChecks(t *testing.T) {
+	issue := &Issue{AcceptanceCriteria: "Invoices round half-even.\n" +
+		"- [ ] run: `go test ./billing/...`\n" +
+		"- exists: `docs/billing.md`\n" +
+		"grep: `billing/*.go func RoundHalfEven`\n" +
+		"* [x] !grep: `billing/*.go TODO`\n" +
+		"- [ ] run: the migration by hand\n" +
+		"grep: `billing.go`\n" +
+		"grep: `billing.go (unclosed`\n" +
+		"Run: `not a kind`\n"}
+
+	checks := ParseAcceptanceChecks(issue)
+	want := []AcceptanceCheck{
+		{Kind: AcceptanceRun, Arg: "go test ./billing/..."},
+		{Kind: AcceptanceExists, Arg: "docs/billing.md"},
+		{Kind: AcceptanceGrep, Arg: "billing/*.go", Pattern: "func RoundHalfEven"},
+		{Kind: AcceptanceNoGrep, Arg: "billing/*.go", Pattern: "TODO"},
+		{Kind: AcceptanceGrep, Arg: "billing.go"},
+		{Kind: AcceptanceGrep, Arg: "billing.go", Pattern: "(unclosed"},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d: %+v", len(checks), len(want), checks)
+	}
+	for i, w := range want {
+		c := checks[i]
+		if c.Kind != w.Kind || c.Arg != w.Arg || c.Pattern != w.Pattern {
+			t.Errorf("check %d = %+v, want %+v", i, c, w)
+		}
+		if malformed := i >= 4; (c.Problem != "") != malformed {
+			t.Errorf("check %d problem = %q, malformed = %v", i, c.Problem, malformed)
+		}
+	}
+	if checks[0].Line != "run: `go test ./billing/...`" {
+		t.Errorf("line = %q, want list marker stripped", checks[0].Line)
+	}
+
+	if got := ParseAcceptanceChecks(&Issue{}); got != nil {
+		t.Errorf("no criteria = %+v, want nil", got)
+	}
+}
+
+func TestHasUncheckedCriteriaIgnoresMachineChecks(t *testing.T) {
+	issue := &Issue{AcceptanceCriteria: "- [ ] run: `make test`\n- [ ] Docs reviewed by a human\n- [x] Done"}
+	if got := HasUncheckedCriteria(issue); got != 1 {
+		t.Errorf("HasUncheckedCriteria = %d, want 1", got)
+	}
+}
+
+func TestMRFieldsAcceptanceRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "branch: polecat/nux\ntarget: main\nacceptance: failed 1/2"}
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.Acceptance != "failed 1/2" {
+		t.Fatalf("ParseMRFields = %+v", fields)
+	}
+	fields.Acceptance = "passed 2/2"
+	issue.Description = SetMRFields(issue, fields)
+	if got := ParseMRFields(issue); got.Acceptance != "passed 2/2" {
+		t.Errorf("after SetMRFields, acceptance = %q\n%s", got.Acceptance, issue.Description)
+	}
+}
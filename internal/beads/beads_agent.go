@@ -269,11 +269,15 @@ func (b *Beads) CreateAgentBead(id, title string, fields *AgentFields) (*Issue,
 }
 
 func (b *Beads) createAgentBeadViaStore(ctx context.Context, id, title, description string) (*Issue, error) {
-	store, cleanup, err := b.OpenStore(ctx)
-	if err != nil {
-		return nil, err
+	store := b.store
+	if store == nil {
+		opened, cleanup, err := b.OpenStore(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		store = opened
 	}
-	defer cleanup()
 
 	now := time.Now().UTC()
 	actor := b.getActor()
@@ -339,7 +343,7 @@ func (b *Beads) CreateOrReopenAgentBead(id, title string, fields *AgentFields) (
 
 	// If bead is closed, reopen it first
 	if existing.Status == "closed" {
-		if _, reopenErr := target.run("reopen", id, "--reason=re-spawning agent"); reopenErr != nil {
+		if reopenErr := target.reopen(id, "re-spawning agent"); reopenErr != nil {
 			// Reopen failed - try setting status to open via update as fallback
 			// This handles Dolt backends where bd reopen may not work
 			openStatus := "open"
@@ -724,6 +728,10 @@ func (b *Beads) ListAgentBeads() (map[string]*Issue, error) {
 // database this wrapper is rooted at. Use it only when the database is the
 // point — reconciling town against rig copies, or reporting per-database state.
 func (b *Beads) ListAgentBeadsLocal() (map[string]*Issue, error) {
+	if b.store != nil {
+		return b.storeListAgentBeads()
+	}
+
 	// Query issues table first. Issues include labels and type metadata used by
 	// doctor checks (for example, validating gt:agent labels).
 	// Legacy agent beads are type=agent (infrastructure), hidden by bd list's
@@ -757,6 +765,30 @@ func (b *Beads) ListAgentBeadsLocal() (map[string]*Issue, error) {
 	return mergeAgentBeadSources(issuesByID, wispBeads), nil
 }
 
+// storeListAgentBeads implements ListAgentBeadsLocal using the in-process
+// store: durable agent beads from the issues table, with wisp-backed agent
+// beads as the fallback source.
+func (b *Beads) storeListAgentBeads() (map[string]*Issue, error) {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	sdkIssues, err := b.store.SearchIssues(ctx, "", beadsdk.IssueFilter{
+		Labels:        []string{"gt:agent"},
+		ExcludeStatus: defaultListExcludedStatuses(),
+		SkipWisps:     true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store list agent beads: %w", err)
+	}
+	issuesByID := make(map[string]*Issue, len(sdkIssues))
+	for _, issue := range sdkIssuesToIssues(sdkIssues) {
+		issuesByID[issue.ID] = issue
+	}
+
+	wispBeads, _ := b.ListAgentBeadsFromWisps()
+	return mergeAgentBeadSources(issuesByID, wispBeads), nil
+}
+
 // mergeAgentBeadSources merges issue-backed and wisp-backed agent bead maps.
 // Issues are authoritative because they carry full metadata (labels/type),
 // while wisps are treated as a fallback existence source.
@@ -776,21 +808,31 @@ func mergeAgentBeadSources(issuesByID, wispsByID map[string]*Issue) map[string]*
 // ListAgentBeadsFromWisps queries the wisps table for agent beads.
 // Returns nil, nil if the wisps table doesn't exist yet or has no agent beads.
 func (b *Beads) ListAgentBeadsFromWisps() (map[string]*Issue, error) {
-	out, err := b.run("mol", "wisp", "list", "--json")
-	if err != nil {
-		return nil, nil // Wisps table may not exist yet
-	}
+	var wisps []*Issue
+	if b.store != nil {
+		listed, err := b.storeListWisps()
+		if err != nil {
+			return nil, nil // Wisps table may not exist yet
+		}
+		wisps = listed
+	} else {
+		out, err := b.run("mol", "wisp", "list", "--json")
+		if err != nil {
+			return nil, nil // Wisps table may not exist yet
+		}
 
-	// bd mol wisp list --json returns {"wisps": [...], "count": N, ...}
-	var wrapper struct {
-		Wisps []*Issue `json:"wisps"`
-	}
-	if err := json.Unmarshal(out, &wrapper); err != nil {
-		return nil, nil
+		// bd mol wisp list --json returns {"wisps": [...], "count": N, ...}
+		var wrapper struct {
+			Wisps []*Issue `json:"wisps"`
+		}
+		if err := json.Unmarshal(out, &wrapper); err != nil {
+			return nil, nil
+		}
+		wisps = wrapper.Wisps
 	}
 
 	result := make(map[string]*Issue)
-	for _, w := range wrapper.Wisps {
+	for _, w := range wisps {
 		// Check by type/label first (works when fields are present)
 		if IsAgentBead(w) {
 			result[w.ID] = w
@@ -834,6 +876,18 @@ func isAgentBeadByID(id string) bool {
 // This is useful for existence checks where wisp metadata (type, labels)
 // may not be available in the list output.
 func (b *Beads) ListWispIDs() (map[string]bool, error) {
+	if b.store != nil {
+		wisps, err := b.storeListWisps()
+		if err != nil {
+			return nil, nil
+		}
+		result := make(map[string]bool, len(wisps))
+		for _, w := range wisps {
+			result[w.ID] = true
+		}
+		return result, nil
+	}
+
 	out, err := b.run("mol", "wisp", "list", "--json")
 	if err != nil {
 		return nil, nil
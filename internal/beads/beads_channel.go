@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	beadsdk "github.com/steveyegge/beads"
 )
 
 // ChannelFields holds structured fields for channel beads.
@@ -151,6 +154,15 @@ func (b *Beads) CreateChannelBead(name string, subscribers []string, createdBy s
 
 	description := FormatChannelDescription(title, fields)
 
+	if b.store != nil {
+		return b.storeCreateBead(&beadsdk.Issue{
+			ID:          id,
+			Title:       title,
+			Description: description,
+			Labels:      []string{"gt:channel"},
+		})
+	}
+
 	args := []string{"create", "--json",
 		"--id=" + id,
 		"--title=" + title,
@@ -327,16 +339,11 @@ func (b *Beads) DeleteChannelBead(name string) error {
 
 // ListChannelBeads returns all channel beads.
 func (b *Beads) ListChannelBeads() (map[string]*ChannelFields, error) {
-	out, err := b.run("list", "--label=gt:channel", "--json")
+	issues, err := b.listByLabels("", "gt:channel")
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	result := make(map[string]*ChannelFields, len(issues))
 	for _, issue := range issues {
 		fields := ParseChannelFields(issue.Description)
@@ -398,23 +405,9 @@ func (b *Beads) EnforceChannelRetention(name string) error {
 	}
 
 	// Query messages in this channel (oldest first)
-	out, err := b.run("list",
-		"--label=gt:message",
-		"--label=channel:"+name,
-		"--json",
-		"--limit=0",
-		"--sort=created",
-	)
+	messages, err := b.listChannelMessages(name)
 	if err != nil {
-		return fmt.Errorf("listing channel messages: %w", err)
-	}
-
-	var messages []struct {
-		ID        string `json:"id"`
-		CreatedAt string `json:"created_at"`
-	}
-	if err := json.Unmarshal(out, &messages); err != nil {
-		return fmt.Errorf("parsing channel messages: %w", err)
+		return err
 	}
 
 	// Track which messages to delete (use map to avoid duplicates)
@@ -445,7 +438,7 @@ func (b *Beads) EnforceChannelRetention(name string) error {
 	// Delete marked messages (best-effort)
 	for id := range toDeleteIDs {
 		// Use close instead of delete for audit trail
-		_, _ = b.run("close", id, "--reason=channel retention pruning")
+		_ = b.CloseWithReason("channel retention pruning", id)
 	}
 
 	return nil
@@ -469,25 +462,11 @@ func (b *Beads) PruneAllChannels() (int, error) {
 		}
 
 		// Get messages with timestamps
-		out, err := b.run("list",
-			"--label=gt:message",
-			"--label=channel:"+name,
-			"--json",
-			"--limit=0",
-			"--sort=created",
-		)
+		messages, err := b.listChannelMessages(name)
 		if err != nil {
 			continue // Skip on error
 		}
 
-		var messages []struct {
-			ID        string `json:"id"`
-			CreatedAt string `json:"created_at"`
-		}
-		if err := json.Unmarshal(out, &messages); err != nil {
-			continue
-		}
-
 		// Track which messages to delete (use map to avoid duplicates)
 		toDeleteIDs := make(map[string]bool)
 
@@ -518,7 +497,7 @@ func (b *Beads) PruneAllChannels() (int, error) {
 
 		// Delete marked messages
 		for id := range toDeleteIDs {
-			if _, err := b.run("close", id, "--reason=patrol retention pruning"); err == nil {
+			if err := b.CloseWithReason("patrol retention pruning", id); err == nil {
 				pruned++
 			}
 		}
@@ -526,3 +505,44 @@ func (b *Beads) PruneAllChannels() (int, error) {
 
 	return pruned, nil
 }
+
+// channelMessage is the part of a channel message retention needs.
+type channelMessage struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// listChannelMessages returns the open messages posted to a channel, oldest first.
+func (b *Beads) listChannelMessages(name string) ([]channelMessage, error) {
+	if b.store != nil {
+		issues, err := b.storeListLabeled("", "gt:message", "channel:"+name)
+		if err != nil {
+			return nil, fmt.Errorf("listing channel messages: %w", err)
+		}
+		messages := make([]channelMessage, 0, len(issues))
+		for _, issue := range issues {
+			messages = append(messages, channelMessage{ID: issue.ID, CreatedAt: issue.CreatedAt})
+		}
+		sort.SliceStable(messages, func(i, j int) bool {
+			return messages[i].CreatedAt < messages[j].CreatedAt
+		})
+		return messages, nil
+	}
+
+	out, err := b.run("list",
+		"--label=gt:message",
+		"--label=channel:"+name,
+		"--json",
+		"--limit=0",
+		"--sort=created",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing channel messages: %w", err)
+	}
+
+	var messages []channelMessage
+	if err := json.Unmarshal(out, &messages); err != nil {
+		return nil, fmt.Errorf("parsing channel messages: %w", err)
+	}
+	return messages, nil
+}
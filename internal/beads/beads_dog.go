@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	beadsdk "github.com/steveyegge/beads"
 )
 
 // CreateDogAgentBead creates an agent bead for a dog.
@@ -22,6 +24,15 @@ func (b *Beads) CreateDogAgentBead(name, location string) (*Issue, error) {
 
 	description := formatDogDescription(name, location)
 
+	if b.store != nil {
+		return b.storeCreateBead(&beadsdk.Issue{
+			ID:          beadID,
+			Title:       title,
+			Description: description,
+			Labels:      labels,
+		})
+	}
+
 	args := []string{
 		"create", "--json",
 		"--id=" + beadID,
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	beadsdk "github.com/steveyegge/beads"
 )
 
 // EscalationFields holds structured fields for escalation beads.
@@ -175,6 +177,23 @@ func (b *Beads) CreateEscalationBead(title string, fields *EscalationFields) (*I
 
 	description := FormatEscalationDescription(title, fields)
 
+	if b.store != nil {
+		labels := []string{"gt:escalation"}
+		if fields != nil && fields.Severity != "" {
+			labels = append(labels, "severity:"+fields.Severity)
+		}
+		if fields != nil && fields.Fingerprint != "" {
+			labels = append(labels, fields.Fingerprint)
+		}
+		return b.storeCreateBead(&beadsdk.Issue{
+			Title:       title,
+			Description: description,
+			Ephemeral:   true,
+			WispType:    "escalation",
+			Labels:      labels,
+		})
+	}
+
 	// Pass description via stdin (--body-file=-) instead of --description=...
 	// to avoid embedding newlines in a flag value. bd 1.0.3+ rejects newline-
 	// containing flag values, which broke `gt escalate` for any escalation
@@ -276,8 +295,7 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	}
 
 	// Close the issue
-	_, err = target.run("close", id, "--reason="+reason)
-	return err
+	return target.CloseWithReason(reason, id)
 }
 
 // GetEscalationBead retrieves an escalation bead by ID.
@@ -301,16 +319,11 @@ func (b *Beads) GetEscalationBead(id string) (*Issue, *EscalationFields, error)
 
 // ListEscalations returns all open escalation beads.
 func (b *Beads) ListEscalations() ([]*Issue, error) {
-	out, err := b.run("list", "--label=gt:escalation", "--status=open", "--json")
+	issues, err := b.listByLabels("open", "gt:escalation")
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	return filterEscalationRecords(issues), nil
 }
 
@@ -319,41 +332,21 @@ func (b *Beads) ListEscalationsByFingerprint(fingerprintLabel string) ([]*Issue,
 	if fingerprintLabel == "" {
 		return nil, nil
 	}
-	out, err := b.run("list",
-		"--label=gt:escalation",
-		"--label="+fingerprintLabel,
-		"--status=open",
-		"--json",
-	)
+	issues, err := b.listByLabels("open", "gt:escalation", fingerprintLabel)
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	return filterEscalationRecords(issues), nil
 }
 
 // ListEscalationsBySeverity returns open escalation beads filtered by severity.
 func (b *Beads) ListEscalationsBySeverity(severity string) ([]*Issue, error) {
-	out, err := b.run("list",
-		"--label=gt:escalation",
-		"--label=severity:"+severity,
-		"--status=open",
-		"--json",
-	)
+	issues, err := b.listByLabels("open", "gt:escalation", "severity:"+severity)
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	return filterEscalationRecords(issues), nil
 }
 
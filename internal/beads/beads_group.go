@@ -9,6 +9,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	beadsdk "github.com/steveyegge/beads"
 )
 
 // groupNameRegex matches valid group names: lowercase alphanumeric, hyphens, underscores.
@@ -156,6 +158,15 @@ func (b *Beads) CreateGroupBead(name string, fields *GroupFields) (*Issue, error
 
 	description := FormatGroupDescription(title, fields)
 
+	if b.store != nil {
+		return b.storeCreateBead(&beadsdk.Issue{
+			ID:          id,
+			Title:       title,
+			Description: description,
+			Labels:      []string{"gt:group"},
+		})
+	}
+
 	args := []string{"create", "--json",
 		"--id=" + id,
 		"--title=" + title,
@@ -318,16 +329,11 @@ func (b *Beads) DeleteGroupBead(name string) error {
 
 // ListGroupBeads returns all group beads.
 func (b *Beads) ListGroupBeads() (map[string]*GroupFields, error) {
-	out, err := b.run("list", "--label=gt:group", "--json")
+	issues, err := b.listByLabels("", "gt:group")
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	result := make(map[string]*GroupFields, len(issues))
 	for _, issue := range issues {
 		fields := ParseGroupFields(issue.Description)
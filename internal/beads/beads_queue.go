@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	beadsdk "github.com/steveyegge/beads"
 )
 
 // QueueFields holds structured fields for queue beads.
@@ -175,6 +177,16 @@ func (b *Beads) CreateQueueBead(id, title string, fields *QueueFields) (*Issue,
 
 	description := FormatQueueDescription(title, fields)
 
+	if b.store != nil {
+		return b.storeCreateBead(&beadsdk.Issue{
+			ID:          id,
+			Title:       title,
+			Description: description,
+			IssueType:   beadsdk.IssueType("queue"),
+			Labels:      []string{"gt:queue"},
+		})
+	}
+
 	args := []string{"create", "--json",
 		"--id=" + id,
 		"--title=" + title,
@@ -272,16 +284,11 @@ func (b *Beads) UpdateQueueStatus(id, status string) error {
 
 // ListQueueBeads returns all queue beads.
 func (b *Beads) ListQueueBeads() (map[string]*Issue, error) {
-	out, err := b.run("list", "--label=gt:queue", "--json")
+	issues, err := b.listByLabels("", "gt:queue")
 	if err != nil {
 		return nil, err
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	result := make(map[string]*Issue, len(issues))
 	for _, issue := range issues {
 		result[issue.ID] = issue
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	beadsdk "github.com/steveyegge/beads"
 )
 
 // RigState represents the operational state of a rig.
@@ -151,6 +153,16 @@ func (b *Beads) CreateRigBead(name string, fields *RigFields) (*Issue, error) {
 		return nil, fmt.Errorf("ensuring rig bead types: %w", err)
 	}
 
+	if b.store != nil {
+		return b.storeCreateBead(&beadsdk.Issue{
+			ID:          id,
+			Title:       name,
+			Description: description,
+			IssueType:   beadsdk.IssueType("rig"),
+			Labels:      []string{"gt:rig"},
+		})
+	}
+
 	args := []string{"create", "--json",
 		"--id=" + id,
 		"--title=" + name,
@@ -251,19 +263,11 @@ func (b *Beads) DeleteRigBead(name string) error {
 
 // ListRigBeads returns all rig beads.
 func (b *Beads) ListRigBeads() (map[string]*RigFields, error) {
-	out, err := b.run("list", "--label=gt:rig", "--json")
+	issues, err := b.listByLabels("", "gt:rig")
 	if err != nil {
 		return nil, err
 	}
 
-	if !isJSONBytes(out) {
-		return nil, nil
-	}
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
 	result := make(map[string]*RigFields, len(issues))
 	for _, issue := range issues {
 		fields := ParseRigFields(issue.Description)
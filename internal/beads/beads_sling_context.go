@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	beadsdk "github.com/steveyegge/beads"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 )
 
@@ -39,6 +40,22 @@ func (b *Beads) CreateSlingContext(workBeadTitle, workBeadID string, fields *cap
 
 	description := FormatSlingContextDescription(fields)
 
+	if b.store != nil {
+		issue, err := b.storeCreateBead(&beadsdk.Issue{
+			Title:       title,
+			Description: description,
+			Ephemeral:   true,
+			Labels:      []string{capacity.LabelSlingContext},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating sling context: %w", err)
+		}
+		if depErr := b.storeAddDependencyOfType(issue.ID, workBeadID, beadsdk.DependencyType("tracks")); depErr != nil {
+			fmt.Printf("Warning: could not add tracks dep %s → %s: %v\n", issue.ID, workBeadID, depErr)
+		}
+		return issue, nil
+	}
+
 	args := []string{"create", "--json",
 		"--ephemeral",
 		"--title=" + title,
@@ -104,7 +121,7 @@ func (b *Beads) ListOpenSlingContexts() ([]*Issue, error) {
 // CloseSlingContext closes a sling context bead with a reason.
 // Idempotent: suppresses "already closed" errors so retries are safe.
 func (b *Beads) CloseSlingContext(contextID, reason string) error {
-	_, err := b.run("close", contextID, "--reason="+reason)
+	err := b.CloseWithReason(reason, contextID)
 	if err != nil && strings.Contains(err.Error(), "already closed") {
 		return nil // Idempotent — already in desired state
 	}
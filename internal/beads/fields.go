@@ -643,6 +643,10 @@ type MRFields struct {
 	PreVerified     bool   // Polecat ran full gates after rebasing onto target
 	PreVerifiedAt   string // ISO 8601 timestamp when verification completed
 	PreVerifiedBase string // Target branch SHA at verification time
+
+	// Acceptance is the result of the source bead's acceptance checks at
+	// submission (e.g. "passed 3/3"). The full report is a comment on the MR.
+	Acceptance string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -729,6 +733,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "pre_verified_base", "pre-verified-base", "preverifiedbase":
 			fields.PreVerifiedBase = value
 			hasFields = true
+		case "acceptance":
+			fields.Acceptance = value
+			hasFields = true
 		}
 	}
 
@@ -805,6 +812,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.PreVerifiedBase != "" {
 		lines = append(lines, "pre_verified_base: "+fields.PreVerifiedBase)
 	}
+	if fields.Acceptance != "" {
+		lines = append(lines, "acceptance: "+fields.Acceptance)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -863,6 +873,7 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"pre_verified_base": true,
 		"pre-verified-base": true,
 		"preverifiedbase":   true,
+		"acceptance":        true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -4,12 +4,23 @@
 // SetStore), methods bypass the bd subprocess and use the store directly.
 // This eliminates ~600ms per operation and the ~30ms CPU overhead of process
 // spawning. Follows the pattern established by internal/daemon/convoy_manager.go.
+//
+// Long-lived processes that hold a store per database (hq plus one per rig)
+// should use SetStores/NewWithStores instead, so operations that route to
+// another database (cross-rig Show, Create with Rig or Parent, forIssueID
+// writes) stay in-process too. Routed operations whose database has no store
+// fall back to the bd subprocess against that database.
+//
+// Every read and write path has an in-process implementation except Init,
+// which creates the database, Stats, which returns bd's rendered text, and
+// Run, the raw bd passthrough.
 package beads
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -38,6 +49,70 @@ func NewWithStore(workDir string, store beadsdk.Storage) *Beads {
 	return &Beads{workDir: workDir, store: store}
 }
 
+// SetStores configures in-process stores for every database in the town.
+// Keys follow the daemon's store map (see convoy.StoreResolver): "hq" for the
+// town database and rig names for rig databases. If no single store has been
+// set, the store for this wrapper's own database is selected from the map.
+//
+// Callers are responsible for closing the stores when done.
+func (b *Beads) SetStores(stores map[string]beadsdk.Storage) {
+	b.stores = stores
+	if b.store == nil {
+		b.store = stores[b.storeNameForDir(b.getResolvedBeadsDir())]
+	}
+}
+
+// NewWithStores creates a Beads wrapper backed by a set of in-process stores,
+// keyed "hq" and by rig name. See SetStores.
+func NewWithStores(workDir string, stores map[string]beadsdk.Storage) *Beads {
+	b := &Beads{workDir: workDir}
+	b.SetStores(stores)
+	return b
+}
+
+// storeNameForDir maps a beads directory to its key in the stores map:
+// "hq" for the town database, the rig name for a rig database, or "" when
+// the directory is outside the town.
+func (b *Beads) storeNameForDir(beadsDir string) string {
+	townRoot := b.getTownRoot()
+	if townRoot == "" || beadsDir == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(townRoot, beadsDir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	first, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	if first == ".beads" {
+		return "hq"
+	}
+	return first
+}
+
+// storeForDir returns the in-process store for a beads directory, or nil if
+// operations against it must go through the bd subprocess.
+func (b *Beads) storeForDir(beadsDir string) beadsdk.Storage {
+	if beadsDir == b.getResolvedBeadsDir() {
+		return b.store
+	}
+	return b.stores[b.storeNameForDir(beadsDir)]
+}
+
+// withBeadsDir returns a wrapper bound to another beads directory, carrying
+// over isolation and the store set so routed operations stay in-process when
+// a store for the target database is available.
+func (b *Beads) withBeadsDir(workDir, beadsDir string) *Beads {
+	return &Beads{
+		workDir:    workDir,
+		beadsDir:   beadsDir,
+		isolated:   b.isolated,
+		serverPort: b.serverPort,
+		store:      b.storeForDir(beadsDir),
+		stores:     b.stores,
+		townRoot:   b.townRoot,
+	}
+}
+
 // NewWithBeadsDirAndStore creates a Beads wrapper with an explicit BEADS_DIR
 // and an in-process store. Used for cross-database access from polecat worktrees.
 func NewWithBeadsDirAndStore(workDir, beadsDir string, store beadsdk.Storage) *Beads {
@@ -120,6 +195,9 @@ func sdkIssueToIssue(si *beadsdk.Issue) *Issue {
 	if si.ClosedAt != nil {
 		issue.ClosedAt = si.ClosedAt.Format(time.RFC3339)
 	}
+	if si.EstimatedMinutes != nil {
+		issue.EstimatedMinutes = *si.EstimatedMinutes
+	}
 
 	// Populate dependency-derived fields from the SDK issue's Dependencies.
 	// The SDK issue may have Dependencies populated (from show) or not (from list).
@@ -210,6 +288,8 @@ func issueFilterFromListOpts(opts ListOptions) beadsdk.IssueFilter {
 	if opts.Status != "" && opts.Status != "all" {
 		status := beadsdk.Status(opts.Status)
 		f.Status = &status
+	} else if opts.Status == "" {
+		f.ExcludeStatus = defaultListExcludedStatuses()
 	}
 
 	// Prefer Label; fall back to deprecated Type
@@ -245,6 +325,12 @@ func issueFilterFromListOpts(opts ListOptions) beadsdk.IssueFilter {
 	return f
 }
 
+// defaultListExcludedStatuses mirrors bd list's default view, which hides
+// closed and pinned beads unless a status is requested explicitly.
+func defaultListExcludedStatuses() []beadsdk.Status {
+	return []beadsdk.Status{beadsdk.StatusClosed, beadsdk.Status(StatusPinned)}
+}
+
 // workFilterFromListOpts builds a beadsdk WorkFilter from ListOptions.
 func workFilterFromListOpts(opts ListOptions) beadsdk.WorkFilter {
 	f := beadsdk.WorkFilter{
@@ -355,13 +441,26 @@ func (b *Beads) storeHydrateIssueDetails(ctx context.Context, issue *Issue) (*Is
 
 // storeCreate implements Create using the in-process store.
 func (b *Beads) storeCreate(opts CreateOptions) (*Issue, error) {
+	return b.storeCreateWithID("", opts)
+}
+
+// storeCreateWithID implements Create and CreateWithID using the in-process
+// store. An empty id lets the store generate one.
+func (b *Beads) storeCreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	ctx, cancel := storeCtx()
 	defer cancel()
 
+	// Match bd create's defaults when the CLI flag would be omitted.
+	priority := opts.Priority
+	if priority < 0 {
+		priority = 2
+	}
 	sdkIssue := &beadsdk.Issue{
+		ID:          id,
 		Title:       opts.Title,
 		Description: opts.Description,
-		Priority:    opts.Priority,
+		Priority:    priority,
+		IssueType:   beadsdk.TypeTask,
 		Ephemeral:   opts.Ephemeral,
 	}
 
@@ -399,6 +498,103 @@ func (b *Beads) storeCreate(opts CreateOptions) (*Issue, error) {
 	return sdkIssueToIssue(sdkIssue), nil
 }
 
+// storeCreateBead creates a typed bead (agent, channel, queue, rig, ...) with
+// the given fields using the in-process store. Status, priority and type
+// default to what bd create would use; typed beads never use P0, so a zero
+// priority is treated as unset.
+func (b *Beads) storeCreateBead(issue *beadsdk.Issue) (*Issue, error) {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	if issue.Status == "" {
+		issue.Status = beadsdk.StatusOpen
+	}
+	if issue.Priority == 0 {
+		issue.Priority = 2
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = beadsdk.TypeTask
+	}
+	actor := b.getActor()
+	if issue.CreatedBy == "" {
+		issue.CreatedBy = actor
+	}
+	if err := b.store.CreateIssue(ctx, issue, actor); err != nil {
+		return nil, fmt.Errorf("store create: %w", err)
+	}
+	return sdkIssueToIssue(issue), nil
+}
+
+// storeListLabeled lists beads carrying all of labels, matching bd list's
+// default view: wisps are included, and closed and pinned beads are hidden
+// unless status is given.
+func (b *Beads) storeListLabeled(status string, labels ...string) ([]*Issue, error) {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	filter := beadsdk.IssueFilter{Labels: labels}
+	if status != "" && status != "all" {
+		s := beadsdk.Status(status)
+		filter.Status = &s
+	} else if status == "" {
+		filter.ExcludeStatus = defaultListExcludedStatuses()
+	}
+
+	sdkIssues, err := b.store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("store list: %w", err)
+	}
+	return sdkIssuesToIssues(sdkIssues), nil
+}
+
+// storeListWisps implements the "bd mol wisp list" queries using the
+// in-process store. Like the CLI, only non-closed wisps are returned.
+func (b *Beads) storeListWisps() ([]*Issue, error) {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	wisps, err := b.store.ListWisps(ctx, beadsdk.WispFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("store wisp list: %w", err)
+	}
+	return sdkIssuesToIssues(wisps), nil
+}
+
+// storeFindByTitleAndAssignee returns the beads bd list --title --assignee
+// would: non-closed beads whose title contains title and whose assignee
+// matches exactly. Callers apply exact title matching.
+func (b *Beads) storeFindByTitleAndAssignee(title, assignee string) ([]*Issue, error) {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	filter := beadsdk.IssueFilter{
+		TitleContains: title,
+		Assignee:      &assignee,
+		ExcludeStatus: defaultListExcludedStatuses(),
+	}
+	sdkIssues, err := b.store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("store list: %w", err)
+	}
+	return sdkIssuesToIssues(sdkIssues), nil
+}
+
+// storeReopen implements bd reopen using the in-process store.
+func (b *Beads) storeReopen(id, reason string) error {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	return b.store.ReopenIssue(ctx, id, reason, b.getActor())
+}
+
+// storeDelete implements bd delete --force using the in-process store.
+func (b *Beads) storeDelete(id string) error {
+	ctx, cancel := storeCtx()
+	defer cancel()
+
+	return b.store.DeleteIssue(ctx, id)
+}
+
 // storeUpdate implements Update using the in-process store.
 func (b *Beads) storeUpdate(id string, opts UpdateOptions) error {
 	ctx, cancel := storeCtx()
@@ -555,13 +751,18 @@ func (b *Beads) storeSearch(opts SearchOptions) ([]*Issue, error) {
 
 // storeAddDependency implements AddDependency using the in-process store.
 func (b *Beads) storeAddDependency(issue, dependsOn string) error {
+	return b.storeAddDependencyOfType(issue, dependsOn, beadsdk.DepBlocks)
+}
+
+// storeAddDependencyOfType implements bd dep add --type using the in-process store.
+func (b *Beads) storeAddDependencyOfType(issue, dependsOn string, depType beadsdk.DependencyType) error {
 	ctx, cancel := storeCtx()
 	defer cancel()
 
 	dep := &beadsdk.Dependency{
 		IssueID:     issue,
 		DependsOnID: dependsOn,
-		Type:        beadsdk.DepBlocks,
+		Type:        depType,
 	}
 
 	return b.store.AddDependency(ctx, dep, b.getActor())
@@ -0,0 +1,240 @@
+//go:build integration
+
+package beads_test
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/testutil"
+)
+
+// The parity suite runs each Beads method against the bd subprocess and the
+// in-process store on the same database and compares what callers see.
+// Write cases run once per backend with a backend tag in their IDs and
+// titles; projections replace the tag so results compare equal.
+
+var parityCounter atomic.Int32
+
+// newParityBackends initializes a fresh database and returns a subprocess
+// wrapper and a store-backed wrapper for it.
+func newParityBackends(t *testing.T) (cli, inproc *beads.Beads, prefix string) {
+	t.Helper()
+	if _, err := exec.LookPath("bd"); err != nil {
+		t.Skip("bd not installed, skipping store parity test")
+	}
+	testutil.RequireDoltContainer(t)
+	port, err := strconv.Atoi(testutil.DoltContainerPort())
+	if err != nil {
+		t.Fatalf("Dolt container port: %v", err)
+	}
+
+	prefix = fmt.Sprintf("par%d", parityCounter.Add(1))
+	dir := t.TempDir()
+	cli = beads.NewIsolatedWithPort(dir, port)
+	if err := cli.Init(prefix); err != nil {
+		t.Fatalf("bd init: %v", err)
+	}
+
+	store, cleanup, err := cli.OpenStore(context.Background())
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	t.Cleanup(cleanup)
+	inproc = beads.NewIsolatedWithPort(dir, port)
+	inproc.SetStore(store)
+	return cli, inproc, prefix
+}
+
+// issueKeys projects issues to sorted "id|title|status|labels" keys with the
+// backend tag removed.
+func issueKeys(issues []*beads.Issue, tag string) []string {
+	keys := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		if issue == nil {
+			continue
+		}
+		keys = append(keys, issueKey(issue, tag))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func issueKey(issue *beads.Issue, tag string) string {
+	labels := append([]string(nil), issue.Labels...)
+	sort.Strings(labels)
+	key := strings.Join([]string{issue.ID, issue.Title, issue.Status, strings.Join(labels, ",")}, "|")
+	if tag != "" {
+		key = strings.ReplaceAll(key, tag, "TAG")
+	}
+	return key
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type parityCase struct {
+	name string
+	// run exercises the method on b. tag is unique per backend so write
+	// cases do not collide; read cases ignore it.
+	run func(t *testing.T, b *beads.Beads, tag string) interface{}
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreParity(t *testing.T) {
+	cli, inproc, prefix := newParityBackends(t)
+
+	// Shared fixture, written through the subprocess backend.
+	open, err := cli.Create(beads.CreateOptions{Title: "open task", Label: "gt:task", Priority: 2})
+	mustNoErr(t, err)
+	blocker, err := cli.Create(beads.CreateOptions{Title: "blocker", Priority: 1})
+	mustNoErr(t, err)
+	mustNoErr(t, cli.AddDependency(open.ID, blocker.ID))
+	done, err := cli.Create(beads.CreateOptions{Title: "done task", Priority: 2})
+	mustNoErr(t, err)
+	mustNoErr(t, cli.CloseWithReason("finished", done.ID))
+	_, err = cli.Create(beads.CreateOptions{Title: "wisp MR", Label: "gt:merge-request", Ephemeral: true, Priority: 2})
+	mustNoErr(t, err)
+	_, err = cli.CreateChannelBead("alerts", []string{"mayor/"}, "mayor")
+	mustNoErr(t, err)
+	_, err = cli.CreateEscalationBead("Dolt down", &beads.EscalationFields{Severity: "high"})
+	mustNoErr(t, err)
+	assigned, err := cli.Create(beads.CreateOptions{Title: "patrol", Priority: 2})
+	mustNoErr(t, err)
+	assignee := "gastown/witness"
+	mustNoErr(t, cli.Update(assigned.ID, beads.UpdateOptions{Assignee: &assignee}))
+
+	cases := []parityCase{
+		{"List default", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.List(beads.ListOptions{Priority: -1})
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"List all", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"List label", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.List(beads.ListOptions{Label: "gt:task", Priority: -1})
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"Show", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issue, err := b.Show(open.ID)
+			mustNoErr(t, err)
+			return []interface{}{issueKey(issue, ""), issue.DependsOn}
+		}},
+		{"ShowMultiple", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.ShowMultiple([]string{open.ID, done.ID, prefix + "-missing"})
+			mustNoErr(t, err)
+			return mapKeys(issues)
+		}},
+		{"Search", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.Search(beads.SearchOptions{Query: "task", Status: "open"})
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"Ready", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.Ready()
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"Blocked", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.Blocked()
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"ListMergeRequests", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.ListMergeRequests(beads.ListOptions{Label: "gt:merge-request", Priority: -1})
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"ListChannelBeads", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			channels, err := b.ListChannelBeads()
+			mustNoErr(t, err)
+			return mapKeys(channels)
+		}},
+		{"ListEscalationsBySeverity", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issues, err := b.ListEscalationsBySeverity("high")
+			mustNoErr(t, err)
+			return issueKeys(issues, "")
+		}},
+		{"FindLatestIssueByTitleAndAssignee", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			issue, err := b.FindLatestIssueByTitleAndAssignee("patrol", assignee)
+			mustNoErr(t, err)
+			return issue.ID
+		}},
+		{"ListWispIDs", func(t *testing.T, b *beads.Beads, _ string) interface{} {
+			ids, err := b.ListWispIDs()
+			mustNoErr(t, err)
+			return mapKeys(ids)
+		}},
+		{"CreateWithID and Update", func(t *testing.T, b *beads.Beads, tag string) interface{} {
+			id := prefix + "-fixed-" + tag
+			_, err := b.CreateWithID(id, beads.CreateOptions{Title: "fixed " + tag, Label: "gt:role", Priority: 1})
+			mustNoErr(t, err)
+			title := "renamed " + tag
+			mustNoErr(t, b.Update(id, beads.UpdateOptions{Title: &title, AddLabels: []string{"extra"}}))
+			issue, err := b.Show(id)
+			mustNoErr(t, err)
+			return []interface{}{issueKey(issue, tag), issue.Priority}
+		}},
+		{"CloseWithReason", func(t *testing.T, b *beads.Beads, tag string) interface{} {
+			issue, err := b.Create(beads.CreateOptions{Title: "closable " + tag, Priority: 2})
+			mustNoErr(t, err)
+			mustNoErr(t, b.CloseWithReason("done", issue.ID))
+			closed, err := b.Show(issue.ID)
+			mustNoErr(t, err)
+			return closed.Status
+		}},
+		{"Channel create and delete", func(t *testing.T, b *beads.Beads, tag string) interface{} {
+			_, err := b.CreateChannelBead("ch"+tag, nil, "mayor")
+			mustNoErr(t, err)
+			_, fields, err := b.GetChannelBead("ch" + tag)
+			mustNoErr(t, err)
+			mustNoErr(t, b.DeleteChannelBead("ch"+tag))
+			gone, _, err := b.GetChannelBead("ch" + tag)
+			mustNoErr(t, err)
+			return []interface{}{strings.ReplaceAll(fields.Name, tag, "TAG"), fields.Status, gone == nil}
+		}},
+		{"Sling context", func(t *testing.T, b *beads.Beads, tag string) interface{} {
+			ctxBead, err := b.CreateSlingContext("work "+tag, open.ID, nil)
+			mustNoErr(t, err)
+			found, _, err := b.FindOpenSlingContext(open.ID)
+			mustNoErr(t, err)
+			mustNoErr(t, b.CloseSlingContext(ctxBead.ID, "dispatched"))
+			return found != nil
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.run(t, cli, "cli")
+			got := tc.run(t, inproc, "store")
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("store result differs from bd subprocess:\n store: %#v\n    bd: %#v", got, want)
+			}
+		})
+	}
+}
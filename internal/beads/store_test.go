@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,8 +50,12 @@ func (m *mockStorage) CreateIssue(_ context.Context, issue *beadsdk.Issue, _ str
 	if m.createErr != nil {
 		return m.createErr
 	}
-	m.nextID++
-	issue.ID = fmt.Sprintf("%s-%d", m.prefix, m.nextID)
+	if issue.ID == "" {
+		m.nextID++
+		issue.ID = fmt.Sprintf("%s-%d", m.prefix, m.nextID)
+	} else if _, exists := m.issues[issue.ID]; exists {
+		return fmt.Errorf("UNIQUE constraint failed: issue %s already exists", issue.ID)
+	}
 	issue.Status = beadsdk.StatusOpen
 	issue.CreatedAt = time.Now()
 	issue.UpdatedAt = time.Now()
@@ -159,6 +167,18 @@ func (m *mockStorage) SearchIssues(_ context.Context, query string, filter beads
 		if filter.Status != nil && issue.Status != *filter.Status {
 			continue
 		}
+		if hasStatus(filter.ExcludeStatus, issue.Status) {
+			continue
+		}
+		if filter.Ephemeral != nil && issue.Ephemeral != *filter.Ephemeral {
+			continue
+		}
+		if filter.SkipWisps && issue.Ephemeral {
+			continue
+		}
+		if filter.TitleContains != "" && !strings.Contains(issue.Title, filter.TitleContains) {
+			continue
+		}
 		if filter.Assignee != nil && issue.Assignee != *filter.Assignee {
 			continue
 		}
@@ -291,6 +311,40 @@ func (m *mockStorage) GetReadyWork(_ context.Context, filter beadsdk.WorkFilter)
 	return result, nil
 }
 
+func (m *mockStorage) ListWisps(_ context.Context, filter beadsdk.WispFilter) ([]*beadsdk.Issue, error) {
+	if m.searchErr != nil {
+		return nil, m.searchErr
+	}
+	var result []*beadsdk.Issue
+	for _, issue := range m.issues {
+		if !issue.Ephemeral || (issue.Status == beadsdk.StatusClosed && !filter.IncludeClosed) {
+			continue
+		}
+		result = append(result, issue)
+	}
+	return result, nil
+}
+
+func (m *mockStorage) ReopenIssue(_ context.Context, id, _, _ string) error {
+	issue, ok := m.issues[id]
+	if !ok {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	issue.Status = beadsdk.StatusOpen
+	issue.ClosedAt = nil
+	delete(m.closed, id)
+	return nil
+}
+
+func hasStatus(statuses []beadsdk.Status, status beadsdk.Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *mockStorage) Close() error { return nil }
 
 // --- Tests ---
@@ -918,3 +972,335 @@ func containsSubstr(s, substr string) bool {
 	}
 	return false
 }
+
+// --- Multi-store routing ---
+
+// newStoreTown creates a town with an hq database and a gastown rig database
+// routed by the gt- prefix.
+func newStoreTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(townRoot, "mayor"),
+		filepath.Join(townRoot, ".beads"),
+		filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	routes := `{"prefix":"hq-","path":"."}` + "\n" + `{"prefix":"gt-","path":"gastown/mayor/rig"}` + "\n"
+	if err := os.WriteFile(filepath.Join(townRoot, ".beads", RoutesFileName), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestSetStoresRoutesToRigStore(t *testing.T) {
+	townRoot := newStoreTown(t)
+	hq := newMockStorage()
+	hq.prefix = "hq"
+	rig := newMockStorage()
+	rig.prefix = "gt"
+	hq.CreateIssue(context.Background(), &beadsdk.Issue{Title: "town work"}, "test")
+	rig.CreateIssue(context.Background(), &beadsdk.Issue{Title: "rig work"}, "test")
+
+	b := NewWithStores(townRoot, map[string]beadsdk.Storage{"hq": hq, "gastown": rig})
+	if b.Store() != hq {
+		t.Fatal("town wrapper should select the hq store")
+	}
+
+	issue, err := b.Show("gt-1")
+	if err != nil {
+		t.Fatalf("Show(gt-1): %v", err)
+	}
+	if issue.Title != "rig work" {
+		t.Errorf("Show(gt-1) title = %q, want rig work", issue.Title)
+	}
+
+	issues, err := b.ShowMultiple([]string{"hq-1", "gt-1"})
+	if err != nil {
+		t.Fatalf("ShowMultiple: %v", err)
+	}
+	if len(issues) != 2 || issues["hq-1"].Title != "town work" || issues["gt-1"].Title != "rig work" {
+		t.Errorf("ShowMultiple = %+v", issues)
+	}
+
+	if got := b.forIssueID("gt-1").Store(); got != rig {
+		t.Error("forIssueID(gt-1) should use the rig store")
+	}
+}
+
+func TestForIssueIDWithoutTargetStoreFallsBackToSubprocess(t *testing.T) {
+	townRoot := newStoreTown(t)
+	hq := newMockStorage()
+
+	// A single store belongs to the wrapper's own database only; a routed
+	// wrapper must not reuse it against another database.
+	b := NewWithStore(townRoot, hq)
+	if got := b.forIssueID("gt-1").Store(); got != nil {
+		t.Error("routed wrapper reused the hq store for the rig database")
+	}
+	if got := b.ForAgentBead().Store(); got != hq {
+		t.Error("ForAgentBead should keep the town store")
+	}
+}
+
+func TestStoreNameForDir(t *testing.T) {
+	townRoot := newStoreTown(t)
+	b := New(townRoot)
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(townRoot, ".beads"), "hq"},
+		{filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads"), "gastown"},
+		{filepath.Join(townRoot, "gastown", ".beads"), "gastown"},
+		{t.TempDir(), ""},
+	}
+	for _, tt := range tests {
+		if got := b.storeNameForDir(tt.dir); got != tt.want {
+			t.Errorf("storeNameForDir(%s) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+// --- Store coverage for typed beads and wisps ---
+
+func TestStoreCreateWithID(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	issue, err := b.CreateWithID("test-role-mayor", CreateOptions{Title: "Mayor", Label: "gt:role", Priority: -1})
+	if err != nil {
+		t.Fatalf("CreateWithID: %v", err)
+	}
+	if issue.ID != "test-role-mayor" {
+		t.Errorf("ID = %q, want test-role-mayor", issue.ID)
+	}
+	stored := store.issues["test-role-mayor"]
+	if stored == nil || stored.Priority != 2 || stored.IssueType != beadsdk.TypeTask {
+		t.Errorf("stored issue = %+v, want bd create defaults", stored)
+	}
+
+	if _, err := b.CreateWithID("test-role-mayor", CreateOptions{Title: "Mayor"}); err == nil {
+		t.Error("duplicate CreateWithID succeeded")
+	}
+}
+
+func TestStoreListDefaultHidesClosed(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "open"}, "test")
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "done"}, "test")
+	store.CloseIssue(context.Background(), "test-2", "", "", "")
+
+	issues, err := b.List(ListOptions{Priority: -1})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "test-1" {
+		t.Errorf("default List = %d issues, want only the open one", len(issues))
+	}
+
+	all, err := b.List(ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		t.Fatalf("List(all): %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("List(all) = %d issues, want 2", len(all))
+	}
+}
+
+func TestStoreChannelBeads(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	if _, err := b.CreateChannelBead("alerts", []string{"mayor/"}, "mayor"); err != nil {
+		t.Fatalf("CreateChannelBead: %v", err)
+	}
+	channels, err := b.ListChannelBeads()
+	if err != nil {
+		t.Fatalf("ListChannelBeads: %v", err)
+	}
+	if fields, ok := channels["alerts"]; !ok || len(fields.Subscribers) != 1 {
+		t.Errorf("ListChannelBeads = %+v", channels)
+	}
+
+	if err := b.DeleteChannelBead("alerts"); err != nil {
+		t.Fatalf("DeleteChannelBead: %v", err)
+	}
+	if _, ok := store.issues[ChannelBeadID("alerts")]; ok {
+		t.Error("channel bead still present after delete")
+	}
+}
+
+func TestStoreQueueAndRigTypes(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	if _, err := b.CreateQueueBead("test-q-merge", "Merge queue", &QueueFields{}); err != nil {
+		t.Fatalf("CreateQueueBead: %v", err)
+	}
+	if got := store.issues["test-q-merge"].IssueType; got != "queue" {
+		t.Errorf("queue bead type = %q, want queue", got)
+	}
+	queues, err := b.ListQueueBeads()
+	if err != nil {
+		t.Fatalf("ListQueueBeads: %v", err)
+	}
+	if _, ok := queues["test-q-merge"]; !ok {
+		t.Errorf("ListQueueBeads = %v", queues)
+	}
+}
+
+func TestStoreEscalations(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	issue, err := b.CreateEscalationBead("Dolt down", &EscalationFields{Severity: "high"})
+	if err != nil {
+		t.Fatalf("CreateEscalationBead: %v", err)
+	}
+	stored := store.issues[issue.ID]
+	if !stored.Ephemeral || stored.WispType != "escalation" {
+		t.Errorf("escalation stored as %+v, want an escalation wisp", stored)
+	}
+	if _, err := b.CreateEscalationBead("Disk full", &EscalationFields{Severity: "low"}); err != nil {
+		t.Fatalf("CreateEscalationBead: %v", err)
+	}
+
+	high, err := b.ListEscalationsBySeverity("high")
+	if err != nil {
+		t.Fatalf("ListEscalationsBySeverity: %v", err)
+	}
+	if len(high) != 1 || high[0].ID != issue.ID {
+		t.Errorf("high escalations = %d, want 1", len(high))
+	}
+
+	if err := b.CloseEscalation(issue.ID, "mayor", "fixed"); err != nil {
+		t.Fatalf("CloseEscalation: %v", err)
+	}
+	open, err := b.ListEscalations()
+	if err != nil {
+		t.Fatalf("ListEscalations: %v", err)
+	}
+	if len(open) != 1 {
+		t.Errorf("open escalations = %d, want 1 after closing one", len(open))
+	}
+}
+
+func TestStoreListMergeRequestsIncludesWisps(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "durable MR", Labels: []string{"gt:merge-request"}}, "test")
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "wisp MR", Ephemeral: true, Labels: []string{"gt:merge-request"}}, "test")
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "other wisp", Ephemeral: true, Labels: []string{"gt:patrol"}}, "test")
+
+	mrs, err := b.ListMergeRequests(ListOptions{Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		t.Fatalf("ListMergeRequests: %v", err)
+	}
+	var titles []string
+	for _, mr := range mrs {
+		titles = append(titles, mr.Title)
+		if mr.Title == "wisp MR" && !mr.Ephemeral {
+			t.Error("wisp MR not marked ephemeral")
+		}
+	}
+	sort.Strings(titles)
+	if strings.Join(titles, ",") != "durable MR,wisp MR" {
+		t.Errorf("ListMergeRequests titles = %v", titles)
+	}
+}
+
+func TestStoreListAgentBeadsMergesWisps(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	if _, err := b.CreateDogAgentBead("rex", "town"); err != nil {
+		t.Fatalf("CreateDogAgentBead: %v", err)
+	}
+	store.CreateIssue(context.Background(), &beadsdk.Issue{ID: "gt-gastown-witness", Title: "witness", Ephemeral: true}, "test")
+
+	agents, err := b.ListAgentBeadsLocal()
+	if err != nil {
+		t.Fatalf("ListAgentBeadsLocal: %v", err)
+	}
+	if _, ok := agents[DogBeadIDTown("rex")]; !ok {
+		t.Error("durable dog agent bead missing")
+	}
+	if _, ok := agents["gt-gastown-witness"]; !ok {
+		t.Error("wisp agent bead missing")
+	}
+
+	ids, err := b.ListWispIDs()
+	if err != nil {
+		t.Fatalf("ListWispIDs: %v", err)
+	}
+	if len(ids) != 1 || !ids["gt-gastown-witness"] {
+		t.Errorf("ListWispIDs = %v", ids)
+	}
+}
+
+func TestStoreSlingContextTracksWorkBead(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	work, _ := b.Create(CreateOptions{Title: "work", Priority: -1})
+	ctxBead, err := b.CreateSlingContext("work", work.ID, nil)
+	if err != nil {
+		t.Fatalf("CreateSlingContext: %v", err)
+	}
+	stored := store.issues[ctxBead.ID]
+	if !stored.Ephemeral || len(stored.Dependencies) != 1 || stored.Dependencies[0].Type != "tracks" {
+		t.Errorf("sling context = %+v, want a wisp tracking %s", stored, work.ID)
+	}
+	if err := b.CloseSlingContext(ctxBead.ID, "dispatched"); err != nil {
+		t.Fatalf("CloseSlingContext: %v", err)
+	}
+	if !store.closed[ctxBead.ID] {
+		t.Error("sling context not closed")
+	}
+}
+
+func TestStoreReopen(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "agent"}, "test")
+	store.CloseIssue(context.Background(), "test-1", "", "", "")
+	if err := b.reopen("test-1", "re-spawning agent"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if store.issues["test-1"].Status != beadsdk.StatusOpen {
+		t.Errorf("status = %q after reopen", store.issues["test-1"].Status)
+	}
+}
+
+func TestStoreFindLatestIssueByTitleAndAssignee(t *testing.T) {
+	store := newMockStorage()
+	b := newTestBeads(store)
+
+	older := &beadsdk.Issue{Title: "patrol", Assignee: "gastown/witness"}
+	store.CreateIssue(context.Background(), older, "test")
+	older.CreatedAt = time.Now().Add(-time.Hour)
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "patrol", Assignee: "gastown/witness"}, "test")
+	store.CreateIssue(context.Background(), &beadsdk.Issue{Title: "patrol extra", Assignee: "gastown/witness"}, "test")
+
+	issue, err := b.FindLatestIssueByTitleAndAssignee("patrol", "gastown/witness")
+	if err != nil {
+		t.Fatalf("FindLatestIssueByTitleAndAssignee: %v", err)
+	}
+	if issue.ID != "test-2" {
+		t.Errorf("latest = %s, want test-2", issue.ID)
+	}
+	if _, err := b.FindLatestIssueByTitleAndAssignee("patrol", "gastown/refinery"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown assignee error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,344 @@
+// Package cgroup places agent sessions in cgroup v2 groups and reads their
+// resource usage back.
+//
+// Each session gets a leaf group under <root>/<rig>/, where root defaults to
+// gastown.slice at the top of the unified hierarchy:
+//
+//	/sys/fs/cgroup/gastown.slice/
+//	    gastown/polecat-Toast    cpu.weight, memory.max, pids.max
+//	    gastown/witness
+//	    hq/mayor
+//
+// Interior groups only delegate controllers; processes live in the leaves,
+// as cgroup v2 requires. The root needs to be writable by the user running
+// the agents: run as root, or delegate it once (mkdir and chown).
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultRoot is where session groups are created unless configured
+// otherwise.
+const DefaultRoot = "/sys/fs/cgroup/gastown.slice"
+
+// controllers are enabled for every level below the root.
+var controllers = []string{"cpu", "memory", "pids"}
+
+// ErrUnavailable is returned when cgroup v2 is not mounted at the root's
+// parent or the root cannot be created.
+var ErrUnavailable = errors.New("cgroup v2 unavailable")
+
+// Limits are the per-session resource limits. Zero fields leave the kernel
+// default (no limit, weight 100).
+type Limits struct {
+	CPUWeight int   // 1-10000, relative share under contention
+	MemoryMax int64 // bytes
+	PidsMax   int
+}
+
+// Usage is a session group's resource accounting.
+type Usage struct {
+	Rig     string `json:"rig"`
+	Session string `json:"session"`
+
+	CPUUsec       uint64 `json:"cpu_usec"`
+	MemoryCurrent int64  `json:"memory_current"`
+	MemoryPeak    int64  `json:"memory_peak,omitempty"`
+	MemoryMax     int64  `json:"memory_max,omitempty"` // 0 = unlimited
+	PidsCurrent   int    `json:"pids_current"`
+	Populated     bool   `json:"populated"`
+}
+
+// SessionName maps a GT_ROLE value to the rig and leaf group names of its
+// session: "gastown/polecats/Toast" is gastown/polecat-Toast, "gastown/crew/max"
+// is gastown/crew-max, "gastown/witness" is gastown/witness, and town-level
+// roles such as "mayor" live under hq.
+func SessionName(gtRole string) (rig, session string) {
+	parts := strings.Split(strings.Trim(gtRole, "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "polecats":
+		return parts[0], "polecat-" + parts[2]
+	case len(parts) == 3:
+		return parts[0], parts[1] + "-" + parts[2]
+	case len(parts) == 2:
+		return parts[0], parts[1]
+	case len(parts) == 1 && parts[0] != "":
+		return "hq", parts[0]
+	}
+	return "", ""
+}
+
+// SessionPath returns the group directory for a session.
+func SessionPath(root, rig, session string) string {
+	return filepath.Join(root, rig, session)
+}
+
+// PolecatPath returns the group directory for a polecat's session.
+func PolecatPath(root, rig, name string) string {
+	return SessionPath(root, rig, "polecat-"+name)
+}
+
+// Available reports whether session groups can be created under root:
+// the unified hierarchy must be mounted at its parent, and root must exist
+// or be creatable.
+func Available(root string) error {
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "cgroup.controllers")); err != nil {
+		return fmt.Errorf("%w: %s is not a cgroup v2 hierarchy", ErrUnavailable, filepath.Dir(root))
+	}
+	if err := ensureGroup(root); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// Create makes the session's group under root, enables the controllers on
+// the way down, and applies limits. It returns the group path.
+func Create(root, rig, session string, limits Limits) (string, error) {
+	if rig == "" || session == "" || strings.ContainsAny(rig+session, `/\`) || rig == ".." || session == ".." {
+		return "", fmt.Errorf("invalid cgroup name %q/%q", rig, session)
+	}
+	if err := Available(root); err != nil {
+		return "", err
+	}
+	rigPath := filepath.Join(root, rig)
+	leaf := filepath.Join(rigPath, session)
+	for _, dir := range []string{root, rigPath} {
+		if err := ensureGroup(dir); err != nil {
+			return "", err
+		}
+		if err := enableControllers(dir); err != nil {
+			return "", err
+		}
+	}
+	if err := ensureGroup(leaf); err != nil {
+		return "", err
+	}
+	if err := applyLimits(leaf, limits); err != nil {
+		return "", err
+	}
+	return leaf, nil
+}
+
+// Join moves the process, with all its threads, into the group.
+func Join(path string, pid int) error {
+	if err := os.WriteFile(filepath.Join(path, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644); err != nil { //nolint:gosec // G306: cgroupfs ignores mode
+		return fmt.Errorf("joining cgroup %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadUsage reads a group's accounting files. Files belonging to
+// controllers that are not enabled read as zero.
+func ReadUsage(path string) (*Usage, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	u := &Usage{Rig: filepath.Base(filepath.Dir(path)), Session: filepath.Base(path)}
+	if stat, err := readKeyed(filepath.Join(path, "cpu.stat")); err == nil {
+		u.CPUUsec = uint64(stat["usage_usec"]) //nolint:gosec // G115: counters are non-negative
+	}
+	u.MemoryCurrent = readInt(filepath.Join(path, "memory.current"))
+	u.MemoryPeak = readInt(filepath.Join(path, "memory.peak"))
+	u.MemoryMax = readInt(filepath.Join(path, "memory.max"))
+	u.PidsCurrent = int(readInt(filepath.Join(path, "pids.current")))
+	if events, err := readKeyed(filepath.Join(path, "cgroup.events")); err == nil {
+		u.Populated = events["populated"] == 1
+	}
+	return u, nil
+}
+
+// List reads usage for every session group under root, sorted by rig and
+// session. A missing root yields no sessions.
+func List(root string) ([]*Usage, error) {
+	rigs, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Usage
+	for _, rig := range rigs {
+		if !rig.IsDir() {
+			continue
+		}
+		sessions, err := os.ReadDir(filepath.Join(root, rig.Name()))
+		if err != nil {
+			continue
+		}
+		for _, s := range sessions {
+			if !s.IsDir() {
+				continue
+			}
+			if u, err := ReadUsage(filepath.Join(root, rig.Name(), s.Name())); err == nil {
+				out = append(out, u)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Rig != out[j].Rig {
+			return out[i].Rig < out[j].Rig
+		}
+		return out[i].Session < out[j].Session
+	})
+	return out, nil
+}
+
+// TotalCPUUsec returns the CPU time consumed by all sessions under root.
+func TotalCPUUsec(root string) (uint64, error) {
+	stat, err := readKeyed(filepath.Join(root, "cpu.stat"))
+	if err != nil {
+		return 0, err
+	}
+	return uint64(stat["usage_usec"]), nil //nolint:gosec // G115: counters are non-negative
+}
+
+// Prune removes session groups whose processes have all exited. Groups
+// still populated, or that the kernel refuses to remove, are kept.
+func Prune(root string) int {
+	usages, err := List(root)
+	if err != nil {
+		return 0
+	}
+	removed := 0
+	for _, u := range usages {
+		if u.Populated {
+			continue
+		}
+		if err := os.Remove(SessionPath(root, u.Rig, u.Session)); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// ParseMemory parses a memory size such as "512M", "4G" or "1073741824".
+// Suffixes are binary (K = 1024).
+func ParseMemory(size string) (int64, error) {
+	s := strings.TrimSpace(strings.ToUpper(size))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+	mult := int64(1)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K':
+			mult = 1 << 10
+		case 'M':
+			mult = 1 << 20
+		case 'G':
+			mult = 1 << 30
+		case 'T':
+			mult = 1 << 40
+		}
+		if mult > 1 {
+			s = s[:n-1]
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid memory size %q", size)
+	}
+	return int64(v * float64(mult)), nil
+}
+
+// FormatBytes renders a byte count as a short human-readable size.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func ensureGroup(dir string) error {
+	if err := os.Mkdir(dir, 0755); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("creating cgroup %s: %w", dir, err)
+	}
+	return nil
+}
+
+// enableControllers delegates cpu, memory and pids to dir's children,
+// skipping controllers the parent does not offer.
+func enableControllers(dir string) error {
+	available := map[string]bool{}
+	if data, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers")); err == nil { //nolint:gosec // G304: path is constructed internally
+		for _, c := range strings.Fields(string(data)) {
+			available[c] = true
+		}
+	}
+	var enable []string
+	for _, c := range controllers {
+		if available[c] {
+			enable = append(enable, "+"+c)
+		}
+	}
+	if len(enable) == 0 {
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0644); err != nil { //nolint:gosec // G306: cgroupfs ignores mode
+		return fmt.Errorf("enabling controllers in %s: %w", dir, err)
+	}
+	return nil
+}
+
+func applyLimits(dir string, limits Limits) error {
+	writes := map[string]string{}
+	if limits.CPUWeight > 0 {
+		writes["cpu.weight"] = strconv.Itoa(limits.CPUWeight)
+	}
+	if limits.MemoryMax > 0 {
+		writes["memory.max"] = strconv.FormatInt(limits.MemoryMax, 10)
+	}
+	if limits.PidsMax > 0 {
+		writes["pids.max"] = strconv.Itoa(limits.PidsMax)
+	}
+	for file, value := range writes {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil { //nolint:gosec // G306: cgroupfs ignores mode
+			return fmt.Errorf("setting %s on %s: %w", file, dir, err)
+		}
+	}
+	return nil
+}
+
+// readInt reads a single-value cgroup file. "max" and unreadable files
+// read as 0.
+func readInt(path string) int64 {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return v
+}
+
+// readKeyed reads a flat-keyed cgroup file such as cpu.stat.
+func readKeyed(path string) (map[string]int64, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	out := make(map[string]int64)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 {
+			if v, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				out[fields[0]] = v
+			}
+		}
+	}
+	return out, sc.Err()
+}
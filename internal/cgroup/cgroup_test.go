@@ -0,0 +1,226 @@
+package cgroup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeHierarchy returns a session root inside a temp directory that looks
+// like the top of a cgroup v2 hierarchy. Plain files stand in for cgroupfs,
+// so writes are recorded but have no effect.
+func fakeHierarchy(t *testing.T) string {
+	t.Helper()
+	top := t.TempDir()
+	writeFile(t, filepath.Join(top, "cgroup.controllers"), "cpuset cpu io memory pids")
+	return filepath.Join(top, "gastown.slice")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSessionName(t *testing.T) {
+	tests := []struct {
+		role, rig, session string
+	}{
+		{"gastown/polecats/Toast", "gastown", "polecat-Toast"},
+		{"gastown/crew/max", "gastown", "crew-max"},
+		{"gastown/witness", "gastown", "witness"},
+		{"gastown/refinery", "gastown", "refinery"},
+		{"mayor", "hq", "mayor"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		rig, session := SessionName(tt.role)
+		if rig != tt.rig || session != tt.session {
+			t.Errorf("SessionName(%q) = %q, %q; want %q, %q", tt.role, rig, session, tt.rig, tt.session)
+		}
+	}
+}
+
+func TestCreate_WritesLimitsAndDelegatesControllers(t *testing.T) {
+	root := fakeHierarchy(t)
+	// A real kernel populates cgroup.controllers in new groups.
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "cgroup.controllers"), "cpu memory pids")
+
+	path, err := Create(root, "gastown", "polecat-Toast", Limits{CPUWeight: 50, MemoryMax: 4 << 30, PidsMax: 512})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if want := PolecatPath(root, "gastown", "Toast"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if got := readFile(t, filepath.Join(root, "cgroup.subtree_control")); got != "+cpu +memory +pids" {
+		t.Errorf("root subtree_control = %q", got)
+	}
+	for file, want := range map[string]string{"cpu.weight": "50", "memory.max": "4294967296", "pids.max": "512"} {
+		if got := readFile(t, filepath.Join(path, file)); got != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestCreate_ZeroLimitsLeaveKernelDefaults(t *testing.T) {
+	root := fakeHierarchy(t)
+	path, err := Create(root, "gastown", "witness", Limits{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, file := range []string{"cpu.weight", "memory.max", "pids.max"} {
+		if _, err := os.Stat(filepath.Join(path, file)); err == nil {
+			t.Errorf("%s written for zero limits", file)
+		}
+	}
+}
+
+func TestCreate_RejectsPathNames(t *testing.T) {
+	root := fakeHierarchy(t)
+	for _, name := range [][2]string{{"gastown", "../x"}, {"..", "witness"}, {"", "witness"}} {
+		if _, err := Create(root, name[0], name[1], Limits{}); err == nil {
+			t.Errorf("Create(%q, %q) succeeded", name[0], name[1])
+		}
+	}
+}
+
+func TestAvailable_RequiresUnifiedHierarchy(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "gastown.slice")
+	if err := Available(root); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Available on a plain directory = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	root := fakeHierarchy(t)
+	path, err := Create(root, "gastown", "polecat-Toast", Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Join(path, 4242); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if got := readFile(t, filepath.Join(path, "cgroup.procs")); got != "4242" {
+		t.Errorf("cgroup.procs = %q", got)
+	}
+}
+
+func TestListAndPrune(t *testing.T) {
+	root := fakeHierarchy(t)
+	live, _ := Create(root, "gastown", "polecat-Toast", Limits{})
+	dead, _ := Create(root, "gastown", "polecat-Nux", Limits{})
+	writeFile(t, filepath.Join(live, "cpu.stat"), "usage_usec 2500000\nuser_usec 2000000\nsystem_usec 500000\n")
+	writeFile(t, filepath.Join(live, "memory.current"), "1048576\n")
+	writeFile(t, filepath.Join(live, "memory.max"), "max\n")
+	writeFile(t, filepath.Join(live, "pids.current"), "7\n")
+	writeFile(t, filepath.Join(live, "cgroup.events"), "populated 1\nfrozen 0\n")
+	writeFile(t, filepath.Join(dead, "cgroup.events"), "populated 0\nfrozen 0\n")
+
+	usages, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 2 || usages[0].Session != "polecat-Nux" || usages[1].Session != "polecat-Toast" {
+		t.Fatalf("List = %+v", usages)
+	}
+	u := usages[1]
+	if u.Rig != "gastown" || u.CPUUsec != 2500000 || u.MemoryCurrent != 1048576 || u.MemoryMax != 0 || u.PidsCurrent != 7 || !u.Populated {
+		t.Errorf("usage = %+v", u)
+	}
+
+	// Fake groups contain files, so os.Remove fails the way it would for a
+	// group the kernel still holds; clear the dead one to let Prune take it.
+	if err := os.Remove(filepath.Join(dead, "cgroup.events")); err != nil {
+		t.Fatal(err)
+	}
+	if n := Prune(root); n != 1 {
+		t.Errorf("Prune removed %d groups, want 1", n)
+	}
+	if _, err := os.Stat(live); err != nil {
+		t.Errorf("populated group removed: %v", err)
+	}
+}
+
+func TestList_MissingRoot(t *testing.T) {
+	usages, err := List(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || usages != nil {
+		t.Errorf("List(missing) = %v, %v; want nil, nil", usages, err)
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"1073741824", 1 << 30},
+		{"512M", 512 << 20},
+		{"4G", 4 << 30},
+		{"4GiB", 4 << 30},
+		{"1.5g", 3 << 29},
+		{"64k", 64 << 10},
+	}
+	for _, tt := range tests {
+		got, err := ParseMemory(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMemory(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "lots", "-1G", "0"} {
+		if _, err := ParseMemory(bad); err == nil {
+			t.Errorf("ParseMemory(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{512: "512B", 1536: "1.5KiB", 4 << 30: "4.0GiB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// TestCreate_LiveHierarchy exercises a real cgroup v2 mount when the test
+// runs as root on a unified-hierarchy host.
+func TestCreate_LiveHierarchy(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root")
+	}
+	root := filepath.Join("/sys/fs/cgroup", "gastown-test.slice")
+	if err := Available(root); err != nil {
+		t.Skipf("cgroup v2 unavailable: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Remove(filepath.Join(root, "test", "session"))
+		_ = os.Remove(filepath.Join(root, "test"))
+		_ = os.Remove(root)
+	})
+	path, err := Create(root, "test", "session", Limits{PidsMax: 64})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := strings.TrimSpace(readFile(t, filepath.Join(path, "pids.max"))); got != "64" {
+		t.Errorf("pids.max = %q", got)
+	}
+	u, err := ReadUsage(path)
+	if err != nil || u.Populated {
+		t.Errorf("ReadUsage = %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,483 @@
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Session recordings: where a checkpoint says where a polecat stopped, a
+// recording says how it got there. Each tool call the agent makes is
+// appended, in order, with the git tree of the worktree after the call, so
+// the change any one call made is the diff between two trees.
+//
+// Trees are written with a throwaway index (the worktree's real index and
+// files are never touched) and include untracked, non-ignored files up to
+// maxSnapshotUntracked. The objects live in the rig's shared object store,
+// so a recording can be replayed after the polecat's worktree is gone,
+// until git gc prunes them.
+//
+// The hook runs after every tool call, so each session keeps a small state
+// file with its recording's path and last step; recording a call never
+// rescans the directory or rereads the recording.
+
+const (
+	// RecordingsDirName is the rig-level directory holding recordings, one
+	// subdirectory per polecat. It lives under .runtime so recordings
+	// survive the polecat being nuked.
+	RecordingsDirName = "recordings"
+
+	// KeepRecordings is how many recordings are kept per polecat.
+	KeepRecordings = 20
+
+	// maxRecordedText caps the tool input and output kept per step. The
+	// diff carries what a call changed; the text is for orientation.
+	maxRecordedText = 4096
+
+	// maxSnapshotUntracked caps the size of an untracked file a snapshot
+	// picks up. Larger ones (build outputs, binaries) are left out so they
+	// are not hashed into the shared object store on every call.
+	maxSnapshotUntracked = 1 << 20
+)
+
+// mutatingTools are the tools that can change the worktree. Other tools
+// reuse the previous step's tree instead of snapshotting.
+var mutatingTools = map[string]bool{
+	"Bash":         true,
+	"Edit":         true,
+	"MultiEdit":    true,
+	"Write":        true,
+	"NotebookEdit": true,
+}
+
+// Step is one recorded tool call.
+type Step struct {
+	Seq  int       `json:"seq"`
+	Time time.Time `json:"time"`
+	Tool string    `json:"tool"`
+	// Input and Output are the call's input and result, truncated.
+	Input  string `json:"input,omitempty"`
+	Output string `json:"output,omitempty"`
+	// Head is HEAD after the call; Parent and Tree are the worktree's tree
+	// before and after it.
+	Head   string `json:"head,omitempty"`
+	Parent string `json:"parent"`
+	Tree   string `json:"tree"`
+	// Session is the Gas Town session name; NativeSession the agent's own.
+	Session       string `json:"session,omitempty"`
+	NativeSession string `json:"native_session,omitempty"`
+}
+
+// Changed reports whether the call changed the worktree.
+func (s Step) Changed() bool { return s.Parent != s.Tree }
+
+// ToolCall is a tool call as reported by the agent's PostToolUse hook.
+type ToolCall struct {
+	Tool          string
+	Input         string
+	Output        string
+	Session       string
+	NativeSession string
+}
+
+// Recording is one agent session's recorded tool calls.
+type Recording struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Started time.Time `json:"started"`
+	Steps   []Step    `json:"steps,omitempty"`
+}
+
+// Session returns the Gas Town session the recording was made in.
+func (r *Recording) Session() string {
+	for _, s := range r.Steps {
+		if s.Session != "" {
+			return s.Session
+		}
+	}
+	return ""
+}
+
+// RecordingsDir returns the directory holding a polecat's recordings.
+func RecordingsDir(rigPath, polecatName string) string {
+	return filepath.Join(rigPath, ".runtime", RecordingsDirName, polecatName)
+}
+
+// Record appends a tool call to the recording for its native session in
+// dir, snapshotting workDir when the tool can have changed it. Concurrent
+// calls (parallel tool use) are serialized.
+func Record(dir, workDir string, call ToolCall) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating recordings dir: %w", err)
+	}
+	native := call.NativeSession
+	if native == "" {
+		native = call.Session
+	}
+	if native == "" {
+		native = fmt.Sprintf("pid-%d", os.Getppid())
+	}
+
+	fl := flock.New(filepath.Join(dir, ".lock"))
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking recordings: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	statePath := filepath.Join(dir, sessionStateName(sanitizeID(native)))
+	path, last, err := loadSessionState(statePath)
+	if err != nil {
+		// No state yet, or it predates this session's recording being
+		// pruned: fall back to finding the recording on disk.
+		if path, last, err = recordingFor(dir, native); err != nil {
+			return err
+		}
+	}
+	created := last == nil && path == ""
+	if created {
+		path = filepath.Join(dir, fmt.Sprintf("%d-%s.jsonl", time.Now().Unix(), sanitizeID(native)))
+	}
+
+	step := Step{
+		Time:          time.Now(),
+		Tool:          call.Tool,
+		Input:         truncateText(call.Input),
+		Output:        truncateText(call.Output),
+		Session:       call.Session,
+		NativeSession: call.NativeSession,
+	}
+	if last != nil {
+		step.Seq = last.Seq + 1
+		step.Parent = last.Tree
+	} else {
+		// First call of the session: diff against HEAD. Changes already in
+		// the worktree when the session started are attributed to it.
+		step.Parent, _ = gitOutput(workDir, "rev-parse", "HEAD^{tree}")
+	}
+
+	if last == nil || mutatingTools[call.Tool] {
+		step.Head, step.Tree, err = Snapshot(workDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		step.Head, step.Tree = last.Head, last.Tree
+	}
+
+	data, err := json.Marshal(step)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G304: path is under the rig's recordings dir
+	if err != nil {
+		return fmt.Errorf("opening recording: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing recording: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteJSON(statePath, sessionState{Path: path, Last: step}); err != nil {
+		return fmt.Errorf("writing recording state: %w", err)
+	}
+	if created {
+		pruneRecordings(dir, KeepRecordings)
+	}
+	return nil
+}
+
+// Snapshot writes the worktree's current state as a git tree without
+// touching its index or files. Returns HEAD and the tree hash.
+func Snapshot(workDir string) (head, tree string, err error) {
+	head, _ = gitOutput(workDir, "rev-parse", "HEAD")
+
+	tmp, err := os.CreateTemp("", "gt-snapshot-index-*")
+	if err != nil {
+		return "", "", err
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	// Start from the real index so git add only rehashes changed files.
+	if indexPath, err := gitOutput(workDir, "rev-parse", "--git-path", "index"); err == nil {
+		if !filepath.IsAbs(indexPath) {
+			indexPath = filepath.Join(workDir, indexPath)
+		}
+		if data, err := os.ReadFile(indexPath); err == nil { //nolint:gosec // G304: git-reported index path
+			_ = os.WriteFile(tmpPath, data, 0600)
+		}
+	}
+	env := []string{"GIT_INDEX_FILE=" + tmpPath}
+	if _, err := gitEnvOutput(workDir, env, "add", "-u"); err != nil {
+		return "", "", fmt.Errorf("staging snapshot: %w", err)
+	}
+	untracked, err := snapshotUntracked(workDir)
+	if err != nil {
+		return "", "", err
+	}
+	if len(untracked) > 0 {
+		stdin := []byte(strings.Join(untracked, "\x00"))
+		if _, err := gitEnvInput(workDir, env, stdin, "add", "--pathspec-from-file=-", "--pathspec-file-nul"); err != nil {
+			return "", "", fmt.Errorf("staging untracked files: %w", err)
+		}
+	}
+	if tree, err = gitEnvOutput(workDir, env, "write-tree"); err != nil {
+		return "", "", fmt.Errorf("writing snapshot tree: %w", err)
+	}
+	return head, tree, nil
+}
+
+// snapshotUntracked returns the untracked, non-ignored files in workDir
+// small enough to snapshot.
+func snapshotUntracked(workDir string) ([]string, error) {
+	out, err := gitOutput(workDir, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("listing untracked files: %w", err)
+	}
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p == "" {
+			continue
+		}
+		info, err := os.Lstat(filepath.Join(workDir, p))
+		if err != nil || info.IsDir() || info.Size() > maxSnapshotUntracked {
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// ListRecordings returns the recordings in dir, newest first, without
+// their steps.
+func ListRecordings(dir string) ([]*Recording, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recs []*Recording
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		recs = append(recs, recordingFromName(filepath.Join(dir, e.Name())))
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Path > recs[j].Path })
+	return recs, nil
+}
+
+// LoadRecording reads a recording and its steps.
+func LoadRecording(path string) (*Recording, error) {
+	rec := recordingFromName(path)
+	f, err := os.Open(path) //nolint:gosec // G304: caller-selected recording path
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var s Step
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			continue // a torn final line from a killed hook
+		}
+		rec.Steps = append(rec.Steps, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading recording: %w", err)
+	}
+	return rec, nil
+}
+
+// StepDiff returns the diff a step made, read from the repository at
+// gitDir (any checkout sharing the polecat's object store). With stat, a
+// diffstat instead of a patch.
+func StepDiff(gitDir string, s Step, stat bool) (string, error) {
+	if !s.Changed() {
+		return "", nil
+	}
+	args := []string{"diff", "--no-color"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	if s.Parent == "" {
+		// No parent tree (unborn branch): diff against the empty tree.
+		args = append(args, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", s.Tree)
+	} else {
+		args = append(args, s.Parent, s.Tree)
+	}
+	out, err := gitOutput(gitDir, args...)
+	if err != nil {
+		return "", fmt.Errorf("diffing step %d (objects may have been pruned): %w", s.Seq, err)
+	}
+	return out, nil
+}
+
+// sessionState is the per-session state file: the recording a native
+// session appends to and the last step written to it.
+type sessionState struct {
+	Path string `json:"path"`
+	Last Step   `json:"last"`
+}
+
+// sessionStateName returns the state file name for a sanitized session ID.
+// The leading dot and .json suffix keep it out of ListRecordings.
+func sessionStateName(id string) string {
+	return "." + id + ".json"
+}
+
+// loadSessionState reads a session's state file. It errors when the file
+// is missing or unreadable or its recording is gone.
+func loadSessionState(statePath string) (string, *Step, error) {
+	data, err := os.ReadFile(statePath) //nolint:gosec // G304: path is under the rig's recordings dir
+	if err != nil {
+		return "", nil, err
+	}
+	var st sessionState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(st.Path); err != nil {
+		return "", nil, err
+	}
+	return st.Path, &st.Last, nil
+}
+
+// recordingFor finds the recording for a native session in dir, returning
+// its path and last step.
+func recordingFor(dir, native string) (string, *Step, error) {
+	recs, err := ListRecordings(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	suffix := "-" + sanitizeID(native) + ".jsonl"
+	for _, r := range recs {
+		if !strings.HasSuffix(r.Path, suffix) {
+			continue
+		}
+		last, err := lastStep(r.Path)
+		return r.Path, last, err
+	}
+	return "", nil, nil
+}
+
+// lastStep returns the final complete step in a recording, or nil. It
+// reads the file from the end, widening the window until a step parses.
+func lastStep(path string) (*Step, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is under the rig's recordings dir
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	for window := int64(64 * 1024); ; window *= 2 {
+		off := max(size-window, 0)
+		buf := make([]byte, size-off)
+		if _, err := f.ReadAt(buf, off); err != nil && err != io.EOF {
+			return nil, err
+		}
+		lines := bytes.Split(bytes.TrimSpace(buf), []byte("\n"))
+		first := 0
+		if off > 0 {
+			first = 1 // may start mid-line
+		}
+		for i := len(lines) - 1; i >= first; i-- {
+			var s Step
+			if err := json.Unmarshal(lines[i], &s); err == nil {
+				return &s, nil
+			}
+		}
+		if off == 0 {
+			return nil, nil
+		}
+	}
+}
+
+// recordingFromName parses <unix-start>-<id>.jsonl.
+func recordingFromName(path string) *Recording {
+	name := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	rec := &Recording{ID: name, Path: path}
+	if ts, id, ok := strings.Cut(name, "-"); ok {
+		var sec int64
+		if _, err := fmt.Sscanf(ts, "%d", &sec); err == nil {
+			rec.ID = id
+			rec.Started = time.Unix(sec, 0)
+		}
+	}
+	return rec
+}
+
+// pruneRecordings removes all but the newest keep recordings in dir.
+func pruneRecordings(dir string, keep int) {
+	recs, err := ListRecordings(dir)
+	if err != nil {
+		return
+	}
+	for i := keep; i < len(recs); i++ {
+		_ = os.Remove(recs[i].Path)
+		_ = os.Remove(filepath.Join(dir, sessionStateName(recs[i].ID)))
+	}
+}
+
+func sanitizeID(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+func truncateText(s string) string {
+	if len(s) <= maxRecordedText {
+		return s
+	}
+	return s[:maxRecordedText] + "\n… (truncated)"
+}
+
+// gitEnvOutput is gitOutput with extra environment variables.
+func gitEnvOutput(workDir string, env []string, args ...string) (string, error) {
+	return gitEnvInput(workDir, env, nil, args...)
+}
+
+// gitEnvInput is gitEnvOutput with stdin.
+func gitEnvInput(workDir string, env []string, stdin []byte, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	util.SetDetachedProcessGroup(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,184 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndDiff(t *testing.T) {
+	repo := initTestRepo(t)
+	dir := filepath.Join(t.TempDir(), "recordings", "nux")
+	record := func(tool string) {
+		t.Helper()
+		if err := Record(dir, repo, ToolCall{Tool: tool, Input: tool + " input", Session: "gt-rig-nux", NativeSession: "abc"}); err != nil {
+			t.Fatalf("Record(%s): %v", tool, err)
+		}
+	}
+
+	record("Read")
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	record("Write")
+	record("Grep")
+
+	recs, err := ListRecordings(dir)
+	if err != nil || len(recs) != 1 {
+		t.Fatalf("ListRecordings = %v, %v; want one recording", recs, err)
+	}
+	rec, err := LoadRecording(recs[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.ID != "abc" || rec.Session() != "gt-rig-nux" || len(rec.Steps) != 3 {
+		t.Fatalf("recording = %+v, want id abc with 3 steps", rec)
+	}
+
+	read, write, grep := rec.Steps[0], rec.Steps[1], rec.Steps[2]
+	if read.Changed() {
+		t.Error("Read on a clean worktree should not change it")
+	}
+	if !write.Changed() || write.Parent != read.Tree {
+		t.Errorf("Write step = %+v, want a change from the Read tree", write)
+	}
+	if grep.Changed() || grep.Tree != write.Tree {
+		t.Error("Grep should reuse the previous tree")
+	}
+
+	diff, err := StepDiff(repo, write, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "new.txt") || !strings.Contains(diff, "+hello") {
+		t.Errorf("diff = %q, want new.txt added", diff)
+	}
+
+	// The snapshot must not stage anything in the real index.
+	out, _ := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if !strings.Contains(string(out), "?? new.txt") {
+		t.Errorf("status = %q, want new.txt still untracked", out)
+	}
+}
+
+func TestRecordSeparateSessionsAndPrune(t *testing.T) {
+	repo := initTestRepo(t)
+	dir := t.TempDir()
+	for i := 0; i < KeepRecordings+2; i++ {
+		// Names sort by start time first; fake distinct starts.
+		path := filepath.Join(dir, fmt.Sprintf("%d-old%02d.jsonl", 1000+i, i))
+		if err := os.WriteFile(path, []byte(`{"seq":0,"tool":"Read"}`+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Record(dir, repo, ToolCall{Tool: "Read", NativeSession: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := ListRecordings(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != KeepRecordings {
+		t.Fatalf("kept %d recordings, want %d", len(recs), KeepRecordings)
+	}
+	if recs[0].ID != "new" {
+		t.Errorf("newest = %s, want new", recs[0].ID)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1000-old00.jsonl")); !os.IsNotExist(err) {
+		t.Error("oldest recording should have been pruned")
+	}
+}
+
+func TestLoadRecordingSkipsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1700000000-s1.jsonl")
+	data := `{"seq":0,"tool":"Read","tree":"a","parent":"a"}` + "\n" + `{"seq":1,"tool":"Ed`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Steps) != 1 || rec.ID != "s1" || rec.Started.Unix() != 1700000000 {
+		t.Errorf("recording = %+v, want one step from s1", rec)
+	}
+}
+
+func TestRecordResumesFromSessionState(t *testing.T) {
+	repo := initTestRepo(t)
+	dir := t.TempDir()
+	for _, tool := range []string{"Read", "Grep"} {
+		if err := Record(dir, repo, ToolCall{Tool: tool, NativeSession: "abc"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	statePath := filepath.Join(dir, sessionStateName("abc"))
+	path, last, err := loadSessionState(statePath)
+	if err != nil {
+		t.Fatalf("loadSessionState: %v", err)
+	}
+	if last.Seq != 1 || last.Tool != "Grep" {
+		t.Errorf("state last = %+v, want seq 1 Grep", last)
+	}
+
+	// Without the state file, the recording itself is tail-read.
+	if err := os.Remove(statePath); err != nil {
+		t.Fatal(err)
+	}
+	if err := Record(dir, repo, ToolCall{Tool: "Glob", NativeSession: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Steps) != 3 || rec.Steps[2].Seq != 2 {
+		t.Fatalf("steps = %+v, want seq 2 appended to the same recording", rec.Steps)
+	}
+}
+
+func TestLastStepReadsTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1700000000-s1.jsonl")
+	// A long first step pushes the last one past the first read window.
+	big := strings.Repeat("x", 100*1024)
+	data := `{"seq":0,"tool":"Read","output":"` + big + `"}` + "\n" + `{"seq":1,"tool":"Grep"}` + "\n" + `{"seq":2,"tool":"Ed`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	last, err := lastStep(path)
+	if err != nil || last == nil || last.Seq != 1 {
+		t.Fatalf("lastStep = %+v, %v; want seq 1", last, err)
+	}
+
+	// Only the oversized step: the window widens until it parses.
+	if err := os.WriteFile(path, []byte(`{"seq":0,"tool":"Read","output":"`+big+`"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := lastStep(path); err != nil || last == nil || last.Seq != 0 {
+		t.Fatalf("lastStep = %+v, %v; want seq 0", last, err)
+	}
+}
+
+func TestSnapshotSkipsLargeUntracked(t *testing.T) {
+	repo := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, "small.txt"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "big.bin"), make([]byte, maxSnapshotUntracked+1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, tree, err := Snapshot(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("git", "-C", repo, "ls-tree", "--name-only", tree).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "small.txt") || strings.Contains(string(out), "big.bin") {
+		t.Errorf("tree = %q, want small.txt without big.bin", out)
+	}
+}
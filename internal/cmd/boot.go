@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/warrant"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -296,7 +295,7 @@ func runDegradedTriage(b *boot.Boot) (action, target string, err error) {
 	// before the normal triage decision — warrant execution is mechanical and
 	// does not affect which action runDegradedTriage returns to the caller.
 	if townRoot != "" {
-		executeWarrants(filepath.Join(townRoot, "warrants"), tm)
+		executeWarrants(warrant.Dir(townRoot), tm)
 	}
 
 	// Check if Deacon session exists — the only mechanical check degraded
@@ -331,40 +330,22 @@ func runDegradedTriage(b *boot.Boot) (action, target string, err error) {
 // executeWarrants scans the warrants directory and executes any pending warrants.
 // It is called as a side effect during degraded triage, before the normal
 // Deacon health decision is made. Errors are non-fatal: a failed execution is
-// logged and skipped rather than aborting triage.
+// logged and skipped rather than aborting triage. Vetoed warrants and policy
+// warrants still inside their grace period are left alone.
 func executeWarrants(warrantDir string, tm *tmux.Tmux) {
-	entries, err := os.ReadDir(warrantDir)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			fmt.Printf("Warning: reading warrants dir: %v\n", err)
-		}
-		return
+	entries, errs := warrant.List(warrantDir)
+	for _, err := range errs {
+		fmt.Printf("Warning: %v\n", err)
 	}
 
-	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".warrant.json") {
-			continue
-		}
-
-		path := filepath.Join(warrantDir, entry.Name())
-		data, err := os.ReadFile(path)
-		if err != nil {
-			fmt.Printf("Warning: reading warrant file %s: %v\n", entry.Name(), err)
-			continue
-		}
-
-		var w Warrant
-		if err := json.Unmarshal(data, &w); err != nil {
-			fmt.Printf("Warning: parsing warrant file %s: %v\n", entry.Name(), err)
-			continue
-		}
-
-		if w.Executed {
+	now := time.Now()
+	for _, e := range entries {
+		if !e.Warrant.Due(now) {
 			continue
 		}
 
-		if err := executeOneWarrant(&w, path, tm); err != nil {
-			fmt.Printf("Warning: executing warrant for %s: %v\n", w.Target, err)
+		if err := executeOneWarrant(e.Warrant, e.Path, tm); err != nil {
+			fmt.Printf("Warning: executing warrant for %s: %v\n", e.Warrant.Target, err)
 			continue
 		}
 	}
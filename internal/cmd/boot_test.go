@@ -122,6 +122,43 @@ func TestExecuteWarrants_SkipsAlreadyExecuted(t *testing.T) {
 	}
 }
 
+// TestExecuteWarrants_RespectsGraceAndVeto verifies that policy warrants
+// inside their grace period and vetoed warrants are not executed.
+func TestExecuteWarrants_RespectsGraceAndVeto(t *testing.T) {
+	setupWarrantTestRegistry(t)
+
+	warrantDir := t.TempDir()
+
+	later := time.Now().Add(10 * time.Minute)
+	grace := Warrant{
+		ID:           "warrant-in-grace",
+		Target:       "gastown/polecats/grace-x7q",
+		Reason:       "policy silent: no pane output for 50m",
+		FiledBy:      "gastown/witness",
+		FiledAt:      time.Now(),
+		Policy:       "silent",
+		ExecuteAfter: &later,
+	}
+	vetoed := Warrant{
+		ID:      "warrant-vetoed",
+		Target:  "gastown/polecats/vetoed-x7q",
+		Reason:  "policy silent: no pane output for 50m",
+		FiledBy: "gastown/witness",
+		FiledAt: time.Now().Add(-time.Hour),
+		Vetoed:  true,
+	}
+	writeTestWarrant(t, warrantDir, grace)
+	writeTestWarrant(t, warrantDir, vetoed)
+
+	executeWarrants(warrantDir, tmux.NewTmux())
+
+	for _, target := range []string{grace.Target, vetoed.Target} {
+		if result := readTestWarrant(t, warrantDir, target); result.Executed {
+			t.Errorf("%s executed, want skipped", target)
+		}
+	}
+}
+
 // TestExecuteWarrants_MissingDir verifies that executeWarrants handles a
 // missing warrants directory gracefully (no panic, no error).
 func TestExecuteWarrants_MissingDir(t *testing.T) {
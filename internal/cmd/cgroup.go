@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cgroup"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	cgroupRoot      string
+	cgroupRig       string
+	cgroupSession   string
+	cgroupCPUWeight int
+	cgroupMemoryMax string
+	cgroupPidsMax   int
+	cgroupJSON      bool
+)
+
+var cgroupCmd = &cobra.Command{
+	Use:     "cgroup",
+	GroupID: GroupServices,
+	Short:   "Per-session cgroup v2 resource limits",
+	RunE:    requireSubcommand,
+	Long: `Place agent sessions in their own cgroup v2 groups and report usage.
+
+Configure limits in town or rig settings/config.json:
+
+  "resources": {
+    "memory_max": "4G",
+    "roles": {"polecat": {"cpu_weight": 50, "pids_max": 2048}}
+  }
+
+Sessions then start under 'gt cgroup exec', one group per session below
+/sys/fs/cgroup/gastown.slice/<rig>/. The daemon reads usage back for
+metrics and spawn pressure decisions.
+
+The cgroup root must be writable by the user running agents: run as root,
+or create it once and chown it to that user.`,
+}
+
+var cgroupExecCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command in a session cgroup",
+	Long: `Create the session's cgroup, move into it, and exec the command.
+
+When cgroup v2 is unavailable (cgroup v1 hosts, macOS, unwritable root) the
+command runs unconfined after a warning: resource limits never block a
+session from starting.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runCgroupExec,
+}
+
+var cgroupStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show resource usage of session cgroups",
+	Args:  cobra.NoArgs,
+	RunE:  runCgroupStatus,
+}
+
+func init() {
+	cgroupExecCmd.Flags().StringVar(&cgroupRoot, "root", cgroup.DefaultRoot, "Cgroup directory sessions live under")
+	cgroupExecCmd.Flags().StringVar(&cgroupRig, "rig", "", "Rig name (required)")
+	cgroupExecCmd.Flags().StringVar(&cgroupSession, "session", "", "Session group name (required)")
+	cgroupExecCmd.Flags().IntVar(&cgroupCPUWeight, "cpu-weight", 0, "CPU weight, 1-10000")
+	cgroupExecCmd.Flags().StringVar(&cgroupMemoryMax, "memory-max", "", "Memory limit, e.g. 4G")
+	cgroupExecCmd.Flags().IntVar(&cgroupPidsMax, "pids-max", 0, "Process and thread limit")
+	_ = cgroupExecCmd.MarkFlagRequired("rig")
+	_ = cgroupExecCmd.MarkFlagRequired("session")
+	// Everything from the command onward belongs to the command.
+	cgroupExecCmd.Flags().SetInterspersed(false)
+
+	cgroupStatusCmd.Flags().BoolVar(&cgroupJSON, "json", false, "Output as JSON")
+
+	cgroupCmd.AddCommand(cgroupExecCmd, cgroupStatusCmd)
+	rootCmd.AddCommand(cgroupCmd)
+}
+
+func runCgroupExec(cmd *cobra.Command, args []string) error {
+	limits := cgroup.Limits{CPUWeight: cgroupCPUWeight, PidsMax: cgroupPidsMax}
+	if cgroupMemoryMax != "" {
+		mem, err := cgroup.ParseMemory(cgroupMemoryMax)
+		if err != nil {
+			return err
+		}
+		limits.MemoryMax = mem
+	}
+
+	path, err := cgroup.Create(cgroupRoot, cgroupRig, cgroupSession, limits)
+	if err == nil {
+		err = cgroup.Join(path, os.Getpid())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: running without resource limits: %v\n", err)
+	}
+	return execCommand(args)
+}
+
+func runCgroupStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	root := config.ResolveCgroupRoot(townRoot)
+	usages, err := cgroup.List(root)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", root, err)
+	}
+
+	if cgroupJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usages)
+	}
+
+	if len(usages) == 0 {
+		fmt.Printf("No session cgroups under %s\n", root)
+		return nil
+	}
+	fmt.Printf("%-28s %10s %18s %6s\n", style.Bold.Render("SESSION"), "CPU", "MEMORY", "PIDS")
+	for _, u := range usages {
+		name := u.Rig + "/" + u.Session
+		if !u.Populated {
+			name += style.Dim.Render(" (exited)")
+		}
+		fmt.Printf("%-28s %10s %18s %6d\n", name, formatCgroupCPU(u.CPUUsec), formatCgroupMemory(u), u.PidsCurrent)
+	}
+	return nil
+}
+
+// formatCgroupCPU renders cgroup CPU time as a rounded duration.
+func formatCgroupCPU(usec uint64) string {
+	return (time.Duration(usec) * time.Microsecond).Round(time.Second).String() //nolint:gosec // G115: CPU time fits in int64
+}
+
+// formatCgroupMemory renders current memory and, when set, the limit.
+func formatCgroupMemory(u *cgroup.Usage) string {
+	if u.MemoryMax > 0 {
+		return cgroup.FormatBytes(u.MemoryCurrent) + " / " + cgroup.FormatBytes(u.MemoryMax)
+	}
+	return cgroup.FormatBytes(u.MemoryCurrent)
+}
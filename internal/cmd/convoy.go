@@ -23,6 +23,8 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	convoyops "github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/forecast"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -257,7 +259,17 @@ var convoyStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a convoy.
 
 Displays convoy metadata, tracked issues, and completion progress.
-Without an ID, shows status of all active convoys.`,
+Without an ID, shows status of all active convoys.
+
+With --forecast, also runs a Monte Carlo simulation over the convoy's
+dependency graph using historical sling-to-merge durations (bucketed by
+bead type and estimate) and shows the P50/P90 completion time, the
+critical path, and the beads that would shorten it most if prioritized.
+
+Examples:
+  gt convoy status hq-cv-abc
+  gt convoy status hq-cv-abc --forecast
+  gt convoy status 1 --forecast --json`,
 	Args:         cobra.MaximumNArgs(1),
 	SilenceUsage: true,
 	RunE:         runConvoyStatus,
@@ -396,6 +408,7 @@ func init() {
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
+	convoyStatusCmd.Flags().BoolVar(&convoyStatusForecast, "forecast", false, "Forecast P50/P90 completion and the critical path from sling-to-merge history")
 
 	// List flags
 	convoyListCmd.Flags().BoolVar(&convoyListJSON, "json", false, "Output as JSON")
@@ -432,6 +445,7 @@ func init() {
 	convoyCmd.AddCommand(convoyCloseCmd)
 	convoyCmd.AddCommand(convoyLandCmd)
 	convoyCmd.AddCommand(convoyStageCmd)
+	convoyCmd.AddCommand(convoyGraphCmd)
 	convoyCmd.AddCommand(convoyLaunchCmd)
 
 	rootCmd.AddCommand(convoyCmd)
@@ -1932,6 +1946,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 
 	// If no ID provided, show all active convoys
 	if len(args) == 0 {
+		if convoyStatusForecast {
+			return fmt.Errorf("--forecast requires a convoy ID")
+		}
 		return showAllConvoyStatus(townBeads)
 	}
 
@@ -1989,21 +2006,30 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	var fc *forecast.Result
+	if convoyStatusForecast {
+		fc, err = forecastConvoy(convoyID, time.Now())
+		if err != nil {
+			return fmt.Errorf("forecasting %s: %w", convoyID, err)
+		}
+	}
+
 	if convoyStatusJSON {
 		lifecycle := "system-managed"
 		if isOwned {
 			lifecycle = "caller-managed"
 		}
 		type jsonStatus struct {
-			ID            string             `json:"id"`
-			Title         string             `json:"title"`
-			Status        string             `json:"status"`
-			Owned         bool               `json:"owned"`
-			Lifecycle     string             `json:"lifecycle"`
-			MergeStrategy string             `json:"merge_strategy,omitempty"`
-			Tracked       []trackedIssueInfo `json:"tracked"`
-			Completed     int                `json:"completed"`
-			Total         int                `json:"total"`
+			ID            string              `json:"id"`
+			Title         string              `json:"title"`
+			Status        string              `json:"status"`
+			Owned         bool                `json:"owned"`
+			Lifecycle     string              `json:"lifecycle"`
+			MergeStrategy string              `json:"merge_strategy,omitempty"`
+			Tracked       []trackedIssueInfo  `json:"tracked"`
+			Completed     int                 `json:"completed"`
+			Total         int                 `json:"total"`
+			Forecast      *convoyForecastJSON `json:"forecast,omitempty"`
 		}
 		out := jsonStatus{
 			ID:            convoy.ID,
@@ -2016,6 +2042,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Completed:     completed,
 			Total:         len(tracked),
 		}
+		if fc != nil {
+			out.Forecast = buildConvoyForecastJSON(fc)
+		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(out)
@@ -2075,6 +2104,10 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if fc != nil {
+		fmt.Print(renderConvoyForecast(fc))
+	}
+
 	// Hint for owned convoys when all issues are complete
 	if isOwned && completed == len(tracked) && len(tracked) > 0 && normalizeConvoyStatus(convoy.Status) == convoyStatusOpen {
 		fmt.Printf("\n  %s\n", style.Dim.Render("All issues complete. Land with: gt convoy land "+convoyID))
@@ -2463,6 +2496,15 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 	// Fetch fresh issue details via bd show (uses prefix routing for cross-rig).
 	freshDetails := getIssueDetailsBatch(trackedIDs)
 
+	// Beads that do not resolve locally may live in a federated peer town.
+	var missing []string
+	for _, id := range trackedIDs {
+		if _, ok := freshDetails[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	remote := federation.LookupRemoteBeads(townBeads, missing)
+
 	// Build tracked dependency structs from fresh details. When fresh details
 	// are missing (cross-rig DB unreachable, missing, parked, or unroutable
 	// from town root), mark the dep with trackedStatusUnknown so callers can
@@ -2475,6 +2517,8 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 		}
 		if details, ok := freshDetails[id]; ok {
 			applyFreshIssueDetails(&dep, details)
+		} else if rb, ok := remote[id]; ok {
+			applyRemoteBead(&dep, rb)
 		} else {
 			dep.Status = trackedStatusUnknown
 		}
@@ -2516,6 +2560,21 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 	return tracked, nil
 }
 
+// applyRemoteBead fills a tracked dependency from a peer town's last known
+// status. The ID is shown as "<peer>:<id>" so it reads as remote.
+func applyRemoteBead(dep *trackedDependency, rb *federation.TrackedRemoteBead) {
+	dep.ID = rb.Ref()
+	dep.Status = strings.TrimSpace(rb.Status)
+	if dep.Status == "" {
+		dep.Status = trackedStatusUnknown
+	}
+	dep.Title = rb.Title
+	dep.Assignee = rb.Assignee
+	dep.IssueType = rb.IssueType
+	dep.Labels = rb.Labels
+	dep.Blocked = rb.Blocked
+}
+
 // bdDepListTracked runs `bd dep list <convoyID> --direction=down --type=tracks --json`
 // and returns the tracked issue IDs (unwrapped from external: prefixes).
 // Uses --allow-stale for consistency with sling's other bd calls (verifyBeadExists,
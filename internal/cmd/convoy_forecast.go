@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forecast"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// convoyStatusForecast adds a completion forecast to gt convoy status.
+var convoyStatusForecast bool
+
+// convoyForecastLeverageLimit caps how many beads the human output suggests
+// prioritizing.
+const convoyForecastLeverageLimit = 3
+
+// convoyForecastJSON is the forecast section of gt convoy status --json.
+type convoyForecastJSON struct {
+	Remaining      int                  `json:"remaining"`
+	HistorySamples int                  `json:"history_samples"`
+	P50            string               `json:"p50"`
+	P90            string               `json:"p90"`
+	P50At          time.Time            `json:"p50_at"`
+	P90At          time.Time            `json:"p90_at"`
+	CriticalPath   []string             `json:"critical_path"`
+	Leverage       []convoyLeverageJSON `json:"leverage,omitempty"`
+}
+
+// convoyLeverageJSON describes one bead that gates convoy completion.
+type convoyLeverageJSON struct {
+	ID          string  `json:"id"`
+	Criticality float64 `json:"criticality"`
+	Median      string  `json:"median"`
+}
+
+// forecastConvoy builds the convoy's execution DAG and runs a Monte Carlo
+// completion forecast over it using sling-to-merge history from the events
+// log. Non-slingable beads (epics, convoys) carry no work of their own and
+// are left out of the simulation.
+func forecastConvoy(convoyID string, now time.Time) (*forecast.Result, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, err
+	}
+	beadInfos, deps, err := collectConvoyBeads(convoyID)
+	if err != nil {
+		return nil, err
+	}
+	dag := buildConvoyDAG(beadInfos, deps)
+	if cycle := detectCycles(dag); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " → "))
+	}
+
+	history, err := forecast.LoadHistory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return forecast.Run(forecastTasksFromDAG(dag, history), history, forecast.Options{Now: now})
+}
+
+// forecastTasksFromDAG converts slingable DAG nodes into forecast tasks.
+// In-flight beads start from their first sling so elapsed work is credited.
+func forecastTasksFromDAG(dag *ConvoyDAG, history *forecast.History) []forecast.Task {
+	var tasks []forecast.Task
+	for _, id := range sortedNodeIDs(dag) {
+		node := dag.Nodes[id]
+		if !isSlingableType(node.Type) {
+			continue
+		}
+		task := forecast.Task{
+			ID:              node.ID,
+			Type:            node.Type,
+			EstimateMinutes: node.Estimate,
+			Done:            node.Status == "closed" || node.Status == "tombstone",
+			BlockedBy:       node.BlockedBy,
+		}
+		if !task.Done {
+			if ts, ok := history.SlungAt(node.ID); ok {
+				task.StartedAt = ts
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// buildConvoyForecastJSON converts a forecast result to its JSON form.
+func buildConvoyForecastJSON(res *forecast.Result) *convoyForecastJSON {
+	out := &convoyForecastJSON{
+		Remaining:      res.Remaining,
+		HistorySamples: res.HistorySize,
+		P50:            res.P50.Round(time.Minute).String(),
+		P90:            res.P90.Round(time.Minute).String(),
+		P50At:          res.P50At.UTC(),
+		P90At:          res.P90At.UTC(),
+		CriticalPath:   res.CriticalPath,
+	}
+	if out.CriticalPath == nil {
+		out.CriticalPath = []string{}
+	}
+	for _, l := range res.Leverage {
+		out.Leverage = append(out.Leverage, convoyLeverageJSON{
+			ID:          l.ID,
+			Criticality: l.Criticality,
+			Median:      l.Median.Round(time.Minute).String(),
+		})
+	}
+	return out
+}
+
+// renderConvoyForecast renders the forecast section of gt convoy status.
+func renderConvoyForecast(res *forecast.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n  %s\n", style.Bold.Render("Forecast:"))
+	if res.Remaining == 0 {
+		b.WriteString("    All tracked work is complete.\n")
+		return b.String()
+	}
+
+	basis := fmt.Sprintf("%d remaining, %d historical samples", res.Remaining, res.HistorySize)
+	if res.HistorySize == 0 {
+		basis = fmt.Sprintf("%d remaining, no history: using estimates", res.Remaining)
+	}
+	fmt.Fprintf(&b, "    %s\n", style.Dim.Render(basis))
+	fmt.Fprintf(&b, "    P50:  %s (in %s)\n", res.P50At.Local().Format("Jan 2 15:04"), formatDuration(res.P50))
+	fmt.Fprintf(&b, "    P90:  %s (in %s)\n", res.P90At.Local().Format("Jan 2 15:04"), formatDuration(res.P90))
+	if len(res.CriticalPath) > 0 {
+		fmt.Fprintf(&b, "    Critical path: %s\n", strings.Join(res.CriticalPath, " → "))
+	}
+
+	if len(res.Leverage) > 0 {
+		b.WriteString("    Prioritize to shorten:\n")
+		for i, l := range res.Leverage {
+			if i == convoyForecastLeverageLimit {
+				break
+			}
+			fmt.Fprintf(&b, "      %s  %3.0f%% critical, ~%s\n", l.ID, l.Criticality*100, formatDuration(l.Median))
+		}
+	}
+	return b.String()
+}
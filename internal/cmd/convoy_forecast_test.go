@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forecast"
+)
+
+func TestForecastTasksFromDAG(t *testing.T) {
+	dag := buildConvoyDAG([]BeadInfo{
+		{ID: "gt-epic", Type: "epic", Status: "open"},
+		{ID: "gt-a", Type: "task", Status: "closed"},
+		{ID: "gt-b", Type: "task", Status: "in_progress", Estimate: 45},
+		{ID: "gt-c", Type: "bug", Status: "open"},
+	}, []DepInfo{
+		{IssueID: "gt-b", DependsOnID: "gt-a", Type: "blocks"},
+		{IssueID: "gt-c", DependsOnID: "gt-b", Type: "blocks"},
+		{IssueID: "gt-a", DependsOnID: "gt-epic", Type: "parent-child"},
+	})
+	history, err := forecast.ParseHistory(strings.NewReader(
+		`{"ts":"2026-01-01T10:00:00Z","type":"sling","payload":{"bead":"gt-b"}}` + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := forecastTasksFromDAG(dag, history)
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3 (epic excluded): %+v", len(tasks), tasks)
+	}
+	byID := map[string]forecast.Task{}
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	if !byID["gt-a"].Done {
+		t.Error("closed bead gt-a should be done")
+	}
+	b := byID["gt-b"]
+	if b.StartedAt.IsZero() || b.EstimateMinutes != 45 || len(b.BlockedBy) != 1 {
+		t.Errorf("gt-b = %+v, want started, estimate 45, one blocker", b)
+	}
+	if !byID["gt-c"].StartedAt.IsZero() {
+		t.Error("unslung bead gt-c should have no start time")
+	}
+}
+
+func TestRenderConvoyForecast(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	res := &forecast.Result{
+		P50:          3 * time.Hour,
+		P90:          5 * time.Hour,
+		P50At:        now.Add(3 * time.Hour),
+		P90At:        now.Add(5 * time.Hour),
+		CriticalPath: []string{"gt-a", "gt-b"},
+		Leverage:     []forecast.Leverage{{ID: "gt-a", Criticality: 0.9, Median: time.Hour}},
+		Remaining:    2,
+		HistorySize:  12,
+	}
+	out := renderConvoyForecast(res)
+	for _, want := range []string{"P50:", "in 3h 0m", "P90:", "gt-a → gt-b", "90% critical", "12 historical samples"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("forecast output missing %q:\n%s", want, out)
+		}
+	}
+
+	done := renderConvoyForecast(&forecast.Result{})
+	if !strings.Contains(done, "complete") {
+		t.Errorf("all-done forecast output = %q", done)
+	}
+
+	js := buildConvoyForecastJSON(res)
+	if js.P50 != "3h0m0s" || len(js.Leverage) != 1 || js.CriticalPath[1] != "gt-b" {
+		t.Errorf("forecast JSON = %+v", js)
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+// convoyGraphFormat is the output format for gt convoy graph.
+var convoyGraphFormat string
+
+// convoyGraphTitle overrides the rendered graph title.
+var convoyGraphTitle string
+
+var convoyGraphCmd = &cobra.Command{
+	Use:   "graph <epic-id | task-id... | convoy-id>",
+	Short: "Render staged waves as a DOT, Mermaid or SVG graph",
+	Long: `Render the execution waves that 'gt convoy stage' would compute as a graph.
+
+Accepts the same inputs as 'gt convoy stage' but is read-only: nothing is
+created, re-staged or launched. Each wave becomes a column (or cluster), tasks
+are colored by status, and edges show blocking dependencies. Tasks gated by
+non-slingable blockers are shown in a trailing "Gated" column.
+
+SVG is rendered in-process and needs no Graphviz or Mermaid install.
+
+Examples:
+  gt convoy graph gt-epic-abc --format svg > epic.svg
+  gt convoy graph hq-cv-xyz --format mermaid
+  gt convoy graph gt-a gt-b gt-c | dot -Tpng -o waves.png`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runConvoyGraph,
+}
+
+func init() {
+	convoyGraphCmd.Flags().StringVar(&convoyGraphFormat, "format", string(graph.FormatDOT), "Output format: dot, mermaid, svg")
+	convoyGraphCmd.Flags().StringVar(&convoyGraphTitle, "title", "", "Graph title (default: derived from input)")
+}
+
+func runConvoyGraph(cmd *cobra.Command, args []string) error {
+	format, err := graph.ParseFormat(convoyGraphFormat)
+	if err != nil {
+		return err
+	}
+	if err := validateStageArgs(args); err != nil {
+		return err
+	}
+
+	beadTypes := make(map[string]string)
+	beadResults := make(map[string]*bdShowResult)
+	for _, arg := range args {
+		result, err := bdShow(arg)
+		if err != nil {
+			return fmt.Errorf("cannot resolve bead %s: %w", arg, err)
+		}
+		if isConvoyIssue(result.IssueType, result.Labels) {
+			beadTypes[arg] = "convoy"
+		} else {
+			beadTypes[arg] = result.IssueType
+		}
+		beadResults[arg] = result
+	}
+	input, err := resolveInputKind(beadTypes)
+	if err != nil {
+		return err
+	}
+
+	beadInfos, deps, err := collectBeads(input)
+	if err != nil {
+		return err
+	}
+	dag := buildConvoyDAG(beadInfos, deps)
+	if cycle := detectCycles(dag); cycle != nil {
+		return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " → "))
+	}
+	waves, gated, err := computeWaves(dag)
+	if err != nil {
+		return err
+	}
+
+	title := resolveConvoyTitle(convoyGraphTitle, input, beadResults)
+	if title == "" && input.Kind == StageInputConvoy {
+		title = beadResults[input.IDs[0]].Title
+	}
+	out, err := graph.Render(convoyWavesGraph(title, waves, gated, dag), format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// convoyWavesGraph builds a ranked graph with one rank per wave. Gated tasks
+// get a final rank of their own so they stay visible.
+func convoyWavesGraph(title string, waves []Wave, gated []GatedTask, dag *ConvoyDAG) *graph.Graph {
+	g := &graph.Graph{Title: title, Ranked: true}
+	included := make(map[string]bool)
+	addNode := func(id string, rank int) {
+		node := dag.Nodes[id]
+		if node == nil || included[id] {
+			return
+		}
+		included[id] = true
+		g.Nodes = append(g.Nodes, graph.Node{ID: id, Label: node.Title, Status: node.Status, Rank: rank})
+	}
+
+	for i, wave := range waves {
+		g.RankNames = append(g.RankNames, fmt.Sprintf("Wave %d", wave.Number))
+		for _, id := range wave.Tasks {
+			addNode(id, i)
+		}
+	}
+	if len(gated) > 0 {
+		rank := len(waves)
+		g.RankNames = append(g.RankNames, "Gated")
+		for _, gt := range gated {
+			addNode(gt.TaskID, rank)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		blockers := append([]string(nil), dag.Nodes[n.ID].BlockedBy...)
+		sort.Strings(blockers)
+		for _, b := range blockers {
+			if included[b] {
+				g.Edges = append(g.Edges, graph.Edge{From: b, To: n.ID})
+			}
+		}
+	}
+	return g
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+func TestConvoyWavesGraph(t *testing.T) {
+	dag := buildConvoyDAG([]BeadInfo{
+		{ID: "gt-a", Title: "Schema", Type: "task", Status: "closed"},
+		{ID: "gt-b", Title: "API", Type: "task", Status: "open"},
+		{ID: "gt-c", Title: "UI", Type: "task", Status: "open"},
+		{ID: "gt-d", Title: "Needs call", Type: "decision", Status: "open"},
+		{ID: "gt-e", Title: "Rollout", Type: "task", Status: "open"},
+	}, []DepInfo{
+		{IssueID: "gt-b", DependsOnID: "gt-a", Type: "blocks"},
+		{IssueID: "gt-c", DependsOnID: "gt-b", Type: "blocks"},
+		{IssueID: "gt-e", DependsOnID: "gt-d", Type: "blocks"},
+	})
+	waves, gated, err := computeWaves(dag)
+	if err != nil {
+		t.Fatalf("computeWaves: %v", err)
+	}
+
+	g := convoyWavesGraph("Convoy: demo", waves, gated, dag)
+	if !g.Ranked {
+		t.Error("wave graph should use explicit ranks")
+	}
+	wantRanks := []string{"Wave 1", "Wave 2", "Wave 3", "Gated"}
+	if !reflect.DeepEqual(g.RankNames, wantRanks) {
+		t.Errorf("RankNames = %v, want %v", g.RankNames, wantRanks)
+	}
+	ranks := map[string]int{}
+	for _, n := range g.Nodes {
+		ranks[n.ID] = n.Rank
+	}
+	if ranks["gt-a"] != 0 || ranks["gt-c"] != 2 || ranks["gt-e"] != 3 {
+		t.Errorf("node ranks = %v", ranks)
+	}
+	if _, ok := ranks["gt-d"]; ok {
+		t.Error("non-slingable decision bead should not be drawn")
+	}
+	wantEdges := []graph.Edge{{From: "gt-a", To: "gt-b"}, {From: "gt-b", To: "gt-c"}}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("Edges = %v, want %v", g.Edges, wantEdges)
+	}
+}
+
+func TestDAGInfoGraph(t *testing.T) {
+	dag := &DAGInfo{
+		RootTitle: "Patrol",
+		Nodes: map[string]*DAGNode{
+			"s1": {ID: "s1", Title: "Load", Status: "closed"},
+			"s2": {ID: "s2", Title: "Check", Status: "ready", Dependencies: []string{"s1"}},
+		},
+		TierGroups: [][]string{{"s1"}, {"s2"}},
+	}
+
+	g := dagInfoGraph(dag)
+	if g.Title != "Patrol" || len(g.Nodes) != 2 || g.Nodes[1].Rank != 1 {
+		t.Errorf("graph = %+v", g)
+	}
+	if !reflect.DeepEqual(g.RankNames, []string{"Tier 0", "Tier 1"}) {
+		t.Errorf("RankNames = %v", g.RankNames)
+	}
+	if !reflect.DeepEqual(g.Edges, []graph.Edge{{From: "s1", To: "s2"}}) {
+		t.Errorf("Edges = %v", g.Edges)
+	}
+}
@@ -876,6 +876,7 @@ type ConvoyDAGNode struct {
 	Type      string // "epic", "task", "bug", etc.
 	Status    string
 	Rig       string
+	Estimate  int      // estimated minutes (0 = unestimated)
 	BlockedBy []string // IDs of beads that block this one (execution edges)
 	Blocks    []string // IDs of beads this one blocks
 	Children  []string // parent-child children (hierarchy only, not execution)
@@ -1191,11 +1192,12 @@ func appendValidationWave(dag *ConvoyDAG, waves []Wave, epicID string) ([]Wave,
 
 // BeadInfo represents raw bead data from bd show output.
 type BeadInfo struct {
-	ID     string
-	Title  string
-	Type   string // "epic", "task", "bug", etc.
-	Status string
-	Rig    string // resolved rig name
+	ID       string
+	Title    string
+	Type     string // "epic", "task", "bug", etc.
+	Status   string
+	Rig      string // resolved rig name
+	Estimate int    // estimated minutes (0 = unestimated)
 }
 
 // DepInfo represents a raw dependency from bd dep list output.
@@ -1216,11 +1218,12 @@ func buildConvoyDAG(beads []BeadInfo, deps []DepInfo) *ConvoyDAG {
 	// Create nodes from beads.
 	for _, b := range beads {
 		dag.Nodes[b.ID] = &ConvoyDAGNode{
-			ID:     b.ID,
-			Title:  b.Title,
-			Type:   b.Type,
-			Status: b.Status,
-			Rig:    b.Rig,
+			ID:       b.ID,
+			Title:    b.Title,
+			Type:     b.Type,
+			Status:   b.Status,
+			Rig:      b.Rig,
+			Estimate: b.Estimate,
 		}
 	}
 
@@ -1469,6 +1472,7 @@ type bdShowResult struct {
 	Status    string   `json:"status"`
 	IssueType string   `json:"issue_type"`
 	Labels    []string `json:"labels"`
+	Estimate  int      `json:"estimated_minutes,omitempty"`
 }
 
 // bdDepResult matches the JSON output of `bd dep list <id> --json`.
@@ -1725,11 +1729,12 @@ func collectTaskListBeads(taskIDs []string) ([]BeadInfo, []DepInfo, error) {
 		}
 
 		allBeads = append(allBeads, BeadInfo{
-			ID:     result.ID,
-			Title:  result.Title,
-			Type:   result.IssueType,
-			Status: result.Status,
-			Rig:    rigFromBeadID(result.ID),
+			ID:       result.ID,
+			Title:    result.Title,
+			Type:     result.IssueType,
+			Status:   result.Status,
+			Rig:      rigFromBeadID(result.ID),
+			Estimate: result.Estimate,
 		})
 
 		// Fetch deps.
@@ -16,6 +16,7 @@ var (
 	crewNoTmux        bool
 	crewDetached      bool
 	crewMessage       string
+	crewRefreshDoc    handoffDocFlags
 	crewAccount       string
 	crewAgentOverride string
 	crewAll           bool
@@ -172,9 +173,14 @@ var crewRefreshCmd = &cobra.Command{
 Sends a handoff mail to the workspace's own inbox, then restarts the session.
 The new session reads the handoff mail and resumes work.
 
+The handoff carries a structured section (see gt handoff --help) with the
+workspace's branch and file state filled in; add the goal and progress with
+--goal, --done, --remaining and friends.
+
 Examples:
   gt crew refresh dave                           # Refresh with auto-generated handoff
-  gt crew refresh dave -m "Working on gt-123"    # Add custom message`,
+  gt crew refresh dave -m "Working on gt-123"    # Add custom message
+  gt crew refresh dave --goal "Ship gt-123" --remaining "Write docs"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCrewRefresh,
 }
@@ -383,6 +389,7 @@ func init() {
 
 	crewRefreshCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
 	crewRefreshCmd.Flags().StringVarP(&crewMessage, "message", "m", "", "Custom handoff message")
+	addHandoffDocFlags(crewRefreshCmd, &crewRefreshDoc)
 
 	crewStatusCmd.Flags().StringVar(&crewRig, "rig", "", "Filter by rig name")
 	crewStatusCmd.Flags().BoolVar(&crewJSON, "json", false, "Output as JSON")
@@ -211,11 +211,19 @@ func runCrewRefresh(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting crew worker: %w", err)
 	}
 
+	// Record the structured handoff in the worker's clone; --strict stops
+	// the refresh before the session is touched.
+	doc, err := prepareHandoffDoc(worker.ClonePath, fmt.Sprintf("%s/crew/%s", r.Name, name), &crewRefreshDoc, false)
+	if err != nil {
+		return err
+	}
+
 	// Create handoff message
 	handoffMsg := crewMessage
 	if handoffMsg == "" {
 		handoffMsg = fmt.Sprintf("Context refresh for %s. Check mail and beads for current work state.", name)
 	}
+	handoffMsg = withHandoffDoc(handoffMsg, doc)
 
 	// Send handoff mail to self
 	mailDir := filepath.Join(worker.ClonePath, "mail")
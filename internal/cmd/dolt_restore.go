@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltRestoreDB      string
+	doltRestoreAt      string
+	doltRestorePromote bool
+	doltRestoreDiscard bool
+	doltRestoreJSON    bool
+
+	doltVerifyBackupsDB   []string
+	doltVerifyBackupsJSON bool
+)
+
+var doltRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore one database to a point in time",
+	Long: `Restore a single database to an earlier commit using Dolt's commit graph.
+
+The restore point is staged on a scratch branch (gt-restore-<commit>) and
+diffed against live main. Nothing live changes until --promote, which:
+  1. Refuses if main moved since the restore was staged
+  2. Commits any uncommitted writes as a checkpoint
+  3. Keeps main as it was on a safety branch (gt-pre-restore-<time>)
+  4. Hard-resets main to the restore point in one statement
+
+To undo a promotion, hard-reset main to the safety branch. The scratch
+branch can be inspected from 'gt dolt sql' before promoting:
+  SELECT * FROM `+"`<db>/gt-restore-<commit>`"+`.issues;
+
+--at accepts a commit hash (8+ characters), a local timestamp
+("2006-01-02 15:04:05", "2006-01-02"), or a duration ago ("2h", "90m").
+A timestamp restores the newest commit at or before it.
+
+Tables excluded from versioning (dolt_ignore, e.g. wisps) are not restored.
+
+Examples:
+  gt dolt restore --db gastown --at 2h              # Stage and show the diff
+  gt dolt restore --db gastown --at 2h --promote    # Stage and make it live
+  gt dolt restore --db gastown --at k2b4v7mq --json # Machine-readable plan
+  gt dolt restore --db gastown --discard            # Drop staged restores`,
+	RunE: runDoltRestore,
+}
+
+var doltVerifyBackupsCmd = &cobra.Command{
+	Use:   "verify-backups",
+	Short: "Restore each backup into a scratch copy and check its integrity",
+	Long: `Verify Dolt filesystem backups by reading them back in full.
+
+Each backup under .dolt-backup/ is restored into a temporary directory — the
+backup itself is only read — and the copy is checked with dolt fsck, a walk
+of the commit graph, a row count of every table, and a foreign key check.
+
+The daemon runs this on the dolt_backup patrol's verify_interval (default
+24h) and escalates failures. Exits non-zero if any backup fails.
+
+Examples:
+  gt dolt verify-backups              # Verify every backup
+  gt dolt verify-backups --db gastown # Verify one database's backup
+  gt dolt verify-backups --json`,
+	RunE: runDoltVerifyBackups,
+}
+
+func init() {
+	doltRestoreCmd.Flags().StringVar(&doltRestoreDB, "db", "", "Database to restore (required)")
+	doltRestoreCmd.Flags().StringVar(&doltRestoreAt, "at", "", "Restore point: commit hash, timestamp, or duration ago")
+	doltRestoreCmd.Flags().BoolVar(&doltRestorePromote, "promote", false, "Make the staged restore live")
+	doltRestoreCmd.Flags().BoolVar(&doltRestoreDiscard, "discard", false, "Delete staged restore branches and exit")
+	doltRestoreCmd.Flags().BoolVar(&doltRestoreJSON, "json", false, "Output as JSON")
+	_ = doltRestoreCmd.MarkFlagRequired("db")
+
+	doltVerifyBackupsCmd.Flags().StringSliceVar(&doltVerifyBackupsDB, "db", nil, "Verify only these databases' backups")
+	doltVerifyBackupsCmd.Flags().BoolVar(&doltVerifyBackupsJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltRestoreCmd)
+	doltCmd.AddCommand(doltVerifyBackupsCmd)
+}
+
+func runDoltRestore(cmd *cobra.Command, args []string) error {
+	switch {
+	case doltRestoreDiscard && (doltRestoreAt != "" || doltRestorePromote):
+		return fmt.Errorf("--discard cannot be combined with --at or --promote")
+	case !doltRestoreDiscard && doltRestoreAt == "":
+		return fmt.Errorf("--at is required (commit hash, timestamp, or duration ago)")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := doltserver.CheckServerReachable(townRoot); err != nil {
+		return err
+	}
+
+	if doltRestoreDiscard {
+		deleted, err := doltserver.DiscardRestores(townRoot, doltRestoreDB)
+		for _, name := range deleted {
+			fmt.Printf("  %s Deleted %s\n", style.Success.Render("✓"), name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			fmt.Printf("No staged restores in %s\n", doltRestoreDB)
+		}
+		return nil
+	}
+
+	plan, err := doltserver.StageRestore(townRoot, doltRestoreDB, doltRestoreAt)
+	if err != nil {
+		return err
+	}
+
+	var result *doltserver.PromoteResult
+	if doltRestorePromote && len(plan.Diff) > 0 {
+		if result, err = doltserver.PromoteRestore(townRoot, plan); err != nil {
+			return err
+		}
+	}
+
+	if doltRestoreJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*doltserver.RestorePlan
+			Promoted *doltserver.PromoteResult `json:"promoted,omitempty"`
+		}{plan, result})
+	}
+
+	printRestorePlan(plan)
+	switch {
+	case len(plan.Diff) == 0:
+		fmt.Printf("\n%s %s main already matches the restore point — nothing to restore\n",
+			style.Success.Render("✓"), plan.Database)
+	case result != nil:
+		fmt.Printf("\n%s Restored %s main to %s\n", style.Success.Render("✓"), plan.Database, shortHash(result.NewHead))
+		fmt.Printf("  Previous main kept on %s (%s)\n", style.Bold.Render(result.SafetyBranch), shortHash(result.PreviousHead))
+		fmt.Printf("  Undo (in gt dolt sql): USE `%s`; CALL DOLT_RESET('--hard', '%s');\n", plan.Database, result.SafetyBranch)
+	default:
+		fmt.Printf("\nStaged on branch %s. Live data is unchanged.\n", style.Bold.Render(plan.Branch))
+		fmt.Printf("  Inspect (in gt dolt sql): SELECT * FROM `%s/%s`.<table>;\n", plan.Database, plan.Branch)
+		fmt.Printf("  Promote: gt dolt restore --db %s --at %s --promote\n", plan.Database, plan.Point.Commit)
+		fmt.Printf("  Discard: gt dolt restore --db %s --discard\n", plan.Database)
+	}
+	return nil
+}
+
+func printRestorePlan(plan *doltserver.RestorePlan) {
+	fmt.Printf("%s Restore %s to %s\n", style.Bold.Render("●"), style.Bold.Render(plan.Database), shortHash(plan.Point.Commit))
+	fmt.Printf("  Commit:  %s (%s)\n", plan.Point.Commit, plan.Point.Date.Local().Format(time.DateTime))
+	if plan.Point.Message != "" {
+		fmt.Printf("  Message: %s\n", plan.Point.Message)
+	}
+	fmt.Printf("  Live:    %s\n", plan.LiveHead)
+	if len(plan.Diff) == 0 {
+		return
+	}
+	fmt.Printf("\n  %-28s %10s %10s %10s\n", "TABLE", "RESTORED", "REMOVED", "CHANGED")
+	for _, d := range plan.Diff {
+		fmt.Printf("  %-28s %10d %10d %10d\n", d.Table, d.RowsAdded, d.RowsDeleted, d.RowsModified)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("RESTORED rows come back; REMOVED rows were written after the restore point."))
+}
+
+func runDoltVerifyBackups(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results := health.VerifyBackups(townRoot, doltVerifyBackupsDB)
+	failed := 0
+	for _, v := range results {
+		if !v.Healthy() {
+			failed++
+		}
+	}
+
+	if doltVerifyBackupsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		if len(results) == 0 {
+			fmt.Println("No backups found in .dolt-backup/")
+			return nil
+		}
+		for _, v := range results {
+			if v.Healthy() {
+				fmt.Printf("%s %s: %d commits, %d tables, %d rows %s\n", style.Success.Render("✓"),
+					style.Bold.Render(v.Name), v.Commits, v.Tables, v.Rows,
+					style.Dim.Render(fmt.Sprintf("(%s, %dms)", shortHash(v.HeadHash), v.DurationMs)))
+			} else {
+				fmt.Printf("%s %s\n", style.Error.Render("✗"), style.Bold.Render(v.Name))
+				for _, p := range v.Problems {
+					fmt.Printf("    %s\n", p)
+				}
+			}
+			for _, diag := range v.Diagnostics {
+				fmt.Printf("    %s\n", style.Dim.Render(diag))
+			}
+		}
+	}
+
+	if failed > 0 {
+		if !doltVerifyBackupsJSON {
+			fmt.Printf("\n%d of %d backup(s) failed verification\n", failed, len(results))
+		}
+		return NewSilentExit(1)
+	}
+	return nil
+}
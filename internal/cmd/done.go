@@ -42,6 +42,12 @@ This is a convenience command for polecats that:
 4. Exits the polecat session after durable handoff
    (Witness/refinery cleanup owns the retired sandbox)
 
+Acceptance checks: if the source bead's acceptance criteria contain
+machine-checkable lines (run, exists, grep, !grep; argument in backticks,
+e.g. run: ` + "`go test ./...`" + `), they run in the worktree before anything
+is pushed, and any failure refuses submission with a report.
+Results are recorded on the MR bead: an acceptance field and a report comment.
+
 Exit statuses:
   COMPLETED      - Work done, MR submitted (default)
   ESCALATED      - Hit blocker, needs human intervention
@@ -807,7 +813,8 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 	var pushFailed bool
 	var mrFailed bool
 	var doneErrors []string
-	var convoyInfo *ConvoyInfo // Populated if issue is tracked by a convoy
+	var convoyInfo *ConvoyInfo       // Populated if issue is tracked by a convoy
+	var acceptance *acceptanceReport // Source bead's acceptance check results, if it declares any
 	if exitType == ExitCompleted {
 		if branch == defaultBranch || branch == "master" {
 			return fmt.Errorf("cannot submit %s/master branch to merge queue", defaultBranch)
@@ -855,15 +862,18 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		// this is a non-code task (email, research, analysis, PRD review)
 		// where zero commits is expected.
 		// Must be checked before the zero-commit guard below (GH#2496, gt-kvf).
+		// The source bead is loaded once here and reused by the acceptance gate.
 		isNoMergeTask := false
 		reviewOnlySource := false
+		var sourceBead *beads.Issue
 		if issueID != "" {
 			noMergeBd := beads.New(cwd)
-			noMergeIssue, showErr := noMergeBd.Show(issueID)
+			var showErr error
+			sourceBead, showErr = noMergeBd.Show(issueID)
 			if showErr != nil {
 				return fmt.Errorf("cannot inspect source issue %s before completion: %w", issueID, showErr)
 			}
-			if af := beads.ParseAttachmentFields(noMergeIssue); af != nil {
+			if af := beads.ParseAttachmentFields(sourceBead); af != nil {
 				if af.NoMerge || af.ReviewOnly {
 					isNoMergeTask = true
 				}
@@ -1032,6 +1042,17 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			aheadCount, _ = g.CommitsAhead(baseRef, "HEAD")
 		}
 
+		// Acceptance gate: run the source bead's machine-checkable criteria
+		// (run/exists/grep lines in its acceptance criteria) against the final
+		// tree, after rebase and overlay stripping. Failures refuse submission
+		// and leave the session alive to fix them.
+		if sourceBead != nil {
+			acceptance, err = runDoneAcceptanceChecks(sourceBead, cwd)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Determine merge strategy from convoy (gt-myofa.3)
 		// Convoys can override the default MR-based workflow:
 		//   direct: push commits straight to target branch, bypass refinery
@@ -1563,6 +1584,9 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if doneSkipVerify {
 				description += "\nskip_verify: true"
 			}
+			if acceptance != nil {
+				description += fmt.Sprintf("\nacceptance: %s", acceptance.Summary())
+			}
 			if worker != "" {
 				description += fmt.Sprintf("\nworker: %s", worker)
 			}
@@ -1670,6 +1694,14 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				}
 			}
 
+			// Attach the full acceptance report so the refinery and reviewers
+			// can see what was checked, not just the summary field.
+			if acceptance != nil {
+				if _, err := bd.Run("comments", "add", mrID, acceptance.String()); err != nil {
+					style.PrintWarning("could not attach acceptance report to MR %s: %v", mrID, err)
+				}
+			}
+
 			// GH#2599: Back-link source issue to MR bead for discoverability.
 			if issueID != "" {
 				comment := fmt.Sprintf("MR created: %s", mrID)
@@ -1757,6 +1789,22 @@ notifyWitness:
 		style.PrintWarning("could not log feed event: %v", err)
 	}
 
+	// Record the structured handoff: what gt handoff compliance audits, and
+	// what the next session in this sandbox reads after a deferral. A failed
+	// push or MR leaves the work unfinished. Skipped when the worktree is
+	// already gone, so it is not recreated.
+	if cwd != "" {
+		if _, err := os.Stat(cwd); err == nil {
+			if doc, err := buildHandoffDoc(cwd, sender, &handoffDocFlags{}); err == nil {
+				handoffExit := exitType
+				if pushFailed || mrFailed {
+					handoffExit = ""
+				}
+				recordPolecatHandoffDoc(cwd, doc, issueID, handoffExit)
+			}
+		}
+	}
+
 	// Update agent bead state (ZFC: self-report completion). If push/MR failed,
 	// keep the hook intact so Witness can recover the still-open work.
 	if err := updateAgentStateAfterSubmission(cwd, townRoot, exitType, issueID, pushFailed, mrFailed); err != nil {
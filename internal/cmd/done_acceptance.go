@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// acceptanceRunTimeout bounds a single "run:" acceptance check.
+const acceptanceRunTimeout = 10 * time.Minute
+
+// acceptanceOutputTail caps how much command output is kept per failed check.
+const acceptanceOutputTail = 1500
+
+// acceptanceResult is the outcome of one acceptance check.
+type acceptanceResult struct {
+	Check   beads.AcceptanceCheck
+	Passed  bool
+	Detail  string // Why it failed, with output tail for run checks
+	Elapsed time.Duration
+}
+
+// acceptanceReport is the outcome of a bead's acceptance checks.
+type acceptanceReport struct {
+	IssueID string
+	Results []acceptanceResult
+}
+
+// Failed returns the number of failed checks.
+func (r *acceptanceReport) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if !res.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary is the one-line result recorded on the MR bead, e.g. "passed 3/3".
+func (r *acceptanceReport) Summary() string {
+	total := len(r.Results)
+	if failed := r.Failed(); failed > 0 {
+		return fmt.Sprintf("failed %d/%d", failed, total)
+	}
+	return fmt.Sprintf("passed %d/%d", total, total)
+}
+
+// String renders the full report, one line per check with failure detail
+// indented below it.
+func (r *acceptanceReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Acceptance checks for %s: %s\n", r.IssueID, r.Summary())
+	for _, res := range r.Results {
+		mark := "✓"
+		if !res.Passed {
+			mark = "✗"
+		}
+		fmt.Fprintf(&b, "%s %s (%s)\n", mark, res.Check.Line, res.Elapsed.Round(time.Millisecond))
+		if res.Detail != "" {
+			for _, line := range strings.Split(res.Detail, "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// runDoneAcceptanceChecks runs the source bead's machine-checkable
+// acceptance criteria in the worktree. It returns nil when the bead
+// declares none, and an error with the full report when any check fails.
+func runDoneAcceptanceChecks(issue *beads.Issue, workDir string) (*acceptanceReport, error) {
+	issueID := issue.ID
+	checks := beads.ParseAcceptanceChecks(issue)
+	if len(checks) == 0 {
+		return nil, nil
+	}
+
+	fmt.Printf("%s Running %d acceptance check(s) for %s\n", style.Bold.Render("→"), len(checks), issueID)
+	report := runAcceptanceChecks(context.Background(), issueID, workDir, checks)
+	if report.Failed() > 0 {
+		return report, fmt.Errorf("cannot complete: acceptance checks failed\n%s\n"+
+			"Fix the failures and run gt done again.\n"+
+			"If the criteria themselves are wrong: gt done --status ESCALATED", report)
+	}
+	fmt.Printf("%s Acceptance checks %s\n", style.Bold.Render("✓"), report.Summary())
+	return report, nil
+}
+
+// runAcceptanceChecks runs every check in order; one failure does not stop
+// the rest, so the report is complete.
+func runAcceptanceChecks(ctx context.Context, issueID, workDir string, checks []beads.AcceptanceCheck) *acceptanceReport {
+	report := &acceptanceReport{IssueID: issueID}
+	for _, check := range checks {
+		start := time.Now()
+		detail := runAcceptanceCheck(ctx, workDir, check)
+		report.Results = append(report.Results, acceptanceResult{
+			Check:   check,
+			Passed:  detail == "",
+			Detail:  detail,
+			Elapsed: time.Since(start),
+		})
+	}
+	return report
+}
+
+// runAcceptanceCheck runs one check and returns why it failed, or "" if it
+// passed.
+func runAcceptanceCheck(ctx context.Context, workDir string, check beads.AcceptanceCheck) string {
+	if check.Problem != "" {
+		return "malformed check: " + check.Problem
+	}
+	switch check.Kind {
+	case beads.AcceptanceRun:
+		return runAcceptanceCommand(ctx, workDir, check.Arg)
+	case beads.AcceptanceExists:
+		files, err := acceptanceGlob(workDir, check.Arg)
+		if err != nil {
+			return err.Error()
+		}
+		if len(files) == 0 {
+			return "no such file: " + check.Arg
+		}
+		return ""
+	case beads.AcceptanceGrep, beads.AcceptanceNoGrep:
+		files, err := acceptanceGlob(workDir, check.Arg)
+		if err != nil {
+			return err.Error()
+		}
+		if len(files) == 0 {
+			return "no such file: " + check.Arg
+		}
+		re := regexp.MustCompile(check.Pattern) // validated by ParseAcceptanceChecks
+		match, err := grepFiles(workDir, files, re)
+		if err != nil {
+			return err.Error()
+		}
+		if check.Kind == beads.AcceptanceGrep && match == "" {
+			return fmt.Sprintf("no match for %q in %s", check.Pattern, check.Arg)
+		}
+		if check.Kind == beads.AcceptanceNoGrep && match != "" {
+			return "unexpected match: " + match
+		}
+		return ""
+	}
+	return "unknown check kind " + check.Kind
+}
+
+// runAcceptanceCommand runs a shell command in workDir and returns its exit
+// error and output tail on failure.
+func runAcceptanceCommand(ctx context.Context, workDir, command string) string {
+	ctx, cancel := context.WithTimeout(ctx, acceptanceRunTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: acceptance commands come from the bead the polecat was assigned
+	util.SetDetachedProcessGroup(cmd)
+	cmd.Dir = workDir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if err == nil {
+		return ""
+	}
+
+	msg := err.Error()
+	if ctx.Err() == context.DeadlineExceeded {
+		msg = fmt.Sprintf("timed out after %v", acceptanceRunTimeout)
+	}
+	output := strings.TrimSpace(out.String())
+	if len(output) > acceptanceOutputTail {
+		output = "..." + strings.ToValidUTF8(output[len(output)-acceptanceOutputTail:], "")
+	}
+	if output != "" {
+		msg += "\n" + output
+	}
+	return msg
+}
+
+// acceptanceGlob expands a worktree-relative path or glob. Patterns that
+// would reach outside the worktree are rejected.
+func acceptanceGlob(workDir, pattern string) ([]string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(pattern)) {
+		return nil, fmt.Errorf("path %q is outside the worktree", pattern)
+	}
+	matches, err := filepath.Glob(filepath.Join(workDir, filepath.FromSlash(pattern)))
+	if err != nil {
+		return nil, fmt.Errorf("bad glob %q: %w", pattern, err)
+	}
+	return matches, nil
+}
+
+// grepFiles returns the first line matching re as "path:line: text", or ""
+// if none does. Directories are skipped.
+func grepFiles(workDir string, files []string, re *regexp.Regexp) (string, error) {
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		if info.IsDir() {
+			continue
+		}
+		f, err := os.Open(path) //nolint:gosec // G304: path is inside the worktree (see acceptanceGlob)
+		if err != nil {
+			return "", err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for n := 1; scanner.Scan(); n++ {
+			if line := scanner.Text(); re.MatchString(line) {
+				_ = f.Close()
+				rel, _ := filepath.Rel(workDir, path)
+				return fmt.Sprintf("%s:%d: %s", filepath.ToSlash(rel), n, strings.TrimSpace(line)), nil
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return "", nil
+}
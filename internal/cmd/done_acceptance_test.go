@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestRunAcceptanceChecks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "billing"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	src := "package billing\n\nfunc RoundHalfEven() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "billing", "round.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &beads.Issue{AcceptanceCriteria: strings.Join([]string{
+		"run: `test -f billing/round.go`",
+		"exists: `billing/*.go`",
+		"grep: `billing/*.go func RoundHalfEven`",
+		"!grep: `billing/*.go TODO`",
+		"run: `echo boom >&2; exit 3`",
+		"exists: `docs/billing.md`",
+		"grep: `billing/round.go Truncate`",
+		"!grep: `billing/round.go ^package`",
+		"exists: `../outside`",
+		"grep: `billing/round.go`",
+	}, "\n")}
+
+	report := runAcceptanceChecks(context.Background(), "gt-abc", dir, beads.ParseAcceptanceChecks(issue))
+	if len(report.Results) != 10 {
+		t.Fatalf("got %d results, want 10", len(report.Results))
+	}
+	for i, res := range report.Results {
+		if wantPass := i < 4; res.Passed != wantPass {
+			t.Errorf("%s: passed = %v, want %v (%s)", res.Check.Line, res.Passed, wantPass, res.Detail)
+		}
+	}
+	if got := report.Summary(); got != "failed 6/10" {
+		t.Errorf("Summary = %q", got)
+	}
+
+	for _, want := range []string{
+		"Acceptance checks for gt-abc: failed 6/10",
+		"✗ run: `echo boom >&2; exit 3`",
+		"    exit status 3\n    boom",
+		"no such file: docs/billing.md",
+		`no match for "Truncate" in billing/round.go`,
+		"unexpected match: billing/round.go:1: package billing",
+		"outside the worktree",
+		"malformed check",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestRunDoneAcceptanceChecksUsesLoadedIssue(t *testing.T) {
+	dir := t.TempDir()
+	if report, err := runDoneAcceptanceChecks(&beads.Issue{ID: "gt-abc", AcceptanceCriteria: "Looks good to a human"}, dir); report != nil || err != nil {
+		t.Errorf("no machine checks: report = %v, err = %v", report, err)
+	}
+
+	issue := &beads.Issue{ID: "gt-abc", AcceptanceCriteria: "run: `true`\nexists: `missing.txt`"}
+	report, err := runDoneAcceptanceChecks(issue, dir)
+	if err == nil || report == nil || report.IssueID != "gt-abc" || report.Summary() != "failed 1/2" {
+		t.Errorf("report = %v, err = %v, want failed 1/2 for gt-abc", report, err)
+	}
+}
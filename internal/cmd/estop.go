@@ -1,7 +1,7 @@
 // Emergency stop (gt estop / gt thaw) — pause and resume agent work.
 //
-// Original implementation by outdoorsea (PR #3237). Cherry-picked for
-// manual-only operation: no daemon auto-trigger.
+// Original implementation by outdoorsea (PR #3237). The daemon's
+// auto_estop patrol trips the same stop via the hidden --auto flag.
 package cmd
 
 import (
@@ -21,6 +21,7 @@ import (
 var (
 	estopReason string
 	estopRig    string
+	estopAuto   bool
 	thawRig     string
 )
 
@@ -39,6 +40,12 @@ Use --rig to freeze a single rig instead of the whole town. Per-rig
 E-stop is useful when traveling or pausing non-critical work while
 keeping other rigs running.
 
+The daemon can also trip the E-stop automatically when an anomaly
+threshold is reached (mass session deaths, cost burn, push storms to
+main, repeated push rejections, low disk). Configure triggers under
+patrols.auto_estop in mayor/daemon.json. Auto stops are cleared the same
+way as manual ones.
+
 To resume: gt thaw [--rig <name>]
 
 Examples:
@@ -102,6 +109,8 @@ Examples:
 func init() {
 	estopCmd.Flags().StringVarP(&estopReason, "reason", "r", "", "Reason for the E-stop")
 	estopCmd.Flags().StringVar(&estopRig, "rig", "", "Freeze only this rig (instead of all)")
+	estopCmd.Flags().BoolVar(&estopAuto, "auto", false, "Record the stop as auto-triggered (used by the daemon)")
+	_ = estopCmd.Flags().MarkHidden("auto")
 	thawCmd.Flags().StringVar(&thawRig, "rig", "", "Thaw only this rig (instead of all)")
 	estopCmd.AddCommand(estopStatusCmd)
 	rootCmd.AddCommand(estopCmd)
@@ -129,7 +138,7 @@ func runEstop(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create the sentinel file first — this is the source of truth
-	if err := estop.Activate(townRoot, estop.TriggerManual, estopReason); err != nil {
+	if err := estop.Activate(townRoot, estopTrigger(), estopReason); err != nil {
 		return fmt.Errorf("failed to create ESTOP file: %w", err)
 	}
 
@@ -155,6 +164,14 @@ func runEstop(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// estopTrigger is the trigger recorded in the ESTOP file.
+func estopTrigger() string {
+	if estopAuto {
+		return estop.TriggerAuto
+	}
+	return estop.TriggerManual
+}
+
 func runEstopRig(townRoot, rigName string) error {
 	if estop.IsRigActive(townRoot, rigName) {
 		info := estop.ReadRig(townRoot, rigName)
@@ -165,7 +182,7 @@ func runEstopRig(townRoot, rigName string) error {
 		return nil
 	}
 
-	if err := estop.ActivateRig(townRoot, rigName, estop.TriggerManual, estopReason); err != nil {
+	if err := estop.ActivateRig(townRoot, rigName, estopTrigger(), estopReason); err != nil {
 		return fmt.Errorf("failed to create ESTOP file for %s: %w", rigName, err)
 	}
 
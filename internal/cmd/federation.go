@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	federationHosts      []string
+	federationListen     string
+	federationPeerCAFile string
+	federationStatusJSON bool
+)
+
+var federationCmd = &cobra.Command{
+	Use:     "federation",
+	GroupID: GroupServices,
+	Short:   "Link this town with peer towns",
+	RunE:    requireSubcommand,
+	Long: `Federate mail and convoy tracking across towns.
+
+Each town gets a certificate from its proxy CA (.runtime/ca) and registers
+peer towns by federation name, URL and CA certificate. Connections are
+mutual TLS, pinned to the registered CA and name.
+
+Once a peer is registered:
+  gt mail send build:gastown/Toast -s "..."   # mail a polecat in town "build"
+  gt convoy add hq-cv-abc build:gt-xyz        # track a bead in town "build"
+
+Mail is store-and-forward: it waits in the outbox until the peer returns a
+delivery receipt. The daemon flushes the outbox on each heartbeat; run
+'gt federation flush' to deliver immediately.
+
+Setup (on each town):
+  gt federation init laptop --host laptop.local
+  gt federation ca > laptop-ca.pem             # give this to the peer
+  gt federation peer add build https://build.local:7443 --ca build-ca.pem
+  gt federation serve`,
+}
+
+var federationInitCmd = &cobra.Command{
+	Use:   "init <name>",
+	Short: "Set this town's federation name and issue its certificate",
+	Long: `Set this town's federation name and hosts, and issue the town certificate.
+
+Hosts are the DNS names or IP addresses peers use to reach this town. Run
+init again after changing hosts to reissue the certificate.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFederationInit,
+}
+
+var federationCACmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Print this town's CA certificate for peers to register",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationCA,
+}
+
+var federationPeerCmd = &cobra.Command{
+	Use:   "peer",
+	Short: "Manage peer towns",
+	RunE:  requireSubcommand,
+}
+
+var federationPeerAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Register a peer town",
+	Long: `Register a peer town under its federation name.
+
+The name must be the one the peer passed to 'gt federation init': only that
+peer's town certificate is accepted for it. It is also what you type in
+addresses ("<name>:rig/polecat"). The URL is
+the peer's federation endpoint; --ca is the peer's CA certificate, as
+printed by 'gt federation ca' on the peer.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runFederationPeerAdd,
+}
+
+var federationPeerRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a peer town",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFederationPeerRemove,
+}
+
+var federationPeerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List peer towns",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationPeerList,
+}
+
+var federationPingCmd = &cobra.Command{
+	Use:   "ping <peer>",
+	Short: "Check connectivity and authentication with a peer",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFederationPing,
+}
+
+var federationServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the federation endpoint in the foreground",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationServe,
+}
+
+var federationFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Deliver queued outbound mail to peers now",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationFlush,
+}
+
+var federationStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show federation identity, peers and queued mail",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationStatus,
+}
+
+func init() {
+	federationInitCmd.Flags().StringArrayVar(&federationHosts, "host", nil, "DNS name or IP peers use to reach this town (repeatable)")
+	federationInitCmd.Flags().StringVar(&federationListen, "listen", "", "Address for 'gt federation serve' (default "+config.DefaultFederationListen+")")
+	federationPeerAddCmd.Flags().StringVar(&federationPeerCAFile, "ca", "", "Path to the peer's CA certificate (PEM)")
+	_ = federationPeerAddCmd.MarkFlagRequired("ca")
+	federationServeCmd.Flags().StringVar(&federationListen, "listen", "", "Override the configured listen address")
+	federationStatusCmd.Flags().BoolVar(&federationStatusJSON, "json", false, "Output as JSON")
+
+	federationPeerCmd.AddCommand(federationPeerAddCmd, federationPeerRemoveCmd, federationPeerListCmd)
+	federationCmd.AddCommand(federationInitCmd, federationCACmd, federationPeerCmd,
+		federationPingCmd, federationServeCmd, federationFlushCmd, federationStatusCmd)
+	rootCmd.AddCommand(federationCmd)
+}
+
+// loadFederation returns the town root and its federation config.
+func loadFederation() (string, *config.FederationConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateFederationConfig(config.FederationConfigPath(townRoot))
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, cfg, nil
+}
+
+func runFederationInit(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	cfg.Name = args[0]
+	if cmd.Flags().Changed("host") {
+		cfg.Hosts = federationHosts
+	}
+	if federationListen != "" {
+		cfg.Listen = federationListen
+	}
+	if err := config.SaveFederationConfig(config.FederationConfigPath(townRoot), cfg); err != nil {
+		return err
+	}
+	id, err := federation.IssueIdentity(townRoot, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Federation identity %s\n", style.Bold.Render("✓"), cfg.Name)
+	fmt.Printf("  Certificate expires %s\n", id.Leaf.NotAfter.Format("2006-01-02"))
+	if len(cfg.Hosts) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No --host set; peers must reach this town as "+cfg.Name))
+	}
+	fmt.Printf("\nShare this town's CA with peers: gt federation ca > %s-ca.pem\n", cfg.Name)
+	return nil
+}
+
+func runFederationCA(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	id, err := federation.LoadIdentity(townRoot, cfg)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(id.CA.CertPEM)
+	return err
+}
+
+func runFederationPeerAdd(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	name, url := args[0], args[1]
+	if !config.ValidFederationPeerName(name) {
+		return fmt.Errorf("invalid peer name %q: use letters, digits, '-' and '_' (and not a reserved address prefix)", name)
+	}
+	if name == cfg.Name {
+		return fmt.Errorf("peer name %q is this town's own federation name", name)
+	}
+	caPEM, err := os.ReadFile(federationPeerCAFile) //nolint:gosec // G304: path is from the operator
+	if err != nil {
+		return fmt.Errorf("reading peer CA: %w", err)
+	}
+	_, replaced := cfg.Peers[name]
+	cfg.Peers[name] = &config.FederationPeer{URL: url, CACert: string(caPEM)}
+	if err := config.SaveFederationConfig(config.FederationConfigPath(townRoot), cfg); err != nil {
+		return err
+	}
+	verb := "Added"
+	if replaced {
+		verb = "Updated"
+	}
+	fmt.Printf("%s %s peer %s (%s)\n", style.Bold.Render("✓"), verb, name, url)
+	return nil
+}
+
+func runFederationPeerRemove(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if _, ok := cfg.Peers[name]; !ok {
+		return fmt.Errorf("peer %q is not registered", name)
+	}
+	delete(cfg.Peers, name)
+	if err := config.SaveFederationConfig(config.FederationConfigPath(townRoot), cfg); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed peer %s\n", style.Bold.Render("✓"), name)
+	if queued, _ := mail.ListFederatedOutbox(townRoot); countForPeer(queued, name) > 0 {
+		style.PrintWarning("%d message(s) for %s remain queued in %s", countForPeer(queued, name), name, mail.FederationOutboxDir(townRoot, name))
+	}
+	return nil
+}
+
+func runFederationPeerList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Peers) == 0 {
+		fmt.Println("No peers registered.")
+		return nil
+	}
+	for _, name := range sortedPeerNames(cfg) {
+		fmt.Printf("  %s  %s\n", style.Bold.Render(name), cfg.Peers[name].URL)
+	}
+	return nil
+}
+
+func runFederationPing(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	peer, ok := cfg.Peers[args[0]]
+	if !ok {
+		return fmt.Errorf("peer %q is not registered", args[0])
+	}
+	id, err := federation.LoadIdentity(townRoot, cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	start := time.Now()
+	remote, err := federation.NewClient(id, args[0], peer).Ping(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s is town %s (%s)\n", style.Bold.Render("✓"), args[0], remote, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func runFederationServe(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	id, err := federation.LoadIdentity(townRoot, cfg)
+	if err != nil {
+		return err
+	}
+	addr := federationListen
+	if addr == "" {
+		addr = cfg.Listen
+	}
+	if addr == "" {
+		addr = config.DefaultFederationListen
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigCh
+		stop()
+	}()
+
+	fmt.Printf("Federation endpoint for %s listening on %s\n", cfg.Name, addr)
+	return federation.NewServer(townRoot, id).ListenAndServe(ctx, addr)
+}
+
+func runFederationFlush(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	result, err := federation.Flush(ctx, townRoot)
+	if err != nil {
+		return err
+	}
+	for _, r := range result.Delivered {
+		fmt.Printf("%s %s delivered to %s:%s\n", style.Bold.Render("✓"), r.ID, r.Peer, r.To)
+	}
+	for _, fm := range result.Failed {
+		fmt.Printf("%s %s for %s:%s: %s\n", style.Error.Render("✗"), fm.ID, fm.Peer, fm.To, fm.LastError)
+	}
+	if len(result.Delivered) == 0 && len(result.Failed) == 0 {
+		fmt.Println("Outbox is empty.")
+	}
+	if len(result.Failed) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runFederationStatus(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadFederation()
+	if err != nil {
+		return err
+	}
+	queued, err := mail.ListFederatedOutbox(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if federationStatusJSON {
+		peers := make(map[string]string, len(cfg.Peers))
+		for name, p := range cfg.Peers {
+			peers[name] = p.URL
+		}
+		if queued == nil {
+			queued = []*mail.FederatedMail{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"name":   cfg.Name,
+			"peers":  peers,
+			"outbox": queued,
+		})
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = style.Dim.Render("(not initialized)")
+	}
+	fmt.Printf("Town:   %s\n", name)
+	fmt.Printf("Peers:  %d\n", len(cfg.Peers))
+	for _, p := range sortedPeerNames(cfg) {
+		fmt.Printf("  %s  %s  %d queued\n", style.Bold.Render(p), cfg.Peers[p].URL, countForPeer(queued, p))
+	}
+	if len(queued) == 0 {
+		fmt.Println("Outbox: empty")
+		return nil
+	}
+	fmt.Printf("Outbox: %d queued\n", len(queued))
+	for _, fm := range queued {
+		line := fmt.Sprintf("  %s → %s:%s  %q", fm.ID, fm.Peer, fm.To, fm.Message.Subject)
+		if fm.Attempts > 0 {
+			line += style.Dim.Render(fmt.Sprintf("  (%d attempts, last: %s)", fm.Attempts, fm.LastError))
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func sortedPeerNames(cfg *config.FederationConfig) []string {
+	names := make([]string, 0, len(cfg.Peers))
+	for name := range cfg.Peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func countForPeer(queued []*mail.FederatedMail, peer string) int {
+	n := 0
+	for _, fm := range queued {
+		if fm.Peer == peer {
+			n++
+		}
+	}
+	return n
+}
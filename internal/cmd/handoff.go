@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/handoff"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -56,6 +57,19 @@ The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+Structured handoff: the successor gets a typed summary rendered by gt prime.
+Supply it with --goal, --done, --remaining, --blocker, --decision, --rerun and
+--touched (list flags repeat), or all at once with --doc <file.json>. Branch,
+commits, uncommitted files, hooked bead and molecule step are filled in from
+the workspace. The handoff is validated before the session ends; incomplete
+ones are recorded as such (see gt handoff compliance), and --strict refuses
+to hand off until it is complete.
+
+  gt handoff --goal "Fix rounding in invoices" \
+    --done "Reproduced with TestInvoiceTotal" \
+    --remaining "Patch money.Round" --blocker "TestInvoiceTotal fails" \
+    --rerun "go test ./billing -run TestInvoiceTotal"
+
 The --cycle flag triggers automatic session cycling (used by PreCompact hooks).
 Unlike --auto (state only) or normal handoff (polecat→gt-done redirect), --cycle
 always does a full respawn regardless of role. This enables crew workers and
@@ -108,13 +122,31 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		handoffMessage = strings.TrimRight(string(data), "\n")
 	}
 
+	// Hook-driven handoffs (--auto, --cycle) carry any structured fields into
+	// the handoff mail but are not validated: the PreCompact hook supplies no
+	// goal or progress. A polecat's cycle is the exception: it is how a
+	// polecat hands off mid-work, so its doc is completed from the hooked
+	// issue and recorded for the successor and for gt handoff compliance.
+	var hookDoc *handoff.Doc
+	if handoffAuto || handoffCycle {
+		var err error
+		if hookDoc, err = sessionHandoffDoc(args, false); err != nil {
+			return err
+		}
+		if _, isPolecat := polecatFromEnv(); isPolecat && handoffCycle && hookDoc != nil && !handoffDryRun {
+			if cwd, err := os.Getwd(); err == nil {
+				recordPolecatHandoffDoc(cwd, hookDoc, "", "")
+			}
+		}
+	}
+
 	// --auto mode: save state only, no session cycling.
 	// Used by PreCompact hook to preserve state before compaction.
 	// Note: auto-mode exits here, before the git-status warning check below.
 	// This is intentional — auto-handoffs are triggered by hooks and should not
 	// spam warnings. The --no-git-check flag has no effect in auto mode.
 	if handoffAuto {
-		return runHandoffAuto()
+		return runHandoffAuto(hookDoc)
 	}
 
 	// --cycle mode: full session cycling, triggered by PreCompact hook.
@@ -125,31 +157,11 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	// Flow: collect state → send handoff mail → respawn pane (fresh Claude instance)
 	// The successor session picks up hooked work via SessionStart hook (gt prime --hook).
 	if handoffCycle {
-		return runHandoffCycle()
+		return runHandoffCycle(hookDoc)
 	}
 
 	// Check if we're a polecat - polecats use gt done instead.
-	// Check GT_ROLE first: coordinators (mayor, witness, etc.) may have a stale
-	// GT_POLECAT in their environment from spawning polecats. Only block if the
-	// parsed role is actually polecat (handles compound forms like
-	// "gastown/polecats/Toast"). If GT_ROLE is unset, fall back to GT_POLECAT.
-	isPolecat := false
-	polecatName := ""
-	if role := os.Getenv("GT_ROLE"); role != "" {
-		parsedRole, _, name := parseRoleString(role)
-		if parsedRole == RolePolecat {
-			isPolecat = true
-			polecatName = name
-			// Bare "polecat" role yields empty name; fall back to GT_POLECAT.
-			if polecatName == "" {
-				polecatName = os.Getenv("GT_POLECAT")
-			}
-		}
-	} else if name := os.Getenv("GT_POLECAT"); name != "" {
-		isPolecat = true
-		polecatName = name
-	}
-	if isPolecat {
+	if polecatName, isPolecat := polecatFromEnv(); isPolecat {
 		fmt.Printf("%s Polecat detected (%s) - using gt done for handoff\n",
 			style.Bold.Render("🐾"), polecatName)
 		// Polecats don't respawn themselves - Witness handles lifecycle
@@ -160,6 +172,14 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		return doneCmd.Run()
 	}
 
+	// Build, validate and record the structured handoff before the session is
+	// touched, so --strict can stop an incomplete handoff. Polecats never get
+	// here: gt done records theirs.
+	doc, err := sessionHandoffDoc(args, true)
+	if err != nil {
+		return err
+	}
+
 	// Prompt for confirmation unless --yes/-y was passed or stdin is not a TTY.
 	// Only interactive (human) sessions get prompted; agent automation proceeds
 	// without blocking on stdin (gas-6z0).
@@ -187,6 +207,7 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 			handoffSubject = "Session handoff with context"
 		}
 	}
+	handoffMessage = withHandoffDoc(handoffMessage, doc)
 
 	// Use a socket-aware Tmux for pane operations. The calling process may be
 	// on a different tmux server than the town socket (e.g., default socket).
@@ -372,10 +393,33 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	return t.RespawnPane(pane, restartCmd)
 }
 
+// polecatFromEnv reports whether this session is a polecat, and its name.
+// GT_ROLE is checked first: coordinators (mayor, witness, etc.) may have a
+// stale GT_POLECAT in their environment from spawning polecats. Only a role
+// that parses as polecat counts (handles compound forms like
+// "gastown/polecats/Toast"). If GT_ROLE is unset, GT_POLECAT decides.
+func polecatFromEnv() (string, bool) {
+	if role := os.Getenv("GT_ROLE"); role != "" {
+		parsedRole, _, name := parseRoleString(role)
+		if parsedRole != RolePolecat {
+			return "", false
+		}
+		// Bare "polecat" role yields empty name; fall back to GT_POLECAT.
+		if name == "" {
+			name = os.Getenv("GT_POLECAT")
+		}
+		return name, true
+	}
+	if name := os.Getenv("GT_POLECAT"); name != "" {
+		return name, true
+	}
+	return "", false
+}
+
 // runHandoffAuto saves state without cycling the session.
 // Used by the PreCompact hook to preserve context before compaction.
 // No tmux required — just collects state, sends handoff mail, and writes marker.
-func runHandoffAuto() error {
+func runHandoffAuto(doc *handoff.Doc) error {
 	// Build subject
 	subject := handoffSubject
 	if subject == "" {
@@ -391,6 +435,7 @@ func runHandoffAuto() error {
 	if message == "" {
 		message = collectHandoffState()
 	}
+	message = withHandoffDoc(message, doc)
 
 	if handoffDryRun {
 		fmt.Printf("[auto-handoff] Would send mail: subject=%q\n", subject)
@@ -452,7 +497,7 @@ func runHandoffAuto() error {
 //
 // The successor session starts via SessionStart hook (gt prime --hook),
 // finds the hooked work, and continues from where we left off.
-func runHandoffCycle() error {
+func runHandoffCycle(doc *handoff.Doc) error {
 	// Build subject
 	subject := handoffSubject
 	if subject == "" {
@@ -468,6 +513,7 @@ func runHandoffCycle() error {
 	if message == "" {
 		message = collectHandoffState()
 	}
+	message = withHandoffDoc(message, doc)
 
 	// Must be in tmux to respawn
 	if !tmux.IsInsideTmux() {
@@ -475,7 +521,7 @@ func runHandoffCycle() error {
 		fmt.Fprintf(os.Stderr, "handoff --cycle: not in tmux, falling back to state-save only\n")
 		handoffMessage = message
 		handoffSubject = subject
-		return runHandoffAuto()
+		return runHandoffAuto(nil)
 	}
 
 	pane := os.Getenv("TMUX_PANE")
@@ -483,7 +529,7 @@ func runHandoffCycle() error {
 		fmt.Fprintf(os.Stderr, "handoff --cycle: TMUX_PANE not set, falling back to state-save only\n")
 		handoffMessage = message
 		handoffSubject = subject
-		return runHandoffAuto()
+		return runHandoffAuto(nil)
 	}
 
 	currentSession, err := getCurrentTmuxSession()
@@ -491,7 +537,7 @@ func runHandoffCycle() error {
 		fmt.Fprintf(os.Stderr, "handoff --cycle: could not get session: %v, falling back to state-save only\n", err)
 		handoffMessage = message
 		handoffSubject = subject
-		return runHandoffAuto()
+		return runHandoffAuto(nil)
 	}
 
 	// Use the caller's socket for pane operations (same as runHandoff).
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/handoff"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// handoffDocStaleAfter is how long gt prime keeps showing a handoff record.
+const handoffDocStaleAfter = 24 * time.Hour
+
+// handoffDocFlags are the structured handoff fields an agent can supply.
+type handoffDocFlags struct {
+	file      string
+	goal      string
+	done      []string
+	remaining []string
+	blockers  []string
+	decisions []string
+	rerun     []string
+	touched   []string
+	strict    bool
+}
+
+var handoffDocOpts handoffDocFlags
+
+// addHandoffDocFlags registers the structured handoff flags on cmd.
+func addHandoffDocFlags(cmd *cobra.Command, f *handoffDocFlags) {
+	cmd.Flags().StringVar(&f.file, "doc", "", "Read the structured handoff from a JSON file (fields: goal, done, remaining, blockers, decisions, commands, files_touched)")
+	cmd.Flags().StringVar(&f.goal, "goal", "", "Handoff: what this session was trying to achieve")
+	cmd.Flags().StringArrayVar(&f.done, "done", nil, "Handoff: something completed (repeatable)")
+	cmd.Flags().StringArrayVar(&f.remaining, "remaining", nil, "Handoff: something left to do (repeatable)")
+	cmd.Flags().StringArrayVar(&f.blockers, "blocker", nil, "Handoff: a blocker, e.g. a failing test name (repeatable)")
+	cmd.Flags().StringArrayVar(&f.decisions, "decision", nil, "Handoff: a decision made and why (repeatable)")
+	cmd.Flags().StringArrayVar(&f.rerun, "rerun", nil, "Handoff: a command the successor should rerun (repeatable)")
+	cmd.Flags().StringArrayVar(&f.touched, "touched", nil, "Handoff: a file touched, in addition to what git reports (repeatable)")
+	cmd.Flags().BoolVar(&f.strict, "strict", false, "Refuse to hand off if the structured handoff is incomplete")
+}
+
+// buildHandoffDoc assembles a handoff document from --doc and the field
+// flags, then fills in the workspace state from workDir.
+func buildHandoffDoc(workDir, agent string, f *handoffDocFlags) (*handoff.Doc, error) {
+	doc := &handoff.Doc{}
+	if f.file != "" {
+		data, err := os.ReadFile(f.file)
+		if err != nil {
+			return nil, fmt.Errorf("reading handoff doc: %w", err)
+		}
+		if err := json.Unmarshal(data, doc); err != nil {
+			return nil, fmt.Errorf("parsing handoff doc %s: %w", f.file, err)
+		}
+	}
+	if f.goal != "" {
+		doc.Goal = f.goal
+	}
+	doc.Done = append(doc.Done, f.done...)
+	doc.Remaining = append(doc.Remaining, f.remaining...)
+	doc.Blockers = append(doc.Blockers, f.blockers...)
+	doc.Decisions = append(doc.Decisions, f.decisions...)
+	doc.Commands = append(doc.Commands, f.rerun...)
+	doc.FilesTouched = append(doc.FilesTouched, f.touched...)
+	if doc.Agent == "" {
+		doc.Agent = agent
+	}
+	doc.Fill(workDir)
+	return doc, nil
+}
+
+// prepareHandoffDoc builds, validates and records the structured handoff
+// for the session in workDir. Incomplete handoffs are recorded as such and
+// warned about; with --strict they stop the handoff before it starts.
+// Nothing is written in dry-run mode.
+func prepareHandoffDoc(workDir, agent string, f *handoffDocFlags, dryRun bool) (*handoff.Doc, error) {
+	doc, err := buildHandoffDoc(workDir, agent, f)
+	if err != nil {
+		return nil, err
+	}
+	problems := doc.Problems()
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%s structured handoff is incomplete:\n", ui.IconWarn)
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "%s   %s\n", ui.IconWarn, p)
+		}
+		fmt.Fprintln(os.Stderr, "  (fill in with --goal, --done, --remaining, --blocker, --decision, --rerun)")
+		if f.strict {
+			return nil, doc.Validate()
+		}
+	}
+	if dryRun {
+		return doc, nil
+	}
+	if _, err := handoff.Save(workDir, doc); err != nil {
+		style.PrintWarning("could not record handoff: %v", err)
+	}
+	return doc, nil
+}
+
+// sessionHandoffDoc returns the structured handoff for the caller's own
+// session, or nil when args name another role's session: the caller's
+// workspace is not theirs. With record set it goes through
+// prepareHandoffDoc; otherwise it is only built.
+func sessionHandoffDoc(args []string, record bool) (*handoff.Doc, error) {
+	if len(args) > 0 && !looksLikeBeadID(args[0]) {
+		return nil, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	if !record {
+		return buildHandoffDoc(cwd, detectSender(), &handoffDocOpts)
+	}
+	return prepareHandoffDoc(cwd, detectSender(), &handoffDocOpts, handoffDryRun)
+}
+
+// recordPolecatHandoffDoc completes a polecat's structured handoff from its
+// work and records it in workDir. Polecats hand off through the PreCompact
+// cycle and gt done, where nobody supplies a goal, so the issue stands in:
+// its title is the goal, and exitType (empty for a cycle, where the work
+// carries on) says what was done and what remains. Fields the agent set
+// are kept.
+func recordPolecatHandoffDoc(workDir string, doc *handoff.Doc, issueID, exitType string) {
+	if issueID == "" {
+		issueID = doc.State.HookedBead
+	}
+	if issueID != "" {
+		if doc.Goal == "" {
+			doc.Goal = "Work on " + issueID
+			if issue, err := beads.New(workDir).Show(issueID); err == nil && issue.Title != "" {
+				doc.Goal = issueID + ": " + issue.Title
+			}
+		}
+		if len(doc.Done) == 0 && len(doc.Remaining) == 0 {
+			switch exitType {
+			case ExitCompleted:
+				submitted := doc.State.Branch
+				if submitted == "" {
+					submitted = issueID
+				}
+				doc.Done = []string{"Submitted " + submitted + " for merge"}
+			case ExitEscalated:
+				doc.Blockers = append(doc.Blockers, "Escalated; waiting on a decision")
+				doc.Remaining = []string{"Resume " + issueID + " once the escalation is resolved"}
+			default:
+				if doc.State.Step != "" {
+					doc.Remaining = append(doc.Remaining, "Continue step: "+doc.State.Step)
+				}
+				doc.Remaining = append(doc.Remaining, "Finish "+issueID+" and run gt done")
+			}
+		}
+	}
+	if _, err := handoff.Save(workDir, doc); err != nil {
+		style.PrintWarning("could not record handoff: %v", err)
+	}
+}
+
+// withHandoffDoc puts the structured handoff at the top of a handoff mail
+// body, keeping any free-form message below it.
+func withHandoffDoc(message string, doc *handoff.Doc) string {
+	if doc == nil {
+		return message
+	}
+	if message == "" {
+		return doc.MailBody()
+	}
+	return doc.MailBody() + "\n\n---\n" + message
+}
+
+// outputStructuredHandoff renders the workspace's latest structured handoff
+// for the successor session.
+func outputStructuredHandoff(ctx RoleContext) {
+	if ctx.Role == RoleUnknown {
+		return
+	}
+	rec, err := handoff.Load(ctx.WorkDir)
+	if err != nil || rec == nil {
+		return
+	}
+	age := time.Since(rec.Doc.CreatedAt)
+	if age > handoffDocStaleAfter {
+		return
+	}
+	body := rec.Doc.Markdown()
+	if body == "" {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## 🧾 Structured Handoff"))
+	from := rec.Doc.Agent
+	if from == "" {
+		from = "your predecessor"
+	}
+	fmt.Printf("Written %s ago by %s.\n\n", age.Round(time.Minute), from)
+	fmt.Println(body)
+	if !rec.Valid {
+		fmt.Println()
+		fmt.Println(style.Dim.Render("(Incomplete handoff: " + strings.Join(rec.Problems, "; ") + ")"))
+	}
+	fmt.Println()
+}
+
+var (
+	handoffComplianceRig  string
+	handoffComplianceJSON bool
+)
+
+var handoffComplianceCmd = &cobra.Command{
+	Use:   "compliance",
+	Short: "Show structured handoff compliance per polecat",
+	Long: `Show each polecat's latest structured handoff and whether it validated.
+
+Polecats record a handoff on every PreCompact cycle and on gt done, with
+the hooked issue as the goal and the exit status saying what was done or
+remains. A handoff is compliant when it names a goal and what remains (or
+what was done, if the work is finished). Polecats that have never handed
+off are listed as "none".
+
+Examples:
+  gt handoff compliance               # All rigs
+  gt handoff compliance --rig gastown # One rig
+  gt handoff compliance --json`,
+	RunE: runHandoffCompliance,
+}
+
+func init() {
+	addHandoffDocFlags(handoffCmd, &handoffDocOpts)
+
+	handoffComplianceCmd.Flags().StringVar(&handoffComplianceRig, "rig", "", "Only show polecats in this rig")
+	handoffComplianceCmd.Flags().BoolVar(&handoffComplianceJSON, "json", false, "Output as JSON")
+	handoffCmd.AddCommand(handoffComplianceCmd)
+}
+
+// HandoffComplianceItem is one polecat's handoff compliance.
+type HandoffComplianceItem struct {
+	Rig      string     `json:"rig"`
+	Polecat  string     `json:"polecat"`
+	Status   string     `json:"status"` // "valid", "incomplete", or "none"
+	At       *time.Time `json:"at,omitempty"`
+	Problems []string   `json:"problems,omitempty"`
+}
+
+func runHandoffCompliance(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	if handoffComplianceRig != "" {
+		_, r, err := getRig(handoffComplianceRig)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	} else {
+		var err error
+		if rigs, err = getAllRigs(); err != nil {
+			return err
+		}
+	}
+
+	var items []HandoffComplianceItem
+	t := tmux.NewTmux()
+	for _, r := range rigs {
+		names, err := listPolecatDirectoryNames(r.Path)
+		if err != nil {
+			continue
+		}
+		mgr := polecat.NewManager(r, nil, t)
+		for _, name := range names {
+			items = append(items, handoffComplianceFor(r.Name, name, mgr.ClonePath(name)))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Rig != items[j].Rig {
+			return items[i].Rig < items[j].Rig
+		}
+		return items[i].Polecat < items[j].Polecat
+	})
+
+	if handoffComplianceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No polecats found.")
+		return nil
+	}
+	valid := 0
+	for _, it := range items {
+		when := ""
+		if it.At != nil {
+			when = style.Dim.Render(it.At.Format("2006-01-02 15:04"))
+		}
+		switch it.Status {
+		case "valid":
+			valid++
+			fmt.Printf("  %s %s/%s  %s\n", style.Success.Render("✓"), it.Rig, it.Polecat, when)
+		case "incomplete":
+			fmt.Printf("  %s %s/%s  %s\n", style.Warning.Render("⚠"), it.Rig, it.Polecat, when)
+			for _, p := range it.Problems {
+				fmt.Printf("      %s\n", p)
+			}
+		default:
+			fmt.Printf("  %s %s/%s  %s\n", style.Dim.Render("○"), it.Rig, it.Polecat, style.Dim.Render("no structured handoff"))
+		}
+	}
+	fmt.Printf("\n%d/%d polecat(s) with a valid structured handoff\n", valid, len(items))
+	return nil
+}
+
+// handoffComplianceFor reports the handoff compliance of one workspace.
+func handoffComplianceFor(rigName, name, workDir string) HandoffComplianceItem {
+	item := HandoffComplianceItem{Rig: rigName, Polecat: name, Status: "none"}
+	rec, err := handoff.Load(workDir)
+	if err != nil {
+		item.Status = "incomplete"
+		item.Problems = []string{err.Error()}
+		return item
+	}
+	if rec == nil {
+		return item
+	}
+	at := rec.Doc.CreatedAt
+	item.At = &at
+	if rec.Valid {
+		item.Status = "valid"
+	} else {
+		item.Status = "incomplete"
+		item.Problems = rec.Problems
+	}
+	return item
+}
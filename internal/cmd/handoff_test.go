@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -8,8 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/handoff"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -970,3 +973,162 @@ func TestEnforceHandoffCooldown(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildHandoffDocMergesFileAndFlags(t *testing.T) {
+	dir := t.TempDir()
+	docFile := filepath.Join(dir, "handoff.json")
+	if err := os.WriteFile(docFile, []byte(`{"goal":"from file","remaining":["a"],"decisions":["keep API"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := &handoffDocFlags{file: docFile, remaining: []string{"b"}, rerun: []string{"go test ./..."}}
+	doc, err := buildHandoffDoc(dir, "gastown/crew/dave", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Goal != "from file" || strings.Join(doc.Remaining, ",") != "a,b" || doc.Agent != "gastown/crew/dave" {
+		t.Errorf("doc = %+v", doc)
+	}
+
+	msg := withHandoffDoc("free text", doc)
+	if !strings.HasPrefix(msg, "**Goal:** from file") || !strings.HasSuffix(msg, "---\nfree text") {
+		t.Errorf("withHandoffDoc = %q", msg)
+	}
+	if got := withHandoffDoc("free text", nil); got != "free text" {
+		t.Errorf("withHandoffDoc(nil) = %q", got)
+	}
+}
+
+func TestPrepareHandoffDocStrict(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := prepareHandoffDoc(dir, "x", &handoffDocFlags{strict: true}, false); err == nil {
+		t.Fatal("strict empty handoff should fail")
+	}
+	if got := handoffComplianceFor("gastown", "toast", dir); got.Status != "none" {
+		t.Errorf("status after refused handoff = %q, want none", got.Status)
+	}
+
+	if _, err := prepareHandoffDoc(dir, "x", &handoffDocFlags{goal: "g"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := handoffComplianceFor("gastown", "toast", dir); got.Status != "incomplete" || got.At == nil {
+		t.Errorf("compliance = %+v, want incomplete", got)
+	}
+
+	if _, err := prepareHandoffDoc(dir, "x", &handoffDocFlags{goal: "g", done: []string{"d"}, strict: true}, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := handoffComplianceFor("gastown", "toast", dir); got.Status != "valid" {
+		t.Errorf("compliance = %+v, want valid", got)
+	}
+}
+
+func TestSessionHandoffDocHookDrivenNotRecorded(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	orig := handoffDocOpts
+	t.Cleanup(func() { handoffDocOpts = orig })
+	handoffDocOpts = handoffDocFlags{goal: "g"}
+
+	doc, err := sessionHandoffDoc(nil, false)
+	if err != nil || doc == nil || doc.Goal != "g" {
+		t.Fatalf("sessionHandoffDoc(hook) = %+v, %v", doc, err)
+	}
+	if got := handoffComplianceFor("gastown", "toast", dir); got.Status != "none" {
+		t.Errorf("status after hook-driven handoff = %q, want none", got.Status)
+	}
+
+	if doc, err := sessionHandoffDoc([]string{"mayor"}, true); err != nil || doc != nil {
+		t.Errorf("sessionHandoffDoc(other role) = %+v, %v, want nil", doc, err)
+	}
+
+	if _, err := sessionHandoffDoc(nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := handoffComplianceFor("gastown", "toast", dir); got.Status != "incomplete" {
+		t.Errorf("status after manual handoff = %q, want incomplete", got.Status)
+	}
+}
+
+func TestPolecatCycleHandoffShowsInCompliance(t *testing.T) {
+	townRoot := setupTestTownForCrewList(t, map[string][]string{"gastown": nil})
+	worktree := filepath.Join(townRoot, "gastown", "polecats", "toast", "gastown")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats", "nux"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, worktree, "init", "-q", "-b", "polecat/toast/gt-abc")
+	runGit(t, worktree, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init")
+	if err := checkpoint.Write(worktree, (&checkpoint.Checkpoint{}).WithHookedBead("gt-abc")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir(worktree)
+	t.Setenv("GT_ROLE", "gastown/polecats/toast")
+	t.Setenv("GT_POLECAT", "toast")
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_PANE", "")
+	origCycle, origDocOpts := handoffCycle, handoffDocOpts
+	t.Cleanup(func() { handoffCycle, handoffDocOpts = origCycle, origDocOpts })
+	handoffCycle = true
+	handoffDocOpts = handoffDocFlags{}
+
+	// The PreCompact hook runs "gt handoff --cycle" with no structured fields.
+	captureStdout(t, func() {
+		if err := runHandoff(handoffCmd, nil); err != nil {
+			t.Errorf("runHandoff --cycle: %v", err)
+		}
+	})
+
+	origJSON := handoffComplianceJSON
+	t.Cleanup(func() { handoffComplianceJSON = origJSON })
+	handoffComplianceJSON = true
+	out := captureStdout(t, func() {
+		if err := runHandoffCompliance(handoffComplianceCmd, nil); err != nil {
+			t.Errorf("runHandoffCompliance: %v", err)
+		}
+	})
+	var items []HandoffComplianceItem
+	if err := json.Unmarshal([]byte(out), &items); err != nil {
+		t.Fatalf("compliance output %q: %v", out, err)
+	}
+	got := map[string]string{}
+	for _, it := range items {
+		got[it.Rig+"/"+it.Polecat] = it.Status
+	}
+	if got["gastown/toast"] != "valid" || got["gastown/nux"] != "none" {
+		t.Errorf("compliance = %+v, want toast valid and nux none", items)
+	}
+}
+
+func TestRecordPolecatHandoffDocFromExit(t *testing.T) {
+	tests := []struct {
+		exitType, want string
+	}{
+		{ExitCompleted, "valid"},
+		{ExitDeferred, "valid"},
+		{ExitEscalated, "valid"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		doc := &handoff.Doc{State: handoff.State{Branch: "polecat/toast/gt-abc"}}
+		recordPolecatHandoffDoc(dir, doc, "gt-abc", tt.exitType)
+		if got := handoffComplianceFor("gastown", "toast", dir); got.Status != tt.want {
+			t.Errorf("%s: compliance = %+v, want %s", tt.exitType, got, tt.want)
+		}
+		if tt.exitType == ExitCompleted && (len(doc.Done) != 1 || len(doc.Remaining) != 0) {
+			t.Errorf("completed doc = %+v, want done only", doc)
+		}
+		if tt.exitType == ExitEscalated && len(doc.Blockers) == 0 {
+			t.Errorf("escalated doc = %+v, want a blocker", doc)
+		}
+	}
+
+	// Without an issue there is nothing to stand in for the goal.
+	dir := t.TempDir()
+	recordPolecatHandoffDoc(dir, &handoff.Doc{}, "", ExitDeferred)
+	if got := handoffComplianceFor("gastown", "toast", dir); got.Status != "incomplete" {
+		t.Errorf("no issue: compliance = %+v, want incomplete", got)
+	}
+}
@@ -88,3 +88,13 @@ func execRuntime(prompt, rigPath, configDir string) error {
 
 	return syscall.Exec(binPath, args, env)
 }
+
+// execCommand execs an arbitrary command, replacing the current process.
+// Used by exec wrappers (gt cgroup exec) once they have set up the process.
+func execCommand(args []string) error {
+	binPath, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("%s not found: %w", args[0], err)
+	}
+	return syscall.Exec(binPath, args, os.Environ())
+}
@@ -115,3 +115,27 @@ func execRuntime(prompt, rigPath, configDir string) error {
 	os.Exit(0)
 	return nil // unreachable
 }
+
+// execCommand runs an arbitrary command and exits with its status.
+// Uses os/exec.Command with stdio passthrough since syscall.Exec is Unix-only.
+func execCommand(args []string) error {
+	binPath, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("%s not found: %w", args[0], err)
+	}
+
+	cmd := exec.Command(binPath, args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+	os.Exit(0)
+	return nil // unreachable
+}
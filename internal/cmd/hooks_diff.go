@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/charmbracelet/lipgloss"
@@ -20,6 +22,11 @@ Compares the current .claude/settings.json files against what would
 be generated from base + overrides. Uses color to highlight additions
 and removals.
 
+Other agents are compared too: Gemini, Cursor, Copilot and Codex hook
+files against base + overrides rendered in their native format, plugin
+files (OpenCode, Pi, OMP) against their template. A target may be an
+override key (gastown/crew), which matches every agent file for it.
+
 Exit codes:
   0 - No changes pending
   1 - Changes would be applied
@@ -51,6 +58,11 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("discovering targets: %w", err)
 	}
 
+	agentTargets, err := hooks.DiscoverAgentTargets(townRoot)
+	if err != nil {
+		return fmt.Errorf("discovering agent targets: %w", err)
+	}
+
 	// Filter to specific target if provided
 	if len(args) > 0 {
 		filter := args[0]
@@ -60,10 +72,16 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 				filtered = append(filtered, t)
 			}
 		}
-		if len(filtered) == 0 {
+		var filteredAgents []hooks.AgentTarget
+		for _, t := range agentTargets {
+			if t.Key == filter || t.DisplayKey() == filter {
+				filteredAgents = append(filteredAgents, t)
+			}
+		}
+		if len(filtered) == 0 && len(filteredAgents) == 0 {
 			return fmt.Errorf("no targets match %q", filter)
 		}
-		targets = filtered
+		targets, agentTargets = filtered, filteredAgents
 	}
 
 	hasChanges := false
@@ -102,6 +120,28 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	for _, target := range agentTargets {
+		changes, err := diffAgentTarget(target)
+		if err != nil {
+			return fmt.Errorf("computing expected hooks for %s: %w", target.DisplayKey(), err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		relPath, err := filepath.Rel(townRoot, target.Path)
+		if err != nil {
+			relPath = target.Path
+		}
+
+		hasChanges = true
+		fmt.Printf("%s %s:\n", style.Bold.Render(relPath), style.Dim.Render("("+target.Provider+")"))
+		for _, change := range changes {
+			fmt.Print(change)
+		}
+		fmt.Println()
+	}
+
 	if !hasChanges {
 		fmt.Println(style.Dim.Render("No changes pending - all targets in sync"))
 		return nil
@@ -230,6 +270,69 @@ func diffCommands(hookType, matcher string, current, expected hooks.HookEntry) [
 	return lines
 }
 
+// diffAgentTarget compares a non-Claude agent's hook file against what sync
+// would write. Translated files are compared hook by hook; plugin files
+// only report that they differ.
+func diffAgentTarget(target hooks.AgentTarget) ([]string, error) {
+	expected, err := target.Expected()
+	if err != nil {
+		return nil, err
+	}
+	current, readErr := os.ReadFile(target.Path)
+	if readErr == nil {
+		if hooks.TemplateContentEqual(expected, current) || bytes.Equal(expected, current) {
+			return nil, nil
+		}
+	}
+
+	if !target.Translated() {
+		if readErr != nil {
+			return []string{fmt.Sprintf("  %s\n", diffAdd.Render("+ missing, would create from template"))}, nil
+		}
+		return []string{fmt.Sprintf("  %s\n", diffRemove.Render("~ differs from template, would overwrite"))}, nil
+	}
+
+	want, err := hooks.NativeHooks(expected)
+	if err != nil {
+		return nil, err
+	}
+	have, err := hooks.NativeHooks(current)
+	if err != nil {
+		return []string{fmt.Sprintf("  %s\n", diffRemove.Render(fmt.Sprintf("~ unreadable (%v), would overwrite", err)))}, nil
+	}
+
+	var lines []string
+	if readErr != nil {
+		lines = append(lines, fmt.Sprintf("  %s\n", diffAdd.Render("+ missing, would create")))
+	}
+	wantSet := make(map[hooks.NativeHook]bool, len(want))
+	for _, h := range want {
+		wantSet[h] = true
+	}
+	haveSet := make(map[hooks.NativeHook]bool, len(have))
+	for _, h := range have {
+		haveSet[h] = true
+		if !wantSet[h] {
+			lines = append(lines, fmt.Sprintf("  %s\n", diffRemove.Render("- "+nativeHookDisplay(h))))
+		}
+	}
+	for _, h := range want {
+		if !haveSet[h] {
+			lines = append(lines, fmt.Sprintf("  %s\n", diffAdd.Render("+ "+nativeHookDisplay(h))))
+		}
+	}
+	if len(lines) == 0 {
+		// Same hooks; only order or a managed field like "version" differs.
+		lines = append(lines, fmt.Sprintf("  %s\n", diffRemove.Render("~ hook layout differs, would rewrite")))
+	}
+	return lines, nil
+}
+
+func nativeHookDisplay(h hooks.NativeHook) string {
+	h.Command = truncateCommand(h.Command)
+	return h.String()
+}
+
 // indexByMatcher builds a map from matcher string to HookEntry.
 func indexByMatcher(entries []hooks.HookEntry) map[string]hooks.HookEntry {
 	m := make(map[string]hooks.HookEntry)
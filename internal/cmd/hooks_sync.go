@@ -2,12 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -27,10 +25,14 @@ For Claude agents (settings.json merge):
 4. Merge hooks section into existing settings.json (preserving all fields)
 5. Write updated settings.json
 
-For template-based agents (OpenCode, Gemini, Copilot, etc.):
-1. Resolve the agent configured for each role
-2. Compare deployed hook file against current template
-3. Overwrite if content differs
+For other agents, per role (and per worktree, for agents without a
+settings directory):
+1. Resolve the agent configured for the role
+2. Gemini, Cursor, Copilot, Codex: render the same base + overrides into the
+   agent's native hook format, keeping the file's non-hook settings. Tool
+   hooks are wrapped in 'gt tap when' to apply Claude-style matchers.
+3. OpenCode, Pi, OMP: use the agent's plugin template
+4. Overwrite if content differs
 
 Examples:
   gt hooks sync             # Regenerate all hook/settings files
@@ -114,97 +116,46 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Sync template-based (non-Claude) agents at each role location.
-	// These agents use SyncForRole (content-aware comparison) instead of the
-	// JSON merge path used for Claude targets above.
-	locations, locErr := hooks.DiscoverRoleLocations(townRoot)
-	if locErr != nil {
-		fmt.Printf("  %s discovering role locations: %v\n", style.Error.Render("✖"), locErr)
+	// Sync non-Claude agents at each role location. Their files are rendered
+	// whole (translated from the same managed config, or from a template)
+	// rather than merged like Claude's settings.json above.
+	agentTargets, discErr := hooks.DiscoverAgentTargets(townRoot)
+	if discErr != nil {
+		fmt.Printf("  %s discovering agent targets: %v\n", style.Error.Render("✖"), discErr)
 		errors++
-	} else {
-		for _, loc := range locations {
-			rigPath := ""
-			if loc.Rig != "" {
-				rigPath = filepath.Join(townRoot, loc.Rig)
-			}
-
-			// Use ResolveRoleAgentName (not ResolveRoleAgentConfig) so that hooks are
-			// installed based on the *configured* agent, not the *resolved* one.
-			// ResolveRoleAgentConfig falls back to claude when the agent binary is not
-			// found in PATH (e.g., in CI or on a fresh machine), which would silently
-			// skip creating opencode/gemini/etc. plugin files.
-			agentName, _ := config.ResolveRoleAgentName(loc.Role, townRoot, rigPath)
-			if agentName == "" {
-				continue
-			}
-
-			preset := config.GetAgentPresetByName(agentName)
-			if preset == nil || preset.HooksDir == "" || preset.HooksSettingsFile == "" {
-				continue
-			}
-
-			hooksProvider := preset.HooksProvider
-			if hooksProvider == "" {
-				hooksProvider = agentName
-			}
-
-			// Claude targets are already handled by DiscoverTargets + syncTarget above.
-			if hooksProvider == "claude" {
-				continue
-			}
+	}
+	for _, target := range agentTargets {
+		relPath, pathErr := filepath.Rel(townRoot, target.Path)
+		if pathErr != nil {
+			relPath = target.Path
+		}
 
-			useSettingsDir := preset.HooksUseSettingsDir
+		result, syncErr := hooks.SyncAgentTarget(target, hooksSyncDryRun)
+		if syncErr != nil {
+			fmt.Printf("  %s %s (%s): %v\n", style.Error.Render("✖"), relPath, target.Provider, syncErr)
+			errors++
+			failedTargets = append(failedTargets, relPath)
+			continue
+		}
 
-			// Determine sync targets.
-			// - Town-level roles (mayor, deacon): the role dir IS the working directory.
-			// - Rig roles with useSettingsDir: one shared file in the role parent.
-			// - Rig roles without useSettingsDir (OpenCode, etc.): need files in each
-			//   individual worktree subdirectory.
-			var syncDirs []string
-			if loc.Rig == "" || useSettingsDir {
-				syncDirs = []string{loc.Dir}
+		switch result {
+		case hooks.SyncCreated:
+			if hooksSyncDryRun {
+				fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would create "+target.Provider+")"))
 			} else {
-				syncDirs = hooks.DiscoverWorktrees(loc.Dir)
+				fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(created "+target.Provider+")"))
 			}
-
-			for _, dir := range syncDirs {
-				targetPath := filepath.Join(dir, preset.HooksDir, preset.HooksSettingsFile)
-				relPath, pathErr := filepath.Rel(townRoot, targetPath)
-				if pathErr != nil {
-					relPath = targetPath
-				}
-
-				if hooksSyncDryRun {
-					if _, statErr := os.Stat(targetPath); statErr == nil {
-						fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would check "+hooksProvider+")"))
-					} else {
-						fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would create "+hooksProvider+")"))
-						created++
-					}
-					continue
-				}
-
-				result, syncErr := hooks.SyncForRole(hooksProvider, dir, dir, loc.Role,
-					preset.HooksDir, preset.HooksSettingsFile, useSettingsDir)
-				if syncErr != nil {
-					fmt.Printf("  %s %s (%s): %v\n", style.Error.Render("✖"), relPath, hooksProvider, syncErr)
-					errors++
-					failedTargets = append(failedTargets, relPath)
-					continue
-				}
-
-				switch result {
-				case hooks.SyncCreated:
-					fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(created "+hooksProvider+")"))
-					created++
-				case hooks.SyncUpdated:
-					fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(updated "+hooksProvider+")"))
-					updated++
-				case hooks.SyncUnchanged:
-					fmt.Printf("  %s %s %s\n", style.Dim.Render("·"), relPath, style.Dim.Render("(unchanged "+hooksProvider+")"))
-					unchanged++
-				}
+			created++
+		case hooks.SyncUpdated:
+			if hooksSyncDryRun {
+				fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would update "+target.Provider+")"))
+			} else {
+				fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(updated "+target.Provider+")"))
 			}
+			updated++
+		case hooks.SyncUnchanged:
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("·"), relPath, style.Dim.Render("(unchanged "+target.Provider+")"))
+			unchanged++
 		}
 	}
 
@@ -16,9 +16,9 @@ var (
 	mailType          string
 	mailReplyTo       string
 	mailNotify        bool
-	mailNoNotify      bool // Suppress auto-nudge notification to recipient
-	mailTo            string   // --to flag (alternative to positional arg)
-	mailFrom          string   // --from flag (override sender, for relay/bridge use)
+	mailNoNotify      bool   // Suppress auto-nudge notification to recipient
+	mailTo            string // --to flag (alternative to positional arg)
+	mailFrom          string // --from flag (override sender, for relay/bridge use)
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
 	mailInboxJSON     bool
@@ -32,14 +32,21 @@ var (
 	mailThreadJSON    bool
 	mailReplySubject  string
 	mailReplyMessage  string
-	mailStdin         bool // Read message body from stdin
+	mailStdin         bool   // Read message body from stdin
+	mailSendAt        string // --at: deliver at a specific time
+	mailSendIn        string // --in: deliver after a delay
+	mailThread        string // --thread: continue an existing thread (relay/bridge use)
+	mailRelayedFrom   string // --relayed-from: message came in from external chat (bridge use)
 
 	// Search flags
 	mailSearchFrom    string
 	mailSearchSubject bool
 	mailSearchBody    bool
-	mailSearchArchive bool
+	mailSearchArchive bool // deprecated: archived mail is always indexed
 	mailSearchJSON    bool
+	mailSearchTown    bool
+	mailSearchReindex bool
+	mailSearchLimit   int
 
 	// Announces flags
 	mailAnnouncesJSON bool
@@ -126,6 +133,9 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Use --at or --in to hold the message for later delivery. Scheduled mail is
+released by the daemon heartbeat; see 'gt mail scheduled'.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -136,6 +146,8 @@ Examples:
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
   gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send --self -s "Check MR" -m "Re-check gt-abc merge" --in 2h
+  gt mail send mayor/ -s "Standup" -m "Daily summary" --at "2026-01-02 09:00"
 
   # Read body from stdin (avoids shell quoting issues):
   gt mail send mayor/ -s "Update" --stdin <<'BODY'
@@ -399,28 +411,47 @@ Examples:
 var mailSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search messages by content",
-	Long: `Search inbox for messages matching a pattern.
+	Long: `Search mail using the full-text search index.
 
 SYNTAX:
   gt mail search <query> [flags]
 
-The query is a regular expression pattern. Search is case-insensitive by default.
+The index lives next to the town beads (.beads/mail-index.json, with recent
+updates in mail-index.jsonl) and covers inbox and archived messages for every
+mailbox. It is updated as mail is sent, read and archived, and built
+automatically on first use.
+
+QUERY SYNTAX (all parts must match, case-insensitive):
+  word              Message contains the word
+  word*             Message contains a word starting with "word"
+  "exact phrase"    Message contains the phrase
+  from:<addr>       Sender contains <addr>
+  to:<addr>         Recipient contains <addr>
+  thread:<id>       Message belongs to thread <id>
+  subject:<word>    Word (or "phrase") appears in the subject
+  after:<date>      Sent at or after <date>
+  before:<date>     Sent before <date>
+  date:<a>..<b>     Sent within a date range
+  is:unread|read|archived
+
+Dates are YYYY-MM-DD, RFC3339, or an age such as 2h or 7d.
 
 FLAGS:
   --from <sender>   Filter by sender address (substring match)
   --subject         Only search subject lines
   --body            Only search message body
-  --archive         Include archived (closed) messages
+  --town            Search every mailbox in the town, not just yours
+  --reindex         Rebuild the search index before searching
+  --limit <n>       Maximum number of results (0 = unlimited)
   --json            Output as JSON
 
-By default, searches both subject and body text.
-
 Examples:
-  gt mail search "urgent"                    # Find messages with "urgent"
-  gt mail search "status.*check" --subject   # Regex in subjects only
-  gt mail search "error" --from witness      # From witness, containing "error"
-  gt mail search "handoff" --archive         # Include archived messages
-  gt mail search "" --from mayor/            # All messages from mayor`,
+  gt mail search urgent                           # Messages containing "urgent"
+  gt mail search '"merge failed"' --subject       # Phrase in subjects only
+  gt mail search 'error from:witness after:1d'    # Witness errors from the last day
+  gt mail search 'thread:hq-abc123' --town        # Whole thread across mailboxes
+  gt mail search 'date:2026-01-01..2026-02-01 deploy*' --town
+  gt mail search "" --from mayor/                 # All messages from mayor`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailSearch,
 }
@@ -475,8 +506,14 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().StringVar(&mailTo, "to", "", "Recipient address (alternative to positional argument)")
 	mailSendCmd.Flags().StringVar(&mailFrom, "from", "", "Override sender address (for relay/bridge use)")
+	mailSendCmd.Flags().StringVar(&mailThread, "thread", "", "Continue an existing thread ID (for relay/bridge use)")
+	mailSendCmd.Flags().StringVar(&mailRelayedFrom, "relayed-from", "", "Mark as relayed in from external chat (for bridge use; skips outbound webhooks)")
+	_ = mailSendCmd.Flags().MarkHidden("relayed-from")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailSendAt, "at", "", "Deliver at a later time (RFC3339, \"YYYY-MM-DD HH:MM\", or HH:MM)")
+	mailSendCmd.Flags().StringVar(&mailSendIn, "in", "", "Deliver after a delay (e.g., 30m, 2h, 1d)")
+	mailSendCmd.MarkFlagsMutuallyExclusive("at", "in")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -508,7 +545,11 @@ func init() {
 	mailSearchCmd.Flags().BoolVar(&mailSearchSubject, "subject", false, "Only search subject lines")
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
 	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
+	_ = mailSearchCmd.Flags().MarkDeprecated("archive", "archived messages are always searched; use is:archived to search only archived mail")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
+	mailSearchCmd.Flags().BoolVar(&mailSearchTown, "town", false, "Search every mailbox in the town")
+	mailSearchCmd.Flags().BoolVar(&mailSearchReindex, "reindex", false, "Rebuild the search index before searching")
+	mailSearchCmd.Flags().IntVar(&mailSearchLimit, "limit", 0, "Maximum number of results (0 = unlimited)")
 
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
@@ -539,6 +580,8 @@ func init() {
 	mailCmd.AddCommand(mailSearchCmd)
 	mailCmd.AddCommand(mailAnnouncesCmd)
 	mailCmd.AddCommand(mailDrainCmd)
+	mailCmd.AddCommand(mailSnoozeCmd)
+	mailCmd.AddCommand(mailScheduledCmd)
 
 	rootCmd.AddCommand(mailCmd)
 }
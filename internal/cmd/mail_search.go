@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -23,26 +24,47 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	// Get mailbox
 	router := mail.NewRouter(workDir)
-	mailbox, err := router.GetMailbox(address)
+
+	// Parse query syntax (from:, to:, thread:, dates, "phrases")
+	q, err := mail.ParseSearchQuery(query)
 	if err != nil {
-		return fmt.Errorf("getting mailbox: %w", err)
+		return fmt.Errorf("parsing query: %w", err)
+	}
+	if mailSearchFrom != "" {
+		q.From = strings.ToLower(mailSearchFrom)
 	}
+	q.SubjectOnly = mailSearchSubject
+	q.BodyOnly = mailSearchBody
 
-	// Build search options
-	opts := mail.SearchOptions{
-		Query:       query,
-		FromFilter:  mailSearchFrom,
-		SubjectOnly: mailSearchSubject,
-		BodyOnly:    mailSearchBody,
+	// Build the index on first use, or when explicitly asked to. Sends
+	// index incrementally, so an index that was never fully built only
+	// holds mail sent since the upgrade.
+	index := router.SearchIndex()
+	if mailSearchReindex || !index.Complete() {
+		n, err := router.RebuildSearchIndex()
+		if err != nil {
+			return fmt.Errorf("building search index: %w", err)
+		}
+		if !mailSearchJSON {
+			fmt.Printf("%s Indexed %d message(s)\n", style.Dim.Render("○"), n)
+		}
+	}
+
+	// Scope to the caller's mailbox unless searching the whole town
+	scope := mail.AddressToIdentity(address)
+	if mailSearchTown {
+		scope = ""
 	}
 
 	// Execute search
-	messages, err := mailbox.Search(opts)
+	messages, err := index.Query(q, scope)
 	if err != nil {
 		return fmt.Errorf("searching messages: %w", err)
 	}
+	if mailSearchLimit > 0 && len(messages) > mailSearchLimit {
+		messages = messages[:mailSearchLimit]
+	}
 
 	// JSON output
 	if mailSearchJSON {
@@ -52,8 +74,12 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
+	target := address
+	if mailSearchTown {
+		target = "town"
+	}
 	fmt.Printf("%s Search results for %s: %d message(s)\n\n",
-		style.Bold.Render("🔍"), address, len(messages))
+		style.Bold.Render("🔍"), target, len(messages))
 
 	if len(messages) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
@@ -79,9 +105,15 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
-		fmt.Printf("    %s from %s\n",
-			style.Dim.Render(msg.ID),
-			msg.From)
+		if mailSearchTown {
+			fmt.Printf("    %s from %s to %s\n",
+				style.Dim.Render(msg.ID),
+				msg.From, msg.To)
+		} else {
+			fmt.Printf("    %s from %s\n",
+				style.Dim.Render(msg.ID),
+				msg.From)
+		}
 		fmt.Printf("    %s\n",
 			style.Dim.Render(msg.Timestamp.Local().Format("2006-01-02 15:04")))
 	}
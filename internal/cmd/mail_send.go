@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -114,12 +115,22 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Hold for later delivery when --at/--in is given
+	if mailSendAt != "" || mailSendIn != "" {
+		notBefore, err := parseSendDeliveryTime(mailSendAt, mailSendIn, time.Now())
+		if err != nil {
+			return err
+		}
+		msg.NotBefore = notBefore
+	}
+
 	// Suppress router-side notification when --no-notify is passed.
 	// Otherwise the router handles idle-aware notification per-recipient,
 	// which also works correctly for fan-out (groups, lists, channels).
 	if mailNoNotify {
 		msg.SuppressNotify = true
 	}
+	msg.RelayedFrom = mailRelayedFrom
 
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
@@ -145,6 +156,12 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// An explicit --thread wins over reply-to lookup (bridges know the thread
+	// but the original message is not in the relay sender's inbox)
+	if mailThread != "" {
+		msg.ThreadID = mailThread
+	}
+
 	// Generate thread ID for new threads
 	if msg.ThreadID == "" {
 		msg.ThreadID = generateThreadID()
@@ -170,7 +187,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("sending message: %w", err)
 		}
 		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
-		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
+		printMailSent(to, msg)
 		fmt.Printf("  Subject: %s\n", mailSubject)
 		return nil
 	}
@@ -229,7 +246,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Log mail event to activity feed
 	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
 
-	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
+	printMailSent(to, msg)
 	fmt.Printf("  Subject: %s\n", mailSubject)
 
 	// Show resolved recipients if fan-out occurred
@@ -247,6 +264,35 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// parseSendDeliveryTime resolves --at/--in into a delivery time.
+func parseSendDeliveryTime(at, in string, now time.Time) (time.Time, error) {
+	if in != "" {
+		d, err := mail.ParseDeliveryDelay(in)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("--in: %w", err)
+		}
+		return now.Add(d), nil
+	}
+	t, err := mail.ParseDeliveryTime(at, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--at: %w", err)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("--at: %s is in the past", t.Format(time.RFC3339))
+	}
+	return t, nil
+}
+
+// printMailSent prints the send confirmation line, noting scheduled delivery.
+func printMailSent(to string, msg *mail.Message) {
+	if msg.NotBefore.After(time.Now()) {
+		fmt.Printf("%s Message to %s scheduled for %s\n", style.Bold.Render("✓"), to,
+			msg.NotBefore.Local().Format("2006-01-02 15:04 MST"))
+		return
+	}
+	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	mailSnoozeUntil    string
+	mailSnoozeIdentity string
+
+	mailScheduledJSON   bool
+	mailScheduledCancel string
+)
+
+var mailSnoozeCmd = &cobra.Command{
+	Use:   "snooze <message-id>",
+	Short: "Hide a message until later",
+	Long: `Hide an inbox message until a later time.
+
+The message is removed from the inbox now and re-delivered (same subject,
+body and thread, marked unread) when the snooze expires. Snoozed messages
+are released by the daemon heartbeat.
+
+--until accepts RFC3339, "YYYY-MM-DD HH:MM", a clock time (HH:MM, today or
+tomorrow), or a duration from now (30m, 2h, 1d).
+
+Examples:
+  gt mail snooze hq-abc123 --until 2h
+  gt mail snooze hq-abc123 --until 09:00
+  gt mail snooze hq-abc123 --until "2026-01-02 14:30"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailSnooze,
+}
+
+var mailScheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List or cancel scheduled and snoozed mail",
+	Long: `List pending scheduled deliveries (gt mail send --at/--in) and snoozed
+messages, soonest first.
+
+Use --cancel to drop a scheduled send. Cancelling a snooze delivers the
+message immediately instead of dropping it.
+
+Examples:
+  gt mail scheduled
+  gt mail scheduled --json
+  gt mail scheduled --cancel sched-abc123`,
+	Args: cobra.NoArgs,
+	RunE: runMailScheduled,
+}
+
+func init() {
+	mailSnoozeCmd.Flags().StringVar(&mailSnoozeUntil, "until", "", "When to bring the message back (time or duration)")
+	mailSnoozeCmd.Flags().StringVar(&mailSnoozeIdentity, "identity", "", "Explicit identity for inbox (e.g., greenplace/Toast)")
+	_ = mailSnoozeCmd.MarkFlagRequired("until")
+
+	mailScheduledCmd.Flags().BoolVar(&mailScheduledJSON, "json", false, "Output as JSON")
+	mailScheduledCmd.Flags().StringVar(&mailScheduledCancel, "cancel", "", "Cancel a scheduled delivery by ID")
+}
+
+func runMailSnooze(cmd *cobra.Command, args []string) error {
+	until, err := mail.ParseDeliveryTime(mailSnoozeUntil, time.Now())
+	if err != nil {
+		return fmt.Errorf("--until: %w", err)
+	}
+
+	address := mailSnoozeIdentity
+	if address == "" {
+		address = detectSender()
+	}
+
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+	mailbox, err := router.GetMailbox(address)
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+
+	sm, err := router.Snooze(mailbox, args[0], until, address)
+	if err != nil {
+		return fmt.Errorf("snoozing %s: %w", args[0], err)
+	}
+
+	fmt.Printf("%s Snoozed %s until %s\n", style.Bold.Render("✓"), args[0],
+		sm.NotBefore.Local().Format("2006-01-02 15:04 MST"))
+	fmt.Printf("  %s\n", style.Dim.Render("Cancel with: gt mail scheduled --cancel "+sm.ID))
+	return nil
+}
+
+func runMailScheduled(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+	defer router.WaitPendingNotifications()
+
+	if mailScheduledCancel != "" {
+		if err := router.CancelScheduled(mailScheduledCancel); err != nil {
+			if errors.Is(err, mail.ErrScheduledNotFound) {
+				return fmt.Errorf("no scheduled delivery %q", mailScheduledCancel)
+			}
+			return fmt.Errorf("cancelling %s: %w", mailScheduledCancel, err)
+		}
+		fmt.Printf("%s Cancelled %s\n", style.Bold.Render("✓"), mailScheduledCancel)
+		return nil
+	}
+
+	pending, err := router.ListScheduled()
+	if err != nil {
+		return fmt.Errorf("listing scheduled mail: %w", err)
+	}
+
+	if mailScheduledJSON {
+		if pending == nil {
+			pending = []*mail.ScheduledMail{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pending)
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("%s No scheduled mail\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Scheduled mail (%d)\n\n", style.Bold.Render("⏰"), len(pending))
+	for _, sm := range pending {
+		fmt.Printf("  %s %s  %s → %s\n", style.Bold.Render(sm.ID),
+			style.Dim.Render("["+sm.Kind+"]"), sm.Message.From, sm.Message.To)
+		fmt.Printf("    %s\n", sm.Message.Subject)
+		fmt.Printf("    %s\n", style.Dim.Render("due "+sm.NotBefore.Local().Format("2006-01-02 15:04 MST")))
+	}
+	return nil
+}
@@ -296,3 +296,13 @@ func TestAnnounceMessageParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestMailSearchArchiveFlagKept(t *testing.T) {
+	flag := mailSearchCmd.Flags().Lookup("archive")
+	if flag == nil {
+		t.Fatal("expected mail search to keep the --archive flag")
+	}
+	if flag.Deprecated == "" {
+		t.Error("expected --archive to be marked deprecated")
+	}
+}
@@ -2,17 +2,27 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/memory"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var memoriesTypeFilter string
+var memoriesExplain bool
+var memoriesBead string
 
 func init() {
 	memoriesCmd.Flags().StringVar(&memoriesTypeFilter, "type", "", "Filter by memory type: feedback, project, user, reference, general")
+	memoriesCmd.Flags().BoolVar(&memoriesExplain, "explain", false, "Show which memories gt prime would inject here, and why")
+	memoriesCmd.Flags().StringVar(&memoriesBead, "bead", "", "With --explain, rank against this bead instead of the hooked one")
 	memoriesCmd.GroupID = GroupWork
 	rootCmd.AddCommand(memoriesCmd)
 }
@@ -23,7 +33,9 @@ var memoriesCmd = &cobra.Command{
 	Long: `List or search memories stored in the beads key-value store.
 
 Without arguments, lists all memories. With a search term, filters
-memories whose key or value contains the term (case-insensitive).
+memories whose key or value contains the term (case-insensitive). Each
+memory a search turns up counts as a use, which gt prime weighs when
+ranking (uses fade over a couple of weeks).
 
 Use --type to filter by memory category:
   feedback   Guidance or corrections from users
@@ -32,10 +44,17 @@ Use --type to filter by memory category:
   reference  Pointers to external resources
   general    Uncategorized memories
 
+Use --explain to see what gt prime would inject in the current session:
+each memory is marked injected or not, with its scope, estimated tokens,
+score (type, relevance to the hooked bead, recency, usage), the reason, and
+how often it has been looked up.
+
 Examples:
   gt memories                    # List all memories
   gt memories --type feedback    # Show only behavioral corrections
-  gt memories refinery           # Search for memories about refinery`,
+  gt memories refinery           # Search for memories about refinery
+  gt memories --explain          # Why each memory is or isn't primed
+  gt memories --explain --bead gt-abc12`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMemories,
 }
@@ -58,43 +77,29 @@ func runMemories(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Filter for memory.* keys and optional search/type
-	type memory struct {
-		memType  string
-		shortKey string
-		value    string
+	all := loadMemories(kvs)
+	if memoriesExplain {
+		return explainMemories(all, typeFilter, search)
 	}
-	var memories []memory
 
-	for k, v := range kvs {
-		if !strings.HasPrefix(k, memoryKeyPrefix) {
-			continue
+	var memories []memory.Memory
+	for _, m := range all {
+		if memoryMatches(m, typeFilter, search) {
+			memories = append(memories, m)
 		}
-
-		memType, shortKey := parseMemoryKey(k)
-
-		if typeFilter != "" && memType != typeFilter {
-			continue
-		}
-
-		if search != "" {
-			if !strings.Contains(strings.ToLower(shortKey), search) &&
-				!strings.Contains(strings.ToLower(v), search) &&
-				!strings.Contains(strings.ToLower(memType), search) {
-				continue
-			}
-		}
-
-		memories = append(memories, memory{memType: memType, shortKey: shortKey, value: v})
 	}
 
 	sort.Slice(memories, func(i, j int) bool {
-		if memories[i].memType != memories[j].memType {
-			return memTypeRank(memories[i].memType) < memTypeRank(memories[j].memType)
+		if memories[i].Type != memories[j].Type {
+			return memTypeRank(memories[i].Type) < memTypeRank(memories[j].Type)
 		}
-		return memories[i].shortKey < memories[j].shortKey
+		return memories[i].ShortKey < memories[j].ShortKey
 	})
 
+	if search != "" {
+		recordMemoryLookups(memories)
+	}
+
 	if len(memories) == 0 {
 		if search != "" {
 			fmt.Printf("No memories matching %q\n", search)
@@ -117,20 +122,130 @@ func runMemories(cmd *cobra.Command, args []string) error {
 
 	lastType := ""
 	for _, m := range memories {
-		if m.memType != lastType {
+		if m.Type != lastType {
 			if lastType != "" {
 				fmt.Println()
 			}
-			fmt.Printf("  %s\n", style.Dim.Render("["+m.memType+"]"))
-			lastType = m.memType
+			fmt.Printf("  %s\n", style.Dim.Render("["+m.Type+"]"))
+			lastType = m.Type
+		}
+		if m.Scope.IsTown() {
+			fmt.Printf("  %s\n", style.Bold.Render(m.ShortKey))
+		} else {
+			fmt.Printf("  %s %s\n", style.Bold.Render(m.ShortKey), style.Dim.Render("("+m.Scope.String()+")"))
+		}
+		fmt.Printf("    %s\n\n", m.Text)
+	}
+
+	return nil
+}
+
+// memoryMatches applies the --type filter and search term to a memory.
+func memoryMatches(m memory.Memory, typeFilter, search string) bool {
+	if typeFilter != "" && m.Type != typeFilter {
+		return false
+	}
+	if search == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(m.ShortKey), search) ||
+		strings.Contains(strings.ToLower(m.Text), search) ||
+		strings.Contains(strings.ToLower(m.Type), search)
+}
+
+// explainMemories ranks memories the way gt prime would for the current
+// session and shows the decision for each.
+func explainMemories(mems []memory.Memory, typeFilter, search string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	var ctx RoleContext
+	if info, err := GetRole(); err == nil {
+		ctx = info
+	}
+
+	var bead *beads.Issue
+	if memoriesBead != "" {
+		bead, err = beads.New(cwd).Show(memoriesBead)
+		if err != nil {
+			return fmt.Errorf("showing %s: %w", memoriesBead, err)
 		}
-		fmt.Printf("  %s\n", style.Bold.Render(m.shortKey))
-		fmt.Printf("    %s\n\n", m.value)
+	} else if ctx.Role != "" && ctx.Role != RoleUnknown {
+		bead, _ = findAgentWork(ctx)
 	}
 
+	budget := config.DefaultMemoryPrimeTokenBudget
+	var usage memory.Usage
+	if ctx.TownRoot != "" {
+		budget = config.LoadOperationalConfig(ctx.TownRoot).GetMemoryConfig().PrimeTokenBudgetV()
+		usage = memory.LoadUsage(memory.UsagePath(ctx.TownRoot))
+	}
+	decisions := memory.Rank(mems, memoryContext(ctx, bead), usage, budget, time.Now())
+
+	session := []string{"role " + orNone(string(ctx.Role)), "rig " + orNone(ctx.Rig)}
+	if bead != nil {
+		session = append(session, fmt.Sprintf("bead %s %q", bead.ID, bead.Title))
+	} else {
+		session = append(session, "no hooked bead")
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Session:"), strings.Join(session, ", "))
+
+	used := 0
+	for _, d := range decisions {
+		if d.Injected {
+			used += d.Tokens
+		}
+	}
+	fmt.Printf("%s %d of %d tokens\n\n", style.Bold.Render("Budget:"), used, budget)
+
+	if len(decisions) == 0 {
+		fmt.Println("No memories stored. Use 'gt remember \"insight\"' to add one.")
+		return nil
+	}
+	for _, d := range decisions {
+		if !memoryMatches(d.Memory, typeFilter, search) {
+			continue
+		}
+		mark := style.Dim.Render("✗")
+		if d.Injected {
+			mark = style.Success.Render("✓")
+		}
+		fmt.Printf("%s %s %s ~%d tokens\n", mark, style.Bold.Render(d.Memory.Type+"/"+d.Memory.ShortKey),
+			style.Dim.Render("("+d.Memory.Scope.String()+")"), d.Tokens)
+		if d.Score > 0 {
+			fmt.Printf("    score %.2f: relevance %.2f, recency %.2f, usage %.2f\n", d.Score, d.Relevance, d.Recency, d.Usage)
+		}
+		if use, ok := usage[d.Memory.Key]; ok {
+			fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("looked up %d times, last %s", use.Count, use.LastUsed.Local().Format("2006-01-02 15:04"))))
+		}
+		fmt.Printf("    %s\n", d.Reason)
+	}
 	return nil
 }
 
+// recordMemoryLookups counts the memories a search turned up as used.
+// Best-effort: outside a town, or if the file cannot be written, nothing
+// is recorded.
+func recordMemoryLookups(mems []memory.Memory) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" || len(mems) == 0 {
+		return
+	}
+	keys := make([]string, len(mems))
+	for i, m := range mems {
+		keys[i] = m.Key
+	}
+	_ = memory.RecordUse(memory.UsagePath(townRoot), keys, time.Now())
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
 // memTypeRank returns the sort order for a memory type (lower = first).
 func memTypeRank(memType string) int {
 	for i, t := range memoryTypeOrder {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/graph"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -50,7 +51,10 @@ Examples:
   gt mol dag gs-wisp-abc     # Show DAG for molecule
   gt mol dag gs-wisp-abc --json  # JSON output
   gt mol dag gs-wisp-abc --tree  # Tree view (default)
-  gt mol dag gs-wisp-abc --tiers # Group by execution tier`,
+  gt mol dag gs-wisp-abc --tiers # Group by execution tier
+  gt mol dag gs-wisp-abc --format svg > dag.svg  # Standalone SVG image
+  gt mol dag gs-wisp-abc --format mermaid        # Mermaid flowchart
+  gt mol dag gs-wisp-abc --format dot | dot -Tpng -o dag.png`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeDag,
 }
@@ -58,17 +62,28 @@ Examples:
 var (
 	dagShowTiers bool
 	dagTreeView  bool
+	dagFormat    string
 )
 
 func init() {
 	moleculeDagCmd.Flags().BoolVar(&dagShowTiers, "tiers", false, "Group output by execution tier")
 	moleculeDagCmd.Flags().BoolVar(&dagTreeView, "tree", true, "Show tree view (default)")
 	moleculeDagCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeDagCmd.Flags().StringVar(&dagFormat, "format", "", "Render as a graph: dot, mermaid, svg")
 }
 
 func runMoleculeDag(cmd *cobra.Command, args []string) error {
 	rootID := args[0]
 
+	var format graph.Format
+	if dagFormat != "" {
+		f, err := graph.ParseFormat(dagFormat)
+		if err != nil {
+			return err
+		}
+		format = f
+	}
+
 	workDir, err := findLocalBeadsDir()
 	if err != nil {
 		return fmt.Errorf("not in a beads workspace: %w", err)
@@ -102,6 +117,16 @@ func runMoleculeDag(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("building DAG: %w", err)
 	}
 
+	// Graph output
+	if format != "" {
+		out, err := graph.Render(dagInfoGraph(dag), format)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	}
+
 	// JSON output
 	if moleculeJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -208,6 +233,27 @@ func buildDAG(b *beads.Beads, root *beads.Issue, children []*beads.Issue) (*DAGI
 	return dag, nil
 }
 
+// dagInfoGraph converts a molecule DAG into a renderable graph with one
+// rank per execution tier.
+func dagInfoGraph(dag *DAGInfo) *graph.Graph {
+	g := &graph.Graph{Title: dag.RootTitle, Ranked: true}
+	for tier, ids := range dag.TierGroups {
+		g.RankNames = append(g.RankNames, fmt.Sprintf("Tier %d", tier))
+		for _, id := range ids {
+			node := dag.Nodes[id]
+			g.Nodes = append(g.Nodes, graph.Node{ID: id, Label: node.Title, Status: node.Status, Rank: tier})
+		}
+	}
+	for _, n := range g.Nodes {
+		deps := append([]string(nil), dag.Nodes[n.ID].Dependencies...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			g.Edges = append(g.Edges, graph.Edge{From: dep, To: n.ID})
+		}
+	}
+	return g
+}
+
 // computeTiers assigns execution tiers to each node.
 // Tier 0 = nodes with no dependencies, higher tiers depend on lower ones.
 func computeTiers(dag *DAGInfo) {
@@ -158,6 +158,9 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		if fields.RetryCount > 0 {
 			fmt.Printf("  Retries:  %d\n", fields.RetryCount)
 		}
+		if fields.Acceptance != "" {
+			fmt.Printf("  Accept:   %s\n", fields.Acceptance)
+		}
 	}
 
 	fmt.Printf("  Age:      %s\n", formatMRAge(next.CreatedAt))
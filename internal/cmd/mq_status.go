@@ -33,6 +33,7 @@ type MRStatusOutput struct {
 	Rig         string `json:"rig,omitempty"`
 	MergeCommit string `json:"merge_commit,omitempty"`
 	CloseReason string `json:"close_reason,omitempty"`
+	Acceptance  string `json:"acceptance,omitempty"`
 
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
@@ -95,6 +96,7 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.Acceptance = mrFields.Acceptance
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -184,6 +186,9 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		if mrFields.CloseReason != "" {
 			fmt.Printf("   Close Reason: %s\n", mrFields.CloseReason)
 		}
+		if mrFields.Acceptance != "" {
+			fmt.Printf("   Acceptance:   %s %s\n", mrFields.Acceptance, style.Dim.Render("(report in MR comments)"))
+		}
 	}
 
 	// Dependencies (what this MR is waiting on)
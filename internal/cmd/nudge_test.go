@@ -513,6 +513,9 @@ func TestNudgeDogTargetRoutesToDogSession(t *testing.T) {
 		nudgeForceFlag = origForce
 	}()
 
+	// Run outside the source tree: the nudge is logged to the town found
+	// from the working directory.
+	t.Chdir(t.TempDir())
 	logPath := filepath.Join(t.TempDir(), "nudge.log")
 	t.Setenv("GT_TEST_NUDGE_LOG", logPath)
 
@@ -35,11 +35,18 @@ Detections:
     stuck done-intent, closed beads with live sessions
   - Stalls: Agents stuck at startup prompts
   - Completions: Agent bead metadata indicating gt done was called
+  - Thrashing: Repeated identical tool calls, edit/revert oscillation,
+    and the same tool error over and over in the agent's conversation
+  - Warrants: Live polecats matching a witness warrant policy
 
 Actions taken automatically:
   - Zombie restart: Sessions are restarted (not nuked) to preserve worktrees
   - Cleanup wisps: Created for dirty state tracking
   - Completion routing: MR cleanup wisps created, refinery nudged
+  - Thrash ladder: Looping polecats are nudged with a targeted hint; if they
+    keep looping after the hint, an escalation bead is filed
+  - Warrant filing: Policy matches get a warrant with evidence and a veto
+    window; the mayor is mailed with veto instructions
 
 Use --notify to send mail when zombies with active work are detected.
 Long-running scan phases emit progress diagnostics to stderr so JSON stdout
@@ -72,6 +79,8 @@ type PatrolScanOutput struct {
 	Stalls      *PatrolScanStallOutput    `json:"stalls,omitempty"`
 	Completions *PatrolScanCompleteOutput `json:"completions,omitempty"`
 	Receipts    []witness.PatrolReceipt   `json:"receipts,omitempty"`
+	Thrash      *PatrolScanThrashOutput   `json:"thrash,omitempty"`
+	Warrants    *PatrolScanWarrantOutput  `json:"warrants,omitempty"`
 }
 
 // PatrolScanZombieOutput holds zombie detection results.
@@ -128,6 +137,40 @@ type PatrolScanCompleteItem struct {
 	CompletionTime string `json:"completion_time,omitempty"`
 }
 
+// PatrolScanThrashOutput holds thrash detection results.
+type PatrolScanThrashOutput struct {
+	Checked  int                    `json:"checked"`
+	Found    int                    `json:"found"`
+	Detected []PatrolScanThrashItem `json:"detected,omitempty"`
+	Errors   []string               `json:"errors,omitempty"`
+}
+
+// PatrolScanThrashItem is a single thrashing polecat in scan output.
+type PatrolScanThrashItem struct {
+	Polecat string `json:"polecat"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Count   int    `json:"count"`
+	Action  string `json:"action"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PatrolScanWarrantOutput holds policy warrant filing results.
+type PatrolScanWarrantOutput struct {
+	Checked int                     `json:"checked"`
+	Filed   []PatrolScanWarrantItem `json:"filed,omitempty"`
+	Errors  []string                `json:"errors,omitempty"`
+}
+
+// PatrolScanWarrantItem is a single policy-filed warrant in scan output.
+type PatrolScanWarrantItem struct {
+	Polecat      string `json:"polecat"`
+	Target       string `json:"target"`
+	Policy       string `json:"policy"`
+	Reason       string `json:"reason"`
+	ExecuteAfter string `json:"execute_after"`
+}
+
 func runPatrolScan(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -167,6 +210,12 @@ func runPatrolScan(cmd *cobra.Command, args []string) error {
 	completionResult := runPatrolScanPhase(diagnostics, "completion discovery", func() *witness.DiscoverCompletionsResult {
 		return witness.DiscoverCompletions(bd, workDir, rigName, router)
 	})
+	thrashResult := runPatrolScanPhase(diagnostics, "thrash detection", func() *witness.DetectThrashResult {
+		return witness.DetectThrashingPolecats(workDir, rigName)
+	})
+	warrantResult := runPatrolScanPhase(diagnostics, "warrant policies", func() *witness.FileWarrantsResult {
+		return witness.FileWarrantsByPolicy(workDir, rigName)
+	})
 
 	// Build patrol receipts for zombies
 	receipts := witness.BuildPatrolReceipts(rigName, zombieResult)
@@ -181,12 +230,15 @@ func runPatrolScan(cmd *cobra.Command, args []string) error {
 			sendZombieNotification(router, rigName, zombieResult, activeZombies)
 		}
 	}
+	if warrantResult != nil && len(warrantResult.Filed) > 0 {
+		sendWarrantNotification(router, rigName, warrantResult)
+	}
 
 	if patrolScanJSON {
-		return outputPatrolScanJSON(rigName, timestamp, zombieResult, stallResult, completionResult, receipts)
+		return outputPatrolScanJSON(rigName, timestamp, zombieResult, stallResult, completionResult, thrashResult, warrantResult, receipts)
 	}
 
-	return outputPatrolScanHuman(rigName, zombieResult, stallResult, completionResult, receipts)
+	return outputPatrolScanHuman(rigName, zombieResult, stallResult, completionResult, thrashResult, warrantResult, receipts)
 }
 
 func runPatrolScanPhase[T any](diagnostics io.Writer, name string, fn func() T) T {
@@ -285,7 +337,31 @@ func sendZombieNotification(router *mail.Router, rigName string, result *witness
 	_ = router.Send(mayorMsg)
 }
 
-func outputPatrolScanJSON(rigName, timestamp string, zombieResult *witness.DetectZombiePolecatsResult, stallResult *witness.DetectStalledPolecatsResult, completionResult *witness.DiscoverCompletionsResult, receipts []witness.PatrolReceipt) error {
+// sendWarrantNotification tells the mayor which warrants were filed by
+// policy and how to veto them before the grace period ends.
+func sendWarrantNotification(router *mail.Router, rigName string, result *witness.FileWarrantsResult) {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Witness filed %d warrant(s) by policy in rig %s:", len(result.Filed), rigName))
+	lines = append(lines, "")
+	for _, w := range result.Filed {
+		lines = append(lines, fmt.Sprintf("- %s: %s (executes after %s)",
+			w.Target, w.Reason, w.ExecuteAfter.Format("15:04")))
+	}
+	lines = append(lines, "",
+		"Review evidence:",
+		"  gt warrant show <target>",
+		"Veto before the deadline if the agent is making progress:",
+		"  gt warrant veto <target> -r \"reason\"")
+
+	_ = router.Send(&mail.Message{
+		From:    fmt.Sprintf("%s/witness", rigName),
+		To:      "mayor/",
+		Subject: fmt.Sprintf("WARRANT_FILED: %d warrant(s) in %s", len(result.Filed), rigName),
+		Body:    strings.Join(lines, "\n"),
+	})
+}
+
+func outputPatrolScanJSON(rigName, timestamp string, zombieResult *witness.DetectZombiePolecatsResult, stallResult *witness.DetectStalledPolecatsResult, completionResult *witness.DiscoverCompletionsResult, thrashResult *witness.DetectThrashResult, warrantResult *witness.FileWarrantsResult, receipts []witness.PatrolReceipt) error {
 	output := PatrolScanOutput{
 		Rig:       rigName,
 		Timestamp: timestamp,
@@ -361,12 +437,55 @@ func outputPatrolScanJSON(rigName, timestamp string, zombieResult *witness.Detec
 		output.Completions = co
 	}
 
+	// Thrash
+	if thrashResult != nil {
+		to := &PatrolScanThrashOutput{
+			Checked: thrashResult.Checked,
+			Found:   len(thrashResult.Detected),
+		}
+		for _, d := range thrashResult.Detected {
+			item := PatrolScanThrashItem{
+				Polecat: d.PolecatName,
+				Kind:    d.Finding.Kind,
+				Subject: d.Finding.Subject,
+				Count:   d.Finding.Count,
+				Action:  d.Action,
+			}
+			if d.Error != nil {
+				item.Error = d.Error.Error()
+			}
+			to.Detected = append(to.Detected, item)
+		}
+		for _, e := range thrashResult.Errors {
+			to.Errors = append(to.Errors, e.Error())
+		}
+		output.Thrash = to
+	}
+
+	// Warrants
+	if warrantResult != nil {
+		wo := &PatrolScanWarrantOutput{Checked: warrantResult.Checked}
+		for _, w := range warrantResult.Filed {
+			wo.Filed = append(wo.Filed, PatrolScanWarrantItem{
+				Polecat:      w.PolecatName,
+				Target:       w.Target,
+				Policy:       w.Policy,
+				Reason:       w.Reason,
+				ExecuteAfter: w.ExecuteAfter.UTC().Format(time.RFC3339),
+			})
+		}
+		for _, e := range warrantResult.Errors {
+			wo.Errors = append(wo.Errors, e.Error())
+		}
+		output.Warrants = wo
+	}
+
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(output)
 }
 
-func outputPatrolScanHuman(rigName string, zombieResult *witness.DetectZombiePolecatsResult, stallResult *witness.DetectStalledPolecatsResult, completionResult *witness.DiscoverCompletionsResult, _ []witness.PatrolReceipt) error {
+func outputPatrolScanHuman(rigName string, zombieResult *witness.DetectZombiePolecatsResult, stallResult *witness.DetectStalledPolecatsResult, completionResult *witness.DiscoverCompletionsResult, thrashResult *witness.DetectThrashResult, warrantResult *witness.FileWarrantsResult, _ []witness.PatrolReceipt) error {
 	fmt.Printf("%s Patrol scan: %s\n\n", style.Bold.Render("🔍"), rigName)
 
 	// Zombies
@@ -452,6 +571,43 @@ func outputPatrolScanHuman(rigName string, zombieResult *witness.DetectZombiePol
 		fmt.Println()
 	}
 
+	// Thrash
+	if thrashResult != nil && (len(thrashResult.Detected) > 0 || patrolScanVerbose) {
+		fmt.Printf("%s Thrash Detection: checked %d polecat(s)\n",
+			style.Bold.Render("🔁"), thrashResult.Checked)
+
+		if len(thrashResult.Detected) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("No loops detected"))
+		}
+		for _, d := range thrashResult.Detected {
+			fmt.Printf("  ⚠ %s: %s x%d → %s\n", d.PolecatName, d.Finding.Kind, d.Finding.Count, d.Action)
+			fmt.Printf("    %s\n", style.Dim.Render(d.Finding.Subject))
+			if d.Error != nil {
+				fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("Error: %v", d.Error)))
+			}
+		}
+		fmt.Println()
+	}
+
+	// Warrants
+	if warrantResult != nil && (len(warrantResult.Filed) > 0 || len(warrantResult.Errors) > 0 || patrolScanVerbose) {
+		fmt.Printf("%s Warrant Policies: checked %d polecat(s)\n",
+			style.Bold.Render("⚖️"), warrantResult.Checked)
+
+		if len(warrantResult.Filed) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("No warrants filed"))
+		}
+		for _, w := range warrantResult.Filed {
+			fmt.Printf("  🚨 %s: %s\n", w.PolecatName, w.Reason)
+			fmt.Printf("    Executes after %s — veto with: gt warrant veto %s -r <reason>\n",
+				w.ExecuteAfter.Format("15:04:05"), w.Target)
+		}
+		for _, e := range warrantResult.Errors {
+			fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("Error: %v", e)))
+		}
+		fmt.Println()
+	}
+
 	// Summary
 	zombieCount := 0
 	activeCount := 0
@@ -468,11 +624,20 @@ func outputPatrolScanHuman(rigName string, zombieResult *witness.DetectZombiePol
 		completionCount = len(completionResult.Discovered)
 	}
 
-	if zombieCount == 0 && stallCount == 0 && completionCount == 0 {
+	thrashCount := 0
+	if thrashResult != nil {
+		thrashCount = len(thrashResult.Detected)
+	}
+	warrantCount := 0
+	if warrantResult != nil {
+		warrantCount = len(warrantResult.Filed)
+	}
+
+	if zombieCount == 0 && stallCount == 0 && completionCount == 0 && thrashCount == 0 && warrantCount == 0 {
 		fmt.Printf("%s All clear — no issues detected\n", style.Success.Render("✓"))
 	} else {
-		fmt.Printf("Summary: %d zombie(s) (%d active-work), %d stall(s), %d completion(s)\n",
-			zombieCount, activeCount, stallCount, completionCount)
+		fmt.Printf("Summary: %d zombie(s) (%d active-work), %d stall(s), %d completion(s), %d thrashing, %d warrant(s)\n",
+			zombieCount, activeCount, stallCount, completionCount, thrashCount, warrantCount)
 	}
 
 	return nil
@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cgroup"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -459,33 +461,13 @@ func getPolecatManager(rigName string) (*polecat.Manager, *rig.Rig, error) {
 	return mgr, r, nil
 }
 
-func runPolecatList(cmd *cobra.Command, args []string) error {
-	var rigs []*rig.Rig
-
-	if polecatListAll {
-		// List all rigs
-		allRigs, err := getAllRigs()
-		if err != nil {
-			return err
-		}
-		rigs = allRigs
-	} else {
-		// Need a rig name
-		if len(args) < 1 {
-			return fmt.Errorf("rig name required (or use --all)")
-		}
-		_, r, err := getPolecatManager(args[0])
-		if err != nil {
-			return err
-		}
-		rigs = []*rig.Rig{r}
-	}
-
-	// Collect polecats from all rigs
+// collectPolecatListItems builds list items for every polecat directory in
+// the given rigs, plus zombie tmux sessions with no matching directory.
+func collectPolecatListItems(rigs []*rig.Rig) ([]PolecatListItem, error) {
 	t := tmux.NewTmux()
 	sessionNames, err := t.ListSessions()
 	if err != nil {
-		return fmt.Errorf("listing tmux sessions: %w", err)
+		return nil, fmt.Errorf("listing tmux sessions: %w", err)
 	}
 	sessions := newPolecatSessionSet(sessionNames)
 	allPolecats := make([]PolecatListItem, 0)
@@ -571,6 +553,36 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	return allPolecats, nil
+}
+
+func runPolecatList(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+
+	if polecatListAll {
+		// List all rigs
+		allRigs, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = allRigs
+	} else {
+		// Need a rig name
+		if len(args) < 1 {
+			return fmt.Errorf("rig name required (or use --all)")
+		}
+		_, r, err := getPolecatManager(args[0])
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	allPolecats, err := collectPolecatListItems(rigs)
+	if err != nil {
+		return err
+	}
+
 	// Output
 	if polecatListJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -735,6 +747,7 @@ type PolecatStatus struct {
 	Windows        int           `json:"windows,omitempty"`
 	CreatedAt      string        `json:"created_at,omitempty"`
 	LastActivity   string        `json:"last_activity,omitempty"`
+	Resources      *cgroup.Usage `json:"resources,omitempty"`
 }
 
 func runPolecatStatus(cmd *cobra.Command, args []string) error {
@@ -766,6 +779,10 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Resource usage from the polecat's cgroup, when it runs in one
+	cgroupPath := cgroup.PolecatPath(config.ResolveCgroupRoot(filepath.Dir(r.Path)), rigName, polecatName)
+	usage, _ := cgroup.ReadUsage(cgroupPath)
+
 	// JSON output
 	if polecatStatusJSON {
 		status := PolecatStatus{
@@ -779,6 +796,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			SessionID:      sessInfo.SessionID,
 			Attached:       sessInfo.Attached,
 			Windows:        sessInfo.Windows,
+			Resources:      usage,
 		}
 		if !sessInfo.Created.IsZero() {
 			status.CreatedAt = sessInfo.Created.Format("2006-01-02 15:04:05")
@@ -856,6 +874,18 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Status:        %s\n", style.Dim.Render("not running"))
 	}
 
+	if usage != nil {
+		fmt.Println()
+		fmt.Printf("%s\n", style.Bold.Render("Resources"))
+		fmt.Printf("  CPU:           %s\n", formatCgroupCPU(usage.CPUUsec))
+		fmt.Printf("  Memory:        %s\n", formatCgroupMemory(usage))
+		if usage.MemoryPeak > 0 {
+			fmt.Printf("  Memory Peak:   %s\n", cgroup.FormatBytes(usage.MemoryPeak))
+		}
+		fmt.Printf("  Processes:     %d\n", usage.PidsCurrent)
+		fmt.Printf("  Cgroup:        %s\n", style.Dim.Render(cgroupPath))
+	}
+
 	return nil
 }
 
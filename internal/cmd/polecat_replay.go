@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/replay"
+)
+
+var (
+	polecatReplayRecording string
+	polecatReplayList      bool
+	polecatReplayPrint     bool
+	polecatReplayStat      bool
+	polecatReplayJSON      bool
+)
+
+var polecatReplayCmd = &cobra.Command{
+	Use:   "replay <session | rig/polecat>",
+	Short: "Step through a polecat session's recorded tool calls",
+	Long: `Replay a polecat session: every tool call it made, in order, with the
+diff each call produced.
+
+Polecat sessions are recorded by the PostToolUse hook ('gt tap record'):
+each call's input and result, plus a git tree of the worktree after it.
+Replay walks those trees, so it works after the polecat has been nuked, as
+long as git has not pruned the objects.
+
+The target is a polecat session name (gt-gastown-Toast) or rig/polecat. The
+newest matching recording is shown; use --list to see the others and
+--recording to pick one by ID prefix.
+
+Interactive keys: ←/→ (or p/n) step through calls, ↑/↓ scroll the diff,
+c shows only calls that changed files, q quits. When stdout is not a
+terminal, or with --print, the replay is written out instead.
+
+Examples:
+  gt polecat replay gastown/Toast
+  gt polecat replay gt-gastown-Toast --list
+  gt polecat replay gastown/Toast --print --stat`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatReplay,
+}
+
+func init() {
+	polecatReplayCmd.Flags().StringVar(&polecatReplayRecording, "recording", "", "Recording ID (or prefix) to replay")
+	polecatReplayCmd.Flags().BoolVar(&polecatReplayList, "list", false, "List recordings instead of replaying")
+	polecatReplayCmd.Flags().BoolVar(&polecatReplayPrint, "print", false, "Print the replay instead of opening the viewer")
+	polecatReplayCmd.Flags().BoolVar(&polecatReplayStat, "stat", false, "With --print, show a diffstat per call instead of the patch")
+	polecatReplayCmd.Flags().BoolVar(&polecatReplayJSON, "json", false, "Output as JSON")
+	polecatCmd.AddCommand(polecatReplayCmd)
+}
+
+func runPolecatReplay(cmd *cobra.Command, args []string) error {
+	target := args[0]
+	sessionName := ""
+	rigName, polecatName, ok := parsePolecatSessionName(target)
+	if ok {
+		sessionName = target
+	} else {
+		var err error
+		if rigName, polecatName, err = parseAddress(target); err != nil {
+			return err
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	dir := checkpoint.RecordingsDir(r.Path, polecatName)
+	recs, err := checkpoint.ListRecordings(dir)
+	if err != nil {
+		return fmt.Errorf("listing recordings: %w", err)
+	}
+	if len(recs) == 0 {
+		return fmt.Errorf("no recordings for %s/%s (recorded by the polecat PostToolUse hook; run 'gt hooks sync' if it is missing)", rigName, polecatName)
+	}
+
+	if polecatReplayList {
+		return printReplayList(recs, rigName, polecatName)
+	}
+
+	rec, err := selectRecording(recs, polecatReplayRecording, sessionName)
+	if err != nil {
+		return err
+	}
+	if rec, err = checkpoint.LoadRecording(rec.Path); err != nil {
+		return err
+	}
+
+	if polecatReplayJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rec)
+	}
+
+	gitDir := replayGitDir(r, polecatName)
+	if polecatReplayPrint || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return printReplay(rec, gitDir, polecatReplayStat)
+	}
+
+	m := replay.New(rec, func(s checkpoint.Step) (string, error) {
+		return checkpoint.StepDiff(gitDir, s, false)
+	})
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// selectRecording picks the recording to replay: by ID prefix when given,
+// else the newest one made in sessionName (if set), else the newest.
+// recs is newest first and carries no steps.
+func selectRecording(recs []*checkpoint.Recording, idPrefix, sessionName string) (*checkpoint.Recording, error) {
+	if idPrefix != "" {
+		var matches []*checkpoint.Recording
+		for _, rec := range recs {
+			if strings.HasPrefix(rec.ID, idPrefix) {
+				matches = append(matches, rec)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("no recording matching %q (see --list)", idPrefix)
+		case 1:
+			return matches[0], nil
+		default:
+			return nil, fmt.Errorf("recording %q is ambiguous (%d matches)", idPrefix, len(matches))
+		}
+	}
+	if sessionName != "" {
+		for _, rec := range recs {
+			loaded, err := checkpoint.LoadRecording(rec.Path)
+			if err == nil && loaded.Session() == sessionName {
+				return rec, nil
+			}
+		}
+		return nil, fmt.Errorf("no recording from session %s (see --list)", sessionName)
+	}
+	return recs[0], nil
+}
+
+// replayGitDir returns a checkout sharing the polecat's object store: the
+// polecat's own worktree while it exists, else the rig's repo base.
+func replayGitDir(r *rig.Rig, polecatName string) string {
+	for _, dir := range []string{
+		filepath.Join(r.Path, "polecats", polecatName, r.Name),
+		filepath.Join(r.Path, ".repo.git"),
+		filepath.Join(r.Path, "mayor", "rig"),
+	} {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return r.Path
+}
+
+func printReplayList(recs []*checkpoint.Recording, rigName, polecatName string) error {
+	loaded := make([]*checkpoint.Recording, 0, len(recs))
+	for _, rec := range recs {
+		l, err := checkpoint.LoadRecording(rec.Path)
+		if err != nil {
+			continue
+		}
+		loaded = append(loaded, l)
+	}
+
+	if polecatReplayJSON {
+		type recordingSummary struct {
+			ID      string `json:"id"`
+			Session string `json:"session,omitempty"`
+			Started string `json:"started"`
+			Calls   int    `json:"calls"`
+			Changed int    `json:"changed"`
+		}
+		out := make([]recordingSummary, 0, len(loaded))
+		for _, rec := range loaded {
+			out = append(out, recordingSummary{
+				ID:      rec.ID,
+				Session: rec.Session(),
+				Started: rec.Started.Format("2006-01-02T15:04:05Z07:00"),
+				Calls:   len(rec.Steps),
+				Changed: countChangedSteps(rec),
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Recordings for %s/%s", rigName, polecatName)))
+	for _, rec := range loaded {
+		fmt.Printf("  %s  %s  %d calls, %d with changes  %s\n",
+			rec.Started.Format("2006-01-02 15:04"), rec.ID, len(rec.Steps), countChangedSteps(rec),
+			style.Dim.Render(rec.Session()))
+	}
+	return nil
+}
+
+func countChangedSteps(rec *checkpoint.Recording) int {
+	n := 0
+	for _, s := range rec.Steps {
+		if s.Changed() {
+			n++
+		}
+	}
+	return n
+}
+
+func printReplay(rec *checkpoint.Recording, gitDir string, stat bool) error {
+	fmt.Printf("%s %s", style.Bold.Render("Replay"), rec.ID)
+	if s := rec.Session(); s != "" {
+		fmt.Printf("  %s", style.Dim.Render(s))
+	}
+	fmt.Printf("\n%d calls, %d with changes\n", len(rec.Steps), countChangedSteps(rec))
+
+	for i, s := range rec.Steps {
+		fmt.Printf("\n%s %s  %s\n", style.Bold.Render(fmt.Sprintf("── Call %d/%d", i+1, len(rec.Steps))), s.Tool, style.Dim.Render(s.Time.Format("15:04:05")))
+		if in := strings.TrimSpace(s.Input); in != "" {
+			first, _, more := strings.Cut(in, "\n")
+			if more {
+				first += " …"
+			}
+			fmt.Printf("   %s\n", first)
+		}
+		if !s.Changed() {
+			continue
+		}
+		diff, err := checkpoint.StepDiff(gitDir, s, stat)
+		if err != nil {
+			style.PrintWarning("%v", err)
+			continue
+		}
+		fmt.Println(diff)
+	}
+	return nil
+}
@@ -60,6 +60,7 @@ type SlingSpawnOptions struct {
 	BaseBranch    string // Override base branch for polecat worktree (e.g., "develop", "release/v2")
 	ResumeBranch  string // Resume an existing branch (e.g. PR head) instead of creating polecat/<name>/<bead>+<ts>
 	SkipAdmission bool   // Caller already holds a polecat admission reservation
+	SparseProfile string // Rig sparse profile for the worktree (empty = full checkout); see slingSparseProfile
 }
 
 func effectivePolecatDirCap(configured int) int {
@@ -180,13 +181,19 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		fmt.Println("  Allocating fresh polecat after reclaiming broken idle sandbox...")
 	}
 
+	sparseProfile := opts.SparseProfile
+
 	// Persistent polecat model (gt-4ac): try to reuse an idle polecat first.
 	// Idle polecats have completed their work but kept their sandbox (worktree).
 	// Reusing avoids the overhead of creating a new worktree.
 	idlePolecat, findErr := polecatMgr.FindIdlePolecat()
 	if findErr == nil && idlePolecat != nil {
 		polecatName := idlePolecat.Name
-		fmt.Printf("Reusing idle polecat: %s\n", polecatName)
+		if polecatMgr.IsWarm(polecatName) {
+			fmt.Printf("Claiming warm polecat: %s\n", polecatName)
+		} else {
+			fmt.Printf("Reusing idle polecat: %s\n", polecatName)
+		}
 
 		// ResumeBranch takes precedence over BaseBranch / integration auto-detection:
 		// when the user (or scheduler) wants to resume an existing PR branch, we
@@ -221,9 +228,10 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		// If reuse is unsafe or fails, allocate a new polecat instead of repairing
 		// this worktree destructively.
 		addOpts := polecat.AddOptions{
-			HookBead:     opts.HookBead,
-			BaseBranch:   baseBranch,
-			ResumeBranch: opts.ResumeBranch,
+			HookBead:      opts.HookBead,
+			BaseBranch:    baseBranch,
+			ResumeBranch:  opts.ResumeBranch,
+			SparseProfile: sparseProfile,
 		}
 		reuseOK := false
 		if _, err := polecatMgr.ReuseIdlePolecat(polecatName, addOpts); err != nil {
@@ -323,9 +331,10 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	// Build add options with hook_bead set atomically at spawn time
 	addOpts := polecat.AddOptions{
-		HookBead:     opts.HookBead,
-		BaseBranch:   baseBranch,
-		ResumeBranch: opts.ResumeBranch,
+		HookBead:      opts.HookBead,
+		BaseBranch:    baseBranch,
+		ResumeBranch:  opts.ResumeBranch,
+		SparseProfile: sparseProfile,
 	}
 
 	// No idle polecat available — allocate and create atomically (GH#2215).
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/worktree"
+)
+
+var (
+	polecatInventoryJSON bool
+	polecatInventoryAll  bool
+	polecatInventoryDisk bool
+
+	polecatWarmAll  bool
+	polecatWarmJSON bool
+)
+
+var polecatInventoryCmd = &cobra.Command{
+	Use:   "inventory [rig]",
+	Short: "Show polecat pool health per rig",
+	Long: `Show polecat pool health for a rig: how many polecats are working,
+idle, or waiting on recovery, how many directories the rig holds against its
+max_polecats cap, and the state of the warm pool.
+
+A warm slot is an idle polecat prepared ahead of time at the default branch
+(see 'gt polecat warm'). Fresh slots were refreshed recently and are claimed
+by sling without fetching; stale slots mean nothing is maintaining the pool.
+
+--disk walks each polecat's worktree and reports its disk usage. For
+worktrees materialized as reflink copies (worktree_strategy in config.json),
+ALLOCATED is what du would say and EXCLUSIVE estimates what the polecat
+alone holds: files written since it was created. Unchanged files share
+extents with the rig's template checkout.
+
+Examples:
+  gt polecat inventory gastown
+  gt polecat inventory gastown --disk
+  gt polecat inventory --all
+  gt polecat inventory --all --json`,
+	RunE: runPolecatInventory,
+}
+
+var polecatWarmCmd = &cobra.Command{
+	Use:   "warm [rig]",
+	Short: "Fill and refresh a rig's warm polecat pool",
+	Long: `Bring a rig's warm polecat pool up to its target.
+
+polecat_warm_pool in the rig's config.json sets how many idle polecats to
+keep prepared. Each pass fast-forwards existing warm slots to the default
+branch, re-running polecat_warm_command (e.g. a dependency install) when the
+branch moved, and creates new slots until the target is met. New slots count
+against max_polecats like any other polecat.
+
+The daemon runs this on every heartbeat for rigs with a warm pool; run it by
+hand after changing the config or to pre-warm before a large sling.
+
+Examples:
+  gt polecat warm gastown
+  gt polecat warm --all`,
+	RunE: runPolecatWarm,
+}
+
+func init() {
+	polecatInventoryCmd.Flags().BoolVar(&polecatInventoryJSON, "json", false, "Output as JSON")
+	polecatInventoryCmd.Flags().BoolVar(&polecatInventoryAll, "all", false, "Show all rigs")
+	polecatInventoryCmd.Flags().BoolVar(&polecatInventoryDisk, "disk", false, "Report per-polecat disk usage (walks every worktree)")
+
+	polecatWarmCmd.Flags().BoolVar(&polecatWarmAll, "all", false, "Warm every rig with a warm pool configured")
+	polecatWarmCmd.Flags().BoolVar(&polecatWarmJSON, "json", false, "Output as JSON")
+
+	polecatCmd.AddCommand(polecatInventoryCmd)
+	polecatCmd.AddCommand(polecatWarmCmd)
+}
+
+// PolecatPoolInventory is one rig's pool health for gt polecat inventory.
+type PolecatPoolInventory struct {
+	Rig           string                 `json:"rig"`
+	Dirs          int                    `json:"dirs"`
+	DirCap        int                    `json:"dir_cap"`
+	Working       int                    `json:"working"`
+	Idle          int                    `json:"idle"`
+	NeedsRecovery int                    `json:"needs_recovery"`
+	Zombies       int                    `json:"zombies,omitempty"`
+	Warm          polecat.WarmPoolStatus `json:"warm"`
+	Disk          []PolecatDiskUsage     `json:"disk,omitempty"`
+}
+
+// PolecatDiskUsage is one polecat worktree's disk usage.
+type PolecatDiskUsage struct {
+	Name string `json:"name"`
+	worktree.Usage
+}
+
+// collectPolecatDiskUsage measures every polecat worktree in the rig, plus
+// the reflink template checkout when there is one.
+func collectPolecatDiskUsage(mgr *polecat.Manager, r *rig.Rig, items []PolecatListItem) []PolecatDiskUsage {
+	var usage []PolecatDiskUsage
+	for _, p := range items {
+		if p.Rig != r.Name || p.Zombie {
+			continue
+		}
+		u, err := worktree.DiskUsage(mgr.ClonePath(p.Name))
+		if err != nil {
+			style.PrintWarning("measuring %s/%s: %v", r.Name, p.Name, err)
+			continue
+		}
+		usage = append(usage, PolecatDiskUsage{Name: p.Name, Usage: u})
+	}
+	template := filepath.Join(r.Path, worktree.TemplateDirName, "polecats")
+	if _, err := os.Stat(template); err == nil {
+		if u, err := worktree.DiskUsage(template); err == nil {
+			usage = append(usage, PolecatDiskUsage{Name: "(template)", Usage: u})
+		}
+	}
+	return usage
+}
+
+// summarizePolecatInventory counts list items into a rig inventory.
+func summarizePolecatInventory(inv *PolecatPoolInventory, items []PolecatListItem) {
+	for _, p := range items {
+		if p.Rig != inv.Rig {
+			continue
+		}
+		if p.Zombie {
+			inv.Zombies++
+			continue
+		}
+		inv.Dirs++
+		switch {
+		case p.NeedsRecovery:
+			inv.NeedsRecovery++
+		case p.State == polecat.StateIdle && p.Reusable:
+			inv.Idle++
+		case p.CountsTowardCapacity:
+			inv.Working++
+		}
+	}
+}
+
+func polecatPoolRigs(args []string, all bool) ([]*rig.Rig, error) {
+	if all {
+		return getAllRigs()
+	}
+	if len(args) < 1 {
+		return nil, fmt.Errorf("rig name required (or use --all)")
+	}
+	_, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return []*rig.Rig{r}, nil
+}
+
+func runPolecatInventory(cmd *cobra.Command, args []string) error {
+	rigs, err := polecatPoolRigs(args, polecatInventoryAll)
+	if err != nil {
+		return err
+	}
+	items, err := collectPolecatListItems(rigs)
+	if err != nil {
+		return err
+	}
+
+	inventories := make([]PolecatPoolInventory, 0, len(rigs))
+	for _, r := range rigs {
+		mgr, mgrRig, err := getPolecatManager(r.Name)
+		if err != nil {
+			return err
+		}
+		inv := PolecatPoolInventory{
+			Rig:    r.Name,
+			DirCap: effectivePolecatDirCap(mgrRig.GetIntConfig("max_polecats")),
+			Warm:   mgr.WarmPoolStatus(),
+		}
+		summarizePolecatInventory(&inv, items)
+		if polecatInventoryDisk {
+			inv.Disk = collectPolecatDiskUsage(mgr, mgrRig, items)
+		}
+		inventories = append(inventories, inv)
+	}
+
+	if polecatInventoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(inventories)
+	}
+
+	now := time.Now()
+	for i, inv := range inventories {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", style.Bold.Render(inv.Rig))
+		fmt.Printf("  Polecats:  %d working, %d idle", inv.Working, inv.Idle)
+		if inv.NeedsRecovery > 0 {
+			fmt.Printf(", %s", style.Warning.Render(fmt.Sprintf("%d need recovery", inv.NeedsRecovery)))
+		}
+		if inv.Zombies > 0 {
+			fmt.Printf(", %s", style.Warning.Render(fmt.Sprintf("%d zombie session(s)", inv.Zombies)))
+		}
+		fmt.Println()
+		fmt.Printf("  Dirs:      %d / %d\n", inv.Dirs, inv.DirCap)
+
+		if len(inv.Disk) > 0 {
+			fmt.Printf("  Disk:\n")
+			fmt.Printf("    %-12s %-9s %10s %10s\n", "POLECAT", "LAYOUT", "ALLOCATED", "EXCLUSIVE")
+			for _, d := range inv.Disk {
+				fmt.Printf("    %-12s %-9s %10s %10s\n", d.Name, d.Strategy, formatBytes(d.Allocated), formatBytes(d.Exclusive))
+			}
+		}
+
+		warm := inv.Warm
+		if warm.Target == 0 && len(warm.Slots) == 0 {
+			fmt.Printf("  Warm pool: %s\n", style.Dim.Render("off (set polecat_warm_pool in config.json)"))
+			continue
+		}
+		status := style.Success.Render("✓")
+		if !warm.Healthy() {
+			status = style.Warning.Render("⚠")
+		}
+		fmt.Printf("  Warm pool: %s %d/%d fresh", status, warm.Fresh, warm.Target)
+		if stale := len(warm.Slots) - warm.Fresh; stale > 0 {
+			fmt.Printf(", %d stale", stale)
+		}
+		fmt.Println()
+		for _, s := range warm.Slots {
+			age := now.Sub(s.RefreshedAt).Round(time.Second)
+			line := fmt.Sprintf("%s @ %s, refreshed %s ago", s.BaseRef, shortHash(s.Commit), age)
+			if !s.Fresh(now) {
+				line = style.Warning.Render(line)
+			} else {
+				line = style.Dim.Render(line)
+			}
+			fmt.Printf("    %-12s %s\n", s.Name, line)
+			if s.WarmError != "" {
+				fmt.Printf("    %-12s %s\n", "", style.Warning.Render(s.WarmError))
+			}
+		}
+	}
+	return nil
+}
+
+func runPolecatWarm(cmd *cobra.Command, args []string) error {
+	rigs, err := polecatPoolRigs(args, polecatWarmAll)
+	if err != nil {
+		return err
+	}
+
+	results := make(map[string]polecat.WarmFillResult)
+	failed := false
+	for _, r := range rigs {
+		mgr, mgrRig, err := getPolecatManager(r.Name)
+		if err != nil {
+			return err
+		}
+		if polecatWarmAll && mgr.WarmPoolTarget() == 0 && len(mgr.WarmSlots()) == 0 {
+			continue
+		}
+		res := mgr.FillWarmPool(effectivePolecatDirCap(mgrRig.GetIntConfig("max_polecats")))
+		results[r.Name] = res
+		if len(res.Errors) > 0 {
+			failed = true
+		}
+
+		if polecatWarmJSON {
+			continue
+		}
+		fmt.Printf("%s warm pool: %d/%d ready\n", style.Bold.Render(r.Name), res.Ready, res.Target)
+		if len(res.Created) > 0 {
+			fmt.Printf("  %s Created %s\n", style.Success.Render("✓"), strings.Join(res.Created, ", "))
+		}
+		if len(res.Refreshed) > 0 {
+			fmt.Printf("  %s Moved to new base: %s\n", style.Success.Render("✓"), strings.Join(res.Refreshed, ", "))
+		}
+		if len(res.Dropped) > 0 {
+			fmt.Printf("  %s No longer warm (claimed or not reusable): %s\n", style.Dim.Render("○"), strings.Join(res.Dropped, ", "))
+		}
+		for _, e := range res.Errors {
+			fmt.Printf("  %s %s\n", style.Error.Render("✗"), e)
+		}
+	}
+
+	if polecatWarmJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else if len(results) == 0 {
+		fmt.Println("No rigs have a warm pool configured (polecat_warm_pool in config.json).")
+	}
+	if failed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
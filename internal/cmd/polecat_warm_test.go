@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+func TestSummarizePolecatInventory(t *testing.T) {
+	items := []PolecatListItem{
+		{Rig: "gastown", Name: "nux", State: polecat.StateWorking, CountsTowardCapacity: true},
+		{Rig: "gastown", Name: "slit", State: polecat.StateIdle, Reusable: true},
+		{Rig: "gastown", Name: "toast", State: polecat.StateIdle, NeedsRecovery: true, CountsTowardCapacity: true},
+		{Rig: "gastown", Name: "ace", State: polecat.StateZombie, Zombie: true},
+		{Rig: "beads", Name: "furiosa", State: polecat.StateIdle, Reusable: true},
+	}
+	inv := PolecatPoolInventory{Rig: "gastown"}
+	summarizePolecatInventory(&inv, items)
+
+	want := PolecatPoolInventory{Rig: "gastown", Dirs: 3, Working: 1, Idle: 1, NeedsRecovery: 1, Zombies: 1}
+	if inv.Dirs != want.Dirs || inv.Working != want.Working || inv.Idle != want.Idle ||
+		inv.NeedsRecovery != want.NeedsRecovery || inv.Zombies != want.Zombies {
+		t.Errorf("inventory = %+v, want %+v", inv, want)
+	}
+}
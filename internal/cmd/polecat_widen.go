@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var polecatWidenTarget string
+
+var polecatWidenCmd = &cobra.Command{
+	Use:   "widen <path>...",
+	Short: "Add paths to a polecat's sparse checkout",
+	Long: `Expand a running polecat's sparse checkout to include more of the repo.
+
+Polecats in monorepos can be narrowed to a sparse profile: a named set of
+directories from sparse_profiles in the rig's config.json, picked per bead
+with a "sparse:<profile>" label or --var sparse_profile=<profile>. When the
+work turns out to need code outside the profile, widen the cone instead of
+restarting. The files appear in place; nothing else in the worktree changes.
+
+Paths are directories. Inside the worktree they are relative to the current
+directory; elsewhere they are relative to the repository root. Run from a
+polecat session, the polecat is taken from GT_RIG/GT_POLECAT.
+
+Examples:
+  gt polecat widen services/billing libs/money
+  gt polecat widen ../shared --polecat gastown/Toast`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPolecatWiden,
+}
+
+func init() {
+	polecatWidenCmd.Flags().StringVar(&polecatWidenTarget, "polecat", "", "Polecat to widen (rig/name); defaults to GT_RIG/GT_POLECAT")
+	polecatCmd.AddCommand(polecatWidenCmd)
+}
+
+func runPolecatWiden(cmd *cobra.Command, args []string) error {
+	address := polecatWidenTarget
+	if address == "" {
+		rigName, name := os.Getenv("GT_RIG"), os.Getenv("GT_POLECAT")
+		if rigName == "" || name == "" {
+			return fmt.Errorf("not in a polecat session; use --polecat <rig>/<name>")
+		}
+		address = rigName + "/" + name
+	}
+	rigName, name, err := parseAddress(address)
+	if err != nil {
+		return err
+	}
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	p, err := mgr.Get(name)
+	if err != nil {
+		return fmt.Errorf("polecat %s/%s: %w", rigName, name, err)
+	}
+
+	paths, err := sparseRepoPaths(p.ClonePath, args)
+	if err != nil {
+		return err
+	}
+	state, err := mgr.WidenSparse(name, paths)
+	if errors.Is(err, polecat.ErrNotSparse) {
+		fmt.Printf("%s/%s already has a full checkout\n", rigName, name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Widened %s/%s (profile %s)\n", style.Success.Render("✓"), rigName, name, style.Bold.Render(state.Profile))
+	fmt.Printf("  Cone: %s\n", strings.Join(state.Cone(), ", "))
+	return nil
+}
+
+// sparseRepoPaths makes widen arguments relative to the repository root.
+// When the current directory is inside the worktree, arguments are taken
+// relative to it, like any other path on the command line.
+func sparseRepoPaths(clonePath string, args []string) ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return args, nil
+	}
+	root, err := filepath.EvalSymlinks(clonePath)
+	if err != nil {
+		root = clonePath
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	if rel, err := filepath.Rel(root, cwd); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return args, nil
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		abs := arg
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(cwd, arg)
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, rel)
+	}
+	return paths, nil
+}
@@ -9,7 +9,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/memory"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
@@ -231,7 +231,7 @@ func runPrime(cmd *cobra.Command, args []string) (retErr error) {
 
 	outputMoleculeContext(ctx)
 	outputCheckpointContext(ctx)
-	runPrimeExternalTools(ctx, cwd)
+	runPrimeExternalTools(ctx, cwd, hookedBead)
 
 	if ctx.Role == RoleMayor {
 		checkPendingEscalations(ctx)
@@ -540,19 +540,20 @@ func outputRoleContext(ctx RoleContext) (string, error) {
 	outputRoleDirectives(ctx, os.Stdout, primeExplain)
 	outputContextFile(ctx)
 	outputHandoffContent(ctx)
+	outputStructuredHandoff(ctx)
 	outputAttachmentStatus(ctx)
 	return formula, nil
 }
 
 // runPrimeExternalTools runs lightweight memory and mail injection.
 // Skipped in dry-run mode with explain output.
-func runPrimeExternalTools(ctx RoleContext, cwd string) {
+func runPrimeExternalTools(ctx RoleContext, cwd string, hookedBead *beads.Issue) {
 	if primeDryRun {
 		explain(true, "memory injection: skipped in dry-run mode")
 		explain(true, "gt mail check --inject: skipped in dry-run mode")
 		return
 	}
-	runMemoryInject(cwd)
+	runMemoryInject(ctx, cwd, hookedBead)
 	if shouldSkipStartupMailInject(string(ctx.Role)) {
 		explain(true, fmt.Sprintf("gt mail check --inject: skipped for patrol role %s", ctx.Role))
 		return
@@ -601,46 +602,51 @@ var memoryTypeLabels = map[string]string{
 	"general":   "General",
 }
 
-// runMemoryInject loads memories from beads kv and outputs them during prime.
-// Memories are grouped by type and ordered by priority (feedback first).
-func runMemoryInject(workDir string) {
+// runMemoryInject loads memories from beads kv, ranks the ones in scope for
+// this session against the hooked bead, and outputs those that fit in the
+// prime token budget.
+func runMemoryInject(ctx RoleContext, workDir string, hookedBead *beads.Issue) {
 	kvs, err := bdKvListJSONForPrime(workDir)
 	if err != nil {
 		return // Silently skip if kv list fails
 	}
-
-	// Group memories by type
-	type mem struct {
-		shortKey string
-		value    string
+	mems := loadMemories(kvs)
+	if len(mems) == 0 {
+		return
 	}
-	grouped := make(map[string][]mem)
 
-	for k, v := range kvs {
-		if !strings.HasPrefix(k, memoryKeyPrefix) {
-			continue
+	budget := config.DefaultMemoryPrimeTokenBudget
+	var usage memory.Usage
+	if ctx.TownRoot != "" {
+		budget = config.LoadOperationalConfig(ctx.TownRoot).GetMemoryConfig().PrimeTokenBudgetV()
+		usage = memory.LoadUsage(memory.UsagePath(ctx.TownRoot))
+	}
+	decisions := memory.Rank(mems, memoryContext(ctx, hookedBead), usage, budget, time.Now())
+
+	// Group injected memories by type, keeping rank order within each group
+	grouped := make(map[string][]memory.Memory)
+	var injected []string
+	overBudget := 0
+	for _, d := range decisions {
+		if d.Injected {
+			grouped[d.Memory.Type] = append(grouped[d.Memory.Type], d.Memory)
+			injected = append(injected, d.Memory.Key)
+		} else if strings.HasPrefix(d.Reason, "over budget") {
+			overBudget++
 		}
-		memType, shortKey := parseMemoryKey(k)
-		grouped[memType] = append(grouped[memType], mem{shortKey: shortKey, value: v})
 	}
-
-	if len(grouped) == 0 {
+	explain(true, fmt.Sprintf("memory injection: %d of %d memories within %d-token budget, %d over budget (see gt memories --explain)",
+		len(injected), len(mems), budget, overBudget))
+	if len(injected) == 0 {
 		return
 	}
 
-	// Sort each group by key
-	for t := range grouped {
-		sort.Slice(grouped[t], func(i, j int) bool {
-			return grouped[t][i].shortKey < grouped[t][j].shortKey
-		})
-	}
-
 	fmt.Println()
 	fmt.Println("# Agent Memories")
 
 	for _, t := range memoryTypeOrder {
-		mems, ok := grouped[t]
-		if !ok || len(mems) == 0 {
+		group, ok := grouped[t]
+		if !ok || len(group) == 0 {
 			continue
 		}
 		label := memoryTypeLabels[t]
@@ -648,10 +654,13 @@ func runMemoryInject(workDir string) {
 			label = t
 		}
 		fmt.Printf("\n## %s\n\n", label)
-		for _, m := range mems {
-			fmt.Printf("- **%s**: %s\n", m.shortKey, m.value)
+		for _, m := range group {
+			fmt.Printf("- **%s**: %s\n", m.ShortKey, m.Text)
 		}
 	}
+	if overBudget > 0 {
+		fmt.Printf("\n_%d more memories left out by the token budget; run `gt memories` to see all._\n", overBudget)
+	}
 }
 
 func bdKvListJSONForPrime(workDir string) (map[string]string, error) {
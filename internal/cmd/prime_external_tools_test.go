@@ -84,7 +84,7 @@ esac
 `)
 
 	start := time.Now()
-	output := captureStdout(t, func() { runPrimeExternalTools(RoleContext{Role: RolePolecat}, workDir) })
+	output := captureStdout(t, func() { runPrimeExternalTools(RoleContext{Role: RolePolecat}, workDir, nil) })
 	assertElapsedUnder(t, time.Since(start), time.Second)
 	assertPrimeToolCalled(t, "bd:kv list --json")
 	assertPrimeToolCalled(t, "gt:mail check --inject")
@@ -119,7 +119,7 @@ esac
 	t.Setenv("PRIME_CHILD_SURVIVED", survivedPath)
 
 	start := time.Now()
-	output := captureStdout(t, func() { runPrimeExternalTools(RoleContext{Role: RolePolecat}, workDir) })
+	output := captureStdout(t, func() { runPrimeExternalTools(RoleContext{Role: RolePolecat}, workDir, nil) })
 	assertElapsedUnder(t, time.Since(start), time.Second)
 	assertPrimeToolCalled(t, "bd:kv list --json")
 	assertPrimeToolCalled(t, "gt:mail check --inject")
@@ -152,7 +152,7 @@ case "$*" in
 esac
 `)
 
-			output := captureStdout(t, func() { runPrimeExternalTools(RoleContext{Role: Role(role)}, workDir) })
+			output := captureStdout(t, func() { runPrimeExternalTools(RoleContext{Role: Role(role)}, workDir, nil) })
 			assertPrimeToolCalled(t, "bd:kv list --json")
 			logData, err := os.ReadFile(os.Getenv("PRIME_TOOL_CALL_LOG"))
 			if err != nil {
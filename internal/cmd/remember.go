@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/memory"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -32,10 +37,19 @@ var memoryTypeOrder = []string{"feedback", "user", "project", "reference", "gene
 
 var rememberKey string
 var rememberType string
+var rememberRig string
+var rememberRole string
+var rememberLabels []string
+
+// memoryScopeRoles are the roles a memory can be scoped to.
+var memoryScopeRoles = []string{"mayor", "deacon", "witness", "refinery", "polecat", "crew", "dog", "boot"}
 
 func init() {
 	rememberCmd.Flags().StringVar(&rememberKey, "key", "", "Explicit key slug (default: auto-generated from content)")
 	rememberCmd.Flags().StringVar(&rememberType, "type", "", "Memory type: feedback, project, user, reference (default: general)")
+	rememberCmd.Flags().StringVar(&rememberRig, "rig", "", "Only inject in this rig")
+	rememberCmd.Flags().StringVar(&rememberRole, "role", "", "Only inject for this role (polecat, crew, witness, ...)")
+	rememberCmd.Flags().StringSliceVar(&rememberLabels, "label", nil, "Only inject when the hooked bead has this label (repeatable)")
 	rememberCmd.GroupID = GroupWork
 	rootCmd.AddCommand(rememberCmd)
 }
@@ -57,11 +71,19 @@ Memory types help organize memories and prioritize injection:
   user       Info about the user's role and preferences
   reference  Pointers to external resources
 
+Memories are town-wide unless scoped. --rig, --role and --label limit
+injection to sessions in that rig, of that role, or hooked to a bead with
+one of the labels; combined, all must match. gt prime ranks the memories
+in scope by relevance to the hooked bead, type and recency, and
+injects the best within its token budget (see 'gt memories --explain').
+
 Examples:
   gt remember "Refinery uses worktree, cannot checkout main"
   gt remember --type feedback "Don't mock the database in integration tests"
   gt remember --type user --key senior-go-dev "User has 10 years Go experience"
-  gt remember --key refinery-worktree "Refinery uses worktree, cannot checkout main"`,
+  gt remember --key refinery-worktree "Refinery uses worktree, cannot checkout main"
+  gt remember --rig gastown --role polecat "Run make generate before go test"
+  gt remember --label frontend "Storybook snapshots live in web/__snapshots__"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRemember,
 }
@@ -83,6 +105,11 @@ func runRemember(cmd *cobra.Command, args []string) error {
 		memType = "general"
 	}
 
+	scope, err := memoryScopeFromFlags(rememberRig, rememberRole, rememberLabels)
+	if err != nil {
+		return err
+	}
+
 	key := rememberKey
 	if key == "" {
 		key = autoKey(content)
@@ -100,7 +127,11 @@ func runRemember(cmd *cobra.Command, args []string) error {
 		verb = "Updated"
 	}
 
-	if err := bdKvSet(fullKey, content); err != nil {
+	value, err := memory.EncodeValue(content, scope, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := bdKvSet(fullKey, value); err != nil {
 		return fmt.Errorf("storing memory: %w", err)
 	}
 
@@ -108,10 +139,62 @@ func runRemember(cmd *cobra.Command, args []string) error {
 	if memType != "general" {
 		displayKey = memType + "/" + key
 	}
-	fmt.Printf("%s %s memory: %s\n", style.Success.Render("✓"), verb, style.Bold.Render(displayKey))
+	fmt.Printf("%s %s memory: %s %s\n", style.Success.Render("✓"), verb, style.Bold.Render(displayKey),
+		style.Dim.Render("("+scope.String()+")"))
 	return nil
 }
 
+// memoryScopeFromFlags validates and builds a memory scope.
+func memoryScopeFromFlags(rig, role string, labels []string) (memory.Scope, error) {
+	scope := memory.Scope{Rig: strings.TrimSpace(rig)}
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role == "polecats" {
+		role = "polecat"
+	}
+	if role != "" && !slices.Contains(memoryScopeRoles, role) {
+		return scope, fmt.Errorf("invalid role %q — valid roles: %s", role, strings.Join(memoryScopeRoles, ", "))
+	}
+	scope.Role = role
+	for _, l := range labels {
+		if l = strings.TrimSpace(l); l != "" && !slices.Contains(scope.Labels, l) {
+			scope.Labels = append(scope.Labels, l)
+		}
+	}
+	return scope, nil
+}
+
+// loadMemories decodes the memory.* entries of a kv listing.
+func loadMemories(kvs map[string]string) []memory.Memory {
+	var mems []memory.Memory
+	for k, v := range kvs {
+		if !strings.HasPrefix(k, memoryKeyPrefix) {
+			continue
+		}
+		memType, shortKey := parseMemoryKey(k)
+		text, scope, created := memory.DecodeValue(v)
+		mems = append(mems, memory.Memory{
+			Key: k, Type: memType, ShortKey: shortKey,
+			Text: text, Scope: scope, Created: created,
+		})
+	}
+	sort.Slice(mems, func(i, j int) bool { return mems[i].Key < mems[j].Key })
+	return mems
+}
+
+// memoryContext is the ranking context for an agent session.
+func memoryContext(ctx RoleContext, hookedBead *beads.Issue) memory.Context {
+	mctx := memory.Context{Rig: ctx.Rig, Role: string(ctx.Role)}
+	if hookedBead != nil {
+		mctx.Bead = &memory.Bead{
+			ID:          hookedBead.ID,
+			Title:       hookedBead.Title,
+			Description: hookedBead.Description,
+			Labels:      hookedBead.Labels,
+		}
+	}
+	return mctx
+}
+
 // parseMemoryKey extracts the type and short key from a full kv key.
 // Handles both typed keys (memory.<type>.<key>) and legacy keys (memory.<key>).
 func parseMemoryKey(kvKey string) (memType, shortKey string) {
@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/memory"
 )
 
 func TestAutoKey(t *testing.T) {
@@ -227,3 +233,59 @@ func TestParseBdKvListJSONMalformed(t *testing.T) {
 		t.Fatal("parseBdKvListJSON() error = nil, want malformed JSON error")
 	}
 }
+
+func TestMemoryScopeFromFlags(t *testing.T) {
+	scope, err := memoryScopeFromFlags(" gastown ", "Polecats", []string{"ui", "", "ui", "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.Rig != "gastown" || scope.Role != "polecat" || strings.Join(scope.Labels, ",") != "ui,api" {
+		t.Errorf("scope = %+v", scope)
+	}
+	if _, err := memoryScopeFromFlags("", "janitor", nil); err == nil {
+		t.Error("expected error for unknown role")
+	}
+}
+
+func TestLoadMemoriesDecodesScopedAndLegacy(t *testing.T) {
+	scoped, err := memory.EncodeValue("run make generate", memory.Scope{Rig: "gastown"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mems := loadMemories(map[string]string{
+		"memory.feedback.generate": scoped,
+		"memory.legacy-note":       "plain text",
+		"other.key":                "ignored",
+	})
+	if len(mems) != 2 {
+		t.Fatalf("got %d memories, want 2", len(mems))
+	}
+	if mems[0].Type != "feedback" || mems[0].Text != "run make generate" || mems[0].Scope.Rig != "gastown" {
+		t.Errorf("scoped memory = %+v", mems[0])
+	}
+	if mems[1].Type != "general" || mems[1].Text != "plain text" || !mems[1].Scope.IsTown() {
+		t.Errorf("legacy memory = %+v", mems[1])
+	}
+}
+
+func TestRecordMemoryLookups(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	recordMemoryLookups([]memory.Memory{{Key: "feedback.generate"}})
+	recordMemoryLookups([]memory.Memory{{Key: "feedback.generate"}, {Key: "legacy-note"}})
+
+	usage := memory.LoadUsage(memory.UsagePath(townRoot))
+	if got := usage["feedback.generate"].Count; got != 2 {
+		t.Errorf("feedback.generate count = %d, want 2", got)
+	}
+	if got := usage["legacy-note"].Count; got != 1 {
+		t.Errorf("legacy-note count = %d, want 1", got)
+	}
+}
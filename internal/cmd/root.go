@@ -77,6 +77,8 @@ var beadsExemptCommands = map[string]bool{
 	"health":        true, // Health check doesn't require beads
 	"upgrade":       true, // Post-install migration orchestrator
 	"heartbeat":     true, // Heartbeat state update — must be fast and dependency-free
+	"sandbox":       true, // Exec wrapper for agent startup; must not depend on beads
+	"cgroup":        true, // Exec wrapper for agent startup; must not depend on beads
 }
 
 // Commands exempt from the town root branch warning.
@@ -93,6 +95,8 @@ var branchCheckExemptCommands = map[string]bool{
 	"git-init":    true, // Git setup
 	"upgrade":     true, // Post-install migration
 	"scheduler":   true, // Daemon hot path; scheduler handles beads internally
+	"sandbox":     true, // Wraps agent startup; warnings would land in the agent pane
+	"cgroup":      true, // Wraps agent startup; warnings would land in the agent pane
 }
 
 // persistentPreRun runs before every command.
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	sandboxProfile  string
+	sandboxWorktree string
+	sandboxWritable []string
+	sandboxPorts    []int
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:     "sandbox",
+	GroupID: GroupServices,
+	Short:   "Run agents in the built-in Linux sandbox",
+	RunE:    requireSubcommand,
+	Long: `Run agents under Linux user, mount and network namespaces plus Landlock.
+
+The sandbox restricts writes to the agent's worktree and tmp, and limits
+network access to the loopback ports the control plane needs (Dolt and
+the proxy). Reads are not restricted.
+
+Select a profile per rig in settings/config.json:
+
+  "sandbox": {"profile": "polecat"}
+
+Polecats in that rig then start under 'gt sandbox exec'. On macOS use an
+exec-wrapper plugin such as exitbox instead.`,
+}
+
+var sandboxExecCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command inside the sandbox",
+	Long: `Run a command inside the sandbox and exit with its status.
+
+The worktree defaults to the current directory. Under the polecat profile
+the Dolt port (GT_DOLT_PORT, default 3307) and a loopback GT_PROXY_URL port
+are always reachable; --allow-port adds more.
+
+Exit status 125 means the sandbox itself could not be set up.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runSandboxExec,
+}
+
+var sandboxCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check whether this kernel supports the sandbox",
+	Args:  cobra.NoArgs,
+	RunE:  runSandboxCheck,
+}
+
+var sandboxProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List built-in sandbox profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runSandboxProfiles,
+}
+
+func init() {
+	sandboxExecCmd.Flags().StringVar(&sandboxProfile, "profile", sandbox.DefaultProfile, "Sandbox profile")
+	sandboxExecCmd.Flags().StringVar(&sandboxWorktree, "worktree", "", "Writable worktree (default: current directory)")
+	sandboxExecCmd.Flags().StringArrayVar(&sandboxWritable, "allow-write", nil, "Extra writable path (repeatable)")
+	sandboxExecCmd.Flags().IntSliceVar(&sandboxPorts, "allow-port", nil, "Extra reachable loopback port (repeatable)")
+	// Everything from the command onward belongs to the command.
+	sandboxExecCmd.Flags().SetInterspersed(false)
+
+	sandboxCmd.AddCommand(sandboxExecCmd, sandboxCheckCmd, sandboxProfilesCmd)
+	rootCmd.AddCommand(sandboxCmd)
+}
+
+func runSandboxExec(cmd *cobra.Command, args []string) error {
+	profile, err := sandbox.LookupProfile(sandboxProfile)
+	if err != nil {
+		return err
+	}
+	ports := append(sandbox.DefaultPorts(os.Getenv), sandboxPorts...)
+	code, err := sandbox.Exec(sandbox.Options{
+		Profile:  profile,
+		Worktree: sandboxWorktree,
+		Writable: sandboxWritable,
+		Ports:    ports,
+		Command:  args,
+	})
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		// The command's own output already explains its failure.
+		cmd.SilenceErrors = true
+		return NewSilentExit(code)
+	}
+	return nil
+}
+
+func runSandboxCheck(cmd *cobra.Command, args []string) error {
+	if err := sandbox.Check(); err != nil {
+		if errors.Is(err, sandbox.ErrUnsupported) {
+			fmt.Printf("%s %v\n", style.Error.Render("✗"), err)
+			cmd.SilenceErrors = true
+			return NewSilentExit(1)
+		}
+		return err
+	}
+	fmt.Printf("%s Sandbox supported (Landlock ABI %d, user namespaces available)\n",
+		style.Success.Render("✓"), sandbox.LandlockABI())
+	return nil
+}
+
+func runSandboxProfiles(cmd *cobra.Command, args []string) error {
+	for _, name := range sandbox.ProfileNames() {
+		p, _ := sandbox.LookupProfile(name)
+		marker := " "
+		if name == sandbox.DefaultProfile {
+			marker = "*"
+		}
+		fmt.Printf("%s %-10s %s\n", marker, style.Bold.Render(name), style.Dim.Render(p.Description))
+	}
+	return nil
+}
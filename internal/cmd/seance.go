@@ -28,6 +28,10 @@ var (
 	seanceTalk   string
 	seancePrompt string
 	seanceJSON   bool
+
+	seanceAgent          string
+	seanceTranscript     bool
+	seanceShowTranscript bool
 )
 
 var seanceCmd = &cobra.Command{
@@ -57,6 +61,16 @@ THE SEANCE (talk to predecessor):
 The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
+AGENTS WITHOUT --fork-session (codex, gemini, opencode, ...):
+  gt seance --talk <id> --agent codex        # Talk through another agent
+  gt seance --talk <id> --show-transcript    # Print the reconstruction
+
+When the chosen agent cannot fork sessions, or the predecessor was not a
+Claude Code session, seance rebuilds the predecessor instead: its
+session_start event, checkpoint, Gas Town events, and a condensed
+transcript of its conversation (or of its recorded tool calls) are fed to
+the agent as its first prompt. --transcript forces this for Claude too.
+
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
   2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
@@ -70,6 +84,9 @@ func init() {
 	seanceCmd.Flags().StringVarP(&seanceTalk, "talk", "t", "", "Session ID to commune with")
 	seanceCmd.Flags().StringVarP(&seancePrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
 	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
+	seanceCmd.Flags().StringVar(&seanceAgent, "agent", "", "Agent preset to hold the seance with (default: fork with Claude, else the town default)")
+	seanceCmd.Flags().BoolVar(&seanceTranscript, "transcript", false, "Reconstruct the predecessor from Gas Town records instead of forking its session")
+	seanceCmd.Flags().BoolVar(&seanceShowTranscript, "show-transcript", false, "Print the reconstructed transcript and exit (with --talk)")
 
 	rootCmd.AddCommand(seanceCmd)
 }
@@ -194,6 +211,11 @@ func runSeanceList() error {
 // resolveSeanceCommand finds the command for an agent that supports --fork-session.
 // Returns the resolved command path, or error if no agent supports fork session.
 func resolveSeanceCommand() (string, error) {
+	if seanceAgent != "" {
+		if preset := config.GetAgentPresetByName(seanceAgent); preset != nil && preset.SupportsForkSession {
+			return config.RuntimeConfigFromPreset(preset.Name).Command, nil
+		}
+	}
 	for _, name := range config.ListAgentPresets() {
 		preset := config.GetAgentPresetByName(name)
 		if preset != nil && preset.SupportsForkSession {
@@ -205,13 +227,33 @@ func resolveSeanceCommand() (string, error) {
 	return "", fmt.Errorf("no agent supports fork session (seance requires --fork-session)")
 }
 
-func runSeanceTalk(sessionID, prompt string) error {
-	// Resolve the agent command that supports fork session
-	agentCmd, err := resolveSeanceCommand()
-	if err != nil {
-		return err
+// seanceNeedsTranscript decides whether a seance must reconstruct the
+// predecessor rather than fork its session, and why (empty when asked for).
+func seanceNeedsTranscript(townRoot, sessionID string) (bool, string) {
+	if seanceTranscript || seanceShowTranscript {
+		return true, ""
+	}
+	if seanceAgent != "" {
+		if preset := config.GetAgentPresetByName(seanceAgent); preset == nil || !preset.SupportsForkSession {
+			return true, ""
+		}
+	}
+	if _, err := resolveSeanceCommand(); err != nil {
+		return true, "No agent supports --fork-session; reconstructing the session from Gas Town records."
 	}
+	// Only fall back when there is something to rebuild from; otherwise
+	// let the resume attempt report what is wrong.
+	if townRoot != "" && findSessionLocation(townRoot, sessionID) == nil {
+		if sessions, err := discoverSessions(townRoot); err == nil {
+			if _, ok := findSeanceWindow(sessions, sessionID); ok {
+				return true, "No Claude Code log found for this session; reconstructing it from Gas Town records."
+			}
+		}
+	}
+	return false, ""
+}
 
+func runSeanceTalk(sessionID, prompt string) error {
 	// Clean up any orphaned symlinks from previous interrupted sessions
 	cleanupOrphanedSessionSymlinks()
 
@@ -231,6 +273,18 @@ func runSeanceTalk(sessionID, prompt string) error {
 		}
 	}
 
+	if reconstruct, reason := seanceNeedsTranscript(townRoot, sessionID); reconstruct {
+		if reason != "" {
+			fmt.Printf("%s\n", style.Dim.Render(reason))
+		}
+		return runSeanceTranscript(townRoot, sessionID, prompt, seanceAgent)
+	}
+
+	agentCmd, err := resolveSeanceCommand()
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
 	cleanup, err := symlinkSessionToCurrentAccount(townRoot, sessionID)
 	if err != nil {
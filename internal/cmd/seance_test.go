@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 )
@@ -506,3 +507,77 @@ func TestResolveSessionPrefix(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildSeanceTranscript(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	eventLines := []string{
+		`{"ts":"2026-01-22T10:00:00Z","type":"session_start","actor":"gastown/polecats/Toast","payload":{"session_id":"codex-1","cwd":"/nonexistent"}}`,
+		`{"ts":"2026-01-22T10:20:00Z","type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc","branch":"polecat/Toast"}}`,
+		`{"ts":"2026-01-22T10:25:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-other"}}`,
+		`{"ts":"2026-01-22T11:00:00Z","type":"session_start","actor":"gastown/polecats/Toast","payload":{"session_id":"codex-2"}}`,
+		`{"ts":"2026-01-22T11:10:00Z","type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-later"}}`,
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(eventLines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	recDir := checkpoint.RecordingsDir(filepath.Join(townRoot, "gastown"), "Toast")
+	if err := os.MkdirAll(recDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	step := `{"seq":0,"time":"2026-01-22T10:05:00Z","tool":"Bash","input":"go test ./billing/...","output":"ok","parent":"a","tree":"a"}`
+	if err := os.WriteFile(filepath.Join(recDir, "1769076300-gt-gastown-Toast.jsonl"), []byte(step+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	transcript, err := buildSeanceTranscript(townRoot, "codex-1")
+	if err != nil {
+		t.Fatalf("buildSeanceTranscript: %v", err)
+	}
+	for _, want := range []string{"Agent: gastown/polecats/Toast", "done bead=gt-abc", "[tool] Bash: go test ./billing/...", "recorded tool calls"} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript missing %q:\n%s", want, transcript)
+		}
+	}
+	for _, unwanted := range []string{"gt-other", "gt-later"} {
+		if strings.Contains(transcript, unwanted) {
+			t.Errorf("transcript should not include %q (other actor or later session)", unwanted)
+		}
+	}
+
+	if _, err := buildSeanceTranscript(townRoot, "unknown"); err == nil {
+		t.Error("expected error for a session missing from events")
+	}
+}
+
+func TestSeanceAgentArgs(t *testing.T) {
+	tests := []struct {
+		agent   string
+		oneShot bool
+		want    []string
+	}{
+		{"codex", true, []string{"exec", "PROMPT"}},
+		{"gemini", true, []string{"-p", "PROMPT"}},
+		{"claude", true, []string{"-p", "PROMPT"}},
+		{"codex", false, []string{"PROMPT"}},
+	}
+	for _, tt := range tests {
+		preset := config.GetAgentPresetByName(tt.agent)
+		if preset == nil {
+			t.Fatalf("no preset %s", tt.agent)
+		}
+		got, err := seanceAgentArgs(preset, "PROMPT", tt.oneShot)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.agent, err)
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s oneShot=%v: args = %v, want %v", tt.agent, tt.oneShot, got, tt.want)
+		}
+	}
+
+	if _, err := seanceAgentArgs(&config.AgentPresetInfo{Name: "quiet", PromptMode: "none"}, "PROMPT", false); err == nil {
+		t.Error("expected error for an agent that takes no initial prompt")
+	}
+}
@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// seanceTranscriptBudget caps the reconstructed transcript. It is passed
+// as a single argv entry, so it must stay well under the kernel's per-arg
+// limit (128 KiB on Linux) and leave room in the agent's context.
+const seanceTranscriptBudget = 48 * 1024
+
+// seanceMaxTownEvents caps the Gas Town events listed in a transcript.
+const seanceMaxTownEvents = 60
+
+// seanceTranscriptPreamble frames the reconstructed transcript for the
+// agent standing in for the predecessor.
+const seanceTranscriptPreamble = `You are standing in for a previous Gas Town agent session so the user can question it.
+The session itself cannot be resumed, so below is a reconstruction of it: who it was,
+what it was working on, the Gas Town events it emitted, and a condensed transcript of
+its conversation and tool calls. Answer as that session, from this record. When the
+record does not cover something, say so rather than guessing.`
+
+// seanceWindow is a predecessor session as found in the event stream.
+type seanceWindow struct {
+	SessionID string
+	Actor     string
+	WorkDir   string
+	Topic     string
+	Start     time.Time
+	End       time.Time // zero if no later session of the same actor was found
+}
+
+// contains reports whether t falls inside the session.
+func (w seanceWindow) contains(t time.Time) bool {
+	if t.Before(w.Start) {
+		return false
+	}
+	return w.End.IsZero() || t.Before(w.End)
+}
+
+// findSeanceWindow locates a session in the session_start events. A session
+// spans from its first session_start to the next session_start of the same
+// actor under a different session ID.
+func findSeanceWindow(sessions []sessionEvent, sessionID string) (*seanceWindow, bool) {
+	var w *seanceWindow
+	var last time.Time
+	for _, s := range sessions {
+		if getPayloadString(s.Payload, "session_id") != sessionID {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, s.Timestamp)
+		if err != nil {
+			continue
+		}
+		if w == nil {
+			w = &seanceWindow{SessionID: sessionID, Actor: s.Actor, Start: ts}
+		}
+		if ts.Before(w.Start) {
+			w.Start = ts
+		}
+		if ts.After(last) {
+			last = ts
+		}
+		if cwd := getPayloadString(s.Payload, "cwd"); cwd != "" {
+			w.WorkDir = cwd
+		}
+		if topic := getPayloadString(s.Payload, "topic"); topic != "" {
+			w.Topic = topic
+		}
+	}
+	if w == nil {
+		return nil, false
+	}
+	for _, s := range sessions {
+		if s.Actor != w.Actor || getPayloadString(s.Payload, "session_id") == sessionID {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, s.Timestamp)
+		if err != nil || !ts.After(last) {
+			continue
+		}
+		if w.End.IsZero() || ts.Before(w.End) {
+			w.End = ts
+		}
+	}
+	return w, true
+}
+
+// buildSeanceTranscript reconstructs a predecessor session from what Gas
+// Town keeps about it, for agents that cannot fork the session itself:
+//   - the session_start event (who, where, when)
+//   - the polecat checkpoint in its working directory, if still there
+//   - the Gas Town events its actor emitted during the session
+//   - its conversation: the agent's own log where an agentlog reader exists
+//     (Claude Code), else the tool calls recorded by 'gt tap record'
+func buildSeanceTranscript(townRoot, sessionID string) (string, error) {
+	sessions, err := discoverSessions(townRoot)
+	if err != nil {
+		return "", fmt.Errorf("discovering sessions: %w", err)
+	}
+	w, ok := findSeanceWindow(sessions, sessionID)
+	if !ok {
+		return "", fmt.Errorf("session %s not found in %s", sessionID, events.EventsFile)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Session %s\n\n", sessionID)
+	fmt.Fprintf(&b, "Agent: %s\n", w.Actor)
+	fmt.Fprintf(&b, "Started: %s\n", w.Start.Local().Format("2006-01-02 15:04"))
+	if !w.End.IsZero() {
+		fmt.Fprintf(&b, "Succeeded by another session at: %s\n", w.End.Local().Format("2006-01-02 15:04"))
+	}
+	if w.WorkDir != "" {
+		fmt.Fprintf(&b, "Working directory: %s\n", w.WorkDir)
+	}
+	if w.Topic != "" {
+		fmt.Fprintf(&b, "Topic: %s\n", w.Topic)
+	}
+
+	if w.WorkDir != "" {
+		if cp, err := checkpoint.Read(w.WorkDir); err == nil && cp != nil {
+			b.WriteString("\n## Checkpoint\n\n")
+			if cp.SessionID != "" && cp.SessionID != sessionID {
+				fmt.Fprintf(&b, "(written by session %s, which may be a successor)\n", cp.SessionID)
+			}
+			fmt.Fprintf(&b, "As of %s: %s\n", cp.Timestamp.Local().Format("2006-01-02 15:04"), cp.Summary())
+			if cp.StepTitle != "" {
+				fmt.Fprintf(&b, "Current step: %s\n", cp.StepTitle)
+			}
+			if len(cp.ModifiedFiles) > 0 {
+				fmt.Fprintf(&b, "Modified files: %s\n", strings.Join(cp.ModifiedFiles, ", "))
+			}
+			if cp.Notes != "" {
+				fmt.Fprintf(&b, "Notes: %s\n", cp.Notes)
+			}
+		}
+	}
+
+	if lines := seanceTownEvents(townRoot, w); len(lines) > 0 {
+		b.WriteString("\n## Gas Town events\n\n")
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n")
+	}
+
+	conversation, source := seanceConversation(townRoot, w)
+	b.WriteString("\n## Conversation (condensed")
+	if source != "" {
+		b.WriteString(", from " + source)
+	}
+	b.WriteString(")\n\n")
+	if len(conversation) == 0 {
+		b.WriteString("No conversation log or tool-call recording was found for this session.\n")
+		return b.String(), nil
+	}
+	remaining := seanceTranscriptBudget - b.Len()
+	b.WriteString(agentlog.CondenseTranscript(conversation, remaining))
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// seanceTownEvents returns the session's Gas Town events, one line each,
+// most recent last.
+func seanceTownEvents(townRoot string, w *seanceWindow) []string {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev sessionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		if ev.Actor != w.Actor || ev.Type == events.TypeSessionStart {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+		if err != nil || !w.contains(ts) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s %s", ts.Local().Format("15:04"), ev.Type, formatSeancePayload(ev.Payload)))
+	}
+	if len(lines) > seanceMaxTownEvents {
+		lines = lines[len(lines)-seanceMaxTownEvents:]
+	}
+	return lines
+}
+
+// formatSeancePayload renders an event payload as sorted key=value pairs.
+func formatSeancePayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, payload[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// seanceConversation returns the session's conversation as agentlog events
+// and where it came from.
+func seanceConversation(townRoot string, w *seanceWindow) ([]agentlog.AgentEvent, string) {
+	if loc := findSessionLocation(townRoot, w.SessionID); loc != nil {
+		path := filepath.Join(loc.configDir, "projects", loc.projectDir, w.SessionID+".jsonl")
+		if evs, err := agentlog.ReadClaudeCodeSession(path, w.SessionID); err == nil && len(evs) > 0 {
+			return evs, "the Claude Code session log"
+		}
+	}
+
+	// Polecats record their tool calls whatever the agent; see gt tap record.
+	parts := strings.Split(w.Actor, "/")
+	if len(parts) != 3 || parts[1] != "polecats" {
+		return nil, ""
+	}
+	recs, err := checkpoint.ListRecordings(checkpoint.RecordingsDir(filepath.Join(townRoot, parts[0]), parts[2]))
+	if err != nil {
+		return nil, ""
+	}
+	for _, rec := range recs {
+		loaded, err := checkpoint.LoadRecording(rec.Path)
+		if err != nil || len(loaded.Steps) == 0 {
+			continue
+		}
+		if !seanceRecordingMatches(loaded, w) {
+			continue
+		}
+		return recordingEvents(loaded, w.SessionID), "recorded tool calls"
+	}
+	return nil, ""
+}
+
+// seanceRecordingMatches reports whether a recording belongs to the session:
+// by the agent's session ID when the hook reported one, else by time.
+func seanceRecordingMatches(rec *checkpoint.Recording, w *seanceWindow) bool {
+	for _, s := range rec.Steps {
+		if s.NativeSession != "" {
+			return s.NativeSession == w.SessionID
+		}
+	}
+	return w.contains(rec.Steps[0].Time)
+}
+
+// recordingEvents converts recorded tool calls to agentlog events.
+func recordingEvents(rec *checkpoint.Recording, sessionID string) []agentlog.AgentEvent {
+	evs := make([]agentlog.AgentEvent, 0, 2*len(rec.Steps))
+	for _, s := range rec.Steps {
+		evs = append(evs, agentlog.AgentEvent{
+			SessionID: sessionID,
+			EventType: "tool_use",
+			Role:      "assistant",
+			Content:   s.Tool + ": " + s.Input,
+			Timestamp: s.Time,
+		})
+		if s.Output != "" {
+			evs = append(evs, agentlog.AgentEvent{
+				SessionID: sessionID,
+				EventType: "tool_result",
+				Role:      "user",
+				Content:   s.Output,
+				Timestamp: s.Time,
+			})
+		}
+	}
+	return evs
+}
+
+// resolveSeanceAgent picks the preset a reconstructed seance runs on: the
+// named one, else the town's default agent.
+func resolveSeanceAgent(townRoot, name string) (*config.AgentPresetInfo, error) {
+	if townRoot != "" {
+		_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot))
+		if name == "" {
+			if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+				name = settings.DefaultAgent
+			}
+		}
+	}
+	if name == "" {
+		name = string(config.DefaultAgentPreset())
+	}
+	preset := config.GetAgentPresetByName(name)
+	if preset == nil {
+		return nil, fmt.Errorf("unknown agent %q; available: %s", name, strings.Join(config.ListAgentPresets(), ", "))
+	}
+	return preset, nil
+}
+
+// seanceAgentArgs builds the arguments that start preset with text as its
+// first prompt. One-shot runs use the agent's non-interactive mode when it
+// has one, so it prints an answer and exits.
+func seanceAgentArgs(preset *config.AgentPresetInfo, text string, oneShot bool) ([]string, error) {
+	if oneShot {
+		if ni := preset.NonInteractive; ni != nil {
+			switch {
+			case ni.Subcommand != "":
+				return []string{ni.Subcommand, text}, nil
+			case ni.PromptFlag != "":
+				return []string{ni.PromptFlag, text}, nil
+			}
+		} else if preset.Name == config.AgentClaude {
+			return []string{"-p", text}, nil
+		}
+	}
+	if preset.PromptMode == "none" {
+		return nil, fmt.Errorf("agent %s does not accept an initial prompt; pick another with --agent", preset.Name)
+	}
+	return []string{text}, nil
+}
+
+// runSeanceTranscript holds a seance on any agent by feeding it a
+// reconstruction of the predecessor instead of forking its session.
+func runSeanceTranscript(townRoot, sessionID, prompt, agentName string) error {
+	if townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+	transcript, err := buildSeanceTranscript(townRoot, sessionID)
+	if err != nil {
+		return err
+	}
+	if seanceShowTranscript {
+		fmt.Print(transcript)
+		return nil
+	}
+
+	preset, err := resolveSeanceAgent(townRoot, agentName)
+	if err != nil {
+		return err
+	}
+	text := seanceTranscriptPreamble + "\n\n" + transcript
+	if prompt != "" {
+		text += "\n\nQuestion: " + prompt
+	} else {
+		text += "\n\nAcknowledge briefly and wait for questions."
+	}
+	args, err := seanceAgentArgs(preset, text, prompt != "")
+	if err != nil {
+		return err
+	}
+
+	agentCmd := config.RuntimeConfigFromPreset(preset.Name).Command
+	fmt.Printf("%s Reconstructing session %s for %s...\n\n", style.Bold.Render("🔮"), sessionID, preset.Name)
+
+	cmd := exec.Command(agentCmd, args...)
+	cmd.Env = clearClaudeCodeEnv(os.Environ())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if prompt != "" {
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("seance failed: %w", err)
+		}
+		return nil
+	}
+
+	cmd.Stdin = os.Stdin
+	fmt.Printf("%s\n", style.Dim.Render("You are now talking to a reconstruction of your predecessor. Ask them anything."))
+	fmt.Printf("%s\n\n", style.Dim.Render("Exit with the agent's quit command or Ctrl+C"))
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if exitErr.ExitCode() == 0 || exitErr.ExitCode() == 130 {
+				return nil
+			}
+		}
+		return fmt.Errorf("seance ended: %w", err)
+	}
+	return nil
+}
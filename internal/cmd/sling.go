@@ -710,17 +710,18 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		target = args[1]
 	}
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
-		DryRun:       slingDryRun,
-		Force:        force,
-		Create:       slingCreate,
-		Account:      slingAccount,
-		Agent:        slingAgent,
-		NoBoot:       slingNoBoot,
-		HookBead:     beadID,
-		BeadID:       beadID,
-		TownRoot:     townRoot,
-		BaseBranch:   slingBaseBranch,
-		ResumeBranch: slingResumeBranch,
+		DryRun:        slingDryRun,
+		Force:         force,
+		Create:        slingCreate,
+		Account:       slingAccount,
+		Agent:         slingAgent,
+		NoBoot:        slingNoBoot,
+		HookBead:      beadID,
+		BeadID:        beadID,
+		TownRoot:      townRoot,
+		BaseBranch:    slingBaseBranch,
+		ResumeBranch:  slingResumeBranch,
+		SparseProfile: slingSparseProfile(slingVars, info),
 	})
 	if err != nil {
 		return err
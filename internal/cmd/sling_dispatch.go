@@ -234,13 +234,14 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 
 	// 3. Spawn polecat (via spawnPolecatForSling)
 	spawnOpts := SlingSpawnOptions{
-		TownRoot:     townRoot,
-		Force:        params.Force,
-		Account:      params.Account,
-		HookBead:     params.BeadID,
-		Agent:        params.Agent,
-		BaseBranch:   params.BaseBranch,
-		ResumeBranch: params.ResumeBranch,
+		TownRoot:      townRoot,
+		Force:         params.Force,
+		Account:       params.Account,
+		HookBead:      params.BeadID,
+		Agent:         params.Agent,
+		BaseBranch:    params.BaseBranch,
+		ResumeBranch:  params.ResumeBranch,
+		SparseProfile: slingSparseProfile(params.Vars, info),
 		// Create is always true for rig targets: executeSling only handles
 		// rig-targeted dispatch (batch sling + queue dispatch), where a fresh
 		// polecat must be spawned. The single-sling path (runSling) handles
@@ -20,6 +20,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/polecat"
 	rigpkg "github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -100,6 +101,18 @@ type beadInfo struct {
 	IssueType    string           `json:"issue_type,omitempty"`
 }
 
+// slingSparseProfile picks the sparse profile for a polecat spawned for a
+// bead: the sparse_profile formula var, else the bead's sparse:<name> label.
+func slingSparseProfile(vars []string, info *beadInfo) string {
+	if profile := polecat.SparseProfileFromVars(vars); profile != "" {
+		return profile
+	}
+	if info == nil {
+		return ""
+	}
+	return polecat.SparseProfileFromLabels(info.Labels)
+}
+
 // isDeferredBead checks whether a bead should be rejected from slinging because
 // it has been deferred. Returns true if the bead has status "deferred" or if its
 // description contains deferral keywords like "deferred to post-launch".
@@ -110,6 +110,9 @@ func TestWakeRigAgentsDoesNotNudgeRefinery(t *testing.T) {
 func TestNudgeRefineryNoOpWithoutLog(t *testing.T) {
 	// Ensure test log is NOT set so we exercise the real tmux path
 	t.Setenv("GT_TEST_NUDGE_LOG", "")
+	// The fallback refinery event goes to the town found from the working
+	// directory; keep it out of the source tree.
+	t.Chdir(t.TempDir())
 
 	// Should not panic even though no tmux session exists
 	nudgeRefinery("nonexistent-rig", "test message")
@@ -142,6 +145,19 @@ func TestIsDeferredBead(t *testing.T) {
 	}
 }
 
+func TestSlingSparseProfile(t *testing.T) {
+	labeled := &beadInfo{Labels: []string{"gt:task", "sparse:web"}}
+	if got := slingSparseProfile(nil, labeled); got != "web" {
+		t.Errorf("from label = %q, want web", got)
+	}
+	if got := slingSparseProfile([]string{"sparse_profile=api"}, labeled); got != "api" {
+		t.Errorf("var over label = %q, want api", got)
+	}
+	if got := slingSparseProfile(nil, nil); got != "" {
+		t.Errorf("no bead = %q, want none", got)
+	}
+}
+
 func TestCollectExistingMoleculesFiltersClosedMolecules(t *testing.T) {
 	tests := []struct {
 		name string
@@ -15,6 +15,7 @@ flow to guard, audit, inject, or check.
 
 Subcommands:
   guard   - Block forbidden operations (PreToolUse, exit 2)
+  record  - Record polecat tool calls for replay (PostToolUse)
   audit   - Log/record tool executions (PostToolUse) [planned]
   inject  - Modify tool inputs (PreToolUse, updatedInput) [planned]
   check   - Validate after execution (PostToolUse) [planned]
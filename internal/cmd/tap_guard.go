@@ -22,7 +22,8 @@ Available guards:
   pr-workflow        - Block PR creation and feature branches
   bd-init            - Block bd init in wrong directories
   mol-patrol         - Block mol patrol from agent contexts
-  dangerous-command  - Block rm -rf, force push, hard reset, git clean
+  dangerous-command  - Block sudo, package installs, rm -rf /, force push, ...
+                       (policy in settings/guard-policy.json; see 'guard test')
 
 External guards (standalone scripts, not compiled into gt):
   context-budget   - scripts/guards/context-budget-guard.sh
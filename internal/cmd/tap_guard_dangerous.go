@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/guard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var tapGuardDangerousCmd = &cobra.Command{
@@ -15,8 +19,15 @@ var tapGuardDangerousCmd = &cobra.Command{
 	Short: "Block dangerous commands (sudo, package installs, rm -rf, force push, etc.)",
 	Long: `Block dangerous commands via Claude Code PreToolUse hooks.
 
-This guard blocks operations that could cause irreversible damage:
-  - sudo <anything>      (agents must never elevate privileges)
+The command is parsed as a shell script and every simple command in it is
+checked: pipelines, lists, subshells, command substitution, bash -c and
+eval strings, xargs and find -exec targets, and commands behind env, sudo,
+timeout, nohup and similar wrappers. Variables assigned literal values
+earlier in the script are substituted, so "X=rm; $X -rf /" is seen as rm.
+Words that merely appear in arguments ("echo sudo") are not commands.
+
+Built-in rules block operations that could cause irreversible damage:
+  - sudo/doas <anything>  (agents must never elevate privileges)
   - apt/apt-get/dnf/yum/pacman install (system package managers)
   - brew install          (Homebrew package installs)
   - pip install --system  (system-level Python installs)
@@ -26,8 +37,26 @@ This guard blocks operations that could cause irreversible damage:
   - git push --force/-f  (--force-with-lease is allowed)
   - git reset --hard
   - git clean -f / git clean -fd
-  - drop table/database
-  - truncate table
+  - drop table/database, truncate table (in SQL clients: psql, mysql, bd, ...)
+
+Commands that run a script the guard can't read (eval "$CMD",
+curl ... | sh) and commands that don't parse are blocked too.
+
+Towns and rigs add rules in settings/guard-policy.json. Rules are checked
+rig first, then town, then built-in; the first rule that applies to the
+agent's role and matches a command decides it:
+
+  {
+    "type": "guard-policy",
+    "version": 1,
+    "rules": [
+      {"action": "allow", "roles": ["crew"], "commands": ["git"], "args": ["reset", "--hard"]},
+      {"id": "no-deploy", "action": "deny", "commands": ["kubectl"], "args": ["apply"],
+       "reason": "deploys go through the release pipeline"}
+    ]
+  }
+
+Use 'gt tap guard test' to see how a command would be decided.
 
 The guard reads the tool input from stdin (Claude Code hook protocol)
 and exits with code 2 to block dangerous operations.
@@ -35,33 +64,45 @@ and exits with code 2 to block dangerous operations.
 Exit codes:
   0 - Operation allowed
   2 - Operation BLOCKED`,
-	RunE: runTapGuardDangerous,
+	RunE:          runTapGuardDangerous,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
-func init() {
-	tapGuardCmd.AddCommand(tapGuardDangerousCmd)
-}
+var (
+	tapGuardTestRole string
+	tapGuardTestRig  string
+	tapGuardTestJSON bool
+)
 
-// dangerousPattern defines a pattern to match and its human-readable reason.
-// All substrings must appear in the command (simple containment check).
-// For patterns that need smarter matching (rm -rf, git push --force),
-// use the dedicated match functions instead.
-type dangerousPattern struct {
-	contains []string
-	reason   string
-}
+var tapGuardTestCmd = &cobra.Command{
+	Use:   "test <command>",
+	Short: "Explain how the dangerous-command guard decides a command",
+	Long: `Show every simple command the dangerous-command guard finds in a shell
+command line, and the rule and policy file that decide each one.
+
+The role and rig default to the current agent's (GT_ROLE, GT_RIG).
 
-// fragmentPatterns use simple containment matching (all substrings must appear).
-var fragmentPatterns = []dangerousPattern{
-	{[]string{"git", "reset", "--hard"}, "Hard reset discards all uncommitted changes irreversibly"},
-	{[]string{"git", "clean", "-f"}, "git clean -f deletes untracked files irreversibly"},
-	{[]string{"drop", "table"}, "database table destruction"},
-	{[]string{"drop", "database"}, "database destruction"},
-	{[]string{"truncate", "table"}, "database table truncation"},
+Examples:
+  gt tap guard test 'bash -c "git push -f origin main"'
+  gt tap guard test 'find . -name "*.tmp" | xargs rm' --role polecat
+  gt tap guard test 'git reset --hard' --role crew --rig gastown --json
+
+Exit codes:
+  0 - The command would be allowed
+  2 - The command would be BLOCKED`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runTapGuardTest,
+	SilenceUsage: true,
 }
 
-// safeForceFlags are git push flags that look like --force but are safe.
-var safeForceFlags = []string{"--force-with-lease", "--force-if-includes"}
+func init() {
+	tapGuardTestCmd.Flags().StringVar(&tapGuardTestRole, "role", "", "Role to evaluate as (polecat, crew, witness, ...)")
+	tapGuardTestCmd.Flags().StringVar(&tapGuardTestRig, "rig", "", "Rig whose policy applies")
+	tapGuardTestCmd.Flags().BoolVar(&tapGuardTestJSON, "json", false, "Output as JSON")
+	tapGuardCmd.AddCommand(tapGuardDangerousCmd)
+	tapGuardCmd.AddCommand(tapGuardTestCmd)
+}
 
 func runTapGuardDangerous(cmd *cobra.Command, args []string) error {
 	// Read hook input from stdin (Claude Code protocol)
@@ -75,37 +116,119 @@ func runTapGuardDangerous(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	lower := strings.ToLower(command)
-
-	// Check privilege escalation and package manager commands first
-	if reason := matchesSudo(lower); reason != "" {
-		printDangerousBlock(reason, command)
+	role, rigName := guardIdentity()
+	ev, err := guardEvaluator(rigName)
+	if err != nil {
+		// A broken policy must not silently disable the guard.
+		printDangerousBlock(err.Error(), command)
 		return NewSilentExit(2)
 	}
-	if reason := matchesPackageInstall(lower); reason != "" {
-		printDangerousBlock(reason, command)
+	if d := ev.Evaluate(command, role); !d.Allowed {
+		printDangerousBlock(d.Reason, command)
 		return NewSilentExit(2)
 	}
+	return nil
+}
 
-	// Check special patterns that need smarter matching
-	if reason := matchesDangerousRmRf(lower); reason != "" {
-		printDangerousBlock(reason, command)
-		return NewSilentExit(2)
+func runTapGuardTest(cmd *cobra.Command, args []string) error {
+	role, rigName := guardIdentity()
+	if tapGuardTestRole != "" {
+		role = tapGuardTestRole
 	}
-	if reason := matchesDangerousGitPush(lower); reason != "" {
-		printDangerousBlock(reason, command)
+	if tapGuardTestRig != "" {
+		rigName = tapGuardTestRig
+	}
+	ev, err := guardEvaluator(rigName)
+	if err != nil {
+		return err
+	}
+	d := ev.Evaluate(args[0], role)
+
+	if tapGuardTestJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	} else {
+		printGuardDecision(args[0], role, rigName, ev, d)
+	}
+	if !d.Allowed {
+		cmd.SilenceErrors = true
 		return NewSilentExit(2)
 	}
+	return nil
+}
 
-	// Check simple fragment patterns
-	for _, pattern := range fragmentPatterns {
-		if matchesAllFragments(lower, pattern.contains) {
-			printDangerousBlock(pattern.reason, command)
-			return NewSilentExit(2)
+// printGuardDecision explains a guard decision command by command.
+func printGuardDecision(command, role, rigName string, ev *guard.Evaluator, d guard.Decision) {
+	if role == "" {
+		role = "(none)"
+	}
+	if rigName == "" {
+		rigName = "(none)"
+	}
+	sources := make([]string, len(ev.Layers))
+	for i, l := range ev.Layers {
+		sources[i] = l.Source
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Command:"), command)
+	fmt.Printf("%s %s   %s %s\n", style.Bold.Render("Role:"), role, style.Bold.Render("Rig:"), rigName)
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Policy:"), strings.Join(sources, " → "))
+
+	if len(d.Verdicts) == 0 && !d.Allowed {
+		fmt.Printf("  %s %s\n", style.Error.Render("✗"), d.Reason)
+	}
+	for _, v := range d.Verdicts {
+		mark, action := style.Success.Render("✓"), "allow"
+		if v.Action == guard.Deny {
+			mark, action = style.Error.Render("✗"), "deny "
+		}
+		fmt.Printf("  %s %s  %s\n", mark, action, v.Command)
+		if len(v.Command.Via) > 0 {
+			fmt.Printf("           %s\n", style.Dim.Render("via "+strings.Join(v.Command.Via, " → ")))
+		}
+		switch {
+		case v.Rule == "":
+			fmt.Printf("           %s\n", style.Dim.Render("no rule matched"))
+		case v.Reason != "":
+			fmt.Printf("           %s\n", style.Dim.Render(fmt.Sprintf("rule %s (%s): %s", v.Rule, v.Source, v.Reason)))
+		default:
+			fmt.Printf("           %s\n", style.Dim.Render(fmt.Sprintf("rule %s (%s)", v.Rule, v.Source)))
 		}
 	}
 
-	return nil
+	fmt.Println()
+	if d.Allowed {
+		fmt.Printf("%s Allowed\n", style.Success.Render("✓"))
+	} else {
+		fmt.Printf("%s Blocked: %s\n", style.Error.Render("✗"), d.Reason)
+	}
+}
+
+// guardIdentity returns the role and rig of the agent the guard runs for.
+func guardIdentity() (role, rigName string) {
+	if env := os.Getenv("GT_ROLE"); env != "" {
+		r, rig, _ := parseRoleString(env)
+		role, rigName = string(r), rig
+	}
+	if env := os.Getenv("GT_RIG"); env != "" {
+		rigName = env
+	}
+	return role, rigName
+}
+
+// guardEvaluator loads the guard policies for the current town and rigName.
+func guardEvaluator(rigName string) (*guard.Evaluator, error) {
+	townRoot, _, _ := workspace.FindFromCwdWithFallback()
+	if townRoot == "" {
+		townRoot = os.Getenv("GT_TOWN_ROOT")
+	}
+	rigPath := ""
+	if townRoot != "" && rigName != "" {
+		rigPath = filepath.Join(townRoot, rigName)
+	}
+	return guard.NewEvaluator(townRoot, rigPath)
 }
 
 // printDangerousBlock prints the standard block banner to stderr.
@@ -137,118 +260,3 @@ func extractCommand(input []byte) string {
 	}
 	return hookInput.ToolInput.Command
 }
-
-// matchesAllFragments returns true if all fragments appear in the command.
-func matchesAllFragments(command string, fragments []string) bool {
-	for _, f := range fragments {
-		if !strings.Contains(command, strings.ToLower(f)) {
-			return false
-		}
-	}
-	return true
-}
-
-// matchesDangerousRmRf blocks "rm -rf /" targeting the root filesystem.
-// Only blocks when the target is literally "/" or "/*". Normal cleanup
-// commands like "rm -rf ./build/" are allowed.
-func matchesDangerousRmRf(command string) string {
-	if !strings.Contains(command, "rm") {
-		return ""
-	}
-	fields := strings.Fields(command)
-	hasRm := false
-	hasRecursiveForce := false
-	for _, f := range fields {
-		if f == "rm" {
-			hasRm = true
-		}
-		if strings.HasPrefix(f, "-") && strings.Contains(f, "r") && strings.Contains(f, "f") {
-			hasRecursiveForce = true
-		}
-		if hasRm && hasRecursiveForce && (f == "/" || f == "/*") {
-			return "filesystem destruction (rm -rf /)"
-		}
-	}
-	return ""
-}
-
-// matchesSudo blocks any command that starts with or contains "sudo".
-// Agents must never elevate privileges on the host system.
-func matchesSudo(command string) string {
-	fields := strings.Fields(command)
-	for _, f := range fields {
-		if f == "sudo" {
-			return "Agents must never use sudo — do not elevate privileges or modify the host OS"
-		}
-	}
-	return ""
-}
-
-// packageManagerPatterns lists system package manager install commands.
-// Each entry has the command prefix tokens and a reason.
-var packageManagerPatterns = []struct {
-	tokens []string
-	reason string
-}{
-	{[]string{"apt", "install"}, "System package install (apt) — use workspace tools instead"},
-	{[]string{"apt-get", "install"}, "System package install (apt-get) — use workspace tools instead"},
-	{[]string{"dnf", "install"}, "System package install (dnf) — use workspace tools instead"},
-	{[]string{"yum", "install"}, "System package install (yum) — use workspace tools instead"},
-	{[]string{"pacman", "-s"}, "System package install (pacman) — use workspace tools instead"},
-	{[]string{"brew", "install"}, "Package install (brew) — use workspace tools instead"},
-	{[]string{"gem", "install"}, "System gem install — use workspace tools instead"},
-}
-
-// matchesPackageInstall blocks system package manager install commands.
-// Also blocks "pip install" with --system flag and "npm install -g" (global installs).
-func matchesPackageInstall(command string) string {
-	// Check simple token-based patterns (apt install, dnf install, etc.)
-	for _, p := range packageManagerPatterns {
-		if matchesAllFragments(command, p.tokens) {
-			return p.reason
-		}
-	}
-
-	// pip install --system (but not regular pip install into a venv)
-	if strings.Contains(command, "pip") && strings.Contains(command, "install") && strings.Contains(command, "--system") {
-		return "System-level pip install — use a virtualenv or workspace tools instead"
-	}
-
-	// npm install -g / npm install --global
-	if strings.Contains(command, "npm") && strings.Contains(command, "install") {
-		if strings.Contains(command, " -g ") || strings.Contains(command, " -g") || strings.Contains(command, "--global") {
-			return "Global npm install — use workspace tools instead"
-		}
-	}
-
-	return ""
-}
-
-// matchesDangerousGitPush blocks "git push --force" while allowing safe
-// variants like "--force-with-lease" and "--force-if-includes".
-func matchesDangerousGitPush(command string) string {
-	if !strings.Contains(command, "git") || !strings.Contains(command, "push") {
-		return ""
-	}
-	fields := strings.Fields(command)
-	hasPush := false
-	for i, f := range fields {
-		if f == "push" && i > 0 && fields[i-1] == "git" {
-			hasPush = true
-			continue
-		}
-		if !hasPush {
-			continue
-		}
-		if f == "--force" || f == "-f" {
-			return "Force push rewrites remote history and can destroy others' work"
-		}
-		// Skip safe force variants (don't accidentally match their substrings)
-		for _, safe := range safeForceFlags {
-			if f == safe {
-				break
-			}
-		}
-	}
-	return ""
-}
@@ -58,7 +58,7 @@ func runTapList(cmd *cobra.Command, args []string) error {
 			kind:        "guard",
 			description: "Block sudo, package installs, rm -rf, force push, hard reset, etc.",
 			event:       "PreToolUse",
-			matchers:    []string{"Bash(sudo *)", "Bash(apt install*)", "Bash(dnf install*)", "Bash(brew install*)", "Bash(rm -rf /*)", "Bash(git push --force*)", "Bash(git push -f*)"},
+			matchers:    []string{"Bash"},
 			implemented: true,
 		},
 		{
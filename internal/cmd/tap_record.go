@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var tapRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record a polecat tool call for replay (PostToolUse)",
+	Long: `Append the tool call that just ran to the polecat's session recording.
+
+Runs from the polecat PostToolUse hook. Each call is recorded with its input,
+its result, and the git tree of the worktree afterwards, so
+'gt polecat replay' can show the diff every call produced. Read-only tools
+reuse the previous tree; only tools that can write (Bash, Edit, Write, ...)
+snapshot the worktree. Snapshots leave out untracked files over 1 MiB, and
+each session's last step is kept alongside its recording, so recording a
+call costs the same at step 500 as at step 1.
+
+Recordings are kept under <rig>/.runtime/recordings/<polecat>/, newest 20
+per polecat. Does nothing outside a polecat session and never fails the
+tool call.`,
+	RunE:         runTapRecord,
+	SilenceUsage: true,
+}
+
+func init() {
+	tapCmd.AddCommand(tapRecordCmd)
+}
+
+// recordHookInput is the part of the PostToolUse hook payload we record.
+type recordHookInput struct {
+	SessionID    string          `json:"session_id"`
+	ToolName     string          `json:"tool_name"`
+	ToolInput    json.RawMessage `json:"tool_input"`
+	ToolResponse json.RawMessage `json:"tool_response"`
+}
+
+func runTapRecord(cmd *cobra.Command, args []string) error {
+	polecatName, rigName := os.Getenv("GT_POLECAT"), os.Getenv("GT_RIG")
+	if polecatName == "" || rigName == "" {
+		return nil
+	}
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil
+	}
+	call, ok := parseRecordHookInput(input)
+	if !ok {
+		return nil
+	}
+	call.Session = os.Getenv("GT_SESSION")
+
+	townRoot, _, _ := workspace.FindFromCwdWithFallback()
+	if townRoot == "" {
+		townRoot = os.Getenv("GT_TOWN_ROOT")
+	}
+	if townRoot == "" {
+		return nil
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	cloneDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
+	if _, err := os.Stat(filepath.Join(cloneDir, ".git")); err != nil {
+		cloneDir = filepath.Join(rigPath, "polecats", polecatName)
+	}
+
+	if err := checkpoint.Record(checkpoint.RecordingsDir(rigPath, polecatName), cloneDir, call); err != nil {
+		fmt.Fprintf(os.Stderr, "gt tap record: %v\n", err)
+	}
+	return nil
+}
+
+// parseRecordHookInput extracts the tool call from a PostToolUse payload.
+func parseRecordHookInput(input []byte) (checkpoint.ToolCall, bool) {
+	var in recordHookInput
+	if err := json.Unmarshal(input, &in); err != nil || in.ToolName == "" {
+		return checkpoint.ToolCall{}, false
+	}
+	return checkpoint.ToolCall{
+		Tool:          in.ToolName,
+		Input:         hookPayloadText(in.ToolInput),
+		Output:        hookPayloadText(in.ToolResponse),
+		NativeSession: in.SessionID,
+	}, true
+}
+
+// hookPayloadText renders a hook payload field for display: strings as
+// themselves, anything else as compact JSON.
+func hookPayloadText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
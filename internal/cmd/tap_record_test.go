@@ -0,0 +1,26 @@
+package cmd
+
+import "testing"
+
+func TestParseRecordHookInput(t *testing.T) {
+	input := `{"session_id":"s-1","tool_name":"Bash","tool_input":{"command":"go test ./..."},"tool_response":"ok"}`
+	call, ok := parseRecordHookInput([]byte(input))
+	if !ok {
+		t.Fatal("valid payload rejected")
+	}
+	if call.Tool != "Bash" || call.NativeSession != "s-1" {
+		t.Errorf("call = %+v, want Bash from s-1", call)
+	}
+	if call.Input != `{"command":"go test ./..."}` {
+		t.Errorf("input = %q, want raw JSON object", call.Input)
+	}
+	if call.Output != "ok" {
+		t.Errorf("output = %q, want unquoted string", call.Output)
+	}
+
+	for _, bad := range []string{"", "not json", `{"session_id":"s-1"}`} {
+		if _, ok := parseRecordHookInput([]byte(bad)); ok {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
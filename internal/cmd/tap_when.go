@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+var tapWhenProvider string
+
+var tapWhenCmd = &cobra.Command{
+	Use:   "when -- <matcher> <command> [<matcher> <command>...]",
+	Short: "Run tool hooks whose Claude-style matcher selects the call",
+	Long: `Run a managed tool hook on a non-Claude runtime.
+
+Gemini, Cursor and Copilot can only select hooks by tool name, so
+'gt hooks sync' wraps PreToolUse/PostToolUse hooks in this command, one
+invocation per native matcher. It reads the runtime's hook payload from
+stdin, rewrites it into the Claude shape ({"tool_name": "Bash",
+"tool_input": {"command": ...}}) so the same gt tap handlers work
+everywhere, and runs each hook with sh -c, in order, when its Claude
+matcher selects the call:
+
+  ""  or "*"          every tool call
+  Bash                the shell tool
+  Edit|Write          a regex on the tool name
+  Bash(git push*)     the shell tool, when the command matches the glob
+
+A hook exiting 2 blocks the call: later hooks are skipped and gt exits 2.
+Any other failure is passed through once the remaining hooks have run.
+Copilot expects a JSON decision instead, so with --provider copilot a
+blocked call prints {"permissionDecision": "deny", ...} and exits 0.
+
+The single-hook form 'gt tap when <matcher> -- <command>' written by
+earlier versions is still accepted.`,
+	Args:         cobra.MinimumNArgs(2),
+	RunE:         runTapWhen,
+	SilenceUsage: true,
+}
+
+func init() {
+	tapWhenCmd.Flags().StringVar(&tapWhenProvider, "provider", "", "Runtime the payload comes from (gemini, cursor, copilot)")
+	tapCmd.AddCommand(tapWhenCmd)
+}
+
+func runTapWhen(cmd *cobra.Command, args []string) error {
+	whenHooks, err := parseWhenHooks(args, cmd.ArgsLenAtDash())
+	if err != nil {
+		return err
+	}
+
+	input, _ := io.ReadAll(os.Stdin)
+	payload, tool, command := normalizeToolPayload(input)
+	code, blocker, stderr, err := runWhenHooks(whenHooks, payload, tool, command)
+	if err != nil {
+		return err
+	}
+
+	if code == 2 && tapWhenProvider == "copilot" {
+		reason := hookDenyReason(stderr)
+		if reason == "" {
+			reason = "Blocked by Gas Town hook (" + blocker + ")"
+		}
+		out, _ := json.Marshal(map[string]string{
+			"permissionDecision":       "deny",
+			"permissionDecisionReason": reason,
+		})
+		fmt.Println(string(out))
+		return nil
+	}
+	if code != 0 {
+		cmd.SilenceErrors = true
+		return NewSilentExit(code)
+	}
+	return nil
+}
+
+// parseWhenHooks reads "gt tap when" arguments as matcher/command pairs.
+// dash is the position of "--" (-1 if absent); at 1 it is the older
+// single-hook form, "<matcher> -- <command words...>".
+func parseWhenHooks(args []string, dash int) ([]hooks.WhenHook, error) {
+	if dash == 1 {
+		return []hooks.WhenHook{{Matcher: args[0], Command: strings.Join(args[1:], " ")}}, nil
+	}
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("expected <matcher> <command> pairs, got %d arguments", len(args))
+	}
+	var out []hooks.WhenHook
+	for i := 0; i < len(args); i += 2 {
+		out = append(out, hooks.WhenHook{Matcher: args[i], Command: args[i+1]})
+	}
+	return out, nil
+}
+
+// runWhenHooks runs, in order, each hook whose matcher selects the call,
+// passing its output through. A hook exiting 2 blocks the call and stops
+// the run; it returns 2 with that hook's matcher and stderr. Otherwise the
+// first non-zero exit code is returned once all hooks have run.
+func runWhenHooks(whenHooks []hooks.WhenHook, payload []byte, tool, command string) (code int, blocker, stderr string, err error) {
+	for _, h := range whenHooks {
+		if !hooks.MatchTool(h.Matcher, tool, command) {
+			continue
+		}
+		var errBuf bytes.Buffer
+		c := exec.Command("sh", "-c", h.Command)
+		c.Stdin = bytes.NewReader(payload)
+		c.Stdout = os.Stdout
+		c.Stderr = io.MultiWriter(os.Stderr, &errBuf)
+		runErr := c.Run()
+
+		var exitErr *exec.ExitError
+		switch {
+		case runErr == nil:
+		case errors.As(runErr, &exitErr):
+			if exitErr.ExitCode() == 2 {
+				return 2, h.Matcher, errBuf.String(), nil
+			}
+			if code == 0 {
+				code = exitErr.ExitCode()
+			}
+		default:
+			return 0, "", "", fmt.Errorf("running hook: %w", runErr)
+		}
+	}
+	return code, "", "", nil
+}
+
+// nativeToolNames maps other runtimes' tool names to Claude's.
+var nativeToolNames = map[string]string{
+	"run_shell_command": "Bash", // Gemini
+	"Shell":             "Bash", // Cursor
+	"shell":             "Bash",
+	"bash":              "Bash", // Copilot
+	"write_file":        "Write",
+	"replace":           "Edit",
+	"read_file":         "Read",
+	"edit":              "Edit",
+	"create":            "Write",
+	"view":              "Read",
+}
+
+// normalizeToolPayload rewrites a runtime's tool hook payload into the
+// Claude shape, keeping any other fields, and returns it with the tool name
+// and shell command. A payload that isn't JSON is passed on unchanged.
+func normalizeToolPayload(input []byte) (payload []byte, tool, command string) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return input, "", ""
+	}
+
+	for _, key := range []string{"tool_name", "toolName"} {
+		if json.Unmarshal(raw[key], &tool) == nil && tool != "" {
+			break
+		}
+	}
+	if mapped, ok := nativeToolNames[tool]; ok {
+		tool = mapped
+	}
+
+	var toolInput map[string]any
+	for _, key := range []string{"tool_input", "toolArgs", "tool_args"} {
+		v, ok := raw[key]
+		if !ok {
+			continue
+		}
+		// Copilot sends the arguments as a JSON-encoded string.
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			v = json.RawMessage(s)
+		}
+		if json.Unmarshal(v, &toolInput) == nil {
+			break
+		}
+	}
+	if toolInput == nil {
+		toolInput = map[string]any{}
+	}
+	if _, ok := toolInput["command"]; !ok {
+		if c, ok := raw["command"]; ok { // Cursor shell hooks put it at the top
+			var s string
+			if json.Unmarshal(c, &s) == nil {
+				toolInput["command"] = s
+			}
+		}
+	}
+	command, _ = toolInput["command"].(string)
+
+	raw["tool_name"], _ = json.Marshal(tool)
+	raw["tool_input"], _ = json.Marshal(toolInput)
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return input, tool, command
+	}
+	return payload, tool, command
+}
+
+// hookDenyReason picks a one-line reason out of a blocking hook's stderr:
+// the "Reason:" line of a guard banner, or else the first line.
+func hookDenyReason(stderr string) string {
+	first := ""
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(strings.Trim(line, "║╔╗╚╝╠╣═ \t"))
+		if line == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "Reason:"); ok {
+			return strings.TrimSpace(rest)
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return first
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+func TestNormalizeToolPayload(t *testing.T) {
+	tests := []struct {
+		name, input, tool, command string
+	}{
+		{"claude", `{"tool_name":"Bash","tool_input":{"command":"git push -f"}}`, "Bash", "git push -f"},
+		{"gemini", `{"session_id":"s","tool_name":"run_shell_command","tool_input":{"command":"ls"}}`, "Bash", "ls"},
+		{"copilot", `{"toolName":"bash","toolArgs":"{\"command\":\"gh pr create\"}"}`, "Bash", "gh pr create"},
+		{"cursor", `{"tool_name":"Shell","command":"rm -rf /"}`, "Bash", "rm -rf /"},
+		{"edit", `{"tool_name":"replace","tool_input":{"file_path":"a.go"}}`, "Edit", ""},
+	}
+	for _, tt := range tests {
+		payload, tool, command := normalizeToolPayload([]byte(tt.input))
+		if tool != tt.tool || command != tt.command {
+			t.Errorf("%s: got %q %q, want %q %q", tt.name, tool, command, tt.tool, tt.command)
+		}
+		// The Claude-shaped payload is what gt tap handlers read.
+		if got := extractCommand(payload); got != tt.command {
+			t.Errorf("%s: extractCommand(payload) = %q, want %q", tt.name, got, tt.command)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(payload, &raw); err != nil || raw["tool_name"] != tt.tool {
+			t.Errorf("%s: payload %s", tt.name, payload)
+		}
+	}
+
+	if payload, tool, _ := normalizeToolPayload([]byte("not json")); string(payload) != "not json" || tool != "" {
+		t.Errorf("non-JSON payload should pass through, got %q %q", payload, tool)
+	}
+}
+
+func TestHookDenyReason(t *testing.T) {
+	banner := "\n╔════╗\n║  ❌ DANGEROUS COMMAND BLOCKED   ║\n║  Command: sudo ls  ║\n║  Reason:  privilege escalation   ║\n╚════╝\n"
+	if got := hookDenyReason(banner); got != "privilege escalation" {
+		t.Errorf("banner reason = %q", got)
+	}
+	if got := hookDenyReason("❌ BLOCKED: use wisps\nUse: bd mol wisp\n"); got != "❌ BLOCKED: use wisps" {
+		t.Errorf("echo reason = %q", got)
+	}
+}
+
+func TestParseWhenHooks(t *testing.T) {
+	got, err := parseWhenHooks([]string{"Bash(git push*)", "guard push", "Edit", "guard edit"}, 0)
+	if err != nil || len(got) != 2 || got[1] != (hooks.WhenHook{Matcher: "Edit", Command: "guard edit"}) {
+		t.Errorf("pairs = %+v, %v", got, err)
+	}
+	// The single-hook form written before hooks were grouped.
+	got, err = parseWhenHooks([]string{"Bash", "gt", "tap", "guard"}, 1)
+	if err != nil || len(got) != 1 || got[0].Command != "gt tap guard" {
+		t.Errorf("legacy = %+v, %v", got, err)
+	}
+	if _, err := parseWhenHooks([]string{"Bash", "a", "Edit"}, 0); err == nil {
+		t.Error("odd argument count should fail")
+	}
+}
+
+func TestRunWhenHooks(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	whenHooks := []hooks.WhenHook{
+		{Matcher: "Edit", Command: "exit 2"},
+		{Matcher: "Bash", Command: "exit 3"},
+		{Matcher: "Bash(git push*)", Command: "echo 'Reason: no pushing' >&2; exit 2"},
+		{Matcher: "Bash", Command: "touch " + marker},
+	}
+
+	code, blocker, stderr, err := runWhenHooks(whenHooks, nil, "Bash", "git push")
+	if err != nil || code != 2 || blocker != "Bash(git push*)" || hookDenyReason(stderr) != "no pushing" {
+		t.Errorf("push = %d %q %q %v, want blocked by the push guard", code, blocker, stderr, err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("hooks after a block should not run")
+	}
+
+	code, _, _, err = runWhenHooks(whenHooks, nil, "Bash", "ls")
+	if err != nil || code != 3 {
+		t.Errorf("ls = %d %v, want the first failure (3)", code, err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("a non-blocking failure should not stop later hooks")
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	beadsdk "github.com/steveyegge/beads"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
 )
 
 var (
@@ -17,6 +18,11 @@ var (
 )
 
 func addTrackingRelation(townRoot, trackerID, issueID string) error {
+	if peer, id, ok := federation.SplitRemoteBeadRef(townRoot, issueID); ok {
+		if err := federation.TrackRemoteBead(townRoot, peer, id); err != nil {
+			return fmt.Errorf("recording remote bead %s: %w", issueID, err)
+		}
+	}
 	if err := mutateTrackingRelationViaStore(townRoot, trackerID, issueID, true); err != nil {
 		return fallbackTrackingRelation(townRoot, trackerID, issueID, true, err)
 	}
@@ -87,6 +93,12 @@ func trackingDependsOnID(townRoot, issueID string) string {
 		return issueID
 	}
 
+	// Beads in a peer town ("<peer>:<id>") are tracked as external refs
+	// named after the peer; status comes from the federation remote index.
+	if peer, id, ok := federation.SplitRemoteBeadRef(townRoot, issueID); ok {
+		return fmt.Sprintf("external:%s:%s", peer, id)
+	}
+
 	prefix := beads.ExtractPrefix(issueID)
 	if prefix == "" {
 		return issueID
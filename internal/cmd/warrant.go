@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,27 +12,22 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/warrant"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Warrant flags
 var (
-	warrantReason  string
-	warrantListAll bool
-	warrantForce   bool
-	warrantStdin   bool // Read reason from stdin
+	warrantReason     string
+	warrantListAll    bool
+	warrantForce      bool
+	warrantStdin      bool // Read reason from stdin
+	warrantVetoReason string
+	warrantShowJSON   bool
 )
 
-// Warrant represents a death warrant for an agent
-type Warrant struct {
-	ID         string     `json:"id"`
-	Target     string     `json:"target"` // e.g., "gastown/polecats/alpha", "deacon/dogs/bravo"
-	Reason     string     `json:"reason"`
-	FiledBy    string     `json:"filed_by"`
-	FiledAt    time.Time  `json:"filed_at"`
-	Executed   bool       `json:"executed,omitempty"`
-	ExecutedAt *time.Time `json:"executed_at,omitempty"`
-}
+// Warrant represents a death warrant for an agent.
+type Warrant = warrant.Warrant
 
 var warrantCmd = &cobra.Command{
 	Use:   "warrant",
@@ -49,6 +43,12 @@ The warrant system provides a controlled way to terminate agents:
 3. Boot executes the warrant (terminates session, updates state)
 4. Warrant is marked as executed
 
+The witness also files warrants automatically when a polecat matches one of
+the warrant_policies in the witness operational config (for example, no pane
+output and no git change for 45m). Policy warrants carry evidence (pane
+capture, agent log tail, git status) and a grace period: Boot will not
+execute them until it expires, and 'gt warrant veto' cancels them.
+
 Warrants are stored in ~/gt/warrants/ as JSON files.`,
 }
 
@@ -101,6 +101,33 @@ Examples:
 	RunE: runWarrantExecute,
 }
 
+var warrantVetoCmd = &cobra.Command{
+	Use:   "veto <target>",
+	Short: "Cancel a pending warrant",
+	Long: `Veto a pending warrant so it is never executed.
+
+Use this during a policy warrant's grace period when the agent is actually
+making progress. The vetoed warrant stays on disk for the record, and the
+witness files no new policy warrant against the agent for the next 4 hours.
+
+Examples:
+  gt warrant veto gastown/polecats/alpha -r "long build, still working"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWarrantVeto,
+}
+
+var warrantShowCmd = &cobra.Command{
+	Use:   "show <target>",
+	Short: "Show a warrant and its evidence",
+	Long: `Show a warrant, including the evidence attached by policy filings.
+
+Examples:
+  gt warrant show gastown/polecats/alpha
+  gt warrant show gastown/polecats/alpha --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWarrantShow,
+}
+
 func init() {
 	// File flags
 	warrantFileCmd.Flags().StringVarP(&warrantReason, "reason", "r", "", "Reason for the warrant (required unless --stdin)")
@@ -112,9 +139,15 @@ func init() {
 	// Execute flags
 	warrantExecuteCmd.Flags().BoolVarP(&warrantForce, "force", "f", false, "Execute even without a warrant")
 
+	// Veto / show flags
+	warrantVetoCmd.Flags().StringVarP(&warrantVetoReason, "reason", "r", "", "Why the warrant is vetoed")
+	warrantShowCmd.Flags().BoolVar(&warrantShowJSON, "json", false, "Output as JSON")
+
 	warrantCmd.AddCommand(warrantFileCmd)
 	warrantCmd.AddCommand(warrantListCmd)
 	warrantCmd.AddCommand(warrantExecuteCmd)
+	warrantCmd.AddCommand(warrantVetoCmd)
+	warrantCmd.AddCommand(warrantShowCmd)
 
 	rootCmd.AddCommand(warrantCmd)
 }
@@ -125,14 +158,12 @@ func getWarrantDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("finding town root: %w", err)
 	}
-	return filepath.Join(townRoot, "warrants"), nil
+	return warrant.Dir(townRoot), nil
 }
 
 // warrantFilePath returns the path for a warrant file
 func warrantFilePath(dir, target string) string {
-	// Replace / with _ for filename safety
-	safe := strings.ReplaceAll(target, "/", "_")
-	return filepath.Join(dir, safe+".warrant.json")
+	return warrant.FilePath(dir, target)
 }
 
 func runWarrantFile(cmd *cobra.Command, args []string) error {
@@ -160,23 +191,13 @@ func runWarrantFile(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create warrants directory if needed
-	if err := os.MkdirAll(warrantDir, 0755); err != nil {
-		return fmt.Errorf("creating warrants directory: %w", err)
-	}
-
 	// Check if warrant already exists
 	warrantPath := warrantFilePath(warrantDir, target)
-	if _, err := os.Stat(warrantPath); err == nil {
-		// Load existing warrant
-		data, _ := os.ReadFile(warrantPath)
-		var existing Warrant
-		if json.Unmarshal(data, &existing) == nil && !existing.Executed {
-			fmt.Printf("Warrant already exists for %s\n", target)
-			fmt.Printf("  Reason: %s\n", existing.Reason)
-			fmt.Printf("  Filed: %s\n", existing.FiledAt.Format(time.RFC3339))
-			return nil
-		}
+	if existing, err := warrant.Load(warrantPath); err == nil && existing.Pending() {
+		fmt.Printf("Warrant already exists for %s\n", target)
+		fmt.Printf("  Reason: %s\n", existing.Reason)
+		fmt.Printf("  Filed: %s\n", existing.FiledAt.Format(time.RFC3339))
+		return nil
 	}
 
 	// Get filer identity
@@ -185,27 +206,23 @@ func runWarrantFile(cmd *cobra.Command, args []string) error {
 		filedBy = "unknown"
 	}
 
-	warrant := Warrant{
-		ID:       fmt.Sprintf("warrant-%d", time.Now().UnixMilli()),
+	now := time.Now()
+	w := &Warrant{
+		ID:       warrant.NewID(now),
 		Target:   target,
 		Reason:   warrantReason,
 		FiledBy:  filedBy,
-		FiledAt:  time.Now(),
+		FiledAt:  now,
 		Executed: false,
 	}
 
-	data, err := json.MarshalIndent(warrant, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling warrant: %w", err)
-	}
-
-	if err := os.WriteFile(warrantPath, data, 0644); err != nil {
+	if err := warrant.Save(warrantPath, w); err != nil {
 		return fmt.Errorf("writing warrant: %w", err)
 	}
 
 	fmt.Printf("✓ Filed death warrant for %s\n", style.Bold.Render(target))
 	fmt.Printf("  Reason: %s\n", warrantReason)
-	fmt.Printf("  ID: %s\n", warrant.ID)
+	fmt.Printf("  ID: %s\n", w.ID)
 
 	return nil
 }
@@ -216,33 +233,15 @@ func runWarrantList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	entries, err := os.ReadDir(warrantDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No warrants filed")
-			return nil
-		}
-		return fmt.Errorf("reading warrants directory: %w", err)
+	entries, errs := warrant.List(warrantDir)
+	if len(entries) == 0 && len(errs) > 0 {
+		return errs[0]
 	}
 
-	var warrants []Warrant
-	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".warrant.json") {
-			continue
-		}
-
-		data, err := os.ReadFile(filepath.Join(warrantDir, entry.Name()))
-		if err != nil {
-			continue
-		}
-
-		var w Warrant
-		if err := json.Unmarshal(data, &w); err != nil {
-			continue
-		}
-
-		if warrantListAll || !w.Executed {
-			warrants = append(warrants, w)
+	var warrants []*Warrant
+	for _, e := range entries {
+		if warrantListAll || e.Warrant.Pending() {
+			warrants = append(warrants, e.Warrant)
 		}
 	}
 
@@ -258,17 +257,21 @@ func runWarrantList(cmd *cobra.Command, args []string) error {
 	fmt.Println(style.Bold.Render("Death Warrants"))
 	fmt.Println()
 
+	now := time.Now()
 	for _, w := range warrants {
-		status := "⚠️  PENDING"
-		if w.Executed {
-			status = "✓ EXECUTED"
-		}
-		fmt.Printf("  %s %s\n", status, style.Bold.Render(w.Target))
+		fmt.Printf("  %s %s\n", warrantStatusLabel(w), style.Bold.Render(w.Target))
 		fmt.Printf("     Reason: %s\n", w.Reason)
 		fmt.Printf("     Filed: %s by %s\n", w.FiledAt.Format("2006-01-02 15:04"), w.FiledBy)
+		if w.Pending() && w.ExecuteAfter != nil && now.Before(*w.ExecuteAfter) {
+			fmt.Printf("     Executes after: %s (veto with: gt warrant veto %s)\n",
+				w.ExecuteAfter.Format("2006-01-02 15:04"), w.Target)
+		}
 		if w.Executed && w.ExecutedAt != nil {
 			fmt.Printf("     Executed: %s\n", w.ExecutedAt.Format("2006-01-02 15:04"))
 		}
+		if w.Vetoed && w.VetoedAt != nil {
+			fmt.Printf("     Vetoed: %s by %s\n", w.VetoedAt.Format("2006-01-02 15:04"), w.VetoedBy)
+		}
 		fmt.Println()
 	}
 
@@ -284,29 +287,27 @@ func runWarrantExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	warrantPath := warrantFilePath(warrantDir, target)
-	var warrant *Warrant
 
 	// Load warrant if exists
-	if data, err := os.ReadFile(warrantPath); err == nil {
-		var w Warrant
-		if json.Unmarshal(data, &w) == nil {
-			warrant = &w
-		}
-	}
+	w, _ := warrant.Load(warrantPath)
 
-	if warrant == nil && !warrantForce {
+	if w == nil && !warrantForce {
 		return fmt.Errorf("no warrant found for %s (use --force to execute anyway)", target)
 	}
 
-	if warrant != nil && warrant.Executed {
-		fmt.Printf("Warrant for %s already executed at %s\n", target, warrant.ExecutedAt.Format(time.RFC3339))
+	if w != nil && w.Executed {
+		fmt.Printf("Warrant for %s already executed at %s\n", target, w.ExecutedAt.Format(time.RFC3339))
 		return nil
 	}
 
+	if w != nil && w.Vetoed && !warrantForce {
+		return fmt.Errorf("warrant for %s was vetoed by %s (use --force to execute anyway)", target, w.VetoedBy)
+	}
+
 	tm := tmux.NewTmux()
 
-	if warrant != nil {
-		if err := executeOneWarrant(warrant, warrantPath, tm); err != nil {
+	if w != nil {
+		if err := executeOneWarrant(w, warrantPath, tm); err != nil {
 			return fmt.Errorf("executing warrant: %w", err)
 		}
 	} else {
@@ -358,17 +359,107 @@ func executeOneWarrant(w *Warrant, warrantPath string, tm *tmux.Tmux) error {
 	now := time.Now()
 	w.Executed = true
 	w.ExecutedAt = &now
-	data, err := json.MarshalIndent(w, "", "  ")
+	if err := warrant.Save(warrantPath, w); err != nil {
+		return fmt.Errorf("writing warrant file: %w", err)
+	}
+
+	return nil
+}
+
+func runWarrantVeto(cmd *cobra.Command, args []string) error {
+	target := args[0]
+
+	warrantDir, err := getWarrantDir()
 	if err != nil {
-		return fmt.Errorf("marshaling warrant: %w", err)
+		return err
+	}
+
+	warrantPath := warrantFilePath(warrantDir, target)
+	w, err := warrant.Load(warrantPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no warrant found for %s", target)
+		}
+		return err
+	}
+	if !w.Pending() {
+		return fmt.Errorf("warrant for %s is not pending (%s)", target, strings.TrimSpace(warrantStatusLabel(w)))
 	}
-	if err := os.WriteFile(warrantPath, data, 0644); err != nil {
+
+	vetoedBy := os.Getenv("BD_ACTOR")
+	if vetoedBy == "" {
+		vetoedBy = "unknown"
+	}
+	w.Veto(vetoedBy, warrantVetoReason, time.Now())
+	if err := warrant.Save(warrantPath, w); err != nil {
 		return fmt.Errorf("writing warrant file: %w", err)
 	}
 
+	fmt.Printf("✓ Vetoed warrant for %s\n", style.Bold.Render(target))
+	return nil
+}
+
+func runWarrantShow(cmd *cobra.Command, args []string) error {
+	target := args[0]
+
+	warrantDir, err := getWarrantDir()
+	if err != nil {
+		return err
+	}
+
+	w, err := warrant.Load(warrantFilePath(warrantDir, target))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no warrant found for %s", target)
+		}
+		return err
+	}
+
+	if warrantShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(w)
+	}
+
+	fmt.Printf("%s %s\n", warrantStatusLabel(w), style.Bold.Render(w.Target))
+	fmt.Printf("  ID: %s\n", w.ID)
+	fmt.Printf("  Reason: %s\n", w.Reason)
+	fmt.Printf("  Filed: %s by %s\n", w.FiledAt.Format(time.RFC3339), w.FiledBy)
+	if w.ExecuteAfter != nil {
+		fmt.Printf("  Executes after: %s\n", w.ExecuteAfter.Format(time.RFC3339))
+	}
+	if w.Vetoed {
+		fmt.Printf("  Vetoed by %s: %s\n", w.VetoedBy, w.VetoReason)
+	}
+	if ev := w.Evidence; ev != nil {
+		for _, section := range []struct{ title, body string }{
+			{"Pane capture", ev.PaneCapture},
+			{"Agent log tail", ev.AgentLogTail},
+			{"Git status", ev.GitStatus},
+		} {
+			if section.body == "" {
+				continue
+			}
+			fmt.Printf("\n%s\n%s\n", style.Bold.Render(section.title), section.body)
+		}
+	}
 	return nil
 }
 
+// warrantStatusLabel is the status marker shown by list and show.
+func warrantStatusLabel(w *Warrant) string {
+	switch {
+	case w.Executed:
+		return "✓ EXECUTED"
+	case w.Vetoed:
+		return "✗ VETOED"
+	case w.ExecuteAfter != nil && time.Now().Before(*w.ExecuteAfter):
+		return "⏳ GRACE"
+	default:
+		return "⚠️  PENDING"
+	}
+}
+
 // targetToSessionName converts a target path to a tmux session name
 func targetToSessionName(target string) (string, error) {
 	parts := strings.Split(target, "/")
@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
@@ -17,6 +17,7 @@ import (
 var (
 	wlJoinHandle      string
 	wlJoinDisplayName string
+	wlJoinForkOrg     string
 )
 
 var wlCmd = &cobra.Command{
@@ -30,6 +31,10 @@ The Wasteland is a federation of Gas Towns via DoltHub. Each rig has a
 sovereign fork of a shared commons database containing the wanted board
 (open work), rig registry, and validated completions.
 
+The commons usually lives on DoltHub, but can also be a self-hosted dolt
+remotesapi server or a directory of file:// dolt remotes, which makes the
+whole flow usable offline.
+
 Getting started:
   gt wl join steveyegge/wl-commons   # Join the default wasteland
 
@@ -42,22 +47,32 @@ var wlJoinCmd = &cobra.Command{
 	Long: `Join a wasteland community by forking its shared commons database.
 
 This command:
-  1. Forks the upstream commons to your DoltHub org
+  1. Forks the upstream commons to your org
   2. Clones the fork locally
   3. Registers your rig in the rigs table
   4. Pushes the registration to your fork
   5. Saves wasteland configuration locally
 
-The upstream argument is a DoltHub path like 'steveyegge/wl-commons'.
+The upstream argument selects where the commons lives:
+  steveyegge/wl-commons                    DoltHub
+  https://dolt.example.com/hop/wl-commons  dolt remotesapi server (<base>/<org>/<db>)
+  file:///srv/commons/hop/wl-commons       file remote (<dir>/<org>/<db>)
+
+The fork is created next to the upstream, as <base>/<fork-org>/<db>. A
+file remote is forked by copying it; a remotesapi fork is pushed from a
+scratch clone, so the server must accept pushes to new databases.
+
+Environment variables (DoltHub only):
+  DOLTHUB_TOKEN  - Your DoltHub API token (required)
+  DOLTHUB_ORG    - Your DoltHub organization name (or pass --fork-org)
 
-Required environment variables:
-  DOLTHUB_TOKEN  - Your DoltHub API token
-  DOLTHUB_ORG    - Your DoltHub organization name
+For other backends the fork org defaults to --handle, then the town name.
 
 Examples:
   gt wl join steveyegge/wl-commons
   gt wl join steveyegge/wl-commons --handle my-rig
-  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"`,
+  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"
+  gt wl join file:///tmp/commons/hop/wl-commons --handle alice`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWlJoin,
 }
@@ -65,6 +80,7 @@ Examples:
 func init() {
 	wlJoinCmd.Flags().StringVar(&wlJoinHandle, "handle", "", "Rig handle for registration (default: DoltHub org)")
 	wlJoinCmd.Flags().StringVar(&wlJoinDisplayName, "display-name", "", "Display name for the rig registry")
+	wlJoinCmd.Flags().StringVar(&wlJoinForkOrg, "fork-org", "", "Org to fork the commons into (default: DOLTHUB_ORG, or the handle for non-DoltHub commons)")
 
 	wlCmd.AddCommand(wlJoinCmd)
 	rootCmd.AddCommand(wlCmd)
@@ -73,21 +89,30 @@ func init() {
 func runWlJoin(cmd *cobra.Command, args []string) error {
 	upstream := args[0]
 
-	// Parse upstream path (validate early)
-	_, _, err := wasteland.ParseUpstream(upstream)
+	// Parse upstream (validate early)
+	_, upstreamDB, err := wasteland.ParseUpstream(upstream)
 	if err != nil {
 		return err
 	}
-
-	// Require DoltHub credentials
-	token := doltserver.DoltHubToken()
-	if token == "" {
-		return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
+	backend, err := wasteland.ResolveBackend(upstream, nil, "")
+	if err != nil {
+		return err
 	}
 
-	forkOrg := doltserver.DoltHubOrg()
-	if forkOrg == "" {
-		return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+	// DoltHub needs credentials; self-hosted and file commons do not.
+	var token string
+	forkOrg := wlJoinForkOrg
+	if backend.Kind() == wasteland.BackendDoltHub {
+		token = doltserver.DoltHubToken()
+		if token == "" {
+			return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
+		}
+		if forkOrg == "" {
+			forkOrg = doltserver.DoltHubOrg()
+		}
+		if forkOrg == "" {
+			return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+		}
 	}
 
 	// Find town root
@@ -116,6 +141,13 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading town config: %w", err)
 	}
 
+	if forkOrg == "" {
+		forkOrg = wlJoinHandle
+	}
+	if forkOrg == "" {
+		forkOrg = townCfg.Name
+	}
+
 	// Determine town handle
 	handle := wlJoinHandle
 	if handle == "" {
@@ -139,7 +171,7 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s\n", step)
 	}
 
-	fmt.Printf("Joining wasteland %s (fork to %s/%s)...\n", upstream, forkOrg, upstream[strings.Index(upstream, "/")+1:])
+	fmt.Printf("Joining wasteland %s (fork to %s/%s)...\n", upstream, forkOrg, upstreamDB)
 	cfg, err := svc.Join(upstream, forkOrg, token, handle, displayName, ownerEmail, gtVersion, townRoot)
 	if err != nil {
 		return err
@@ -148,7 +180,24 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n%s Joined wasteland: %s\n", style.Bold.Render("✓"), upstream)
 	fmt.Printf("  Handle: %s\n", cfg.RigHandle)
 	fmt.Printf("  Fork: %s/%s\n", cfg.ForkOrg, cfg.ForkDB)
+	if cfg.Backend != wasteland.BackendDoltHub {
+		fmt.Printf("  Remote: %s\n", cfg.ForkRemote)
+	}
 	fmt.Printf("  Local: %s\n", cfg.LocalDir)
 	fmt.Printf("\n  %s\n", style.Dim.Render("Next: gt wl browse  — browse the wanted board"))
 	return nil
 }
+
+// pushLocalClone publishes a write made in the local clone to the rig's
+// fork on self-hosted and file commons, where the fork is the only way other
+// rigs see it. DoltHub commons keep their existing flow: the commit stays in
+// the clone. Failing to push leaves the commit in the clone for the next
+// push, so it is only a warning.
+func pushLocalClone(wlCfg *wasteland.Config) {
+	if wlCfg.Backend == "" || wlCfg.Backend == wasteland.BackendDoltHub {
+		return
+	}
+	if err := wasteland.PushToOrigin(wlCfg.LocalDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: committed locally but could not push to fork: %v\n", err)
+	}
+}
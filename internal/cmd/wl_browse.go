@@ -172,12 +172,12 @@ func resolveWLCommonsBrowse(townRoot, doltPath string) (cloneDir, tmpDir string,
 
 	// No local clone — do a one-time clone-then-discard.
 	// Read upstream from config, or default to hop/wl-commons.
-	commonsOrg := "hop"
 	commonsDB := "wl-commons"
+	remote := "hop/wl-commons"
 	if cfg, cfgErr := wasteland.LoadConfig(townRoot); cfgErr == nil && cfg.Upstream != "" {
-		if o, d, parseErr := wasteland.ParseUpstream(cfg.Upstream); parseErr == nil {
-			commonsOrg = o
+		if _, d, parseErr := wasteland.ParseUpstream(cfg.Upstream); parseErr == nil {
 			commonsDB = d
+			remote = cfg.Upstream
 		}
 	}
 
@@ -187,7 +187,6 @@ func resolveWLCommonsBrowse(townRoot, doltPath string) (cloneDir, tmpDir string,
 	}
 
 	cloneDir = filepath.Join(tmpDir, commonsDB)
-	remote := fmt.Sprintf("%s/%s", commonsOrg, commonsDB)
 	if !wlBrowseJSON {
 		fmt.Printf("Cloning %s...\n", style.Bold.Render(remote))
 	}
@@ -57,6 +57,7 @@ func runWlClaim(cmd *cobra.Command, args []string) error {
 		if err := claimWantedInLocalClone(wlCfg.LocalDir, wantedID, rigHandle); err != nil {
 			return err
 		}
+		pushLocalClone(wlCfg)
 		item = &doltserver.WantedItem{ID: wantedID, Status: "claimed", ClaimedBy: rigHandle}
 	} else {
 		store := doltserver.NewWLCommons(townRoot)
@@ -68,6 +68,7 @@ func runWlDone(cmd *cobra.Command, args []string) error {
 		if err := submitDoneInLocalClone(wlCfg.LocalDir, wantedID, rigHandle, wlDoneEvidence, completionID); err != nil {
 			return err
 		}
+		pushLocalClone(wlCfg)
 	} else {
 		store := doltserver.NewWLCommons(townRoot)
 		if err := submitDone(store, wantedID, rigHandle, wlDoneEvidence, completionID); err != nil {
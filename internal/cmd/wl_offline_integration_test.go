@@ -0,0 +1,167 @@
+//go:build integration
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
+)
+
+// offlineCommonsSchema is the subset of the wl-commons schema the
+// post/claim/done/stamp flow writes to.
+const offlineCommonsSchema = `CREATE TABLE rigs (
+	handle VARCHAR(255) PRIMARY KEY,
+	display_name VARCHAR(255),
+	dolthub_org VARCHAR(255),
+	owner_email VARCHAR(255),
+	gt_version VARCHAR(32),
+	trust_level INT DEFAULT 0,
+	registered_at TIMESTAMP,
+	last_seen TIMESTAMP
+);
+CREATE TABLE wanted (
+	id VARCHAR(64) PRIMARY KEY,
+	title TEXT NOT NULL,
+	description TEXT,
+	project VARCHAR(64),
+	type VARCHAR(32),
+	priority INT DEFAULT 2,
+	tags JSON,
+	posted_by VARCHAR(255),
+	claimed_by VARCHAR(255),
+	status VARCHAR(32) DEFAULT 'open',
+	effort_level VARCHAR(16) DEFAULT 'medium',
+	evidence_url TEXT,
+	created_at TIMESTAMP,
+	updated_at TIMESTAMP
+);
+CREATE TABLE completions (
+	id VARCHAR(64) PRIMARY KEY,
+	wanted_id VARCHAR(64),
+	completed_by VARCHAR(255),
+	evidence TEXT,
+	completed_at TIMESTAMP
+);
+CREATE TABLE stamps (
+	id VARCHAR(64) PRIMARY KEY,
+	author VARCHAR(255) NOT NULL,
+	subject VARCHAR(255) NOT NULL,
+	valence JSON NOT NULL,
+	confidence FLOAT DEFAULT 1,
+	severity VARCHAR(16) DEFAULT 'leaf',
+	context_id VARCHAR(64),
+	context_type VARCHAR(32),
+	stamp_type VARCHAR(32),
+	pilot_cohort VARCHAR(64),
+	skill_tags JSON,
+	message TEXT,
+	created_at TIMESTAMP
+);`
+
+// TestWlLifecycle_FileRemoteOffline runs join, post, claim, done and stamp
+// against a commons served from a file:// remote and checks every write
+// reaches the fork without any network access.
+func TestWlLifecycle_FileRemoteOffline(t *testing.T) {
+	doltPath, err := exec.LookPath("dolt")
+	if err != nil {
+		t.Skip("dolt not found in PATH — skipping integration test")
+	}
+
+	base := t.TempDir()
+	homeDir := filepath.Join(base, "home")
+	seedDir := filepath.Join(base, "seed")
+	remotes := filepath.Join(base, "remotes")
+	townRoot := filepath.Join(base, "town")
+	for _, dir := range []string{homeDir, seedDir, remotes, filepath.Join(townRoot, "mayor")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The wl commands shell out to dolt with the inherited environment.
+	t.Setenv("DOLT_ROOT_PATH", homeDir)
+	t.Setenv("HOME", homeDir)
+
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(doltPath, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("dolt %v failed: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+
+	run(homeDir, "config", "--global", "--add", "user.email", "test@test.com")
+	run(homeDir, "config", "--global", "--add", "user.name", "test")
+	run(seedDir, "init")
+	run(seedDir, "sql", "-q", offlineCommonsSchema)
+	run(seedDir, "add", ".")
+	run(seedDir, "commit", "-m", "seed commons")
+	upstream := "file://" + filepath.ToSlash(filepath.Join(remotes, "hop", "wl-commons"))
+	run(seedDir, "remote", "add", "origin", upstream)
+	run(seedDir, "push", "origin", "main")
+
+	wlCfg, err := wasteland.NewService().Join(upstream, "alice-dev", "", "alice", "Alice", "alice@example.com", "dev", townRoot)
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if wlCfg.Backend != wasteland.BackendFile {
+		t.Fatalf("Backend = %q, want %q", wlCfg.Backend, wasteland.BackendFile)
+	}
+
+	item := &doltserver.WantedItem{ID: "w-offline", Title: "Offline work", PostedBy: "alice"}
+	if err := postWantedInLocalClone(wlCfg.LocalDir, item); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	pushLocalClone(wlCfg)
+	if err := claimWantedInLocalClone(wlCfg.LocalDir, item.ID, "alice"); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	pushLocalClone(wlCfg)
+	if err := submitDoneInLocalClone(wlCfg.LocalDir, item.ID, "alice", "https://example.com/pr/1", "c-offline"); err != nil {
+		t.Fatalf("done: %v", err)
+	}
+	pushLocalClone(wlCfg)
+	stamp := &doltserver.StampRecord{
+		ID:          "s-offline",
+		Author:      "bob",
+		Subject:     "alice",
+		Valence:     `{"quality": 4}`,
+		Confidence:  0.9,
+		Severity:    "leaf",
+		ContextID:   "c-offline",
+		ContextType: "completion",
+		StampType:   "work",
+	}
+	if err := insertStampInLocalClone(wlCfg.LocalDir, stamp); err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	pushLocalClone(wlCfg)
+
+	// A fresh clone of the fork sees every write.
+	check := filepath.Join(base, "check")
+	run(base, "clone", wlCfg.ForkRemote, check)
+	query := func(q string) string {
+		t.Helper()
+		return run(check, "sql", "-q", q, "-r", "csv")
+	}
+	if out := query("SELECT handle FROM rigs"); !strings.Contains(out, "alice") {
+		t.Errorf("fork rigs = %q, want alice registered", out)
+	}
+	if out := query("SELECT status, claimed_by FROM wanted WHERE id='w-offline'"); !strings.Contains(out, "in_review,alice") {
+		t.Errorf("fork wanted row = %q, want in_review claimed by alice", out)
+	}
+	if out := query("SELECT id FROM completions WHERE wanted_id='w-offline'"); !strings.Contains(out, "c-offline") {
+		t.Errorf("fork completions = %q, want c-offline", out)
+	}
+	if out := query("SELECT subject FROM stamps WHERE id='s-offline'"); !strings.Contains(out, "alice") {
+		t.Errorf("fork stamps = %q, want stamp for alice", out)
+	}
+}
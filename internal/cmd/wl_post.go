@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -73,8 +74,6 @@ func runWlPost(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	store := doltserver.NewWLCommons(townRoot)
-
 	wlCfg, err := wasteland.LoadConfig(townRoot)
 	if err != nil {
 		return fmt.Errorf("loading wasteland config: %w", err)
@@ -92,8 +91,18 @@ func runWlPost(cmd *cobra.Command, args []string) error {
 		EffortLevel: wlPostEffort,
 	}
 
-	if err := postWanted(store, item); err != nil {
-		return err
+	dbName := wasteland.ResolveDBName(townRoot)
+	if !doltserver.DatabaseExists(townRoot, dbName) && wlCfg.LocalDir != "" {
+		// Fallback for wl-commons clone-based workspaces (join creates .wasteland clone).
+		if err := postWantedInLocalClone(wlCfg.LocalDir, item); err != nil {
+			return err
+		}
+		pushLocalClone(wlCfg)
+	} else {
+		store := doltserver.NewWLCommons(townRoot)
+		if err := postWanted(store, item); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("%s Posted wanted item: %s\n", style.Bold.Render("✓"), style.Bold.Render(item.ID))
@@ -149,3 +158,18 @@ func postWanted(store doltserver.WLCommonsStore, item *doltserver.WantedItem) er
 
 	return nil
 }
+
+func postWantedInLocalClone(localDir string, item *doltserver.WantedItem) error {
+	script, err := doltserver.WantedInsertScript(item)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("dolt", "sql", "-q", script)
+	cmd.Dir = localDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("posting wanted item: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -121,10 +121,9 @@ func resolveWLCommonsClone(townRoot, doltPath string) (cloneDir, tmpDir string,
 	if err != nil {
 		return "", "", fmt.Errorf("creating temp directory: %w", err)
 	}
-	parts := strings.SplitN(remote, "/", 2)
 	dbName := "wl-commons"
-	if len(parts) == 2 {
-		dbName = parts[1]
+	if _, d, parseErr := wasteland.ParseUpstream(remote); parseErr == nil {
+		dbName = d
 	}
 	cloneDir = filepath.Join(tmpDir, dbName)
 	fmt.Printf("Cloning %s...\n", style.Bold.Render(remote))
@@ -139,12 +139,15 @@ func runWlStamp(cmd *cobra.Command, args []string) error {
 		if wlCfg.LocalDir == "" {
 			return fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", dbName)
 		}
-		return insertStampInLocalClone(wlCfg.LocalDir, stamp)
-	}
-
-	store := doltserver.NewWLCommons(townRoot)
-	if err := insertStamp(store, stamp); err != nil {
-		return err
+		if err := insertStampInLocalClone(wlCfg.LocalDir, stamp); err != nil {
+			return err
+		}
+		pushLocalClone(wlCfg)
+	} else {
+		store := doltserver.NewWLCommons(townRoot)
+		if err := insertStamp(store, stamp); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("%s Stamp created\n", style.Bold.Render("✓"))
@@ -207,12 +207,12 @@ func runWLStamps(cmd *cobra.Command, args []string) error {
 
 	// No local fork — clone fresh from config upstream or default
 	if cloneDir == "" {
-		commonsOrg := "hop"
 		commonsDB := "wl-commons"
+		remote := "hop/wl-commons"
 		if cfg, cfgErr := wasteland.LoadConfig(townRoot); cfgErr == nil && cfg.Upstream != "" {
-			if o, d, parseErr := wasteland.ParseUpstream(cfg.Upstream); parseErr == nil {
-				commonsOrg = o
+			if _, d, parseErr := wasteland.ParseUpstream(cfg.Upstream); parseErr == nil {
 				commonsDB = d
+				remote = cfg.Upstream
 			}
 		}
 
@@ -223,7 +223,6 @@ func runWLStamps(cmd *cobra.Command, args []string) error {
 		defer os.RemoveAll(tmpDir)
 
 		cloneDir = filepath.Join(tmpDir, commonsDB)
-		remote := fmt.Sprintf("%s/%s", commonsOrg, commonsDB)
 
 		if !wlStampsJSON {
 			fmt.Printf("Cloning %s...\n", style.Bold.Render(remote))
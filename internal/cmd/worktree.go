@@ -11,8 +11,10 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"github.com/steveyegge/gastown/internal/worktree"
 )
 
 // Worktree command flags
@@ -154,7 +156,7 @@ func runWorktree(cmd *cobra.Command, args []string) error {
 	// Create the worktree on main branch
 	// Use WorktreeAddExistingForce because main may already be checked out
 	// in other worktrees (e.g., mayor/rig). This is safe for cross-rig work.
-	if err := g.WorktreeAddExistingForce(worktreePath, "main"); err != nil {
+	if _, err := crewWorktrees(targetRigInfo.Path).AddExistingForce(g, worktreePath, "main"); err != nil {
 		return fmt.Errorf("creating worktree: %w", err)
 	}
 
@@ -346,3 +348,22 @@ func runWorktreeRemove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// crewWorktrees returns the materializer for crew worktrees in a rig,
+// following its worktree_strategy. Crew worktrees come from mayor/rig, so
+// they get their own template, separate from the polecats' bare-repo one.
+func crewWorktrees(rigPath string) worktree.Materializer {
+	strategy := worktree.StrategyWorktree
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil {
+		if s, err := worktree.ParseStrategy(rigCfg.WorktreeStrategy); err == nil {
+			strategy = s
+		} else {
+			style.PrintWarning("%v; using plain worktrees", err)
+		}
+	}
+	return worktree.Materializer{
+		Strategy:    strategy,
+		TemplateDir: filepath.Join(rigPath, worktree.TemplateDirName, "crew"),
+		Warn:        style.PrintWarning,
+	}
+}
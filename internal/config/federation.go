@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FederationConfig represents cross-town federation settings
+// (settings/federation.json). A town registers peer towns by name; mail
+// addressed to "<peer>:<address>" is stored and forwarded to that peer over
+// mTLS, and convoys can track beads that live in a peer town.
+type FederationConfig struct {
+	Type    string `json:"type"`    // "federation"
+	Version int    `json:"version"` // schema version
+
+	// Name is this town's federation name, used as the certificate CN.
+	Name string `json:"name"`
+
+	// Listen is the address the federation server binds (default ":7443").
+	Listen string `json:"listen,omitempty"`
+
+	// Hosts are DNS names or IPs peers use to reach this town. They are
+	// added to this town's certificate.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Peers maps local peer names to their endpoint and trust anchor.
+	// The peer name is what users type in "<peer>:<address>".
+	Peers map[string]*FederationPeer `json:"peers,omitempty"`
+}
+
+// FederationPeer is a registered peer town.
+type FederationPeer struct {
+	// URL is the peer's federation endpoint, e.g. "https://build.local:7443".
+	URL string `json:"url"`
+
+	// CACert is the PEM-encoded CA certificate of the peer town. Only the
+	// town certificate this CA issued for the peer's name is accepted as the
+	// peer, in either direction.
+	CACert string `json:"ca_cert"`
+}
+
+// CurrentFederationVersion is the current schema version for FederationConfig.
+const CurrentFederationVersion = 1
+
+// DefaultFederationListen is the default federation server address.
+const DefaultFederationListen = ":7443"
+
+// federationPeerNamePattern restricts peer names to characters that cannot
+// be confused with address syntax ("/", "@", ":").
+var federationPeerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// reservedFederationPeerNames are address prefixes with existing meaning in
+// mail routing; a peer with one of these names could never be addressed.
+var reservedFederationPeerNames = map[string]bool{
+	"list": true, "queue": true, "announce": true, "channel": true,
+	"group": true, "external": true, "local": true,
+}
+
+// NewFederationConfig creates a new FederationConfig with defaults.
+func NewFederationConfig() *FederationConfig {
+	return &FederationConfig{
+		Type:    "federation",
+		Version: CurrentFederationVersion,
+		Peers:   make(map[string]*FederationPeer),
+	}
+}
+
+// FederationConfigPath returns the standard path for federation config in a town.
+func FederationConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "federation.json")
+}
+
+// LoadFederationConfig loads and validates a federation configuration file.
+func LoadFederationConfig(path string) (*FederationConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading federation config: %w", err)
+	}
+
+	var config FederationConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing federation config: %w", err)
+	}
+
+	if err := validateFederationConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadOrCreateFederationConfig loads the federation config, creating a default if not found.
+func LoadOrCreateFederationConfig(path string) (*FederationConfig, error) {
+	config, err := LoadFederationConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewFederationConfig(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// SaveFederationConfig saves a federation configuration to a file.
+func SaveFederationConfig(path string, config *FederationConfig) error {
+	if err := validateFederationConfig(config); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding federation config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: CA certificates are public
+		return fmt.Errorf("writing federation config: %w", err)
+	}
+
+	return nil
+}
+
+// ValidFederationPeerName reports whether name can be used as a peer name.
+func ValidFederationPeerName(name string) bool {
+	return federationPeerNamePattern.MatchString(name) && !reservedFederationPeerNames[strings.ToLower(name)]
+}
+
+// validateFederationConfig validates a FederationConfig.
+func validateFederationConfig(c *FederationConfig) error {
+	if c.Type != "federation" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'federation', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentFederationVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentFederationVersion)
+	}
+	if c.Name != "" && !federationPeerNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("%w: invalid town name '%s'", ErrMissingField, c.Name)
+	}
+
+	if c.Peers == nil {
+		c.Peers = make(map[string]*FederationPeer)
+	}
+	for name, peer := range c.Peers {
+		if !ValidFederationPeerName(name) {
+			return fmt.Errorf("%w: invalid peer name '%s'", ErrMissingField, name)
+		}
+		if peer == nil || peer.URL == "" {
+			return fmt.Errorf("%w: peer '%s' url", ErrMissingField, name)
+		}
+		if !strings.HasPrefix(peer.URL, "https://") {
+			return fmt.Errorf("%w: peer '%s' url must be https", ErrMissingField, name)
+		}
+		if !strings.Contains(peer.CACert, "BEGIN CERTIFICATE") {
+			return fmt.Errorf("%w: peer '%s' ca_cert", ErrMissingField, name)
+		}
+	}
+	return nil
+}
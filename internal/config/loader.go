@@ -9,12 +9,15 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/sandbox"
 )
 
 // resolveConfigMu serializes agent config resolution across all callers.
@@ -236,6 +239,62 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Sandbox != nil {
+		if err := validateSandboxConfig(c.Sandbox); err != nil {
+			return err
+		}
+	}
+	if c.Resources != nil {
+		if err := validateResourcesConfig(c.Resources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateResourcesConfig validates a ResourcesConfig.
+func validateResourcesConfig(c *ResourcesConfig) error {
+	check := func(where string, l *ResourceLimits) error {
+		if l == nil {
+			return nil
+		}
+		if l.CPUWeight < 0 || l.CPUWeight > 10000 {
+			return fmt.Errorf("invalid %scpu_weight %d: must be 1-10000", where, l.CPUWeight)
+		}
+		if l.MemoryMax != "" {
+			if _, err := cgroup.ParseMemory(l.MemoryMax); err != nil {
+				return fmt.Errorf("invalid %smemory_max: %w", where, err)
+			}
+		}
+		if l.PidsMax < 0 {
+			return fmt.Errorf("invalid %spids_max %d", where, l.PidsMax)
+		}
+		return nil
+	}
+	if err := check("resources.", &c.ResourceLimits); err != nil {
+		return err
+	}
+	for role, l := range c.Roles {
+		if err := check("resources.roles."+role+".", l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSandboxConfig validates a SandboxConfig.
+func validateSandboxConfig(c *SandboxConfig) error {
+	if c.Profile == "" {
+		return fmt.Errorf("%w: sandbox.profile", ErrMissingField)
+	}
+	if _, err := sandbox.LookupProfile(c.Profile); err != nil {
+		return err
+	}
+	for _, port := range c.Ports {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid sandbox port %d", port)
+		}
+	}
 	return nil
 }
 
@@ -1002,6 +1061,12 @@ func validateMessagingConfig(c *MessagingConfig) error {
 	if c.NudgeChannels == nil {
 		c.NudgeChannels = make(map[string][]string)
 	}
+	if c.Rules == nil {
+		c.Rules = make(map[string][]MailRule)
+	}
+	if c.Webhooks == nil {
+		c.Webhooks = make(map[string][]WebhookSink)
+	}
 
 	// Validate lists have at least one recipient
 	for name, recipients := range c.Lists {
@@ -1040,6 +1105,79 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	// Validate mailbox rules
+	for mailbox, rules := range c.Rules {
+		if mailbox == "" {
+			return fmt.Errorf("%w: rule mailbox cannot be empty", ErrMissingField)
+		}
+		for i, rule := range rules {
+			if err := validateMailRule(rule); err != nil {
+				return fmt.Errorf("rule %d for '%s': %w", i, mailbox, err)
+			}
+		}
+	}
+
+	// Validate webhook sinks
+	for address, sinks := range c.Webhooks {
+		if !strings.HasPrefix(address, "channel:") && !strings.HasPrefix(address, "announce:") {
+			return fmt.Errorf("%w: webhook key '%s' must be channel:<name> or announce:<name>", ErrMissingField, address)
+		}
+		for i, sink := range sinks {
+			if err := validateWebhookSink(sink); err != nil {
+				return fmt.Errorf("webhook %d for '%s': %w", i, address, err)
+			}
+		}
+	}
+	if c.InboundWebhook != nil && c.InboundWebhook.Secret == "" && c.InboundWebhook.SecretEnv == "" {
+		return fmt.Errorf("%w: inbound_webhook secret or secret_env", ErrMissingField)
+	}
+
+	return nil
+}
+
+// validateWebhookSink checks that a webhook sink has a usable URL and format.
+func validateWebhookSink(sink WebhookSink) error {
+	if sink.URL == "" {
+		return fmt.Errorf("%w: webhook url", ErrMissingField)
+	}
+	if !strings.HasPrefix(sink.URL, "http://") && !strings.HasPrefix(sink.URL, "https://") {
+		return fmt.Errorf("%w: webhook url must be http(s): '%s'", ErrMissingField, sink.URL)
+	}
+	switch sink.Format {
+	case "", WebhookFormatJSON, WebhookFormatSlack, WebhookFormatDiscord, WebhookFormatMatrix:
+	default:
+		return fmt.Errorf("%w: unknown webhook format '%s'", ErrMissingField, sink.Format)
+	}
+	if sink.MaxAttempts < 0 {
+		return fmt.Errorf("%w: webhook max_attempts must be non-negative", ErrMissingField)
+	}
+	return nil
+}
+
+// validateMailRule checks that a mailbox rule has the fields its action needs.
+func validateMailRule(rule MailRule) error {
+	if rule.Match.Priority != "" && !isValidMailPriority(rule.Match.Priority) {
+		return fmt.Errorf("%w: invalid match priority '%s'", ErrMissingField, rule.Match.Priority)
+	}
+	switch rule.Action {
+	case MailRuleArchive, MailRuleSuppressNudge:
+	case MailRuleForward:
+		if rule.Target == "" {
+			return fmt.Errorf("%w: forward rule target", ErrMissingField)
+		}
+	case MailRulePriority:
+		if !isValidMailPriority(rule.Priority) {
+			return fmt.Errorf("%w: invalid rule priority '%s'", ErrMissingField, rule.Priority)
+		}
+	case MailRuleEscalate:
+		if rule.Severity != "" && !IsValidSeverity(rule.Severity) {
+			return fmt.Errorf("%w: invalid rule severity '%s'", ErrMissingField, rule.Severity)
+		}
+	case "":
+		return fmt.Errorf("%w: rule action", ErrMissingField)
+	default:
+		return fmt.Errorf("%w: unknown rule action '%s'", ErrMissingField, rule.Action)
+	}
 	return nil
 }
 
@@ -2254,8 +2392,9 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	// Apply exec wrapper from rig/town settings if not already set on the resolved config.
 	// ExecWrapper is a deployment-level setting (sandbox/container) independent of agent choice.
 	if len(rc.ExecWrapper) == 0 {
-		rc.ExecWrapper = resolveExecWrapper(rigPath)
+		rc.ExecWrapper = resolveExecWrapper(rigPath, role)
 	}
+	rc.ExecWrapper = withCgroupWrapper(rc.ExecWrapper, envVars["GT_ROLE"], townRoot, rigPath)
 
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
@@ -2508,8 +2647,9 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 
 	// Apply exec wrapper from rig/town settings if not already set on the resolved config.
 	if len(rc.ExecWrapper) == 0 {
-		rc.ExecWrapper = resolveExecWrapper(rigPath)
+		rc.ExecWrapper = resolveExecWrapper(rigPath, role)
 	}
+	rc.ExecWrapper = withCgroupWrapper(rc.ExecWrapper, envVars["GT_ROLE"], townRoot, rigPath)
 
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
@@ -2716,17 +2856,103 @@ func BuildCrewStartupCommandWithAgentOverride(rigName, crewName, rigPath, prompt
 // resolveExecWrapper loads the exec_wrapper from rig settings.
 // ExecWrapper is a deployment-level setting (sandbox/container) that wraps the agent binary.
 // It is independent of agent choice — exitbox wraps Claude, Codex, or any other runtime.
-func resolveExecWrapper(rigPath string) []string {
+// An explicit runtime.exec_wrapper wins; otherwise a rig sandbox profile wraps
+// polecats in gt sandbox exec.
+func resolveExecWrapper(rigPath, role string) []string {
 	if rigPath != "" {
 		if rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil && rigSettings != nil {
 			if rigSettings.Runtime != nil && len(rigSettings.Runtime.ExecWrapper) > 0 {
 				return rigSettings.Runtime.ExecWrapper
 			}
+			if role == constants.RolePolecat && rigSettings.Sandbox != nil && rigSettings.Sandbox.Profile != "" {
+				return rigSettings.Sandbox.ExecWrapper()
+			}
 		}
 	}
 	return nil
 }
 
+// ResolveResourceLimits returns the cgroup limits for a role and whether
+// sessions should be placed in cgroups at all (town or rig settings have a
+// resources section). role is the simple role name ("polecat", "witness").
+// Later levels override earlier ones field by field: town defaults, town
+// role, rig defaults, rig role.
+func ResolveResourceLimits(role, townRoot, rigPath string) (ResourceLimits, bool) {
+	var limits ResourceLimits
+	configured := false
+	apply := func(c *ResourcesConfig) {
+		if c == nil {
+			return
+		}
+		configured = true
+		limits.merge(&c.ResourceLimits)
+		limits.merge(c.Roles[role])
+	}
+	if townRoot != "" {
+		if ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil {
+			apply(ts.Resources)
+		}
+	}
+	if rigPath != "" {
+		if rs, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil && rs != nil {
+			apply(rs.Resources)
+		}
+	}
+	return limits, configured
+}
+
+// ResolveCgroupRoot returns the directory session cgroups live under.
+func ResolveCgroupRoot(townRoot string) string {
+	if townRoot != "" {
+		if ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil &&
+			ts.Resources != nil && ts.Resources.CgroupRoot != "" {
+			return ts.Resources.CgroupRoot
+		}
+	}
+	return cgroup.DefaultRoot
+}
+
+// CgroupSessionName returns the rig and leaf group of a session's cgroup.
+// Town-level agents (rigPath empty) live under "hq".
+func CgroupSessionName(gtRole, rigPath string) (rig, session string) {
+	if rigPath == "" {
+		return "hq", strings.ReplaceAll(strings.Trim(gtRole, "/"), "/", "-")
+	}
+	return cgroup.SessionName(gtRole)
+}
+
+// withCgroupWrapper prepends gt cgroup exec to the exec wrapper when
+// resources are configured, so the session (including any sandbox) runs in
+// its own cgroup. The cgroup wrapper must be outermost: the sandbox's user
+// namespace cannot write to the host cgroup tree.
+func withCgroupWrapper(wrapper []string, gtRole, townRoot, rigPath string) []string {
+	if runtime.GOOS != "linux" || gtRole == "" {
+		return wrapper
+	}
+	limits, ok := ResolveResourceLimits(ExtractSimpleRole(gtRole), townRoot, rigPath)
+	if !ok {
+		return wrapper
+	}
+	rig, session := CgroupSessionName(gtRole, rigPath)
+	if rig == "" || session == "" {
+		return wrapper
+	}
+	cg := []string{"gt", "cgroup", "exec",
+		"--root", ShellQuote(ResolveCgroupRoot(townRoot)),
+		"--rig", ShellQuote(rig), "--session", ShellQuote(session)}
+	if limits.CPUWeight > 0 {
+		cg = append(cg, "--cpu-weight", strconv.Itoa(limits.CPUWeight))
+	}
+	if limits.MemoryMax != "" {
+		cg = append(cg, "--memory-max", ShellQuote(limits.MemoryMax))
+	}
+	if limits.PidsMax > 0 {
+		cg = append(cg, "--pids-max", strconv.Itoa(limits.PidsMax))
+	}
+	cg = append(cg, "--")
+	return append(cg, wrapper...)
+}
+
 // ExpectedPaneCommands returns tmux pane command names that indicate the runtime is running.
 // Claude can report as "node" (older versions) or "claude" (newer versions).
 // Other runtimes typically report their executable name.
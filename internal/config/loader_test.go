@@ -910,6 +910,98 @@ func TestMessagingConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid config with rules",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules: map[string][]MailRule{
+					"mayor/": {
+						{Match: MailRuleMatch{Subject: "POLECAT_DONE*"}, Action: MailRuleArchive},
+						{Match: MailRuleMatch{Priority: "urgent"}, Action: MailRuleEscalate, Severity: "high"},
+						{Action: MailRuleForward, Target: "queue:triage"},
+						{Action: MailRulePriority, Priority: "low"},
+						{Action: MailRuleSuppressNudge},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rule with unknown action",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Action: "explode"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "forward rule without target",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Action: MailRuleForward}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "priority rule with invalid priority",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Action: MailRulePriority, Priority: "p0"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "escalate rule with invalid severity",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules:   map[string][]MailRule{"mayor/": {{Action: MailRuleEscalate, Severity: "meh"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid config with webhooks",
+			config: &MessagingConfig{
+				Version: 1,
+				Webhooks: map[string][]WebhookSink{
+					"channel:alerts":  {{URL: "https://hooks.slack.com/x", Format: WebhookFormatSlack}},
+					"announce:status": {{URL: "http://localhost:9000/hook", Template: `{"t": {{json .Subject}}}`}},
+				},
+				InboundWebhook: &InboundWebhookConfig{SecretEnv: "GT_WEBHOOK_SECRET"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "webhook keyed by agent address",
+			config: &MessagingConfig{
+				Version:  1,
+				Webhooks: map[string][]WebhookSink{"mayor/": {{URL: "https://example.com"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook with non-http url",
+			config: &MessagingConfig{
+				Version:  1,
+				Webhooks: map[string][]WebhookSink{"channel:alerts": {{URL: "file:///etc/passwd"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook with unknown format",
+			config: &MessagingConfig{
+				Version:  1,
+				Webhooks: map[string][]WebhookSink{"channel:alerts": {{URL: "https://example.com", Format: "irc"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inbound webhook without secret",
+			config: &MessagingConfig{
+				Version:        1,
+				InboundWebhook: &InboundWebhookConfig{From: "overseer"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -987,6 +1079,77 @@ func TestMessagingConfigPath(t *testing.T) {
 	}
 }
 
+func TestFederationConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := FederationConfigPath(dir)
+
+	cfg := NewFederationConfig()
+	cfg.Name = "laptop"
+	cfg.Hosts = []string{"laptop.local", "10.0.0.5"}
+	cfg.Peers["build"] = &FederationPeer{
+		URL:    "https://build.local:7443",
+		CACert: "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+	}
+	if err := SaveFederationConfig(path, cfg); err != nil {
+		t.Fatalf("SaveFederationConfig: %v", err)
+	}
+
+	loaded, err := LoadFederationConfig(path)
+	if err != nil {
+		t.Fatalf("LoadFederationConfig: %v", err)
+	}
+	if loaded.Name != "laptop" || len(loaded.Hosts) != 2 {
+		t.Errorf("loaded = %+v", loaded)
+	}
+	if p := loaded.Peers["build"]; p == nil || p.URL != "https://build.local:7443" {
+		t.Errorf("peer build = %+v", p)
+	}
+}
+
+func TestFederationConfigValidation(t *testing.T) {
+	t.Parallel()
+	const pem = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	tests := []struct {
+		name    string
+		peers   map[string]*FederationPeer
+		town    string
+		wantErr bool
+	}{
+		{"no peers", nil, "laptop", false},
+		{"valid peer", map[string]*FederationPeer{"build": {URL: "https://b:7443", CACert: pem}}, "laptop", false},
+		{"http url", map[string]*FederationPeer{"build": {URL: "http://b:7443", CACert: pem}}, "laptop", true},
+		{"missing ca", map[string]*FederationPeer{"build": {URL: "https://b:7443"}}, "laptop", true},
+		{"reserved name", map[string]*FederationPeer{"channel": {URL: "https://b:7443", CACert: pem}}, "laptop", true},
+		{"name with slash", map[string]*FederationPeer{"a/b": {URL: "https://b:7443", CACert: pem}}, "laptop", true},
+		{"bad town name", nil, "my:town", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFederationConfig()
+			cfg.Name = tt.town
+			if tt.peers != nil {
+				cfg.Peers = tt.peers
+			}
+			err := validateFederationConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFederationConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadOrCreateFederationConfigMissing(t *testing.T) {
+	t.Parallel()
+	cfg, err := LoadOrCreateFederationConfig(FederationConfigPath(t.TempDir()))
+	if err != nil {
+		t.Fatalf("LoadOrCreateFederationConfig: %v", err)
+	}
+	if cfg.Type != "federation" || cfg.Peers == nil {
+		t.Errorf("default config = %+v", cfg)
+	}
+}
+
 func TestRuntimeConfigDefaults(t *testing.T) {
 	t.Parallel()
 	rc := DefaultRuntimeConfig()
@@ -53,9 +53,11 @@ const (
 	// Pressure check defaults — fully opt-in. All zero = disabled.
 	// Configure in settings/config.json under operational.daemon to enable.
 	// Example: {"pressure_cpu_threshold": 3.0, "pressure_mem_threshold_gb": 0.5}
-	DefaultPressureCPUThreshold   = 0.0
-	DefaultPressureMemThresholdGB = 0.0
-	DefaultPressureMaxSessions    = 0
+	DefaultPressureCPUThreshold        = 0.0
+	DefaultPressureMemThresholdGB      = 0.0
+	DefaultPressureMaxSessions         = 0
+	DefaultPressureSliceCPUThreshold   = 0.0
+	DefaultPressureSliceMemThresholdGB = 0.0
 )
 
 // Deacon defaults.
@@ -105,6 +107,11 @@ const (
 	DefaultWebMaxBodyLen        = 100_000
 )
 
+// Memory defaults.
+const (
+	DefaultMemoryPrimeTokenBudget = 2000
+)
+
 // Witness defaults.
 const (
 	DefaultWitnessStartupStallThreshold  = 90 * time.Second
@@ -113,6 +120,10 @@ const (
 	DefaultWitnessDoneIntentStuckTimeout    = 60 * time.Second
 	DefaultWitnessDoneIntentRecentGrace     = 30 * time.Second
 	DefaultWitnessHeartbeatStartupGrace     = 5 * time.Minute
+	DefaultWitnessThrashRepeatedCalls       = 5
+	DefaultWitnessThrashOscillations        = 2
+	DefaultWitnessThrashRepeatedErrors      = 5
+	DefaultWitnessThrashLookback            = 30 * time.Minute
 )
 
 // LoadOperationalConfig loads operational config from a town root.
@@ -427,6 +438,24 @@ func (d *DaemonThresholds) PressureMaxSessionsV() int {
 	return DefaultPressureMaxSessions
 }
 
+// PressureSliceCPUThresholdV returns the configured or default share of host CPU
+// agent session cgroups may use before spawns are deferred (0 = disabled).
+func (d *DaemonThresholds) PressureSliceCPUThresholdV() float64 {
+	if d != nil && d.PressureSliceCPUThreshold != nil {
+		return *d.PressureSliceCPUThreshold
+	}
+	return DefaultPressureSliceCPUThreshold
+}
+
+// PressureSliceMemThresholdGBV returns the configured or default total session
+// cgroup memory in GB above which spawns are deferred (0 = disabled).
+func (d *DaemonThresholds) PressureSliceMemThresholdGBV() float64 {
+	if d != nil && d.PressureSliceMemThresholdGB != nil {
+		return *d.PressureSliceMemThresholdGB
+	}
+	return DefaultPressureSliceMemThresholdGB
+}
+
 // --- Deacon accessors ---
 
 // GetDeaconConfig returns the deacon thresholds, never nil.
@@ -694,6 +723,24 @@ func (w *WebThresholds) MaxBodyLenV() int {
 	return DefaultWebMaxBodyLen
 }
 
+// --- Memory accessors ---
+
+// GetMemoryConfig returns the memory thresholds, never nil.
+func (c *OperationalConfig) GetMemoryConfig() *MemoryThresholds {
+	if c != nil && c.Memory != nil {
+		return c.Memory
+	}
+	return &MemoryThresholds{}
+}
+
+// PrimeTokenBudgetV returns the configured or default prime memory token budget.
+func (m *MemoryThresholds) PrimeTokenBudgetV() int {
+	if m != nil && m.PrimeTokenBudget != nil {
+		return *m.PrimeTokenBudget
+	}
+	return DefaultMemoryPrimeTokenBudget
+}
+
 // --- Witness accessors ---
 
 // GetWitnessConfig returns the witness thresholds, never nil.
@@ -753,3 +800,35 @@ func (wt *WitnessThresholds) HeartbeatStartupGraceD() time.Duration {
 	}
 	return DefaultWitnessHeartbeatStartupGrace
 }
+
+// ThrashRepeatedCallsV returns the configured or default identical tool call limit.
+func (wt *WitnessThresholds) ThrashRepeatedCallsV() int {
+	if wt != nil && wt.ThrashRepeatedCalls != nil {
+		return *wt.ThrashRepeatedCalls
+	}
+	return DefaultWitnessThrashRepeatedCalls
+}
+
+// ThrashOscillationsV returns the configured or default edit reversal limit.
+func (wt *WitnessThresholds) ThrashOscillationsV() int {
+	if wt != nil && wt.ThrashOscillations != nil {
+		return *wt.ThrashOscillations
+	}
+	return DefaultWitnessThrashOscillations
+}
+
+// ThrashRepeatedErrorsV returns the configured or default identical tool error limit.
+func (wt *WitnessThresholds) ThrashRepeatedErrorsV() int {
+	if wt != nil && wt.ThrashRepeatedErrors != nil {
+		return *wt.ThrashRepeatedErrors
+	}
+	return DefaultWitnessThrashRepeatedErrors
+}
+
+// ThrashLookbackD returns the configured or default thrash detection lookback.
+func (wt *WitnessThresholds) ThrashLookbackD() time.Duration {
+	if wt != nil {
+		return ParseDurationOrDefault(wt.ThrashLookback, DefaultWitnessThrashLookback)
+	}
+	return DefaultWitnessThrashLookback
+}
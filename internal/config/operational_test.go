@@ -361,6 +361,12 @@ func TestWitnessThresholds_Defaults(t *testing.T) {
 	if got := wit.DoneIntentRecentGraceD(); got != DefaultWitnessDoneIntentRecentGrace {
 		t.Errorf("DoneIntentRecentGrace: got %v, want %v", got, DefaultWitnessDoneIntentRecentGrace)
 	}
+	if got := wit.ThrashRepeatedCallsV(); got != DefaultWitnessThrashRepeatedCalls {
+		t.Errorf("ThrashRepeatedCalls: got %v, want %v", got, DefaultWitnessThrashRepeatedCalls)
+	}
+	if got := wit.ThrashLookbackD(); got != DefaultWitnessThrashLookback {
+		t.Errorf("ThrashLookback: got %v, want %v", got, DefaultWitnessThrashLookback)
+	}
 }
 
 func TestWitnessThresholds_Overrides(t *testing.T) {
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeResources saves town and rig settings with the given resources
+// sections and returns the town root and rig path.
+func writeResources(t *testing.T, town, rig *ResourcesConfig) (string, string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	if town != nil {
+		ts := NewTownSettings()
+		ts.Resources = town
+		if err := SaveTownSettings(TownSettingsPath(townRoot), ts); err != nil {
+			t.Fatalf("SaveTownSettings: %v", err)
+		}
+	}
+	if rig != nil {
+		rs := NewRigSettings()
+		rs.Resources = rig
+		if err := SaveRigSettings(RigSettingsPath(rigPath), rs); err != nil {
+			t.Fatalf("SaveRigSettings: %v", err)
+		}
+	}
+	return townRoot, rigPath
+}
+
+func TestResolveResourceLimits_Precedence(t *testing.T) {
+	t.Parallel()
+
+	townRoot, rigPath := writeResources(t,
+		&ResourcesConfig{
+			ResourceLimits: ResourceLimits{CPUWeight: 100, MemoryMax: "8G", PidsMax: 4096},
+			Roles:          map[string]*ResourceLimits{"polecat": {CPUWeight: 50, MemoryMax: "4G"}},
+		},
+		&ResourcesConfig{
+			ResourceLimits: ResourceLimits{MemoryMax: "6G"},
+			Roles:          map[string]*ResourceLimits{"polecat": {PidsMax: 1024}},
+		},
+	)
+
+	got, ok := ResolveResourceLimits("polecat", townRoot, rigPath)
+	want := ResourceLimits{CPUWeight: 50, MemoryMax: "6G", PidsMax: 1024}
+	if !ok || got != want {
+		t.Errorf("polecat limits = %+v, %v; want %+v", got, ok, want)
+	}
+
+	got, _ = ResolveResourceLimits("witness", townRoot, rigPath)
+	want = ResourceLimits{CPUWeight: 100, MemoryMax: "6G", PidsMax: 4096}
+	if got != want {
+		t.Errorf("witness limits = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveResourceLimits_UnconfiguredTown(t *testing.T) {
+	t.Parallel()
+
+	townRoot, rigPath := writeResources(t, nil, nil)
+	if _, ok := ResolveResourceLimits("polecat", townRoot, rigPath); ok {
+		t.Error("resources reported configured without a resources section")
+	}
+	if got := ResolveCgroupRoot(townRoot); got != "/sys/fs/cgroup/gastown.slice" {
+		t.Errorf("ResolveCgroupRoot = %q, want default", got)
+	}
+}
+
+func TestBuildStartupCommand_CgroupWrapperOutermost(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroup wrapper is Linux-only")
+	}
+	t.Parallel()
+
+	townRoot, rigPath := writeResources(t,
+		&ResourcesConfig{CgroupRoot: "/sys/fs/cgroup/gt.slice"},
+		&ResourcesConfig{Roles: map[string]*ResourceLimits{"polecat": {CPUWeight: 50, MemoryMax: "4G", PidsMax: 512}}},
+	)
+	rs, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.Sandbox = &SandboxConfig{Profile: "polecat"}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rs); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/polecats/Toast"}, rigPath, "")
+	want := "gt cgroup exec --root /sys/fs/cgroup/gt.slice --rig testrig --session polecat-Toast " +
+		"--cpu-weight 50 --memory-max 4G --pids-max 512 -- gt sandbox exec --profile polecat -- "
+	if !strings.Contains(cmd, want) {
+		t.Errorf("polecat startup command = %q, want it to contain %q", cmd, want)
+	}
+
+	// Roles without limits of their own still get a group for accounting.
+	cmd = BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/witness"}, rigPath, "")
+	if !strings.Contains(cmd, "gt cgroup exec --root /sys/fs/cgroup/gt.slice --rig testrig --session witness -- ") {
+		t.Errorf("witness startup command = %q", cmd)
+	}
+
+	// Town-level agents are grouped under hq.
+	cmd = BuildStartupCommand(map[string]string{"GT_ROLE": "mayor", "GT_ROOT": townRoot}, "", "")
+	if !strings.Contains(cmd, "--rig hq --session mayor -- ") {
+		t.Errorf("mayor startup command = %q", cmd)
+	}
+}
+
+func TestBuildStartupCommand_NoCgroupWrapperByDefault(t *testing.T) {
+	t.Parallel()
+
+	_, rigPath := writeResources(t, nil, nil)
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/polecats/Toast"}, rigPath, "")
+	if strings.Contains(cmd, "gt cgroup exec") {
+		t.Errorf("startup command wrapped without resources config: %q", cmd)
+	}
+}
+
+func TestRigSettings_ResourcesValidated(t *testing.T) {
+	t.Parallel()
+
+	for _, bad := range []*ResourcesConfig{
+		{ResourceLimits: ResourceLimits{MemoryMax: "lots"}},
+		{ResourceLimits: ResourceLimits{CPUWeight: 20000}},
+		{Roles: map[string]*ResourceLimits{"polecat": {PidsMax: -1}}},
+	} {
+		path := RigSettingsPath(t.TempDir())
+		rs := NewRigSettings()
+		rs.Resources = bad
+		if err := SaveRigSettings(path, rs); err == nil {
+			if _, err := LoadRigSettings(path); err == nil {
+				t.Errorf("rig settings with resources %+v loaded without error", bad)
+			}
+		}
+	}
+}
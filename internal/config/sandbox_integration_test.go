@@ -399,3 +399,52 @@ echo "DONE"
 		t.Fatal("sandbox breach: file was created outside project dir")
 	}
 }
+
+// TestSandbox_RigProfileWrapsPolecats verifies that a rig's sandbox profile
+// selects the built-in Linux sandbox as the polecat exec wrapper, and only
+// for polecats.
+func TestSandbox_RigProfileWrapsPolecats(t *testing.T) {
+	t.Parallel()
+
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	rigSettings := NewRigSettings()
+	rigSettings.Sandbox = &SandboxConfig{Profile: "polecat", Writable: []string{"/srv/cache dir"}, Ports: []int{8080}}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	polecatCmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/polecats/test"}, rigPath, "")
+	want := "gt sandbox exec --profile polecat --allow-write '/srv/cache dir' --allow-port 8080 -- "
+	if !strings.Contains(polecatCmd, want) {
+		t.Errorf("polecat startup command = %q, want it to contain %q", polecatCmd, want)
+	}
+
+	witnessCmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/witness"}, rigPath, "")
+	if strings.Contains(witnessCmd, "gt sandbox exec") {
+		t.Errorf("witness startup command is sandboxed: %q", witnessCmd)
+	}
+
+	// An explicit runtime exec wrapper takes precedence over the profile.
+	rigSettings.Runtime = &RuntimeConfig{ExecWrapper: []string{"exitbox", "run", "--"}}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	polecatCmd = BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/polecats/test"}, rigPath, "")
+	if strings.Contains(polecatCmd, "gt sandbox exec") || !strings.Contains(polecatCmd, "exitbox run --") {
+		t.Errorf("runtime exec_wrapper did not take precedence: %q", polecatCmd)
+	}
+}
+
+func TestSandbox_RigProfileValidated(t *testing.T) {
+	t.Parallel()
+
+	path := RigSettingsPath(t.TempDir())
+	rigSettings := NewRigSettings()
+	rigSettings.Sandbox = &SandboxConfig{Profile: "bogus"}
+	if err := SaveRigSettings(path, rigSettings); err == nil {
+		if _, err := LoadRigSettings(path); err == nil {
+			t.Fatal("rig settings with unknown sandbox profile loaded without error")
+		}
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/warrant"
 )
 
 // TownConfig represents the main town identity (mayor/town.json).
@@ -124,6 +126,10 @@ type TownSettings struct {
 	// "main_branch_test", "handler").
 	// Example: ["doctor_dog", "compactor_dog"]
 	DisabledPatrols []string `json:"disabled_patrols,omitempty"`
+
+	// Resources places agent sessions in per-session cgroup v2 groups with
+	// these limits (Linux only). Rig settings override it per rig.
+	Resources *ResourcesConfig `json:"resources,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -243,6 +249,9 @@ type OperationalConfig struct {
 
 	// Witness configures witness patrol thresholds.
 	Witness *WitnessThresholds `json:"witness,omitempty"`
+
+	// Memory configures memory injection during gt prime.
+	Memory *MemoryThresholds `json:"memory,omitempty"`
 }
 
 // SessionThresholds configures session management timeouts.
@@ -372,6 +381,17 @@ type DaemonThresholds struct {
 	// PressureMaxSessions is the maximum number of concurrent agent tmux
 	// sessions before new non-infrastructure spawns are deferred. Disabled by default (0 = unlimited).
 	PressureMaxSessions *int `json:"pressure_max_sessions,omitempty"`
+
+	// PressureSliceCPUThreshold is the share of host CPU (0-1) that agent
+	// session cgroups may use, averaged over the last heartbeat, before new
+	// non-infrastructure spawns are deferred. Requires resources to be
+	// configured so sessions run in cgroups. Disabled by default (0).
+	PressureSliceCPUThreshold *float64 `json:"pressure_slice_cpu_threshold,omitempty"`
+
+	// PressureSliceMemThresholdGB is the total memory (in GB) of agent session
+	// cgroups above which new non-infrastructure spawns are deferred.
+	// Disabled by default (0).
+	PressureSliceMemThresholdGB *float64 `json:"pressure_slice_mem_threshold_gb,omitempty"`
 }
 
 // DeaconThresholds configures deacon health-check and dispatch thresholds.
@@ -472,6 +492,13 @@ type WebThresholds struct {
 	MaxBodyLen *int `json:"max_body_len,omitempty"`
 }
 
+// MemoryThresholds configures memory injection during gt prime.
+type MemoryThresholds struct {
+	// PrimeTokenBudget caps the estimated tokens of memories injected by
+	// gt prime (default 2000). Zero or negative means no limit.
+	PrimeTokenBudget *int `json:"prime_token_budget,omitempty"`
+}
+
 // WitnessThresholds configures witness patrol detection thresholds.
 type WitnessThresholds struct {
 	// StartupStallThreshold is the minimum session age before a session with no
@@ -499,6 +526,26 @@ type WitnessThresholds struct {
 	// possibly stuck at startup (e.g., auth 401 blocking initialization, default "5m").
 	// The witness exposes the signal; patrol formula decides whether to escalate.
 	HeartbeatStartupGrace string `json:"heartbeat_startup_grace,omitempty"`
+
+	// WarrantPolicies declare when the witness files a death warrant for a
+	// stuck polecat (see warrant.Policy). Empty disables automatic filing.
+	WarrantPolicies []warrant.Policy `json:"warrant_policies,omitempty"`
+
+	// ThrashRepeatedCalls is how many identical tool calls among a polecat's
+	// recent calls count as a loop (default 5, 0 disables).
+	ThrashRepeatedCalls *int `json:"thrash_repeated_calls,omitempty"`
+
+	// ThrashOscillations is how many times a polecat may edit a file back to
+	// an earlier state before it is considered thrashing (default 2, 0 disables).
+	ThrashOscillations *int `json:"thrash_oscillations,omitempty"`
+
+	// ThrashRepeatedErrors is how many identical tool errors among recent
+	// results count as a loop (default 5, 0 disables).
+	ThrashRepeatedErrors *int `json:"thrash_repeated_errors,omitempty"`
+
+	// ThrashLookback is how far back in the conversation log thrash
+	// detection looks (default "30m").
+	ThrashLookback string `json:"thrash_lookback,omitempty"`
 }
 
 // DefaultOperationalConfig returns an OperationalConfig with all defaults.
@@ -707,6 +754,94 @@ type RigSettings struct {
 	// Values are effort levels: "low", "medium", "high", "max".
 	// Example: {"crew": "max", "witness": "low"}
 	RoleEffort map[string]string `json:"role_effort,omitempty"`
+
+	// Sandbox runs this rig's polecats under the built-in Linux sandbox
+	// (gt sandbox exec). Ignored when runtime.exec_wrapper is set.
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// Resources overrides the town's per-session cgroup limits for this rig.
+	Resources *ResourcesConfig `json:"resources,omitempty"`
+}
+
+// SandboxConfig selects the built-in Linux sandbox for a rig's polecats.
+type SandboxConfig struct {
+	// Profile names a built-in profile: "polecat" (worktree and tmp writes,
+	// loopback network to Dolt and the proxy), "offline" or "network".
+	// See gt sandbox profiles.
+	Profile string `json:"profile"`
+
+	// Writable lists extra paths polecats may write besides their worktree
+	// and tmp.
+	Writable []string `json:"writable,omitempty"`
+
+	// Ports lists extra host loopback ports reachable under the polecat
+	// profile, in addition to Dolt and the proxy.
+	Ports []int `json:"ports,omitempty"`
+}
+
+// ExecWrapper returns the gt sandbox exec prefix for this configuration.
+func (c *SandboxConfig) ExecWrapper() []string {
+	wrapper := []string{"gt", "sandbox", "exec", "--profile", ShellQuote(c.Profile)}
+	for _, path := range c.Writable {
+		wrapper = append(wrapper, "--allow-write", ShellQuote(path))
+	}
+	for _, port := range c.Ports {
+		wrapper = append(wrapper, "--allow-port", strconv.Itoa(port))
+	}
+	return append(wrapper, "--")
+}
+
+// ResourceLimits are cgroup v2 limits for one agent session. Zero values
+// inherit from the next less specific level.
+type ResourceLimits struct {
+	// CPUWeight is the session's relative CPU share under contention
+	// (1-10000, kernel default 100).
+	CPUWeight int `json:"cpu_weight,omitempty"`
+
+	// MemoryMax caps the session's memory, e.g. "4G" or "512M". The kernel
+	// OOM-kills inside the session when it is exceeded.
+	MemoryMax string `json:"memory_max,omitempty"`
+
+	// PidsMax caps the number of processes and threads in the session.
+	PidsMax int `json:"pids_max,omitempty"`
+}
+
+// ResourcesConfig configures per-session cgroup v2 limits. Sessions are
+// only placed in cgroups when town or rig settings contain this section.
+//
+// Resolution, most specific last: town defaults, town roles[role], rig
+// defaults, rig roles[role].
+//
+//	"resources": {
+//	  "memory_max": "4G",
+//	  "roles": {"polecat": {"cpu_weight": 50, "pids_max": 2048}}
+//	}
+type ResourcesConfig struct {
+	ResourceLimits
+
+	// Roles overrides the defaults per role: "polecat", "crew", "witness",
+	// "refinery", "mayor", "deacon".
+	Roles map[string]*ResourceLimits `json:"roles,omitempty"`
+
+	// CgroupRoot is the cgroup directory sessions are created under
+	// (town settings only). Default: /sys/fs/cgroup/gastown.slice.
+	CgroupRoot string `json:"cgroup_root,omitempty"`
+}
+
+// merge overlays the non-zero fields of o onto l.
+func (l *ResourceLimits) merge(o *ResourceLimits) {
+	if o == nil {
+		return
+	}
+	if o.CPUWeight != 0 {
+		l.CPUWeight = o.CPUWeight
+	}
+	if o.MemoryMax != "" {
+		l.MemoryMax = o.MemoryMax
+	}
+	if o.PidsMax != 0 {
+		l.PidsMax = o.PidsMax
+	}
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -1621,6 +1756,153 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Rules are per-mailbox filing rules evaluated at delivery time.
+	// Keys are recipient addresses ("mayor/", "gastown/witness") or queue
+	// addresses ("queue:work/gastown"). Rules run in order; a matching rule
+	// with Stop set ends evaluation for that message.
+	// Example: {"mayor/": [{"match": {"subject": "POLECAT_DONE*"}, "action": "archive"}]}
+	Rules map[string][]MailRule `json:"rules,omitempty"`
+
+	// Webhooks are outbound HTTP sinks for channels and announces. Keys are
+	// "channel:<name>" or "announce:<name>"; every message posted there is
+	// also delivered to each sink.
+	// Example: {"channel:alerts": [{"url": "https://hooks.slack.com/...", "format": "slack"}]}
+	Webhooks map[string][]WebhookSink `json:"webhooks,omitempty"`
+
+	// InboundWebhook enables the dashboard endpoint that posts replies from
+	// external chat back into a bridged thread (POST /api/webhooks/inbound).
+	InboundWebhook *InboundWebhookConfig `json:"inbound_webhook,omitempty"`
+}
+
+// Webhook payload formats.
+const (
+	WebhookFormatJSON    = "json"    // generic JSON object with all message fields (default)
+	WebhookFormatSlack   = "slack"   // Slack incoming webhook: {"text": ...}
+	WebhookFormatDiscord = "discord" // Discord webhook: {"content": ...}
+	WebhookFormatMatrix  = "matrix"  // Matrix m.text event: {"msgtype": "m.text", "body": ...}
+)
+
+// WebhookSink is an outbound webhook for a channel or announce.
+type WebhookSink struct {
+	// Name identifies the sink in logs and the dead-letter file (optional).
+	Name string `json:"name,omitempty"`
+
+	// URL is the endpoint messages are POSTed to.
+	URL string `json:"url"`
+
+	// Format selects a built-in payload shape: json, slack, discord, matrix.
+	// Ignored when Template is set.
+	Format string `json:"format,omitempty"`
+
+	// Template is a Go text/template rendering the request body. Fields:
+	// .Address .Name .ID .ThreadID .From .Subject .Body .Priority .Timestamp.
+	// The "json" function quotes a value as a JSON string.
+	Template string `json:"template,omitempty"`
+
+	// Headers are added to every request (e.g. Authorization).
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// MaxAttempts is the number of delivery attempts before the payload is
+	// written to the dead-letter file (default 3).
+	MaxAttempts int `json:"max_attempts,omitempty"`
+}
+
+// InboundWebhookConfig configures replies from external chat.
+type InboundWebhookConfig struct {
+	// Secret is the shared secret callers must present, either as
+	// "Authorization: Bearer <secret>" or as an HMAC-SHA256 of the request
+	// body in "X-Gastown-Signature: sha256=<hex>".
+	Secret string `json:"secret,omitempty"`
+
+	// SecretEnv names an environment variable holding the secret, so it
+	// need not be stored in messaging.json. Takes precedence over Secret.
+	SecretEnv string `json:"secret_env,omitempty"`
+
+	// From is the sender address replies are posted as (default "overseer").
+	From string `json:"from,omitempty"`
+}
+
+// ResolveSecret returns the configured shared secret, preferring SecretEnv.
+func (c *InboundWebhookConfig) ResolveSecret() string {
+	if c == nil {
+		return ""
+	}
+	if c.SecretEnv != "" {
+		if v := os.Getenv(c.SecretEnv); v != "" {
+			return v
+		}
+	}
+	return c.Secret
+}
+
+// Mail rule actions.
+const (
+	// MailRuleArchive files the message straight into the archive without
+	// delivering it to the inbox or notifying the recipient.
+	MailRuleArchive = "archive"
+
+	// MailRuleForward delivers a copy of the message to Target (an address
+	// or queue:name). The original is still delivered.
+	MailRuleForward = "forward"
+
+	// MailRulePriority rewrites the message priority to Priority.
+	MailRulePriority = "priority"
+
+	// MailRuleEscalate files an escalation bead for the message with Severity.
+	MailRuleEscalate = "escalate"
+
+	// MailRuleSuppressNudge delivers the message without notifying the
+	// recipient's session.
+	MailRuleSuppressNudge = "suppress_nudge"
+)
+
+// MailRule is a single mailbox rule: when Match matches an incoming message,
+// Action is applied.
+type MailRule struct {
+	// Name identifies the rule in logs and escalation sources (optional).
+	Name string `json:"name,omitempty"`
+
+	// Match selects the messages this rule applies to. An empty match
+	// matches every message.
+	Match MailRuleMatch `json:"match"`
+
+	// Action is one of archive, forward, priority, escalate, suppress_nudge.
+	Action string `json:"action"`
+
+	// Target is the forward destination (forward action only).
+	Target string `json:"target,omitempty"`
+
+	// Priority is the new message priority: low, normal, high, urgent
+	// (priority action only).
+	Priority string `json:"priority,omitempty"`
+
+	// Severity is the escalation severity (escalate action only, default medium).
+	Severity string `json:"severity,omitempty"`
+
+	// Stop ends rule evaluation after this rule matches.
+	Stop bool `json:"stop,omitempty"`
+}
+
+// MailRuleMatch holds the match criteria for a MailRule. All non-empty
+// fields must match. From, Subject and Thread are glob patterns where '*'
+// matches any run of characters; Subject matching is case-insensitive.
+type MailRuleMatch struct {
+	From     string `json:"from,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	Priority string `json:"priority,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Thread   string `json:"thread,omitempty"`
+}
+
+// isValidMailPriority reports whether p is a mail priority name.
+func isValidMailPriority(p string) bool {
+	switch p {
+	case "low", "normal", "high", "urgent":
+		return true
+	default:
+		return false
+	}
 }
 
 // QueueConfig represents a work queue configuration.
@@ -1655,6 +1937,8 @@ func NewMessagingConfig() *MessagingConfig {
 		Queues:        make(map[string]QueueConfig),
 		Announces:     make(map[string]AnnounceConfig),
 		NudgeChannels: make(map[string][]string),
+		Rules:         make(map[string][]MailRule),
+		Webhooks:      make(map[string][]WebhookSink),
 	}
 }
 
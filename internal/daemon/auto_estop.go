@@ -0,0 +1,366 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/estop"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	defaultAutoEstopInterval = time.Minute
+	// autoEstopTimeout bounds the `gt estop` call that freezes sessions.
+	autoEstopTimeout = 2 * time.Minute
+	// autoEstopTailBytes caps how much of the events log is scanned per
+	// cycle. Trigger windows are minutes to hours, so the tail suffices.
+	autoEstopTailBytes = 8 << 20
+	// autoEstopStateFile holds the triggers' last fire times, under the
+	// town's daemon directory.
+	autoEstopStateFile = "auto_estop.json"
+)
+
+// AutoEstopConfig holds configuration for the auto_estop patrol.
+// Each trigger watches one anomaly signal and trips the E-stop — town-wide
+// or for the offending rig — when its threshold is reached.
+//
+// Example mayor/daemon.json:
+//
+//	"auto_estop": {
+//	  "enabled": true,
+//	  "triggers": [
+//	    {"kind": "mass_death", "threshold": 2, "window": "30m"},
+//	    {"kind": "main_commit_rate", "threshold": 3, "window": "5m", "scope": "rig"},
+//	    {"kind": "disk_space", "threshold": 97}
+//	  ]
+//	}
+type AutoEstopConfig struct {
+	// Enabled controls whether the triggers are evaluated.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to evaluate, as a string (e.g., "1m").
+	IntervalStr string `json:"interval,omitempty"`
+
+	// Triggers are the rules to evaluate. See estop.Rule. They are re-read
+	// every cycle, so edits apply without restarting the daemon.
+	Triggers []estop.Rule `json:"triggers,omitempty"`
+}
+
+// autoEstopInterval returns the configured interval, or the default (1m).
+// The interval is read when the daemon starts; the rest of the config is
+// re-read every tick.
+func autoEstopInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AutoEstop != nil {
+		if config.Patrols.AutoEstop.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.AutoEstop.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultAutoEstopInterval
+}
+
+// autoEstopRules returns the triggers configured in config.
+func autoEstopRules(config *DaemonPatrolConfig) []estop.Rule {
+	if config != nil && config.Patrols != nil && config.Patrols.AutoEstop != nil {
+		return config.Patrols.AutoEstop.Triggers
+	}
+	return nil
+}
+
+// autoEstopStatePath returns where the triggers' fire times are kept.
+func autoEstopStatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", autoEstopStateFile)
+}
+
+// refreshAutoEstop re-reads mayor/daemon.json, reporting whether the
+// patrol is enabled, and rebuilds the monitor when the triggers differ from
+// the ones it was built from. An unreadable config keeps the current state.
+// Fire times are restored from disk, so neither an edit nor a daemon
+// restart lets the observations behind an earlier stop trip it again after
+// a thaw.
+func (d *Daemon) refreshAutoEstop() bool {
+	townRoot := d.config.TownRoot
+	config := LoadPatrolConfig(townRoot)
+	if config == nil {
+		if d.autoEstop != nil {
+			return d.autoEstopEnabled
+		}
+		config = d.patrolConfig
+	}
+	d.autoEstopEnabled = !d.disabledPatrols["auto_estop"] && IsPatrolEnabled(config, "auto_estop")
+	rules := autoEstopRules(config)
+	if d.autoEstop != nil && reflect.DeepEqual(rules, d.autoEstopTriggers) {
+		return d.autoEstopEnabled
+	}
+
+	monitor, errs := estop.NewMonitor(rules)
+	for _, err := range errs {
+		d.logger.Printf("auto_estop: ignoring %v", err)
+	}
+	if err := monitor.LoadFired(autoEstopStatePath(townRoot)); err != nil {
+		d.logger.Printf("auto_estop: loading fire times: %v", err)
+	}
+	d.autoEstop = monitor
+	d.autoEstopTriggers = rules
+	return d.autoEstopEnabled
+}
+
+// runAutoEstop starts an auto E-stop pass in the background. Collecting
+// samples reads the events and costs logs and a trip shells out to
+// `gt estop`, so it stays off the main loop. A tick that arrives while a
+// pass is still running is skipped.
+func (d *Daemon) runAutoEstop() {
+	if !d.autoEstopRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer d.autoEstopRunning.Store(false)
+		d.checkAutoEstop()
+	}()
+}
+
+// checkAutoEstop evaluates the configured triggers and trips the E-stop for
+// each breach. The daemon only decides; freezing sessions is delegated to
+// `gt estop --auto` so automatic and manual stops behave identically.
+func (d *Daemon) checkAutoEstop() {
+	townRoot := d.config.TownRoot
+	if estop.IsActive(townRoot) {
+		return
+	}
+	if !d.refreshAutoEstop() || len(d.autoEstop.Rules()) == 0 {
+		return
+	}
+
+	now := time.Now()
+	samples := d.collectEstopSamples(d.autoEstop.Rules(), now)
+	for _, b := range d.autoEstop.Check(samples, now) {
+		if b.Rig != "" && estop.IsRigActive(townRoot, b.Rig) {
+			continue
+		}
+		reason := b.Reason()
+		d.logger.Printf("auto_estop: TRIGGERED %s", reason)
+		d.autoEstop.MarkFired(b, now)
+		if err := d.autoEstop.SaveFired(autoEstopStatePath(townRoot)); err != nil {
+			d.logger.Printf("auto_estop: saving fire times: %v", err)
+		}
+		if err := d.tripEstop(b.Rig, reason); err != nil {
+			d.logger.Printf("auto_estop: gt estop failed: %v", err)
+			// Fall back to the sentinel alone so the heartbeat stops
+			// restarting agents even if sessions could not be frozen.
+			if b.Rig != "" {
+				_ = estop.ActivateRig(townRoot, b.Rig, estop.TriggerAuto, reason)
+			} else {
+				_ = estop.Activate(townRoot, estop.TriggerAuto, reason)
+			}
+		}
+		_ = events.LogFeed(events.TypeAutoEstop, "daemon",
+			events.AutoEstopPayload(b.Rule.Kind, b.Rig, b.Value, b.Rule.Threshold, reason))
+		if b.Rig == "" {
+			return // town is stopped; nothing left to evaluate
+		}
+	}
+}
+
+// tripEstop shells out to `gt estop --auto` for the town or a single rig.
+func (d *Daemon) tripEstop(rig, reason string) error {
+	ctx, cancel := context.WithTimeout(d.ctx, autoEstopTimeout)
+	defer cancel()
+
+	args := []string{"estop", "--auto", "--reason", reason}
+	if rig != "" {
+		args = append(args, "--rig", rig)
+	}
+	cmd := exec.CommandContext(ctx, d.gtPath, args...) //nolint:gosec // G204: gtPath resolved at daemon init
+	cmd.Dir = d.config.TownRoot
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// collectEstopSamples gathers observations for the signal kinds the rules
+// use, looking back as far as the longest window.
+func (d *Daemon) collectEstopSamples(rules []estop.Rule, now time.Time) []estop.Sample {
+	kinds := make(map[string]bool)
+	var lookback time.Duration
+	for _, r := range rules {
+		kinds[r.Kind] = true
+		lookback = max(lookback, r.WindowDuration())
+	}
+	since := now.Add(-lookback)
+	townRoot := d.config.TownRoot
+
+	var samples []estop.Sample
+	if kinds[estop.KindMassDeath] || kinds[estop.KindMainCommitRate] || kinds[estop.KindPushRejections] {
+		samples = append(samples, eventEstopSamples(filepath.Join(townRoot, events.EventsFile), since)...)
+	}
+	if kinds[estop.KindCostBurn] {
+		samples = append(samples, costEstopSamples(costsLogPath(), since)...)
+	}
+	if kinds[estop.KindDiskSpace] {
+		if info, err := util.GetDiskSpace(townRoot); err == nil {
+			samples = append(samples, estop.Sample{Kind: estop.KindDiskSpace, At: now, Value: info.UsedPercent})
+		}
+		for _, rig := range d.getKnownRigs() {
+			if info, err := util.GetDiskSpace(filepath.Join(townRoot, rig)); err == nil {
+				samples = append(samples, estop.Sample{Kind: estop.KindDiskSpace, Rigs: []string{rig}, At: now, Value: info.UsedPercent})
+			}
+		}
+	}
+	return samples
+}
+
+// openTail opens an append-only JSONL log positioned autoEstopTailBytes
+// before its end, or at the start if it is smaller. The first line read
+// after a seek is usually partial; callers skip lines that do not parse.
+func openTail(path string) (*os.File, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a town or gt log
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := f.Stat(); err == nil && fi.Size() > autoEstopTailBytes {
+		if _, err := f.Seek(fi.Size()-autoEstopTailBytes, io.SeekStart); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// eventEstopSamples turns recent events-log entries into trigger samples.
+// Refinery events are attributed by actor ("<rig>/refinery"); mass deaths
+// by parsing the rig out of each dead session's name. A merge counts the
+// commits it landed on main; merges elsewhere are not sampled.
+func eventEstopSamples(path string, since time.Time) []estop.Sample {
+	f, err := openTail(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var samples []estop.Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // includes the partial first line after a seek
+		}
+		var kind string
+		switch e.Type {
+		case events.TypeMassDeath:
+			kind = estop.KindMassDeath
+		case events.TypeMerged:
+			kind = estop.KindMainCommitRate
+		case events.TypePushRejected:
+			kind = estop.KindPushRejections
+		default:
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || at.Before(since) {
+			continue
+		}
+
+		s := estop.Sample{Kind: kind, At: at, Value: 1}
+		if kind == estop.KindMainCommitRate {
+			commits, _ := e.Payload["main_commits"].(float64)
+			if commits <= 0 {
+				continue
+			}
+			s.Value = commits
+		}
+		if kind == estop.KindMassDeath {
+			s.Rigs = massDeathRigs(e.Payload)
+		} else if rig, ok := strings.CutSuffix(e.Actor, "/refinery"); ok {
+			s.Rigs = []string{rig}
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// massDeathRigs returns the distinct rigs of the sessions in a mass_death
+// payload. Town-level sessions (mayor, deacon, dogs) have no rig.
+func massDeathRigs(payload map[string]interface{}) []string {
+	sessions, _ := payload["sessions"].([]interface{})
+	seen := make(map[string]bool)
+	var rigs []string
+	for _, s := range sessions {
+		name, _ := s.(string)
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Rig == "" || seen[id.Rig] {
+			continue
+		}
+		seen[id.Rig] = true
+		rigs = append(rigs, id.Rig)
+	}
+	return rigs
+}
+
+// costLogEntry is the subset of a costs.jsonl entry the cost trigger needs.
+type costLogEntry struct {
+	Rig     string    `json:"rig,omitempty"`
+	CostUSD float64   `json:"cost_usd"`
+	EndedAt time.Time `json:"ended_at"`
+}
+
+// costEstopSamples reads session costs recorded by `gt costs record` that
+// ended at or after since, from the tail of the log.
+func costEstopSamples(path string, since time.Time) []estop.Sample {
+	f, err := openTail(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var samples []estop.Sample
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry costLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.EndedAt.Before(since) || entry.CostUSD <= 0 {
+			continue
+		}
+		s := estop.Sample{Kind: estop.KindCostBurn, At: entry.EndedAt, Value: entry.CostUSD}
+		if entry.Rig != "" {
+			s.Rigs = []string{entry.Rig}
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// costsLogPath mirrors `gt costs record`: $GT_HOME/.gt/costs.jsonl when
+// GT_HOME is set, otherwise ~/.gt/costs.jsonl.
+func costsLogPath() string {
+	if h := os.Getenv("GT_HOME"); h != "" {
+		return filepath.Join(h, ".gt", "costs.jsonl")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), ".gt", "costs.jsonl")
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/estop"
+)
+
+func TestIsPatrolEnabled_AutoEstop(t *testing.T) {
+	if IsPatrolEnabled(nil, "auto_estop") {
+		t.Error("expected auto_estop to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "auto_estop") {
+		t.Error("expected auto_estop to be disabled by default")
+	}
+	config.Patrols.AutoEstop = &AutoEstopConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "auto_estop") {
+		t.Error("expected auto_estop to be enabled when configured")
+	}
+	if got := autoEstopInterval(config); got != defaultAutoEstopInterval {
+		t.Errorf("expected default interval, got %v", got)
+	}
+}
+
+func TestAutoEstopConfigJSON(t *testing.T) {
+	data := `{"patrols": {"auto_estop": {"enabled": true, "interval": "30s", "triggers": [
+		{"kind": "push_rejections", "threshold": 5, "window": "10m", "scope": "rig", "rigs": ["gastown"]}
+	]}}}`
+	var config DaemonPatrolConfig
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	ae := config.Patrols.AutoEstop
+	if ae == nil || len(ae.Triggers) != 1 {
+		t.Fatalf("auto_estop = %+v", ae)
+	}
+	if r := ae.Triggers[0]; r.Kind != estop.KindPushRejections || !r.IsRigScoped() || r.Rigs[0] != "gastown" {
+		t.Errorf("trigger = %+v", r)
+	}
+	if got := autoEstopInterval(&config); got != 30*time.Second {
+		t.Errorf("interval = %v, want 30s", got)
+	}
+}
+
+func TestEventEstopSamples(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	lines := []string{
+		`{"ts":"` + now.Add(-time.Minute).Format(time.RFC3339) + `","type":"merged","actor":"gastown/refinery","payload":{"main_commits":3}}`,
+		`{"ts":"` + now.Add(-time.Minute).Format(time.RFC3339) + `","type":"merged","actor":"gastown/refinery","payload":{}}`,
+		`{"ts":"` + now.Add(-time.Minute).Format(time.RFC3339) + `","type":"push_rejected","actor":"beads/refinery","payload":{}}`,
+		`{"ts":"` + now.Add(-2*time.Hour).Format(time.RFC3339) + `","type":"merged","actor":"gastown/refinery","payload":{}}`,
+		`{"ts":"` + now.Format(time.RFC3339) + `","type":"mass_death","actor":"daemon","payload":{"sessions":["hq-mayor"]}}`,
+		`{"ts":"` + now.Format(time.RFC3339) + `","type":"sling","actor":"mayor","payload":{}}`,
+		`not json`,
+	}
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	content := ""
+	for _, l := range lines {
+		content += l + "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	samples := eventEstopSamples(path, now.Add(-time.Hour))
+	if len(samples) != 3 {
+		t.Fatalf("samples = %+v, want merged to main, push_rejected and mass_death", samples)
+	}
+	if samples[0].Kind != estop.KindMainCommitRate || samples[0].Rigs[0] != "gastown" || samples[0].Value != 3 {
+		t.Errorf("merged sample = %+v", samples[0])
+	}
+	if samples[1].Kind != estop.KindPushRejections || samples[1].Rigs[0] != "beads" {
+		t.Errorf("push_rejected sample = %+v", samples[1])
+	}
+	if samples[2].Kind != estop.KindMassDeath || len(samples[2].Rigs) != 0 {
+		t.Errorf("mass_death sample = %+v, want town-level (mayor has no rig)", samples[2])
+	}
+}
+
+func TestCostEstopSamples(t *testing.T) {
+	now := time.Now().UTC()
+	path := filepath.Join(t.TempDir(), "costs.jsonl")
+	content := `{"session_id":"a","rig":"gastown","cost_usd":4.5,"ended_at":"` + now.Add(-10*time.Minute).Format(time.RFC3339) + `"}
+{"session_id":"b","cost_usd":9,"ended_at":"` + now.Add(-3*time.Hour).Format(time.RFC3339) + `"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	samples := costEstopSamples(path, now.Add(-time.Hour))
+	if len(samples) != 1 || samples[0].Value != 4.5 || samples[0].Rigs[0] != "gastown" {
+		t.Fatalf("samples = %+v", samples)
+	}
+}
+
+func TestRefreshAutoEstopPicksUpEdits(t *testing.T) {
+	townRoot := t.TempDir()
+	d := &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(io.Discard, "", 0),
+	}
+	writeTriggers := func(threshold float64) {
+		t.Helper()
+		config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{AutoEstop: &AutoEstopConfig{
+			Enabled:  true,
+			Triggers: []estop.Rule{{Kind: estop.KindMainCommitRate, Threshold: threshold, Scope: estop.ScopeRig}},
+		}}}
+		if err := SavePatrolConfig(townRoot, config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeTriggers(3)
+	d.refreshAutoEstop()
+	if rules := d.autoEstop.Rules(); len(rules) != 1 || rules[0].Threshold != 3 {
+		t.Fatalf("rules = %+v, want threshold 3", rules)
+	}
+
+	// A stop fired before the edit still holds back its samples afterwards.
+	now := time.Now()
+	samples := []estop.Sample{{Kind: estop.KindMainCommitRate, Rigs: []string{"gastown"}, At: now.Add(-time.Minute), Value: 20}}
+	breaches := d.autoEstop.Check(samples, now)
+	if len(breaches) != 1 {
+		t.Fatalf("Check = %+v, want one breach", breaches)
+	}
+	d.autoEstop.MarkFired(breaches[0], now)
+	if err := d.autoEstop.SaveFired(autoEstopStatePath(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTriggers(1)
+	d.refreshAutoEstop()
+	if rules := d.autoEstop.Rules(); len(rules) != 1 || rules[0].Threshold != 1 {
+		t.Fatalf("rules after edit = %+v, want threshold 1", rules)
+	}
+	if again := d.autoEstop.Check(samples, now.Add(time.Minute)); len(again) != 0 {
+		t.Errorf("fired samples re-triggered after edit: %+v", again)
+	}
+
+	// An unparseable config keeps the current triggers.
+	if err := os.WriteFile(PatrolConfigFile(townRoot), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d.refreshAutoEstop()
+	if rules := d.autoEstop.Rules(); len(rules) != 1 || rules[0].Threshold != 1 {
+		t.Errorf("rules after bad edit = %+v, want threshold 1 kept", rules)
+	}
+}
+
+func TestCheckAutoEstopFollowsConfigEdits(t *testing.T) {
+	townRoot := t.TempDir()
+	d := &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(io.Discard, "", 0),
+		ctx:    context.Background(),
+		gtPath: filepath.Join(townRoot, "no-such-gt"),
+	}
+
+	// Disabled when the daemon started: nothing happens.
+	d.checkAutoEstop()
+	if estop.IsActive(townRoot) {
+		t.Fatal("E-stop tripped with auto_estop disabled")
+	}
+
+	// Enabled later in mayor/daemon.json. The gt binary is unavailable, so
+	// the trip falls back to writing the sentinel.
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{AutoEstop: &AutoEstopConfig{
+		Enabled:  true,
+		Triggers: []estop.Rule{{Kind: estop.KindDiskSpace, Threshold: 0.001}},
+	}}}
+	if err := SavePatrolConfig(townRoot, config); err != nil {
+		t.Fatal(err)
+	}
+	d.checkAutoEstop()
+	if !estop.IsActive(townRoot) {
+		t.Error("E-stop not tripped after auto_estop was enabled")
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -30,8 +31,10 @@ import (
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/estop"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/federation"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
@@ -67,6 +70,15 @@ type Daemon struct {
 	// mayor/daemon.json. Checked by isPatrolActive alongside patrolConfig.
 	disabledPatrols map[string]bool
 
+	// autoEstop evaluates the auto_estop triggers. Rebuilt when the
+	// triggers change (autoEstopTriggers holds the ones it was built from).
+	// These fields are only accessed by the auto E-stop pass, which runs one
+	// at a time in the background (autoEstopRunning).
+	autoEstop         *estop.Monitor
+	autoEstopTriggers []estop.Rule
+	autoEstopEnabled  bool
+	autoEstopRunning  atomic.Bool
+
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
@@ -117,6 +129,28 @@ type Daemon struct {
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	mayorZombieCount int
 
+	// sessionResources is the latest per-session cgroup usage sample, taken
+	// each heartbeat before the pressure-gated spawns.
+	// Only accessed from heartbeat loop goroutine - no sync needed.
+	sessionResources sessionResourceSample
+
+	// backupVerifyRunning is set while a backup verification runs. Verifying
+	// restores every backup in full, so it runs off the main loop and a tick
+	// that arrives while one is still running is skipped.
+	backupVerifyRunning atomic.Bool
+
+	// warmPoolRunning is set while a warm pool pass runs in the background
+	// (see maintainWarmPools).
+	warmPoolRunning atomic.Bool
+
+	// scheduledMailRunning is set while scheduled mail is being released
+	// in the background (see releaseScheduledMail).
+	scheduledMailRunning atomic.Bool
+
+	// federationSyncRunning is set while a federation sync runs in the
+	// background (see syncFederation).
+	federationSyncRunning atomic.Bool
+
 	// rigPool runs per-rig heartbeat operations (witness checks, refinery checks,
 	// polecat health, idle reaping, branch pruning) with bounded concurrency and
 	// per-rig context timeouts so one slow rig cannot block all others.
@@ -619,6 +653,19 @@ func (d *Daemon) Run() (err error) {
 		d.logger.Printf("Dolt backup ticker started (interval %v)", interval)
 	}
 
+	// Start Dolt backup verification ticker alongside the backup patrol.
+	// Restores each backup into a scratch copy and runs integrity checks.
+	var doltBackupVerifyTicker *time.Ticker
+	var doltBackupVerifyChan <-chan time.Time
+	if d.isPatrolActive("dolt_backup") {
+		if interval := doltBackupVerifyInterval(d.patrolConfig); interval > 0 {
+			doltBackupVerifyTicker = time.NewTicker(interval)
+			doltBackupVerifyChan = doltBackupVerifyTicker.C
+			defer doltBackupVerifyTicker.Stop()
+			d.logger.Printf("Dolt backup verify ticker started (interval %v)", interval)
+		}
+	}
+
 	// Start JSONL git backup ticker if configured.
 	// Exports issues to JSONL, scrubs ephemeral data, pushes to git repo.
 	var jsonlGitBackupTicker *time.Ticker
@@ -717,6 +764,14 @@ func (d *Daemon) Run() (err error) {
 		d.logger.Printf("Quota dog ticker started (interval %v)", interval)
 	}
 
+	// Start auto E-stop ticker. Unlike the other patrols it always runs:
+	// each tick re-reads the config, so enabling auto_estop in
+	// mayor/daemon.json takes effect without restarting the daemon.
+	// Trips the E-stop when a configured anomaly threshold is reached.
+	autoEstopTicker := time.NewTicker(autoEstopInterval(d.patrolConfig))
+	defer autoEstopTicker.Stop()
+	autoEstopChan := autoEstopTicker.C
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -770,6 +825,18 @@ func (d *Daemon) Run() (err error) {
 				d.syncDoltBackups()
 			}
 
+		case <-doltBackupVerifyChan:
+			// Periodic Dolt backup verification — restores each backup into a
+			// scratch copy and checks it can be read back in full.
+			if !d.isShutdownInProgress() {
+				if d.backupVerifyRunning.CompareAndSwap(false, true) {
+					go func() {
+						defer d.backupVerifyRunning.Store(false)
+						d.verifyDoltBackups()
+					}()
+				}
+			}
+
 		case <-jsonlGitBackupChan:
 			// Periodic JSONL git backup — exports issues, scrubs ephemeral data,
 			// commits and pushes to git repo.
@@ -826,6 +893,13 @@ func (d *Daemon) Run() (err error) {
 				d.runQuotaDog()
 			}
 
+		case <-autoEstopChan:
+			// Auto E-stop — evaluates anomaly triggers (mass death, cost
+			// burn, push storms, disk space) and freezes work on a breach.
+			if !d.isShutdownInProgress() {
+				d.runAutoEstop()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -927,6 +1001,9 @@ func (d *Daemon) heartbeat(state *State) {
 		d.killWitnessSessions()
 	}
 
+	// 4b. Sample per-session cgroup usage for metrics and pressure checks.
+	d.sampleSessionResources()
+
 	// 5. Ensure Refineries are running for all rigs (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
 	// Pressure-gated: refineries consume API credits, defer when system is loaded.
@@ -999,6 +1076,21 @@ func (d *Daemon) heartbeat(state *State) {
 		d.dispatchQueuedWork()
 	}
 
+	// 14a. Keep warm polecat pools topped up so sling can claim a prepared
+	// slot. Runs after dispatch so queued work gets idle polecats first.
+	// Pressure-gated like dispatch: warm slots are polecat worktrees.
+	if p := d.checkPressure("polecat"); !p.OK {
+		d.logger.Printf("Deferring warm pool maintenance: %s", p.Reason)
+	} else {
+		d.maintainWarmPools()
+	}
+
+	// 14b. Release scheduled and snoozed mail whose delivery time has passed.
+	d.releaseScheduledMail()
+
+	// 14c. Forward federated mail to peer towns and refresh remote convoy beads.
+	d.syncFederation()
+
 	// 15. Rotate oversized Dolt logs (copytruncate for child process fds).
 	// daemon.log uses lumberjack for automatic rotation; this handles Dolt server logs.
 	d.rotateOversizedLogs()
@@ -1013,6 +1105,68 @@ func (d *Daemon) heartbeat(state *State) {
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
+// releaseScheduledMail delivers mail sent with `gt mail send --at/--in` and
+// mail snoozed with `gt mail snooze` once its not-before time has passed.
+// Failed deliveries stay queued and are retried on the next heartbeat.
+//
+// Releasing sends mail, which notifies recipients and posts to webhooks, so
+// it runs off the heartbeat: a slow webhook endpoint must not stall the
+// other checks. A heartbeat that arrives while a release is still running
+// (including its notifications) skips it.
+func (d *Daemon) releaseScheduledMail() {
+	if !d.scheduledMailRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer d.scheduledMailRunning.Store(false)
+		router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+		released, err := router.ReleaseDueMail(time.Now())
+		if released > 0 {
+			d.logger.Printf("scheduled_mail: released %d message(s)", released)
+		}
+		if err != nil {
+			d.logger.Printf("scheduled_mail: %v", err)
+		}
+		router.WaitPendingNotifications()
+	}()
+}
+
+// syncFederation delivers queued mail to peer towns and refreshes the cached
+// status of remote beads tracked by convoys. Towns without a federation
+// config skip this entirely. Undelivered mail stays in the outbox.
+//
+// Peers are remote, so the sync runs off the heartbeat and each peer gets a
+// bounded share of it (see federation.Flush). A heartbeat that arrives while
+// a sync is still running skips it.
+func (d *Daemon) syncFederation() {
+	if _, err := os.Stat(agentconfig.FederationConfigPath(d.config.TownRoot)); err != nil {
+		return
+	}
+	if !d.federationSyncRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer d.federationSyncRunning.Store(false)
+		result, err := federation.Flush(d.ctx, d.config.TownRoot)
+		if err != nil {
+			d.logger.Printf("federation: flush: %v", err)
+		} else {
+			if len(result.Delivered) > 0 {
+				d.logger.Printf("federation: delivered %d message(s)", len(result.Delivered))
+			}
+			for _, fm := range result.Failed {
+				d.logger.Printf("federation: %s for %s:%s not delivered (attempt %d): %s", fm.ID, fm.Peer, fm.To, fm.Attempts, fm.LastError)
+			}
+			if len(result.Deferred) > 0 {
+				d.logger.Printf("federation: %d message(s) deferred to the next heartbeat", len(result.Deferred))
+			}
+		}
+		if err := federation.RefreshRemoteBeads(d.ctx, d.config.TownRoot); err != nil {
+			d.logger.Printf("federation: refreshing remote beads: %v", err)
+		}
+	}()
+}
+
 // rotateOversizedLogs checks Dolt server log files and rotates any that exceed
 // the size threshold. Uses copytruncate which is safe for logs held open by
 // child processes. Runs every heartbeat but is cheap (just stat calls).
@@ -2223,6 +2377,9 @@ func (d *Daemon) isRigOperational(rigName string) (bool, string) {
 	rigBeadID := fmt.Sprintf("%s-rig-%s", prefix, rigName)
 	rigBeadsDir := beads.ResolveBeadsDir(rigPath)
 	bd := beads.NewWithBeadsDir(rigPath, rigBeadsDir)
+	// Reuse the daemon's open stores so the per-heartbeat rig check does not
+	// spawn a bd subprocess per rig.
+	bd.SetStores(d.beadsStores)
 	if issue, err := bd.Show(rigBeadID); err == nil {
 		for _, label := range issue.Labels {
 			if label == "status:docked" {
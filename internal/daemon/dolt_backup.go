@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/health"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -25,6 +26,11 @@ const (
 	// fail the whole backup cycle.
 	doltBackupRetries    = 1
 	doltBackupRetryDelay = 5 * time.Second
+	// defaultDoltBackupVerifyInterval is how often each backup is restored
+	// into a scratch copy and checked. A full read of every backup is too
+	// heavy for the sync cadence; daily catches a rotten backup long before
+	// anyone needs it.
+	defaultDoltBackupVerifyInterval = 24 * time.Hour
 )
 
 // doltBackupInterval returns the configured backup interval, or the default (15m).
@@ -39,6 +45,62 @@ func doltBackupInterval(config *DaemonPatrolConfig) time.Duration {
 	return defaultDoltBackupInterval
 }
 
+// doltBackupVerifyInterval returns the configured backup verification
+// interval, the default (24h) when unset, or 0 when verification is disabled.
+func doltBackupVerifyInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DoltBackup != nil {
+		switch s := config.Patrols.DoltBackup.VerifyIntervalStr; s {
+		case "":
+		case "0", "off":
+			return 0
+		default:
+			if d, err := time.ParseDuration(s); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultDoltBackupVerifyInterval
+}
+
+// verifyDoltBackups restores each backup under .dolt-backup into a scratch
+// copy, runs integrity checks on it, and escalates any backup that fails.
+// Unlike sync this runs on every platform: a backup taken by hand or synced
+// from another machine is worth verifying too.
+func (d *Daemon) verifyDoltBackups() {
+	if !d.isPatrolActive("dolt_backup") {
+		return
+	}
+	if _, err := exec.LookPath("dolt"); err != nil {
+		return
+	}
+
+	results := health.VerifyBackups(d.config.TownRoot, d.patrolConfig.Patrols.DoltBackup.Databases)
+	if len(results) == 0 {
+		return
+	}
+
+	var failures []string
+	for _, v := range results {
+		for _, diag := range v.Diagnostics {
+			d.logger.Printf("dolt_backup_verify: %s: %s", v.Name, diag)
+		}
+		if v.Healthy() {
+			d.logger.Printf("dolt_backup_verify: %s: ok (%d commits, %d tables, %d rows, %dms)",
+				v.Name, v.Commits, v.Tables, v.Rows, v.DurationMs)
+			continue
+		}
+		for _, p := range v.Problems {
+			d.logger.Printf("dolt_backup_verify: %s: %s", v.Name, p)
+		}
+		failures = append(failures, fmt.Sprintf("%s %s", v.Name, strings.Join(v.Problems, "; ")))
+	}
+
+	d.logger.Printf("dolt_backup_verify: verified %d/%d backup(s)", len(results)-len(failures), len(results))
+	if len(failures) > 0 {
+		d.escalate("dolt_backup_verify", "backup failed verification: "+strings.Join(failures, " | "))
+	}
+}
+
 // syncDoltBackups syncs each production database to its configured backup location.
 // Non-fatal: errors are logged but don't stop the daemon.
 func (d *Daemon) syncDoltBackups() {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -232,3 +233,30 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestDoltBackupVerifyInterval(t *testing.T) {
+	if got := doltBackupVerifyInterval(nil); got != defaultDoltBackupVerifyInterval {
+		t.Errorf("expected default interval %v, got %v", defaultDoltBackupVerifyInterval, got)
+	}
+
+	tests := []struct {
+		verify string
+		want   time.Duration
+	}{
+		{"", defaultDoltBackupVerifyInterval},
+		{"6h", 6 * time.Hour},
+		{"0", 0},
+		{"off", 0},
+		{"bogus", defaultDoltBackupVerifyInterval},
+	}
+	for _, tt := range tests {
+		config := &DaemonPatrolConfig{
+			Patrols: &PatrolsConfig{
+				DoltBackup: &DoltBackupConfig{Enabled: true, VerifyIntervalStr: tt.verify},
+			},
+		}
+		if got := doltBackupVerifyInterval(config); got != tt.want {
+			t.Errorf("verify_interval %q: got %v, want %v", tt.verify, got, tt.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cgroup"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -26,6 +27,13 @@ type PressureResult struct {
 
 	// ActiveSessions is the count of active Claude agent sessions.
 	ActiveSessions int
+
+	// SliceCPUFraction is the share of host CPU used by agent session
+	// cgroups over the last heartbeat.
+	SliceCPUFraction float64
+
+	// SliceMemoryGB is the total memory of agent session cgroups in GB.
+	SliceMemoryGB float64
 }
 
 // checkPressure evaluates system load and session concurrency to decide
@@ -34,6 +42,8 @@ type PressureResult struct {
 //  1. CPU pressure: 1-minute load average vs threshold (per-core).
 //  2. Memory pressure: available memory vs minimum threshold.
 //  3. Session concurrency: active tmux sessions vs maximum cap.
+//  4. Session cgroups: CPU share and memory actually used by agent
+//     sessions, from the heartbeat's cgroup sample.
 //
 // Infrastructure agents (deacon, witness, mayor) should NOT be gated by
 // pressure—they are the monitoring/recovery layer. Only gate:
@@ -46,9 +56,12 @@ func (d *Daemon) checkPressure(_ string) PressureResult {
 	cpuThreshold := cfg.PressureCPUThresholdV()
 	memThreshold := cfg.PressureMemThresholdGBV()
 	maxSessions := cfg.PressureMaxSessionsV()
+	sliceCPUThreshold := cfg.PressureSliceCPUThresholdV()
+	sliceMemThreshold := cfg.PressureSliceMemThresholdGBV()
 
 	// All checks disabled (default) — skip entirely, no subprocess calls.
-	if cpuThreshold <= 0 && memThreshold <= 0 && maxSessions <= 0 {
+	if cpuThreshold <= 0 && memThreshold <= 0 && maxSessions <= 0 &&
+		sliceCPUThreshold <= 0 && sliceMemThreshold <= 0 {
 		return PressureResult{OK: true}
 	}
 
@@ -87,9 +100,43 @@ func (d *Daemon) checkPressure(_ string) PressureResult {
 		}
 	}
 
+	// Tier 3: Usage measured in the agent sessions' own cgroups
+	sample := d.sessionResources
+	if sliceCPUThreshold > 0 {
+		result.SliceCPUFraction = sample.cpuFraction
+		if result.SliceCPUFraction > sliceCPUThreshold {
+			recent := func(u *cgroup.Usage) float64 { return float64(sample.cpuDeltaUsec[u.Rig+"/"+u.Session]) }
+			top := topSessionBy(sample.usages, recent)
+			result.OK = false
+			result.Reason = fmt.Sprintf("session cpu pressure: agents using %.0f%% of host CPU, threshold %.0f%%%s",
+				result.SliceCPUFraction*100, sliceCPUThreshold*100,
+				topSuffix(top, func(u *cgroup.Usage) string { return fmt.Sprintf("%.0fs cpu since last heartbeat", recent(u)/1e6) }))
+			return result
+		}
+	}
+	if sliceMemThreshold > 0 {
+		result.SliceMemoryGB = float64(sample.memoryBytes) / (1 << 30)
+		if result.SliceMemoryGB > sliceMemThreshold {
+			top := topSessionBy(sample.usages, func(u *cgroup.Usage) float64 { return float64(u.MemoryCurrent) })
+			result.OK = false
+			result.Reason = fmt.Sprintf("session memory pressure: agents using %.1fGB, threshold %.1fGB%s",
+				result.SliceMemoryGB, sliceMemThreshold, topSuffix(top, func(u *cgroup.Usage) string { return cgroup.FormatBytes(u.MemoryCurrent) }))
+			return result
+		}
+	}
+
 	return result
 }
 
+// topSuffix names the top consumer for a pressure reason, or returns ""
+// when there is none.
+func topSuffix(top *cgroup.Usage, value func(*cgroup.Usage) string) string {
+	if top == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (top: %s/%s %s)", top.Rig, top.Session, value(top))
+}
+
 // countAgentSessions counts active tmux sessions that belong to Gas Town agents.
 // Uses the town's tmux socket so it only counts sessions for this town.
 func (d *Daemon) countAgentSessions() int {
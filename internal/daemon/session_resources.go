@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cgroup"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// sessionResourceSample is the per-session cgroup usage taken once per
+// heartbeat. checkPressure consults the latest sample instead of re-reading
+// cgroupfs, so every gate in one heartbeat sees the same numbers.
+type sessionResourceSample struct {
+	// usages is the usage of each populated session cgroup.
+	usages []*cgroup.Usage
+
+	// cpuFraction is the share of host CPU the sessions used since the
+	// previous sample (0-1). Zero until two samples exist.
+	cpuFraction float64
+
+	// memoryBytes is the sessions' total current memory.
+	memoryBytes int64
+
+	// cpuUsec is each session's cumulative CPU time keyed by rig/session,
+	// and cpuDeltaUsec the CPU time it used since the previous sample.
+	cpuUsec      map[string]uint64
+	cpuDeltaUsec map[string]uint64
+
+	// totalCPUUsec and takenAt anchor the next cpuFraction computation.
+	totalCPUUsec uint64
+	takenAt      time.Time
+}
+
+// sampleSessionResources reads usage from every session cgroup, exports it
+// as OTel gauges, and removes cgroups of sessions that have exited. Towns
+// without session cgroups (no resources config, or cgroup v1) read nothing.
+func (d *Daemon) sampleSessionResources() {
+	root := config.ResolveCgroupRoot(d.config.TownRoot)
+	usages, err := cgroup.List(root)
+	if err != nil {
+		d.logger.Printf("session_resources: %v", err)
+		return
+	}
+	if len(usages) == 0 {
+		d.sessionResources = sessionResourceSample{}
+		return
+	}
+
+	prev := d.sessionResources
+	sample := sessionResourceSample{
+		takenAt:      time.Now(),
+		cpuUsec:      make(map[string]uint64),
+		cpuDeltaUsec: make(map[string]uint64),
+	}
+	for _, u := range usages {
+		if !u.Populated {
+			continue
+		}
+		key := u.Rig + "/" + u.Session
+		sample.usages = append(sample.usages, u)
+		sample.memoryBytes += u.MemoryCurrent
+		sample.cpuUsec[key] = u.CPUUsec
+		if before, ok := prev.cpuUsec[key]; ok && u.CPUUsec >= before {
+			sample.cpuDeltaUsec[key] = u.CPUUsec - before
+		}
+		telemetry.RecordSessionResources(d.ctx, u.Rig, u.Session,
+			float64(u.CPUUsec)/1e6, u.MemoryCurrent, u.PidsCurrent)
+	}
+
+	// The root's cpu.stat includes sessions that have since exited, so the
+	// delta stays accurate across prunes.
+	if total, err := cgroup.TotalCPUUsec(root); err == nil {
+		sample.totalCPUUsec = total
+		sample.cpuFraction = cpuFraction(prev.totalCPUUsec, total, sample.takenAt.Sub(prev.takenAt), runtime.NumCPU())
+		if prev.takenAt.IsZero() {
+			sample.cpuFraction = 0
+		}
+	}
+	d.sessionResources = sample
+
+	if n := cgroup.Prune(root); n > 0 {
+		d.logger.Printf("session_resources: removed %d exited session cgroup(s)", n)
+	}
+}
+
+// cpuFraction converts a cgroup CPU time delta over a wall-clock interval
+// into a share of host CPU capacity.
+func cpuFraction(prevUsec, curUsec uint64, elapsed time.Duration, numCPU int) float64 {
+	if curUsec < prevUsec || elapsed <= 0 || numCPU <= 0 {
+		return 0
+	}
+	used := time.Duration(curUsec-prevUsec) * time.Microsecond //nolint:gosec // G115: delta fits in int64
+	return used.Seconds() / (elapsed.Seconds() * float64(numCPU))
+}
+
+// topSessionBy returns the session with the highest value of key, for naming
+// the main consumer in pressure reasons.
+func topSessionBy(usages []*cgroup.Usage, key func(*cgroup.Usage) float64) *cgroup.Usage {
+	var top *cgroup.Usage
+	for _, u := range usages {
+		if top == nil || key(u) > key(top) {
+			top = u
+		}
+	}
+	return top
+}
@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// setupSessionCgroups creates a town whose cgroup root is a fake directory
+// tree with two running polecat sessions and one exited, and enables the
+// slice pressure thresholds.
+func setupSessionCgroups(t *testing.T, cpuThreshold, memThresholdGB float64) (*Daemon, string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	root := filepath.Join(t.TempDir(), "gastown.slice")
+
+	ts := config.NewTownSettings()
+	ts.Resources = &config.ResourcesConfig{CgroupRoot: root}
+	ts.Operational = &config.OperationalConfig{Daemon: &config.DaemonThresholds{
+		PressureSliceCPUThreshold:   &cpuThreshold,
+		PressureSliceMemThresholdGB: &memThresholdGB,
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"cpu.stat":                             "usage_usec 0\n",
+		"gastown/polecat-Toast/cpu.stat":       "usage_usec 9000000\n",
+		"gastown/polecat-Toast/memory.current": "3221225472\n",
+		"gastown/polecat-Toast/pids.current":   "40\n",
+		"gastown/polecat-Toast/cgroup.events":  "populated 1\n",
+		"gastown/polecat-Nux/cpu.stat":         "usage_usec 1000000\n",
+		"gastown/polecat-Nux/memory.current":   "1073741824\n",
+		"gastown/polecat-Nux/cgroup.events":    "populated 1\n",
+		"gastown/polecat-Gone/cgroup.events":   "populated 0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(io.Discard, "", 0),
+		ctx:    context.Background(),
+	}
+	return d, root
+}
+
+func TestSampleSessionResources(t *testing.T) {
+	d, _ := setupSessionCgroups(t, 0, 0)
+	d.sampleSessionResources()
+
+	s := d.sessionResources
+	if len(s.usages) != 2 {
+		t.Fatalf("sampled %d sessions, want the 2 populated ones", len(s.usages))
+	}
+	if s.memoryBytes != 4<<30 {
+		t.Errorf("memoryBytes = %d, want 4GiB", s.memoryBytes)
+	}
+	if s.cpuFraction != 0 {
+		t.Errorf("cpuFraction = %f on first sample, want 0", s.cpuFraction)
+	}
+}
+
+func TestCheckPressure_SliceMemory(t *testing.T) {
+	d, _ := setupSessionCgroups(t, 0, 3.5)
+	d.sampleSessionResources()
+
+	p := d.checkPressure("polecat")
+	if p.OK {
+		t.Fatal("spawn allowed with session memory over threshold")
+	}
+	if !strings.Contains(p.Reason, "session memory pressure") || !strings.Contains(p.Reason, "gastown/polecat-Toast") {
+		t.Errorf("reason = %q, want session memory pressure naming the top session", p.Reason)
+	}
+}
+
+func TestCheckPressure_SliceCPU(t *testing.T) {
+	d, root := setupSessionCgroups(t, 0.5, 0)
+	d.sampleSessionResources()
+
+	// Sessions used a full host's worth of CPU over the last 10 seconds,
+	// mostly in Toast.
+	busy := uint64(runtime.NumCPU()) * 10_000_000
+	if err := os.WriteFile(filepath.Join(root, "cpu.stat"), []byte("usage_usec "+strconv.FormatUint(busy, 10)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "gastown/polecat-Toast/cpu.stat"), []byte("usage_usec "+strconv.FormatUint(9000000+busy, 10)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d.sessionResources.takenAt = time.Now().Add(-10 * time.Second)
+	d.sampleSessionResources()
+
+	p := d.checkPressure("polecat")
+	if p.OK {
+		t.Fatalf("spawn allowed with agents using %.0f%% of host CPU", p.SliceCPUFraction*100)
+	}
+	if !strings.Contains(p.Reason, "session cpu pressure") || !strings.Contains(p.Reason, "gastown/polecat-Toast") {
+		t.Errorf("reason = %q, want session cpu pressure naming Toast", p.Reason)
+	}
+}
+
+func TestCheckPressure_SliceThresholdsDisabled(t *testing.T) {
+	d, _ := setupSessionCgroups(t, 0, 0)
+	d.sampleSessionResources()
+	if p := d.checkPressure("polecat"); !p.OK {
+		t.Errorf("spawn blocked with slice thresholds disabled: %s", p.Reason)
+	}
+}
+
+func TestCPUFraction(t *testing.T) {
+	if got := cpuFraction(0, 4_000_000, 2*time.Second, 4); got != 0.5 {
+		t.Errorf("cpuFraction = %f, want 0.5", got)
+	}
+	if got := cpuFraction(5, 1, time.Second, 4); got != 0 {
+		t.Errorf("cpuFraction with counter reset = %f, want 0", got)
+	}
+}
@@ -37,6 +37,19 @@ func TestMain(m *testing.M) {
 		tmux.SetDefaultSocket(tmuxSocket)
 	}
 
+	// Run from a scratch directory. Session deaths and reaps are logged to
+	// the events file of the town found from the working directory, which
+	// from the package directory is the source tree.
+	scratch, err := os.MkdirTemp("", "gt-daemon-test-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create scratch dir: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(scratch); err != nil {
+		fmt.Fprintf(os.Stderr, "chdir to scratch dir: %v\n", err)
+		os.Exit(1)
+	}
+
 	code := m.Run()
 
 	if tmuxSocket != "" {
@@ -45,5 +58,6 @@ func TestMain(m *testing.M) {
 		_ = os.Remove(socketPath)
 	}
 	testutil.TerminateDoltContainer()
+	_ = os.RemoveAll(scratch)
 	os.Exit(code)
 }
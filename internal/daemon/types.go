@@ -130,6 +130,7 @@ type PatrolsConfig struct {
 	ScheduledMaintenance   *ScheduledMaintenanceConfig    `json:"scheduled_maintenance,omitempty"`
 	MainBranchTest         *MainBranchTestConfig          `json:"main_branch_test,omitempty"`
 	QuotaDog               *QuotaDogConfig                `json:"quota_dog,omitempty"`
+	AutoEstop              *AutoEstopConfig               `json:"auto_estop,omitempty"`
 	RestartTracker         *RestartTrackerConfig          `json:"restart_tracker,omitempty"`
 }
 
@@ -165,6 +166,10 @@ type DoltBackupConfig struct {
 	// Databases lists specific database names to back up.
 	// If empty, auto-discovers databases with configured backup remotes.
 	Databases []string `json:"databases,omitempty"`
+
+	// VerifyIntervalStr is how often to restore each backup into a scratch
+	// copy and run integrity checks on it (default "24h", "0" disables).
+	VerifyIntervalStr string `json:"verify_interval,omitempty"`
 }
 
 // JsonlGitBackupConfig holds configuration for the jsonl_git_backup patrol.
@@ -308,6 +313,12 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.QuotaDog.Enabled
 	}
+	if patrol == "auto_estop" {
+		if config == nil || config.Patrols == nil || config.Patrols.AutoEstop == nil {
+			return false
+		}
+		return config.Patrols.AutoEstop.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
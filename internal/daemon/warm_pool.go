@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// warmPoolTimeout bounds one `gt polecat warm` run. Creating a slot can
+// include a full dependency install (polecat_warm_command), so this is long.
+const warmPoolTimeout = 30 * time.Minute
+
+// warmPoolRigs returns the operational rigs that have a warm pool configured,
+// sorted by name.
+func (d *Daemon) warmPoolRigs() []string {
+	var rigs []string
+	for _, rigName := range d.getKnownRigs() {
+		cfg, err := rig.LoadRigConfig(filepath.Join(d.config.TownRoot, rigName))
+		if err != nil || cfg.PolecatWarmPool <= 0 {
+			continue
+		}
+		if ok, _ := d.isRigOperational(rigName); !ok {
+			continue
+		}
+		rigs = append(rigs, rigName)
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// maintainWarmPools tops up and refreshes warm polecat pools for rigs with
+// polecat_warm_pool set. Shells out to `gt polecat warm` (daemon cannot import
+// cmd) in the background: preparing slots can take minutes and must not stall
+// the heartbeat. A tick that arrives while a pass is still running is skipped.
+func (d *Daemon) maintainWarmPools() {
+	rigs := d.warmPoolRigs()
+	if len(rigs) == 0 {
+		return
+	}
+	if !d.warmPoolRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer d.warmPoolRunning.Store(false)
+		for _, rigName := range rigs {
+			d.warmRig(rigName)
+		}
+	}()
+}
+
+func (d *Daemon) warmRig(rigName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), warmPoolTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "polecat", "warm", rigName) //nolint:gosec // G204: rig name comes from rigs.json
+	setSysProcAttr(cmd)
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(beads.BuildMutationRoutingBDEnv(os.Environ(), filepath.Join(d.config.TownRoot, ".beads")), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		d.logger.Printf("warm_pool: %s timed out after %s", rigName, warmPoolTimeout)
+	case err != nil:
+		d.logger.Printf("warm_pool: %s: %v (output: %s)", rigName, err, strings.TrimSpace(string(out)))
+	case len(out) > 0:
+		d.logger.Printf("warm_pool: %s", strings.TrimSpace(string(out)))
+	}
+}
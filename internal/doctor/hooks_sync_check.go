@@ -7,26 +7,14 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/hooks"
 )
 
-// templateTarget tracks a non-Claude template-based agent file that is out of sync.
-type templateTarget struct {
-	path           string
-	dir            string
-	provider       string
-	role           string
-	hooksDir       string
-	settingsFile   string
-	useSettingsDir bool
-}
-
 // HooksSyncCheck verifies all hook/settings files match what gt hooks sync would generate.
 type HooksSyncCheck struct {
 	FixableCheck
-	outOfSync         []hooks.Target   // Claude targets
-	templateOutOfSync []templateTarget // Non-Claude template-based targets
+	outOfSync      []hooks.Target      // Claude targets
+	agentOutOfSync []hooks.AgentTarget // Non-Claude agent hook files
 }
 
 // NewHooksSyncCheck creates a new hooks sync validation check.
@@ -45,7 +33,7 @@ func NewHooksSyncCheck() *HooksSyncCheck {
 // Run checks all managed hook/settings files for sync status.
 func (c *HooksSyncCheck) Run(ctx *CheckContext) *CheckResult {
 	c.outOfSync = nil
-	c.templateOutOfSync = nil
+	c.agentOutOfSync = nil
 
 	var details []string
 	totalTargets := 0
@@ -91,89 +79,42 @@ func (c *HooksSyncCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	// Loop 2: Non-Claude template-based agents — use DiscoverRoleLocations + SyncForRole comparison.
-	locations, locErr := hooks.DiscoverRoleLocations(ctx.TownRoot)
-	if locErr != nil {
-		details = append(details, fmt.Sprintf("discovering role locations: %v", locErr))
-	} else {
-		for _, loc := range locations {
-			rigPath := ""
-			if loc.Rig != "" {
-				rigPath = filepath.Join(ctx.TownRoot, loc.Rig)
-			}
-			// Use ResolveRoleAgentName (not ResolveRoleAgentConfig) so that checks are
-			// based on the *configured* agent, not the *resolved* one.
-			// ResolveRoleAgentConfig falls back to claude when the agent binary is not
-			// found in PATH (e.g., in CI), which would silently skip non-Claude targets.
-			agentName, _ := config.ResolveRoleAgentName(loc.Role, ctx.TownRoot, rigPath)
-			if agentName == "" {
-				continue
-			}
-			preset := config.GetAgentPresetByName(agentName)
-			if preset == nil || preset.HooksDir == "" || preset.HooksSettingsFile == "" {
-				continue
-			}
-			hooksProvider := preset.HooksProvider
-			if hooksProvider == "" {
-				hooksProvider = agentName
-			}
-			// Claude targets are handled by Loop 1.
-			if hooksProvider == "claude" {
-				continue
-			}
-
-			useSettingsDir := preset.HooksUseSettingsDir
-
-			var checkDirs []string
-			if loc.Rig == "" || useSettingsDir {
-				checkDirs = []string{loc.Dir}
-			} else {
-				checkDirs = hooks.DiscoverWorktrees(loc.Dir)
-			}
-
-			for _, dir := range checkDirs {
-				totalTargets++
-				targetPath := filepath.Join(dir, preset.HooksDir, preset.HooksSettingsFile)
-
-				expected, err := hooks.ComputeExpectedTemplate(hooksProvider, preset.HooksSettingsFile, loc.Role)
-				if err != nil {
-					details = append(details, fmt.Sprintf("%s (%s): error computing template: %v", targetPath, hooksProvider, err))
-					continue
-				}
+	// Loop 2: Non-Claude agents — translated from the same base+overrides,
+	// or from a plugin template.
+	agentTargets, discErr := hooks.DiscoverAgentTargets(ctx.TownRoot)
+	if discErr != nil {
+		details = append(details, fmt.Sprintf("discovering agent targets: %v", discErr))
+	}
+	for _, target := range agentTargets {
+		totalTargets++
 
-				actual, readErr := os.ReadFile(targetPath)
-				if readErr != nil {
-					// File missing
-					c.templateOutOfSync = append(c.templateOutOfSync, templateTarget{
-						path: targetPath, dir: dir, provider: hooksProvider,
-						role: loc.Role, hooksDir: preset.HooksDir,
-						settingsFile: preset.HooksSettingsFile, useSettingsDir: useSettingsDir,
-					})
-					details = append(details, fmt.Sprintf("%s (%s): missing", targetPath, hooksProvider))
-					continue
-				}
+		expected, err := target.Expected()
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s (%s): error computing expected: %v", target.Path, target.Provider, err))
+			continue
+		}
 
-				// Compare: structural for JSON, byte-exact for other files.
-				inSync := false
-				if filepath.Ext(preset.HooksSettingsFile) == ".json" {
-					inSync = hooks.TemplateContentEqual(expected, actual)
-				} else {
-					inSync = bytes.Equal(expected, actual)
-				}
+		actual, readErr := os.ReadFile(target.Path)
+		if readErr != nil {
+			c.agentOutOfSync = append(c.agentOutOfSync, target)
+			details = append(details, fmt.Sprintf("%s (%s): missing", target.Path, target.Provider))
+			continue
+		}
 
-				if !inSync {
-					c.templateOutOfSync = append(c.templateOutOfSync, templateTarget{
-						path: targetPath, dir: dir, provider: hooksProvider,
-						role: loc.Role, hooksDir: preset.HooksDir,
-						settingsFile: preset.HooksSettingsFile, useSettingsDir: useSettingsDir,
-					})
-					details = append(details, fmt.Sprintf("%s (%s): out of sync", targetPath, hooksProvider))
-				}
-			}
+		// Compare: structural for JSON, byte-exact for other files.
+		inSync := false
+		if filepath.Ext(target.HooksFile) == ".json" {
+			inSync = hooks.TemplateContentEqual(expected, actual)
+		} else {
+			inSync = bytes.Equal(expected, actual)
+		}
+		if !inSync {
+			c.agentOutOfSync = append(c.agentOutOfSync, target)
+			details = append(details, fmt.Sprintf("%s (%s): out of sync", target.Path, target.Provider))
 		}
 	}
 
-	outOfSyncCount := len(c.outOfSync) + len(c.templateOutOfSync)
+	outOfSyncCount := len(c.outOfSync) + len(c.agentOutOfSync)
 	if outOfSyncCount == 0 {
 		return &CheckResult{
 			Name:     c.Name(),
@@ -195,7 +136,7 @@ func (c *HooksSyncCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix brings all out-of-sync targets back into sync.
 func (c *HooksSyncCheck) Fix(ctx *CheckContext) error {
-	if len(c.outOfSync) == 0 && len(c.templateOutOfSync) == 0 {
+	if len(c.outOfSync) == 0 && len(c.agentOutOfSync) == 0 {
 		return nil
 	}
 
@@ -241,12 +182,10 @@ func (c *HooksSyncCheck) Fix(ctx *CheckContext) error {
 		}
 	}
 
-	// Fix template-based targets via SyncForRole.
-	for _, tt := range c.templateOutOfSync {
-		_, err := hooks.SyncForRole(tt.provider, tt.dir, tt.dir, tt.role,
-			tt.hooksDir, tt.settingsFile, tt.useSettingsDir)
-		if err != nil {
-			errs = append(errs, fmt.Sprintf("%s: %v", tt.path, err))
+	// Fix non-Claude agent targets by rewriting their files.
+	for _, target := range c.agentOutOfSync {
+		if _, err := hooks.SyncAgentTarget(target, false); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", target.Path, err))
 		}
 	}
 
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
 )
 
 // SparseCheckoutCheck detects legacy sparse checkout configurations that should be removed.
@@ -143,6 +144,10 @@ func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 			if !entry.IsDir() {
 				continue
 			}
+			// Polecats narrowed to a rig sparse profile are not legacy.
+			if _, err := os.Stat(filepath.Join(polecatDir, entry.Name(), polecat.SparseMarkerFile)); err == nil {
+				continue
+			}
 			// The actual worktree is at polecats/<name>/<rigname>/
 			worktreePath := filepath.Join(polecatDir, entry.Name(), rigName)
 			if _, err := os.Stat(worktreePath); err == nil {
@@ -293,6 +293,30 @@ func TestSparseCheckoutCheck_PolecatNestedWorktree(t *testing.T) {
 	}
 }
 
+func TestSparseCheckoutCheck_PolecatSparseProfileSkipped(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigName := "testrig"
+	rigDir := filepath.Join(tmpDir, rigName)
+
+	// A polecat narrowed to a rig sparse profile records it in its home dir.
+	polecatWorktree := filepath.Join(rigDir, "polecats", "pc1", rigName)
+	initGitRepo(t, polecatWorktree)
+	configureLegacySparseCheckout(t, polecatWorktree)
+	marker := filepath.Join(rigDir, "polecats", "pc1", ".sparse.json")
+	if err := os.WriteFile(marker, []byte(`{"profile":"web","paths":["web"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewSparseCheckoutCheck()
+	ctx := &CheckContext{TownRoot: tmpDir, RigName: rigName}
+
+	result := check.Run(ctx)
+
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK for profile-managed sparse polecat, got %v: %v", result.Status, result.Details)
+	}
+}
+
 func TestSparseCheckoutCheck_PolecatLegacyFlatLayout(t *testing.T) {
 	tmpDir := t.TempDir()
 	rigName := "testrig"
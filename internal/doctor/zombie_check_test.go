@@ -64,6 +64,9 @@ func TestZombieSessionCheck_FixProtectsCrewSessions(t *testing.T) {
 		"gt-gastown-witness",  // Would be killed (if real)
 	}
 
+	// Fix logs each kill to the town found from the working directory;
+	// run outside the source tree.
+	t.Chdir(t.TempDir())
 	ctx := &CheckContext{TownRoot: t.TempDir()}
 
 	// Fix should skip crew sessions due to safeguard
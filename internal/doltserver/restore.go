@@ -0,0 +1,255 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point-in-time restore works through the running server and Dolt's commit
+// graph, one database at a time: the restore point is staged on a scratch
+// branch, diffed against live main, and promoted by moving main to it in a
+// single hard reset. The pre-restore head is kept on a safety branch, so a
+// promotion can itself be undone.
+//
+// Tables listed in dolt_ignore (the wisps tables) are not versioned and are
+// left as they are.
+
+const (
+	// RestoreBranchPrefix names scratch branches holding a staged restore.
+	RestoreBranchPrefix = "gt-restore-"
+
+	// PreRestoreBranchPrefix names safety branches holding main as it was
+	// before a promotion.
+	PreRestoreBranchPrefix = "gt-pre-restore-"
+)
+
+// commitHashRe matches a full or abbreviated Dolt commit hash (base32).
+var commitHashRe = regexp.MustCompile(`^[0-9a-v]{8,32}$`)
+
+// restoreTimeLayouts are the absolute timestamp forms --at accepts.
+var restoreTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// restoreSQL runs a query through the server and returns CSV output.
+// Var so tests can stub the server.
+var restoreSQL = doltSQLQuery
+
+// RestorePoint is the commit a database is restored to.
+type RestorePoint struct {
+	Commit  string    `json:"commit"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message,omitempty"`
+}
+
+// TableDiff is the row-level difference a restore makes to one table.
+// Added rows come back with the restore; deleted rows are written after the
+// restore point and go away.
+type TableDiff struct {
+	Table        string `json:"table"`
+	RowsAdded    int64  `json:"rows_added"`
+	RowsDeleted  int64  `json:"rows_deleted"`
+	RowsModified int64  `json:"rows_modified"`
+}
+
+// RestorePlan is a restore staged on a scratch branch, ready to promote.
+type RestorePlan struct {
+	Database string       `json:"database"`
+	Branch   string       `json:"branch"`
+	Point    RestorePoint `json:"point"`
+	LiveHead string       `json:"live_head"`
+	Diff     []TableDiff  `json:"diff"`
+}
+
+// PromoteResult records a promoted restore.
+type PromoteResult struct {
+	SafetyBranch string `json:"safety_branch"`
+	PreviousHead string `json:"previous_head"`
+	NewHead      string `json:"new_head"`
+}
+
+// ParseRestoreAt interprets a --at value: a commit hash (full or at least 8
+// characters), an absolute timestamp in local time, or a duration meaning
+// that long before now ("90m", "2h"). Exactly one of commit and ts is set.
+func ParseRestoreAt(at string, now time.Time) (commit string, ts time.Time, err error) {
+	at = strings.TrimSpace(at)
+	if at == "" {
+		return "", time.Time{}, fmt.Errorf("restore point is empty")
+	}
+	if commitHashRe.MatchString(at) {
+		return at, time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(at); err == nil && d > 0 {
+		return "", now.Add(-d), nil
+	}
+	for _, layout := range restoreTimeLayouts {
+		if t, err := time.ParseInLocation(layout, at, time.Local); err == nil {
+			return "", t, nil
+		}
+	}
+	return "", time.Time{}, fmt.Errorf("invalid restore point %q: want a commit hash, a timestamp (2006-01-02 15:04:05) or a duration ago (2h)", at)
+}
+
+// ResolveRestorePoint finds the commit on db's main branch for a --at value:
+// the commit itself, or the newest commit at or before the timestamp.
+func ResolveRestorePoint(townRoot, db, at string) (*RestorePoint, error) {
+	if !validSQLName(db) {
+		return nil, fmt.Errorf("invalid database name %q: must match [a-zA-Z0-9_.-]+", db)
+	}
+	commit, ts, err := ParseRestoreAt(at, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	where := fmt.Sprintf("commit_hash LIKE '%s%%'", commit)
+	if commit == "" {
+		// Dolt records commit dates in UTC.
+		where = fmt.Sprintf("date <= '%s'", ts.UTC().Format("2006-01-02 15:04:05"))
+	}
+	query := fmt.Sprintf("USE `%s`; SELECT commit_hash, date, REPLACE(message, '\\n', ' ') AS message FROM dolt_log WHERE %s ORDER BY date DESC LIMIT 2", db, where)
+	out, err := restoreSQL(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("resolving restore point in %s: %w", db, err)
+	}
+	rows := parseSimpleCSV(out)
+	switch {
+	case len(rows) == 0 && commit != "":
+		return nil, fmt.Errorf("commit %s is not in the history of %s main", commit, db)
+	case len(rows) == 0:
+		return nil, fmt.Errorf("%s has no commits at or before %s", db, ts.Format(time.RFC3339))
+	case len(rows) > 1 && commit != "":
+		return nil, fmt.Errorf("commit prefix %s is ambiguous in %s", commit, db)
+	}
+	point := &RestorePoint{Commit: rows[0]["commit_hash"], Message: rows[0]["message"]}
+	point.Date, _ = time.ParseInLocation("2006-01-02 15:04:05", rows[0]["date"], time.UTC)
+	return point, nil
+}
+
+// StageRestore resolves the restore point, (re)creates a scratch branch at
+// it, and diffs it against live main. Live data is not touched.
+func StageRestore(townRoot, db, at string) (*RestorePlan, error) {
+	point, err := ResolveRestorePoint(townRoot, db, at)
+	if err != nil {
+		return nil, err
+	}
+	short := point.Commit
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	plan := &RestorePlan{Database: db, Branch: RestoreBranchPrefix + short, Point: *point}
+
+	if _, err := restoreSQL(townRoot, fmt.Sprintf("USE `%s`; CALL DOLT_BRANCH('-f', '%s', '%s')", db, plan.Branch, point.Commit)); err != nil {
+		return nil, fmt.Errorf("creating scratch branch %s: %w", plan.Branch, err)
+	}
+
+	head, err := branchHead(townRoot, db, "main")
+	if err != nil {
+		return nil, err
+	}
+	plan.LiveHead = head
+
+	out, err := restoreSQL(townRoot, fmt.Sprintf(
+		"USE `%s`; SELECT table_name, rows_added, rows_deleted, rows_modified FROM dolt_diff_stat('main', '%s')",
+		db, plan.Branch))
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s against main: %w", plan.Branch, err)
+	}
+	plan.Diff = parseDiffStat(out)
+	return plan, nil
+}
+
+// PromoteRestore makes a staged restore live: main is checkpointed, kept on a
+// safety branch, and hard-reset to the scratch branch. It refuses if main
+// has moved since the plan was staged, so what was reviewed is what lands.
+func PromoteRestore(townRoot string, plan *RestorePlan) (*PromoteResult, error) {
+	db := plan.Database
+	if !validSQLName(db) {
+		return nil, fmt.Errorf("invalid database name %q: must match [a-zA-Z0-9_.-]+", db)
+	}
+	head, err := branchHead(townRoot, db, "main")
+	if err != nil {
+		return nil, err
+	}
+	if head != plan.LiveHead {
+		return nil, fmt.Errorf("%s main moved from %s to %s since the restore was staged; stage it again", db, plan.LiveHead, head)
+	}
+
+	// Checkpoint uncommitted writes so the safety branch holds them too.
+	commit := fmt.Sprintf(
+		"USE `%s`; CALL DOLT_ADD('-A'); CALL DOLT_COMMIT('-m', 'gt dolt restore: checkpoint before restoring %s', '--author', 'Gas Town Restore <restore@gastown.local>')",
+		db, plan.Point.Commit)
+	if _, err := restoreSQL(townRoot, commit); err != nil && !strings.Contains(err.Error(), "nothing to commit") {
+		fmt.Fprintf(os.Stderr, "  %s: checkpoint (non-fatal): %v\n", db, err)
+	}
+	if head, err = branchHead(townRoot, db, "main"); err != nil {
+		return nil, err
+	}
+
+	result := &PromoteResult{
+		SafetyBranch: PreRestoreBranchPrefix + time.Now().UTC().Format("20060102-150405"),
+		PreviousHead: head,
+		NewHead:      plan.Point.Commit,
+	}
+	promote := fmt.Sprintf("USE `%s`; CALL DOLT_BRANCH('%s', 'main'); CALL DOLT_RESET('--hard', '%s'); CALL DOLT_BRANCH('-D', '%s')",
+		db, result.SafetyBranch, plan.Branch, plan.Branch)
+	if _, err := restoreSQL(townRoot, promote); err != nil {
+		return nil, fmt.Errorf("promoting %s: %w", plan.Branch, err)
+	}
+	return result, nil
+}
+
+// DiscardRestores deletes db's scratch restore branches and returns their names.
+func DiscardRestores(townRoot, db string) ([]string, error) {
+	if !validSQLName(db) {
+		return nil, fmt.Errorf("invalid database name %q: must match [a-zA-Z0-9_.-]+", db)
+	}
+	out, err := restoreSQL(townRoot, fmt.Sprintf("USE `%s`; SELECT name FROM dolt_branches WHERE name LIKE '%s%%'", db, RestoreBranchPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("listing restore branches in %s: %w", db, err)
+	}
+	var deleted []string
+	for _, row := range parseSimpleCSV(out) {
+		name := row["name"]
+		if _, err := restoreSQL(townRoot, fmt.Sprintf("USE `%s`; CALL DOLT_BRANCH('-D', '%s')", db, EscapeSQL(name))); err != nil {
+			return deleted, fmt.Errorf("deleting %s: %w", name, err)
+		}
+		deleted = append(deleted, name)
+	}
+	return deleted, nil
+}
+
+// branchHead returns the commit a branch points at.
+func branchHead(townRoot, db, branch string) (string, error) {
+	out, err := restoreSQL(townRoot, fmt.Sprintf("USE `%s`; SELECT HASHOF('%s') AS head", db, branch))
+	if err != nil {
+		return "", fmt.Errorf("reading %s head of %s: %w", branch, db, err)
+	}
+	rows := parseSimpleCSV(out)
+	if len(rows) == 0 || rows[0]["head"] == "" {
+		return "", fmt.Errorf("reading %s head of %s: no result", branch, db)
+	}
+	return rows[0]["head"], nil
+}
+
+// parseDiffStat parses dolt_diff_stat CSV output, skipping unchanged tables.
+func parseDiffStat(out string) []TableDiff {
+	var diffs []TableDiff
+	for _, row := range parseSimpleCSV(out) {
+		d := TableDiff{Table: row["table_name"]}
+		d.RowsAdded, _ = strconv.ParseInt(row["rows_added"], 10, 64)
+		d.RowsDeleted, _ = strconv.ParseInt(row["rows_deleted"], 10, 64)
+		d.RowsModified, _ = strconv.ParseInt(row["rows_modified"], 10, 64)
+		if d.Table == "" || d.RowsAdded+d.RowsDeleted+d.RowsModified == 0 {
+			continue
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
@@ -0,0 +1,175 @@
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRestoreServer stubs restoreSQL: queries are answered by the first
+// response whose key is a substring of the query, and every query is logged.
+type fakeRestoreServer struct {
+	responses [][2]string // key, CSV output
+	failures  map[string]error
+	queries   []string
+}
+
+func stubRestoreSQL(t *testing.T, f *fakeRestoreServer) {
+	t.Helper()
+	old := restoreSQL
+	restoreSQL = func(_ string, query string) (string, error) {
+		f.queries = append(f.queries, query)
+		for key, err := range f.failures {
+			if strings.Contains(query, key) {
+				return "", err
+			}
+		}
+		for _, r := range f.responses {
+			if strings.Contains(query, r[0]) {
+				return r[1], nil
+			}
+		}
+		return "", nil
+	}
+	t.Cleanup(func() { restoreSQL = old })
+}
+
+func (f *fakeRestoreServer) ran(substr string) bool {
+	for _, q := range f.queries {
+		if strings.Contains(q, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseRestoreAt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		at     string
+		commit string
+		ts     time.Time
+	}{
+		{"k2b4v7mqh1s0e9r3f6c8a5d2g4j7l0n1", "k2b4v7mqh1s0e9r3f6c8a5d2g4j7l0n1", time.Time{}},
+		{"k2b4v7mq", "k2b4v7mq", time.Time{}},
+		{"2h", "", now.Add(-2 * time.Hour)},
+		{"2026-02-28 09:30:00", "", time.Date(2026, 2, 28, 9, 30, 0, 0, time.Local)},
+		{"2026-02-28", "", time.Date(2026, 2, 28, 0, 0, 0, 0, time.Local)},
+		{"2026-02-28T09:30:00Z", "", time.Date(2026, 2, 28, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		commit, ts, err := ParseRestoreAt(tt.at, now)
+		if err != nil || commit != tt.commit || !ts.Equal(tt.ts) {
+			t.Errorf("ParseRestoreAt(%q) = %q, %v, %v; want %q, %v", tt.at, commit, ts, err, tt.commit, tt.ts)
+		}
+	}
+	for _, bad := range []string{"", "yesterday", "-2h", "abc", "2026-13-01"} {
+		if _, _, err := ParseRestoreAt(bad, now); err == nil {
+			t.Errorf("ParseRestoreAt(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestResolveRestorePoint_Timestamp(t *testing.T) {
+	f := &fakeRestoreServer{responses: [][2]string{
+		{"FROM dolt_log", "commit_hash,date,message\nk2b4v7mqh1s0e9r3f6c8a5d2g4j7l0n1,2026-02-28 08:00:00.123,bd: close gt-abc\n"},
+	}}
+	stubRestoreSQL(t, f)
+
+	at := time.Date(2026, 2, 28, 9, 30, 0, 0, time.UTC)
+	point, err := ResolveRestorePoint("/town", "gastown", at.Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("ResolveRestorePoint: %v", err)
+	}
+	if point.Commit != "k2b4v7mqh1s0e9r3f6c8a5d2g4j7l0n1" || point.Message != "bd: close gt-abc" {
+		t.Errorf("point = %+v", point)
+	}
+	if want := time.Date(2026, 2, 28, 8, 0, 0, 123e6, time.UTC); !point.Date.Equal(want) {
+		t.Errorf("date = %v, want %v", point.Date, want)
+	}
+	if !f.ran("USE `gastown`") || !f.ran("date <= '2026-02-28 09:30:00'") {
+		t.Errorf("queries = %v, want a UTC date bound on gastown", f.queries)
+	}
+}
+
+func TestResolveRestorePoint_Errors(t *testing.T) {
+	f := &fakeRestoreServer{responses: [][2]string{
+		{"LIKE 'k2b4v7mq%'", "commit_hash,date,message\nk2b4v7mqaaaa,2026-02-28 08:00:00,a\nk2b4v7mqbbbb,2026-02-28 07:00:00,b\n"},
+		{"FROM dolt_log", "commit_hash,date,message\n"},
+	}}
+	stubRestoreSQL(t, f)
+
+	if _, err := ResolveRestorePoint("/town", "gastown", "k2b4v7mq"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("ambiguous prefix: err = %v", err)
+	}
+	if _, err := ResolveRestorePoint("/town", "gastown", "0123456789abcdef"); err == nil || !strings.Contains(err.Error(), "not in the history") {
+		t.Errorf("unknown commit: err = %v", err)
+	}
+	if _, err := ResolveRestorePoint("/town", "gastown", "2000-01-01"); err == nil || !strings.Contains(err.Error(), "no commits") {
+		t.Errorf("before first commit: err = %v", err)
+	}
+	if _, err := ResolveRestorePoint("/town", "gas`town", "2h"); err == nil {
+		t.Error("invalid database name accepted")
+	}
+}
+
+func TestStageAndPromoteRestore(t *testing.T) {
+	const point = "k2b4v7mqh1s0e9r3f6c8a5d2g4j7l0n1"
+	f := &fakeRestoreServer{
+		responses: [][2]string{
+			{"FROM dolt_log", "commit_hash,date,message\n" + point + ",2026-02-28 08:00:00,before the wipe\n"},
+			{"HASHOF('main')", "head\nlivehead0000000000000000000000000\n"},
+			{"dolt_diff_stat", "table_name,rows_added,rows_deleted,rows_modified\n" +
+				"issues,40,2,3\nlabels,0,0,0\ncomments,12,0,0\n"},
+		},
+		failures: map[string]error{"DOLT_COMMIT": fmt.Errorf("nothing to commit")},
+	}
+	stubRestoreSQL(t, f)
+
+	plan, err := StageRestore("/town", "gastown", point)
+	if err != nil {
+		t.Fatalf("StageRestore: %v", err)
+	}
+	if plan.Branch != "gt-restore-k2b4v7mq" || plan.LiveHead != "livehead0000000000000000000000000" {
+		t.Errorf("plan = %+v", plan)
+	}
+	if !f.ran("DOLT_BRANCH('-f', 'gt-restore-k2b4v7mq', '" + point + "')") {
+		t.Errorf("scratch branch not created at the restore point: %v", f.queries)
+	}
+	want := []TableDiff{{"issues", 40, 2, 3}, {"comments", 12, 0, 0}}
+	if len(plan.Diff) != len(want) || plan.Diff[0] != want[0] || plan.Diff[1] != want[1] {
+		t.Errorf("diff = %+v, want %+v (unchanged tables dropped)", plan.Diff, want)
+	}
+	if f.ran("DOLT_RESET") {
+		t.Fatal("staging touched live main")
+	}
+
+	result, err := PromoteRestore("/town", plan)
+	if err != nil {
+		t.Fatalf("PromoteRestore: %v", err)
+	}
+	if !strings.HasPrefix(result.SafetyBranch, PreRestoreBranchPrefix) || result.PreviousHead != plan.LiveHead || result.NewHead != point {
+		t.Errorf("result = %+v", result)
+	}
+	last := f.queries[len(f.queries)-1]
+	wantPromote := fmt.Sprintf("CALL DOLT_BRANCH('%s', 'main'); CALL DOLT_RESET('--hard', 'gt-restore-k2b4v7mq'); CALL DOLT_BRANCH('-D', 'gt-restore-k2b4v7mq')", result.SafetyBranch)
+	if !strings.Contains(last, wantPromote) {
+		t.Errorf("promote query = %q, want it to contain %q", last, wantPromote)
+	}
+}
+
+func TestPromoteRestore_RefusesWhenMainMoved(t *testing.T) {
+	f := &fakeRestoreServer{responses: [][2]string{
+		{"HASHOF('main')", "head\nnewer000000000000000000000000000\n"},
+	}}
+	stubRestoreSQL(t, f)
+
+	plan := &RestorePlan{Database: "gastown", Branch: "gt-restore-k2b4v7mq", LiveHead: "older000000000000000000000000000"}
+	if _, err := PromoteRestore("/town", plan); err == nil || !strings.Contains(err.Error(), "moved") {
+		t.Errorf("err = %v, want main-moved refusal", err)
+	}
+	if f.ran("DOLT_RESET") {
+		t.Error("reset ran although main moved")
+	}
+}
@@ -323,11 +323,22 @@ func backtickKey() string {
 
 // InsertWanted inserts a new wanted item into the wl-commons database.
 func InsertWanted(townRoot string, item *WantedItem) error {
+	script, err := WantedInsertScript(item)
+	if err != nil {
+		return err
+	}
+	return doltSQLScriptWithRetry(townRoot, fmt.Sprintf("USE %s;\n\n%s", WLCommonsDB, script))
+}
+
+// WantedInsertScript returns the SQL that inserts and commits a wanted item
+// in the current database, for running against the commons server or
+// directly in a local clone.
+func WantedInsertScript(item *WantedItem) (string, error) {
 	if item.ID == "" {
-		return fmt.Errorf("wanted item ID cannot be empty")
+		return "", fmt.Errorf("wanted item ID cannot be empty")
 	}
 	if item.Title == "" {
-		return fmt.Errorf("wanted item title cannot be empty")
+		return "", fmt.Errorf("wanted item title cannot be empty")
 	}
 
 	now := time.Now().UTC().Format("2006-01-02 15:04:05")
@@ -369,21 +380,16 @@ func InsertWanted(townRoot string, item *WantedItem) error {
 		status = fmt.Sprintf("'%s'", EscapeSQL(item.Status))
 	}
 
-	script := fmt.Sprintf(`USE %s;
-
-INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
+	return fmt.Sprintf(`INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
 VALUES ('%s', '%s', %s, %s, %s, %d, %s, %s, %s, %s, '%s', '%s');
 
 CALL DOLT_ADD('-A');
 CALL DOLT_COMMIT('-m', 'wl post: %s');
 `,
-		WLCommonsDB,
 		EscapeSQL(item.ID), EscapeSQL(item.Title), descField, projectField, typeField,
 		item.Priority, tagsJSON, postedByField, status, effortField,
 		now, now,
-		EscapeSQL(item.Title))
-
-	return doltSQLScriptWithRetry(townRoot, script)
+		EscapeSQL(item.Title)), nil
 }
 
 // ClaimWanted updates a wanted item's status to claimed.
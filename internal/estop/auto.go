@@ -0,0 +1,291 @@
+package estop
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atomicfile"
+)
+
+// Automatic trigger kinds. Each kind names the signal a rule watches and
+// fixes how that signal's samples are aggregated over the rule's window.
+const (
+	// KindMassDeath counts mass_death events (threshold: events per window).
+	KindMassDeath = "mass_death"
+
+	// KindCostBurn is session cost in USD per hour over the window.
+	KindCostBurn = "cost_burn"
+
+	// KindMainCommitRate is commits the refinery lands on main (the rig's
+	// default branch) per minute, summed over the merges in the window.
+	KindMainCommitRate = "main_commit_rate"
+
+	// KindPushRejections counts pushes rejected by the remote.
+	KindPushRejections = "push_rejections"
+
+	// KindDiskSpace is the used percentage of the filesystem holding the
+	// town (or rig). The window is ignored; the latest reading counts.
+	KindDiskSpace = "disk_space"
+)
+
+// Trigger scopes.
+const (
+	// ScopeTown aggregates samples across the whole town and stops it all.
+	ScopeTown = "town"
+
+	// ScopeRig evaluates each rig separately and stops only the offending rig.
+	ScopeRig = "rig"
+)
+
+// defaultWindows is the aggregation window used when a rule sets none.
+var defaultWindows = map[string]time.Duration{
+	KindMassDeath:      30 * time.Minute,
+	KindCostBurn:       time.Hour,
+	KindMainCommitRate: 5 * time.Minute,
+	KindPushRejections: 15 * time.Minute,
+	KindDiskSpace:      0,
+}
+
+// Rule configures one automatic trigger. A rule fires when its aggregated
+// value reaches Threshold.
+type Rule struct {
+	// Kind is one of the Kind* constants.
+	Kind string `json:"kind"`
+
+	// Threshold is the value at which the rule fires, in the kind's unit.
+	Threshold float64 `json:"threshold"`
+
+	// Window is the lookback for aggregation (e.g., "10m"). Defaults per kind.
+	Window string `json:"window,omitempty"`
+
+	// Scope is "town" (default) or "rig".
+	Scope string `json:"scope,omitempty"`
+
+	// Rigs limits a rig-scoped rule to these rigs. Empty means all rigs.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// Validate reports whether the rule is usable.
+func (r Rule) Validate() error {
+	if _, ok := defaultWindows[r.Kind]; !ok {
+		return fmt.Errorf("unknown trigger kind %q", r.Kind)
+	}
+	if r.Threshold <= 0 {
+		return fmt.Errorf("%s: threshold must be positive", r.Kind)
+	}
+	if r.Window != "" {
+		d, err := time.ParseDuration(r.Window)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid window %q", r.Kind, r.Window)
+		}
+	}
+	switch r.Scope {
+	case "", ScopeTown:
+		if len(r.Rigs) > 0 {
+			return fmt.Errorf("%s: rigs requires scope %q", r.Kind, ScopeRig)
+		}
+	case ScopeRig:
+	default:
+		return fmt.Errorf("%s: unknown scope %q", r.Kind, r.Scope)
+	}
+	return nil
+}
+
+// WindowDuration returns the rule's aggregation window.
+func (r Rule) WindowDuration() time.Duration {
+	if r.Window != "" {
+		if d, err := time.ParseDuration(r.Window); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultWindows[r.Kind]
+}
+
+// IsRigScoped reports whether the rule stops individual rigs.
+func (r Rule) IsRigScoped() bool {
+	return r.Scope == ScopeRig
+}
+
+// Sample is one observation of a trigger signal. Rigs lists the rigs the
+// observation is attributed to; a town-scoped rule counts it once however
+// many rigs it touches, and a rig-scoped rule counts it for each of them.
+type Sample struct {
+	Kind  string
+	Rigs  []string
+	At    time.Time
+	Value float64
+}
+
+// Breach is a rule whose threshold was reached.
+type Breach struct {
+	Rule  Rule
+	Rig   string // set for rig-scoped rules
+	Value float64
+}
+
+// Reason describes the breach for the ESTOP file and the activity feed.
+func (b Breach) Reason() string {
+	var what string
+	switch b.Rule.Kind {
+	case KindMassDeath:
+		what = fmt.Sprintf("%s mass-death events in %s", formatValue(b.Value), b.Rule.WindowDuration())
+	case KindCostBurn:
+		what = fmt.Sprintf("cost burn $%.2f/h over %s", b.Value, b.Rule.WindowDuration())
+	case KindMainCommitRate:
+		what = fmt.Sprintf("%s commits/min to main over %s", formatValue(b.Value), b.Rule.WindowDuration())
+	case KindPushRejections:
+		what = fmt.Sprintf("%s push rejections in %s", formatValue(b.Value), b.Rule.WindowDuration())
+	case KindDiskSpace:
+		what = fmt.Sprintf("disk %.1f%% used", b.Value)
+	}
+	reason := fmt.Sprintf("%s: %s (threshold %s)", b.Rule.Kind, what, formatValue(b.Rule.Threshold))
+	if b.Rig != "" {
+		reason = b.Rig + ": " + reason
+	}
+	return reason
+}
+
+// Monitor evaluates rules against samples. It remembers when each kind last
+// fired for each scope so the observations that caused one stop cannot
+// immediately re-trigger another after a thaw. The fire times are keyed by
+// kind rather than rule, so they survive rules being edited; LoadFired and
+// SaveFired carry them across restarts.
+type Monitor struct {
+	rules []Rule
+	fired map[string]time.Time
+}
+
+// NewMonitor builds a monitor from rules. Invalid rules are dropped and
+// returned as errors so the caller can report them.
+func NewMonitor(rules []Rule) (*Monitor, []error) {
+	m := &Monitor{fired: make(map[string]time.Time)}
+	var errs []error
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("trigger %d: %w", i, err))
+			continue
+		}
+		m.rules = append(m.rules, r)
+	}
+	return m, errs
+}
+
+// Rules returns the valid rules the monitor evaluates.
+func (m *Monitor) Rules() []Rule {
+	return m.rules
+}
+
+// Check returns every rule that is in breach at now. Rig-scoped rules yield
+// one breach per offending rig, ordered by rig name.
+func (m *Monitor) Check(samples []Sample, now time.Time) []Breach {
+	var breaches []Breach
+	for _, r := range m.rules {
+		window := r.WindowDuration()
+		townFired := m.fired[firedKey(r.Kind, "")]
+		start := now.Add(-window)
+
+		values := make(map[string][]float64)
+		for _, s := range samples {
+			if s.Kind != r.Kind || s.At.After(now) {
+				continue
+			}
+			if window > 0 && s.At.Before(start) {
+				continue
+			}
+			if !r.IsRigScoped() {
+				if s.At.After(townFired) {
+					values[""] = append(values[""], s.Value)
+				}
+				continue
+			}
+			for _, rig := range s.Rigs {
+				if len(r.Rigs) > 0 && !slices.Contains(r.Rigs, rig) {
+					continue
+				}
+				// A town-wide stop covered every rig.
+				if s.At.After(townFired) && s.At.After(m.fired[firedKey(r.Kind, rig)]) {
+					values[rig] = append(values[rig], s.Value)
+				}
+			}
+		}
+
+		scopes := make([]string, 0, len(values))
+		for scope := range values {
+			scopes = append(scopes, scope)
+		}
+		slices.Sort(scopes)
+		for _, scope := range scopes {
+			v := aggregate(r.Kind, values[scope], window)
+			if v >= r.Threshold {
+				breaches = append(breaches, Breach{Rule: r, Rig: scope, Value: v})
+			}
+		}
+	}
+	return breaches
+}
+
+// MarkFired records that b stopped work at the given time. Samples at or
+// before it no longer count toward rules of the same kind and scope.
+func (m *Monitor) MarkFired(b Breach, at time.Time) {
+	m.fired[firedKey(b.Rule.Kind, b.Rig)] = at
+}
+
+// LoadFired restores fire times saved by SaveFired. A missing file is not
+// an error. Times already known to the monitor are kept if later.
+func (m *Monitor) LoadFired(path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the daemon's state file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var fired map[string]time.Time
+	if err := json.Unmarshal(data, &fired); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for key, at := range fired {
+		if at.After(m.fired[key]) {
+			m.fired[key] = at
+		}
+	}
+	return nil
+}
+
+// SaveFired writes the monitor's fire times to path.
+func (m *Monitor) SaveFired(path string) error {
+	return atomicfile.EnsureDirAndWriteJSON(path, m.fired)
+}
+
+func firedKey(kind, rig string) string {
+	return kind + "/" + rig
+}
+
+// aggregate folds a rule's windowed sample values into the value compared
+// against its threshold.
+func aggregate(kind string, values []float64, window time.Duration) float64 {
+	if kind == KindDiskSpace {
+		return slices.Max(values)
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	switch kind {
+	case KindCostBurn:
+		return sum / window.Hours()
+	case KindMainCommitRate:
+		return sum / window.Minutes()
+	}
+	return sum
+}
+
+// formatValue prints v with at most two decimals and no trailing zeros.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
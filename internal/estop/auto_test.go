@@ -0,0 +1,158 @@
+package estop
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{"valid town", Rule{Kind: KindMassDeath, Threshold: 2}, ""},
+		{"valid rig", Rule{Kind: KindDiskSpace, Threshold: 95, Scope: ScopeRig, Rigs: []string{"gastown"}}, ""},
+		{"unknown kind", Rule{Kind: "vibes", Threshold: 1}, "unknown trigger kind"},
+		{"zero threshold", Rule{Kind: KindCostBurn}, "threshold must be positive"},
+		{"bad window", Rule{Kind: KindCostBurn, Threshold: 5, Window: "soon"}, "invalid window"},
+		{"bad scope", Rule{Kind: KindCostBurn, Threshold: 5, Scope: "galaxy"}, "unknown scope"},
+		{"rigs on town", Rule{Kind: KindCostBurn, Threshold: 5, Rigs: []string{"gastown"}}, "rigs requires scope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMonitorDropsInvalidRules(t *testing.T) {
+	m, errs := NewMonitor([]Rule{
+		{Kind: KindMassDeath, Threshold: 1},
+		{Kind: "bogus", Threshold: 1},
+	})
+	if len(errs) != 1 || len(m.Rules()) != 1 {
+		t.Fatalf("rules = %v, errs = %v", m.Rules(), errs)
+	}
+}
+
+func TestMonitorCheckTownScope(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, _ := NewMonitor([]Rule{{Kind: KindMassDeath, Threshold: 2, Window: "10m"}})
+
+	samples := []Sample{
+		{Kind: KindMassDeath, Rigs: []string{"gastown", "beads"}, At: now.Add(-2 * time.Minute), Value: 1},
+		{Kind: KindMassDeath, At: now.Add(-20 * time.Minute), Value: 1}, // outside window
+		{Kind: KindPushRejections, At: now.Add(-time.Minute), Value: 1}, // other kind
+	}
+	if got := m.Check(samples, now); len(got) != 0 {
+		t.Fatalf("one event should not breach threshold 2, got %+v", got)
+	}
+
+	samples = append(samples, Sample{Kind: KindMassDeath, Rigs: []string{"gastown"}, At: now.Add(-time.Minute), Value: 1})
+	got := m.Check(samples, now)
+	if len(got) != 1 || got[0].Rig != "" || got[0].Value != 2 {
+		t.Fatalf("Check = %+v, want one town breach with value 2", got)
+	}
+	if reason := got[0].Reason(); reason != "mass_death: 2 mass-death events in 10m0s (threshold 2)" {
+		t.Errorf("Reason = %q", reason)
+	}
+
+	// After firing, the same events cannot re-trigger a stop.
+	m.MarkFired(got[0], now)
+	if again := m.Check(samples, now.Add(time.Minute)); len(again) != 0 {
+		t.Errorf("fired samples re-triggered: %+v", again)
+	}
+}
+
+func TestMonitorFiredSurvivesRestartAndEdit(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "daemon", "auto_estop.json")
+	samples := []Sample{
+		{Kind: KindMainCommitRate, Rigs: []string{"gastown"}, At: now.Add(-time.Minute), Value: 1},
+		{Kind: KindMainCommitRate, Rigs: []string{"gastown"}, At: now.Add(-30 * time.Second), Value: 1},
+	}
+
+	m, _ := NewMonitor([]Rule{{Kind: KindMainCommitRate, Threshold: 0.4, Window: "5m", Scope: ScopeRig}})
+	got := m.Check(samples, now)
+	if len(got) != 1 {
+		t.Fatalf("Check = %+v, want one breach", got)
+	}
+	m.MarkFired(got[0], now)
+	if err := m.SaveFired(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted daemon with an edited rule still ignores what fired.
+	restarted, _ := NewMonitor([]Rule{{Kind: KindMainCommitRate, Threshold: 0.2, Window: "10m", Scope: ScopeRig}})
+	if err := restarted.LoadFired(path); err != nil {
+		t.Fatal(err)
+	}
+	if again := restarted.Check(samples, now.Add(time.Minute)); len(again) != 0 {
+		t.Errorf("fired samples re-triggered after restart: %+v", again)
+	}
+	samples = append(samples, Sample{Kind: KindMainCommitRate, Rigs: []string{"gastown"}, At: now.Add(30 * time.Second), Value: 3})
+	if again := restarted.Check(samples, now.Add(time.Minute)); len(again) != 1 {
+		t.Errorf("new samples should still breach, got %+v", again)
+	}
+
+	if err := restarted.LoadFired(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("LoadFired(missing) = %v", err)
+	}
+}
+
+func TestMonitorCheckRigScope(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, _ := NewMonitor([]Rule{
+		{Kind: KindMainCommitRate, Threshold: 1, Window: "2m", Scope: ScopeRig},
+		{Kind: KindDiskSpace, Threshold: 90, Scope: ScopeRig, Rigs: []string{"beads"}},
+	})
+
+	var samples []Sample
+	for i := range 3 {
+		samples = append(samples, Sample{Kind: KindMainCommitRate, Rigs: []string{"gastown"}, At: now.Add(-time.Duration(i) * 30 * time.Second), Value: 1})
+	}
+	samples = append(samples,
+		Sample{Kind: KindMainCommitRate, Rigs: []string{"beads"}, At: now.Add(-time.Minute), Value: 1},
+		Sample{Kind: KindDiskSpace, Rigs: []string{"gastown"}, At: now, Value: 99},
+		Sample{Kind: KindDiskSpace, Rigs: []string{"beads"}, At: now, Value: 93.5},
+	)
+
+	got := m.Check(samples, now)
+	if len(got) != 2 {
+		t.Fatalf("Check = %+v, want gastown push rate and beads disk", got)
+	}
+	if got[0].Rig != "gastown" || got[0].Value != 1.5 {
+		t.Errorf("push rate breach = %+v, want gastown at 1.5/min", got[0])
+	}
+	if got[1].Rig != "beads" || got[1].Rule.Kind != KindDiskSpace {
+		t.Errorf("disk breach = %+v, want beads (gastown is not in rigs)", got[1])
+	}
+	if reason := got[1].Reason(); reason != "beads: disk_space: disk 93.5% used (threshold 90)" {
+		t.Errorf("Reason = %q", reason)
+	}
+}
+
+func TestMonitorCheckCostBurn(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m, _ := NewMonitor([]Rule{{Kind: KindCostBurn, Threshold: 20, Window: "30m"}})
+	samples := []Sample{
+		{Kind: KindCostBurn, At: now.Add(-10 * time.Minute), Value: 6},
+		{Kind: KindCostBurn, At: now.Add(-5 * time.Minute), Value: 5},
+	}
+	got := m.Check(samples, now)
+	if len(got) != 1 || got[0].Value != 22 {
+		t.Fatalf("Check = %+v, want $22/h", got)
+	}
+}
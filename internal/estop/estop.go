@@ -6,6 +6,10 @@
 //
 // The Mayor is exempt from E-stop so it can coordinate recovery.
 //
+// Besides the manual gt estop command, the daemon can trip the stop
+// automatically: Monitor evaluates configured Rules against anomaly
+// samples and reports the breaches that should stop the town or a rig.
+//
 // Original implementation by outdoorsea (PR #3237).
 package estop
 
@@ -68,6 +68,10 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+	TypePushRejected = "push_rejected" // Remote refused the push to the target branch
+
+	// Emergency stop events
+	TypeAutoEstop = "auto_estop" // Daemon tripped the E-stop on an anomaly
 
 	// Scheduler events
 	TypeSchedulerEnqueue        = "scheduler_enqueue"         // Bead scheduled for deferred dispatch
@@ -225,6 +229,29 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// MergedPayload creates a payload for merged events.
+// It extends MergePayload with the source bead and its type and estimate so
+// completion forecasting can bucket sling-to-merge durations without having
+// to look the bead up again.
+// mainCommits: commits the merge landed on the rig's default branch (0 when
+// it targeted another branch or was not pushed)
+func MergedPayload(mrID, worker, branch, beadID, beadType string, estimateMinutes, mainCommits int) map[string]interface{} {
+	p := MergePayload(mrID, worker, branch, "")
+	if beadID != "" {
+		p["bead"] = beadID
+	}
+	if beadType != "" {
+		p["bead_type"] = beadType
+	}
+	if estimateMinutes > 0 {
+		p["estimate_minutes"] = estimateMinutes
+	}
+	if mainCommits > 0 {
+		p["main_commits"] = mainCommits
+	}
+	return p
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -306,6 +333,21 @@ func SessionDeathPayload(session, agent, reason, caller string) map[string]inter
 	}
 }
 
+// AutoEstopPayload creates a payload for auto_estop events.
+// rig is empty for a town-wide stop.
+func AutoEstopPayload(kind, rig string, value, threshold float64, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"kind":      kind,
+		"value":     value,
+		"threshold": threshold,
+		"reason":    reason,
+	}
+	if rig != "" {
+		p["rig"] = rig
+	}
+	return p
+}
+
 // MassDeathPayload creates a payload for mass death events.
 // count: number of sessions that died
 // window: time window in which deaths occurred (e.g., "5s")
@@ -0,0 +1,211 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// clientTimeout bounds a single request to a peer.
+const clientTimeout = 30 * time.Second
+
+// peerSyncTimeout bounds the time one Flush or RefreshRemoteBeads pass
+// spends on a single peer, so an unreachable peer holds up the others by at
+// most this much. A variable so tests can shorten it.
+var peerSyncTimeout = 15 * time.Second
+
+// Client talks to one peer town.
+type Client struct {
+	Peer string
+	url  string
+	http *http.Client
+}
+
+// NewClient creates a client for a registered peer.
+func NewClient(identity *Identity, name string, peer *config.FederationPeer) *Client {
+	return &Client{
+		Peer: name,
+		url:  strings.TrimSuffix(peer.URL, "/"),
+		http: &http.Client{
+			Timeout:   clientTimeout,
+			Transport: &http.Transport{TLSClientConfig: identity.clientTLSConfig(name, peer)},
+		},
+	}
+}
+
+// Ping checks connectivity and authentication, returning the peer's own
+// federation name.
+func (c *Client) Ping(ctx context.Context) (string, error) {
+	var resp struct {
+		Town string `json:"town"`
+	}
+	if err := c.do(ctx, http.MethodGet, pingPath, nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Town, nil
+}
+
+// SendMail delivers one envelope and returns the peer's receipt.
+func (c *Client) SendMail(ctx context.Context, env *Envelope) (*Receipt, error) {
+	var receipt Receipt
+	if err := c.do(ctx, http.MethodPost, mailPath, env, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt.ID != env.ID {
+		return nil, fmt.Errorf("receipt for %q does not match envelope %q", receipt.ID, env.ID)
+	}
+	return &receipt, nil
+}
+
+// FetchBeads returns the status of beads in the peer town. Unknown IDs are
+// omitted from the result.
+func (c *Client) FetchBeads(ctx context.Context, ids []string) ([]RemoteBead, error) {
+	var out []RemoteBead
+	path := beadsPath + "?ids=" + url.QueryEscape(strings.Join(ids, ","))
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("contacting %s: %w", c.Peer, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: HTTP %d: %s", c.Peer, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: decoding response: %w", c.Peer, err)
+	}
+	return nil
+}
+
+// FlushResult summarizes one pass over the outbox.
+type FlushResult struct {
+	Delivered []*Receipt
+	Failed    []*mail.FederatedMail
+	// Deferred entries were not attempted because their peer used up its
+	// time this pass. They stay queued as they were.
+	Deferred []*mail.FederatedMail
+}
+
+// flushPeer is one peer's client and time budget within a Flush pass.
+type flushPeer struct {
+	client *Client
+	ctx    context.Context
+}
+
+// Flush forwards every queued outbound message. Delivered entries are
+// removed from the outbox and their receipts kept under receipts/<peer>/;
+// failed entries stay queued with the error recorded for the next pass.
+// Each peer gets peerSyncTimeout; messages to a peer that runs out of it are
+// deferred to the next pass.
+func Flush(ctx context.Context, townRoot string) (*FlushResult, error) {
+	queued, err := mail.ListFederatedOutbox(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing federation outbox: %w", err)
+	}
+	result := &FlushResult{}
+	if len(queued) == 0 {
+		return result, nil
+	}
+
+	cfg, err := config.LoadFederationConfig(config.FederationConfigPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading federation config: %w", err)
+	}
+	identity, err := LoadIdentity(townRoot, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make(map[string]*flushPeer)
+	for _, fm := range queued {
+		path := filepath.Join(mail.FederationOutboxDir(townRoot, fm.Peer), fm.ID+".json")
+		peer, ok := cfg.Peers[fm.Peer]
+		if !ok {
+			recordAttempt(path, fm, fmt.Errorf("peer %s is not registered", fm.Peer))
+			result.Failed = append(result.Failed, fm)
+			continue
+		}
+		fp, ok := peers[fm.Peer]
+		if !ok {
+			peerCtx, cancel := context.WithTimeout(ctx, peerSyncTimeout)
+			defer cancel()
+			fp = &flushPeer{client: NewClient(identity, fm.Peer, peer), ctx: peerCtx}
+			peers[fm.Peer] = fp
+		}
+		if fp.ctx.Err() != nil {
+			result.Deferred = append(result.Deferred, fm)
+			continue
+		}
+
+		receipt, err := fp.client.SendMail(fp.ctx, &Envelope{ID: fm.ID, To: fm.To, Message: fm.Message})
+		if err != nil {
+			recordAttempt(path, fm, err)
+			result.Failed = append(result.Failed, fm)
+			continue
+		}
+		receiptPath := filepath.Join(ReceiptsDir(townRoot, fm.Peer), fm.ID+".json")
+		if err := atomicfile.EnsureDirAndWriteJSON(receiptPath, receipt); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: saving receipt for %s: %v\n", fm.ID, err)
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: removing delivered outbox entry %s: %v\n", fm.ID, err)
+		}
+		result.Delivered = append(result.Delivered, receipt)
+	}
+	return result, nil
+}
+
+// recordAttempt updates a failed outbox entry in place.
+func recordAttempt(path string, fm *mail.FederatedMail, sendErr error) {
+	fm.Attempts++
+	fm.LastAttempt = time.Now()
+	fm.LastError = sendErr.Error()
+	if err := atomicfile.WriteJSON(path, fm); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: updating outbox entry %s: %v\n", fm.ID, err)
+	}
+}
+
+// ReceiptsDir returns the directory holding delivery receipts from a peer.
+func ReceiptsDir(townRoot, peer string) string {
+	return filepath.Join(Dir(townRoot), "receipts", peer)
+}
+
+// ReadReceipt returns the delivery receipt for an outbound message, if the
+// peer has acknowledged it.
+func ReadReceipt(townRoot, peer, id string) (*Receipt, error) {
+	return readReceipt(filepath.Join(ReceiptsDir(townRoot, peer), id+".json"))
+}
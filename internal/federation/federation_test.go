@@ -0,0 +1,396 @@
+package federation
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/proxy"
+)
+
+// testTown is a town with a federation identity.
+type testTown struct {
+	root string
+	cfg  *config.FederationConfig
+	id   *Identity
+}
+
+func newTestTown(t *testing.T, name string) *testTown {
+	t.Helper()
+	root := t.TempDir()
+	cfg := config.NewFederationConfig()
+	cfg.Name = name
+	if err := config.SaveFederationConfig(config.FederationConfigPath(root), cfg); err != nil {
+		t.Fatalf("SaveFederationConfig: %v", err)
+	}
+	id, err := LoadIdentity(root, cfg)
+	if err != nil {
+		t.Fatalf("LoadIdentity(%s): %v", name, err)
+	}
+	return &testTown{root: root, cfg: cfg, id: id}
+}
+
+// addPeer registers peer under name, trusting caPEM, reachable at url.
+func (tt *testTown) addPeer(t *testing.T, name, url string, caPEM []byte) {
+	t.Helper()
+	tt.cfg.Peers[name] = &config.FederationPeer{URL: url, CACert: string(caPEM)}
+	if err := config.SaveFederationConfig(config.FederationConfigPath(tt.root), tt.cfg); err != nil {
+		t.Fatalf("SaveFederationConfig: %v", err)
+	}
+}
+
+// fakeDelivery records messages delivered by a Server.
+type fakeDelivery struct {
+	mu   sync.Mutex
+	msgs []*mail.Message
+	err  error
+}
+
+func (f *fakeDelivery) deliver(msg *mail.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.msgs = append(f.msgs, msg)
+	return nil
+}
+
+// startServer runs town's federation endpoint over mTLS.
+func startServer(t *testing.T, town *testTown, delivery *fakeDelivery) *httptest.Server {
+	t.Helper()
+	s := NewServer(town.root, town.id)
+	s.deliver = delivery.deliver
+	s.lookupBeads = func(ids []string) (map[string]*beads.Issue, error) {
+		out := make(map[string]*beads.Issue)
+		for _, id := range ids {
+			if id == "gt-remote" {
+				out[id] = &beads.Issue{ID: id, Title: "Remote work", Status: "in_progress", Assignee: "gastown/Toast"}
+			}
+		}
+		return out, nil
+	}
+	srv := httptest.NewUnstartedServer(s.Handler())
+	srv.TLS = town.id.serverTLSConfig()
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newPeeredTowns returns laptop and build towns registered with each other,
+// with build's endpoint running.
+func newPeeredTowns(t *testing.T) (laptop, build *testTown, delivery *fakeDelivery) {
+	t.Helper()
+	laptop = newTestTown(t, "laptop")
+	build = newTestTown(t, "build")
+	delivery = &fakeDelivery{}
+	srv := startServer(t, build, delivery)
+	laptop.addPeer(t, "build", srv.URL, build.id.CA.CertPEM)
+	build.addPeer(t, "laptop", "https://laptop.invalid:7443", laptop.id.CA.CertPEM)
+	return laptop, build, delivery
+}
+
+func TestLoadIdentity_ReusesAndReissues(t *testing.T) {
+	town := newTestTown(t, "laptop")
+	again, err := LoadIdentity(town.root, town.cfg)
+	if err != nil {
+		t.Fatalf("LoadIdentity: %v", err)
+	}
+	if again.Leaf.SerialNumber.Cmp(town.id.Leaf.SerialNumber) != 0 {
+		t.Error("certificate reissued although still valid")
+	}
+
+	town.cfg.Name = "renamed"
+	renamed, err := LoadIdentity(town.root, town.cfg)
+	if err != nil {
+		t.Fatalf("LoadIdentity: %v", err)
+	}
+	if renamed.Leaf.Subject.CommonName != "renamed" {
+		t.Errorf("CN = %q after rename, want renamed", renamed.Leaf.Subject.CommonName)
+	}
+}
+
+func TestFlush_DeliversWithReceipt(t *testing.T) {
+	laptop, _, delivery := newPeeredTowns(t)
+
+	router := mail.NewRouterWithTownRoot(laptop.root, laptop.root)
+	if err := router.Send(&mail.Message{From: "mayor/", To: "build:gastown/Toast", Subject: "Handoff", Body: "take gt-1"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	queued, _ := mail.ListFederatedOutbox(laptop.root)
+	if len(queued) != 1 {
+		t.Fatalf("queued %d, want 1", len(queued))
+	}
+
+	result, err := Flush(context.Background(), laptop.root)
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(result.Delivered) != 1 || len(result.Failed) != 0 {
+		t.Fatalf("result = %d delivered, %d failed", len(result.Delivered), len(result.Failed))
+	}
+	if left, _ := mail.ListFederatedOutbox(laptop.root); len(left) != 0 {
+		t.Errorf("outbox still holds %d entries after delivery", len(left))
+	}
+	receipt, err := ReadReceipt(laptop.root, "build", queued[0].ID)
+	if err != nil {
+		t.Fatalf("ReadReceipt: %v", err)
+	}
+	if receipt.Peer != "build" || receipt.To != "gastown/Toast" || receipt.DeliveredAt.IsZero() {
+		t.Errorf("receipt = %+v", receipt)
+	}
+
+	if len(delivery.msgs) != 1 {
+		t.Fatalf("delivered %d messages, want 1", len(delivery.msgs))
+	}
+	got := delivery.msgs[0]
+	if got.From != "laptop:mayor/" || got.To != "gastown/Toast" || got.Subject != "Handoff" {
+		t.Errorf("delivered message = %+v", got)
+	}
+}
+
+func TestServer_DeduplicatesRetries(t *testing.T) {
+	laptop, _, delivery := newPeeredTowns(t)
+	client := NewClient(laptop.id, "build", laptop.cfg.Peers["build"])
+
+	env := &Envelope{ID: "msg-1", To: "mayor/", Message: &mail.Message{From: "mayor/", Subject: "once"}}
+	first, err := client.SendMail(context.Background(), env)
+	if err != nil {
+		t.Fatalf("SendMail: %v", err)
+	}
+	second, err := client.SendMail(context.Background(), env)
+	if err != nil {
+		t.Fatalf("SendMail retry: %v", err)
+	}
+	if first.Duplicate || !second.Duplicate {
+		t.Errorf("duplicate flags = %v, %v; want false, true", first.Duplicate, second.Duplicate)
+	}
+	if len(delivery.msgs) != 1 {
+		t.Errorf("delivered %d times, want 1", len(delivery.msgs))
+	}
+}
+
+func TestServer_RejectsUnregisteredTown(t *testing.T) {
+	_, build, delivery := newPeeredTowns(t)
+	stranger := newTestTown(t, "stranger")
+	srv := startServer(t, build, delivery)
+	stranger.addPeer(t, "build", srv.URL, build.id.CA.CertPEM)
+
+	_, err := NewClient(stranger.id, "build", stranger.cfg.Peers["build"]).Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Ping error = %v, want 401", err)
+	}
+}
+
+func TestClient_RejectsWrongServerCA(t *testing.T) {
+	laptop, build, delivery := newPeeredTowns(t)
+	impostor := newTestTown(t, "impostor")
+	impostor.addPeer(t, "laptop", "https://laptop.invalid:7443", laptop.id.CA.CertPEM)
+	srv := startServer(t, impostor, delivery)
+	// laptop expects build's CA but reaches the impostor.
+	laptop.addPeer(t, "build", srv.URL, build.id.CA.CertPEM)
+
+	_, err := NewClient(laptop.id, "build", laptop.cfg.Peers["build"]).Ping(context.Background())
+	if err == nil {
+		t.Fatal("Ping succeeded against a server outside the pinned CA")
+	}
+}
+
+// withCert returns a copy of town's identity presenting a certificate issued
+// by its CA through issue instead of the town certificate.
+func withCert(t *testing.T, town *testTown, issue func(*proxy.CA) ([]byte, []byte, error)) *Identity {
+	t.Helper()
+	certPEM, keyPEM, err := issue(town.id.CA)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair: %v", err)
+	}
+	id := *town.id
+	id.Cert = cert
+	return &id
+}
+
+func TestServer_RejectsPolecatCert(t *testing.T) {
+	laptop, build, _ := newPeeredTowns(t)
+	// Same CA as laptop's town certificate, but issued to a polecat.
+	polecat := withCert(t, laptop, func(ca *proxy.CA) ([]byte, []byte, error) {
+		return ca.IssuePolecat("gt-gastown-furiosa", time.Hour)
+	})
+	_, err := NewClient(polecat, "build", laptop.cfg.Peers["build"]).Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Ping with polecat cert error = %v, want 401", err)
+	}
+
+	// A client cert named after the peer is still not a town certificate.
+	named := withCert(t, laptop, func(ca *proxy.CA) ([]byte, []byte, error) {
+		return ca.IssuePolecat("gt-laptop-x", time.Hour)
+	})
+	build.addPeer(t, "gt-laptop-x", "https://laptop.invalid:7443", laptop.id.CA.CertPEM)
+	_, err = NewClient(named, "build", laptop.cfg.Peers["build"]).Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Ping with polecat cert named after a peer error = %v, want 401", err)
+	}
+}
+
+func TestServer_RejectsPeerUnderOtherName(t *testing.T) {
+	laptop, build, _ := newPeeredTowns(t)
+	// build knows laptop's CA only under another name.
+	delete(build.cfg.Peers, "laptop")
+	build.addPeer(t, "desktop", "https://laptop.invalid:7443", laptop.id.CA.CertPEM)
+
+	_, err := NewClient(laptop.id, "build", laptop.cfg.Peers["build"]).Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Ping error = %v, want 401", err)
+	}
+}
+
+func TestClient_RejectsNonTownServerCert(t *testing.T) {
+	laptop, build, delivery := newPeeredTowns(t)
+	// build's endpoint presents a proxy server certificate from its CA.
+	proxyServer := withCert(t, build, func(ca *proxy.CA) ([]byte, []byte, error) {
+		return ca.IssueServer("build", nil, nil, time.Hour)
+	})
+	srv := startServer(t, &testTown{root: build.root, cfg: build.cfg, id: proxyServer}, delivery)
+	laptop.addPeer(t, "build", srv.URL, build.id.CA.CertPEM)
+
+	_, err := NewClient(laptop.id, "build", laptop.cfg.Peers["build"]).Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not a town certificate") {
+		t.Fatalf("Ping error = %v, want town certificate rejection", err)
+	}
+}
+
+func TestFlush_FailureStaysQueued(t *testing.T) {
+	laptop, _, delivery := newPeeredTowns(t)
+	delivery.err = errors.New("no such agent")
+
+	router := mail.NewRouterWithTownRoot(laptop.root, laptop.root)
+	if err := router.Send(&mail.Message{From: "mayor/", To: "build:gastown/Nobody", Subject: "hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	result, err := Flush(context.Background(), laptop.root)
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(result.Failed) != 1 {
+		t.Fatalf("failed %d, want 1", len(result.Failed))
+	}
+	left, _ := mail.ListFederatedOutbox(laptop.root)
+	if len(left) != 1 {
+		t.Fatalf("outbox holds %d entries, want 1", len(left))
+	}
+	if left[0].Attempts != 1 || !strings.Contains(left[0].LastError, "no such agent") {
+		t.Errorf("entry attempts/error = %d / %q", left[0].Attempts, left[0].LastError)
+	}
+}
+
+func TestFlush_UnresponsivePeerIsBounded(t *testing.T) {
+	laptop, build, delivery := newPeeredTowns(t)
+
+	// A peer that accepts connections and never answers.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	laptop.addPeer(t, "hung", "https://"+ln.Addr().String(), build.id.CA.CertPEM)
+
+	old := peerSyncTimeout
+	peerSyncTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { peerSyncTimeout = old })
+
+	router := mail.NewRouterWithTownRoot(laptop.root, laptop.root)
+	for _, to := range []string{"hung:gastown/A", "hung:gastown/B", "hung:gastown/C", "build:gastown/Toast"} {
+		if err := router.Send(&mail.Message{From: "mayor/", To: to, Subject: "hi"}); err != nil {
+			t.Fatalf("Send %s: %v", to, err)
+		}
+	}
+
+	start := time.Now()
+	result, err := Flush(context.Background(), laptop.root)
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Flush took %s with an unresponsive peer", elapsed)
+	}
+	if len(result.Delivered) != 1 || len(delivery.msgs) != 1 {
+		t.Errorf("delivered %d (server saw %d), want 1", len(result.Delivered), len(delivery.msgs))
+	}
+	if len(result.Failed)+len(result.Deferred) != 3 || len(result.Deferred) == 0 {
+		t.Errorf("failed %d, deferred %d; want the hung peer's 3 split with some deferred", len(result.Failed), len(result.Deferred))
+	}
+	left, _ := mail.ListFederatedOutbox(laptop.root)
+	if len(left) != 3 {
+		t.Errorf("outbox holds %d entries, want 3", len(left))
+	}
+}
+
+func TestRemoteBeads_TrackAndLookup(t *testing.T) {
+	laptop, _, _ := newPeeredTowns(t)
+
+	peer, id, ok := SplitRemoteBeadRef(laptop.root, "build:gt-remote")
+	if !ok || peer != "build" || id != "gt-remote" {
+		t.Fatalf("SplitRemoteBeadRef = %q, %q, %v", peer, id, ok)
+	}
+	if _, _, ok := SplitRemoteBeadRef(laptop.root, "desktop:gt-remote"); ok {
+		t.Error("unregistered peer accepted as remote bead ref")
+	}
+	if err := TrackRemoteBead(laptop.root, peer, id); err != nil {
+		t.Fatalf("TrackRemoteBead: %v", err)
+	}
+
+	found := LookupRemoteBeads(laptop.root, []string{"gt-remote", "gt-local"})
+	rb, ok := found["gt-remote"]
+	if !ok || len(found) != 1 {
+		t.Fatalf("LookupRemoteBeads = %v", found)
+	}
+	if rb.Ref() != "build:gt-remote" || rb.Status != "in_progress" || rb.Title != "Remote work" {
+		t.Errorf("remote bead = %+v", rb)
+	}
+
+	// The refreshed status is cached for when the peer is unreachable.
+	ix, err := loadRemoteIndex(laptop.root)
+	if err != nil {
+		t.Fatalf("loadRemoteIndex: %v", err)
+	}
+	if cached := ix.Beads["gt-remote"]; cached == nil || cached.Status != "in_progress" || cached.FetchedAt.IsZero() {
+		t.Errorf("cached entry = %+v", cached)
+	}
+}
+
+func TestServer_RejectsPathTraversalID(t *testing.T) {
+	laptop, build, delivery := newPeeredTowns(t)
+	client := NewClient(laptop.id, "build", laptop.cfg.Peers["build"])
+	env := &Envelope{ID: "../../escape", To: "mayor/", Message: &mail.Message{From: "mayor/", Subject: "x"}}
+	if _, err := client.SendMail(context.Background(), env); err == nil {
+		t.Fatal("envelope with path separators accepted")
+	}
+	if len(delivery.msgs) != 0 {
+		t.Error("message delivered despite invalid envelope ID")
+	}
+	if _, err := os.Stat(Dir(build.root) + "/escape.json"); !os.IsNotExist(err) {
+		t.Error("receipt written outside the inbox")
+	}
+}
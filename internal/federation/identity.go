@@ -0,0 +1,237 @@
+// Package federation links towns so that mail and convoy tracking can cross
+// town boundaries.
+//
+// Each town has an identity: a certificate issued by its proxy CA
+// (<townRoot>/.runtime/ca, shared with gt-proxy-server) for the town's
+// federation name. Peers are registered in settings/federation.json under
+// their federation names with their endpoint URL and CA certificate, and
+// every connection is mutual TLS pinned to the peer's CA and name. Outbound
+// mail is store-and-forward: the mail router queues it in the town's outbox
+// and Flush delivers it, keeping each entry until the peer returns a delivery
+// receipt.
+package federation
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/proxy"
+)
+
+const (
+	// townCertTTL is the lifetime of an issued town certificate.
+	townCertTTL = 365 * 24 * time.Hour
+
+	// townCertRenewBefore reissues the town certificate when it is this
+	// close to expiry.
+	townCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// Dir returns the federation runtime directory of a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "federation")
+}
+
+// CADir returns the town's CA directory. It is the same CA gt-proxy-server
+// uses, so a town has a single trust root.
+func CADir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "ca")
+}
+
+// Identity is a town's federation credentials.
+type Identity struct {
+	Name string
+	CA   *proxy.CA
+	Cert tls.Certificate
+	Leaf *x509.Certificate
+}
+
+// LoadIdentity loads the town's federation certificate, issuing a new one
+// when it is missing, close to expiry, or no longer matches the configured
+// name or CA.
+func LoadIdentity(townRoot string, cfg *config.FederationConfig) (*Identity, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("federation name not set (run 'gt federation init <name>')")
+	}
+	ca, err := proxy.LoadOrGenerateCA(CADir(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town CA: %w", err)
+	}
+
+	id, err := readIdentity(townRoot, ca)
+	if err == nil && checkTownProfile(id.Leaf, cfg.Name) == nil &&
+		time.Until(id.Leaf.NotAfter) > townCertRenewBefore {
+		id.Name = cfg.Name
+		return id, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Warning: reissuing federation certificate: %v\n", err)
+	}
+	return issueIdentity(townRoot, cfg, ca)
+}
+
+// IssueIdentity unconditionally issues a new town certificate, e.g. after
+// the configured name or hosts change.
+func IssueIdentity(townRoot string, cfg *config.FederationConfig) (*Identity, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("federation name not set (run 'gt federation init <name>')")
+	}
+	ca, err := proxy.LoadOrGenerateCA(CADir(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town CA: %w", err)
+	}
+	return issueIdentity(townRoot, cfg, ca)
+}
+
+func issueIdentity(townRoot string, cfg *config.FederationConfig, ca *proxy.CA) (*Identity, error) {
+	var dnsNames []string
+	var ips []net.IP
+	for _, h := range cfg.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, h)
+		}
+	}
+
+	certPEM, keyPEM, err := ca.IssueTown(cfg.Name, dnsNames, ips, townCertTTL)
+	if err != nil {
+		return nil, fmt.Errorf("issuing town certificate: %w", err)
+	}
+	dir := Dir(townRoot)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating federation dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "town.crt"), certPEM, 0600); err != nil {
+		return nil, fmt.Errorf("writing town.crt: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "town.key"), keyPEM, 0600); err != nil {
+		return nil, fmt.Errorf("writing town.key: %w", err)
+	}
+
+	id, err := readIdentity(townRoot, ca)
+	if err != nil {
+		return nil, err
+	}
+	id.Name = cfg.Name
+	return id, nil
+}
+
+// readIdentity loads town.crt/town.key and checks they chain to ca.
+func readIdentity(townRoot string, ca *proxy.CA) (*Identity, error) {
+	dir := Dir(townRoot)
+	certPEM, err := os.ReadFile(filepath.Join(dir, "town.crt")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, "town.key")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse town keypair: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse town cert: %w", err)
+	}
+	if err := leaf.CheckSignatureFrom(ca.Cert); err != nil {
+		return nil, fmt.Errorf("town cert not issued by current CA: %w", err)
+	}
+	cert.Leaf = leaf
+	return &Identity{CA: ca, Cert: cert, Leaf: leaf}, nil
+}
+
+// peerRoots parses a peer's pinned CA certificate.
+func peerRoots(peer *config.FederationPeer) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(peer.CACert)) {
+		return nil, fmt.Errorf("invalid peer CA certificate")
+	}
+	return pool, nil
+}
+
+// checkTownProfile checks that leaf is a town certificate for name, as
+// issued by IssueTown. The CA behind it also issues proxy server and polecat
+// certificates, which must not pass as the town.
+func checkTownProfile(leaf *x509.Certificate, name string) error {
+	if leaf.Subject.CommonName != name {
+		return fmt.Errorf("certificate is for %q, not %q", leaf.Subject.CommonName, name)
+	}
+	if !slices.Contains(leaf.Subject.OrganizationalUnit, proxy.TownCertOU) {
+		return fmt.Errorf("certificate for %q is not a town certificate", name)
+	}
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		if !slices.Contains(leaf.ExtKeyUsage, usage) {
+			return fmt.Errorf("certificate for %q is not a town certificate", name)
+		}
+	}
+	return nil
+}
+
+// verifyPeerChain checks that certs (leaf first) are the town certificate of
+// the peer registered as name, chain to the peer's CA and carry the given
+// usage.
+func verifyPeerChain(name string, peer *config.FederationPeer, certs []*x509.Certificate, usage x509.ExtKeyUsage) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("no certificate presented")
+	}
+	if err := checkTownProfile(certs[0], name); err != nil {
+		return err
+	}
+	roots, err := peerRoots(peer)
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	return err
+}
+
+// clientTLSConfig returns the TLS config for connecting to the peer
+// registered as name. The server certificate is verified against the peer's
+// pinned CA and town name rather than by hostname, so peers can be reached by
+// any address.
+func (id *Identity) clientTLSConfig(name string, peer *config.FederationPeer) *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{id.Cert},
+		// Standard verification checks the hostname against the system roots;
+		// VerifyConnection replaces it with verification against the pinned CA.
+		InsecureSkipVerify: true, //nolint:gosec // G402: verified in VerifyConnection
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if err := verifyPeerChain(name, peer, cs.PeerCertificates, x509.ExtKeyUsageServerAuth); err != nil {
+				return fmt.Errorf("peer certificate rejected: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// serverTLSConfig returns the TLS config for the federation server. A client
+// certificate is required; which peer it belongs to is decided per request
+// against the current config, so peers can be added without a restart.
+func (id *Identity) serverTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{id.Cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+	}
+}
@@ -0,0 +1,241 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// remoteRefreshAfter is how old a cached remote bead status may be before
+// lookups try to refresh it from the peer.
+const remoteRefreshAfter = time.Minute
+
+// remoteLookupTimeout bounds the refresh done inline by LookupRemoteBeads,
+// so an unreachable peer only delays convoy status briefly.
+const remoteLookupTimeout = 5 * time.Second
+
+// TrackedRemoteBead is a peer-town bead tracked by a local convoy, with its
+// last known status.
+type TrackedRemoteBead struct {
+	Peer string `json:"peer"`
+	RemoteBead
+}
+
+// Ref returns the "<peer>:<id>" form users type and convoys display.
+func (b *TrackedRemoteBead) Ref() string {
+	return b.Peer + ":" + b.ID
+}
+
+// remoteIndex maps bead ID to its tracked remote entry. Convoys store remote
+// beads as external:<peer>:<id> dependencies, and bead IDs are unwrapped
+// before lookup, so this index remembers which peer each ID lives in.
+type remoteIndex struct {
+	Beads map[string]*TrackedRemoteBead `json:"beads"`
+}
+
+func remoteIndexPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "remote_beads.json")
+}
+
+// SplitRemoteBeadRef parses "<peer>:<bead-id>" where peer is a registered
+// peer town.
+func SplitRemoteBeadRef(townRoot, ref string) (peer, id string, ok bool) {
+	peer, id, ok = strings.Cut(ref, ":")
+	if !ok || id == "" || strings.Contains(id, ":") || !config.ValidFederationPeerName(peer) {
+		return "", "", false
+	}
+	cfg, err := config.LoadFederationConfig(config.FederationConfigPath(townRoot))
+	if err != nil {
+		return "", "", false
+	}
+	if _, registered := cfg.Peers[peer]; !registered {
+		return "", "", false
+	}
+	return peer, id, true
+}
+
+// TrackRemoteBead records that bead id lives in peer, so convoy status can
+// resolve it.
+func TrackRemoteBead(townRoot, peer, id string) error {
+	return updateRemoteIndex(townRoot, func(ix *remoteIndex) {
+		if existing, ok := ix.Beads[id]; ok && existing.Peer == peer {
+			return
+		}
+		ix.Beads[id] = &TrackedRemoteBead{Peer: peer, RemoteBead: RemoteBead{ID: id}}
+	})
+}
+
+// LookupRemoteBeads returns the tracked remote entries among ids, keyed by
+// bead ID. Stale entries are refreshed from their peers first; if a peer is
+// unreachable the last known status is returned.
+func LookupRemoteBeads(townRoot string, ids []string) map[string]*TrackedRemoteBead {
+	ix, err := loadRemoteIndex(townRoot)
+	if err != nil || len(ix.Beads) == 0 {
+		return nil
+	}
+	found := make(map[string]*TrackedRemoteBead)
+	var stale []*TrackedRemoteBead
+	for _, id := range ids {
+		if b, ok := ix.Beads[id]; ok {
+			found[id] = b
+			if time.Since(b.FetchedAt) > remoteRefreshAfter {
+				stale = append(stale, b)
+			}
+		}
+	}
+	if len(stale) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteLookupTimeout)
+		defer cancel()
+		if refreshed, err := refreshRemoteBeads(ctx, townRoot, stale); err == nil {
+			for id, b := range refreshed {
+				if _, ok := found[id]; ok {
+					found[id] = b
+				}
+			}
+		}
+	}
+	return found
+}
+
+// RefreshRemoteBeads refreshes every tracked remote bead that is not yet
+// closed. The daemon calls this on its heartbeat so cached status stays
+// current while nobody is looking.
+func RefreshRemoteBeads(ctx context.Context, townRoot string) error {
+	ix, err := loadRemoteIndex(townRoot)
+	if err != nil {
+		return err
+	}
+	var open []*TrackedRemoteBead
+	for _, b := range ix.Beads {
+		if b.Status != "closed" {
+			open = append(open, b)
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+	_, err = refreshRemoteBeads(ctx, townRoot, open)
+	return err
+}
+
+// refreshRemoteBeads fetches the given beads from their peers and writes the
+// results to the index. It returns the updated entries; per-peer failures
+// leave cached entries untouched and are reported in the joined error. Each
+// peer gets peerSyncTimeout.
+func refreshRemoteBeads(ctx context.Context, townRoot string, beads []*TrackedRemoteBead) (map[string]*TrackedRemoteBead, error) {
+	cfg, err := config.LoadFederationConfig(config.FederationConfigPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	identity, err := LoadIdentity(townRoot, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	byPeer := make(map[string][]string)
+	for _, b := range beads {
+		byPeer[b.Peer] = append(byPeer[b.Peer], b.ID)
+	}
+	peers := make([]string, 0, len(byPeer))
+	for p := range byPeer {
+		peers = append(peers, p)
+	}
+	sort.Strings(peers)
+
+	updated := make(map[string]*TrackedRemoteBead)
+	var errs []error
+	now := time.Now()
+	for _, name := range peers {
+		peer, ok := cfg.Peers[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("peer %s is not registered", name))
+			continue
+		}
+		fetched, err := fetchPeerBeads(ctx, NewClient(identity, name, peer), byPeer[name])
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, rb := range fetched {
+			rb.FetchedAt = now
+			updated[rb.ID] = &TrackedRemoteBead{Peer: name, RemoteBead: rb}
+		}
+	}
+
+	if len(updated) > 0 {
+		if err := updateRemoteIndex(townRoot, func(ix *remoteIndex) {
+			for id, b := range updated {
+				if existing, ok := ix.Beads[id]; ok && existing.Peer == b.Peer {
+					ix.Beads[id] = b
+				}
+			}
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return updated, errors.Join(errs...)
+}
+
+// fetchPeerBeads fetches ids from one peer in batches, within
+// peerSyncTimeout. Batches fetched before an error are returned with it.
+func fetchPeerBeads(ctx context.Context, client *Client, ids []string) ([]RemoteBead, error) {
+	ctx, cancel := context.WithTimeout(ctx, peerSyncTimeout)
+	defer cancel()
+	var out []RemoteBead
+	for start := 0; start < len(ids); start += maxBeadsPerRequest {
+		end := min(start+maxBeadsPerRequest, len(ids))
+		fetched, err := client.FetchBeads(ctx, ids[start:end])
+		if err != nil {
+			return out, err
+		}
+		out = append(out, fetched...)
+	}
+	return out, nil
+}
+
+func loadRemoteIndex(townRoot string) (*remoteIndex, error) {
+	ix := &remoteIndex{Beads: make(map[string]*TrackedRemoteBead)}
+	data, err := os.ReadFile(remoteIndexPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ix, nil
+		}
+		return nil, fmt.Errorf("reading remote bead index: %w", err)
+	}
+	if err := json.Unmarshal(data, ix); err != nil {
+		return nil, fmt.Errorf("parsing remote bead index: %w", err)
+	}
+	if ix.Beads == nil {
+		ix.Beads = make(map[string]*TrackedRemoteBead)
+	}
+	return ix, nil
+}
+
+func updateRemoteIndex(townRoot string, fn func(ix *remoteIndex)) error {
+	path := remoteIndexPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating federation dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring remote bead index lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	ix, err := loadRemoteIndex(townRoot)
+	if err != nil {
+		return err
+	}
+	fn(ix)
+	return atomicfile.WriteJSON(path, ix)
+}
@@ -0,0 +1,280 @@
+package federation
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// API paths served by a town's federation endpoint.
+const (
+	mailPath  = "/federation/v1/mail"
+	beadsPath = "/federation/v1/beads"
+	pingPath  = "/federation/v1/ping"
+)
+
+// maxEnvelopeBytes bounds an inbound mail envelope.
+const maxEnvelopeBytes = 1 << 20
+
+// maxBeadsPerRequest bounds a remote bead lookup.
+const maxBeadsPerRequest = 200
+
+// Envelope carries one message between towns.
+type Envelope struct {
+	ID      string        `json:"id"` // sender's outbox ID, used for deduplication
+	To      string        `json:"to"` // address inside the receiving town
+	Message *mail.Message `json:"message"`
+}
+
+// Receipt acknowledges delivery of an envelope into the receiving town's mail.
+type Receipt struct {
+	ID          string    `json:"id"`
+	Peer        string    `json:"peer"` // receiving town, as named by the sender
+	To          string    `json:"to"`
+	DeliveredAt time.Time `json:"delivered_at"`
+	Duplicate   bool      `json:"duplicate,omitempty"` // envelope was already delivered
+}
+
+// RemoteBead is the status of a bead in a peer town, as used by convoy tracking.
+type RemoteBead struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	IssueType string    `json:"issue_type,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"`
+	Labels    []string  `json:"labels,omitempty"`
+	Blocked   bool      `json:"blocked,omitempty"`
+	FetchedAt time.Time `json:"fetched_at,omitempty"`
+}
+
+// Server is a town's federation endpoint.
+type Server struct {
+	townRoot string
+	identity *Identity
+
+	// deliver hands an inbound message to local mail routing.
+	deliver func(msg *mail.Message) error
+	// lookupBeads returns local beads by ID.
+	lookupBeads func(ids []string) (map[string]*beads.Issue, error)
+
+	mu sync.Mutex // serializes inbound deliveries for deduplication
+}
+
+// NewServer creates the federation server for a town.
+func NewServer(townRoot string, identity *Identity) *Server {
+	return &Server{
+		townRoot: townRoot,
+		identity: identity,
+		deliver: func(msg *mail.Message) error {
+			router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+			defer router.WaitPendingNotifications()
+			return router.Send(msg)
+		},
+		lookupBeads: func(ids []string) (map[string]*beads.Issue, error) {
+			return beads.New(townRoot).ShowMultiple(ids)
+		},
+	}
+}
+
+// Handler returns the HTTP handler for the federation API. Requests must
+// arrive over TLS with a client certificate issued by a registered peer's CA.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pingPath, s.handlePing)
+	mux.HandleFunc(mailPath, s.handleMail)
+	mux.HandleFunc(beadsPath, s.handleBeads)
+	return mux
+}
+
+// ListenAndServe serves the federation API on addr until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		TLSConfig:         s.identity.serverTLSConfig(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServeTLS("", "") }()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// authenticate returns the name of the registered peer whose town
+// certificate the request's client presented.
+func (s *Server) authenticate(r *http.Request) (string, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return "", fmt.Errorf("client certificate required")
+	}
+	cfg, err := config.LoadFederationConfig(config.FederationConfigPath(s.townRoot))
+	if err != nil {
+		return "", fmt.Errorf("federation not configured")
+	}
+	names := make([]string, 0, len(cfg.Peers))
+	for name := range cfg.Peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if verifyPeerChain(name, cfg.Peers[name], r.TLS.PeerCertificates, x509.ExtKeyUsageClientAuth) == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("certificate not issued by a registered peer")
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	peer, err := s.authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, map[string]string{"town": s.identity.Name, "peer": peer})
+}
+
+// handleMail delivers an envelope into local mail. The sender is rewritten to
+// "<peer>:<from>" so replies route back through federation. Envelopes are
+// deduplicated by ID so a sender can safely retry after a lost receipt.
+func (s *Server) handleMail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	peer, err := s.authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var env Envelope
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxEnvelopeBytes)).Decode(&env); err != nil {
+		http.Error(w, "invalid envelope", http.StatusBadRequest)
+		return
+	}
+	if env.ID == "" || env.To == "" || env.Message == nil || !validEnvelopeID(env.ID) {
+		http.Error(w, "envelope requires id, to and message", http.StatusBadRequest)
+		return
+	}
+	if _, _, ok := mail.SplitFederatedAddress(env.To); ok {
+		http.Error(w, "multi-hop federation is not supported", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seenPath := filepath.Join(Dir(s.townRoot), "inbox", peer, env.ID+".json")
+	if prev, err := readReceipt(seenPath); err == nil {
+		prev.Duplicate = true
+		writeJSON(w, prev)
+		return
+	}
+
+	msg := *env.Message
+	msg.ID = ""
+	msg.To = env.To
+	msg.From = peer + ":" + env.Message.From
+	msg.NotBefore = time.Time{}
+	if err := s.deliver(&msg); err != nil {
+		http.Error(w, "delivery failed: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	receipt := &Receipt{ID: env.ID, Peer: s.identity.Name, To: env.To, DeliveredAt: time.Now()}
+	if err := atomicfile.EnsureDirAndWriteJSON(seenPath, receipt); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recording federated delivery %s: %v\n", env.ID, err)
+	}
+	writeJSON(w, receipt)
+}
+
+// handleBeads returns the status of local beads for a peer's convoy tracking.
+func (s *Server) handleBeads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := s.authenticate(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var ids []string
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxBeadsPerRequest {
+		http.Error(w, fmt.Sprintf("ids must list 1-%d bead IDs", maxBeadsPerRequest), http.StatusBadRequest)
+		return
+	}
+
+	issues, err := s.lookupBeads(ids)
+	if err != nil {
+		http.Error(w, "bead lookup failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := make([]RemoteBead, 0, len(issues))
+	for _, id := range ids {
+		issue, ok := issues[id]
+		if !ok || issue == nil {
+			continue
+		}
+		out = append(out, RemoteBead{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			IssueType: issue.Type,
+			Assignee:  issue.Assignee,
+			Labels:    issue.Labels,
+			Blocked:   len(issue.BlockedBy) > 0,
+		})
+	}
+	writeJSON(w, out)
+}
+
+// validEnvelopeID rejects IDs that could escape the inbox directory.
+func validEnvelopeID(id string) bool {
+	return len(id) <= 128 && !strings.ContainsAny(id, `/\`) && id != "." && id != ".."
+}
+
+func readReceipt(path string) (*Receipt, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var receipt Receipt
+	if err := json.Unmarshal(data, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
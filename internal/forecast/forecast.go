@@ -0,0 +1,302 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// DefaultIterations is the number of Monte Carlo runs when Options leaves
+// Iterations unset.
+const DefaultIterations = 2000
+
+// Distribution is an empirical duration distribution.
+type Distribution struct {
+	durations []time.Duration // sorted ascending
+	source    string          // bucket key, "estimate", or "default"
+}
+
+// Source names the history bucket (or fallback) the distribution came from.
+func (d Distribution) Source() string { return d.source }
+
+// Len returns the number of durations backing the distribution.
+func (d Distribution) Len() int { return len(d.durations) }
+
+// Quantile returns the q-th quantile (0..1) using nearest-rank.
+func (d Distribution) Quantile(q float64) time.Duration {
+	if len(d.durations) == 0 {
+		return 0
+	}
+	return quantile(d.durations, q)
+}
+
+// draw samples a remaining duration for work that has already run for
+// elapsed. Only durations longer than elapsed are eligible; work that has
+// outrun all history is assumed to need another quarter of its elapsed time.
+func (d Distribution) draw(rng *rand.Rand, elapsed time.Duration) time.Duration {
+	i := sort.Search(len(d.durations), func(i int) bool { return d.durations[i] > elapsed })
+	if i == len(d.durations) {
+		return elapsed / 4
+	}
+	return d.durations[i+rng.Intn(len(d.durations)-i)] - elapsed
+}
+
+// Task is one bead in the graph being forecast.
+type Task struct {
+	ID              string
+	Type            string
+	EstimateMinutes int
+	Done            bool      // closed beads contribute no remaining time
+	StartedAt       time.Time // sling time for in-flight beads; zero if not started
+	BlockedBy       []string  // IDs of tasks that must finish first
+}
+
+// Options tunes a forecast run.
+type Options struct {
+	Now        time.Time
+	Iterations int
+	Seed       int64
+}
+
+// Leverage describes how much a bead gates completion.
+type Leverage struct {
+	ID string
+	// Criticality is the fraction of simulations in which the bead sat on
+	// the critical path.
+	Criticality float64
+	// Median is the bead's median remaining duration.
+	Median time.Duration
+	// Impact is Criticality × Median: the expected completion time that
+	// would be saved by finishing this bead sooner.
+	Impact time.Duration
+}
+
+// Result is the outcome of a forecast.
+type Result struct {
+	P50          time.Duration
+	P90          time.Duration
+	P50At        time.Time
+	P90At        time.Time
+	CriticalPath []string // longest chain using median durations, first to last
+	Leverage     []Leverage
+	Remaining    int // tasks not yet done
+	HistorySize  int // historical samples available
+}
+
+// Run forecasts completion of tasks using durations drawn from h.
+//
+// The simulation is dependency-bound: every task starts as soon as its
+// blockers finish, so the result is the earliest achievable completion with
+// unlimited polecats. Blockers outside the task set are ignored.
+func Run(tasks []Task, h *History, opts Options) (*Result, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = DefaultIterations
+	}
+	if h == nil {
+		h = NewHistory(nil)
+	}
+
+	order, err := topoOrder(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(order)
+	dists := make([]Distribution, n)
+	elapsed := make([]time.Duration, n)
+	preds := make([][]int, n)
+	index := make(map[string]int, n)
+	for i, t := range order {
+		index[t.ID] = i
+	}
+	result := &Result{HistorySize: len(h.Samples)}
+	for i, t := range order {
+		if !t.Done {
+			result.Remaining++
+			dists[i] = h.Distribution(t.Type, t.EstimateMinutes)
+			if !t.StartedAt.IsZero() && opts.Now.After(t.StartedAt) {
+				elapsed[i] = opts.Now.Sub(t.StartedAt)
+			}
+		}
+		for _, b := range t.BlockedBy {
+			if j, ok := index[b]; ok {
+				preds[i] = append(preds[i], j)
+			}
+		}
+	}
+
+	// Deterministic critical path from median remaining durations.
+	medians := make([]time.Duration, n)
+	for i, t := range order {
+		if !t.Done {
+			medians[i] = medianRemaining(dists[i], elapsed[i])
+		}
+	}
+	_, path := longestPath(medians, preds)
+	for _, i := range path {
+		result.CriticalPath = append(result.CriticalPath, order[i].ID)
+	}
+
+	// Monte Carlo.
+	rng := rand.New(rand.NewSource(opts.Seed)) //nolint:gosec // forecasting, not security
+	totals := make([]time.Duration, opts.Iterations)
+	onPath := make([]int, n)
+	durs := make([]time.Duration, n)
+	for it := 0; it < opts.Iterations; it++ {
+		for i, t := range order {
+			durs[i] = 0
+			if !t.Done {
+				durs[i] = dists[i].draw(rng, elapsed[i])
+			}
+		}
+		total, p := longestPath(durs, preds)
+		totals[it] = total
+		for _, i := range p {
+			onPath[i]++
+		}
+	}
+	sortDurations(totals)
+	result.P50 = quantile(totals, 0.5)
+	result.P90 = quantile(totals, 0.9)
+	result.P50At = opts.Now.Add(result.P50)
+	result.P90At = opts.Now.Add(result.P90)
+
+	for i, t := range order {
+		if t.Done || onPath[i] == 0 {
+			continue
+		}
+		crit := float64(onPath[i]) / float64(opts.Iterations)
+		result.Leverage = append(result.Leverage, Leverage{
+			ID:          t.ID,
+			Criticality: crit,
+			Median:      medians[i],
+			Impact:      time.Duration(crit * float64(medians[i])),
+		})
+	}
+	sort.SliceStable(result.Leverage, func(i, j int) bool {
+		if result.Leverage[i].Impact != result.Leverage[j].Impact {
+			return result.Leverage[i].Impact > result.Leverage[j].Impact
+		}
+		return result.Leverage[i].ID < result.Leverage[j].ID
+	})
+
+	return result, nil
+}
+
+// medianRemaining is the median of the remaining-time distribution for a
+// task that has already run for elapsed.
+func medianRemaining(d Distribution, elapsed time.Duration) time.Duration {
+	i := sort.Search(len(d.durations), func(i int) bool { return d.durations[i] > elapsed })
+	if i == len(d.durations) {
+		return elapsed / 4
+	}
+	return quantile(d.durations[i:], 0.5) - elapsed
+}
+
+// longestPath returns the finish time of the longest dependency chain and
+// the chain itself (first to last). nodes must be in topological order.
+func longestPath(durs []time.Duration, preds [][]int) (time.Duration, []int) {
+	finish := make([]time.Duration, len(durs))
+	via := make([]int, len(durs))
+	end := -1
+	for i := range durs {
+		via[i] = -1
+		var start time.Duration
+		for _, p := range preds[i] {
+			if finish[p] > start || (via[i] == -1 && finish[p] == start) {
+				start = finish[p]
+				via[i] = p
+			}
+		}
+		finish[i] = start + durs[i]
+		if end == -1 || finish[i] > finish[end] {
+			end = i
+		}
+	}
+	if end == -1 {
+		return 0, nil
+	}
+
+	var path []int
+	for i := end; i != -1; i = via[i] {
+		if durs[i] > 0 {
+			path = append(path, i)
+		}
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return finish[end], path
+}
+
+// topoOrder sorts tasks so every task follows its blockers. Ties keep ID
+// order so results are reproducible. Returns an error naming a task on a
+// cycle.
+func topoOrder(tasks []Task) ([]Task, error) {
+	byID := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(ids))
+	order := make([]Task, 0, len(ids))
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("dependency cycle through %s", id)
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		blockers := append([]string(nil), byID[id].BlockedBy...)
+		sort.Strings(blockers)
+		for _, b := range blockers {
+			if _, ok := byID[b]; !ok {
+				continue
+			}
+			if err := visit(b); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		order = append(order, byID[id])
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// quantile returns the nearest-rank q-th quantile of sorted durations.
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
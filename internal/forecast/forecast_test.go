@@ -0,0 +1,118 @@
+package forecast
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedHistory returns a history where every bead takes exactly d.
+func fixedHistory(d time.Duration) *History {
+	samples := make([]Sample, MinBucketSamples)
+	for i := range samples {
+		samples[i] = Sample{Type: "task", Duration: d}
+	}
+	return NewHistory(samples)
+}
+
+func TestRunCriticalPath(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{ID: "a", Type: "task"},
+		{ID: "b", Type: "task", BlockedBy: []string{"a"}},
+		{ID: "c", Type: "task", BlockedBy: []string{"b"}},
+		{ID: "d", Type: "task"},
+		{ID: "done", Type: "task", Done: true},
+		{ID: "e", Type: "task", BlockedBy: []string{"done", "outside-convoy"}},
+	}
+
+	res, err := Run(tasks, fixedHistory(time.Hour), Options{Now: now, Iterations: 100})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(res.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", res.CriticalPath, want)
+	}
+	if res.P50 != 3*time.Hour || res.P90 != 3*time.Hour {
+		t.Errorf("P50/P90 = %v/%v, want 3h/3h", res.P50, res.P90)
+	}
+	if !res.P50At.Equal(now.Add(3 * time.Hour)) {
+		t.Errorf("P50At = %v, want %v", res.P50At, now.Add(3*time.Hour))
+	}
+	if res.Remaining != 5 {
+		t.Errorf("Remaining = %d, want 5", res.Remaining)
+	}
+	if len(res.Leverage) != 3 || res.Leverage[0].ID != "a" || res.Leverage[0].Criticality != 1 {
+		t.Errorf("Leverage = %+v, want a,b,c fully critical", res.Leverage)
+	}
+}
+
+func TestRunInFlightUsesElapsed(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	h := NewHistory([]Sample{
+		{Type: "task", Duration: time.Hour},
+		{Type: "task", Duration: 2 * time.Hour},
+		{Type: "task", Duration: 4 * time.Hour},
+	})
+	tasks := []Task{{ID: "a", Type: "task", StartedAt: now.Add(-90 * time.Minute)}}
+
+	res, err := Run(tasks, h, Options{Now: now, Iterations: 200})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Only the 2h and 4h samples outlast 90m of elapsed work.
+	if res.P50 < 30*time.Minute || res.P90 > 150*time.Minute {
+		t.Errorf("P50/P90 = %v/%v, want within remaining 30m..2h30m", res.P50, res.P90)
+	}
+}
+
+func TestRunDeterministicWithSeed(t *testing.T) {
+	h := NewHistory([]Sample{
+		{Type: "task", Duration: time.Hour},
+		{Type: "task", Duration: 3 * time.Hour},
+		{Type: "task", Duration: 8 * time.Hour},
+	})
+	tasks := []Task{
+		{ID: "a", Type: "task"},
+		{ID: "b", Type: "task"},
+		{ID: "c", Type: "task", BlockedBy: []string{"a", "b"}},
+	}
+	opts := Options{Now: time.Unix(0, 0), Iterations: 500, Seed: 7}
+	r1, err := Run(tasks, h, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, _ := Run(tasks, h, opts)
+	if r1.P50 != r2.P50 || r1.P90 != r2.P90 {
+		t.Errorf("same seed gave different forecasts: %v/%v vs %v/%v", r1.P50, r1.P90, r2.P50, r2.P90)
+	}
+	if r1.P90 < r1.P50 {
+		t.Errorf("P90 %v < P50 %v", r1.P90, r1.P50)
+	}
+	if r1.Leverage[0].ID != "c" {
+		t.Errorf("top leverage = %s, want c (always on the critical path)", r1.Leverage[0].ID)
+	}
+}
+
+func TestRunCycle(t *testing.T) {
+	tasks := []Task{
+		{ID: "a", BlockedBy: []string{"b"}},
+		{ID: "b", BlockedBy: []string{"a"}},
+	}
+	_, err := Run(tasks, nil, Options{})
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Run with cycle: err = %v, want cycle error", err)
+	}
+}
+
+func TestRunAllDone(t *testing.T) {
+	res, err := Run([]Task{{ID: "a", Done: true}}, nil, Options{Iterations: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.P50 != 0 || res.Remaining != 0 || len(res.CriticalPath) != 0 {
+		t.Errorf("all-done forecast = %+v, want zero", res)
+	}
+}
@@ -0,0 +1,218 @@
+// Package forecast predicts when a dependency graph of beads will finish.
+//
+// Historical sling-to-merge durations are mined from the town events log
+// (~/gt/.events.jsonl) and bucketed by bead type and estimate. Those buckets
+// drive a Monte Carlo simulation over the graph that yields P50/P90
+// completion times, the critical path, and the beads whose delay most often
+// gates completion.
+package forecast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// MinBucketSamples is the number of samples a bucket needs before it is
+// trusted on its own. Sparser buckets fall back to broader ones.
+const MinBucketSamples = 3
+
+// DefaultDuration is the assumed median for a bead with no history and no
+// estimate.
+const DefaultDuration = 2 * time.Hour
+
+// fallbackSpread scales a single base duration into a synthetic
+// distribution when no history is available, so the simulation still
+// expresses uncertainty rather than a single point.
+var fallbackSpread = []float64{0.5, 0.75, 1, 1.5, 2.5}
+
+// Sample is one observed sling-to-merge duration.
+type Sample struct {
+	BeadID          string
+	Type            string
+	EstimateMinutes int
+	Duration        time.Duration
+}
+
+// History holds completed-work samples and the sling times of beads that
+// have not merged yet.
+type History struct {
+	Samples []Sample
+
+	slungAt map[string]time.Time
+	buckets map[string][]time.Duration
+}
+
+// LoadHistory reads the events log for the town rooted at townRoot.
+// A missing log yields an empty history rather than an error.
+func LoadHistory(townRoot string) (*History, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewHistory(nil), nil
+		}
+		return nil, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+	return ParseHistory(f)
+}
+
+// ParseHistory pairs each bead's first sling event with its merged event.
+// Timing starts at the first sling so re-slings after a failed attempt
+// count the whole effort. A done event is used as the end time only when
+// no merged event follows it. Merged events without a bead (older
+// refineries) and malformed lines are skipped.
+func ParseHistory(r io.Reader) (*History, error) {
+	slung := make(map[string]time.Time)
+	done := make(map[string]time.Time)
+	var samples []Sample
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		bead, _ := e.Payload["bead"].(string)
+		if bead == "" {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+
+		switch e.Type {
+		case events.TypeSling:
+			if _, ok := slung[bead]; !ok {
+				slung[bead] = ts
+			}
+		case events.TypeDone:
+			if _, ok := slung[bead]; ok {
+				done[bead] = ts
+			}
+		case events.TypeMerged:
+			start, ok := slung[bead]
+			if !ok || !ts.After(start) {
+				continue
+			}
+			beadType, _ := e.Payload["bead_type"].(string)
+			estimate, _ := e.Payload["estimate_minutes"].(float64)
+			samples = append(samples, Sample{
+				BeadID:          bead,
+				Type:            beadType,
+				EstimateMinutes: int(estimate),
+				Duration:        ts.Sub(start),
+			})
+			delete(slung, bead)
+			delete(done, bead)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events log: %w", err)
+	}
+
+	// Beads that finished via gt done but never produced a merged event
+	// (direct merges, local strategy) still contribute a sample.
+	for bead, end := range done {
+		start := slung[bead]
+		if end.After(start) {
+			samples = append(samples, Sample{BeadID: bead, Duration: end.Sub(start)})
+		}
+		delete(slung, bead)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].BeadID < samples[j].BeadID })
+
+	h := NewHistory(samples)
+	h.slungAt = slung
+	return h, nil
+}
+
+// NewHistory builds a History from samples.
+func NewHistory(samples []Sample) *History {
+	h := &History{
+		Samples: samples,
+		slungAt: make(map[string]time.Time),
+		buckets: make(map[string][]time.Duration),
+	}
+	for _, s := range samples {
+		for _, key := range bucketKeys(s.Type, s.EstimateMinutes) {
+			h.buckets[key] = append(h.buckets[key], s.Duration)
+		}
+	}
+	for key := range h.buckets {
+		sortDurations(h.buckets[key])
+	}
+	return h
+}
+
+// SlungAt returns when an unmerged bead was first slung.
+func (h *History) SlungAt(beadID string) (time.Time, bool) {
+	ts, ok := h.slungAt[beadID]
+	return ts, ok
+}
+
+// Distribution returns the duration distribution for a bead of the given
+// type and estimate. Buckets are tried from most to least specific: type
+// and estimate, type alone, estimate alone, then all history. With no
+// usable history the estimate (or DefaultDuration) seeds a synthetic spread.
+func (h *History) Distribution(beadType string, estimateMinutes int) Distribution {
+	for _, key := range bucketKeys(beadType, estimateMinutes) {
+		if d := h.buckets[key]; len(d) >= MinBucketSamples {
+			return Distribution{durations: d, source: key}
+		}
+	}
+
+	base := DefaultDuration
+	source := "default"
+	if estimateMinutes > 0 {
+		base = time.Duration(estimateMinutes) * time.Minute
+		source = "estimate"
+	}
+	synthetic := make([]time.Duration, len(fallbackSpread))
+	for i, f := range fallbackSpread {
+		synthetic[i] = time.Duration(float64(base) * f)
+	}
+	return Distribution{durations: synthetic, source: source}
+}
+
+// bucketKeys lists the buckets a bead falls into, most specific first.
+func bucketKeys(beadType string, estimateMinutes int) []string {
+	est := estimateBucket(estimateMinutes)
+	var keys []string
+	if beadType != "" {
+		keys = append(keys, "type="+beadType+",est="+est, "type="+beadType)
+	}
+	if estimateMinutes > 0 {
+		keys = append(keys, "est="+est)
+	}
+	return append(keys, "all")
+}
+
+// estimateBucket coarsens an estimate so similar-sized beads share history.
+func estimateBucket(minutes int) string {
+	switch {
+	case minutes <= 0:
+		return "none"
+	case minutes <= 30:
+		return "30m"
+	case minutes <= 120:
+		return "2h"
+	case minutes <= 480:
+		return "8h"
+	default:
+		return "large"
+	}
+}
+
+func sortDurations(d []time.Duration) {
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+}
@@ -0,0 +1,107 @@
+package forecast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEvents = `{"ts":"2026-01-01T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-a","target":"gastown"}}
+{"ts":"2026-01-01T10:30:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-a","target":"gastown"}}
+{"ts":"2026-01-01T11:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-b","target":"gastown"}}
+not json
+{"ts":"2026-01-01T12:00:00Z","type":"merged","actor":"gastown/refinery","payload":{"mr":"gt-mr1","bead":"gt-a","bead_type":"task","estimate_minutes":60}}
+{"ts":"2026-01-01T12:00:00Z","type":"merged","actor":"gastown/refinery","payload":{"mr":"gt-mr0","branch":"polecat/x"}}
+{"ts":"2026-01-01T11:30:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-c","target":"gastown"}}
+{"ts":"2026-01-01T12:30:00Z","type":"done","actor":"gastown/polecats/x","payload":{"bead":"gt-c","branch":"polecat/x"}}
+`
+
+func TestParseHistory(t *testing.T) {
+	h, err := ParseHistory(strings.NewReader(testEvents))
+	if err != nil {
+		t.Fatalf("ParseHistory: %v", err)
+	}
+	if len(h.Samples) != 2 {
+		t.Fatalf("got %d samples, want 2: %+v", len(h.Samples), h.Samples)
+	}
+
+	a := h.Samples[0]
+	if a.BeadID != "gt-a" || a.Duration != 2*time.Hour || a.Type != "task" || a.EstimateMinutes != 60 {
+		t.Errorf("gt-a sample = %+v, want 2h task est=60 measured from first sling", a)
+	}
+	c := h.Samples[1]
+	if c.BeadID != "gt-c" || c.Duration != time.Hour {
+		t.Errorf("gt-c sample = %+v, want 1h from done fallback", c)
+	}
+
+	if ts, ok := h.SlungAt("gt-b"); !ok || ts.Hour() != 11 {
+		t.Errorf("SlungAt(gt-b) = %v, %v; want 11:00, true", ts, ok)
+	}
+	if _, ok := h.SlungAt("gt-a"); ok {
+		t.Error("merged bead gt-a should not be reported as in flight")
+	}
+}
+
+func TestLoadHistoryMissingFile(t *testing.T) {
+	h, err := LoadHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(h.Samples) != 0 {
+		t.Errorf("expected empty history, got %d samples", len(h.Samples))
+	}
+}
+
+func TestLoadHistoryReadsEventsFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".events.jsonl"), []byte(testEvents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(h.Samples) != 2 {
+		t.Errorf("got %d samples, want 2", len(h.Samples))
+	}
+}
+
+func TestDistributionFallback(t *testing.T) {
+	samples := []Sample{
+		{Type: "bug", EstimateMinutes: 20, Duration: time.Hour},
+		{Type: "bug", EstimateMinutes: 20, Duration: 2 * time.Hour},
+		{Type: "bug", EstimateMinutes: 20, Duration: 3 * time.Hour},
+		{Type: "task", Duration: 10 * time.Hour},
+	}
+	h := NewHistory(samples)
+
+	tests := []struct {
+		name     string
+		beadType string
+		estimate int
+		source   string
+	}{
+		{"exact bucket", "bug", 25, "type=bug,est=30m"},
+		{"type bucket", "bug", 0, "type=bug"},
+		{"estimate bucket", "feature", 10, "est=30m"},
+		{"all history", "task", 0, "all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := h.Distribution(tt.beadType, tt.estimate)
+			if d.Source() != tt.source {
+				t.Errorf("Source() = %q, want %q", d.Source(), tt.source)
+			}
+		})
+	}
+
+	empty := NewHistory(nil)
+	if d := empty.Distribution("task", 90); d.Source() != "estimate" || d.Quantile(0.5) != 90*time.Minute {
+		t.Errorf("estimate fallback = %s median %v, want estimate 1h30m", d.Source(), d.Quantile(0.5))
+	}
+	if d := empty.Distribution("task", 0); d.Source() != "default" || d.Quantile(0.5) != DefaultDuration {
+		t.Errorf("default fallback = %s median %v, want default %v", d.Source(), d.Quantile(0.5), DefaultDuration)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -1518,6 +1519,24 @@ func (g *Git) RecentCommits(n int) (string, error) {
 	return g.run("log", "--oneline", fmt.Sprintf("-%d", n))
 }
 
+// LastCommitTime returns the committer time of HEAD.
+func (g *Git) LastCommitTime() (time.Time, error) {
+	out, err := g.run("log", "-1", "--format=%ct")
+	if err != nil {
+		return time.Time{}, err
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing commit time %q: %w", out, err)
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// StatusSummary returns `git status --short --branch` output for display.
+func (g *Git) StatusSummary() (string, error) {
+	return g.run("status", "--short", "--branch")
+}
+
 // DeleteRemoteBranch deletes a branch on the remote.
 func (g *Git) DeleteRemoteBranch(remote, branch string) error {
 	_, err := g.runWithTimeout(pushTimeout, "push", remote, "--delete", branch)
@@ -2269,6 +2288,44 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 	return InitSubmodules(path, g.submoduleReferencePath())
 }
 
+// WorktreeAddNoCheckout registers a worktree at path without populating its
+// files or index, for callers that materialize the checkout themselves (see
+// internal/worktree). With startPoint set it creates branch from startPoint;
+// otherwise it attaches the existing branch, even if checked out elsewhere.
+func (g *Git) WorktreeAddNoCheckout(path, branch, startPoint string) error {
+	args := []string{"worktree", "add", "--no-checkout"}
+	if startPoint != "" {
+		args = append(args, "-b", branch, path, startPoint)
+	} else {
+		args = append(args, "--force", path, branch)
+	}
+	_, err := g.run(args...)
+	return err
+}
+
+// WorktreeCheckout populates the worktree at path by resetting it to ref,
+// as the worktree-add methods do at creation: the LFS smudge filter is
+// skipped (see WorktreeAddFromRef) and submodules are initialized. Used for
+// worktrees added with WorktreeAddNoCheckout and for moving a detached
+// worktree to another commit.
+func (g *Git) WorktreeCheckout(path, ref string) error {
+	if _, err := NewGit(path).runWithEnv(
+		[]string{"reset", "--hard", ref},
+		[]string{"GIT_LFS_SKIP_SMUDGE=1"},
+	); err != nil {
+		return err
+	}
+	return InitSubmodules(path, g.submoduleReferencePath())
+}
+
+// RefreshIndex re-stats tracked files so the index matches a working tree
+// whose files were written outside git. Files whose content differs stay
+// modified; only cached stat data is updated.
+func (g *Git) RefreshIndex() error {
+	_, err := g.run("update-index", "-q", "--refresh")
+	return err
+}
+
 // submoduleReferencePath returns the mayor/rig path to use as --reference
 // for submodule init. For bare repos (.repo.git), this resolves to the
 // sibling mayor/rig directory which contains the initialized submodules.
@@ -3361,6 +3418,23 @@ func InitSparseCheckout(repoPath string, paths []string) error {
 	return nil
 }
 
+// AddSparseCheckout adds paths to a cone-mode sparse checkout, checking out
+// the files under them.
+func AddSparseCheckout(repoPath string, paths []string) error {
+	if err := EnsureSafeMutationWorkDir(repoPath); err != nil {
+		return err
+	}
+	args := append([]string{"-C", repoPath, "sparse-checkout", "add"}, paths...)
+	cmd := exec.Command("git", args...)
+	util.SetDetachedProcessGroup(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("adding sparse checkout paths: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
 // SubmoduleChanges detects submodule pointer changes between two refs.
 // Returns nil if no submodules changed or if the repo has no submodules.
 func (g *Git) SubmoduleChanges(base, head string) ([]SubmoduleChange, error) {
@@ -1585,6 +1585,25 @@ func TestInitSubmodules_WithSubmodules(t *testing.T) {
 	}
 }
 
+func TestWorktreeCheckoutInitsSubmodules(t *testing.T) {
+	parent, _ := initTestRepoWithSubmodule(t)
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	g := NewGit(parent)
+	path := filepath.Join(t.TempDir(), "wt")
+	if err := g.WorktreeAddNoCheckout(path, "feature", "HEAD"); err != nil {
+		t.Fatalf("WorktreeAddNoCheckout: %v", err)
+	}
+	if err := g.WorktreeCheckout(path, "HEAD"); err != nil {
+		t.Fatalf("WorktreeCheckout: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "libs", "sub", "lib.go")); err != nil {
+		t.Fatalf("expected submodule file after WorktreeCheckout: %v", err)
+	}
+}
+
 func TestSubmoduleChanges(t *testing.T) {
 	parent, subRemote := initTestRepoWithSubmodule(t)
 
@@ -0,0 +1,246 @@
+// Package graph renders bead dependency graphs (molecule DAGs, convoy
+// waves) as Graphviz DOT, Mermaid, or standalone SVG.
+//
+// SVG is laid out in-process with a simple layered layout, so no Graphviz
+// or Mermaid binary is needed to produce an image. Nodes are colored by
+// bead status in every format.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Format names an output format.
+type Format string
+
+// Supported output formats.
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatSVG     Format = "svg"
+)
+
+// Formats lists the supported formats in display order.
+var Formats = []Format{FormatDOT, FormatMermaid, FormatSVG}
+
+// ParseFormat validates a user-supplied format name.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, known := range Formats {
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown graph format %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// ContentType returns the MIME type for a rendered format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatSVG:
+		return "image/svg+xml"
+	case FormatDOT:
+		return "text/vnd.graphviz; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Node is one bead in the graph.
+type Node struct {
+	ID     string
+	Label  string // title; may be empty
+	Status string // bead status: closed, in_progress, hooked, open, ready, blocked, ...
+	Rank   int    // layer index when Graph.Ranked is set (0 = first)
+}
+
+// Edge is an execution dependency: From must finish before To starts.
+type Edge struct {
+	From string
+	To   string
+}
+
+// Graph is a renderable dependency graph.
+type Graph struct {
+	Title string
+	Nodes []Node
+	Edges []Edge
+
+	// Ranked means Node.Rank is authoritative (e.g. convoy waves or
+	// molecule tiers). Otherwise ranks are derived from the edges.
+	Ranked bool
+	// RankNames optionally labels each rank ("Wave 1", "Tier 0", ...).
+	// Named ranks are drawn as clusters/lanes.
+	RankNames []string
+}
+
+// Render renders g in the given format.
+func Render(g *Graph, f Format) (string, error) {
+	switch f {
+	case FormatDOT:
+		return DOT(g), nil
+	case FormatMermaid:
+		return Mermaid(g), nil
+	case FormatSVG:
+		return SVG(g), nil
+	default:
+		return "", fmt.Errorf("unknown graph format %q", f)
+	}
+}
+
+// palette is the fill/stroke pair for a status.
+type palette struct {
+	class  string
+	fill   string
+	stroke string
+}
+
+var (
+	paletteDone     = palette{"done", "#d4edda", "#28a745"}
+	paletteActive   = palette{"active", "#fff3cd", "#d39e00"}
+	paletteReady    = palette{"ready", "#d1ecf1", "#17a2b8"}
+	paletteBlocked  = palette{"blocked", "#f8d7da", "#dc3545"}
+	paletteInactive = palette{"inactive", "#e2e3e5", "#6c757d"}
+)
+
+// legend lists palettes in the order they are explained to readers.
+var legend = []palette{paletteDone, paletteActive, paletteReady, paletteBlocked, paletteInactive}
+
+// statusPalette maps a bead status to its colors.
+func statusPalette(status string) palette {
+	switch status {
+	case "closed", "done", "tombstone":
+		return paletteDone
+	case "in_progress", "hooked", "working":
+		return paletteActive
+	case "open", "ready":
+		return paletteReady
+	case "blocked":
+		return paletteBlocked
+	default:
+		return paletteInactive
+	}
+}
+
+// layers groups node indexes by rank, in node order within each rank.
+// Unranked graphs get longest-path ranks from the edges; nodes caught in a
+// cycle land after everything else rather than being dropped.
+func (g *Graph) layers() [][]int {
+	ranks := make([]int, len(g.Nodes))
+	if g.Ranked {
+		for i, n := range g.Nodes {
+			ranks[i] = max(n.Rank, 0)
+		}
+	} else {
+		ranks = g.deriveRanks()
+	}
+
+	var out [][]int
+	for i, r := range ranks {
+		for len(out) <= r {
+			out = append(out, nil)
+		}
+		out[r] = append(out[r], i)
+	}
+	return out
+}
+
+// deriveRanks assigns each node the length of the longest chain of
+// predecessors leading to it (Kahn's algorithm).
+func (g *Graph) deriveRanks() []int {
+	index := g.index()
+	indeg := make([]int, len(g.Nodes))
+	succ := make([][]int, len(g.Nodes))
+	for _, e := range g.Edges {
+		from, okFrom := index[e.From]
+		to, okTo := index[e.To]
+		if !okFrom || !okTo || from == to {
+			continue
+		}
+		succ[from] = append(succ[from], to)
+		indeg[to]++
+	}
+
+	ranks := make([]int, len(g.Nodes))
+	placed := make([]bool, len(g.Nodes))
+	var queue []int
+	for i, d := range indeg {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+	maxRank := 0
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		placed[i] = true
+		maxRank = max(maxRank, ranks[i])
+		for _, j := range succ[i] {
+			ranks[j] = max(ranks[j], ranks[i]+1)
+			indeg[j]--
+			if indeg[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	for i := range ranks {
+		if !placed[i] {
+			ranks[i] = maxRank + 1
+		}
+	}
+	return ranks
+}
+
+// index maps node IDs to their position in g.Nodes.
+func (g *Graph) index() map[string]int {
+	index := make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+	return index
+}
+
+// sortedEdges returns edges between known nodes in a stable order.
+func (g *Graph) sortedEdges() []Edge {
+	index := g.index()
+	var edges []Edge
+	for _, e := range g.Edges {
+		if _, ok := index[e.From]; !ok {
+			continue
+		}
+		if _, ok := index[e.To]; !ok {
+			continue
+		}
+		edges = append(edges, e)
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// rankName returns the label for rank r, or "" when ranks are unnamed.
+func (g *Graph) rankName(r int) string {
+	if r < len(g.RankNames) {
+		return g.RankNames[r]
+	}
+	return ""
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
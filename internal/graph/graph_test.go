@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleGraph() *Graph {
+	return &Graph{
+		Title: `Epic "alpha"`,
+		Nodes: []Node{
+			{ID: "gt-a", Label: "Schema", Status: "closed"},
+			{ID: "gt-b", Label: "API <v2>", Status: "in_progress"},
+			{ID: "gt-c", Label: "UI", Status: "blocked"},
+			{ID: "gt-d", Status: "open"},
+		},
+		Edges: []Edge{
+			{From: "gt-a", To: "gt-b"},
+			{From: "gt-b", To: "gt-c"},
+			{From: "gt-a", To: "gt-missing"},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, in := range []string{"dot", "Mermaid", " svg "} {
+		if _, err := ParseFormat(in); err != nil {
+			t.Errorf("ParseFormat(%q): %v", in, err)
+		}
+	}
+	if _, err := ParseFormat("png"); err == nil {
+		t.Error("ParseFormat(png) should fail")
+	}
+	if FormatSVG.ContentType() != "image/svg+xml" {
+		t.Errorf("svg content type = %q", FormatSVG.ContentType())
+	}
+}
+
+func TestDeriveRanks(t *testing.T) {
+	g := sampleGraph()
+	got := g.layers()
+	want := [][]int{{0, 3}, {1}, {2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("layers = %v, want %v", got, want)
+	}
+
+	cyclic := &Graph{
+		Nodes: []Node{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Edges: []Edge{{From: "a", To: "b"}, {From: "b", To: "a"}},
+	}
+	if got := cyclic.layers(); len(got) != 2 || len(got[0]) != 1 || len(got[1]) != 2 {
+		t.Errorf("cyclic layers = %v, want c first then a,b", got)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	g := sampleGraph()
+	g.Ranked = true
+	g.Nodes[1].Rank = 1
+	g.Nodes[2].Rank = 2
+	g.RankNames = []string{"Wave 1", "Wave 2", "Wave 3"}
+
+	out := DOT(g)
+	for _, want := range []string{
+		"digraph G {",
+		`label="Epic \"alpha\""`,
+		`subgraph cluster_0 {`,
+		`label="Wave 2"`,
+		`"gt-a" [label="gt-a\nSchema", fillcolor="#d4edda"`,
+		`"gt-c" [label="gt-c\nUI", fillcolor="#f8d7da"`,
+		`"gt-a" -> "gt-b";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "gt-missing") {
+		t.Error("DOT should drop edges to unknown nodes")
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	out := Mermaid(sampleGraph())
+	for _, want := range []string{
+		"title: Epic #quot;alpha#quot;",
+		"flowchart LR",
+		`n0["gt-a<br/>Schema"]:::done`,
+		`n1["gt-b<br/>API <v2>"]:::active`,
+		"n0 --> n1",
+		"classDef blocked fill:#f8d7da,stroke:#dc3545",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSVGIsWellFormed(t *testing.T) {
+	g := sampleGraph()
+	g.RankNames = []string{"Tier 0", "Tier 1", "Tier 2"}
+	out := SVG(g)
+
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		_, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatalf("SVG is not well-formed XML: %v\n%s", err, out)
+		}
+	}
+	for _, want := range []string{"<svg ", "API &lt;v2&gt;", `fill="#fff3cd"`, "Tier 1", "marker-end"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG missing %q", want)
+		}
+	}
+}
+
+func TestSVGEmptyGraph(t *testing.T) {
+	out, err := Render(&Graph{}, FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "<svg ") {
+		t.Errorf("empty graph SVG = %q", out)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("héllo world", 6); got != "héllo…" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate = %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// SVG layout metrics, in pixels.
+const (
+	svgNodeWidth  = 200
+	svgNodeHeight = 44
+	svgColGap     = 70
+	svgRowGap     = 16
+	svgMargin     = 20
+	svgTitleH     = 28
+	svgLaneH      = 22
+	svgLegendH    = 30
+	svgMaxLabel   = 30
+)
+
+// svgPos is a node's top-left corner.
+type svgPos struct{ x, y int }
+
+// SVG renders g as a standalone SVG document. Ranks become columns, read
+// left to right; within a column nodes are ordered by the average row of
+// their predecessors to keep edges short and crossings rare.
+func SVG(g *Graph) string {
+	layers := g.layers()
+	index := g.index()
+	preds := make([][]int, len(g.Nodes))
+	for _, e := range g.sortedEdges() {
+		preds[index[e.To]] = append(preds[index[e.To]], index[e.From])
+	}
+
+	top := svgMargin
+	if g.Title != "" {
+		top += svgTitleH
+	}
+	if len(g.RankNames) > 0 {
+		top += svgLaneH
+	}
+
+	pos := make([]svgPos, len(g.Nodes))
+	row := make([]float64, len(g.Nodes))
+	tallest := 0
+	for c, layer := range layers {
+		order := append([]int(nil), layer...)
+		if c > 0 {
+			sort.SliceStable(order, func(a, b int) bool {
+				return barycenter(preds[order[a]], row) < barycenter(preds[order[b]], row)
+			})
+		}
+		for r, i := range order {
+			row[i] = float64(r)
+			pos[i] = svgPos{
+				x: svgMargin + c*(svgNodeWidth+svgColGap),
+				y: top + r*(svgNodeHeight+svgRowGap),
+			}
+		}
+		tallest = max(tallest, len(order))
+	}
+
+	width := svgMargin*2 + max(len(layers), 1)*(svgNodeWidth+svgColGap) - svgColGap
+	height := top + max(tallest, 1)*(svgNodeHeight+svgRowGap) - svgRowGap + svgLegendH + svgMargin
+	width = max(width, svgMargin*2+len(legend)*110)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="11">`+"\n", width, height, width, height)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="7" markerHeight="7" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#6c757d"/></marker></defs>` + "\n")
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	y := svgMargin
+	if g.Title != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="15" font-weight="bold" fill="#212529">%s</text>`+"\n", svgMargin, y+16, html.EscapeString(g.Title))
+		y += svgTitleH
+	}
+	if len(g.RankNames) > 0 {
+		for c := range layers {
+			if name := g.rankName(c); name != "" {
+				x := svgMargin + c*(svgNodeWidth+svgColGap)
+				fmt.Fprintf(&b, `<text x="%d" y="%d" font-weight="bold" fill="#6c757d">%s</text>`+"\n", x, y+12, html.EscapeString(name))
+			}
+		}
+	}
+
+	for _, e := range g.sortedEdges() {
+		from, to := pos[index[e.From]], pos[index[e.To]]
+		x1, y1 := from.x+svgNodeWidth, from.y+svgNodeHeight/2
+		x2, y2 := to.x, to.y+svgNodeHeight/2
+		mid := (x1 + x2) / 2
+		fmt.Fprintf(&b, `<path d="M%d,%d C%d,%d %d,%d %d,%d" fill="none" stroke="#6c757d" stroke-width="1.2" marker-end="url(#arrow)"/>`+"\n",
+			x1, y1, mid, y1, mid, y2, x2, y2)
+	}
+
+	for i, n := range g.Nodes {
+		p := statusPalette(n.Status)
+		at := pos[i]
+		fmt.Fprintf(&b, `<g><title>%s</title>`, html.EscapeString(n.ID+" ["+n.Status+"] "+n.Label))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="%s" stroke="%s" stroke-width="1.5"/>`,
+			at.x, at.y, svgNodeWidth, svgNodeHeight, p.fill, p.stroke)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-weight="bold" fill="#212529">%s</text>`,
+			at.x+8, at.y+17, html.EscapeString(truncate(n.ID, svgMaxLabel)))
+		if n.Label != "" {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#495057">%s</text>`,
+				at.x+8, at.y+33, html.EscapeString(truncate(n.Label, svgMaxLabel)))
+		}
+		b.WriteString("</g>\n")
+	}
+
+	ly := height - svgMargin - 10
+	for i, p := range legend {
+		x := svgMargin + i*110
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" rx="2" fill="%s" stroke="%s"/><text x="%d" y="%d" fill="#495057">%s</text>`+"\n",
+			x, ly-10, p.fill, p.stroke, x+17, ly, p.class)
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// barycenter is the mean row of a node's predecessors, or -1 for sources so
+// they stay at the top.
+func barycenter(preds []int, row []float64) float64 {
+	if len(preds) == 0 {
+		return -1
+	}
+	var sum float64
+	for _, p := range preds {
+		sum += row[p]
+	}
+	return sum / float64(len(preds))
+}
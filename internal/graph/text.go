@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxTextLabel caps title length in DOT and Mermaid labels.
+const maxTextLabel = 48
+
+// DOT renders g as a Graphviz digraph. Named ranks become clusters.
+func DOT(g *Graph) string {
+	var b strings.Builder
+	b.WriteString("digraph G {\n")
+	b.WriteString("  rankdir=LR;\n")
+	if g.Title != "" {
+		fmt.Fprintf(&b, "  label=%s;\n  labelloc=t;\n", dotQuote(g.Title))
+	}
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [color=\"#6c757d\"];\n")
+
+	for r, layer := range g.layers() {
+		indent := "  "
+		name := g.rankName(r)
+		if name != "" {
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=%s;\n    style=dashed;\n    color=\"#adb5bd\";\n", r, dotQuote(name))
+			indent = "    "
+		}
+		for _, i := range layer {
+			n := g.Nodes[i]
+			p := statusPalette(n.Status)
+			fmt.Fprintf(&b, "%s%s [label=%s, fillcolor=%q, color=%q];\n",
+				indent, dotQuote(n.ID), dotQuote(nodeText(n, "\n")), p.fill, p.stroke)
+		}
+		if name != "" {
+			b.WriteString("  }\n")
+		}
+	}
+
+	for _, e := range g.sortedEdges() {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders g as a Mermaid flowchart. Bead IDs are mapped to
+// synthetic node IDs because Mermaid reserves some punctuation in IDs.
+func Mermaid(g *Graph) string {
+	var b strings.Builder
+	if g.Title != "" {
+		fmt.Fprintf(&b, "---\ntitle: %s\n---\n", mermaidEscape(g.Title))
+	}
+	b.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	for r, layer := range g.layers() {
+		indent := "  "
+		name := g.rankName(r)
+		if name != "" {
+			fmt.Fprintf(&b, "  subgraph r%d[\"%s\"]\n", r, mermaidEscape(name))
+			indent = "    "
+		}
+		for _, i := range layer {
+			n := g.Nodes[i]
+			fmt.Fprintf(&b, "%s%s[\"%s\"]:::%s\n", indent, ids[n.ID], mermaidEscape(nodeText(n, "<br/>")), statusPalette(n.Status).class)
+		}
+		if name != "" {
+			b.WriteString("  end\n")
+		}
+	}
+
+	for _, e := range g.sortedEdges() {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+	for _, p := range legend {
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", p.class, p.fill, p.stroke)
+	}
+	return b.String()
+}
+
+// nodeText is the two-line ID + title label used by the text formats.
+func nodeText(n Node, sep string) string {
+	if n.Label == "" {
+		return n.ID
+	}
+	return n.ID + sep + truncate(n.Label, maxTextLabel)
+}
+
+// dotQuote quotes s as a DOT string, escaping quotes and backslashes.
+// Newlines become DOT's centered line break.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidEscape replaces characters that would end a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
@@ -0,0 +1,61 @@
+package guard
+
+// DefaultRules are the built-in rules, checked after any policy file. They
+// block operations that can cause irreversible damage or change the host.
+func DefaultRules() []Rule {
+	const pkgReason = "use workspace tools instead"
+	return []Rule{
+		{ID: "sudo", Action: Deny, Commands: []string{"sudo", "doas"},
+			Reason: "Agents must never use sudo — do not elevate privileges or modify the host OS"},
+
+		{ID: "apt-install", Action: Deny, Commands: []string{"apt", "apt-get"}, Args: []string{"install"},
+			Reason: "System package install (apt) — " + pkgReason},
+		{ID: "dnf-install", Action: Deny, Commands: []string{"dnf", "yum"}, Args: []string{"install"},
+			Reason: "System package install (dnf/yum) — " + pkgReason},
+		{ID: "pacman-install", Action: Deny, Commands: []string{"pacman"}, Args: []string{"-s*"},
+			Reason: "System package install (pacman) — " + pkgReason},
+		{ID: "brew-install", Action: Deny, Commands: []string{"brew"}, Args: []string{"install"},
+			Reason: "Package install (brew) — " + pkgReason},
+		{ID: "gem-install", Action: Deny, Commands: []string{"gem"}, Args: []string{"install"},
+			Reason: "System gem install — " + pkgReason},
+		{ID: "pip-system", Action: Deny, Commands: []string{"pip", "pip3"}, Args: []string{"install", "--system"},
+			Reason: "System-level pip install — use a virtualenv or workspace tools instead"},
+		{ID: "npm-global", Action: Deny, Commands: []string{"npm"}, Args: []string{"install", "-g"},
+			Reason: "Global npm install — " + pkgReason},
+		{ID: "npm-global-long", Action: Deny, Commands: []string{"npm"}, Args: []string{"install", "--global"},
+			Reason: "Global npm install — " + pkgReason},
+
+		{ID: "rm-root", Action: Deny, Commands: []string{"rm"}, Args: []string{rmRecursive, rmForce, "/"},
+			Reason: "filesystem destruction (rm -rf /)"},
+		{ID: "rm-root-glob", Action: Deny, Commands: []string{"rm"}, Args: []string{rmRecursive, rmForce, `/\*`},
+			Reason: "filesystem destruction (rm -rf /*)"},
+
+		{ID: "git-push-force", Action: Deny, Commands: []string{"git"}, Args: []string{"push", "--force"},
+			Reason: "Force push rewrites remote history and can destroy others' work"},
+		{ID: "git-push-f", Action: Deny, Commands: []string{"git"}, Args: []string{"push", "-f"},
+			Reason: "Force push rewrites remote history and can destroy others' work"},
+		{ID: "git-reset-hard", Action: Deny, Commands: []string{"git"}, Args: []string{"reset", "--hard"},
+			Reason: "Hard reset discards all uncommitted changes irreversibly"},
+		{ID: "git-clean-force", Action: Deny, Commands: []string{"git"}, Args: []string{"clean", "-*f*"},
+			Reason: "git clean -f deletes untracked files irreversibly"},
+
+		{ID: "sql-drop-table", Action: Deny, Commands: sqlClients, Contains: []string{"drop table"},
+			Reason: "database table destruction"},
+		{ID: "sql-drop-database", Action: Deny, Commands: sqlClients, Contains: []string{"drop database"},
+			Reason: "database destruction"},
+		{ID: "sql-truncate", Action: Deny, Commands: sqlClients, Contains: []string{"truncate table"},
+			Reason: "database table truncation"},
+	}
+}
+
+// rm's recursive and force flags, as a short-flag cluster ("-r", "-rf",
+// "-vfR") or the long option. Short clusters start with a letter so that
+// "--force" is not also taken for a cluster containing "r".
+const (
+	rmRecursive = "-r*|-[a-z]*r*|--recursive"
+	rmForce     = "-f*|-[a-z]*f*|--force"
+)
+
+// sqlClients are the programs SQL rules apply to. SQL words in any other
+// command (an echo, a commit message) are just text.
+var sqlClients = []string{"psql", "mysql", "mariadb", "sqlite3", "dolt", "bd", "duckdb", "clickhouse-client"}
@@ -0,0 +1,104 @@
+package guard
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// BuiltinSource is the Source of verdicts from the built-in rules.
+const BuiltinSource = "builtin"
+
+// Layer is the rules from one source.
+type Layer struct {
+	Source string // the policy file, or BuiltinSource
+	Rules  []Rule
+}
+
+// Evaluator checks commands against layers of rules, first layer first.
+type Evaluator struct {
+	Layers []Layer
+}
+
+// NewEvaluator loads the rig policy (when rigPath is set), then the town
+// policy (when townRoot is set), then the built-in rules. A policy file
+// that exists but is invalid is an error.
+func NewEvaluator(townRoot, rigPath string) (*Evaluator, error) {
+	e := &Evaluator{}
+	for _, dir := range []string{rigPath, townRoot} {
+		if dir == "" {
+			continue
+		}
+		p := PolicyPath(dir)
+		pol, err := LoadPolicy(p)
+		if err != nil {
+			return nil, err
+		}
+		if pol != nil && len(pol.Rules) > 0 {
+			e.Layers = append(e.Layers, Layer{Source: filepath.Clean(p), Rules: pol.Rules})
+		}
+	}
+	e.Layers = append(e.Layers, Layer{Source: BuiltinSource, Rules: DefaultRules()})
+	return e, nil
+}
+
+// Verdict is the decision for one command.
+type Verdict struct {
+	Command Command `json:"command"`
+	Action  Action  `json:"action"`
+	// Rule and Source identify the deciding rule; both are empty when no
+	// rule matched and the command is allowed by default.
+	Rule   string `json:"rule,omitempty"`
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Decision is the outcome for a whole command line.
+type Decision struct {
+	Allowed bool `json:"allowed"`
+	// Reason is why the command line was blocked: the first denying
+	// rule's reason, or the parse error.
+	Reason   string    `json:"reason,omitempty"`
+	Verdicts []Verdict `json:"verdicts,omitempty"`
+}
+
+// Evaluate decides whether role may run script. Each simple command is
+// decided by the first rule that applies to the role and matches it; a
+// command no rule matches is allowed, unless it runs a script that cannot
+// be read. The script is blocked if any command is denied or it does not
+// parse.
+func (e *Evaluator) Evaluate(script, role string) Decision {
+	cmds, err := Parse(script)
+	if err != nil {
+		return Decision{Reason: fmt.Sprintf("could not check command (%v)", err)}
+	}
+	d := Decision{Allowed: true}
+	for _, c := range cmds {
+		v := e.decide(c, role)
+		if v.Action == Deny && d.Allowed {
+			d.Allowed = false
+			d.Reason = v.Reason
+		}
+		d.Verdicts = append(d.Verdicts, v)
+	}
+	return d
+}
+
+func (e *Evaluator) decide(c Command, role string) Verdict {
+	for _, layer := range e.Layers {
+		for i, r := range layer.Rules {
+			if !r.appliesTo(role) || !r.matches(c) {
+				continue
+			}
+			reason := r.Reason
+			if reason == "" && r.Action == Deny {
+				reason = fmt.Sprintf("denied by guard rule %s", r.name(i))
+			}
+			return Verdict{Command: c, Action: r.Action, Rule: r.name(i), Source: layer.Source, Reason: reason}
+		}
+	}
+	if c.Opaque {
+		return Verdict{Command: c, Action: Deny, Rule: "opaque", Source: BuiltinSource,
+			Reason: "runs a script that can't be checked before it runs"}
+	}
+	return Verdict{Command: c, Action: Allow}
+}
@@ -0,0 +1,200 @@
+// Package guard decides whether a shell command an agent is about to run
+// is allowed. Commands are parsed with a real shell parser and every simple
+// command in them (pipelines, subshells, command substitution, bash -c
+// strings, xargs and find -exec targets, ...) is checked against an ordered
+// list of allow/deny rules from the rig and town guard policies, falling
+// back to built-in defaults.
+package guard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PolicyFile is the guard policy file name under a town's or rig's
+// settings directory.
+const PolicyFile = "guard-policy.json"
+
+// CurrentPolicyVersion is the current schema version for Policy.
+const CurrentPolicyVersion = 1
+
+// Action is what a matching rule does with a command.
+type Action string
+
+const (
+	Allow Action = "allow"
+	Deny  Action = "deny"
+)
+
+// Policy is an ordered list of rules (settings/guard-policy.json).
+type Policy struct {
+	Type    string `json:"type"`    // "guard-policy"
+	Version int    `json:"version"` // schema version
+
+	// Rules are checked in order; the first rule matching a command decides
+	// it. Rig rules are checked before town rules, and both before the
+	// built-in defaults, so a rig can allow what the town denies.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule allows or denies the simple commands it matches.
+//
+// Patterns are shell globs (path.Match) compared case-insensitively.
+// A command matches when its program name matches one of Commands, every
+// pattern in Args matches at least one argument, and every string in
+// Contains appears in the arguments joined by spaces. An Args pattern may
+// list alternatives separated by "|" ("-r*|--recursive"); it matches an
+// argument when any alternative does.
+type Rule struct {
+	// ID names the rule in explanations. Defaults to its position.
+	ID string `json:"id,omitempty"`
+
+	Action Action `json:"action"`
+
+	// Roles limits the rule to these roles (polecat, crew, witness,
+	// refinery, mayor, deacon, dog). Empty means every role.
+	Roles []string `json:"roles,omitempty"`
+
+	// Commands are program names, matched against the base name of the
+	// command word. Empty or "*" matches any program.
+	Commands []string `json:"commands,omitempty"`
+
+	Args     []string `json:"args,omitempty"`
+	Contains []string `json:"contains,omitempty"`
+
+	// Reason is shown when the rule blocks a command.
+	Reason string `json:"reason,omitempty"`
+}
+
+// PolicyPath returns the guard policy path for a town or rig directory.
+func PolicyPath(dir string) string {
+	return filepath.Join(dir, "settings", PolicyFile)
+}
+
+// LoadPolicy reads and validates a policy file. A missing file is not an
+// error: it returns nil, nil.
+func LoadPolicy(p string) (*Policy, error) {
+	data, err := os.ReadFile(p) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading guard policy: %w", err)
+	}
+	var pol Policy
+	if err := json.Unmarshal(data, &pol); err != nil {
+		return nil, fmt.Errorf("parsing guard policy %s: %w", p, err)
+	}
+	if err := pol.Validate(); err != nil {
+		return nil, fmt.Errorf("guard policy %s: %w", p, err)
+	}
+	return &pol, nil
+}
+
+// Validate checks the policy's rules.
+func (p *Policy) Validate() error {
+	if p.Type != "" && p.Type != "guard-policy" {
+		return fmt.Errorf("expected type 'guard-policy', got %q", p.Type)
+	}
+	if p.Version > CurrentPolicyVersion {
+		return fmt.Errorf("version %d is newer than supported (%d)", p.Version, CurrentPolicyVersion)
+	}
+	for i, r := range p.Rules {
+		if r.Action != Allow && r.Action != Deny {
+			return fmt.Errorf("rule %s: action must be %q or %q", r.name(i), Allow, Deny)
+		}
+		pats := append([]string{}, r.Commands...)
+		for _, arg := range r.Args {
+			pats = append(pats, strings.Split(arg, "|")...)
+		}
+		for _, pat := range pats {
+			if _, err := path.Match(strings.ToLower(pat), ""); err != nil {
+				return fmt.Errorf("rule %s: bad pattern %q", r.name(i), pat)
+			}
+		}
+	}
+	return nil
+}
+
+// name returns the rule's ID, or its 1-based position.
+func (r Rule) name(i int) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// appliesTo reports whether the rule is in force for role.
+func (r Rule) appliesTo(role string) bool {
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, want := range r.Roles {
+		if want == "*" || strings.EqualFold(want, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the rule matches a command. When the program
+// name could not be resolved statically, the Commands check is skipped:
+// a command that looks dangerous by its arguments is judged as if it were
+// any of the programs the rule names.
+func (r Rule) matches(c Command) bool {
+	if c.Name == "" {
+		if len(r.Args) == 0 && len(r.Contains) == 0 {
+			return false
+		}
+	} else if !matchesCommand(r.Commands, c.Name) {
+		return false
+	}
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = strings.ToLower(a)
+	}
+	for _, pat := range r.Args {
+		if !matchesAnyArg(strings.Split(strings.ToLower(pat), "|"), args) {
+			return false
+		}
+	}
+	if len(r.Contains) > 0 {
+		joined := strings.Join(args, " ")
+		for _, s := range r.Contains {
+			if !strings.Contains(joined, strings.ToLower(s)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesAnyArg reports whether any of the alternative patterns matches
+// any of the (lowercased) arguments.
+func matchesAnyArg(alternatives, args []string) bool {
+	for _, a := range args {
+		for _, pat := range alternatives {
+			if ok, _ := path.Match(pat, a); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesCommand(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	name = strings.ToLower(path.Base(name))
+	for _, pat := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pat), name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,435 @@
+package guard
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// maxDepth bounds how deeply bash -c, eval and friends are unwrapped.
+const maxDepth = 8
+
+// Command is one simple command found in a shell script.
+type Command struct {
+	// Name is the program as written (possibly a path). Empty when it is
+	// computed at run time, e.g. "$(pick-tool) args".
+	Name string   `json:"name,omitempty"`
+	Args []string `json:"args,omitempty"`
+
+	// Via lists the wrappers the command was reached through, outermost
+	// first: "bash -c", "eval", "xargs", "find -exec", "sudo", ...
+	Via []string `json:"via,omitempty"`
+
+	// Opaque is set when the command runs a script that cannot be read
+	// statically, such as eval "$CMD" or bash -c "$(curl ...)".
+	Opaque bool `json:"opaque,omitempty"`
+}
+
+// String renders the command for display.
+func (c Command) String() string {
+	name := c.Name
+	if name == "" {
+		name = "<dynamic>"
+	}
+	parts := []string{name}
+	for _, a := range c.Args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"") {
+			if q, err := syntax.Quote(a, syntax.LangBash); err == nil {
+				a = q
+			}
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parse returns every simple command in script, including those inside
+// pipelines, lists, subshells, command and process substitution, control
+// flow, function bodies, and the scripts run by wrappers like bash -c,
+// eval, xargs, find -exec, env, sudo and timeout.
+//
+// Variables assigned literal values earlier in the script are substituted
+// so "X=rm; $X -rf /" is seen as rm. A script that does not parse is an
+// error; callers should treat it as blocked.
+func Parse(script string) ([]Command, error) {
+	x := &extractor{vars: make(map[string]string)}
+	if err := x.script(script, nil, 0); err != nil {
+		return nil, err
+	}
+	return x.cmds, nil
+}
+
+type extractor struct {
+	vars map[string]string
+	cmds []Command
+}
+
+// word is a resolved shell word. Static words have a known value; others
+// keep their source text.
+type word struct {
+	text   string
+	static bool
+}
+
+func (x *extractor) script(src string, via []string, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("commands nested more than %d levels deep", maxDepth)
+	}
+	f, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(src), "")
+	if err != nil {
+		return fmt.Errorf("parsing command: %w", err)
+	}
+
+	// Statements reading a pipe; a shell reading one runs unseen code.
+	piped := make(map[*syntax.Stmt]bool)
+	var walkErr error
+	syntax.Walk(f, func(node syntax.Node) bool {
+		if walkErr != nil {
+			return false
+		}
+		switch n := node.(type) {
+		case *syntax.Stmt:
+			if call, ok := n.Cmd.(*syntax.CallExpr); ok && len(call.Args) > 0 {
+				argv := make([]word, len(call.Args))
+				for i, w := range call.Args {
+					argv[i] = x.word(w)
+				}
+				walkErr = x.command(argv, x.stdinScript(n.Redirs), piped[n], via, depth)
+			}
+		case *syntax.BinaryCmd:
+			if n.Op == syntax.Pipe || n.Op == syntax.PipeAll {
+				piped[n.Y] = true
+			}
+		case *syntax.CallExpr:
+			if len(n.Args) == 0 {
+				x.assign(n.Assigns)
+			}
+		case *syntax.DeclClause:
+			x.assign(n.Args)
+		}
+		return true
+	})
+	return walkErr
+}
+
+// assign records literal variable assignments and forgets variables
+// assigned anything else.
+func (x *extractor) assign(assigns []*syntax.Assign) {
+	for _, a := range assigns {
+		if a.Name == nil || a.Naked {
+			continue
+		}
+		if a.Value == nil || a.Append || a.Index != nil || a.Array != nil {
+			delete(x.vars, a.Name.Value)
+			continue
+		}
+		if w := x.word(a.Value); w.static {
+			x.vars[a.Name.Value] = w.text
+		} else {
+			delete(x.vars, a.Name.Value)
+		}
+	}
+}
+
+// stdinScript returns a here-document or here-string fed to the command,
+// which a shell would run as its script.
+func (x *extractor) stdinScript(redirs []*syntax.Redirect) *word {
+	for _, r := range redirs {
+		switch r.Op {
+		case syntax.Hdoc, syntax.DashHdoc:
+			if r.Hdoc != nil {
+				w := x.word(r.Hdoc)
+				return &w
+			}
+		case syntax.WordHdoc:
+			w := x.word(r.Word)
+			return &w
+		}
+	}
+	return nil
+}
+
+// command records a simple command and unwraps any command it runs.
+func (x *extractor) command(argv []word, stdin *word, piped bool, via []string, depth int) error {
+	c := Command{Via: via}
+	if argv[0].static {
+		c.Name = argv[0].text
+	}
+	for _, a := range argv[1:] {
+		c.Args = append(c.Args, a.text)
+	}
+	prog := path.Base(c.Name)
+
+	inner := func(how string) []string {
+		return append(append([]string{}, via...), how)
+	}
+
+	switch {
+	case shells[prog]:
+		script, fromStdin := shellScript(argv[1:])
+		if script == nil && fromStdin {
+			script = stdin
+			if script == nil && piped {
+				// curl ... | sh
+				script = &word{}
+			}
+		}
+		if script == nil {
+			break
+		}
+		if !script.static {
+			c.Opaque = true
+			break
+		}
+		x.cmds = append(x.cmds, c)
+		return x.script(script.text, inner(prog+" -c"), depth+1)
+
+	case prog == "eval":
+		joined, static := joinWords(argv[1:])
+		if !static {
+			c.Opaque = true
+			break
+		}
+		x.cmds = append(x.cmds, c)
+		return x.script(joined, inner("eval"), depth+1)
+
+	case prog == "watch":
+		rest := skipFlags(argv[1:], "nqx")
+		if len(rest) == 0 {
+			break
+		}
+		joined, static := joinWords(rest)
+		if !static {
+			c.Opaque = true
+			break
+		}
+		x.cmds = append(x.cmds, c)
+		return x.script(joined, inner("watch"), depth+1)
+
+	case prog == "find":
+		x.cmds = append(x.cmds, c)
+		for i := 1; i < len(argv); i++ {
+			switch argv[i].text {
+			case "-exec", "-execdir", "-ok", "-okdir":
+				how := "find " + argv[i].text
+				j := i + 1
+				for j < len(argv) && argv[j].text != ";" && argv[j].text != "+" {
+					j++
+				}
+				if j > i+1 {
+					if err := x.command(argv[i+1:j], nil, false, inner(how), depth+1); err != nil {
+						return err
+					}
+				}
+				i = j
+			}
+		}
+		return nil
+
+	case prog == "alias":
+		x.cmds = append(x.cmds, c)
+		for _, a := range argv[1:] {
+			if _, body, ok := strings.Cut(a.text, "="); ok && a.static {
+				if err := x.script(body, inner("alias"), depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	default:
+		if w, ok := wrappers[prog]; ok {
+			rest := skipFlags(argv[1:], w.valueFlags)
+			if w.assigns {
+				for len(rest) > 0 && rest[0].static && isAssignment(rest[0].text) {
+					rest = rest[1:]
+				}
+			}
+			if len(rest) > w.skip {
+				x.cmds = append(x.cmds, c)
+				return x.command(rest[w.skip:], stdin, piped, inner(prog), depth+1)
+			}
+		}
+	}
+
+	x.cmds = append(x.cmds, c)
+	return nil
+}
+
+// shells run their -c argument (or stdin) as a script.
+var shells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "mksh": true, "ash": true,
+}
+
+// wrapper describes a command that runs its arguments as another command.
+type wrapper struct {
+	valueFlags string // short flags that take a separate value
+	skip       int    // positional arguments before the command
+	assigns    bool   // NAME=value arguments before the command
+}
+
+var wrappers = map[string]wrapper{
+	"env":     {valueFlags: "uCS", assigns: true},
+	"nice":    {valueFlags: "n"},
+	"nohup":   {},
+	"setsid":  {},
+	"time":    {},
+	"command": {},
+	"builtin": {},
+	"exec":    {valueFlags: "a"},
+	"timeout": {valueFlags: "sk", skip: 1},
+	"stdbuf":  {valueFlags: "ioe"},
+	"ionice":  {valueFlags: "cnp"},
+	"sudo":    {valueFlags: "ugCDhprtU"},
+	"doas":    {valueFlags: "uC"},
+	"xargs":   {valueFlags: "nIPLdEsa"},
+}
+
+// shellScript finds the script a shell is asked to run with -c. script is
+// nil when there is no -c; it is non-static when -c has no argument.
+// fromStdin reports whether the shell reads its script from stdin (no -c
+// and no script file).
+func shellScript(args []word) (script *word, fromStdin bool) {
+	for i := 0; i < len(args); i++ {
+		t := args[i].text
+		switch {
+		case t == "--":
+			return nil, i+1 == len(args)
+		case t == "-o" || t == "+o" || t == "-O" || t == "+O":
+			i++ // option name
+		case strings.HasPrefix(t, "--"):
+			// long options (--norc, --login) take no value
+		case len(t) > 1 && (t[0] == '-' || t[0] == '+'):
+			if t[0] == '-' && strings.Contains(t[1:], "c") {
+				// The script is the first argument after the options.
+				for _, b := range args[i+1:] {
+					if len(b.text) > 1 && b.text[0] == '-' {
+						continue
+					}
+					return &b, false
+				}
+				return &word{}, false
+			}
+		default:
+			return nil, false // script file
+		}
+	}
+	return nil, true
+}
+
+// skipFlags drops leading options. Short options listed in valueFlags
+// consume the following argument unless the value is attached.
+func skipFlags(args []word, valueFlags string) []word {
+	for len(args) > 0 {
+		a := args[0].text
+		if a == "--" {
+			return args[1:]
+		}
+		if len(a) < 2 || a[0] != '-' {
+			return args
+		}
+		args = args[1:]
+		if len(a) == 2 && strings.IndexByte(valueFlags, a[1]) >= 0 && len(args) > 0 {
+			args = args[1:]
+		}
+	}
+	return args
+}
+
+func isAssignment(s string) bool {
+	name, _, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && (i == 0 || !(r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+func joinWords(ws []word) (string, bool) {
+	parts := make([]string, len(ws))
+	for i, w := range ws {
+		if !w.static {
+			return "", false
+		}
+		parts[i] = w.text
+	}
+	return strings.Join(parts, " "), true
+}
+
+// word resolves a shell word to its value when it is static: literals,
+// quoted strings, and variables assigned literal values earlier.
+func (x *extractor) word(w *syntax.Word) word {
+	var sb strings.Builder
+	if x.parts(&sb, w.Parts, false) {
+		return word{text: sb.String(), static: true}
+	}
+	var src bytes.Buffer
+	if err := syntax.NewPrinter().Print(&src, w); err != nil {
+		return word{}
+	}
+	return word{text: src.String()}
+}
+
+func (x *extractor) parts(sb *strings.Builder, parts []syntax.WordPart, quoted bool) bool {
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			sb.WriteString(unescape(p.Value, quoted))
+		case *syntax.SglQuoted:
+			if p.Dollar && strings.Contains(p.Value, `\`) {
+				return false
+			}
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			if !x.parts(sb, p.Parts, true) {
+				return false
+			}
+		case *syntax.ParamExp:
+			if p.Param == nil || p.Excl || p.Length || p.Width || p.IsSet || p.Index != nil ||
+				p.NestedParam != nil || p.Slice != nil || p.Repl != nil || p.Exp != nil || len(p.Modifiers) > 0 {
+				return false
+			}
+			v, ok := x.vars[p.Param.Value]
+			if !ok {
+				return false
+			}
+			sb.WriteString(v)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// unescape removes shell backslash escapes from a literal. Inside double
+// quotes a backslash only escapes $, `, ", \ and newline.
+func unescape(s string, quoted bool) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		next := s[i+1]
+		switch {
+		case next == '\n':
+			i++
+		case !quoted || strings.IndexByte("$`\"\\", next) >= 0:
+			sb.WriteByte(next)
+			i++
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,328 @@
+// Package handoff defines the structured handoff document an agent leaves
+// for its successor session.
+//
+// Free-form handoff mail loses the facts a successor needs most: which tests
+// were failing, what state the branch is in, what was decided and why. A Doc
+// names those facts explicitly. The workspace state part is filled in
+// automatically from the checkpoint and git; the agent supplies the goal,
+// progress, and reasoning.
+//
+// A Doc travels two ways: embedded in the handoff mail as a fenced JSON
+// block (see MailBody and Parse), and as a Record in the workspace's
+// .runtime directory, which gt prime renders and gt handoff compliance
+// audits.
+package handoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// SchemaVersion is the current Doc schema version.
+const SchemaVersion = 1
+
+// Filename is the handoff record file within the workspace runtime dir.
+const Filename = "handoff.json"
+
+// fenceTag marks the JSON block embedded in handoff mail.
+const fenceTag = "gt-handoff"
+
+// Doc is a structured handoff document.
+type Doc struct {
+	Version   int       `json:"version"`
+	Agent     string    `json:"agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Goal is what the session was trying to achieve.
+	Goal string `json:"goal"`
+
+	// Done lists what was completed.
+	Done []string `json:"done,omitempty"`
+
+	// Remaining lists what is left, most important first.
+	Remaining []string `json:"remaining,omitempty"`
+
+	// Blockers lists what is in the way, e.g. failing tests by name.
+	Blockers []string `json:"blockers,omitempty"`
+
+	// Decisions records choices made and why, so the successor does not
+	// relitigate them.
+	Decisions []string `json:"decisions,omitempty"`
+
+	// Commands are commands the successor should rerun to get back to
+	// where the session was (failing test invocations, repro steps).
+	Commands []string `json:"commands,omitempty"`
+
+	// FilesTouched lists files changed on the branch or in the worktree.
+	// Agent-supplied entries are merged with what git reports.
+	FilesTouched []string `json:"files_touched,omitempty"`
+
+	// State is the auto-filled workspace snapshot.
+	State State `json:"state"`
+}
+
+// State is the part of a Doc captured from the workspace, not the agent.
+type State struct {
+	Branch          string   `json:"branch,omitempty"`
+	LastCommit      string   `json:"last_commit,omitempty"`
+	UnpushedCommits int      `json:"unpushed_commits,omitempty"`
+	Uncommitted     []string `json:"uncommitted,omitempty"`
+	HookedBead      string   `json:"hooked_bead,omitempty"`
+	MoleculeID      string   `json:"molecule_id,omitempty"`
+	Step            string   `json:"step,omitempty"`
+}
+
+// Capture snapshots workDir's state and the files touched on its branch.
+// The session checkpoint, if one exists, supplies the hooked bead and
+// molecule step. Anything that cannot be read is left empty.
+func Capture(workDir string) (State, []string) {
+	var st State
+	if cp, err := checkpoint.Capture(workDir); err == nil {
+		st.Branch = cp.Branch
+		st.LastCommit = cp.LastCommit
+		st.Uncommitted = cp.ModifiedFiles
+	}
+	if cp, err := checkpoint.Read(workDir); err == nil && cp != nil {
+		st.HookedBead = cp.HookedBead
+		st.MoleculeID = cp.MoleculeID
+		st.Step = cp.StepTitle
+		if st.Step == "" {
+			st.Step = cp.CurrentStep
+		}
+	}
+
+	g := git.NewGit(workDir)
+	if !g.IsRepo() {
+		return st, st.Uncommitted
+	}
+	if n, err := g.UnpushedCommits(); err == nil {
+		st.UnpushedCommits = n
+	}
+	var files []string
+	if def := g.RemoteDefaultBranch(); st.Branch != "" && st.Branch != def {
+		files, _ = g.DiffNameOnly("origin/"+def, "HEAD")
+	}
+	return st, mergeUnique(files, st.Uncommitted)
+}
+
+// Fill sets the auto-captured fields of d from workDir.
+func (d *Doc) Fill(workDir string) {
+	st, files := Capture(workDir)
+	d.State = st
+	d.FilesTouched = mergeUnique(d.FilesTouched, files)
+	if d.Version == 0 {
+		d.Version = SchemaVersion
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+}
+
+// ValidationError lists everything wrong with a Doc.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "incomplete handoff: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that d tells a successor enough to continue: a goal, and
+// either what is left or, if nothing is, what was done. List entries must
+// not be blank. It returns a *ValidationError or nil.
+func (d *Doc) Validate() error {
+	var problems []string
+	if d.Version > SchemaVersion {
+		problems = append(problems, fmt.Sprintf("unknown schema version %d", d.Version))
+	}
+	if strings.TrimSpace(d.Goal) == "" {
+		problems = append(problems, "goal is required")
+	}
+	if len(d.Remaining) == 0 && len(d.Done) == 0 {
+		problems = append(problems, "remaining is required (or done, if the work is finished)")
+	}
+	for _, f := range []struct {
+		name  string
+		items []string
+	}{
+		{"done", d.Done}, {"remaining", d.Remaining}, {"blockers", d.Blockers},
+		{"decisions", d.Decisions}, {"commands", d.Commands}, {"files_touched", d.FilesTouched},
+	} {
+		for i, item := range f.items {
+			if strings.TrimSpace(item) == "" {
+				problems = append(problems, fmt.Sprintf("%s[%d] is empty", f.name, i))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// Problems returns the validation problems of d, or nil if it is valid.
+func (d *Doc) Problems() []string {
+	var ve *ValidationError
+	if errors.As(d.Validate(), &ve) {
+		return ve.Problems
+	}
+	return nil
+}
+
+// Markdown renders d for humans and agents.
+func (d *Doc) Markdown() string {
+	var b strings.Builder
+	if d.Goal != "" {
+		fmt.Fprintf(&b, "**Goal:** %s\n", d.Goal)
+	}
+	section := func(title string, items []string, code bool) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n**%s:**\n", title)
+		for _, item := range items {
+			if code {
+				fmt.Fprintf(&b, "- `%s`\n", item)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", item)
+			}
+		}
+	}
+	section("Done", d.Done, false)
+	section("Remaining", d.Remaining, false)
+	section("Blockers", d.Blockers, false)
+	section("Decisions", d.Decisions, false)
+	section("Rerun", d.Commands, true)
+	section("Files touched", d.FilesTouched, false)
+
+	var st []string
+	if d.State.Branch != "" {
+		branch := d.State.Branch
+		if d.State.LastCommit != "" {
+			branch += " @ " + shortSHA(d.State.LastCommit)
+		}
+		st = append(st, "branch "+branch)
+	}
+	if d.State.UnpushedCommits > 0 {
+		st = append(st, fmt.Sprintf("%d unpushed commit(s)", d.State.UnpushedCommits))
+	}
+	if n := len(d.State.Uncommitted); n > 0 {
+		st = append(st, fmt.Sprintf("%d uncommitted file(s)", n))
+	}
+	if d.State.HookedBead != "" {
+		st = append(st, "hooked "+d.State.HookedBead)
+	}
+	if d.State.Step != "" {
+		st = append(st, "step: "+d.State.Step)
+	}
+	if len(st) > 0 {
+		fmt.Fprintf(&b, "\n**Workspace:** %s\n", strings.Join(st, ", "))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// MailBody renders d as Markdown followed by the machine-readable block
+// Parse reads back.
+func (d *Doc) MailBody() string {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return d.Markdown()
+	}
+	return d.Markdown() + "\n\n```" + fenceTag + "\n" + string(data) + "\n```"
+}
+
+// Parse extracts the Doc embedded in a handoff mail body.
+// It returns nil, nil when the body has no structured block.
+func Parse(body string) (*Doc, error) {
+	_, rest, ok := strings.Cut(body, "```"+fenceTag+"\n")
+	if !ok {
+		return nil, nil
+	}
+	raw, _, ok := strings.Cut(rest, "\n```")
+	if !ok {
+		return nil, fmt.Errorf("unterminated %s block", fenceTag)
+	}
+	var d Doc
+	if err := json.Unmarshal([]byte(raw), &d); err != nil {
+		return nil, fmt.Errorf("parsing %s block: %w", fenceTag, err)
+	}
+	return &d, nil
+}
+
+// Record is a Doc as saved in the workspace, with its validation result.
+type Record struct {
+	Doc      Doc      `json:"doc"`
+	Valid    bool     `json:"valid"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Path returns the handoff record path for a workspace.
+func Path(workDir string) string {
+	return filepath.Join(workDir, constants.DirRuntime, Filename)
+}
+
+// Save validates d and writes it as the workspace's latest handoff record.
+func Save(workDir string, d *Doc) (*Record, error) {
+	rec := &Record{Doc: *d, Problems: d.Problems()}
+	rec.Valid = len(rec.Problems) == 0
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling handoff: %w", err)
+	}
+	path := Path(workDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: handoff notes are non-sensitive
+		return nil, fmt.Errorf("writing handoff: %w", err)
+	}
+	return rec, nil
+}
+
+// Load reads a workspace's latest handoff record.
+// It returns nil, nil if the workspace has none.
+func Load(workDir string) (*Record, error) {
+	data, err := os.ReadFile(Path(workDir)) //nolint:gosec // G304: path is inside the workspace runtime dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading handoff: %w", err)
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing handoff: %w", err)
+	}
+	return &rec, nil
+}
+
+func mergeUnique(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var out []string
+	for _, list := range [][]string{a, b} {
+		for _, s := range list {
+			if s == "" || seen[s] {
+				continue
+			}
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,143 @@
+package handoff
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleDoc() *Doc {
+	return &Doc{
+		Version:   SchemaVersion,
+		Agent:     "gastown/crew/dave",
+		CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Goal:      "Fix invoice rounding",
+		Done:      []string{"Reproduced the bug"},
+		Remaining: []string{"Patch money.Round"},
+		Blockers:  []string{"TestInvoiceTotal fails"},
+		Decisions: []string{"Round half-even to match the ledger"},
+		Commands:  []string{"go test ./billing -run TestInvoiceTotal"},
+		State:     State{Branch: "polecat/dave", LastCommit: "0123456789abcdef", UnpushedCommits: 2},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := sampleDoc().Validate(); err != nil {
+		t.Fatalf("valid doc: %v", err)
+	}
+
+	finished := &Doc{Goal: "Ship it", Done: []string{"Shipped"}}
+	if err := finished.Validate(); err != nil {
+		t.Errorf("finished doc with no remaining: %v", err)
+	}
+
+	bad := &Doc{Version: SchemaVersion + 1, Remaining: []string{"a", "  "}}
+	err := bad.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate = %v, want *ValidationError", err)
+	}
+	want := []string{"unknown schema version 2", "goal is required", "remaining[1] is empty"}
+	if strings.Join(ve.Problems, "|") != strings.Join(want, "|") {
+		t.Errorf("problems = %q, want %q", ve.Problems, want)
+	}
+	if got := (&Doc{}).Problems(); len(got) != 2 {
+		t.Errorf("empty doc problems = %q", got)
+	}
+}
+
+func TestMailBodyRoundTrip(t *testing.T) {
+	d := sampleDoc()
+	body := d.MailBody() + "\n\n---\nfree-form notes"
+
+	for _, want := range []string{
+		"**Goal:** Fix invoice rounding",
+		"**Blockers:**\n- TestInvoiceTotal fails",
+		"- `go test ./billing -run TestInvoiceTotal`",
+		"**Workspace:** branch polecat/dave @ 01234567, 2 unpushed commit(s)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	got, err := Parse(body)
+	if err != nil || got == nil {
+		t.Fatalf("Parse = %v, %v", got, err)
+	}
+	if got.Goal != d.Goal || got.Commands[0] != d.Commands[0] || !got.CreatedAt.Equal(d.CreatedAt) {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	if d, err := Parse("just a note"); d != nil || err != nil {
+		t.Errorf("plain body = %v, %v", d, err)
+	}
+	if _, err := Parse("```gt-handoff\n{"); err == nil {
+		t.Error("unterminated block should fail")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	if rec, err := Load(dir); rec != nil || err != nil {
+		t.Fatalf("empty workspace = %v, %v", rec, err)
+	}
+
+	rec, err := Save(dir, &Doc{Goal: "Half done"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Valid || len(rec.Problems) != 1 {
+		t.Errorf("record = %+v, want invalid with one problem", rec)
+	}
+	if _, err := Save(dir, sampleDoc()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(dir)
+	if err != nil || got == nil || !got.Valid || got.Doc.Agent != "gastown/crew/dave" {
+		t.Fatalf("Load = %+v, %v", got, err)
+	}
+	if filepath.Base(filepath.Dir(Path(dir))) != ".runtime" {
+		t.Errorf("Path = %s", Path(dir))
+	}
+}
+
+func TestFillCapturesGitState(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "feature")
+	run("commit", "-q", "--allow-empty", "-m", "init")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "wip")
+
+	d := &Doc{Goal: "g", FilesTouched: []string{"docs/plan.md"}}
+	d.Fill(dir)
+	if d.State.Branch != "feature" || len(d.State.LastCommit) != 40 {
+		t.Errorf("state = %+v", d.State)
+	}
+	if strings.Join(d.FilesTouched, ",") != "docs/plan.md,notes.txt" {
+		t.Errorf("files touched = %q", d.FilesTouched)
+	}
+	if d.Version != SchemaVersion || d.CreatedAt.IsZero() {
+		t.Errorf("version/created not set: %+v", d)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
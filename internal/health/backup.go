@@ -163,11 +163,9 @@ func inspectBackup(townRoot, dbBackupDir, name string, now time.Time, staleAfter
 	// dead backup — and must not be papered over by a leftover legacy store
 	// sitting next to it. Twelve town databases carry both (gt-1j3e), and
 	// preferring "whichever has a manifest" reported a wiped backup as verified.
-	store := filepath.Join(dbBackupDir, name+"-backup")
-	st.Layout = LayoutCurrent
-	if !isDir(store) {
-		store = dbBackupDir
-		st.Layout = LayoutLegacy
+	store, layout := backupStore(dbBackupDir, name)
+	st.Layout = layout
+	if layout == LayoutLegacy {
 		st.Diagnostics = append(st.Diagnostics,
 			fmt.Sprintf("no %s-backup directory — verifying the legacy store in the backup root (%s)", name, dbBackupDir))
 	}
@@ -229,6 +227,17 @@ func inspectBackup(townRoot, dbBackupDir, name string, now time.Time, staleAfter
 	return st
 }
 
+// backupStore returns the dolt store a database's backup lives in and its
+// layout: <db>/<db>-backup when that directory exists, else the legacy
+// store in <db> itself.
+func backupStore(dbBackupDir, name string) (store, layout string) {
+	store = filepath.Join(dbBackupDir, name+"-backup")
+	if isDir(store) {
+		return store, LayoutCurrent
+	}
+	return dbBackupDir, LayoutLegacy
+}
+
 // isDir reports whether path exists and is a directory.
 func isDir(path string) bool {
 	info, err := os.Stat(path)
@@ -0,0 +1,209 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// backupVerifyTimeout bounds each dolt invocation of a verification. Restoring
+// a large backup copies every chunk, so this is generous.
+const backupVerifyTimeout = 10 * time.Minute
+
+// runDolt runs a dolt command in dir and returns its combined output.
+// Var so tests can stub dolt.
+var runDolt = func(dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), backupVerifyTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", args...)
+	cmd.Dir = dir
+	util.SetDetachedProcessGroup(cmd)
+	return cmd.CombinedOutput()
+}
+
+// BackupVerification is the result of opening one database's backup and
+// running integrity queries against it.
+type BackupVerification struct {
+	Name        string   `json:"name"`
+	StorePath   string   `json:"store_path"`
+	Layout      string   `json:"layout"`
+	HeadHash    string   `json:"head_hash,omitempty"`
+	Commits     int      `json:"commits"`
+	Tables      int      `json:"tables"`
+	Rows        int64    `json:"rows"`
+	DurationMs  int64    `json:"duration_ms"`
+	Problems    []string `json:"problems,omitempty"`
+	Diagnostics []string `json:"diagnostics,omitempty"`
+}
+
+// Healthy reports whether the backup passed every check.
+func (v BackupVerification) Healthy() bool { return len(v.Problems) == 0 }
+
+// VerifyBackups verifies the backups under .dolt-backup, or only those of
+// the named databases. InspectBackups checks that a backup looks like a dolt
+// store; this checks that it IS one, by reading all of it back.
+//
+// Each backup is restored into a scratch directory with `dolt backup restore`
+// — the backup store itself is only read — and the copy is checked: dolt
+// fsck over every chunk, the commit graph walked via dolt_log, every table's
+// rows counted, and foreign keys verified. Slow on large databases; meant
+// for a daily patrol or an operator, not the health check hot path.
+//
+// Returns nil when backups are not configured (no .dolt-backup directory).
+func VerifyBackups(townRoot string, databases []string) []BackupVerification {
+	backupDir := filepath.Join(townRoot, ".dolt-backup")
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return nil
+	}
+	want := make(map[string]bool, len(databases))
+	for _, db := range databases {
+		want[db] = true
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if len(want) > 0 && !want[entry.Name()] {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	results := make([]BackupVerification, 0, len(names))
+	for _, name := range names {
+		results = append(results, VerifyBackup(filepath.Join(backupDir, name), name))
+	}
+	return results
+}
+
+// VerifyBackup restores one database's backup into a scratch directory and
+// runs integrity checks on the copy.
+func VerifyBackup(dbBackupDir, name string) BackupVerification {
+	start := time.Now()
+	store, layout := backupStore(dbBackupDir, name)
+	v := BackupVerification{Name: name, StorePath: store, Layout: layout}
+	defer func() { v.DurationMs = time.Since(start).Milliseconds() }()
+
+	if !hasManifest(store) {
+		v.Problems = append(v.Problems, "contains no dolt store (no manifest) — nothing to verify")
+		return v
+	}
+
+	scratch, err := os.MkdirTemp("", "gt-backup-verify-")
+	if err != nil {
+		v.Problems = append(v.Problems, fmt.Sprintf("creating scratch directory: %v", err))
+		return v
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+
+	if out, err := runDolt(scratch, "backup", "restore", fileURL(store), name); err != nil {
+		v.Problems = append(v.Problems, fmt.Sprintf("cannot be restored: %v (%s)", err, strings.TrimSpace(string(out))))
+		return v
+	}
+	db := filepath.Join(scratch, name)
+
+	if out, err := runDolt(db, "fsck"); err != nil {
+		if unknownDoltCommand(out) {
+			v.Diagnostics = append(v.Diagnostics, "dolt fsck not available in this dolt version — chunk integrity not checked")
+		} else {
+			v.Problems = append(v.Problems, fmt.Sprintf("fails dolt fsck: %s", firstLine(out, err)))
+		}
+	}
+
+	if rows, err := sqlRows(db, "SELECT COUNT(*) AS commits, HASHOF('HEAD') AS head FROM dolt_log"); err != nil {
+		v.Problems = append(v.Problems, fmt.Sprintf("commit graph unreadable: %v", err))
+	} else if len(rows) == 1 && len(rows[0]) == 2 {
+		v.Commits, _ = strconv.Atoi(rows[0][0])
+		v.HeadHash = rows[0][1]
+		if v.Commits == 0 {
+			v.Problems = append(v.Problems, "has no commits")
+		}
+	}
+
+	tables, err := sqlRows(db, "SHOW TABLES")
+	if err != nil {
+		v.Problems = append(v.Problems, fmt.Sprintf("cannot list tables: %v", err))
+		return v
+	}
+	for _, t := range tables {
+		if len(t) == 0 || t[0] == "" {
+			continue
+		}
+		v.Tables++
+		counted, err := sqlRows(db, fmt.Sprintf("SELECT COUNT(*) FROM `%s`", strings.ReplaceAll(t[0], "`", "``")))
+		if err != nil || len(counted) != 1 || len(counted[0]) != 1 {
+			v.Problems = append(v.Problems, fmt.Sprintf("table %s unreadable: %v", t[0], err))
+			continue
+		}
+		n, _ := strconv.ParseInt(counted[0][0], 10, 64)
+		v.Rows += n
+	}
+
+	if out, err := runDolt(db, "constraints", "verify", "--all"); err != nil {
+		if unknownDoltCommand(out) {
+			v.Diagnostics = append(v.Diagnostics, "dolt constraints verify not available — foreign keys not checked")
+		} else {
+			v.Problems = append(v.Problems, fmt.Sprintf("has constraint violations: %s", firstLine(out, err)))
+		}
+	}
+	return v
+}
+
+// sqlRows runs a query against the dolt repository in dir and returns the
+// rows of its CSV output, header dropped. Values containing commas are not
+// expected in the queries this runs.
+func sqlRows(dir, query string) ([][]string, error) {
+	out, err := runDolt(dir, "sql", "-r", "csv", "-q", query)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 2 {
+		return nil, nil
+	}
+	rows := make([][]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		rows = append(rows, strings.Split(strings.TrimSpace(line), ","))
+	}
+	return rows, nil
+}
+
+// fileURL returns the dolt file:// remote URL of a local directory.
+func fileURL(dir string) string {
+	p := filepath.ToSlash(dir)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive path
+	}
+	return "file://" + p
+}
+
+// unknownDoltCommand reports whether dolt rejected a subcommand it does not
+// have, as older versions do for fsck.
+func unknownDoltCommand(out []byte) bool {
+	s := strings.ToLower(string(out))
+	return strings.Contains(s, "unknown command") || strings.Contains(s, "is not a valid command")
+}
+
+// firstLine returns the first line of a failed command's output, or the
+// error when there was none.
+func firstLine(out []byte, err error) string {
+	s := strings.TrimSpace(string(out))
+	if s == "" {
+		return err.Error()
+	}
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
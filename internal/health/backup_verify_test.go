@@ -0,0 +1,133 @@
+package health
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeDolt stubs runDolt: each call is answered by the first response whose
+// key is a substring of the joined arguments, and every call is logged.
+type fakeDolt struct {
+	responses [][2]string // key, output
+	failures  map[string]string
+	calls     []string
+}
+
+func stubDolt(t *testing.T, f *fakeDolt) {
+	t.Helper()
+	old := runDolt
+	runDolt = func(_ string, args ...string) ([]byte, error) {
+		call := strings.Join(args, " ")
+		f.calls = append(f.calls, call)
+		for key, out := range f.failures {
+			if strings.Contains(call, key) {
+				return []byte(out), errors.New("exit status 1")
+			}
+		}
+		for _, r := range f.responses {
+			if strings.Contains(call, r[0]) {
+				return []byte(r[1]), nil
+			}
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { runDolt = old })
+}
+
+// healthyDolt answers the verification queries for a two-table database.
+func healthyDolt() *fakeDolt {
+	return &fakeDolt{responses: [][2]string{
+		{"FROM dolt_log", "commits,head\n42,k2b4v7mqh1s0e9r3f6c8a5d2g4j7l0n1\n"},
+		{"SHOW TABLES", "Tables_in_beads\nissues\nlabels\n"},
+		{"FROM `issues`", "COUNT(*)\n120\n"},
+		{"FROM `labels`", "COUNT(*)\n30\n"},
+	}}
+}
+
+func TestVerifyBackups_Healthy(t *testing.T) {
+	root := t.TempDir()
+	writeStore(t, filepath.Join(backupPath(root, "beads"), "beads-backup"), 1, 4096)
+	f := healthyDolt()
+	stubDolt(t, f)
+
+	results := VerifyBackups(root, nil)
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	v := results[0]
+	if !v.Healthy() {
+		t.Fatalf("problems = %v", v.Problems)
+	}
+	if v.Layout != LayoutCurrent || v.Commits != 42 || v.Tables != 2 || v.Rows != 150 {
+		t.Errorf("verification = %+v", v)
+	}
+	if !strings.HasPrefix(f.calls[0], "backup restore file://") || !strings.HasSuffix(f.calls[0], "beads-backup beads") {
+		t.Errorf("first call = %q, want a restore of the backup store", f.calls[0])
+	}
+}
+
+func TestVerifyBackups_Problems(t *testing.T) {
+	root := t.TempDir()
+	writeStore(t, filepath.Join(backupPath(root, "beads"), "beads-backup"), 1, 4096)
+	f := healthyDolt()
+	f.failures = map[string]string{
+		"fsck":          "chunk k2b4v7mq: checksum mismatch\n",
+		"FROM `labels`": "table file missing",
+	}
+	stubDolt(t, f)
+
+	v := VerifyBackups(root, nil)[0]
+	var fsck, labels bool
+	for _, p := range v.Problems {
+		fsck = fsck || strings.Contains(p, "checksum mismatch")
+		labels = labels || strings.Contains(p, "labels unreadable")
+	}
+	if !fsck || !labels {
+		t.Errorf("problems = %v, want fsck and labels failures", v.Problems)
+	}
+}
+
+func TestVerifyBackups_OldDoltAndUnrestorable(t *testing.T) {
+	root := t.TempDir()
+	writeStore(t, filepath.Join(backupPath(root, "beads"), "beads-backup"), 1, 4096)
+	writeStore(t, backupPath(root, "gastown"), 1, 4096) // legacy layout
+	if err := os.MkdirAll(backupPath(root, "hq"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f := healthyDolt()
+	f.failures = map[string]string{
+		"fsck":        "dolt: 'fsck' is not a valid command",
+		"gastown":     "manifest references missing table file",
+		"constraints": "unknown command \"constraints\"",
+	}
+	stubDolt(t, f)
+
+	results := VerifyBackups(root, nil)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	beads, gastown, hq := results[0], results[1], results[2]
+	if !beads.Healthy() || len(beads.Diagnostics) != 2 {
+		t.Errorf("beads = %+v, want healthy with fsck/constraints diagnostics", beads)
+	}
+	if gastown.Healthy() || gastown.Layout != LayoutLegacy || !strings.Contains(gastown.Problems[0], "cannot be restored") {
+		t.Errorf("gastown = %+v, want restore failure", gastown)
+	}
+	if hq.Healthy() || !strings.Contains(hq.Problems[0], "no manifest") {
+		t.Errorf("hq = %+v, want no-manifest problem", hq)
+	}
+
+	only := VerifyBackups(root, []string{"beads"})
+	if len(only) != 1 || only[0].Name != "beads" {
+		t.Errorf("filtered = %+v, want beads only", only)
+	}
+}
+
+func TestVerifyBackups_NoBackupDirReturnsNil(t *testing.T) {
+	if got := VerifyBackups(t.TempDir(), nil); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
@@ -0,0 +1,151 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// AgentTarget is a managed hook file of a non-Claude agent runtime.
+// Claude targets are discovered by DiscoverTargets and merged into
+// settings.json; these are rendered whole, either by a translator or from
+// a static template.
+type AgentTarget struct {
+	Path     string // Full path to the hook file
+	Dir      string // Directory the file is installed under
+	Key      string // Override key (e.g., "gastown/crew", "mayor")
+	Rig      string // Rig name, or empty for town-level
+	Role     string // crew, polecat, witness, refinery, mayor, deacon
+	Provider string // The preset's hooks provider (e.g., "gemini")
+	Agent    string // The configured agent name
+
+	HooksDir  string
+	HooksFile string
+}
+
+// Translated reports whether the file is rendered from the managed hooks
+// config rather than a template.
+func (t AgentTarget) Translated() bool {
+	return Translated(t.Provider) && isSettingsFile(t.HooksFile)
+}
+
+// DisplayKey returns the override key with the provider, e.g. "gastown/crew (gemini)".
+func (t AgentTarget) DisplayKey() string {
+	return t.Key + " (" + t.Provider + ")"
+}
+
+// DiscoverAgentTargets finds the hook files of every non-Claude agent
+// configured for a role in the workspace. Town-level roles and agents that
+// take a settings directory get one file in the role directory; other rig
+// roles get one per worktree.
+func DiscoverAgentTargets(townRoot string) ([]AgentTarget, error) {
+	locations, err := DiscoverRoleLocations(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []AgentTarget
+	for _, loc := range locations {
+		rigPath := ""
+		if loc.Rig != "" {
+			rigPath = filepath.Join(townRoot, loc.Rig)
+		}
+
+		// Use the configured agent, not the resolved one: resolution falls
+		// back to claude when the agent binary isn't in PATH (CI, fresh
+		// machines), which would silently skip the agent's hook files.
+		agentName, _ := config.ResolveRoleAgentName(loc.Role, townRoot, rigPath)
+		if agentName == "" {
+			continue
+		}
+		preset := config.GetAgentPresetByName(agentName)
+		if preset == nil || preset.HooksDir == "" || preset.HooksSettingsFile == "" {
+			continue
+		}
+		provider := preset.HooksProvider
+		if provider == "" {
+			provider = agentName
+		}
+		if provider == "claude" {
+			continue
+		}
+
+		dirs := []string{loc.Dir}
+		if loc.Rig != "" && !preset.HooksUseSettingsDir {
+			dirs = DiscoverWorktrees(loc.Dir)
+		}
+		for _, dir := range dirs {
+			targets = append(targets, AgentTarget{
+				Path:      filepath.Join(dir, preset.HooksDir, preset.HooksSettingsFile),
+				Dir:       dir,
+				Key:       roleKey(loc.Rig, loc.Role),
+				Rig:       loc.Rig,
+				Role:      loc.Role,
+				Provider:  provider,
+				Agent:     agentName,
+				HooksDir:  preset.HooksDir,
+				HooksFile: preset.HooksSettingsFile,
+			})
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Path < targets[j].Path })
+	return targets, nil
+}
+
+// Expected returns the content the target's file should have. Translated
+// files keep whatever non-hook fields the current file has.
+func (t AgentTarget) Expected() ([]byte, error) {
+	existing, _ := os.ReadFile(t.Path)
+	return expectedContent(t.Provider, t.Key, t.Role, t.HooksFile, existing)
+}
+
+// SyncAgentTarget brings the target's file up to date, or with dryRun
+// reports what it would do.
+func SyncAgentTarget(t AgentTarget, dryRun bool) (SyncResult, error) {
+	return syncFile(t.Provider, t.Key, t.Role, t.HooksFile, t.Path, dryRun)
+}
+
+// roleDirs maps rig subdirectories to the role that lives in them.
+var roleDirs = map[string]string{
+	"crew":     "crew",
+	"polecats": "polecat",
+	"witness":  "witness",
+	"refinery": "refinery",
+}
+
+// roleKey returns the override key for a role location.
+func roleKey(rig, role string) string {
+	key := role
+	if role == "polecat" {
+		key = "polecats"
+	}
+	if rig == "" {
+		return key
+	}
+	return rig + "/" + key
+}
+
+// inferTargetKey finds the override key for a file installed under dir by
+// walking up to the rig role directory that contains it. Outside a rig the
+// key is the role itself.
+func inferTargetKey(role, dir string) string {
+	if dir != "" {
+		d := filepath.Clean(dir)
+		for {
+			parent := filepath.Dir(d)
+			if parent == d {
+				break
+			}
+			if r, ok := roleDirs[filepath.Base(d)]; ok && isRig(parent) {
+				return roleKey(filepath.Base(parent), r)
+			}
+			d = parent
+		}
+	}
+	if key, ok := NormalizeTarget(role); ok {
+		return key
+	}
+	return role
+}
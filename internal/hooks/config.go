@@ -330,7 +330,20 @@ func DefaultOverrides() map[string]*HooksConfig {
 		// forget to call gt done before the session ends. The polecat-stop-check
 		// command is idempotent — it checks heartbeat state and branch commits
 		// before deciding whether to run gt done.
+		// Polecats also record every tool call with the worktree state after
+		// it, for gt polecat replay.
 		"polecats": {
+			PostToolUse: []HookEntry{
+				{
+					Matcher: "",
+					Hooks: []Hook{
+						{
+							Type:    "command",
+							Command: gtCommand("gt tap record"),
+						},
+					},
+				},
+			},
 			Stop: []HookEntry{
 				{
 					Matcher: "",
@@ -1039,21 +1052,9 @@ func DefaultBase() *HooksConfig {
 				}},
 			},
 			{
-				Matcher: "Bash(rm -rf /*)",
-				Hooks: []Hook{{
-					Type:    "command",
-					Command: gtCommand("gt tap guard dangerous-command"),
-				}},
-			},
-			{
-				Matcher: "Bash(git push --force*)",
-				Hooks: []Hook{{
-					Type:    "command",
-					Command: gtCommand("gt tap guard dangerous-command"),
-				}},
-			},
-			{
-				Matcher: "Bash(git push -f*)",
+				// Every Bash call: the guard parses the command line itself,
+				// so wrappers like bash -c and xargs can't hide a command.
+				Matcher: "Bash",
 				Hooks: []Hook{{
 					Type:    "command",
 					Command: gtCommand("gt tap guard dangerous-command"),
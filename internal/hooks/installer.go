@@ -37,7 +37,9 @@ var templateFS embed.FS
 //   - role: the Gas Town role (e.g., "polecat", "crew", "witness").
 //   - hooksDir/hooksFile: from the preset's HooksDir and HooksSettingsFile.
 //
-// Template resolution:
+// Content resolution:
+//   - Translated agents (Gemini, Cursor, Copilot, Codex): the managed hooks config
+//     (base + overrides, as for Claude) rendered in the agent's native format.
 //   - Role-aware agents (have both autonomous and interactive templates):
 //     templates/<provider>/settings-autonomous.json + settings-interactive.json
 //     or templates/<provider>/hooks-autonomous.json + hooks-interactive.json
@@ -61,14 +63,17 @@ func InstallForRole(provider, settingsDir, workDir, role, hooksDir, hooksFile st
 		return err
 	}
 
-	if existing, err := os.ReadFile(targetPath); err == nil {
-		if !needsUpgrade(existing) {
-			return nil // File exists and is current — don't overwrite
-		}
-		// Stale file detected — fall through to overwrite with current template
+	existing, err := os.ReadFile(targetPath)
+	if err == nil && !needsUpgrade(existing) {
+		return nil // File exists and is current — don't overwrite
 	}
-
-	return writeTemplate(provider, role, hooksFile, targetPath)
+	// Missing or stale — write the current content.
+	key := inferTargetKey(role, installDir(settingsDir, workDir, useSettingsDir))
+	content, err := expectedContent(provider, key, role, hooksFile, existing)
+	if err != nil {
+		return err
+	}
+	return writeHooksFile(targetPath, hooksFile, content)
 }
 
 // needsUpgrade returns true if an existing hooks file contains stale patterns
@@ -101,50 +106,47 @@ const (
 // SyncForRole compares the deployed hook/settings file against the current template
 // and overwrites if content differs. Returns what action was taken.
 //
-// This is the explicit sync path used by "gt hooks sync" for template-based agents
-// (OpenCode, Copilot, Pi, OMP, etc.). It should NOT be used for agents whose settings
-// are managed by the JSON merge path (Claude), as that would clobber merged overrides.
+// This is the explicit sync path for non-Claude agents. Translated providers
+// (Gemini, Cursor, Copilot, Codex) get the managed hooks config for the role's
+// override key, inferred from the install path; the rest (OpenCode, Pi, OMP)
+// get their template. It should NOT be used for agents whose settings are
+// managed by the JSON merge path (Claude), as that would clobber merged overrides.
 func SyncForRole(provider, settingsDir, workDir, role, hooksDir, hooksFile string, useSettingsDir bool) (SyncResult, error) {
 	if provider == "" || hooksDir == "" || hooksFile == "" {
 		return SyncUnchanged, nil
 	}
 
 	targetPath := installTargetPath(settingsDir, workDir, hooksDir, hooksFile, useSettingsDir)
+	key := inferTargetKey(role, installDir(settingsDir, workDir, useSettingsDir))
+	return syncFile(provider, key, role, hooksFile, targetPath, false)
+}
+
+// syncFile compares the file at targetPath against its expected content and
+// rewrites it when they differ, unless dryRun is set.
+func syncFile(provider, key, role, hooksFile, targetPath string, dryRun bool) (SyncResult, error) {
+	existing, readErr := os.ReadFile(targetPath)
+	fileExisted := readErr == nil
 
-	content, err := resolveAndSubstitute(provider, hooksFile, role)
+	content, err := expectedContent(provider, key, role, hooksFile, existing)
 	if err != nil {
 		return 0, err
 	}
 
-	fileExisted := false
-	if existing, err := os.ReadFile(targetPath); err == nil {
-		fileExisted = true
+	if fileExisted {
 		if isSettingsFile(hooksFile) {
 			// JSON files: use structural comparison to tolerate whitespace differences.
 			if TemplateContentEqual(existing, content) {
 				return SyncUnchanged, nil
 			}
-		} else {
-			if bytes.Equal(existing, content) {
-				return SyncUnchanged, nil
-			}
+		} else if bytes.Equal(existing, content) {
+			return SyncUnchanged, nil
 		}
 	}
 
-	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-		return 0, fmt.Errorf("creating hooks directory: %w", err)
-	}
-
-	perm := os.FileMode(0644)
-	if isSettingsFile(hooksFile) {
-		perm = 0600
-	}
-
-	// Atomic write (temp + rename) prevents concurrent polecat spawns from
-	// interleaving truncates+writes into a partial JSON file that Claude
-	// rejects at startup. See gh#3500.
-	if err := atomicfile.WriteFile(targetPath, content, perm); err != nil {
-		return 0, fmt.Errorf("writing hooks file: %w", err)
+	if !dryRun {
+		if err := writeHooksFile(targetPath, hooksFile, content); err != nil {
+			return 0, err
+		}
 	}
 
 	if fileExisted {
@@ -153,13 +155,35 @@ func SyncForRole(provider, settingsDir, workDir, role, hooksDir, hooksFile strin
 	return SyncCreated, nil
 }
 
+// expectedContent returns what a hooks file should contain. Translated
+// providers render the managed hooks config for key, keeping non-hook
+// fields of existing; the rest resolve their template.
+func expectedContent(provider, key, role, hooksFile string, existing []byte) ([]byte, error) {
+	if !Translated(provider) || !isSettingsFile(hooksFile) {
+		return resolveAndSubstitute(provider, hooksFile, role)
+	}
+	cfg, err := ComputeExpected(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := NativeHooks(existing); err != nil {
+		// Unreadable: replace it rather than fail closed on a file we own.
+		existing = nil
+	}
+	return RenderProviderHooks(provider, cfg, role, existing)
+}
+
 // installTargetPath computes the full path for a hook/settings file.
 func installTargetPath(settingsDir, workDir, hooksDir, hooksFile string, useSettingsDir bool) string {
-	installDir := workDir
+	return filepath.Join(installDir(settingsDir, workDir, useSettingsDir), hooksDir, hooksFile)
+}
+
+// installDir is the directory a hook/settings file is installed under.
+func installDir(settingsDir, workDir string, useSettingsDir bool) string {
 	if useSettingsDir {
-		installDir = settingsDir
+		return settingsDir
 	}
-	return filepath.Join(installDir, hooksDir, hooksFile)
+	return workDir
 }
 
 // resolveAndSubstitute resolves the template and performs {{GT_BIN}} substitution.
@@ -185,13 +209,8 @@ func resolveAndSubstitute(provider, hooksFile, role string) ([]byte, error) {
 	return content, nil
 }
 
-// writeTemplate resolves a template, substitutes placeholders, and writes it to targetPath.
-func writeTemplate(provider, role, hooksFile, targetPath string) error {
-	content, err := resolveAndSubstitute(provider, hooksFile, role)
-	if err != nil {
-		return err
-	}
-
+// writeHooksFile atomically writes content to targetPath.
+func writeHooksFile(targetPath, hooksFile string, content []byte) error {
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 		return fmt.Errorf("creating hooks directory: %w", err)
 	}
@@ -201,11 +220,12 @@ func writeTemplate(provider, role, hooksFile, targetPath string) error {
 		perm = 0600
 	}
 
-	// Atomic write (temp + rename) — see gh#3500.
+	// Atomic write (temp + rename) prevents concurrent polecat spawns from
+	// interleaving truncates+writes into a partial JSON file that Claude
+	// rejects at startup. See gh#3500.
 	if err := atomicfile.WriteFile(targetPath, content, perm); err != nil {
 		return fmt.Errorf("writing hooks file: %w", err)
 	}
-
 	return nil
 }
 
@@ -274,12 +294,11 @@ func resolveGTBinary() string {
 	return "gt"
 }
 
-// ComputeExpectedTemplate returns the expected file content for a template-based
-// provider (e.g., gemini) with {{GT_BIN}} resolved to the actual gt binary path.
-// This is used by the doctor hooks-sync check to compare installed files against
-// current templates.
+// ComputeExpectedTemplate returns the expected file content for a non-Claude
+// provider with {{GT_BIN}} resolved to the actual gt binary path. Translated
+// providers (e.g., gemini) render the managed hooks config for role.
 func ComputeExpectedTemplate(provider, hooksFile, role string) ([]byte, error) {
-	return resolveAndSubstitute(provider, hooksFile, role)
+	return expectedContent(provider, inferTargetKey(role, ""), role, hooksFile, nil)
 }
 
 // TemplateContentEqual compares two JSON byte slices for structural equality
@@ -603,8 +603,22 @@ func TestInstallForRole_Permissions(t *testing.T) {
 	}
 }
 
+// renderedHooks returns what InstallForRole should write for provider and
+// role into dir: the managed hooks rendered into the native format.
+func renderedHooks(t *testing.T, provider, role, dir string) string {
+	t.Helper()
+	cfg, err := ComputeExpected(inferTargetKey(role, dir))
+	if err != nil {
+		t.Fatalf("ComputeExpected: %v", err)
+	}
+	want, err := RenderProviderHooks(provider, cfg, role, nil)
+	if err != nil {
+		t.Fatalf("RenderProviderHooks: %v", err)
+	}
+	return string(want)
+}
+
 func TestInstallForRole_CursorRoleAware(t *testing.T) {
-	// Cursor uses hooks-autonomous.json / hooks-interactive.json naming
 	dir := t.TempDir()
 	err := InstallForRole("cursor", dir, dir, "polecat", ".cursor", "hooks.json", false)
 	if err != nil {
@@ -612,12 +626,8 @@ func TestInstallForRole_CursorRoleAware(t *testing.T) {
 	}
 
 	got, _ := os.ReadFile(filepath.Join(dir, ".cursor", "hooks.json"))
-	want, err := resolveAndSubstitute("cursor", "hooks-autonomous.json", "polecat")
-	if err != nil {
-		t.Fatalf("resolveAndSubstitute: %v", err)
-	}
-	if string(got) != string(want) {
-		t.Error("cursor autonomous: content mismatch")
+	if want := renderedHooks(t, "cursor", "polecat", dir); string(got) != want {
+		t.Errorf("cursor autonomous: content mismatch\ngot:\n%s\nwant:\n%s", got, want)
 	}
 
 	dir2 := t.TempDir()
@@ -627,12 +637,8 @@ func TestInstallForRole_CursorRoleAware(t *testing.T) {
 	}
 
 	got, _ = os.ReadFile(filepath.Join(dir2, ".cursor", "hooks.json"))
-	want, err = resolveAndSubstitute("cursor", "hooks-interactive.json", "crew")
-	if err != nil {
-		t.Fatalf("resolveAndSubstitute: %v", err)
-	}
-	if string(got) != string(want) {
-		t.Error("cursor interactive: content mismatch")
+	if want := renderedHooks(t, "cursor", "crew", dir2); string(got) != want {
+		t.Errorf("cursor interactive: content mismatch\ngot:\n%s\nwant:\n%s", got, want)
 	}
 }
 
@@ -644,57 +650,30 @@ func TestInstallForRole_GeminiRoleAware(t *testing.T) {
 	}
 
 	got, _ := os.ReadFile(filepath.Join(dir, ".gemini", "settings.json"))
-	want, _ := templateFS.ReadFile("templates/gemini/settings-autonomous.json")
-	// Gemini templates contain {{GT_BIN}} which gets resolved at install time.
-	// Apply the same substitution (with JSON escaping) to the expected content for comparison.
-	gtBin := resolveGTBinary()
-	gtBinJSON := strings.ReplaceAll(gtBin, `\`, `\\`)
-	wantResolved := strings.ReplaceAll(string(want), "{{GT_BIN}}", gtBinJSON)
-	if string(got) != wantResolved {
-		t.Error("gemini autonomous: content mismatch")
+	if want := renderedHooks(t, "gemini", "witness", dir); string(got) != want {
+		t.Errorf("gemini autonomous: content mismatch\ngot:\n%s\nwant:\n%s", got, want)
 	}
 }
 
 func TestInstallForRole_CodexRoleAware(t *testing.T) {
-	dir := t.TempDir()
-	err := InstallForRole("codex", dir, dir, "crew", ".codex", "hooks.json", false)
-	if err != nil {
-		t.Fatalf("InstallForRole(codex, crew): %v", err)
-	}
-
-	got, _ := os.ReadFile(filepath.Join(dir, ".codex", "hooks.json"))
-	want, err := resolveAndSubstitute("codex", "hooks-interactive.json", "crew")
-	if err != nil {
-		t.Fatalf("resolveAndSubstitute: %v", err)
-	}
-	if string(got) != string(want) {
-		t.Error("codex interactive: content mismatch")
-	}
-	if !strings.Contains(string(got), "costs record >/dev/null 2>&1 &") {
-		t.Error("codex interactive: stop hook should silence gt costs record output")
-	}
-
-	dir2 := t.TempDir()
-	err = InstallForRole("codex", dir2, dir2, "polecat", ".codex", "hooks.json", false)
-	if err != nil {
-		t.Fatalf("InstallForRole(codex, polecat): %v", err)
-	}
+	for _, role := range []string{"crew", "polecat"} {
+		dir := t.TempDir()
+		err := InstallForRole("codex", dir, dir, role, ".codex", "hooks.json", false)
+		if err != nil {
+			t.Fatalf("InstallForRole(codex, %s): %v", role, err)
+		}
 
-	got, _ = os.ReadFile(filepath.Join(dir2, ".codex", "hooks.json"))
-	want, err = resolveAndSubstitute("codex", "hooks-autonomous.json", "polecat")
-	if err != nil {
-		t.Fatalf("resolveAndSubstitute: %v", err)
-	}
-	if string(got) != string(want) {
-		t.Error("codex autonomous: content mismatch")
-	}
-	if !strings.Contains(string(got), "costs record >/dev/null 2>&1 &") {
-		t.Error("codex autonomous: stop hook should silence gt costs record output")
+		got, _ := os.ReadFile(filepath.Join(dir, ".codex", "hooks.json"))
+		if want := renderedHooks(t, "codex", role, dir); string(got) != want {
+			t.Errorf("codex %s: content mismatch\ngot:\n%s\nwant:\n%s", role, got, want)
+		}
+		if role == "crew" && !strings.Contains(string(got), "costs record >/dev/null 2>&1 &") {
+			t.Error("codex interactive: stop hook should silence gt costs record output")
+		}
 	}
 }
 
 func TestInstallForRole_CopilotRoleAware(t *testing.T) {
-	// Copilot uses gastown-autonomous.json / gastown-interactive.json naming
 	dir := t.TempDir()
 	err := InstallForRole("copilot", dir, dir, "polecat", ".github/hooks", "gastown.json", false)
 	if err != nil {
@@ -702,12 +681,8 @@ func TestInstallForRole_CopilotRoleAware(t *testing.T) {
 	}
 
 	got, _ := os.ReadFile(filepath.Join(dir, ".github/hooks", "gastown.json"))
-	want, err := resolveAndSubstitute("copilot", "gastown-autonomous.json", "polecat")
-	if err != nil {
-		t.Fatalf("resolveAndSubstitute: %v", err)
-	}
-	if string(got) != string(want) {
-		t.Error("copilot autonomous: content mismatch")
+	if want := renderedHooks(t, "copilot", "polecat", dir); string(got) != want {
+		t.Errorf("copilot autonomous: content mismatch\ngot:\n%s\nwant:\n%s", got, want)
 	}
 
 	dir2 := t.TempDir()
@@ -715,19 +690,50 @@ func TestInstallForRole_CopilotRoleAware(t *testing.T) {
 	if err != nil {
 		t.Fatalf("InstallForRole(copilot, crew): %v", err)
 	}
-
 	got, _ = os.ReadFile(filepath.Join(dir2, ".github/hooks", "gastown.json"))
-	want, err = resolveAndSubstitute("copilot", "gastown-interactive.json", "crew")
+	if want := renderedHooks(t, "copilot", "crew", dir2); string(got) != want {
+		t.Errorf("copilot interactive: content mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInstallForRole_InfersRigOverride(t *testing.T) {
+	town := t.TempDir()
+	worktree := filepath.Join(town, "gastown", "polecats", "toast", "gastown")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallForRole("gemini", worktree, worktree, "polecat", ".gemini", "settings.json", false); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filepath.Join(worktree, ".gemini", "settings.json"))
+	if !strings.Contains(string(got), "polecat-stop-check") {
+		t.Errorf("polecat worktree should get the polecats override:\n%s", got)
+	}
+	if key := inferTargetKey("polecat", worktree); key != "gastown/polecats" {
+		t.Errorf("inferTargetKey = %q, want gastown/polecats", key)
+	}
+}
+
+func TestSyncForRole_PreservesUserSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gemini", "settings.json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"model": {"name": "gemini-2.5-pro"}, "hooks": {}}`), 0600)
+
+	result, err := SyncForRole("gemini", dir, dir, "crew", ".gemini", "settings.json", false)
 	if err != nil {
-		t.Fatalf("resolveAndSubstitute: %v", err)
+		t.Fatal(err)
 	}
-	if string(got) != string(want) {
-		t.Error("copilot interactive: content mismatch")
+	if result != SyncUpdated {
+		t.Errorf("result = %d, want SyncUpdated", result)
+	}
+	got, _ := os.ReadFile(path)
+	if !strings.Contains(string(got), "gemini-2.5-pro") || !strings.Contains(string(got), "SessionStart") {
+		t.Errorf("sync should keep the model and add hooks:\n%s", got)
 	}
 }
 
 func TestComputeExpectedTemplate_Gemini(t *testing.T) {
-	// Autonomous role should get settings-autonomous.json template
 	content, err := ComputeExpectedTemplate("gemini", "settings.json", "witness")
 	if err != nil {
 		t.Fatalf("ComputeExpectedTemplate: %v", err)
@@ -737,25 +743,26 @@ func TestComputeExpectedTemplate_Gemini(t *testing.T) {
 	if strings.Contains(string(content), "{{GT_BIN}}") {
 		t.Error("expected {{GT_BIN}} to be resolved")
 	}
+	gtBinJSON := strings.ReplaceAll(resolveGTBinary(), `\`, `\\`)
+	if !strings.Contains(string(content), gtBinJSON+" tap when") {
+		t.Errorf("expected tool hooks to run %s tap when", gtBinJSON)
+	}
 
-	// Should contain GT_HOOK_SOURCE=compact (from autonomous template)
+	// Autonomous roles tell prime why the session started.
 	if !strings.Contains(string(content), "GT_HOOK_SOURCE=compact") {
-		t.Error("expected GT_HOOK_SOURCE=compact in autonomous template")
+		t.Error("expected GT_HOOK_SOURCE=compact for autonomous role")
 	}
 
 	if strings.Contains(string(content), `"context"`) || strings.Contains(string(content), `"fileName"`) {
-		t.Error("Gemini template should not force context.fileName; GEMINI.md overlays must remain loadable")
+		t.Error("Gemini settings should not force context.fileName; GEMINI.md overlays must remain loadable")
 	}
 
-	// Interactive role should get settings-interactive.json template
 	interactiveContent, err := ComputeExpectedTemplate("gemini", "settings.json", "crew")
 	if err != nil {
 		t.Fatalf("ComputeExpectedTemplate(crew): %v", err)
 	}
-
-	// Interactive template should NOT contain GT_HOOK_SOURCE=compact
 	if strings.Contains(string(interactiveContent), "GT_HOOK_SOURCE=compact") {
-		t.Error("interactive template should not contain GT_HOOK_SOURCE=compact")
+		t.Error("interactive role should not get GT_HOOK_SOURCE=compact")
 	}
 }
 
@@ -0,0 +1,339 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/hookutil"
+)
+
+// Translators render the managed HooksConfig (the same base + overrides that
+// produce Claude's settings.json) into the native hook file of another
+// runtime. A runtime without a translator (OpenCode, Pi, OMP: JS/TS plugins;
+// Vibe: no hooks) keeps a static template.
+//
+// Native matchers can only select a tool by name, so tool hooks are wrapped
+// in "gt tap when", which applies the Claude matcher (e.g. "Bash(git push*)")
+// to the runtime's payload and hands the hook a Claude-shaped payload. Tool
+// hooks sharing a native matcher go through one "gt tap when", so a shell
+// call costs one gt process however many Bash guards are configured.
+
+// translator describes one runtime's hook file format.
+type translator struct {
+	// events maps managed event names to native ones. Events a runtime
+	// lacks are left out of its file.
+	events map[string]string
+
+	// entry renders one native hook entry.
+	entry func(event, matcher, command string) map[string]any
+
+	// shellTool is the runtime's native name for the shell tool, used as
+	// the native matcher for Bash hooks. Empty when the runtime has no
+	// matchers.
+	shellTool string
+
+	// version, when set, is written as the file's top-level "version".
+	version int
+
+	// prepare adjusts a command for the runtime and role before rendering.
+	prepare func(event, command, role string) string
+}
+
+var translators = map[string]translator{
+	"gemini": {
+		events: map[string]string{
+			"PreToolUse":       "BeforeTool",
+			"PostToolUse":      "AfterTool",
+			"SessionStart":     "SessionStart",
+			"PreCompact":       "PreCompress",
+			"UserPromptSubmit": "BeforeAgent",
+			"Stop":             "SessionEnd",
+		},
+		entry:     nestedEntry,
+		shellTool: "run_shell_command",
+		prepare: func(event, command, role string) string {
+			// Gemini doesn't say why a session started; autonomous roles
+			// need it to pick up hooked work.
+			if !hookutil.IsAutonomousRole(role) {
+				return command
+			}
+			switch event {
+			case "SessionStart":
+				return "GT_SESSION_ID=${GT_SESSION_ID:-$(uuidgen)} GT_HOOK_SOURCE=startup " + command
+			case "PreCompact":
+				return "GT_HOOK_SOURCE=compact " + command
+			}
+			return command
+		},
+	},
+	"cursor": {
+		events: map[string]string{
+			"PreToolUse":       "preToolUse",
+			"PostToolUse":      "postToolUse",
+			"SessionStart":     "sessionStart",
+			"PreCompact":       "preCompact",
+			"UserPromptSubmit": "beforeSubmitPrompt",
+			"Stop":             "stop",
+		},
+		entry: func(event, matcher, command string) map[string]any {
+			e := map[string]any{"command": command}
+			if matcher != "" {
+				e["matcher"] = matcher
+			}
+			return e
+		},
+		shellTool: "Shell",
+		version:   1,
+	},
+	"copilot": {
+		events: map[string]string{
+			"PreToolUse":       "preToolUse",
+			"PostToolUse":      "postToolUse",
+			"SessionStart":     "sessionStart",
+			"UserPromptSubmit": "userPromptSubmitted",
+			"Stop":             "sessionEnd",
+		},
+		entry: func(event, matcher, command string) map[string]any {
+			timeout := 10
+			if event == "sessionStart" {
+				timeout = 30
+			}
+			return map[string]any{"type": "command", "bash": command, "timeoutSec": timeout}
+		},
+		version: 1,
+	},
+	"codex": {
+		events: map[string]string{
+			"SessionStart": "SessionStart",
+			"Stop":         "Stop",
+		},
+		entry: nestedEntry,
+		prepare: func(event, command, role string) string {
+			// Codex prints hook output into the transcript.
+			if strings.HasSuffix(command, " &") && !strings.Contains(command, ">/dev/null") {
+				return strings.TrimSuffix(command, " &") + " >/dev/null 2>&1 &"
+			}
+			return command
+		},
+	},
+}
+
+// nestedEntry renders the Claude-style {matcher, hooks: [...]} entry.
+func nestedEntry(event, matcher, command string) map[string]any {
+	return map[string]any{
+		"matcher": matcher,
+		"hooks":   []any{map[string]any{"type": "command", "command": command}},
+	}
+}
+
+// Translated reports whether provider's hook file is rendered from the
+// managed HooksConfig rather than copied from a template.
+func Translated(provider string) bool {
+	_, ok := translators[provider]
+	return ok
+}
+
+// toolEvents are the events whose matchers select tools.
+var toolEvents = map[string]bool{"PreToolUse": true, "PostToolUse": true}
+
+// RenderProviderHooks renders cfg as provider's hook file for role. Fields
+// of existing (the current file, may be nil) other than "hooks" are kept.
+func RenderProviderHooks(provider string, cfg *HooksConfig, role string, existing []byte) ([]byte, error) {
+	tr, ok := translators[provider]
+	if !ok {
+		return nil, fmt.Errorf("no hook translator for provider %q", provider)
+	}
+
+	out := map[string]json.RawMessage{}
+	if len(existing) > 0 {
+		if err := json.Unmarshal(existing, &out); err != nil {
+			return nil, &SettingsIntegrityError{Path: provider + " hooks", Err: err}
+		}
+	}
+
+	native := map[string][]any{}
+	for _, event := range EventTypes {
+		nativeEvent, ok := tr.events[event]
+		if !ok {
+			continue
+		}
+		// Tool hooks grouped by native matcher, in first-seen order.
+		var matchers []string
+		groups := map[string][]WhenHook{}
+		for _, entry := range cfg.GetEntries(event) {
+			for _, h := range entry.Hooks {
+				command := h.Command
+				if tr.prepare != nil {
+					command = tr.prepare(event, command, role)
+				}
+				if !toolEvents[event] {
+					native[nativeEvent] = append(native[nativeEvent], tr.entry(nativeEvent, "", command))
+					continue
+				}
+				matcher := nativeToolMatcher(tr.shellTool, entry.Matcher)
+				if _, ok := groups[matcher]; !ok {
+					matchers = append(matchers, matcher)
+				}
+				groups[matcher] = append(groups[matcher], WhenHook{Matcher: entry.Matcher, Command: command})
+			}
+		}
+		for _, matcher := range matchers {
+			native[nativeEvent] = append(native[nativeEvent], tr.entry(nativeEvent, matcher, whenCommand(provider, groups[matcher])))
+		}
+	}
+
+	raw, err := marshalCommands(native, "")
+	if err != nil {
+		return nil, err
+	}
+	out["hooks"] = raw
+	if tr.version != 0 {
+		if _, ok := out["version"]; !ok {
+			out["version"] = json.RawMessage(fmt.Sprint(tr.version))
+		}
+	}
+	return marshalCommands(out, "  ")
+}
+
+// marshalCommands encodes v without HTML-escaping, so shell commands stay
+// readable ("&", ">" rather than "\u0026", "\u003e").
+func marshalCommands(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nativeToolMatcher narrows a native matcher to the shell tool when the
+// managed matcher only applies to Bash; "gt tap when" does the rest.
+func nativeToolMatcher(shellTool, matcher string) string {
+	if shellTool == "" {
+		return ""
+	}
+	if tool, _, _ := splitToolMatcher(matcher); tool == "Bash" {
+		return shellTool
+	}
+	return ""
+}
+
+// WhenHook is one tool hook run by "gt tap when": a Claude matcher and the
+// command it selects.
+type WhenHook struct {
+	Matcher string
+	Command string
+}
+
+// whenCommand wraps tool hooks in one "gt tap when", which runs each hook
+// whose matcher selects the call, in order.
+func whenCommand(provider string, hooks []WhenHook) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s --provider %s --", gtCommand("gt tap when"), provider)
+	for _, h := range hooks {
+		b.WriteString(" " + shellQuote(h.Matcher) + " " + shellQuote(h.Command))
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+var toolPatternRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\((.*)\)$`)
+
+// splitToolMatcher splits "Tool(pattern)" into its parts. A bare tool name
+// has no pattern; anything else is returned as a tool-name regex.
+func splitToolMatcher(matcher string) (tool, pattern string, hasPattern bool) {
+	if m := toolPatternRe.FindStringSubmatch(matcher); m != nil {
+		return m[1], m[2], true
+	}
+	return matcher, "", false
+}
+
+// MatchTool reports whether a Claude hook matcher selects a tool call.
+// "" and "*" match everything; "Tool(pattern)" matches the tool when its
+// command matches the glob pattern; anything else is a regex on the tool
+// name, like "Edit|Write".
+func MatchTool(matcher, tool, command string) bool {
+	if matcher == "" || matcher == "*" {
+		return true
+	}
+	name, pattern, hasPattern := splitToolMatcher(matcher)
+	if hasPattern {
+		return name == tool && globMatch(pattern, command)
+	}
+	re, err := regexp.Compile("^(?:" + name + ")$")
+	if err != nil {
+		return name == tool
+	}
+	return re.MatchString(tool)
+}
+
+// globMatch matches s against a pattern where * matches any run of
+// characters, spaces and slashes included.
+func globMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	re, err := regexp.Compile("(?s)^" + strings.Join(parts, ".*") + "$")
+	return err == nil && re.MatchString(s)
+}
+
+// NativeHook is one hook command in a runtime's hook file.
+type NativeHook struct {
+	Event   string
+	Matcher string
+	Command string
+}
+
+func (h NativeHook) String() string {
+	if h.Matcher == "" {
+		return h.Event + ": " + h.Command
+	}
+	return fmt.Sprintf("%s[%s]: %s", h.Event, h.Matcher, h.Command)
+}
+
+// NativeHooks lists the hook commands in a JSON hook file of any of the
+// translated formats, sorted for comparison.
+func NativeHooks(content []byte) ([]NativeHook, error) {
+	var file struct {
+		Hooks map[string][]map[string]json.RawMessage `json:"hooks"`
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	var out []NativeHook
+	for event, entries := range file.Hooks {
+		for _, e := range entries {
+			var matcher string
+			_ = json.Unmarshal(e["matcher"], &matcher)
+			for _, key := range []string{"command", "bash"} {
+				var c string
+				if json.Unmarshal(e[key], &c) == nil && c != "" {
+					out = append(out, NativeHook{Event: event, Matcher: matcher, Command: c})
+				}
+			}
+			var nested []struct {
+				Command string `json:"command"`
+			}
+			if json.Unmarshal(e["hooks"], &nested) == nil {
+				for _, n := range nested {
+					out = append(out, NativeHook{Event: event, Matcher: matcher, Command: n.Command})
+				}
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out, nil
+}
@@ -0,0 +1,113 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMatchTool(t *testing.T) {
+	tests := []struct {
+		matcher, tool, command string
+		want                   bool
+	}{
+		{"", "Edit", "", true},
+		{"*", "Bash", "ls", true},
+		{"Bash", "Bash", "rm -rf /", true},
+		{"Bash", "Edit", "", false},
+		{"Edit|Write", "Write", "", true},
+		{"Bash(gh pr create*)", "Bash", "gh pr create --fill", true},
+		{"Bash(gh pr create*)", "Bash", "echo gh pr create", false},
+		{"Bash(*bd mol pour*patrol*)", "Bash", "cd x && bd mol pour mol-witness-patrol", true},
+		{"Bash(*bd mol pour*patrol*)", "Edit", "bd mol pour patrol", false},
+		{"Bash(git stash*)", "Bash", "git status", false},
+	}
+	for _, tt := range tests {
+		if got := MatchTool(tt.matcher, tt.tool, tt.command); got != tt.want {
+			t.Errorf("MatchTool(%q, %q, %q) = %v, want %v", tt.matcher, tt.tool, tt.command, got, tt.want)
+		}
+	}
+}
+
+func testConfig() *HooksConfig {
+	return &HooksConfig{
+		PreToolUse: []HookEntry{
+			{Matcher: "Bash(git push*)", Hooks: []Hook{{Type: "command", Command: "guard 'push'"}}},
+			{Matcher: "Edit", Hooks: []Hook{{Type: "command", Command: "guard edit"}}},
+			{Matcher: "Bash", Hooks: []Hook{{Type: "command", Command: "guard shell"}}},
+		},
+		SessionStart:     []HookEntry{{Matcher: "", Hooks: []Hook{{Type: "command", Command: "prime"}}}},
+		PreCompact:       []HookEntry{{Matcher: "", Hooks: []Hook{{Type: "command", Command: "prime"}}}},
+		UserPromptSubmit: []HookEntry{{Matcher: ""}}, // disabled
+		Stop:             []HookEntry{{Matcher: "", Hooks: []Hook{{Type: "command", Command: "record &"}}}},
+	}
+}
+
+func TestRenderProviderHooks(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     []string // NativeHook strings, sorted
+	}{
+		{"gemini", []string{
+			"BeforeTool: " + whenCommand("gemini", []WhenHook{{"Edit", "guard edit"}}),
+			"BeforeTool[run_shell_command]: " + whenCommand("gemini", []WhenHook{{"Bash(git push*)", "guard 'push'"}, {"Bash", "guard shell"}}),
+			"PreCompress: GT_HOOK_SOURCE=compact prime",
+			"SessionEnd: record &",
+			"SessionStart: GT_SESSION_ID=${GT_SESSION_ID:-$(uuidgen)} GT_HOOK_SOURCE=startup prime",
+		}},
+		{"cursor", []string{
+			"preCompact: prime",
+			"preToolUse: " + whenCommand("cursor", []WhenHook{{"Edit", "guard edit"}}),
+			"preToolUse[Shell]: " + whenCommand("cursor", []WhenHook{{"Bash(git push*)", "guard 'push'"}, {"Bash", "guard shell"}}),
+			"sessionStart: prime",
+			"stop: record &",
+		}},
+		{"copilot", []string{
+			"preToolUse: " + whenCommand("copilot", []WhenHook{{"Bash(git push*)", "guard 'push'"}, {"Edit", "guard edit"}, {"Bash", "guard shell"}}),
+			"sessionEnd: record &",
+			"sessionStart: prime",
+		}},
+		{"codex", []string{
+			"SessionStart: prime",
+			"Stop: record >/dev/null 2>&1 &",
+		}},
+	}
+	for _, tt := range tests {
+		data, err := RenderProviderHooks(tt.provider, testConfig(), "polecat", nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.provider, err)
+		}
+		hooks, err := NativeHooks(data)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.provider, err)
+		}
+		var got []string
+		for _, h := range hooks {
+			got = append(got, h.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s hooks:\n%s\nwant:\n%s", tt.provider, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestRenderProviderHooksKeepsOtherFields(t *testing.T) {
+	existing := []byte(`{"version": 2, "theme": "dark", "hooks": {"stop": [{"command": "old"}]}}`)
+	data, err := RenderProviderHooks("cursor", testConfig(), "crew", existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["theme"] != "dark" || got["version"] != float64(2) {
+		t.Errorf("non-hook fields not kept: %s", data)
+	}
+	if strings.Contains(string(data), `"old"`) {
+		t.Errorf("stale hooks kept: %s", data)
+	}
+	if _, err := RenderProviderHooks("opencode", testConfig(), "crew", nil); err == nil {
+		t.Error("opencode has no translator")
+	}
+}
@@ -0,0 +1,161 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// FederatedMail is a message queued for delivery to a peer town. Entries are
+// stored one file per message under <townRoot>/.runtime/federation/outbox/<peer>/
+// and forwarded by the federation package (daemon heartbeat or
+// `gt federation flush`), which removes the entry once the peer returns a
+// delivery receipt.
+type FederatedMail struct {
+	ID          string    `json:"id"`
+	Peer        string    `json:"peer"`
+	To          string    `json:"to"` // address inside the peer town
+	QueuedAt    time.Time `json:"queued_at"`
+	Attempts    int       `json:"attempts,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	Message     *Message  `json:"message"`
+}
+
+// federationOutboxRoot holds one outbox directory per peer.
+func federationOutboxRoot(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "federation", "outbox")
+}
+
+// FederationOutboxDir returns the outbox directory for a peer town.
+func FederationOutboxDir(townRoot, peer string) string {
+	return filepath.Join(federationOutboxRoot(townRoot), peer)
+}
+
+// SplitFederatedAddress splits "<peer>:<address>" into its parts. It only
+// checks syntax; use Router.federatedPeer to confirm the peer is registered.
+func SplitFederatedAddress(address string) (peer, local string, ok bool) {
+	peer, local, ok = strings.Cut(address, ":")
+	if !ok || local == "" || !config.ValidFederationPeerName(peer) {
+		return "", "", false
+	}
+	return peer, local, true
+}
+
+// federatedPeer reports whether address targets a registered peer town.
+func (r *Router) federatedPeer(address string) (peer, local string, ok bool) {
+	return federatedPeerIn(r.townRoot, address)
+}
+
+func federatedPeerIn(townRoot, address string) (peer, local string, ok bool) {
+	if townRoot == "" {
+		return "", "", false
+	}
+	peer, local, ok = SplitFederatedAddress(address)
+	if !ok {
+		return "", "", false
+	}
+	cfg, err := config.LoadFederationConfig(config.FederationConfigPath(townRoot))
+	if err != nil {
+		return "", "", false
+	}
+	if _, registered := cfg.Peers[peer]; !registered {
+		return "", "", false
+	}
+	return peer, local, true
+}
+
+// queueFederated stores msg in the peer's outbox.
+func (r *Router) queueFederated(peer, local string, msg *Message) error {
+	if isFederatedLoop(local) {
+		return fmt.Errorf("cannot relay %s through peer %s: multi-hop federation is not supported", local, peer)
+	}
+	queued := *msg
+	if queued.ID == "" {
+		queued.ID = GenerateID()
+	}
+	if queued.Timestamp.IsZero() {
+		queued.Timestamp = time.Now()
+	}
+	queued.To = local
+	entry := &FederatedMail{
+		ID:       queued.ID,
+		Peer:     peer,
+		To:       local,
+		QueuedAt: time.Now(),
+		Message:  &queued,
+	}
+	path := filepath.Join(FederationOutboxDir(r.townRoot, peer), entry.ID+".json")
+	if err := atomicfile.EnsureDirAndWriteJSON(path, entry); err != nil {
+		return fmt.Errorf("queueing mail for %s: %w", peer, err)
+	}
+	return nil
+}
+
+// isFederatedLoop reports whether the peer-local address itself names
+// another town, which would require relaying through the peer.
+func isFederatedLoop(local string) bool {
+	_, _, ok := SplitFederatedAddress(local)
+	return ok
+}
+
+// ListFederatedOutbox returns queued outbound mail for every peer, oldest first.
+func ListFederatedOutbox(townRoot string) ([]*FederatedMail, error) {
+	root := federationOutboxRoot(townRoot)
+	peers, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*FederatedMail
+	for _, p := range peers {
+		if !p.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, p.Name())
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+				continue
+			}
+			fm, err := ReadFederatedMail(filepath.Join(dir, e.Name()))
+			if err != nil {
+				continue // skip corrupt entries rather than blocking the queue
+			}
+			out = append(out, fm)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].QueuedAt.Before(out[j].QueuedAt)
+	})
+	return out, nil
+}
+
+// ReadFederatedMail reads one outbox entry.
+func ReadFederatedMail(path string) (*FederatedMail, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var fm FederatedMail
+	if err := json.Unmarshal(data, &fm); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	if fm.Message == nil {
+		return nil, fmt.Errorf("parsing %s: missing message", filepath.Base(path))
+	}
+	return &fm, nil
+}
@@ -0,0 +1,116 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const testPeerCA = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func newFederatedTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	cfg := config.NewFederationConfig()
+	cfg.Name = "laptop"
+	cfg.Peers["build"] = &config.FederationPeer{URL: "https://build.local:7443", CACert: testPeerCA}
+	if err := config.SaveFederationConfig(config.FederationConfigPath(townRoot), cfg); err != nil {
+		t.Fatalf("SaveFederationConfig: %v", err)
+	}
+	return townRoot
+}
+
+func TestSplitFederatedAddress(t *testing.T) {
+	tests := []struct {
+		addr      string
+		peer      string
+		local     string
+		federated bool
+	}{
+		{"build:gastown/Toast", "build", "gastown/Toast", true},
+		{"build:mayor/", "build", "mayor/", true},
+		{"channel:alerts", "", "", false},
+		{"list:oncall", "", "", false},
+		{"gastown/Toast", "", "", false},
+		{"build:", "", "", false},
+	}
+	for _, tt := range tests {
+		peer, local, ok := SplitFederatedAddress(tt.addr)
+		if ok != tt.federated || peer != tt.peer || local != tt.local {
+			t.Errorf("SplitFederatedAddress(%q) = %q, %q, %v", tt.addr, peer, local, ok)
+		}
+	}
+}
+
+func TestSend_QueuesFederatedMail(t *testing.T) {
+	townRoot := newFederatedTown(t)
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	msg := &Message{From: "mayor/", To: "build:gastown/Toast", Subject: "Handoff", Body: "take gt-1", ThreadID: "thread-1"}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	queued, err := ListFederatedOutbox(townRoot)
+	if err != nil {
+		t.Fatalf("ListFederatedOutbox: %v", err)
+	}
+	if len(queued) != 1 {
+		t.Fatalf("queued %d, want 1", len(queued))
+	}
+	fm := queued[0]
+	if fm.Peer != "build" || fm.To != "gastown/Toast" || fm.Message.To != "gastown/Toast" {
+		t.Errorf("entry = %+v", fm)
+	}
+	if fm.ID == "" || fm.Message.ID != fm.ID || fm.Message.ThreadID != "thread-1" {
+		t.Errorf("entry ID/thread = %q / %+v", fm.ID, fm.Message)
+	}
+}
+
+func TestSend_FederatedMultiHopRejected(t *testing.T) {
+	townRoot := newFederatedTown(t)
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	err := r.Send(&Message{From: "mayor/", To: "build:other:mayor/", Subject: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "multi-hop") {
+		t.Fatalf("Send error = %v, want multi-hop rejection", err)
+	}
+}
+
+func TestFederatedPeer_UnregisteredPeer(t *testing.T) {
+	townRoot := newFederatedTown(t)
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	if _, _, ok := r.federatedPeer("build:gastown/Toast"); !ok {
+		t.Error("registered peer not recognized")
+	}
+	if _, _, ok := r.federatedPeer("desktop:gastown/Toast"); ok {
+		t.Error("unregistered peer treated as federated")
+	}
+	noFed := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+	if _, _, ok := noFed.federatedPeer("build:gastown/Toast"); ok {
+		t.Error("town without federation config treated address as federated")
+	}
+}
+
+func TestSend_ScheduledFederatedMail(t *testing.T) {
+	townRoot := newFederatedTown(t)
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	msg := &Message{From: "mayor/", To: "build:mayor/", Subject: "Later", NotBefore: time.Now().Add(time.Hour)}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if queued, _ := ListFederatedOutbox(townRoot); len(queued) != 0 {
+		t.Errorf("scheduled mail queued for federation before its time: %d entries", len(queued))
+	}
+	scheduled, err := r.ListScheduled()
+	if err != nil {
+		t.Fatalf("ListScheduled: %v", err)
+	}
+	if len(scheduled) != 1 {
+		t.Fatalf("scheduled %d, want 1", len(scheduled))
+	}
+}
@@ -0,0 +1,538 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/atomicfile"
+)
+
+// SearchIndexFile is the name of the on-disk mail search index. It lives in
+// the same .beads directory as the archive so one index covers every mailbox
+// stored there (the whole town for town beads).
+const SearchIndexFile = "mail-index.json"
+
+// searchIndexJournalFile holds the updates made since the index file was
+// last written, one JSON op per line. Writes append to it, so sending or
+// reading a message costs the size of that message, not of all mail.
+const searchIndexJournalFile = "mail-index.jsonl"
+
+// searchIndexMinCompact is the journal size below which it is never folded
+// into the index file. Above it the journal is compacted once it outgrows
+// the index file, which keeps the cost of compaction amortized per write.
+const searchIndexMinCompact = 1 << 20
+
+// searchIndexVersion is bumped when the on-disk layout changes; an index with
+// a different version is treated as empty and rebuilt on the next write.
+const searchIndexVersion = 1
+
+// SearchIndex is a small inverted index over mail messages. Documents are
+// keyed by message ID; postings map lowercase terms to the IDs that contain
+// them. Updates (Upsert, MarkRead, ...) are appended to a journal that is
+// replayed on load and periodically folded into the index file. Writes are
+// serialized with a file lock so concurrent agents don't clobber each other.
+type SearchIndex struct {
+	path string
+}
+
+// indexOp is one journaled update. Ops are idempotent, so replaying a
+// journal over an index file that already includes it is harmless.
+type indexOp struct {
+	Op  string    `json:"op"` // "put", "read" or "remove"
+	Doc *indexDoc `json:"doc,omitempty"`
+	ID  string    `json:"id,omitempty"`
+}
+
+// indexDoc is one indexed message.
+type indexDoc struct {
+	ID        string    `json:"id"`
+	Mailbox   string    `json:"mailbox"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	ThreadID  string    `json:"thread_id,omitempty"`
+	Priority  Priority  `json:"priority,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Read      bool      `json:"read,omitempty"`
+	Archived  bool      `json:"archived,omitempty"`
+}
+
+// indexData is the serialized index.
+type indexData struct {
+	Version int `json:"version"`
+
+	// BuiltAt is set only by Rebuild. Incremental updates create the file
+	// too (the first send after an upgrade), so an index without it holds
+	// only recent mail and must be rebuilt before it can be trusted.
+	BuiltAt time.Time `json:"built_at,omitzero"`
+
+	Docs     map[string]*indexDoc `json:"docs"`
+	Postings map[string][]string  `json:"postings"`
+}
+
+// SearchIndexPath returns the index path for a .beads directory.
+func SearchIndexPath(beadsDir string) string {
+	return filepath.Join(beadsDir, SearchIndexFile)
+}
+
+// OpenSearchIndex returns the search index stored in dir. The file is
+// created lazily on the first write.
+func OpenSearchIndex(dir string) *SearchIndex {
+	return &SearchIndex{path: SearchIndexPath(dir)}
+}
+
+// Path returns the index file path.
+func (ix *SearchIndex) Path() string {
+	return ix.path
+}
+
+// Complete reports whether the index has been fully built by Rebuild and
+// kept up to date since. An index written only by incremental updates is
+// not complete.
+func (ix *SearchIndex) Complete() bool {
+	d, err := ix.load()
+	return err == nil && !d.BuiltAt.IsZero()
+}
+
+// Upsert adds or replaces messages in the index under the given mailbox.
+func (ix *SearchIndex) Upsert(mailbox string, msgs ...*Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	var ops []indexOp
+	for _, msg := range msgs {
+		if msg == nil || msg.ID == "" {
+			continue
+		}
+		ops = append(ops, indexOp{Op: "put", Doc: docFromMessage(mailbox, msg)})
+	}
+	return ix.update(ops...)
+}
+
+// MarkArchived upserts a message and flags it as archived.
+func (ix *SearchIndex) MarkArchived(mailbox string, msg *Message) error {
+	doc := docFromMessage(mailbox, msg)
+	doc.Archived = true
+	doc.Read = true
+	return ix.update(indexOp{Op: "put", Doc: doc})
+}
+
+// Remove drops messages from the index. Unknown IDs are ignored.
+func (ix *SearchIndex) Remove(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	ops := make([]indexOp, 0, len(ids))
+	for _, id := range ids {
+		ops = append(ops, indexOp{Op: "remove", ID: id})
+	}
+	return ix.update(ops...)
+}
+
+// MarkRead flags an already-indexed message as read. Unknown IDs are ignored.
+func (ix *SearchIndex) MarkRead(id string) error {
+	return ix.update(indexOp{Op: "read", ID: id})
+}
+
+// Rebuild replaces the entire index with the given messages, keyed by mailbox.
+func (ix *SearchIndex) Rebuild(byMailbox map[string][]*Message, archived map[string][]*Message) error {
+	d := newIndexData()
+	d.BuiltAt = time.Now().UTC()
+	for mailbox, msgs := range byMailbox {
+		for _, msg := range msgs {
+			if msg != nil && msg.ID != "" {
+				d.put(docFromMessage(mailbox, msg))
+			}
+		}
+	}
+	for mailbox, msgs := range archived {
+		for _, msg := range msgs {
+			if msg == nil || msg.ID == "" {
+				continue
+			}
+			doc := docFromMessage(mailbox, msg)
+			doc.Archived = true
+			doc.Read = true
+			d.put(doc)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return err
+	}
+	fl, err := ix.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+	if err := ix.save(d); err != nil {
+		return err
+	}
+	return ix.truncateJournal()
+}
+
+// Query returns indexed messages matching q, newest first. Mailbox restricts
+// results to one mailbox; empty searches every mailbox in the index.
+func (ix *SearchIndex) Query(q *SearchQuery, mailbox string) ([]*Message, error) {
+	d, err := ix.load()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := d.candidates(q.postingTerms())
+	var matches []*indexDoc
+	for _, id := range candidates {
+		doc := d.Docs[id]
+		if doc == nil {
+			continue
+		}
+		if mailbox != "" && doc.Mailbox != mailbox && AddressToIdentity(doc.To) != mailbox {
+			continue
+		}
+		if q.matches(doc) {
+			matches = append(matches, doc)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].Timestamp.Equal(matches[j].Timestamp) {
+			return matches[i].Timestamp.After(matches[j].Timestamp)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	out := make([]*Message, 0, len(matches))
+	for _, doc := range matches {
+		out = append(out, doc.toMessage())
+	}
+	return out, nil
+}
+
+// update appends ops to the journal under lock, compacting the journal into
+// the index file once it has grown large enough.
+func (ix *SearchIndex) update(ops ...indexOp) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, op := range ops {
+		line, err := json.Marshal(op)
+		if err != nil {
+			return fmt.Errorf("encoding search index update: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return err
+	}
+	fl, err := ix.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	f, err := os.OpenFile(ix.journalPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: index is not secret; path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening search index journal: %w", err)
+	}
+	_, werr := f.Write(buf)
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		return fmt.Errorf("writing search index journal: %w", werr)
+	}
+
+	if ix.journalNeedsCompaction() {
+		return ix.compact()
+	}
+	return nil
+}
+
+func (ix *SearchIndex) journalPath() string {
+	return filepath.Join(filepath.Dir(ix.path), searchIndexJournalFile)
+}
+
+// journalNeedsCompaction reports whether the journal has outgrown both the
+// minimum and the index file itself.
+func (ix *SearchIndex) journalNeedsCompaction() bool {
+	j, err := os.Stat(ix.journalPath())
+	if err != nil || j.Size() < searchIndexMinCompact {
+		return false
+	}
+	snap, err := os.Stat(ix.path)
+	return err != nil || j.Size() >= snap.Size()
+}
+
+// compact folds the journal into the index file. Caller holds the lock.
+func (ix *SearchIndex) compact() error {
+	d, err := ix.loadSnapshot()
+	if err != nil {
+		// A corrupt index is a cache, not a source of truth: start over.
+		d = newIndexData()
+	}
+	if err := ix.replayJournal(d); err != nil {
+		return err
+	}
+	if err := ix.save(d); err != nil {
+		return err
+	}
+	return ix.truncateJournal()
+}
+
+func (ix *SearchIndex) truncateJournal() error {
+	if err := os.Remove(ix.journalPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("truncating search index journal: %w", err)
+	}
+	return nil
+}
+
+func (ix *SearchIndex) lock() (*flock.Flock, error) {
+	fl := flock.New(ix.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring search index lock: %w", err)
+	}
+	return fl, nil
+}
+
+// load returns the index file with the journal replayed over it.
+func (ix *SearchIndex) load() (*indexData, error) {
+	d, err := ix.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if err := ix.replayJournal(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// replayJournal applies the journaled ops to d. A torn last line, left by
+// a writer that died mid-append, is skipped.
+func (ix *SearchIndex) replayJournal(d *indexData) error {
+	data, err := os.ReadFile(ix.journalPath()) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading search index journal: %w", err)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var op indexOp
+		if len(line) == 0 || json.Unmarshal(line, &op) != nil {
+			continue
+		}
+		d.apply(op)
+	}
+	return nil
+}
+
+// loadSnapshot reads the index file alone.
+func (ix *SearchIndex) loadSnapshot() (*indexData, error) {
+	data, err := os.ReadFile(ix.path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return newIndexData(), nil
+		}
+		return nil, fmt.Errorf("reading search index: %w", err)
+	}
+	var d indexData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parsing search index: %w", err)
+	}
+	if d.Version != searchIndexVersion || d.Docs == nil {
+		return newIndexData(), nil
+	}
+	if d.Postings == nil {
+		d.Postings = make(map[string][]string)
+	}
+	return &d, nil
+}
+
+func (ix *SearchIndex) save(d *indexData) error {
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("encoding search index: %w", err)
+	}
+	return atomicfile.WriteFile(ix.path, data, 0644)
+}
+
+func newIndexData() *indexData {
+	return &indexData{
+		Version:  searchIndexVersion,
+		Docs:     make(map[string]*indexDoc),
+		Postings: make(map[string][]string),
+	}
+}
+
+// put inserts doc, replacing any previous version and its postings.
+func (d *indexData) put(doc *indexDoc) {
+	d.remove(doc.ID)
+	d.Docs[doc.ID] = doc
+	for _, term := range docTerms(doc) {
+		d.Postings[term] = insertSorted(d.Postings[term], doc.ID)
+	}
+}
+
+// apply applies one journaled op.
+func (d *indexData) apply(op indexOp) {
+	switch op.Op {
+	case "put":
+		if op.Doc != nil && op.Doc.ID != "" {
+			d.put(op.Doc)
+		}
+	case "read":
+		if doc, ok := d.Docs[op.ID]; ok {
+			doc.Read = true
+		}
+	case "remove":
+		d.remove(op.ID)
+	}
+}
+
+// remove deletes a document and its postings.
+func (d *indexData) remove(id string) {
+	old, ok := d.Docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range docTerms(old) {
+		d.Postings[term] = removeSorted(d.Postings[term], old.ID)
+		if len(d.Postings[term]) == 0 {
+			delete(d.Postings, term)
+		}
+	}
+	delete(d.Docs, id)
+}
+
+// candidates intersects the postings for terms. With no terms every
+// document is a candidate.
+func (d *indexData) candidates(terms []string) []string {
+	if len(terms) == 0 {
+		ids := make([]string, 0, len(d.Docs))
+		for id := range d.Docs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+	result := d.Postings[terms[0]]
+	for _, term := range terms[1:] {
+		result = intersectSorted(result, d.Postings[term])
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
+
+func docFromMessage(mailbox string, msg *Message) *indexDoc {
+	if mailbox == "" {
+		mailbox = AddressToIdentity(msg.To)
+	}
+	return &indexDoc{
+		ID:        msg.ID,
+		Mailbox:   mailbox,
+		From:      msg.From,
+		To:        msg.To,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		ThreadID:  msg.ThreadID,
+		Priority:  msg.Priority,
+		Type:      string(msg.Type),
+		Timestamp: msg.Timestamp,
+		Read:      msg.Read,
+	}
+}
+
+func (doc *indexDoc) toMessage() *Message {
+	return &Message{
+		ID:        doc.ID,
+		From:      doc.From,
+		To:        doc.To,
+		Subject:   doc.Subject,
+		Body:      doc.Body,
+		ThreadID:  doc.ThreadID,
+		Priority:  doc.Priority,
+		Type:      MessageType(doc.Type),
+		Timestamp: doc.Timestamp,
+		Read:      doc.Read,
+	}
+}
+
+// docTerms returns the unique terms indexed for a document.
+func docTerms(doc *indexDoc) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(doc.Subject + " " + doc.Body) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// tokenize splits text into lowercase alphanumeric terms.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizeText lowercases text and collapses it to single-space separated
+// terms, so phrase matching ignores punctuation and whitespace differences.
+func normalizeText(text string) string {
+	return " " + strings.Join(tokenize(text), " ") + " "
+}
+
+func insertSorted(ids []string, id string) []string {
+	i := sort.SearchStrings(ids, id)
+	if i < len(ids) && ids[i] == id {
+		return ids
+	}
+	ids = append(ids, "")
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+	return ids
+}
+
+func removeSorted(ids []string, id string) []string {
+	i := sort.SearchStrings(ids, id)
+	if i < len(ids) && ids[i] == id {
+		return append(ids[:i], ids[i+1:]...)
+	}
+	return ids
+}
+
+func intersectSorted(a, b []string) []string {
+	var out []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
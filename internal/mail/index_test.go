@@ -0,0 +1,311 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func indexTestMessages() []*Message {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*Message{
+		{ID: "hq-1", From: "gastown/witness", To: "mayor/", Subject: "Merge failed for gt-abc", Body: "The refinery reported: merge failed on rebase.", ThreadID: "t-1", Timestamp: base},
+		{ID: "hq-2", From: "gastown/polecats/Toast", To: "mayor/", Subject: "POLECAT_DONE Toast", Body: "Work complete, deployed.", ThreadID: "t-2", Timestamp: base.Add(time.Hour)},
+		{ID: "hq-3", From: "mayor/", To: "gastown/witness", Subject: "Re: Merge failed", Body: "Retry the merge after rebasing.", ThreadID: "t-1", Timestamp: base.Add(2 * time.Hour)},
+	}
+}
+
+func newTestIndex(t *testing.T) *SearchIndex {
+	t.Helper()
+	ix := OpenSearchIndex(t.TempDir())
+	msgs := indexTestMessages()
+	if err := ix.Upsert("mayor/", msgs[0], msgs[1]); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := ix.Upsert("gastown/witness", msgs[2]); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	return ix
+}
+
+func queryIDs(t *testing.T, ix *SearchIndex, query, mailbox string) []string {
+	t.Helper()
+	q, err := parseSearchQueryAt(query, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("parse %q: %v", query, err)
+	}
+	msgs, err := ix.Query(q, mailbox)
+	if err != nil {
+		t.Fatalf("Query %q: %v", query, err)
+	}
+	var ids []string
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestSearchIndexQuery(t *testing.T) {
+	ix := newTestIndex(t)
+
+	tests := []struct {
+		query   string
+		mailbox string
+		want    []string
+	}{
+		{"merge", "", []string{"hq-3", "hq-1"}},
+		{"merge", "mayor/", []string{"hq-1"}},
+		{`"merge failed"`, "", []string{"hq-3", "hq-1"}},
+		{`"failed merge"`, "", nil},
+		{"merge from:witness", "", []string{"hq-1"}},
+		{"to:witness", "", []string{"hq-3"}},
+		{"thread:t-1", "", []string{"hq-3", "hq-1"}},
+		{"deploy*", "", []string{"hq-2"}},
+		{"subject:toast", "", []string{"hq-2"}},
+		{"rebase subject:merge", "", []string{"hq-1"}},
+		{"after:2026-03-01T12:30:00Z", "", []string{"hq-3", "hq-2"}},
+		{"date:2026-03-01..2026-03-01T13:30:00Z", "", []string{"hq-2", "hq-1"}},
+		{"gt-abc", "", []string{"hq-1"}},
+		{"nonexistent", "", nil},
+		{"", "mayor/", []string{"hq-2", "hq-1"}},
+	}
+	for _, tt := range tests {
+		got := queryIDs(t, ix, tt.query, tt.mailbox)
+		if len(got) != len(tt.want) {
+			t.Errorf("query %q mailbox %q = %v, want %v", tt.query, tt.mailbox, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("query %q mailbox %q = %v, want %v", tt.query, tt.mailbox, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSearchIndexUpsertReplacesPostings(t *testing.T) {
+	ix := newTestIndex(t)
+	updated := *indexTestMessages()[0]
+	updated.Subject = "Renamed"
+	updated.Body = "nothing relevant"
+	if err := ix.Upsert("mayor/", &updated); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if got := queryIDs(t, ix, "refinery", ""); len(got) != 0 {
+		t.Errorf("stale postings still match: %v", got)
+	}
+	if got := queryIDs(t, ix, "renamed", ""); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("renamed = %v, want [hq-1]", got)
+	}
+}
+
+func TestSearchIndexReadAndArchive(t *testing.T) {
+	ix := newTestIndex(t)
+	if err := ix.MarkRead("hq-2"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := ix.MarkRead("hq-missing"); err != nil {
+		t.Fatalf("MarkRead unknown: %v", err)
+	}
+	if got := queryIDs(t, ix, "is:unread", "mayor/"); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("is:unread = %v, want [hq-1]", got)
+	}
+
+	if err := ix.MarkArchived("mayor/", indexTestMessages()[0]); err != nil {
+		t.Fatalf("MarkArchived: %v", err)
+	}
+	if got := queryIDs(t, ix, "is:archived", ""); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("is:archived = %v, want [hq-1]", got)
+	}
+}
+
+func TestSearchIndexRebuild(t *testing.T) {
+	ix := newTestIndex(t)
+	msgs := indexTestMessages()
+	err := ix.Rebuild(
+		map[string][]*Message{"gastown/witness": {msgs[2]}},
+		map[string][]*Message{"mayor/": {msgs[0]}},
+	)
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if got := queryIDs(t, ix, "toast", ""); len(got) != 0 {
+		t.Errorf("rebuild kept dropped message: %v", got)
+	}
+	if got := queryIDs(t, ix, "is:archived merge", ""); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("archived after rebuild = %v, want [hq-1]", got)
+	}
+}
+
+func TestSearchIndexCompleteOnlyAfterRebuild(t *testing.T) {
+	ix := newTestIndex(t)
+	if ix.Complete() {
+		t.Fatal("index written only by Upsert reported complete")
+	}
+	if err := ix.Rebuild(nil, nil); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if err := ix.Upsert("mayor/", indexTestMessages()[0]); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if !ix.Complete() {
+		t.Error("index not complete after Rebuild plus Upsert")
+	}
+}
+
+func TestSearchIndexRemove(t *testing.T) {
+	ix := newTestIndex(t)
+	if err := ix.Remove("hq-1", "hq-missing"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if got := queryIDs(t, ix, "merge", ""); len(got) != 1 || got[0] != "hq-3" {
+		t.Errorf("after remove = %v, want [hq-3]", got)
+	}
+	if got := queryIDs(t, ix, "refinery", ""); len(got) != 0 {
+		t.Errorf("removed message's postings remain: %v", got)
+	}
+}
+
+func TestSearchIndexCorruptFileIsRebuilt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, SearchIndexFile), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ix := OpenSearchIndex(dir)
+	if _, err := ix.Query(&SearchQuery{}, ""); err == nil {
+		t.Fatal("expected error querying corrupt index")
+	}
+	if ix.Complete() {
+		t.Fatal("corrupt index reported complete")
+	}
+	if err := ix.Upsert("mayor/", indexTestMessages()[0]); err != nil {
+		t.Fatalf("Upsert over corrupt index: %v", err)
+	}
+	if err := ix.Rebuild(map[string][]*Message{"mayor/": indexTestMessages()[:1]}, nil); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if got := queryIDs(t, ix, "merge", ""); len(got) != 1 {
+		t.Errorf("after recovery = %v, want one result", got)
+	}
+}
+
+func TestSearchIndexWritesAppendToJournal(t *testing.T) {
+	dir := t.TempDir()
+	ix := OpenSearchIndex(dir)
+	msgs := indexTestMessages()
+	if err := ix.Rebuild(map[string][]*Message{"mayor/": msgs[:2]}, nil); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	before, err := os.ReadFile(ix.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ix.Upsert("gastown/witness", msgs[2]); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := ix.MarkRead("hq-1"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := ix.Remove("hq-2"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	after, err := os.ReadFile(ix.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("incremental writes rewrote the index file")
+	}
+	if got := queryIDs(t, ix, "merge", ""); len(got) != 2 || got[0] != "hq-3" || got[1] != "hq-1" {
+		t.Errorf("merge = %v, want [hq-3 hq-1]", got)
+	}
+	if got := queryIDs(t, ix, "is:read", ""); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("is:read = %v, want [hq-1]", got)
+	}
+	if got := queryIDs(t, ix, "deployed", ""); len(got) != 0 {
+		t.Errorf("removed message still matches: %v", got)
+	}
+	if !ix.Complete() {
+		t.Error("index not complete after Rebuild plus journaled writes")
+	}
+}
+
+func TestSearchIndexCompactsLargeJournal(t *testing.T) {
+	dir := t.TempDir()
+	ix := OpenSearchIndex(dir)
+	if err := ix.Rebuild(nil, nil); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	body := strings.Repeat("padding ", searchIndexMinCompact/8/4)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		msg := &Message{ID: fmt.Sprintf("hq-%d", i), From: "mayor/", To: "gastown/witness", Subject: "bulk", Body: body, Timestamp: base.Add(time.Duration(i) * time.Minute)}
+		if err := ix.Upsert("gastown/witness", msg); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, searchIndexJournalFile)); !os.IsNotExist(err) {
+		t.Errorf("journal not compacted: %v", err)
+	}
+	if got := queryIDs(t, ix, "bulk", ""); len(got) != 4 {
+		t.Errorf("after compaction = %v, want 4 results", got)
+	}
+	if !ix.Complete() {
+		t.Error("compaction lost the index's built time")
+	}
+}
+
+func TestLegacyMailboxAppendUpdatesIndex(t *testing.T) {
+	dir := t.TempDir()
+	mb := NewMailbox(dir)
+	msg := &Message{ID: "msg-1", From: "mayor/", To: "gastown/crew/joe", Subject: "Handoff notes", Body: "Check the flaky test", Timestamp: time.Now()}
+	if err := mb.Append(msg); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := mb.MarkRead("msg-1"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	got := queryIDs(t, OpenSearchIndex(dir), "flaky is:read", "")
+	if len(got) != 1 || got[0] != "msg-1" {
+		t.Errorf("indexed = %v, want [msg-1]", got)
+	}
+}
+
+func TestLegacyMailboxDeleteRemovesFromIndex(t *testing.T) {
+	dir := t.TempDir()
+	mb := NewMailbox(dir)
+	msg := &Message{ID: "msg-1", From: "mayor/", To: "gastown/crew/joe", Subject: "Handoff notes", Body: "Check the flaky test", Timestamp: time.Now()}
+	if err := mb.Append(msg); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := mb.Delete("msg-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := queryIDs(t, OpenSearchIndex(dir), "flaky", ""); len(got) != 0 {
+		t.Errorf("deleted message still indexed: %v", got)
+	}
+}
+
+func TestParseCreatedID(t *testing.T) {
+	tests := []struct {
+		out  string
+		want string
+	}{
+		{`{"id":"hq-abc","title":"x"}`, "hq-abc"},
+		{"hq-testmail-1\n", "hq-testmail-1"},
+		{"✓ Created issue: hq-abc", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := parseCreatedID([]byte(tt.out)); got != tt.want {
+			t.Errorf("parseCreatedID(%q) = %q, want %q", tt.out, got, tt.want)
+		}
+	}
+}
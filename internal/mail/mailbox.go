@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -568,10 +567,18 @@ func (m *Mailbox) getLegacy(id string) (*Message, error) {
 
 // MarkRead marks a message as read.
 func (m *Mailbox) MarkRead(id string) error {
+	var err error
 	if m.legacy {
-		return m.markReadLegacy(id)
+		err = m.markReadLegacy(id)
+	} else {
+		err = m.markReadBeads(id)
+	}
+	if err == nil {
+		if ix := m.searchIndex(); ix != nil {
+			_ = ix.MarkRead(id) // best-effort: the index is a cache
+		}
 	}
-	return m.markReadBeads(id)
+	return err
 }
 
 func (m *Mailbox) markReadBeads(id string) error {
@@ -841,12 +848,20 @@ func (m *Mailbox) markUnreadLegacy(id string) error {
 	return m.rewriteLegacy(messages)
 }
 
-// Delete removes a message.
+// Delete removes a message, including from the search index.
 func (m *Mailbox) Delete(id string) error {
+	var err error
 	if m.legacy {
-		return m.deleteLegacy(id)
+		err = m.deleteLegacy(id)
+	} else {
+		err = m.MarkRead(id) // beads: just acknowledge/close
+	}
+	if err == nil {
+		if ix := m.searchIndex(); ix != nil {
+			_ = ix.Remove(id) // best-effort: the index is a cache
+		}
 	}
-	return m.MarkRead(id) // beads: just acknowledge/close
+	return err
 }
 
 func (m *Mailbox) deleteLegacy(id string) error {
@@ -901,9 +916,13 @@ func (m *Mailbox) Archive(id string) error {
 	if err := m.appendToArchive(msg); err != nil {
 		return err
 	}
-	if err := m.Delete(id); err != nil {
+	if ix := m.searchIndex(); ix != nil {
+		_ = ix.MarkArchived(m.identity, msg) // best-effort: the index is a cache
+	}
+	// Close via MarkRead, not Delete: the message stays searchable as archived.
+	if err := m.MarkRead(id); err != nil {
 		if errors.Is(err, ErrMessageNotFound) {
-			// Bead was GC'd between Get and Delete; metadata is archived,
+			// Bead was GC'd between Get and close; metadata is archived,
 			// and there is nothing left to close.
 			return nil
 		}
@@ -947,6 +966,9 @@ func (m *Mailbox) archiveLegacy(id string) error {
 	if err := m.appendToArchive(target); err != nil {
 		return err
 	}
+	if ix := m.searchIndex(); ix != nil {
+		_ = ix.MarkArchived(m.identity, target) // best-effort: the index is a cache
+	}
 
 	// Rewrite inbox without the target
 	return m.rewriteLegacy(remaining)
@@ -1109,83 +1131,6 @@ func (m *Mailbox) rewriteArchive(messages []*Message) error {
 	return os.Rename(tmpPath, archivePath)
 }
 
-// SearchOptions specifies search parameters.
-type SearchOptions struct {
-	Query       string // Regex pattern to search for
-	FromFilter  string // Optional: only match messages from this sender
-	SubjectOnly bool   // Only search subject
-	BodyOnly    bool   // Only search body
-}
-
-// Search finds messages matching the given criteria.
-// Returns messages from both inbox and archive.
-// Query and FromFilter are treated as literal strings (not regex) to prevent ReDoS.
-func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
-	// Use QuoteMeta to escape special regex chars - prevents ReDoS attacks
-	// and provides intuitive literal string matching for users
-	re, err := regexp.Compile("(?i)" + regexp.QuoteMeta(opts.Query))
-	if err != nil {
-		return nil, fmt.Errorf("invalid search pattern: %w", err)
-	}
-
-	var fromRe *regexp.Regexp
-	if opts.FromFilter != "" {
-		fromRe, err = regexp.Compile("(?i)" + regexp.QuoteMeta(opts.FromFilter))
-		if err != nil {
-			return nil, fmt.Errorf("invalid from pattern: %w", err)
-		}
-	}
-
-	// Get inbox messages
-	inbox, err := m.List()
-	if err != nil {
-		return nil, err
-	}
-
-	// Get archived messages
-	archived, err := m.ListArchived()
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
-	}
-
-	// Combine and search
-	all := append(inbox, archived...)
-	var matches []*Message
-
-	for _, msg := range all {
-		// Apply from filter
-		if fromRe != nil && !fromRe.MatchString(msg.From) {
-			continue
-		}
-
-		// Search in specified fields
-		matched := false
-		if opts.SubjectOnly {
-			matched = re.MatchString(msg.Subject)
-		} else if opts.BodyOnly {
-			matched = re.MatchString(msg.Body)
-		} else {
-			// Search in both subject and body
-			matched = re.MatchString(msg.Subject) || re.MatchString(msg.Body)
-		}
-
-		if matched {
-			matches = append(matches, msg)
-		}
-	}
-
-	// Sort by priority (higher first), then timestamp (newest first).
-	sort.Slice(matches, func(i, j int) bool {
-		pi, pj := PriorityToBeads(matches[i].Priority), PriorityToBeads(matches[j].Priority)
-		if pi != pj {
-			return pi < pj
-		}
-		return matches[i].Timestamp.After(matches[j].Timestamp)
-	})
-
-	return matches, nil
-}
-
 // Count returns the total and unread message counts.
 func (m *Mailbox) Count() (total, unread int, err error) {
 	messages, err := m.List()
@@ -1268,7 +1213,33 @@ func (m *Mailbox) Append(msg *Message) error {
 	if !m.legacy {
 		return errors.New("use Router.Send() to send messages via beads")
 	}
-	return m.appendLegacy(msg)
+	if err := m.appendLegacy(msg); err != nil {
+		return err
+	}
+	if ix := m.searchIndex(); ix != nil {
+		_ = ix.Upsert(m.identity, msg) // best-effort: the index is a cache
+	}
+	return nil
+}
+
+// searchIndex returns the search index covering this mailbox, or nil when
+// the mailbox has no on-disk location to keep one.
+func (m *Mailbox) searchIndex() *SearchIndex {
+	if m.legacy {
+		if m.path == "" {
+			return nil
+		}
+		return OpenSearchIndex(filepath.Dir(m.path))
+	}
+	if m.beadsDir == "" {
+		return nil
+	}
+	return OpenSearchIndex(m.beadsDir)
+}
+
+// SearchIndex returns the on-disk search index for this mailbox's store.
+func (m *Mailbox) SearchIndex() *SearchIndex {
+	return m.searchIndex()
 }
 
 func (m *Mailbox) appendLegacy(msg *Message) error {
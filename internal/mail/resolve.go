@@ -81,6 +81,12 @@ func (r *Resolver) resolveWithVisited(address string, visited map[string]bool) (
 		return r.resolveChannel(name)
 	}
 
+	// Peer-town addresses (<peer>:<address>) - the router queues these for
+	// federation; the peer validates the local part on delivery.
+	if _, _, ok := federatedPeerIn(r.townRoot, address); ok {
+		return []Recipient{{Address: address, Type: RecipientAgent}}, nil
+	}
+
 	// Legacy prefixes (list:, announce:) - pass through
 	if strings.HasPrefix(address, "list:") || strings.HasPrefix(address, "announce:") {
 		// These are handled by existing router logic
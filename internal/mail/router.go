@@ -860,7 +860,23 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+//
+// Messages with a future NotBefore are not delivered now: they are persisted
+// as scheduled mail and sent by ReleaseDueMail once due.
 func (r *Router) Send(msg *Message) error {
+	if !msg.NotBefore.IsZero() && time.Now().Before(msg.NotBefore) {
+		if err := r.validateScheduledTarget(msg.To); err != nil {
+			return err
+		}
+		_, err := r.scheduleDelivery(msg, ScheduleKindSend, msg.From)
+		return err
+	}
+
+	// Check for peer-town address - store and forward via federation
+	if peer, local, ok := r.federatedPeer(msg.To); ok {
+		return r.queueFederated(peer, local, msg)
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -1132,6 +1148,19 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
 	}
 
+	// Apply the recipient's mailbox rules (forward, escalate, re-prioritize,
+	// suppress nudge). Rule side-effect failures never block delivery.
+	rules, err := r.applyMailRules(toIdentity, msg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if rules.archive {
+		if err := r.archiveFromRule(msg); err != nil {
+			return fmt.Errorf("archiving message by rule: %w", err)
+		}
+		return nil
+	}
+
 	// Build labels for type, from/thread/reply-to/cc
 	labels := r.buildLabels(msg)
 
@@ -1139,7 +1168,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags (see web/api.go).
 	// Let bd auto-generate the ID with the correct database prefix.
-	args := []string{"create",
+	args := []string{"create", "--json",
 		"--assignee", toIdentity,
 		"-d", msg.Body,
 	}
@@ -1176,7 +1205,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	telemetry.RecordMailMessage(context.Background(), "send", telemetry.MailMessageInfo{
 		ID:       msg.ID,
 		From:     msg.From,
@@ -1191,6 +1220,8 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("sending message: %w", err)
 	}
 
+	indexSentMessage(beadsDir, toIdentity, parseCreatedID(out), msg)
+
 	// Notify recipient if they have an active session (best-effort notification).
 	// Skip when the caller explicitly suppressed notification (--no-notify)
 	// or for self-mail (handoffs to future-self don't need present-self notified).
@@ -1209,6 +1240,93 @@ func (r *Router) sendToSingle(msg *Message) error {
 	return nil
 }
 
+// indexSentMessage adds a just-created message bead to the full-text search
+// index under mailbox (best-effort: the index is a cache).
+func indexSentMessage(beadsDir, mailbox, beadID string, msg *Message) {
+	if beadID == "" {
+		return
+	}
+	indexed := *msg
+	indexed.ID = beadID
+	if indexed.Timestamp.IsZero() {
+		indexed.Timestamp = time.Now()
+	}
+	_ = OpenSearchIndex(beadsDir).Upsert(mailbox, &indexed)
+}
+
+// parseCreatedID extracts the new bead ID from `bd create --json` output.
+// Plain-text output consisting of a single ID is accepted as well.
+func parseCreatedID(out []byte) string {
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return ""
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(trimmed), &created); err == nil {
+		return created.ID
+	}
+	if fields := strings.Fields(trimmed); len(fields) == 1 {
+		return fields[0]
+	}
+	return ""
+}
+
+// RebuildSearchIndex rebuilds the mail search index for this router's beads
+// database from every message bead (open and closed) plus the archive.
+// Returns the number of messages indexed.
+func (r *Router) RebuildSearchIndex() (int, error) {
+	beadsDir := r.resolveBeadsDir()
+	args := []string{"list",
+		"--label", "gt:message",
+		"--all",
+		"--json",
+		"--limit", "0",
+	}
+	ctx, cancel := bdReadCtx()
+	defer cancel()
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return 0, fmt.Errorf("listing messages: %w", err)
+	}
+	beadsMsgs, err := parseBeadsListOutput(out)
+	if err != nil {
+		return 0, fmt.Errorf("parsing messages: %w", err)
+	}
+
+	count := 0
+	byMailbox := make(map[string][]*Message)
+	for i := range beadsMsgs {
+		msg := beadsMsgs[i].ToMessage()
+		mailbox := beadsMsgs[i].Assignee
+		byMailbox[mailbox] = append(byMailbox[mailbox], msg)
+		count++
+	}
+
+	archivedByMailbox := make(map[string][]*Message)
+	archive := NewMailboxWithBeadsDir("", filepath.Dir(beadsDir), beadsDir)
+	archived, err := archive.ListArchived()
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("reading archive: %w", err)
+	}
+	for _, msg := range archived {
+		mailbox := AddressToIdentity(msg.To)
+		archivedByMailbox[mailbox] = append(archivedByMailbox[mailbox], msg)
+		count++
+	}
+
+	if err := OpenSearchIndex(beadsDir).Rebuild(byMailbox, archivedByMailbox); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SearchIndex returns the search index for this router's beads database.
+func (r *Router) SearchIndex() *SearchIndex {
+	return OpenSearchIndex(r.resolveBeadsDir())
+}
+
 // sendToList expands a mailing list and sends individual copies to each recipient.
 // Each recipient gets their own message copy with the same content.
 // Collects all delivery errors and reports partial failures.
@@ -1262,6 +1380,18 @@ func (r *Router) sendToQueue(msg *Message) error {
 		return err
 	}
 
+	// Apply the queue's mailbox rules before it becomes claimable.
+	rules, ruleErr := r.applyMailRules(msg.To, msg)
+	if ruleErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", ruleErr)
+	}
+	if rules.archive {
+		if err := r.archiveFromRule(msg); err != nil {
+			return fmt.Errorf("archiving queue message by rule: %w", err)
+		}
+		return nil
+	}
+
 	// Build labels for type, from/thread/reply-to/cc plus queue metadata
 	var labels []string
 	labels = append(labels, "gt:message")
@@ -1283,7 +1413,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use queue:<name> as assignee so inbox queries can filter by queue
-	args := []string{"create",
+	args := []string{"create", "--json",
 		"--assignee", msg.To, // queue:name
 		"-d", msg.Body,
 	}
@@ -1313,10 +1443,11 @@ func (r *Router) sendToQueue(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return fmt.Errorf("sending to queue %s: %w", queueName, err)
 	}
+	indexSentMessage(beadsDir, msg.To, parseCreatedID(out), msg)
 
 	// No notification for queue messages - workers poll or check on their own schedule
 
@@ -1367,7 +1498,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use announce:<name> as assignee so queries can filter by channel
-	args := []string{"create",
+	args := []string{"create", "--json",
 		"--assignee", msg.To, // announce:name
 		"-d", msg.Body,
 	}
@@ -1397,12 +1528,17 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return fmt.Errorf("sending to announce %s: %w", announceName, err)
 	}
 
-	// No notification for announce messages - readers poll or check on their own schedule
+	createdID := parseCreatedID(out)
+	indexSentMessage(beadsDir, msg.To, createdID, msg)
+
+	// No notification for announce messages - readers poll or check on their own schedule.
+	// Bridged announces are mirrored to their outbound webhooks.
+	r.dispatchWebhooks(msg.To, announceName, createdID, msg)
 
 	return nil
 }
@@ -1453,7 +1589,7 @@ func (r *Router) sendToChannel(msg *Message) error {
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use channel:<name> as assignee so queries can filter by channel
-	args := []string{"create",
+	args := []string{"create", "--json",
 		"--assignee", msg.To, // channel:name
 		"-d", msg.Body,
 	}
@@ -1483,11 +1619,17 @@ func (r *Router) sendToChannel(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return fmt.Errorf("sending to channel %s: %w", channelName, err)
 	}
 
+	createdID := parseCreatedID(out)
+	indexSentMessage(beadsDir, msg.To, createdID, msg)
+
+	// Mirror to outbound webhooks (Slack, Discord, Matrix bridges)
+	r.dispatchWebhooks(msg.To, channelName, createdID, msg)
+
 	// Enforce channel retention policy (on-write cleanup)
 	_ = b.EnforceChannelRetention(channelName)
 
@@ -0,0 +1,213 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// maxRuleForwardHops bounds how many times a message can be forwarded by
+// mailbox rules, so two mailboxes forwarding to each other cannot loop.
+const maxRuleForwardHops = 3
+
+// ruleOutcome is the result of evaluating a mailbox's rules against a message.
+// Priority and notification changes are applied to the message directly;
+// the remaining actions are side effects the router carries out.
+type ruleOutcome struct {
+	archive  bool              // file into the archive instead of the inbox
+	forwards []string          // addresses to forward a copy to
+	escalate []config.MailRule // escalate rules that matched
+	matched  []string          // names (or indexes) of matching rules
+}
+
+// evaluateMailRules runs rules in order against msg. Priority and
+// suppress_nudge actions mutate msg so later rules see the rewritten
+// priority; other actions are collected into the returned outcome.
+func evaluateMailRules(rules []config.MailRule, msg *Message) ruleOutcome {
+	var out ruleOutcome
+	for i, rule := range rules {
+		if !mailRuleMatches(rule.Match, msg) {
+			continue
+		}
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		out.matched = append(out.matched, name)
+
+		switch rule.Action {
+		case config.MailRuleArchive:
+			out.archive = true
+		case config.MailRuleForward:
+			out.forwards = append(out.forwards, rule.Target)
+		case config.MailRulePriority:
+			msg.Priority = ParsePriority(rule.Priority)
+		case config.MailRuleEscalate:
+			out.escalate = append(out.escalate, rule)
+		case config.MailRuleSuppressNudge:
+			msg.SuppressNotify = true
+		}
+
+		if rule.Stop {
+			break
+		}
+	}
+	return out
+}
+
+// mailRuleMatches reports whether every non-empty criterion in m matches msg.
+func mailRuleMatches(m config.MailRuleMatch, msg *Message) bool {
+	if m.From != "" && !matchRuleGlob(m.From, msg.From, false) &&
+		!matchRuleGlob(m.From, AddressToIdentity(msg.From), false) {
+		return false
+	}
+	if m.Subject != "" && !matchRuleGlob(m.Subject, msg.Subject, true) {
+		return false
+	}
+	if m.Priority != "" && Priority(m.Priority) != msg.Priority {
+		return false
+	}
+	if m.Type != "" && MessageType(m.Type) != msg.Type {
+		return false
+	}
+	if m.Thread != "" && !matchRuleGlob(m.Thread, msg.ThreadID, false) {
+		return false
+	}
+	return true
+}
+
+// matchRuleGlob matches s against a glob pattern where '*' matches any run
+// of characters (including '/') and '?' matches a single character.
+func matchRuleGlob(pattern, s string, foldCase bool) bool {
+	if foldCase {
+		pattern = strings.ToLower(pattern)
+		s = strings.ToLower(s)
+	}
+	if !strings.ContainsAny(pattern, "*?") {
+		return pattern == s
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+// loadMailRules returns the configured rules for a mailbox. The mailbox is
+// either a recipient identity or a queue:name address. Missing or invalid
+// messaging config yields no rules: rules must never block delivery.
+func (r *Router) loadMailRules(mailbox string) []config.MailRule {
+	if r.townRoot == "" {
+		return nil
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil || len(cfg.Rules) == 0 {
+		return nil
+	}
+	if rules, ok := cfg.Rules[mailbox]; ok {
+		return rules
+	}
+	if isQueueAddress(mailbox) {
+		return nil
+	}
+	// Rule keys are written as addresses ("mayor/", "gastown/polecats/Toast");
+	// compare on normalized identity.
+	for key, rules := range cfg.Rules {
+		if !isQueueAddress(key) && AddressToIdentity(key) == mailbox {
+			return rules
+		}
+	}
+	return nil
+}
+
+// applyMailRules evaluates the mailbox's rules and carries out forward and
+// escalate side effects. It returns the outcome so the caller can honor
+// archive. Side-effect failures are reported but do not block delivery.
+func (r *Router) applyMailRules(mailbox string, msg *Message) (ruleOutcome, error) {
+	rules := r.loadMailRules(mailbox)
+	if len(rules) == 0 {
+		return ruleOutcome{}, nil
+	}
+	out := evaluateMailRules(rules, msg)
+
+	var errs []string
+	for _, target := range out.forwards {
+		if msg.forwardHops >= maxRuleForwardHops {
+			errs = append(errs, fmt.Sprintf("forward to %s: hop limit reached", target))
+			continue
+		}
+		if AddressToIdentity(target) == mailbox || target == mailbox {
+			continue // forwarding to self is a no-op
+		}
+		fwd := *msg
+		fwd.To = target
+		fwd.ID = ""
+		fwd.CC = nil
+		fwd.forwardHops = msg.forwardHops + 1
+		if err := r.Send(&fwd); err != nil {
+			errs = append(errs, fmt.Sprintf("forward to %s: %v", target, err))
+		}
+	}
+	for _, rule := range out.escalate {
+		if err := r.escalateFromRule(mailbox, rule, msg); err != nil {
+			errs = append(errs, fmt.Sprintf("escalate: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return out, fmt.Errorf("mail rules for %s: %s", mailbox, strings.Join(errs, "; "))
+	}
+	return out, nil
+}
+
+// escalateFromRule files an escalation bead for a message matched by an
+// escalate rule.
+func (r *Router) escalateFromRule(mailbox string, rule config.MailRule, msg *Message) error {
+	severity := rule.Severity
+	if severity == "" {
+		severity = config.SeverityMedium
+	}
+	source := "mail-rule:" + mailbox
+	if rule.Name != "" {
+		source += ":" + rule.Name
+	}
+	fields := &beads.EscalationFields{
+		Severity:    severity,
+		Reason:      fmt.Sprintf("mail from %s to %s", msg.From, msg.To),
+		Source:      source,
+		EscalatedBy: msg.From,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+		RelatedBead: msg.ThreadID,
+	}
+	bd := beads.New(r.resolveBeadsDir())
+	if _, err := bd.CreateEscalationBead(msg.Subject, fields); err != nil {
+		return fmt.Errorf("creating escalation bead: %w", err)
+	}
+	return nil
+}
+
+// archiveFromRule files a message directly into the mailbox archive.
+func (r *Router) archiveFromRule(msg *Message) error {
+	mb, err := r.GetMailbox(msg.To)
+	if err != nil {
+		return err
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	msg.Read = true
+	if err := mb.appendToArchive(msg); err != nil {
+		return err
+	}
+	if ix := mb.searchIndex(); ix != nil {
+		_ = ix.MarkArchived(AddressToIdentity(msg.To), msg) // best-effort: the index is a cache
+	}
+	return nil
+}
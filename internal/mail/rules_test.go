@@ -0,0 +1,166 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMatchRuleGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		s        string
+		foldCase bool
+		want     bool
+	}{
+		{"POLECAT_DONE*", "POLECAT_DONE gastown/Toast", false, true},
+		{"polecat_done*", "POLECAT_DONE gastown/Toast", true, true},
+		{"polecat_done*", "POLECAT_DONE gastown/Toast", false, false},
+		{"gastown/polecats/*", "gastown/polecats/Toast", false, true},
+		{"*/witness", "gastown/witness", false, true},
+		{"mayor/", "mayor/", false, true},
+		{"mayor/", "deacon/", false, false},
+		{"hq-?bc", "hq-abc", false, true},
+		{"a.b", "axb", false, false},
+	}
+	for _, tt := range tests {
+		if got := matchRuleGlob(tt.pattern, tt.s, tt.foldCase); got != tt.want {
+			t.Errorf("matchRuleGlob(%q, %q, %v) = %v, want %v", tt.pattern, tt.s, tt.foldCase, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateMailRules(t *testing.T) {
+	rules := []config.MailRule{
+		{Name: "done-receipts", Match: config.MailRuleMatch{Subject: "POLECAT_DONE*"}, Action: config.MailRuleArchive, Stop: true},
+		{Name: "witness-low", Match: config.MailRuleMatch{From: "*/witness"}, Action: config.MailRulePriority, Priority: "low"},
+		{Name: "quiet-low", Match: config.MailRuleMatch{Priority: "low"}, Action: config.MailRuleSuppressNudge},
+		{Name: "fwd-tasks", Match: config.MailRuleMatch{Type: "task"}, Action: config.MailRuleForward, Target: "queue:work"},
+		{Name: "esc-thread", Match: config.MailRuleMatch{Thread: "hq-esc*"}, Action: config.MailRuleEscalate, Severity: "high"},
+	}
+
+	t.Run("stop after archive", func(t *testing.T) {
+		msg := &Message{From: "gastown/witness", Subject: "POLECAT_DONE Toast", Priority: PriorityNormal, Type: TypeTask}
+		out := evaluateMailRules(rules, msg)
+		if !out.archive {
+			t.Error("expected archive")
+		}
+		if len(out.matched) != 1 || out.matched[0] != "done-receipts" {
+			t.Errorf("matched = %v, want [done-receipts]", out.matched)
+		}
+		if msg.Priority != PriorityNormal {
+			t.Errorf("priority rewritten after stop: %s", msg.Priority)
+		}
+	})
+
+	t.Run("priority rewrite feeds later rules", func(t *testing.T) {
+		msg := &Message{From: "gastown/witness", Subject: "Patrol receipt", Priority: PriorityNormal, Type: TypeNotification}
+		out := evaluateMailRules(rules, msg)
+		if out.archive {
+			t.Error("unexpected archive")
+		}
+		if msg.Priority != PriorityLow {
+			t.Errorf("priority = %s, want low", msg.Priority)
+		}
+		if !msg.SuppressNotify {
+			t.Error("expected SuppressNotify after priority rewrite to low")
+		}
+	})
+
+	t.Run("forward and escalate collected", func(t *testing.T) {
+		msg := &Message{From: "mayor/", Subject: "Fix it", Priority: PriorityHigh, Type: TypeTask, ThreadID: "hq-esc42"}
+		out := evaluateMailRules(rules, msg)
+		if len(out.forwards) != 1 || out.forwards[0] != "queue:work" {
+			t.Errorf("forwards = %v, want [queue:work]", out.forwards)
+		}
+		if len(out.escalate) != 1 || out.escalate[0].Severity != "high" {
+			t.Errorf("escalate = %v, want one high-severity rule", out.escalate)
+		}
+		if msg.SuppressNotify {
+			t.Error("unexpected SuppressNotify")
+		}
+	})
+
+	t.Run("unnamed rules reported by index", func(t *testing.T) {
+		msg := &Message{Subject: "anything"}
+		out := evaluateMailRules([]config.MailRule{{Action: config.MailRuleSuppressNudge}}, msg)
+		if len(out.matched) != 1 || out.matched[0] != "#0" {
+			t.Errorf("matched = %v, want [#0]", out.matched)
+		}
+	})
+}
+
+func TestLoadMailRules(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := config.NewMessagingConfig()
+	cfg.Queues["work"] = config.QueueConfig{Workers: []string{"gastown/polecats/*"}}
+	cfg.Rules["mayor/"] = []config.MailRule{{Action: config.MailRuleSuppressNudge}}
+	cfg.Rules["gastown/polecats/Toast"] = []config.MailRule{{Action: config.MailRuleArchive}}
+	cfg.Rules["queue:work"] = []config.MailRule{{Action: config.MailRulePriority, Priority: "high"}}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatalf("SaveMessagingConfig: %v", err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	tests := []struct {
+		mailbox string
+		want    string
+	}{
+		{"mayor/", config.MailRuleSuppressNudge},
+		{"gastown/Toast", config.MailRuleArchive},
+		{"queue:work", config.MailRulePriority},
+		{"deacon/", ""},
+	}
+	for _, tt := range tests {
+		rules := r.loadMailRules(tt.mailbox)
+		got := ""
+		if len(rules) > 0 {
+			got = rules[0].Action
+		}
+		if got != tt.want {
+			t.Errorf("loadMailRules(%q) action = %q, want %q", tt.mailbox, got, tt.want)
+		}
+	}
+
+	if rules := NewRouterWithTownRoot(townRoot, "").loadMailRules("mayor/"); rules != nil {
+		t.Errorf("expected no rules without town root, got %v", rules)
+	}
+}
+
+func TestArchiveFromRule(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{ID: "msg-1", From: "gastown/witness", To: "mayor/", Subject: "POLECAT_DONE Toast"}
+	if err := r.archiveFromRule(msg); err != nil {
+		t.Fatalf("archiveFromRule: %v", err)
+	}
+
+	mb, _ := r.GetMailbox("mayor/")
+	archived, err := mb.ListArchived()
+	if err != nil {
+		t.Fatalf("ListArchived: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != "msg-1" || !archived[0].Read {
+		t.Fatalf("archived = %+v, want one read msg-1", archived)
+	}
+}
+
+func TestApplyMailRules_ForwardHopLimit(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := config.NewMessagingConfig()
+	cfg.Rules["mayor/"] = []config.MailRule{{Action: config.MailRuleForward, Target: "deacon/"}}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatalf("SaveMessagingConfig: %v", err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{From: "deacon/", To: "mayor/", Subject: "loop", forwardHops: maxRuleForwardHops}
+	if _, err := r.applyMailRules("mayor/", msg); err == nil {
+		t.Fatal("expected hop limit error")
+	}
+}
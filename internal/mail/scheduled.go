@@ -0,0 +1,311 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Scheduled delivery kinds.
+const (
+	// ScheduleKindSend is a message sent with a future delivery time.
+	ScheduleKindSend = "send"
+
+	// ScheduleKindSnooze is an inbox message hidden until a later time.
+	ScheduleKindSnooze = "snooze"
+)
+
+// scheduledClaimTimeout is how long a release claim may be held before it is
+// treated as abandoned (the releaser died mid-send) and requeued. Sends are
+// bounded by bd timeouts well below this.
+const scheduledClaimTimeout = 10 * time.Minute
+
+// ErrScheduledNotFound indicates no pending scheduled delivery has the given ID.
+var ErrScheduledNotFound = errors.New("scheduled message not found")
+
+// ScheduledMail is a message held back until NotBefore. Pending deliveries
+// are stored one file per message under <townRoot>/.runtime/mail_scheduled/
+// and released by the daemon heartbeat (see Router.ReleaseDueMail).
+type ScheduledMail struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	NotBefore   time.Time `json:"not_before"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	ScheduledBy string    `json:"scheduled_by,omitempty"`
+	Message     *Message  `json:"message"`
+
+	// SuppressNotify carries --no-notify through to delivery; the field is
+	// not serialized on Message itself.
+	SuppressNotify bool `json:"suppress_notify,omitempty"`
+}
+
+// scheduledDir returns the directory holding pending scheduled deliveries.
+func scheduledDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "mail_scheduled")
+}
+
+// scheduleDelivery persists msg for delivery at msg.NotBefore.
+func (r *Router) scheduleDelivery(msg *Message, kind, by string) (*ScheduledMail, error) {
+	if r.townRoot == "" {
+		return nil, fmt.Errorf("cannot schedule mail: no town root")
+	}
+	if msg.ID == "" {
+		msg.ID = GenerateID()
+	}
+	entry := &ScheduledMail{
+		ID:          "sched-" + strings.TrimPrefix(GenerateID(), "msg-"),
+		Kind:        kind,
+		NotBefore:   msg.NotBefore,
+		ScheduledAt: time.Now(),
+		ScheduledBy: by,
+		Message:     msg,
+
+		SuppressNotify: msg.SuppressNotify,
+	}
+	path := filepath.Join(scheduledDir(r.townRoot), entry.ID+".json")
+	if err := atomicfile.EnsureDirAndWriteJSON(path, entry); err != nil {
+		return nil, fmt.Errorf("persisting scheduled mail: %w", err)
+	}
+	return entry, nil
+}
+
+// validateScheduledTarget rejects addresses that could never be delivered,
+// so mistakes surface at send time rather than when the daemon releases them.
+func (r *Router) validateScheduledTarget(address string) error {
+	if _, _, ok := r.federatedPeer(address); ok {
+		return nil // validated by the peer town on delivery
+	}
+	switch {
+	case isListAddress(address):
+		_, err := r.expandList(parseListName(address))
+		return err
+	case isQueueAddress(address):
+		_, err := r.expandQueue(parseQueueName(address))
+		return err
+	case isAnnounceAddress(address):
+		_, err := r.expandAnnounce(parseAnnounceName(address))
+		return err
+	case isChannelAddress(address), isGroupAddress(address):
+		return nil // membership is resolved at delivery time
+	}
+	identity := r.resolveCrewShorthand(AddressToIdentity(address))
+	if err := r.validateRecipient(identity); err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", address, err)
+	}
+	return nil
+}
+
+// ListScheduled returns pending scheduled deliveries, soonest first.
+func (r *Router) ListScheduled() ([]*ScheduledMail, error) {
+	if r.townRoot == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(scheduledDir(r.townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*ScheduledMail
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		sm, err := readScheduled(filepath.Join(scheduledDir(r.townRoot), e.Name()))
+		if err != nil {
+			continue // skip corrupt entries rather than blocking the queue
+		}
+		out = append(out, sm)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].NotBefore.Before(out[j].NotBefore)
+	})
+	return out, nil
+}
+
+// CancelScheduled removes a pending scheduled delivery. Cancelling a snooze
+// releases the message immediately instead of dropping it.
+func (r *Router) CancelScheduled(id string) error {
+	if r.townRoot == "" || id == "" || strings.ContainsAny(id, `/\`) {
+		return ErrScheduledNotFound
+	}
+	path := filepath.Join(scheduledDir(r.townRoot), id+".json")
+	sm, err := readScheduled(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrScheduledNotFound
+		}
+		return err
+	}
+	if sm.Kind == ScheduleKindSnooze {
+		return r.releaseScheduled(path, sm)
+	}
+	return os.Remove(path)
+}
+
+// ReleaseDueMail delivers every scheduled message whose NotBefore has passed.
+// Called from the daemon heartbeat. Returns the number released; delivery
+// failures leave the entry in place to retry on the next call.
+func (r *Router) ReleaseDueMail(now time.Time) (int, error) {
+	r.requeueStaleClaims(now)
+	pending, err := r.ListScheduled()
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	var errs []string
+	for _, sm := range pending {
+		if now.Before(sm.NotBefore) {
+			break // sorted soonest first
+		}
+		path := filepath.Join(scheduledDir(r.townRoot), sm.ID+".json")
+		if err := r.releaseScheduled(path, sm); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", sm.ID, err))
+			continue
+		}
+		released++
+	}
+	if len(errs) > 0 {
+		return released, fmt.Errorf("releasing scheduled mail: %s", strings.Join(errs, "; "))
+	}
+	return released, nil
+}
+
+// requeueStaleClaims returns entries whose release claim is older than
+// scheduledClaimTimeout to the pending queue, so a releaser that died
+// between claiming and sending doesn't lose the message.
+func (r *Router) requeueStaleClaims(now time.Time) {
+	if r.townRoot == "" {
+		return
+	}
+	claims, _ := filepath.Glob(filepath.Join(scheduledDir(r.townRoot), "*.json.claimed"))
+	for _, claimed := range claims {
+		info, err := os.Stat(claimed)
+		if err != nil || now.Sub(info.ModTime()) < scheduledClaimTimeout {
+			continue
+		}
+		_ = os.Rename(claimed, strings.TrimSuffix(claimed, ".claimed"))
+	}
+}
+
+// releaseScheduled claims the entry (rename) so concurrent releasers cannot
+// deliver it twice, then sends it. On failure the claim is undone.
+func (r *Router) releaseScheduled(path string, sm *ScheduledMail) error {
+	claimed := path + ".claimed"
+	if err := os.Rename(path, claimed); err != nil {
+		if os.IsNotExist(err) {
+			return nil // another releaser got it
+		}
+		return err
+	}
+	// Rename keeps the original mtime; stamp the claim time for requeueStaleClaims.
+	now := time.Now()
+	_ = os.Chtimes(claimed, now, now)
+
+	msg := *sm.Message
+	msg.NotBefore = time.Time{}
+	msg.ID = ""
+	msg.SuppressNotify = sm.SuppressNotify
+	if err := r.Send(&msg); err != nil {
+		_ = os.Rename(claimed, path)
+		return err
+	}
+	return os.Remove(claimed)
+}
+
+// Snooze hides an inbox message until the given time: the message is
+// closed now and re-delivered to the same mailbox (same subject, body and
+// thread) when the snooze expires.
+func (r *Router) Snooze(mailbox *Mailbox, id string, until time.Time, by string) (*ScheduledMail, error) {
+	if !until.After(time.Now()) {
+		return nil, fmt.Errorf("snooze time %s is not in the future", until.Format(time.RFC3339))
+	}
+	msg, err := mailbox.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	held := *msg
+	held.NotBefore = until
+	held.Read = false
+	if held.To == "" {
+		held.To = identityToAddress(mailbox.Identity())
+	}
+	sm, err := r.scheduleDelivery(&held, ScheduleKindSnooze, by)
+	if err != nil {
+		return nil, err
+	}
+	if err := mailbox.Delete(id); err != nil && !errors.Is(err, ErrMessageNotFound) {
+		// Roll back so the message isn't delivered twice.
+		_ = os.Remove(filepath.Join(scheduledDir(r.townRoot), sm.ID+".json"))
+		return nil, fmt.Errorf("removing snoozed message from inbox: %w", err)
+	}
+	return sm, nil
+}
+
+func readScheduled(path string) (*ScheduledMail, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var sm ScheduledMail
+	if err := json.Unmarshal(data, &sm); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	if sm.Message == nil {
+		return nil, fmt.Errorf("parsing %s: missing message", filepath.Base(path))
+	}
+	return &sm, nil
+}
+
+// ParseDeliveryTime parses a delivery time for --at/--until flags.
+// Accepts RFC3339, "2006-01-02 15:04", "2006-01-02", a clock time "15:04"
+// (today, or tomorrow if already past), or a duration from now ("90m", "2h",
+// "1d").
+func ParseDeliveryTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.Add(24 * time.Hour)
+		}
+		return at, nil
+	}
+	if d, err := ParseDeliveryDelay(value); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC3339, YYYY-MM-DD HH:MM, HH:MM, or a duration like 2h)", value)
+}
+
+// ParseDeliveryDelay parses a positive delay for --in flags. Go durations
+// are accepted, plus a "d" suffix for whole days.
+func ParseDeliveryDelay(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days > 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid delay %q (want a positive duration like 30m, 2h or 1d)", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,250 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseDeliveryDelay(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30m", 30 * time.Minute, false},
+		{"2h", 2 * time.Hour, false},
+		{"1d", 24 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"0s", 0, true},
+		{"-1h", 0, true},
+		{"0d", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeliveryDelay(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDeliveryDelay(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDeliveryDelay(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDeliveryTime(t *testing.T) {
+	loc := time.FixedZone("test", 0)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, loc)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2026-03-11T09:30:00Z", time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC), false},
+		{"2026-03-11 09:30", time.Date(2026, 3, 11, 9, 30, 0, 0, loc), false},
+		{"2026-03-12", time.Date(2026, 3, 12, 0, 0, 0, 0, loc), false},
+		{"16:45", time.Date(2026, 3, 10, 16, 45, 0, 0, loc), false},
+		{"09:00", time.Date(2026, 3, 11, 9, 0, 0, 0, loc), false}, // already past today
+		{"2h", now.Add(2 * time.Hour), false},
+		{"1d", now.Add(24 * time.Hour), false},
+		{"next tuesday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeliveryTime(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDeliveryTime(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDeliveryTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSendWithNotBefore_Schedules(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	later := time.Now().Add(time.Hour)
+	msg := &Message{From: "gastown/witness", To: "mayor/", Subject: "check MR", NotBefore: later}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	pending, err := r.ListScheduled()
+	if err != nil {
+		t.Fatalf("ListScheduled: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("pending = %d, want 1", len(pending))
+	}
+	sm := pending[0]
+	if sm.Kind != ScheduleKindSend || !sm.NotBefore.Equal(later) || sm.Message.Subject != "check MR" {
+		t.Errorf("scheduled = %+v", sm)
+	}
+
+	// Nothing is due yet.
+	released, err := r.ReleaseDueMail(time.Now())
+	if err != nil || released != 0 {
+		t.Fatalf("ReleaseDueMail = %d, %v; want 0, nil", released, err)
+	}
+
+	if err := r.CancelScheduled(sm.ID); err != nil {
+		t.Fatalf("CancelScheduled: %v", err)
+	}
+	if pending, _ := r.ListScheduled(); len(pending) != 0 {
+		t.Errorf("pending after cancel = %d, want 0", len(pending))
+	}
+	if err := r.CancelScheduled(sm.ID); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("second cancel err = %v, want ErrScheduledNotFound", err)
+	}
+	if err := r.CancelScheduled("../escape"); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("path cancel err = %v, want ErrScheduledNotFound", err)
+	}
+}
+
+func TestScheduledMail_PersistsSuppressNotify(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{From: "gastown/witness", To: "mayor/", Subject: "quiet", SuppressNotify: true,
+		NotBefore: time.Now().Add(time.Hour)}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	pending, err := r.ListScheduled()
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("ListScheduled = %v, %v", pending, err)
+	}
+	if !pending[0].SuppressNotify {
+		t.Error("--no-notify lost when scheduling")
+	}
+}
+
+func TestReleaseDueMail_RequeuesStaleClaims(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	for _, subject := range []string{"stale", "fresh"} {
+		msg := &Message{From: "gastown/witness", To: "mayor/", Subject: subject, NotBefore: time.Now().Add(time.Hour)}
+		if err := r.Send(msg); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	pending, _ := r.ListScheduled()
+	if len(pending) != 2 {
+		t.Fatalf("pending = %d, want 2", len(pending))
+	}
+
+	// Simulate two releasers that died after claiming, one long ago.
+	now := time.Now()
+	for _, sm := range pending {
+		path := filepath.Join(scheduledDir(townRoot), sm.ID+".json")
+		if err := os.Rename(path, path+".claimed"); err != nil {
+			t.Fatal(err)
+		}
+		if sm.Message.Subject == "stale" {
+			old := now.Add(-2 * scheduledClaimTimeout)
+			if err := os.Chtimes(path+".claimed", old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	r.requeueStaleClaims(now)
+	pending, _ = r.ListScheduled()
+	if len(pending) != 1 || pending[0].Message.Subject != "stale" {
+		t.Errorf("pending after requeue = %+v, want only the stale claim", pending)
+	}
+}
+
+func TestSendWithNotBefore_RejectsUnknownList(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{From: "mayor/", To: "list:nobody", Subject: "x", NotBefore: time.Now().Add(time.Hour)}
+	if err := r.Send(msg); err == nil {
+		t.Fatal("expected error for unknown list")
+	}
+	if pending, _ := r.ListScheduled(); len(pending) != 0 {
+		t.Errorf("pending = %d, want 0", len(pending))
+	}
+}
+
+func TestReleaseDueMail(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a bash bd stub")
+	}
+
+	tmpDir := t.TempDir()
+	townRoot := filepath.Join(tmpDir, "town")
+	townBeadsDir := filepath.Join(townRoot, ".beads")
+	for _, dir := range []string{filepath.Join(townRoot, "mayor"), townBeadsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townBeadsDir, ".gt-types-configured"), []byte(beads.TypeConfigSentinelValue()+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	binDir := filepath.Join(tmpDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(tmpDir, "bd.log")
+	script := `#!/usr/bin/env bash
+echo "$*" >> "` + logPath + `"
+if [[ "${1:-}" == "create" ]]; then
+  echo "hq-released-1"
+  exit 0
+fi
+if [[ "${1:-}" == "list" ]]; then
+  echo "[]"
+fi
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	due := &Message{From: "gastown/witness", To: "mayor/", Subject: "due now", SuppressNotify: true,
+		NotBefore: time.Now().Add(time.Minute)}
+	notDue := &Message{From: "gastown/witness", To: "mayor/", Subject: "due later", SuppressNotify: true,
+		NotBefore: time.Now().Add(time.Hour)}
+	for _, msg := range []*Message{due, notDue} {
+		if err := r.Send(msg); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	released, err := r.ReleaseDueMail(time.Now().Add(2 * time.Minute))
+	if err != nil {
+		t.Fatalf("ReleaseDueMail: %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("released = %d, want 1", released)
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("reading bd log: %v", err)
+	}
+	if !strings.Contains(string(log), "due now") || strings.Contains(string(log), "due later") {
+		t.Errorf("bd calls = %s, want only the due message created", log)
+	}
+
+	pending, _ := r.ListScheduled()
+	if len(pending) != 1 || pending[0].Message.Subject != "due later" {
+		t.Errorf("pending = %+v, want only 'due later'", pending)
+	}
+}
@@ -0,0 +1,309 @@
+package mail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchQuery is a parsed mail search query.
+//
+// Syntax (all parts are ANDed):
+//
+//	word              message contains the term (subject or body)
+//	word*             message contains a term starting with "word"
+//	"exact phrase"    message contains the phrase (punctuation-insensitive)
+//	from:addr         sender contains addr (case-insensitive)
+//	to:addr           recipient contains addr
+//	thread:id         thread ID equals id
+//	subject:word      term or "phrase" must appear in the subject
+//	after:DATE        sent at or after DATE
+//	before:DATE       sent before DATE
+//	date:A..B         sent within [A, B)
+//	is:unread|read|archived
+//
+// DATE is YYYY-MM-DD, RFC3339, or a relative age such as 2h or 7d.
+type SearchQuery struct {
+	Terms          []string
+	Prefixes       []string
+	Phrases        []string
+	SubjectTerms   []string
+	SubjectPhrases []string
+	From           string
+	To             string
+	Thread         string
+	After          time.Time
+	Before         time.Time
+	Unread         bool
+	ReadOnly       bool
+	Archived       bool
+
+	// SubjectOnly and BodyOnly restrict bare terms and phrases to one field.
+	SubjectOnly bool
+	BodyOnly    bool
+}
+
+// ParseSearchQuery parses the mail search query syntax.
+func ParseSearchQuery(input string) (*SearchQuery, error) {
+	return parseSearchQueryAt(input, time.Now())
+}
+
+func parseSearchQueryAt(input string, now time.Time) (*SearchQuery, error) {
+	q := &SearchQuery{}
+	parts, err := splitQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range parts {
+		if part.quoted {
+			q.addPhrase(part.text, false)
+			continue
+		}
+		key, value, hasKey := strings.Cut(part.text, ":")
+		if !hasKey || value == "" {
+			q.addTerm(part.text, false)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "from":
+			q.From = strings.ToLower(value)
+		case "to":
+			q.To = strings.ToLower(value)
+		case "thread":
+			q.Thread = value
+		case "subject":
+			if part.valueQuoted {
+				q.addPhrase(value, true)
+			} else {
+				q.addTerm(value, true)
+			}
+		case "after":
+			t, err := parseSearchDate(value, now)
+			if err != nil {
+				return nil, err
+			}
+			q.After = t
+		case "before":
+			t, err := parseSearchDate(value, now)
+			if err != nil {
+				return nil, err
+			}
+			q.Before = t
+		case "date":
+			from, to, isRange := strings.Cut(value, "..")
+			if !isRange {
+				// A single day: [day, day+24h)
+				t, err := parseSearchDate(value, now)
+				if err != nil {
+					return nil, err
+				}
+				q.After, q.Before = t, t.Add(24*time.Hour)
+				continue
+			}
+			if from != "" {
+				if q.After, err = parseSearchDate(from, now); err != nil {
+					return nil, err
+				}
+			}
+			if to != "" {
+				if q.Before, err = parseSearchDate(to, now); err != nil {
+					return nil, err
+				}
+			}
+		case "is":
+			switch strings.ToLower(value) {
+			case "unread":
+				q.Unread = true
+			case "read":
+				q.ReadOnly = true
+			case "archived":
+				q.Archived = true
+			default:
+				return nil, fmt.Errorf("unknown is: value %q (want unread, read or archived)", value)
+			}
+		default:
+			// Not a known operator (e.g. "http://x" or "note:"), treat as text.
+			q.addTerm(part.text, false)
+		}
+	}
+	return q, nil
+}
+
+func (q *SearchQuery) addTerm(text string, subject bool) {
+	if strings.HasSuffix(text, "*") && !subject {
+		if prefix := strings.Join(tokenize(strings.TrimSuffix(text, "*")), " "); prefix != "" && !strings.Contains(prefix, " ") {
+			q.Prefixes = append(q.Prefixes, prefix)
+			return
+		}
+	}
+	tokens := tokenize(text)
+	if len(tokens) > 1 {
+		// "gastown/witness" tokenizes to two terms: match them as a phrase.
+		q.addPhrase(text, subject)
+		return
+	}
+	for _, t := range tokens {
+		if subject {
+			q.SubjectTerms = append(q.SubjectTerms, t)
+		} else {
+			q.Terms = append(q.Terms, t)
+		}
+	}
+}
+
+func (q *SearchQuery) addPhrase(text string, subject bool) {
+	norm := normalizeText(text)
+	if strings.TrimSpace(norm) == "" {
+		return
+	}
+	if subject {
+		q.SubjectPhrases = append(q.SubjectPhrases, norm)
+	} else {
+		q.Phrases = append(q.Phrases, norm)
+	}
+}
+
+// postingTerms returns the exact terms every match must contain, used to
+// narrow candidates through the inverted index.
+func (q *SearchQuery) postingTerms() []string {
+	terms := append([]string{}, q.Terms...)
+	terms = append(terms, q.SubjectTerms...)
+	for _, p := range append(append([]string{}, q.Phrases...), q.SubjectPhrases...) {
+		terms = append(terms, strings.Fields(p)...)
+	}
+	return terms
+}
+
+// matches verifies every query constraint against a candidate document.
+func (q *SearchQuery) matches(doc *indexDoc) bool {
+	if q.From != "" && !strings.Contains(strings.ToLower(doc.From), q.From) {
+		return false
+	}
+	if q.To != "" && !strings.Contains(strings.ToLower(doc.To), q.To) &&
+		!strings.Contains(strings.ToLower(doc.Mailbox), q.To) {
+		return false
+	}
+	if q.Thread != "" && doc.ThreadID != q.Thread {
+		return false
+	}
+	if !q.After.IsZero() && doc.Timestamp.Before(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && !doc.Timestamp.Before(q.Before) {
+		return false
+	}
+	if q.Unread && doc.Read {
+		return false
+	}
+	if q.ReadOnly && !doc.Read {
+		return false
+	}
+	if q.Archived && !doc.Archived {
+		return false
+	}
+
+	subject := normalizeText(doc.Subject)
+	body := normalizeText(doc.Body)
+	text := subject + body
+	switch {
+	case q.SubjectOnly:
+		text = subject
+	case q.BodyOnly:
+		text = body
+	}
+
+	for _, t := range q.Terms {
+		if !strings.Contains(text, " "+t+" ") {
+			return false
+		}
+	}
+	for _, p := range q.Prefixes {
+		if !strings.Contains(text, " "+p) {
+			return false
+		}
+	}
+	for _, p := range q.Phrases {
+		if !strings.Contains(text, p) {
+			return false
+		}
+	}
+	for _, t := range q.SubjectTerms {
+		if !strings.Contains(subject, " "+t+" ") {
+			return false
+		}
+	}
+	for _, p := range q.SubjectPhrases {
+		if !strings.Contains(subject, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// queryPart is one whitespace-separated piece of a query.
+type queryPart struct {
+	text        string
+	quoted      bool // the whole part was quoted: "exact phrase"
+	valueQuoted bool // the operator value was quoted: subject:"exact phrase"
+}
+
+// splitQuery splits on whitespace, keeping double-quoted runs together.
+func splitQuery(input string) ([]queryPart, error) {
+	var parts []queryPart
+	var cur strings.Builder
+	inQuote := false
+	quoteStart := -1
+	flush := func() {
+		if cur.Len() == 0 {
+			return
+		}
+		text := cur.String()
+		p := queryPart{text: text}
+		if quoteStart == 0 {
+			p.quoted = true
+		} else if quoteStart > 0 {
+			p.valueQuoted = true
+		}
+		parts = append(parts, p)
+		cur.Reset()
+		quoteStart = -1
+	}
+	for _, r := range input {
+		switch {
+		case r == '"':
+			if !inQuote && quoteStart < 0 {
+				quoteStart = cur.Len()
+			}
+			inQuote = !inQuote
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuote:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in search query")
+	}
+	flush()
+	return parts, nil
+}
+
+// parseSearchDate parses YYYY-MM-DD, RFC3339, or a relative age ("2h", "7d").
+func parseSearchDate(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return now.Add(-time.Duration(days) * 24 * time.Hour), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD, RFC3339, or an age like 2h or 7d)", value)
+}
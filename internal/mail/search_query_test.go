@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	q, err := parseSearchQueryAt(`deploy* from:Witness to:mayor/ thread:hq-1 "merge failed" subject:"POLECAT DONE" after:7d before:2026-03-09 is:unread`, now)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(q.Prefixes) != 1 || q.Prefixes[0] != "deploy" {
+		t.Errorf("Prefixes = %v", q.Prefixes)
+	}
+	if q.From != "witness" || q.To != "mayor/" || q.Thread != "hq-1" {
+		t.Errorf("filters = from %q to %q thread %q", q.From, q.To, q.Thread)
+	}
+	if len(q.Phrases) != 1 || q.Phrases[0] != " merge failed " {
+		t.Errorf("Phrases = %q", q.Phrases)
+	}
+	if len(q.SubjectPhrases) != 1 || q.SubjectPhrases[0] != " polecat done " {
+		t.Errorf("SubjectPhrases = %q", q.SubjectPhrases)
+	}
+	if want := now.Add(-7 * 24 * time.Hour); !q.After.Equal(want) {
+		t.Errorf("After = %v, want %v", q.After, want)
+	}
+	if q.Before.Format("2006-01-02") != "2026-03-09" {
+		t.Errorf("Before = %v", q.Before)
+	}
+	if !q.Unread {
+		t.Error("expected Unread")
+	}
+}
+
+func TestParseSearchQuery_AddressTermsBecomePhrases(t *testing.T) {
+	q, err := ParseSearchQuery("gastown/witness")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(q.Terms) != 0 || len(q.Phrases) != 1 || q.Phrases[0] != " gastown witness " {
+		t.Errorf("Terms = %v, Phrases = %q", q.Terms, q.Phrases)
+	}
+}
+
+func TestParseSearchQuery_Errors(t *testing.T) {
+	for _, input := range []string{`"unterminated`, "after:yesterday", "is:starred", "date:2026-13-01"} {
+		if _, err := ParseSearchQuery(input); err == nil {
+			t.Errorf("ParseSearchQuery(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseSearchQuery_UnknownOperatorIsText(t *testing.T) {
+	q, err := ParseSearchQuery("note:important")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(q.Phrases) != 1 || q.Phrases[0] != " note important " {
+		t.Errorf("Phrases = %q", q.Phrases)
+	}
+}
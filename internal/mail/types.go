@@ -133,10 +133,24 @@ type Message struct {
 	// DeliveryAckedAt is when receipt was acknowledged.
 	DeliveryAckedAt *time.Time `json:"delivery_acked_at,omitempty"`
 
+	// NotBefore, if set in the future, holds the message back: Router.Send
+	// persists it as scheduled mail and the daemon delivers it once due.
+	NotBefore time.Time `json:"not_before,omitzero"`
+
 	// SuppressNotify tells the router to skip all recipient notification
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
 	// In-memory only — not serialized.
 	SuppressNotify bool `json:"-"`
+
+	// RelayedFrom names the external chat system a message was relayed in
+	// from (e.g. "slack"). The router does not mirror relayed messages back
+	// out to outbound webhooks, which would echo them to their source.
+	// In-memory only — not serialized.
+	RelayedFrom string `json:"-"`
+
+	// forwardHops counts how many times mailbox rules have forwarded this
+	// message. In-memory only; guards against forwarding loops.
+	forwardHops int
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
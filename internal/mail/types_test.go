@@ -2,6 +2,7 @@ package mail
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -969,3 +970,13 @@ func TestNewMessageFanOutCopiesGetUniqueIDs(t *testing.T) {
 		t.Error("copy with empty ID should fail validation before sendToSingle regenerates it")
 	}
 }
+
+func TestMessageJSONOmitsZeroNotBefore(t *testing.T) {
+	data, err := json.Marshal(&Message{ID: "msg-1", Subject: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "not_before") {
+		t.Errorf("zero NotBefore serialized: %s", data)
+	}
+}
@@ -0,0 +1,242 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// WebhookDeadLetterFile is the JSONL file (under <townRoot>/.runtime/)
+// receiving webhook payloads that could not be delivered.
+const WebhookDeadLetterFile = "webhook_dead_letter.jsonl"
+
+// defaultWebhookAttempts is used when a sink does not set MaxAttempts.
+const defaultWebhookAttempts = 3
+
+// webhookBackoff is the delay before the second attempt; it doubles on
+// each retry. A variable so tests can shorten it.
+var webhookBackoff = time.Second
+
+// webhookTimeout bounds each HTTP attempt.
+const webhookTimeout = 10 * time.Second
+
+// webhookClient is the HTTP client used for outbound webhooks.
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// WebhookEvent is the data a webhook payload is rendered from.
+type WebhookEvent struct {
+	Address   string    `json:"address"` // channel:<name> or announce:<name>
+	Name      string    `json:"name"`    // channel or announce name
+	ID        string    `json:"id,omitempty"`
+	ThreadID  string    `json:"thread_id,omitempty"`
+	From      string    `json:"from"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body,omitempty"`
+	Priority  Priority  `json:"priority"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// webhookDeadLetter is one line of the dead-letter file.
+type webhookDeadLetter struct {
+	Time     time.Time `json:"time"`
+	Address  string    `json:"address"`
+	Sink     string    `json:"sink,omitempty"`
+	URL      string    `json:"url"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	Payload  string    `json:"payload"`
+}
+
+// loadWebhookSinks returns the sinks configured for a channel or announce
+// address. Missing or invalid config yields none: webhooks never block mail.
+func (r *Router) loadWebhookSinks(address string) []config.WebhookSink {
+	if r.townRoot == "" {
+		return nil
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		return nil
+	}
+	return cfg.Webhooks[address]
+}
+
+// dispatchWebhooks posts msg to every sink configured for address. Delivery
+// is asynchronous (tracked by notifyWg, like notifications) so sending never
+// waits on external chat services. Failed deliveries land in the dead-letter
+// file.
+func (r *Router) dispatchWebhooks(address, name, id string, msg *Message) {
+	if msg.RelayedFrom != "" {
+		return // came in from a bridge; sending it out again would echo it
+	}
+	sinks := r.loadWebhookSinks(address)
+	if len(sinks) == 0 {
+		return
+	}
+	ev := WebhookEvent{
+		Address:   address,
+		Name:      name,
+		ID:        id,
+		ThreadID:  msg.ThreadID,
+		From:      msg.From,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		Priority:  msg.Priority,
+		Timestamp: msg.Timestamp,
+	}
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	for _, sink := range sinks {
+		r.notifyWg.Add(1)
+		go func() {
+			defer r.notifyWg.Done()
+			r.deliverWebhook(sink, ev)
+		}()
+	}
+}
+
+// deliverWebhook renders and posts one event to one sink, retrying with
+// exponential backoff, and dead-letters the payload if every attempt fails.
+func (r *Router) deliverWebhook(sink config.WebhookSink, ev WebhookEvent) {
+	payload, err := renderWebhookPayload(sink, ev)
+	if err != nil {
+		r.deadLetterWebhook(sink, ev.Address, 0, err, "")
+		return
+	}
+
+	attempts := sink.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultWebhookAttempts
+	}
+	delay := webhookBackoff
+	for i := 1; i <= attempts; i++ {
+		err = postWebhook(sink, payload)
+		if err == nil {
+			return
+		}
+		if i < attempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	r.deadLetterWebhook(sink, ev.Address, attempts, err, string(payload))
+}
+
+// postWebhook makes a single delivery attempt. Any non-2xx status is an error.
+func postWebhook(sink config.WebhookSink, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-mail-webhook")
+	for k, v := range sink.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// renderWebhookPayload builds the request body for a sink: the sink's
+// template if set, otherwise its built-in format.
+func renderWebhookPayload(sink config.WebhookSink, ev WebhookEvent) ([]byte, error) {
+	if sink.Template != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).Parse(sink.Template)
+		if err != nil {
+			return nil, fmt.Errorf("parsing webhook template: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, ev); err != nil {
+			return nil, fmt.Errorf("rendering webhook template: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	text := webhookText(ev)
+	switch sink.Format {
+	case config.WebhookFormatSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case config.WebhookFormatDiscord:
+		return json.Marshal(map[string]string{"content": text})
+	case config.WebhookFormatMatrix:
+		return json.Marshal(map[string]string{"msgtype": "m.text", "body": text})
+	default:
+		return json.Marshal(ev)
+	}
+}
+
+// webhookText is the human-readable rendering used by chat formats. The
+// thread ID is included so replies can be routed back via the inbound
+// endpoint.
+func webhookText(ev WebhookEvent) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s — from %s", ev.Address, ev.Subject, ev.From)
+	if ev.Body != "" {
+		sb.WriteString("\n")
+		sb.WriteString(ev.Body)
+	}
+	if ev.ThreadID != "" {
+		fmt.Fprintf(&sb, "\n(thread %s)", ev.ThreadID)
+	}
+	return sb.String()
+}
+
+// deadLetterWebhook appends an undeliverable payload to the dead-letter file.
+func (r *Router) deadLetterWebhook(sink config.WebhookSink, address string, attempts int, cause error, payload string) {
+	entry := webhookDeadLetter{
+		Time:     time.Now(),
+		Address:  address,
+		Sink:     sink.Name,
+		URL:      sink.URL,
+		Attempts: attempts,
+		Error:    cause.Error(),
+		Payload:  payload,
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := WebhookDeadLetterPath(r.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: webhook to %s failed and could not be dead-lettered: %v\n", address, cause)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: webhook to %s failed and could not be dead-lettered: %v\n", address, cause)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.Write(append(line, '\n'))
+}
+
+// WebhookDeadLetterPath returns the dead-letter file path for a town.
+func WebhookDeadLetterPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, WebhookDeadLetterFile)
+}
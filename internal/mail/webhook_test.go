@@ -0,0 +1,202 @@
+package mail
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRenderWebhookPayload(t *testing.T) {
+	ev := WebhookEvent{
+		Address:  "channel:alerts",
+		Name:     "alerts",
+		ID:       "hq-1",
+		ThreadID: "thread-abc",
+		From:     "gastown/witness",
+		Subject:  "Merge failed",
+		Body:     "gt-123 conflicts",
+	}
+
+	tests := []struct {
+		name string
+		sink config.WebhookSink
+		key  string
+	}{
+		{"slack", config.WebhookSink{Format: config.WebhookFormatSlack}, "text"},
+		{"discord", config.WebhookSink{Format: config.WebhookFormatDiscord}, "content"},
+		{"matrix", config.WebhookSink{Format: config.WebhookFormatMatrix}, "body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := renderWebhookPayload(tt.sink, ev)
+			if err != nil {
+				t.Fatalf("renderWebhookPayload: %v", err)
+			}
+			var got map[string]string
+			if err := json.Unmarshal(payload, &got); err != nil {
+				t.Fatalf("payload is not JSON: %v\n%s", err, payload)
+			}
+			text := got[tt.key]
+			for _, want := range []string{"channel:alerts", "Merge failed", "gastown/witness", "gt-123 conflicts", "thread-abc"} {
+				if !strings.Contains(text, want) {
+					t.Errorf("%s = %q, missing %q", tt.key, text, want)
+				}
+			}
+		})
+	}
+
+	t.Run("json default", func(t *testing.T) {
+		payload, err := renderWebhookPayload(config.WebhookSink{}, ev)
+		if err != nil {
+			t.Fatalf("renderWebhookPayload: %v", err)
+		}
+		var got WebhookEvent
+		if err := json.Unmarshal(payload, &got); err != nil {
+			t.Fatalf("payload is not JSON: %v", err)
+		}
+		if got.ID != "hq-1" || got.ThreadID != "thread-abc" || got.Name != "alerts" {
+			t.Errorf("payload = %+v", got)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		sink := config.WebhookSink{Template: `{"title": {{json .Subject}}, "thread": "{{.ThreadID}}"}`}
+		payload, err := renderWebhookPayload(sink, WebhookEvent{Subject: `say "hi"`, ThreadID: "t-1"})
+		if err != nil {
+			t.Fatalf("renderWebhookPayload: %v", err)
+		}
+		var got map[string]string
+		if err := json.Unmarshal(payload, &got); err != nil {
+			t.Fatalf("payload is not JSON: %v\n%s", err, payload)
+		}
+		if got["title"] != `say "hi"` || got["thread"] != "t-1" {
+			t.Errorf("payload = %v", got)
+		}
+	})
+
+	t.Run("bad template", func(t *testing.T) {
+		if _, err := renderWebhookPayload(config.WebhookSink{Template: "{{.Nope"}, ev); err == nil {
+			t.Error("expected template parse error")
+		}
+	})
+}
+
+func shortWebhookBackoff(t *testing.T) {
+	t.Helper()
+	old := webhookBackoff
+	webhookBackoff = time.Millisecond
+	t.Cleanup(func() { webhookBackoff = old })
+}
+
+func TestDeliverWebhook_RetriesThenSucceeds(t *testing.T) {
+	shortWebhookBackoff(t)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("missing sink header, got %q", r.Header.Get("Authorization"))
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	sink := config.WebhookSink{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer tok"}}
+	r.deliverWebhook(sink, WebhookEvent{Address: "channel:alerts", Subject: "hi"})
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+	if _, err := os.Stat(WebhookDeadLetterPath(townRoot)); !os.IsNotExist(err) {
+		t.Errorf("dead-letter file written after eventual success (err=%v)", err)
+	}
+}
+
+func TestDeliverWebhook_DeadLetters(t *testing.T) {
+	shortWebhookBackoff(t)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	sink := config.WebhookSink{Name: "slack-ops", URL: srv.URL, Format: config.WebhookFormatSlack, MaxAttempts: 2}
+	r.deliverWebhook(sink, WebhookEvent{Address: "announce:status", Subject: "down"})
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+	data, err := os.ReadFile(WebhookDeadLetterPath(townRoot))
+	if err != nil {
+		t.Fatalf("reading dead-letter file: %v", err)
+	}
+	var entry webhookDeadLetter
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &entry); err != nil {
+		t.Fatalf("parsing dead-letter entry: %v\n%s", err, data)
+	}
+	if entry.Sink != "slack-ops" || entry.Address != "announce:status" || entry.Attempts != 2 {
+		t.Errorf("entry = %+v", entry)
+	}
+	if !strings.Contains(entry.Error, "500") || !strings.Contains(entry.Payload, "down") {
+		t.Errorf("entry error/payload = %q / %q", entry.Error, entry.Payload)
+	}
+}
+
+func TestDispatchWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	townRoot := t.TempDir()
+	cfg := config.NewMessagingConfig()
+	cfg.Webhooks["channel:alerts"] = []config.WebhookSink{
+		{URL: srv.URL, Format: config.WebhookFormatSlack},
+		{URL: srv.URL, Format: config.WebhookFormatDiscord},
+	}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatalf("SaveMessagingConfig: %v", err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{From: "mayor/", To: "channel:alerts", Subject: "Deploy", ThreadID: "thread-1"}
+	r.dispatchWebhooks("channel:alerts", "alerts", "hq-9", msg)
+	r.dispatchWebhooks("channel:other", "other", "hq-10", msg) // not bridged
+	relayed := *msg
+	relayed.RelayedFrom = "slack"
+	r.dispatchWebhooks("channel:alerts", "alerts", "hq-11", &relayed) // would echo
+	r.WaitPendingNotifications()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("received %d webhook posts, want 2: %v", len(bodies), bodies)
+	}
+	joined := strings.Join(bodies, "\n")
+	if !strings.Contains(joined, `"text"`) || !strings.Contains(joined, `"content"`) {
+		t.Errorf("expected slack and discord payloads, got %s", joined)
+	}
+}
@@ -0,0 +1,122 @@
+// Package memory ranks the agent memories stored by gt remember and picks
+// the ones gt prime injects.
+//
+// Memories live in the beads kv store as memory.<type>.<key>. The value is
+// either plain text (legacy, town-wide) or a JSON record carrying the text,
+// its scope and when it was stored. A memory applies to a session when its
+// scope matches the session's rig, role and hooked bead; applicable
+// memories are ranked by relevance to the hooked bead, type, recency and
+// usage, and injected best-first until the token budget runs out.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scope limits where a memory is injected. The zero Scope is town-wide;
+// each set field must match, so {Rig: "gastown", Role: "crew"} applies only
+// to gastown crew.
+type Scope struct {
+	Rig    string   `json:"rig,omitempty"`
+	Role   string   `json:"role,omitempty"`
+	Labels []string `json:"labels,omitempty"` // any one label on the hooked bead
+}
+
+// IsTown reports whether the scope is town-wide.
+func (s Scope) IsTown() bool {
+	return s.Rig == "" && s.Role == "" && len(s.Labels) == 0
+}
+
+func (s Scope) String() string {
+	if s.IsTown() {
+		return "town"
+	}
+	var parts []string
+	if s.Rig != "" {
+		parts = append(parts, "rig:"+s.Rig)
+	}
+	if s.Role != "" {
+		parts = append(parts, "role:"+s.Role)
+	}
+	if len(s.Labels) > 0 {
+		parts = append(parts, "label:"+strings.Join(s.Labels, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Memory is one stored memory.
+type Memory struct {
+	Key      string // full kv key, e.g. memory.feedback.dont-mock-db
+	Type     string
+	ShortKey string
+	Text     string
+	Scope    Scope
+	Created  time.Time // zero for legacy memories
+}
+
+// record is the stored form of a memory value.
+type record struct {
+	Text    string    `json:"text"`
+	Scope   Scope     `json:"scope,omitempty"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+// EncodeValue returns the kv value for a memory.
+func EncodeValue(text string, scope Scope, created time.Time) (string, error) {
+	data, err := json.Marshal(record{Text: text, Scope: scope, Created: created.UTC()})
+	if err != nil {
+		return "", fmt.Errorf("encoding memory: %w", err)
+	}
+	return string(data), nil
+}
+
+// DecodeValue reads a kv value. Values that aren't a memory record are
+// legacy plain-text, town-wide memories.
+func DecodeValue(value string) (text string, scope Scope, created time.Time) {
+	if strings.HasPrefix(value, "{") {
+		var r record
+		if err := json.Unmarshal([]byte(value), &r); err == nil && r.Text != "" {
+			return r.Text, r.Scope, r.Created
+		}
+	}
+	return value, Scope{}, time.Time{}
+}
+
+// Context is the session memories are chosen for.
+type Context struct {
+	Rig  string
+	Role string
+	Bead *Bead // the hooked bead, or nil
+}
+
+// Bead is the part of the hooked bead that memories are ranked against.
+type Bead struct {
+	ID          string
+	Title       string
+	Description string
+	Labels      []string
+}
+
+// applies reports whether a memory with scope s applies in ctx, and if not,
+// why not.
+func (s Scope) applies(ctx Context) (bool, string) {
+	if s.Rig != "" && s.Rig != ctx.Rig {
+		return false, fmt.Sprintf("scoped to rig %s", s.Rig)
+	}
+	if s.Role != "" && s.Role != ctx.Role {
+		return false, fmt.Sprintf("scoped to role %s", s.Role)
+	}
+	if len(s.Labels) > 0 {
+		if ctx.Bead == nil {
+			return false, fmt.Sprintf("scoped to label %s, no hooked bead", strings.Join(s.Labels, ","))
+		}
+		if !slices.ContainsFunc(s.Labels, func(l string) bool { return slices.Contains(ctx.Bead.Labels, l) }) {
+			return false, fmt.Sprintf("scoped to label %s, not on %s", strings.Join(s.Labels, ","), ctx.Bead.ID)
+		}
+	}
+	return true, ""
+}
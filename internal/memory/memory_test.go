@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeValue(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	scope := Scope{Rig: "gastown", Role: "polecat", Labels: []string{"ui"}}
+	v, err := EncodeValue("check storybook", scope, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, gotScope, gotCreated := DecodeValue(v)
+	if text != "check storybook" || gotScope.String() != scope.String() || !gotCreated.Equal(created) {
+		t.Errorf("DecodeValue = %q, %v, %v", text, gotScope, gotCreated)
+	}
+
+	for _, legacy := range []string{"plain note", "{not json", `{"other":"object"}`} {
+		text, scope, created := DecodeValue(legacy)
+		if text != legacy || !scope.IsTown() || !created.IsZero() {
+			t.Errorf("DecodeValue(%q) = %q, %v, %v; want legacy town memory", legacy, text, scope, created)
+		}
+	}
+}
+
+func TestScopeApplies(t *testing.T) {
+	bead := &Bead{ID: "gt-1", Labels: []string{"ui", "p1"}}
+	tests := []struct {
+		scope Scope
+		ctx   Context
+		want  bool
+	}{
+		{Scope{}, Context{}, true},
+		{Scope{Rig: "gastown"}, Context{Rig: "gastown"}, true},
+		{Scope{Rig: "gastown"}, Context{Rig: "beads"}, false},
+		{Scope{Role: "crew"}, Context{Role: "polecat"}, false},
+		{Scope{Labels: []string{"api", "ui"}}, Context{Bead: bead}, true},
+		{Scope{Labels: []string{"api"}}, Context{Bead: bead}, false},
+		{Scope{Labels: []string{"ui"}}, Context{}, false},
+		{Scope{Rig: "gastown", Role: "polecat"}, Context{Rig: "gastown", Role: "crew"}, false},
+	}
+	for _, tt := range tests {
+		got, why := tt.scope.applies(tt.ctx)
+		if got != tt.want {
+			t.Errorf("%v applies in %+v = %v (%s), want %v", tt.scope, tt.ctx, got, why, tt.want)
+		}
+		if !got && why == "" {
+			t.Errorf("%v: no reason given for skipping", tt.scope)
+		}
+	}
+}
+
+func TestRankRelevanceAndScope(t *testing.T) {
+	now := time.Now()
+	mems := []Memory{
+		{Key: "memory.general.dolt", Type: "general", ShortKey: "dolt", Text: "dolt server restarts drop open transactions"},
+		{Key: "memory.general.storybook", Type: "general", ShortKey: "storybook", Text: "storybook snapshots must be regenerated after button changes"},
+		{Key: "memory.general.crew-only", Type: "general", ShortKey: "crew-only", Text: "crew push directly", Scope: Scope{Role: "crew"}},
+	}
+	ctx := Context{Role: "polecat", Bead: &Bead{ID: "gt-9", Title: "Fix button styles", Description: "storybook shows wrong padding"}}
+
+	ds := Rank(mems, ctx, nil, 0, now)
+	if len(ds) != 3 {
+		t.Fatalf("got %d decisions", len(ds))
+	}
+	if ds[0].Memory.ShortKey != "storybook" || !ds[0].Injected || ds[0].Relevance != 1 {
+		t.Errorf("first decision = %+v, want storybook injected with full relevance", ds[0])
+	}
+	if !strings.Contains(ds[0].Reason, "gt-9") {
+		t.Errorf("reason %q should mention the bead", ds[0].Reason)
+	}
+	if ds[2].Memory.ShortKey != "crew-only" || ds[2].Injected || ds[2].Reason != "scoped to role crew" {
+		t.Errorf("last decision = %+v, want crew-only out of scope", ds[2])
+	}
+}
+
+func TestRankBudget(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	mems := []Memory{
+		{Key: "memory.feedback.short", Type: "feedback", ShortKey: "short", Text: "short rule"},
+		{Key: "memory.general.long", Type: "general", ShortKey: "long", Text: long},
+		{Key: "memory.general.tiny", Type: "general", ShortKey: "tiny", Text: "tiny"},
+	}
+	ds := Rank(mems, Context{}, nil, 20, time.Now())
+	injected := map[string]bool{}
+	for _, d := range ds {
+		injected[d.Memory.ShortKey] = d.Injected
+		if d.Memory.ShortKey == "long" && !strings.HasPrefix(d.Reason, "over budget") {
+			t.Errorf("long reason = %q", d.Reason)
+		}
+	}
+	if !injected["short"] || injected["long"] || !injected["tiny"] {
+		t.Errorf("injected = %v, want short and tiny only", injected)
+	}
+}
+
+func TestRankRecency(t *testing.T) {
+	now := time.Now()
+	mems := []Memory{
+		{Key: "memory.general.a", Type: "general", ShortKey: "a", Text: "alpha", Created: now.Add(-90 * 24 * time.Hour)},
+		{Key: "memory.general.b", Type: "general", ShortKey: "b", Text: "beta", Created: now.Add(-time.Hour)},
+	}
+	ds := Rank(mems, Context{}, nil, 0, now)
+	if ds[0].Memory.ShortKey != "b" || ds[0].Recency < 0.99 {
+		t.Errorf("first = %+v, want recently stored b", ds[0])
+	}
+	if ds[1].Recency > 0.2 {
+		t.Errorf("90-day-old recency = %.2f", ds[1].Recency)
+	}
+}
+
+func TestRankUsage(t *testing.T) {
+	now := time.Now()
+	mems := []Memory{
+		{Key: "memory.general.a", Type: "general", ShortKey: "a", Text: "alpha", Created: now},
+		{Key: "memory.general.b", Type: "general", ShortKey: "b", Text: "beta", Created: now},
+	}
+	usage := Usage{"memory.general.b": {Count: 1000, Weight: 1000, LastUsed: now}}
+	ds := Rank(mems, Context{}, usage, 0, now)
+	if ds[0].Memory.ShortKey != "b" || ds[0].Usage < 0.99 || ds[0].Usage >= 1 {
+		t.Errorf("first = %+v, want looked-up b with bounded usage", ds[0])
+	}
+
+	// Lookups fade: after ten half-lives they barely count.
+	later := now.Add(10 * usageHalfLife)
+	if got := usage["memory.general.b"].Decayed(later); got > 1 {
+		t.Errorf("decayed weight = %.2f after ten half-lives", got)
+	}
+
+	// Usage never outweighs relevance to the hooked bead.
+	ctx := Context{Bead: &Bead{ID: "gt-1", Title: "alpha"}}
+	ds = Rank(mems, ctx, usage, 0, now)
+	if ds[0].Memory.ShortKey != "a" {
+		t.Errorf("first = %s, want relevant a over much-used b", ds[0].Memory.ShortKey)
+	}
+}
+
+func TestRecordUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".runtime", "memory-usage.json")
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := RecordUse(path, []string{"memory.general.a"}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u := LoadUsage(path)["memory.general.a"]
+	if u.Count != 2 || u.LastUsed.IsZero() || u.Decayed(now) < 1.99 {
+		t.Errorf("usage = %+v", u)
+	}
+	if err := RecordUse(path, []string{"memory.general.a"}, now.Add(usageHalfLife)); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadUsage(path)["memory.general.a"].Weight; got < 1.99 || got > 2.01 {
+		t.Errorf("weight after a half-life and one more use = %.2f, want 2", got)
+	}
+}
+
+func TestRecordUseConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".runtime", "memory-usage.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := RecordUse(path, []string{"memory.general.a"}, time.Now()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := LoadUsage(path)["memory.general.a"].Count; got != 8 {
+		t.Errorf("count = %d after 8 concurrent lookups, want 8", got)
+	}
+}
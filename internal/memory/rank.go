@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Score weights. Relevance dominates when there is a hooked bead; type
+// keeps feedback (behavioral rules) ahead of general notes otherwise.
+// Usage counts lookups, not injections (see Use), and is bounded so a
+// much-used memory cannot outrank a relevant one.
+const (
+	weightRelevance = 0.5
+	weightRecency   = 0.15
+	weightUsage     = 0.1
+	bonusScoped     = 0.15 // a scoped memory that applies is more targeted
+
+	recencyHalfLife = 30 * 24 * time.Hour
+)
+
+var typeWeights = map[string]float64{
+	"feedback":  0.3,
+	"user":      0.2,
+	"project":   0.1,
+	"reference": 0.05,
+}
+
+// Decision is the ranking outcome for one memory.
+type Decision struct {
+	Memory   Memory
+	Injected bool
+	Reason   string // why it was or wasn't injected
+	Tokens   int
+
+	// Score components (zero for memories out of scope).
+	Score     float64
+	Relevance float64 // normalized BM25 against the hooked bead, 0..1
+	Recency   float64 // 1 for just stored, halving every 30 days
+	Usage     float64 // decayed lookups u as u/(u+1), 0..1
+}
+
+// Rank decides which memories to inject in ctx. Memories out of scope are
+// skipped; the rest are scored and injected best-first while they fit in
+// budget tokens (budget <= 0 means no limit). Decisions come back in
+// injection order: injected first, then over budget, then out of scope.
+// usage may be nil.
+func Rank(mems []Memory, ctx Context, usage Usage, budget int, now time.Time) []Decision {
+	var in, out []Decision
+	for _, m := range mems {
+		d := Decision{Memory: m, Tokens: EstimateTokens(m)}
+		if ok, why := m.Scope.applies(ctx); !ok {
+			d.Reason = why
+			out = append(out, d)
+			continue
+		}
+		in = append(in, d)
+	}
+
+	var query []string
+	if ctx.Bead != nil {
+		query = tokenize(ctx.Bead.Title + " " + ctx.Bead.Description + " " + strings.Join(ctx.Bead.Labels, " "))
+	}
+	docs := make([][]string, len(in))
+	for i, d := range in {
+		docs[i] = tokenize(strings.ReplaceAll(d.Memory.ShortKey, "-", " ") + " " + d.Memory.Text)
+	}
+	relevance := bm25(query, docs)
+
+	for i := range in {
+		d := &in[i]
+		d.Relevance = relevance[i]
+		d.Recency = recency(d.Memory, now)
+		u := usage[d.Memory.Key].Decayed(now)
+		d.Usage = u / (u + 1)
+		d.Score = typeWeights[d.Memory.Type] + weightRelevance*d.Relevance +
+			weightRecency*d.Recency + weightUsage*d.Usage
+		if !d.Memory.Scope.IsTown() {
+			d.Score += bonusScoped
+		}
+	}
+	sort.SliceStable(in, func(i, j int) bool {
+		if in[i].Score != in[j].Score {
+			return in[i].Score > in[j].Score
+		}
+		return in[i].Memory.Key < in[j].Memory.Key
+	})
+
+	left := budget
+	var injected, over []Decision
+	for _, d := range in {
+		if budget > 0 && d.Tokens > left {
+			d.Reason = fmt.Sprintf("over budget: needs %d tokens, %d left of %d", d.Tokens, left, budget)
+			over = append(over, d)
+			continue
+		}
+		left -= d.Tokens
+		d.Injected = true
+		d.Reason = injectReason(d, ctx)
+		injected = append(injected, d)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Memory.Key < out[j].Memory.Key })
+	return append(append(injected, over...), out...)
+}
+
+func injectReason(d Decision, ctx Context) string {
+	var why []string
+	if d.Memory.Scope.IsTown() {
+		why = append(why, "town-wide")
+	} else {
+		why = append(why, "in scope ("+d.Memory.Scope.String()+")")
+	}
+	if d.Relevance > 0 {
+		why = append(why, fmt.Sprintf("matches %s", ctx.Bead.ID))
+	}
+	return strings.Join(why, ", ")
+}
+
+// EstimateTokens approximates the tokens a memory takes in prime output,
+// at about four characters per token.
+func EstimateTokens(m Memory) int {
+	// "- **<key>**: <text>\n"
+	return (len(m.ShortKey)+len(m.Text)+8)/4 + 1
+}
+
+func recency(m Memory, now time.Time) float64 {
+	if m.Created.IsZero() {
+		return 0
+	}
+	age := now.Sub(m.Created)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+}
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// bm25 scores each doc against the query and normalizes the scores to 0..1
+// by the best one. No query, or no match, scores 0.
+func bm25(query []string, docs [][]string) []float64 {
+	scores := make([]float64, len(docs))
+	if len(query) == 0 || len(docs) == 0 {
+		return scores
+	}
+
+	df := map[string]int{}
+	total := 0
+	for _, doc := range docs {
+		total += len(doc)
+		seen := map[string]bool{}
+		for _, t := range doc {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+	avg := float64(total) / float64(len(docs))
+	if avg == 0 {
+		return scores
+	}
+
+	terms := map[string]bool{}
+	for _, t := range query {
+		terms[t] = true
+	}
+	best := 0.0
+	for i, doc := range docs {
+		tf := map[string]int{}
+		for _, t := range doc {
+			tf[t]++
+		}
+		for t := range terms {
+			f := float64(tf[t])
+			if f == 0 {
+				continue
+			}
+			n := float64(df[t])
+			idf := math.Log(1 + (float64(len(docs))-n+0.5)/(n+0.5))
+			scores[i] += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*float64(len(doc))/avg))
+		}
+		best = max(best, scores[i])
+	}
+	if best > 0 {
+		for i := range scores {
+			scores[i] /= best
+		}
+	}
+	return scores
+}
+
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "do": true, "for": true, "from": true, "in": true, "is": true, "it": true,
+	"not": true, "of": true, "on": true, "or": true, "that": true, "the": true, "this": true,
+	"to": true, "use": true, "we": true, "when": true, "with": true, "you": true,
+}
+
+// tokenize splits text into lowercase alphanumeric terms, dropping
+// stopwords and single characters.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if len(f) > 1 && !stopwords[f] {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
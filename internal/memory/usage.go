@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/atomicfile"
+)
+
+// usageHalfLife is how fast a lookup's weight in ranking fades.
+const usageHalfLife = 14 * 24 * time.Hour
+
+// Use is how often agents have looked a memory up with gt memories <term>.
+// Weight is the lookup count decayed by usageHalfLife as of LastUsed.
+// Injections by gt prime are not uses: counting them would keep whatever
+// is injected injected.
+type Use struct {
+	Count    int       `json:"count"`
+	Weight   float64   `json:"weight"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Decayed returns the use's weight at now.
+func (u Use) Decayed(now time.Time) float64 {
+	if u.LastUsed.IsZero() || u.Weight <= 0 {
+		return 0
+	}
+	age := max(now.Sub(u.LastUsed), 0)
+	return u.Weight * math.Pow(0.5, float64(age)/float64(usageHalfLife))
+}
+
+// Usage maps full memory keys to their use.
+type Usage map[string]Use
+
+// UsagePath returns the town's memory usage file.
+func UsagePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "memory-usage.json")
+}
+
+// LoadUsage reads the usage file. A missing or unreadable file is empty
+// usage.
+func LoadUsage(path string) Usage {
+	u := Usage{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return u
+	}
+	_ = json.Unmarshal(data, &u)
+	return u
+}
+
+// RecordUse counts one lookup of each key and saves the file. The update
+// holds a lock, since agents across the town look memories up.
+func RecordUse(path string, keys []string, now time.Time) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	u := LoadUsage(path)
+	for _, k := range keys {
+		use := u[k]
+		use.Weight = use.Decayed(now) + 1
+		use.Count++
+		use.LastUsed = now.UTC()
+		u[k] = use
+	}
+	return atomicfile.WriteJSON(path, u)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/steveyegge/gastown/internal/templates"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/worktree"
 )
 
 // Retry constants for Dolt operations (matching hook update pattern in sling.go).
@@ -518,6 +519,27 @@ func (m *Manager) clonePath(name string) string {
 	return newPath
 }
 
+// worktrees returns the materializer for new polecat worktrees, following
+// the rig's worktree_strategy. Reflink copies come from a template checkout
+// of the rig's repo in .worktree-template/. A sparse profile narrows the
+// worktree before its files are checked out.
+func (m *Manager) worktrees(sparseProfile string) worktree.Materializer {
+	strategy := worktree.StrategyWorktree
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil {
+		if s, err := worktree.ParseStrategy(rigCfg.WorktreeStrategy); err == nil {
+			strategy = s
+		} else {
+			style.PrintWarning("%v; using plain worktrees", err)
+		}
+	}
+	return worktree.Materializer{
+		Strategy:    strategy,
+		TemplateDir: filepath.Join(m.rig.Path, worktree.TemplateDirName, "polecats"),
+		Warn:        style.PrintWarning,
+		Sparse:      m.sparseCone(sparseProfile),
+	}
+}
+
 // ClonePath returns the path to a polecat's git worktree.
 func (m *Manager) ClonePath(name string) string {
 	return m.clonePath(name)
@@ -539,6 +561,9 @@ type AddOptions struct {
 	// updating the existing PR. Mutually exclusive with BaseBranch (resume implies its
 	// own start point). When empty, normal fresh-branch behavior is used.
 	ResumeBranch string
+	// SparseProfile narrows the worktree to one of the rig's sparse_profiles
+	// (see sparse.go). Empty means a full checkout.
+	SparseProfile string
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -771,7 +796,7 @@ func (m *Manager) addWithOptionsLocked(name string, opts AddOptions, polecatDir
 		if err := repoGit.FetchBranch("origin", opts.ResumeBranch); err != nil {
 			style.PrintWarning("could not fetch resume branch %s: %v", opts.ResumeBranch, err)
 		}
-		if err := repoGit.WorktreeAddExistingForce(clonePath, opts.ResumeBranch); err != nil {
+		if _, err := m.worktrees(opts.SparseProfile).AddExistingForce(repoGit, clonePath, opts.ResumeBranch); err != nil {
 			cleanupOnError()
 			return nil, fmt.Errorf("creating worktree on existing branch %s: %w", opts.ResumeBranch, err)
 		}
@@ -802,7 +827,7 @@ func (m *Manager) addWithOptionsLocked(name string, opts AddOptions, polecatDir
 				startPoint, m.rig.Path, filepath.Join(m.rig.Path, ".repo.git"))
 		}
 
-		if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+		if _, err := m.worktrees(opts.SparseProfile).AddFromRef(repoGit, clonePath, branchName, startPoint); err != nil {
 			cleanupOnError()
 			return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 		}
@@ -842,6 +867,7 @@ func (m *Manager) addWithOptionsLocked(name string, opts AddOptions, polecatDir
 	if err := rig.RunSetupHooks(m.rig.Path, clonePath); err != nil {
 		style.PrintWarning("could not run setup hooks: %v", err)
 	}
+	m.applySparseOrWarn(name, clonePath, opts.SparseProfile)
 	if err := m.runSetupCommand(clonePath); err != nil {
 		cleanupOnError()
 		return nil, err
@@ -970,7 +996,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 		if err := repoGit.FetchBranch("origin", opts.ResumeBranch); err != nil {
 			style.PrintWarning("could not fetch resume branch %s: %v", opts.ResumeBranch, err)
 		}
-		if err := repoGit.WorktreeAddExistingForce(clonePath, opts.ResumeBranch); err != nil {
+		if _, err := m.worktrees(opts.SparseProfile).AddExistingForce(repoGit, clonePath, opts.ResumeBranch); err != nil {
 			cleanupOnError()
 			return nil, fmt.Errorf("creating worktree on existing branch %s: %w", opts.ResumeBranch, err)
 		}
@@ -1006,7 +1032,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 		// Always create fresh branch - unique name guarantees no collision
 		// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 		// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-		if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+		if _, err := m.worktrees(opts.SparseProfile).AddFromRef(repoGit, clonePath, branchName, startPoint); err != nil {
 			cleanupOnError()
 			return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 		}
@@ -1068,6 +1094,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 		// Non-fatal - log warning but continue
 		style.PrintWarning("could not run setup hooks: %v", err)
 	}
+	m.applySparseOrWarn(name, clonePath, opts.SparseProfile)
 	if err := m.runSetupCommand(clonePath); err != nil {
 		cleanupOnError()
 		return nil, err
@@ -1553,6 +1580,14 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		return nil, ErrPolecatNotFound
 	}
 
+	// Keep the polecat's sparse cone, including paths it widened, unless the
+	// caller picked a profile.
+	prevSparse := m.Sparse(name)
+	sparseProfile := opts.SparseProfile
+	if sparseProfile == "" && prevSparse != nil {
+		sparseProfile = prevSparse.Profile
+	}
+
 	// Get the old clone path (may be old or new structure)
 	oldClonePath := m.clonePath(name)
 	polecatGit := git.NewGit(oldClonePath)
@@ -1599,7 +1634,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		if err := repoGit.FetchBranch("origin", opts.ResumeBranch); err != nil {
 			style.PrintWarning("could not fetch resume branch %s: %v", opts.ResumeBranch, err)
 		}
-		if err := repoGit.WorktreeAddExistingForce(tmpClonePath, opts.ResumeBranch); err != nil {
+		if _, err := m.worktrees(sparseProfile).AddExistingForce(repoGit, tmpClonePath, opts.ResumeBranch); err != nil {
 			return nil, fmt.Errorf("creating fresh worktree on existing branch %s: %w", opts.ResumeBranch, err)
 		}
 	} else {
@@ -1630,7 +1665,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 
 		// Create fresh worktree to a temporary path first, so we can roll back if it fails.
 		// This prevents destroying the old worktree before the new one is confirmed working.
-		if err := repoGit.WorktreeAddFromRef(tmpClonePath, branchName, startPoint); err != nil {
+		if _, err := m.worktrees(sparseProfile).AddFromRef(repoGit, tmpClonePath, branchName, startPoint); err != nil {
 			return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 		}
 	}
@@ -1704,6 +1739,13 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	}
 
 	// NOTE: Slash commands inherited from town level - no per-workspace copies needed.
+
+	m.applySparseOrWarn(name, newClonePath, sparseProfile)
+	if prevSparse != nil && sparseProfile == prevSparse.Profile && len(prevSparse.Widened) > 0 {
+		if _, err := m.widenSparse(name, prevSparse.Widened); err != nil {
+			style.PrintWarning("could not re-widen sparse checkout: %v", err)
+		}
+	}
 	if err := m.runSetupCommand(newClonePath); err != nil {
 		_ = repoGit.WorktreeRemove(newClonePath, true)
 		_ = os.RemoveAll(newClonePath)
@@ -1787,6 +1829,12 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 		return nil, fmt.Errorf("%w: %s", ErrPolecatNeedsRecovery, decision.Reason)
 	}
 
+	// Claiming a warm slot takes it out of the pool. A recently refreshed one
+	// is already at the default branch with dependencies in place, so skip
+	// the fetch and the target/ clean below.
+	warm := m.takeWarmSlot(name)
+	warmFresh := warm != nil && warm.Fresh(time.Now()) && opts.ResumeBranch == "" && opts.BaseBranch == ""
+
 	// Get worktree path (must already exist for reuse)
 	clonePath := m.clonePath(name)
 	if _, err := os.Stat(clonePath); err != nil {
@@ -1799,7 +1847,8 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 	// Policy is per-town config (polecat.target_clean_policy). target/ is
 	// gitignored, so the subsequent reset/clean below won't touch it on its own.
 	// Errors are logged as warnings — reuse must not fail because a cleanup did.
-	{
+	// Warm slots keep target/: it holds the dependencies the pool pre-built.
+	if warm == nil {
 		policy := m.targetCleanPolicy()
 		polecatDir := m.polecatDir(name)
 		msg, err := RunTargetCleanHook(polecatDir, clonePath, policy)
@@ -1814,11 +1863,13 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 
 	// Fetch latest from origin (non-fatal: may be offline)
 	repoGit, err := m.repoBase()
-	if err == nil {
+	if err == nil && !warmFresh {
 		_ = repoGit.Fetch("origin")
 	}
 	// Also fetch in the worktree itself so it has the latest refs
-	_ = polecatGit.Fetch("origin")
+	if !warmFresh {
+		_ = polecatGit.Fetch("origin")
+	}
 
 	// Determine the start point for the new branch.
 	// When resuming an existing branch (gh#3602), the start point IS that branch's
@@ -1840,11 +1891,7 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 	case opts.BaseBranch != "":
 		startPoint = opts.BaseBranch
 	default:
-		defaultBranch := "main"
-		if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-			defaultBranch = rigCfg.DefaultBranch
-		}
-		startPoint = fmt.Sprintf("origin/%s", defaultBranch)
+		startPoint = m.defaultStartPoint()
 	}
 
 	// Validate that startPoint ref exists
@@ -1854,6 +1901,11 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 		return nil, fmt.Errorf("start point %s not found — fall back to full repair", startPoint)
 	}
 
+	// A reused worktree keeps the previous bead's cone unless reset here.
+	// Narrow it before the reset below so files outside the new cone are
+	// never written.
+	m.applySparseOrWarn(name, clonePath, opts.SparseProfile)
+
 	// GH#2536: Clean worktree state before branch switch — the worktree may have
 	// stale state from a previous dog/pool dispatch (uncommitted changes, untracked
 	// files, detached HEAD, or checked out on an old dog/alpha-* branch).
@@ -2263,19 +2315,26 @@ func (m *Manager) List() ([]*Polecat, error) {
 	return polecats, nil
 }
 
-// FindIdlePolecat returns the first idle polecat in the rig, or nil if none.
-// Idle means no hook, no active session, and no pending completion/MR cleanup state.
+// FindIdlePolecat returns an idle polecat in the rig, preferring warm slots,
+// or nil if none. Idle means no hook, no active session, and no pending completion/MR cleanup state.
 func (m *Manager) FindIdlePolecat() (*Polecat, error) {
 	polecats, err := m.List()
 	if err != nil {
 		return nil, err
 	}
+	var first *Polecat
 	for _, p := range polecats {
 		if p.State == StateIdle && m.reuseDecisionForPolecat(p.Name, p.State).Reusable {
-			return p, nil
+			// Prefer a warm slot: it is already prepared for new work.
+			if m.IsWarm(p.Name) {
+				return p, nil
+			}
+			if first == nil {
+				first = p
+			}
 		}
 	}
-	return nil, nil
+	return first, nil
 }
 
 // ReuseDecisionForPolecat exposes the same reuse verdict used by FindIdlePolecat
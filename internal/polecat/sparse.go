@@ -0,0 +1,251 @@
+package polecat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Sparse profiles: cone-mode sparse checkouts for polecats in large
+// monorepos. A rig names path sets in config.json (sparse_profiles); a bead
+// picks one with a "sparse:<name>" label or the sparse_profile formula var.
+// A new worktree is created without a checkout and narrowed before its files
+// are written; a reused one is narrowed before it is reset to the new start
+// point. Either way the profile is applied before setup_command and recorded
+// in polecats/<name>/.sparse.json, outside the worktree. A running polecat
+// that needs more of the tree widens its cone with `gt polecat widen`.
+//
+// Only the polecat's checkout is narrowed. Commits carry the whole tree, and
+// the refinery runs its gates in a full checkout.
+
+const (
+	// SparseMarkerFile, in a polecat's home directory, records the sparse
+	// profile its worktree was narrowed to. Doctor leaves worktrees that
+	// have one alone.
+	SparseMarkerFile = ".sparse.json"
+
+	// SparseProfileLabelPrefix selects a profile from a bead label.
+	SparseProfileLabelPrefix = "sparse:"
+
+	// SparseProfileVar selects a profile from a formula var.
+	SparseProfileVar = "sparse_profile"
+)
+
+// ErrNotSparse is returned when widening a polecat with a full checkout.
+var ErrNotSparse = errors.New("polecat does not have a sparse checkout")
+
+// SparseState records a polecat's sparse checkout.
+type SparseState struct {
+	Profile string   `json:"profile"`
+	Paths   []string `json:"paths"`
+	// Widened holds paths added with `gt polecat widen` since the profile
+	// was applied.
+	Widened   []string  `json:"widened,omitempty"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Cone returns every path in the checkout's cone.
+func (s SparseState) Cone() []string {
+	return append(append([]string{}, s.Paths...), s.Widened...)
+}
+
+// SparseProfileFromLabels returns the profile named by a bead's
+// "sparse:<name>" label, or "" if it has none.
+func SparseProfileFromLabels(labels []string) string {
+	for _, l := range labels {
+		if name, ok := strings.CutPrefix(l, SparseProfileLabelPrefix); ok {
+			if name = strings.TrimSpace(name); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// SparseProfileFromVars returns the sparse_profile value from formula vars
+// in key=value form, or "" if unset.
+func SparseProfileFromVars(vars []string) string {
+	for _, v := range vars {
+		if key, value, ok := strings.Cut(v, "="); ok && strings.TrimSpace(key) == SparseProfileVar {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// SparseProfiles returns the rig's configured sparse profiles.
+func (m *Manager) SparseProfiles() map[string][]string {
+	cfg, err := rig.LoadRigConfig(m.rig.Path)
+	if err != nil {
+		return nil
+	}
+	return cfg.SparseProfiles
+}
+
+// Sparse returns the polecat's sparse checkout state, or nil if it has a
+// full checkout.
+func (m *Manager) Sparse(name string) *SparseState {
+	data, err := os.ReadFile(m.sparseMarkerPath(name))
+	if err != nil {
+		return nil
+	}
+	var state SparseState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+	return &state
+}
+
+// sparseCone returns the cone of a known, valid profile, or nil. New
+// worktrees are created with it so files outside it are never checked out;
+// applySparseProfile reports problems with the profile.
+func (m *Manager) sparseCone(profile string) []string {
+	if profile == "" {
+		return nil
+	}
+	paths, ok := m.SparseProfiles()[profile]
+	if !ok {
+		return nil
+	}
+	cone, err := cleanSparsePaths(paths)
+	if err != nil {
+		return nil
+	}
+	return cone
+}
+
+// applySparseProfile narrows the polecat's worktree to the named profile,
+// or restores a full checkout when profile is empty. An unknown profile
+// leaves a full checkout: slower, never wrong. Caller must hold the polecat
+// lock.
+func (m *Manager) applySparseProfile(name, clonePath, profile string) error {
+	var paths []string
+	if profile != "" {
+		var ok bool
+		paths, ok = m.SparseProfiles()[profile]
+		if !ok {
+			style.PrintWarning("unknown sparse profile %q in %s; using a full checkout", profile, m.rig.Name)
+			profile = ""
+		}
+	}
+
+	if profile == "" {
+		if m.Sparse(name) == nil {
+			return nil
+		}
+		if err := git.RemoveSparseCheckout(clonePath); err != nil {
+			return err
+		}
+		_ = os.Remove(m.sparseMarkerPath(name))
+		return nil
+	}
+
+	cone, err := cleanSparsePaths(paths)
+	if err != nil {
+		return fmt.Errorf("sparse profile %q: %w", profile, err)
+	}
+	if err := git.InitSparseCheckout(clonePath, cone); err != nil {
+		return err
+	}
+	fmt.Printf("Sparse checkout: profile %s (%s)\n", profile, strings.Join(cone, ", "))
+	return m.writeSparseState(name, &SparseState{Profile: profile, Paths: cone, AppliedAt: time.Now()})
+}
+
+// applySparseOrWarn applies a sparse profile, falling back to a full
+// checkout with a warning if git refuses it.
+func (m *Manager) applySparseOrWarn(name, clonePath, profile string) {
+	if err := m.applySparseProfile(name, clonePath, profile); err != nil {
+		style.PrintWarning("could not apply sparse profile %q: %v; using a full checkout", profile, err)
+		_ = git.RemoveSparseCheckout(clonePath)
+		_ = os.Remove(m.sparseMarkerPath(name))
+	}
+}
+
+// WidenSparse adds paths to a running polecat's sparse cone. Paths are
+// relative to the repository root.
+func (m *Manager) WidenSparse(name string, paths []string) (*SparseState, error) {
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fl.Unlock() }()
+	return m.widenSparse(name, paths)
+}
+
+// widenSparse is WidenSparse for a caller holding the polecat lock.
+func (m *Manager) widenSparse(name string, paths []string) (*SparseState, error) {
+	state := m.Sparse(name)
+	if state == nil {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotSparse)
+	}
+	add, err := cleanSparsePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	if err := git.AddSparseCheckout(m.clonePath(name), add); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range state.Cone() {
+		seen[p] = true
+	}
+	for _, p := range add {
+		if !seen[p] {
+			seen[p] = true
+			state.Widened = append(state.Widened, p)
+		}
+	}
+	return state, m.writeSparseState(name, state)
+}
+
+// cleanSparsePaths normalizes repo-relative cone paths, rejecting any that
+// escape the repository. The result is sorted and deduplicated.
+func cleanSparsePaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, p := range paths {
+		p = strings.TrimSpace(filepath.ToSlash(p))
+		if p == "" {
+			continue
+		}
+		if path.IsAbs(p) || filepath.IsAbs(p) {
+			return nil, fmt.Errorf("sparse path %q must be relative to the repository root", p)
+		}
+		p = path.Clean(p)
+		if p == "." {
+			return nil, fmt.Errorf("sparse path %q is the whole repository", p)
+		}
+		if p == ".." || strings.HasPrefix(p, "../") {
+			return nil, fmt.Errorf("sparse path %q is outside the repository", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (m *Manager) sparseMarkerPath(name string) string {
+	return filepath.Join(m.polecatDir(name), SparseMarkerFile)
+}
+
+func (m *Manager) writeSparseState(name string, state *SparseState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.sparseMarkerPath(name), data, 0644)
+}
@@ -0,0 +1,115 @@
+package polecat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newSparseTestPolecat creates a polecat whose worktree is a real repo with
+// web/, api/, and a root file.
+func newSparseTestPolecat(t *testing.T, m *Manager, name string) string {
+	t.Helper()
+	clonePath := filepath.Join(m.polecatDir(name), m.rig.Name)
+	for _, f := range []string{"README.md", "web/index.js", "api/main.go"} {
+		p := filepath.Join(clonePath, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(f+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = clonePath
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return clonePath
+}
+
+func checkedOut(clonePath, rel string) bool {
+	_, err := os.Stat(filepath.Join(clonePath, rel))
+	return err == nil
+}
+
+func TestSparseProfileSelection(t *testing.T) {
+	if got := SparseProfileFromLabels([]string{"gt:task", "sparse:web"}); got != "web" {
+		t.Errorf("from labels = %q, want web", got)
+	}
+	if got := SparseProfileFromLabels([]string{"gt:task", "sparse:"}); got != "" {
+		t.Errorf("empty label = %q, want none", got)
+	}
+	if got := SparseProfileFromVars([]string{"issue=gt-1", "sparse_profile = api"}); got != "api" {
+		t.Errorf("from vars = %q, want api", got)
+	}
+}
+
+func TestApplyAndWidenSparseProfile(t *testing.T) {
+	m := newWarmTestManager(t, `{"sparse_profiles": {"web": ["web"]}}`)
+	clonePath := newSparseTestPolecat(t, m, "nux")
+
+	if err := m.applySparseProfile("nux", clonePath, "web"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !checkedOut(clonePath, "web/index.js") || !checkedOut(clonePath, "README.md") || checkedOut(clonePath, "api/main.go") {
+		t.Fatal("profile web should check out web/ and root files only")
+	}
+	if st := m.Sparse("nux"); st == nil || st.Profile != "web" || !reflect.DeepEqual(st.Paths, []string{"web"}) {
+		t.Fatalf("state = %+v, want profile web", st)
+	}
+
+	st, err := m.WidenSparse("nux", []string{"./api/", "web"})
+	if err != nil {
+		t.Fatalf("widen: %v", err)
+	}
+	if !checkedOut(clonePath, "api/main.go") {
+		t.Error("api/ should be checked out after widening")
+	}
+	if !reflect.DeepEqual(st.Widened, []string{"api"}) {
+		t.Errorf("widened = %v, want [api]", st.Widened)
+	}
+
+	// Reuse for a bead without a profile restores the full tree.
+	if err := m.applySparseProfile("nux", clonePath, ""); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if m.Sparse("nux") != nil {
+		t.Error("marker should be removed")
+	}
+	if _, err := m.WidenSparse("nux", []string{"api"}); err == nil {
+		t.Error("widening a full checkout should fail")
+	}
+}
+
+func TestApplyUnknownSparseProfile(t *testing.T) {
+	m := newWarmTestManager(t, `{"sparse_profiles": {"web": ["web"]}}`)
+	clonePath := newSparseTestPolecat(t, m, "nux")
+
+	if err := m.applySparseProfile("nux", clonePath, "mobile"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if m.Sparse("nux") != nil || !checkedOut(clonePath, "api/main.go") {
+		t.Error("unknown profile should leave a full checkout")
+	}
+}
+
+func TestCleanSparsePaths(t *testing.T) {
+	got, err := cleanSparsePaths([]string{"web/", "./api", "web", ""})
+	if err != nil || !reflect.DeepEqual(got, []string{"api", "web"}) {
+		t.Errorf("clean = %v, %v; want [api web]", got, err)
+	}
+	for _, bad := range []string{"../other", "/etc", "."} {
+		if _, err := cleanSparsePaths([]string{bad}); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package polecat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Warm pool: idle polecats prepared ahead of sling so a big repo does not pay
+// for worktree creation, fetch, and dependency install at dispatch time.
+//
+// A warm slot is an ordinary idle polecat plus a marker file in its home
+// directory (polecats/<name>/.warm.json, outside the worktree). The daemon
+// keeps polecat_warm_pool slots per rig: it creates missing ones, and on each
+// pass fast-forwards existing ones to the default branch and re-runs
+// polecat_warm_command when the base moved. Sling prefers warm slots and
+// claims them through ReuseIdlePolecat; claiming removes the marker.
+
+const (
+	warmMarkerFile = ".warm.json"
+
+	// WarmFreshFor is how long after its last refresh a warm slot is claimed
+	// without fetching first. The daemon refreshes slots every heartbeat, so
+	// an older slot means nothing is maintaining the pool.
+	WarmFreshFor = 15 * time.Minute
+)
+
+// ErrNotWarm is returned when a polecat is no longer a warm slot, typically
+// because sling claimed it.
+var ErrNotWarm = errors.New("polecat is not a warm slot")
+
+// WarmSlot records what a warm polecat was prepared at.
+type WarmSlot struct {
+	Name        string    `json:"name"`
+	BaseRef     string    `json:"base_ref"`
+	Commit      string    `json:"commit"`
+	PreparedAt  time.Time `json:"prepared_at"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	// WarmError is set when polecat_warm_command failed on the last prepare.
+	// The slot is still claimable; the polecat installs dependencies itself.
+	WarmError string `json:"warm_error,omitempty"`
+}
+
+// Fresh reports whether the slot was refreshed recently enough to claim
+// without fetching.
+func (s WarmSlot) Fresh(now time.Time) bool {
+	return now.Sub(s.RefreshedAt) < WarmFreshFor
+}
+
+// WarmPoolStatus summarizes a rig's warm pool.
+type WarmPoolStatus struct {
+	Rig    string     `json:"rig"`
+	Target int        `json:"target"`
+	Slots  []WarmSlot `json:"slots"`
+	Fresh  int        `json:"fresh"`
+}
+
+// Healthy reports whether the pool holds its target of fresh slots.
+func (s WarmPoolStatus) Healthy() bool { return s.Fresh >= s.Target }
+
+// WarmFillResult reports what one maintenance pass over a warm pool did.
+type WarmFillResult struct {
+	Target    int      `json:"target"`
+	Ready     int      `json:"ready"`
+	Created   []string `json:"created,omitempty"`
+	Refreshed []string `json:"refreshed,omitempty"`
+	Dropped   []string `json:"dropped,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// WarmPoolTarget returns the rig's configured warm pool size (0 = disabled).
+func (m *Manager) WarmPoolTarget() int {
+	cfg, err := rig.LoadRigConfig(m.rig.Path)
+	if err != nil || cfg.PolecatWarmPool < 0 {
+		return 0
+	}
+	return cfg.PolecatWarmPool
+}
+
+// WarmSlots returns the rig's warm slots, sorted by name. Markers whose
+// worktree is gone are skipped.
+func (m *Manager) WarmSlots() []WarmSlot {
+	entries, err := os.ReadDir(filepath.Join(m.rig.Path, "polecats"))
+	if err != nil {
+		return nil
+	}
+	var slots []WarmSlot
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if slot := m.readWarmSlot(entry.Name()); slot != nil {
+			if _, err := os.Stat(m.clonePath(entry.Name())); err == nil {
+				slots = append(slots, *slot)
+			}
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+	return slots
+}
+
+// WarmPoolStatus reports the rig's warm pool against its target.
+func (m *Manager) WarmPoolStatus() WarmPoolStatus {
+	st := WarmPoolStatus{Rig: m.rig.Name, Target: m.WarmPoolTarget(), Slots: m.WarmSlots()}
+	now := time.Now()
+	for _, s := range st.Slots {
+		if s.Fresh(now) {
+			st.Fresh++
+		}
+	}
+	return st
+}
+
+// IsWarm reports whether the named polecat is a warm slot.
+func (m *Manager) IsWarm(name string) bool {
+	return m.readWarmSlot(name) != nil
+}
+
+// FillWarmPool refreshes the rig's warm slots and creates new ones until the
+// pool reaches its target, never taking the rig past maxDirs polecat
+// directories. Slots that stopped being reusable idle polecats (work was
+// hooked onto them some other way) lose their marker.
+func (m *Manager) FillWarmPool(maxDirs int) WarmFillResult {
+	res := WarmFillResult{Target: m.WarmPoolTarget()}
+
+	for _, slot := range m.WarmSlots() {
+		p, err := m.Get(slot.Name)
+		if err != nil || p.State != StateIdle || !m.reuseDecisionForPolecat(slot.Name, p.State).Reusable {
+			m.removeWarmMarker(slot.Name)
+			res.Dropped = append(res.Dropped, slot.Name)
+			continue
+		}
+		moved, err := m.RefreshWarm(slot.Name)
+		if errors.Is(err, ErrNotWarm) {
+			res.Dropped = append(res.Dropped, slot.Name)
+			continue
+		}
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("%s: refresh: %v", slot.Name, err))
+			continue
+		}
+		if moved {
+			res.Refreshed = append(res.Refreshed, slot.Name)
+		}
+		res.Ready++
+	}
+
+	for res.Ready < res.Target {
+		if maxDirs > 0 && m.polecatDirCount() >= maxDirs {
+			res.Errors = append(res.Errors, fmt.Sprintf("rig is at its polecat directory cap (%d); warm pool short by %d", maxDirs, res.Target-res.Ready))
+			break
+		}
+		name, err := m.AddWarm()
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("creating warm polecat: %v", err))
+			break
+		}
+		res.Created = append(res.Created, name)
+		res.Ready++
+	}
+	return res
+}
+
+// AddWarm allocates a new polecat with no work, prepares its worktree at the
+// default branch, and marks it warm.
+func (m *Manager) AddWarm() (string, error) {
+	name, p, err := m.AllocateAndAdd(AddOptions{})
+	if err != nil {
+		return "", err
+	}
+	// Same as pool-init: a polecat created without work is idle.
+	if err := m.SetAgentStateWithRetry(name, string(beads.AgentStateIdle)); err != nil {
+		return name, fmt.Errorf("created %s but could not mark it idle: %w", name, err)
+	}
+
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return name, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	slot := &WarmSlot{Name: name, BaseRef: m.defaultStartPoint(), PreparedAt: time.Now()}
+	if slot.Commit, err = git.NewGit(p.ClonePath).Rev("HEAD"); err != nil {
+		return name, fmt.Errorf("reading %s HEAD: %w", name, err)
+	}
+	if err := m.runWarmCommand(p.ClonePath); err != nil {
+		slot.WarmError = err.Error()
+	}
+	slot.RefreshedAt = time.Now()
+	return name, m.writeWarmSlot(slot)
+}
+
+// RefreshWarm fetches and, if the default branch moved, resets the warm
+// slot's worktree to it and re-runs polecat_warm_command. Returns whether
+// the base moved.
+func (m *Manager) RefreshWarm(name string) (moved bool, err error) {
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	slot := m.readWarmSlot(name)
+	if slot == nil {
+		return false, fmt.Errorf("%s: %w", name, ErrNotWarm)
+	}
+	clonePath := m.clonePath(name)
+	g := git.NewGit(clonePath)
+	if repoGit, err := m.repoBase(); err == nil {
+		if err := repoGit.Fetch("origin"); err != nil {
+			return false, fmt.Errorf("fetching origin: %w", err)
+		}
+	}
+
+	base := m.defaultStartPoint()
+	head, err := g.Rev(base)
+	if err != nil {
+		return false, fmt.Errorf("resolving %s: %w", base, err)
+	}
+	if head != slot.Commit || base != slot.BaseRef {
+		if err := g.ResetHard(base); err != nil {
+			return false, fmt.Errorf("resetting to %s: %w", base, err)
+		}
+		_ = g.CleanForce()
+		// reset/clean drop the provisioned CLAUDE.md, as on reuse.
+		if _, err := templates.CreatePolecatCLAUDEmd(clonePath, filepath.Base(m.rig.Path), name); err != nil {
+			return false, fmt.Errorf("re-provisioning CLAUDE.md: %w", err)
+		}
+		slot.BaseRef, slot.Commit, slot.PreparedAt, slot.WarmError = base, head, time.Now(), ""
+		if err := m.runWarmCommand(clonePath); err != nil {
+			slot.WarmError = err.Error()
+		}
+		moved = true
+	}
+	slot.RefreshedAt = time.Now()
+	return moved, m.writeWarmSlot(slot)
+}
+
+// takeWarmSlot removes and returns the named polecat's warm marker, or nil
+// if it is not warm. Caller must hold the polecat lock.
+func (m *Manager) takeWarmSlot(name string) *WarmSlot {
+	slot := m.readWarmSlot(name)
+	if slot != nil {
+		m.removeWarmMarker(name)
+	}
+	return slot
+}
+
+// runWarmCommand runs the rig's polecat_warm_command in a warm worktree,
+// typically to install dependencies before work arrives.
+func (m *Manager) runWarmCommand(worktreePath string) error {
+	cfg, err := rig.LoadRigConfig(m.rig.Path)
+	if err != nil || strings.TrimSpace(cfg.PolecatWarmCommand) == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupCmdTimeout)
+	defer cancel()
+
+	shell, args := setupShellCommand(strings.TrimSpace(cfg.PolecatWarmCommand))
+	cmd := exec.CommandContext(ctx, shell, args...) //nolint:gosec // polecat_warm_command is operator-controlled rig configuration.
+	util.SetProcessGroup(cmd)
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("GT_WORKTREE_PATH=%s", worktreePath),
+		fmt.Sprintf("GT_RIG_PATH=%s", m.rig.Path),
+		"GT_WARM=1",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("polecat_warm_command timed out after %s", setupCmdTimeout)
+		}
+		return fmt.Errorf("polecat_warm_command failed: %w (%s)", err, lastLine(out))
+	}
+	return nil
+}
+
+// defaultStartPoint returns origin/<default branch> for the rig.
+func (m *Manager) defaultStartPoint() string {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	return "origin/" + defaultBranch
+}
+
+// polecatDirCount counts polecat directories in the rig.
+func (m *Manager) polecatDirCount() int {
+	entries, err := os.ReadDir(filepath.Join(m.rig.Path, "polecats"))
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *Manager) warmMarkerPath(name string) string {
+	return filepath.Join(m.polecatDir(name), warmMarkerFile)
+}
+
+func (m *Manager) readWarmSlot(name string) *WarmSlot {
+	data, err := os.ReadFile(m.warmMarkerPath(name))
+	if err != nil {
+		return nil
+	}
+	var slot WarmSlot
+	if err := json.Unmarshal(data, &slot); err != nil {
+		return nil
+	}
+	slot.Name = name
+	return &slot
+}
+
+func (m *Manager) writeWarmSlot(slot *WarmSlot) error {
+	data, err := json.MarshalIndent(slot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.warmMarkerPath(slot.Name), data, 0644)
+}
+
+func (m *Manager) removeWarmMarker(name string) {
+	_ = os.Remove(m.warmMarkerPath(name))
+}
+
+// lastLine returns the last non-empty line of command output.
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
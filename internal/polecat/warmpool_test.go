@@ -0,0 +1,114 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func newWarmTestManager(t *testing.T, config string) *Manager {
+	t.Helper()
+	root := t.TempDir()
+	if config != "" {
+		if err := os.WriteFile(filepath.Join(root, "config.json"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &rig.Rig{Name: "testrig", Path: root}
+	return NewManager(r, git.NewGit(root), nil)
+}
+
+func makeWarmSlot(t *testing.T, m *Manager, name string, refreshed time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(m.polecatDir(name), m.rig.Name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	slot := &WarmSlot{Name: name, BaseRef: "origin/main", Commit: "abc123", PreparedAt: refreshed, RefreshedAt: refreshed}
+	if err := m.writeWarmSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWarmPoolTarget(t *testing.T) {
+	if got := newWarmTestManager(t, "").WarmPoolTarget(); got != 0 {
+		t.Errorf("no config: target = %d, want 0", got)
+	}
+	if got := newWarmTestManager(t, `{"polecat_warm_pool": 3}`).WarmPoolTarget(); got != 3 {
+		t.Errorf("target = %d, want 3", got)
+	}
+	if got := newWarmTestManager(t, `{"polecat_warm_pool": -1}`).WarmPoolTarget(); got != 0 {
+		t.Errorf("negative target = %d, want 0", got)
+	}
+}
+
+func TestWarmPoolStatus(t *testing.T) {
+	m := newWarmTestManager(t, `{"polecat_warm_pool": 2}`)
+	now := time.Now()
+	makeWarmSlot(t, m, "nux", now)
+	makeWarmSlot(t, m, "furiosa", now.Add(-2*WarmFreshFor))
+
+	// A marker whose worktree is gone is not a slot.
+	if err := os.MkdirAll(m.polecatDir("slit"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.warmMarkerPath("slit"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	st := m.WarmPoolStatus()
+	if len(st.Slots) != 2 || st.Slots[0].Name != "furiosa" || st.Slots[1].Name != "nux" {
+		t.Fatalf("slots = %+v, want furiosa and nux", st.Slots)
+	}
+	if st.Target != 2 || st.Fresh != 1 || st.Healthy() {
+		t.Errorf("status = %+v, want 1 of 2 fresh and unhealthy", st)
+	}
+}
+
+func TestTakeWarmSlot(t *testing.T) {
+	m := newWarmTestManager(t, "")
+	makeWarmSlot(t, m, "nux", time.Now())
+
+	if !m.IsWarm("nux") {
+		t.Fatal("nux should be warm")
+	}
+	slot := m.takeWarmSlot("nux")
+	if slot == nil || slot.Commit != "abc123" {
+		t.Fatalf("takeWarmSlot = %+v", slot)
+	}
+	if m.IsWarm("nux") {
+		t.Error("nux still warm after claim")
+	}
+	if m.takeWarmSlot("nux") != nil {
+		t.Error("second claim returned a slot")
+	}
+}
+
+func TestRunWarmCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+	m := newWarmTestManager(t, `{"polecat_warm_command": "echo \"$GT_WARM $GT_RIG_PATH\" > warmed"}`)
+	wt := t.TempDir()
+	if err := m.runWarmCommand(wt); err != nil {
+		t.Fatalf("runWarmCommand: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(wt, "warmed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "1 "+m.rig.Path {
+		t.Errorf("warm command saw %q", got)
+	}
+
+	m = newWarmTestManager(t, `{"polecat_warm_command": "echo installing; echo lockfile out of date >&2; exit 3"}`)
+	err = m.runWarmCommand(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "lockfile out of date") {
+		t.Errorf("err = %v, want failure with last output line", err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/worktree"
 )
 
 // WorktreeIntegrityError marks structural worktree damage that is safe to
@@ -74,6 +76,11 @@ func VerifyWorktreeExists(clonePath string) error {
 				}
 				return fmt.Errorf("checking worktree gitdir %s: %w", gitdirPath, err)
 			}
+			// A reflink worktree whose copy was interrupted has valid git
+			// metadata but missing files.
+			if err := worktree.Validate(clonePath, worktree.IntegrityOptions{TownRoot: clonePath}); errors.Is(err, worktree.ErrMaterializationIncomplete) {
+				return structuralWorktreeError(clonePath, "%v", err)
+			}
 		}
 	}
 
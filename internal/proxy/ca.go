@@ -18,6 +18,11 @@ import (
 	"time"
 )
 
+// TownCertOU is the Subject OrganizationalUnit of town federation
+// certificates. The proxy CA also issues server and polecat certificates, so
+// federation peers require it to tell a town identity from those.
+const TownCertOU = "gastown-town"
+
 // CA holds the CA certificate and private key.
 type CA struct {
 	Cert    *x509.Certificate
@@ -139,7 +144,7 @@ func LoadOrGenerateCA(dir string) (*CA, error) {
 // setting an explicit ServerName override.
 func (ca *CA) IssueServer(cn string, extraIPs []net.IP, extraDNSNames []string, ttl time.Duration) (certPEM, keyPEM []byte, err error) {
 	dnsNames := append([]string{cn}, extraDNSNames...)
-	return ca.issue(cn, dnsNames, extraIPs, ttl, x509.ExtKeyUsageServerAuth)
+	return ca.issue(pkix.Name{CommonName: cn}, dnsNames, extraIPs, ttl, x509.ExtKeyUsageServerAuth)
 }
 
 // IssuePolecat issues a leaf certificate signed by the CA for a polecat (client auth).
@@ -150,12 +155,23 @@ func (ca *CA) IssuePolecat(cn string, ttl time.Duration) (certPEM, keyPEM []byte
 	if cnToIdentity(cn) == "" {
 		return nil, nil, fmt.Errorf("invalid polecat CN %q: must be gt-<rig>-<name> with non-empty rig and name", cn)
 	}
-	return ca.issue(cn, nil, nil, ttl, x509.ExtKeyUsageClientAuth)
+	return ca.issue(pkix.Name{CommonName: cn}, nil, nil, ttl, x509.ExtKeyUsageClientAuth)
+}
+
+// IssueTown issues a leaf certificate for cross-town federation. The same
+// certificate serves the town's federation endpoint and authenticates the
+// town as a client when it connects to peers, so it carries both server and
+// client auth usages. cn is included as a DNS SAN alongside dnsNames, and the
+// subject carries TownCertOU.
+func (ca *CA) IssueTown(cn string, dnsNames []string, ipAddrs []net.IP, ttl time.Duration) (certPEM, keyPEM []byte, err error) {
+	subject := pkix.Name{CommonName: cn, OrganizationalUnit: []string{TownCertOU}}
+	return ca.issue(subject, append([]string{cn}, dnsNames...), ipAddrs, ttl,
+		x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth)
 }
 
 // issue creates and signs a leaf certificate. dnsNames and ipAddrs are added as SANs
 // for server certs so that modern TLS stacks (Go 1.15+) accept them without relying on CN.
-func (ca *CA) issue(cn string, dnsNames []string, ipAddrs []net.IP, ttl time.Duration, eku x509.ExtKeyUsage) (certPEM, keyPEM []byte, err error) {
+func (ca *CA) issue(subject pkix.Name, dnsNames []string, ipAddrs []net.IP, ttl time.Duration, ekus ...x509.ExtKeyUsage) (certPEM, keyPEM []byte, err error) {
 	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
 		return nil, nil, fmt.Errorf("generate leaf key: %w", err)
@@ -168,13 +184,13 @@ func (ca *CA) issue(cn string, dnsNames []string, ipAddrs []net.IP, ttl time.Dur
 
 	tmpl := &x509.Certificate{
 		SerialNumber: serial,
-		Subject:      pkix.Name{CommonName: cn},
+		Subject:      subject,
 		DNSNames:     dnsNames, // required by modern TLS clients for server certs
 		IPAddresses:  ipAddrs,  // required for clients connecting by IP address
 		NotBefore:    time.Now().Add(-time.Minute),
 		NotAfter:     time.Now().Add(ttl),
 		KeyUsage:     x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:  []x509.ExtKeyUsage{eku},
+		ExtKeyUsage:  ekus,
 	}
 
 	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, &key.PublicKey, ca.Key)
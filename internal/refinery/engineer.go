@@ -488,6 +488,7 @@ func (e *Engineer) Config() *MergeQueueConfig {
 type ProcessResult struct {
 	Success        bool
 	MergeCommit    string
+	CommitsLanded  int // commits the merge added to the target branch on origin
 	Error          string
 	Conflict       bool
 	TestsFailed    bool
@@ -686,6 +687,7 @@ func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo, skipGates ...bool) P
 	}
 
 	// Step 7-8: Push to origin (when auto_push is enabled).
+	var landed int
 	if e.config.AutoPush {
 		// Acquire merge slot before push to serialize writes to the default branch.
 		// Only serialize pushes to the rig's default branch (typically main).
@@ -727,6 +729,13 @@ func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo, skipGates ...bool) P
 			return eligibility
 		}
 
+		// Normally just the squash commit, but a push also carries any
+		// local commits origin had not seen.
+		var countErr error
+		if landed, countErr = e.git.CommitsAhead("origin/"+target, target); countErr != nil {
+			landed = 1
+		}
+
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
 		if err := e.git.Push("origin", target, false); err != nil {
 			// Reset the checked-out target branch to undo the local squash commit.
@@ -734,6 +743,10 @@ func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo, skipGates ...bool) P
 			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after push failure: %v\n", target, resetErr)
 			}
+			// Recorded so the daemon's auto E-stop can spot a rig whose
+			// pushes keep bouncing off the remote.
+			_ = events.LogFeed(events.TypePushRejected, e.rig.Name+"/refinery",
+				events.MergePayload(mr.ID, mr.Worker, mr.Branch, err.Error()))
 			return ProcessResult{
 				Success: false,
 				Error:   fmt.Sprintf("failed to push to origin: %v", err),
@@ -754,8 +767,9 @@ func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo, skipGates ...bool) P
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", shortSHA(mergeCommit))
 	return ProcessResult{
-		Success:     true,
-		MergeCommit: mergeCommit,
+		Success:       true,
+		MergeCommit:   mergeCommit,
+		CommitsLanded: landed,
 	}
 }
 
@@ -856,8 +870,9 @@ func (e *Engineer) doMergePR(ctx context.Context, mr *MRInfo) ProcessResult {
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged PR #%d: %s\n", prNumber, shortSHA(mergeCommit))
 	return ProcessResult{
-		Success:     true,
-		MergeCommit: mergeCommit,
+		Success:       true,
+		MergeCommit:   mergeCommit,
+		CommitsLanded: 1, // squash merge
 	}
 }
 
@@ -1095,6 +1110,10 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		}
 	}
 
+	if err := e.ensureFullCheckout(); err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
+	}
+
 	// Run the test command with retries for flaky tests
 	maxRetries := e.config.RetryFlakyTests
 	if maxRetries < 1 {
@@ -1140,6 +1159,20 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 }
 
+// ensureFullCheckout restores a full tree in the refinery's clone before
+// tests or gates run. Polecats may work in sparse checkouts; the refinery
+// validates everything, so a break outside a polecat's cone cannot merge.
+func (e *Engineer) ensureFullCheckout() error {
+	if !git.IsSparseCheckoutConfigured(e.workDir) {
+		return nil
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Sparse checkout in %s; restoring the full tree for validation\n", e.workDir)
+	if err := git.RemoveSparseCheckout(e.workDir); err != nil {
+		return fmt.Errorf("restoring full checkout for validation: %w", err)
+	}
+	return nil
+}
+
 // runGate executes a single quality gate command and returns the result.
 func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
 	start := time.Now()
@@ -1222,6 +1255,9 @@ func (e *Engineer) runGatesForPhase(ctx context.Context, phase GatePhase) Proces
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
 	}
+	if err := e.ensureFullCheckout(); err != nil {
+		return ProcessResult{Success: false, Error: err.Error()}
+	}
 
 	// Sort gate names for deterministic ordering
 	names := make([]string, 0, len(gates))
@@ -1438,9 +1474,32 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	}
 
 	// 5. Log success
+	e.logMergedEvent(mr, result)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// logMergedEvent records the merge in the activity feed. The source bead's
+// type and estimate ride along so convoy forecasting can bucket historical
+// sling-to-merge durations from the events log alone, and the commits
+// landed on the default branch feed the daemon's auto E-stop.
+func (e *Engineer) logMergedEvent(mr *MRInfo, result ProcessResult) {
+	var beadType string
+	var estimate int
+	if mr.SourceIssue != "" {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil {
+			beadType = issue.Type
+			estimate = issue.EstimatedMinutes
+		}
+	}
+	var mainCommits int
+	if mr.Target == e.rig.DefaultBranch() {
+		mainCommits = result.CommitsLanded
+	}
+	actor := e.rig.Name + "/refinery"
+	_ = events.LogFeed(events.TypeMerged, actor,
+		events.MergedPayload(mr.ID, mr.Worker, mr.Branch, mr.SourceIssue, beadType, estimate, mainCommits))
+}
+
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
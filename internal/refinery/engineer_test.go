@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -972,6 +973,46 @@ func TestRunGates_Empty(t *testing.T) {
 	}
 }
 
+func TestRunGates_SparseCheckoutRestoresFullTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c")
+	}
+	workDir := t.TempDir()
+	for _, f := range []string{"a/f", "b/f"} {
+		if err := os.MkdirAll(filepath.Join(workDir, filepath.Dir(f)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(workDir, f), []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "init"},
+		{"sparse-checkout", "set", "--cone", "a"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = workDir
+	e.output = io.Discard
+	e.config.Gates = map[string]*GateConfig{
+		"full-tree": {Cmd: "test -f b/f", Phase: GatePhasePostSquash},
+	}
+
+	result := e.runGatesForPhase(context.Background(), GatePhasePostSquash)
+	if !result.Success {
+		t.Errorf("post-squash gate should see the full tree, got: %s", result.Error)
+	}
+}
+
 func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 	// Test that DeleteMergedBranches is true by default
 	cfg := DefaultMergeQueueConfig()
@@ -1,6 +1,7 @@
 package refinery
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -8,7 +9,21 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	// Run from a scratch directory. The engineer logs merges and rejected
+	// pushes to the events file of the town found from the working
+	// directory, which from the package directory is the source tree.
+	scratch, err := os.MkdirTemp("", "gt-refinery-test-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create scratch dir: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(scratch); err != nil {
+		fmt.Fprintf(os.Stderr, "chdir to scratch dir: %v\n", err)
+		os.Exit(1)
+	}
+
 	code := m.Run()
 	testutil.TerminateDoltContainer()
+	_ = os.RemoveAll(scratch)
 	os.Exit(code)
 }
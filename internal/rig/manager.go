@@ -111,6 +111,24 @@ type RigConfig struct {
 	// PolecatNames optionally specifies fixed names (overrides theme-based naming).
 	PolecatPoolSize int      `json:"polecat_pool_size,omitempty"`
 	PolecatNames    []string `json:"polecat_names,omitempty"`
+
+	// Warm pool configuration.
+	// PolecatWarmPool is the number of idle polecats the daemon keeps prepared
+	// at the default branch so sling can claim one without building a worktree.
+	// PolecatWarmCommand optionally runs in each warm worktree after it is
+	// prepared (e.g. dependency install).
+	PolecatWarmPool    int    `json:"polecat_warm_pool,omitempty"`
+	PolecatWarmCommand string `json:"polecat_warm_command,omitempty"`
+
+	// WorktreeStrategy selects how polecat and crew worktrees are written:
+	// "worktree" (default, plain git worktree), "reflink" (copy-on-write
+	// copies of a template checkout, warning on fallback), or "auto".
+	WorktreeStrategy string `json:"worktree_strategy,omitempty"`
+
+	// SparseProfiles names path sets for cone-mode sparse checkouts of
+	// polecat worktrees in large monorepos. A bead selects one with a
+	// "sparse:<name>" label or the sparse_profile formula var.
+	SparseProfiles map[string][]string `json:"sparse_profiles,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for the rig.
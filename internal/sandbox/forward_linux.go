@@ -0,0 +1,162 @@
+package sandbox
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Loopback forwarding.
+//
+// A sandbox with its own network namespace cannot reach the host's loopback
+// interface. The host process keeps one end of a SOCK_SEQPACKET pair; the
+// init stage listens on each allowed port inside the namespace and, per
+// connection, asks the host to dial the port. The host replies with the
+// connected socket as an SCM_RIGHTS descriptor, which keeps its host network
+// namespace, and the init stage copies bytes between the two.
+
+const dialTimeout = 5 * time.Second
+
+// Broker replies carry one status byte.
+const (
+	brokerOK     byte = 0
+	brokerDenied byte = 1
+	brokerFailed byte = 2
+)
+
+// serveDials answers dial requests on fd until the sandbox side closes it.
+// Only the allowed ports are dialed, always on 127.0.0.1.
+func serveDials(fd int, allowed []int) {
+	permitted := make(map[int]bool, len(allowed))
+	for _, p := range allowed {
+		permitted[p] = true
+	}
+	buf := make([]byte, 2)
+	for {
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return
+		}
+		if n == 0 {
+			return
+		}
+		if n != 2 {
+			_, _ = unix.Write(fd, []byte{brokerFailed})
+			continue
+		}
+		port := int(binary.BigEndian.Uint16(buf))
+		if !permitted[port] {
+			_, _ = unix.Write(fd, []byte{brokerDenied})
+			continue
+		}
+		if err := sendDial(fd, port); err != nil {
+			_, _ = unix.Write(fd, []byte{brokerFailed})
+		}
+	}
+}
+
+func sendDial(fd, port int) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	f, err := conn.(*net.TCPConn).File()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return unix.Sendmsg(fd, []byte{brokerOK}, unix.UnixRights(int(f.Fd())), nil, 0)
+}
+
+// brokerClient requests host-side dials from inside the sandbox.
+type brokerClient struct {
+	mu sync.Mutex
+	fd int
+}
+
+func (b *brokerClient) dial(port int) (net.Conn, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	req := make([]byte, 2)
+	binary.BigEndian.PutUint16(req, uint16(port)) //nolint:gosec // G115: port validated to 1-65535
+	if _, err := unix.Write(b.fd, req); err != nil {
+		return nil, fmt.Errorf("requesting dial: %w", err)
+	}
+	status := make([]byte, 1)
+	oob := make([]byte, unix.CmsgSpace(4))
+	n, oobn, _, _, err := unix.Recvmsg(b.fd, status, oob, 0)
+	if err != nil {
+		return nil, fmt.Errorf("reading dial reply: %w", err)
+	}
+	if n != 1 || status[0] != brokerOK {
+		if n == 1 && status[0] == brokerDenied {
+			return nil, fmt.Errorf("port %d not allowed", port)
+		}
+		return nil, fmt.Errorf("host could not dial port %d", port)
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		return nil, errors.New("dial reply carried no descriptor")
+	}
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		return nil, errors.New("dial reply carried no descriptor")
+	}
+	f := os.NewFile(uintptr(fds[0]), "forwarded")
+	defer f.Close()
+	return net.FileConn(f)
+}
+
+// forwardPort accepts connections on the sandbox's loopback port and
+// splices each to a host connection obtained from the broker.
+func forwardPort(ln net.Listener, port int, broker *brokerClient) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			upstream, err := broker.dial(port)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "gt sandbox: forwarding port %d: %v\n", port, err)
+				_ = conn.Close()
+				return
+			}
+			splice(conn, upstream)
+		}()
+	}
+}
+
+// splice copies in both directions, half-closing each side as its peer
+// finishes writing.
+func splice(a, b net.Conn) {
+	var wg sync.WaitGroup
+	copyHalf := func(dst, src net.Conn) {
+		defer wg.Done()
+		_, _ = io.Copy(dst, src)
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		} else {
+			_ = dst.Close()
+		}
+	}
+	wg.Add(2)
+	go copyHalf(a, b)
+	go copyHalf(b, a)
+	wg.Wait()
+	_ = a.Close()
+	_ = b.Close()
+}
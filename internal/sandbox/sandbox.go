@@ -0,0 +1,264 @@
+// Package sandbox confines agent processes with Linux namespaces and Landlock.
+//
+// It backs `gt sandbox exec`, the built-in exec wrapper rigs select with the
+// sandbox.profile setting. The wrapper re-executes gt in new user, mount and
+// network namespaces, restricts filesystem writes with Landlock, and forwards
+// only the loopback ports the control plane needs (Dolt and the proxy).
+//
+// Reads and process execution stay unrestricted: agents need the toolchain,
+// system libraries and their own configuration. The boundary is where they
+// can write and whom they can talk to.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Network is a profile's network policy.
+type Network string
+
+const (
+	// NetworkLoopback gives the sandbox its own network namespace in which
+	// only the allowed loopback ports are reachable, forwarded to the host.
+	NetworkLoopback Network = "loopback"
+
+	// NetworkNone gives the sandbox an empty network namespace.
+	NetworkNone Network = "none"
+
+	// NetworkHost leaves networking unrestricted.
+	NetworkHost Network = "host"
+)
+
+// DefaultProfile is the profile used when none is named.
+const DefaultProfile = "polecat"
+
+// DefaultDoltPort is the Dolt SQL port assumed when GT_DOLT_PORT is unset.
+const DefaultDoltPort = 3307
+
+// ErrUnsupported is returned on platforms or kernels without the required
+// namespace and Landlock support.
+var ErrUnsupported = errors.New("built-in sandbox not supported")
+
+// Profile describes what a sandboxed process may write and reach.
+type Profile struct {
+	Name        string
+	Description string
+	Network     Network
+
+	// PrivateTmp mounts a fresh tmpfs over /tmp instead of sharing the
+	// host's /tmp.
+	PrivateTmp bool
+
+	// HomeWritable lists paths relative to $HOME the agent may write, for
+	// runtime state such as session logs and credentials caches.
+	HomeWritable []string
+}
+
+var profiles = map[string]*Profile{
+	"polecat": {
+		Name:         "polecat",
+		Description:  "Write worktree and tmp; network limited to Dolt and proxy on loopback",
+		Network:      NetworkLoopback,
+		HomeWritable: []string{".claude", ".claude.json", ".cache"},
+	},
+	"offline": {
+		Name:         "offline",
+		Description:  "Write worktree and a private tmp; no network",
+		Network:      NetworkNone,
+		PrivateTmp:   true,
+		HomeWritable: []string{".claude", ".claude.json", ".cache"},
+	},
+	"network": {
+		Name:         "network",
+		Description:  "Write worktree and tmp; unrestricted network",
+		Network:      NetworkHost,
+		HomeWritable: []string{".claude", ".claude.json", ".cache"},
+	},
+}
+
+// LookupProfile returns the built-in profile with the given name.
+func LookupProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	p, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown sandbox profile %q (available: %s)", name, strings.Join(ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// ProfileNames returns the built-in profile names, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options configures one sandboxed execution.
+type Options struct {
+	Profile *Profile
+
+	// Worktree is the directory the agent works in and may write. Defaults
+	// to the current directory.
+	Worktree string
+
+	// Writable lists extra paths the agent may write.
+	Writable []string
+
+	// Ports lists the host loopback ports reachable under NetworkLoopback.
+	Ports []int
+
+	// Command is the program and arguments to run.
+	Command []string
+
+	// Stdin, Stdout and Stderr default to the process's own.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// DefaultPorts returns the loopback ports a polecat's control plane uses:
+// the Dolt SQL server and, when GT_PROXY_URL points at this host, the proxy.
+func DefaultPorts(getenv func(string) string) []int {
+	doltPort := DefaultDoltPort
+	if v := getenv("GT_DOLT_PORT"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil && validPort(p) {
+			doltPort = p
+		}
+	}
+	ports := []int{doltPort}
+	if raw := getenv("GT_PROXY_URL"); raw != "" {
+		if u, err := url.Parse(raw); err == nil && isLoopbackHost(u.Hostname()) {
+			if p, err := strconv.Atoi(u.Port()); err == nil && validPort(p) {
+				ports = append(ports, p)
+			}
+		}
+	}
+	return ports
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isWithin reports whether path is dir or lies beneath it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+func validPort(p int) bool {
+	return p > 0 && p <= 65535
+}
+
+// writablePaths returns the resolved, existing paths the sandbox may write:
+// the worktree, tmp directories, the profile's home paths, the extras, and
+// the character devices programs expect to open for writing.
+func writablePaths(p *Profile, worktree string, extra []string) []string {
+	candidates := []string{worktree, "/tmp", "/var/tmp", "/dev/shm", os.TempDir()}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, rel := range p.HomeWritable {
+			candidates = append(candidates, filepath.Join(home, rel))
+		}
+	}
+	candidates = append(candidates, extra...)
+	candidates = append(candidates, "/dev/null", "/dev/zero", "/dev/full", "/dev/tty", "/dev/ptmx", "/dev/pts")
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		resolved, err := filepath.EvalSymlinks(c)
+		if err != nil {
+			continue
+		}
+		if abs, err := filepath.Abs(resolved); err == nil {
+			resolved = abs
+		}
+		if !seen[resolved] {
+			seen[resolved] = true
+			out = append(out, resolved)
+		}
+	}
+	return out
+}
+
+// spec is the resolved configuration handed from the host process to the
+// sandbox stages through the environment.
+type spec struct {
+	Profile    string   `json:"profile"`
+	Network    Network  `json:"network"`
+	PrivateTmp bool     `json:"private_tmp,omitempty"`
+	Writable   []string `json:"writable"`
+	Ports      []int    `json:"ports,omitempty"`
+	Command    []string `json:"command"`
+}
+
+func newSpec(opts Options) (*spec, error) {
+	if len(opts.Command) == 0 {
+		return nil, errors.New("no command to run")
+	}
+	p := opts.Profile
+	if p == nil {
+		var err error
+		if p, err = LookupProfile(""); err != nil {
+			return nil, err
+		}
+	}
+	worktree := opts.Worktree
+	if worktree == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("getting working directory: %w", err)
+		}
+		worktree = wd
+	}
+	if info, err := os.Stat(worktree); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("worktree %s is not a directory", worktree)
+	}
+	if p.PrivateTmp {
+		if resolved, err := filepath.EvalSymlinks(worktree); err == nil && isWithin(resolved, "/tmp") {
+			return nil, fmt.Errorf("profile %s mounts a private /tmp, which would hide worktree %s", p.Name, worktree)
+		}
+	}
+	s := &spec{
+		Profile:    p.Name,
+		Network:    p.Network,
+		PrivateTmp: p.PrivateTmp,
+		Writable:   writablePaths(p, worktree, opts.Writable),
+		Command:    opts.Command,
+	}
+	if p.Network == NetworkLoopback {
+		seen := make(map[int]bool)
+		for _, port := range opts.Ports {
+			if !validPort(port) {
+				return nil, fmt.Errorf("invalid port %d", port)
+			}
+			if !seen[port] {
+				seen[port] = true
+				s.Ports = append(s.Ports, port)
+			}
+		}
+		sort.Ints(s.Ports)
+	}
+	return s, nil
+}
@@ -0,0 +1,343 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// The sandbox runs in three processes, all the gt binary:
+//
+//	host     gt sandbox exec: starts init in new namespaces, brokers dials
+//	init     inside the namespaces: mounts, brings up lo, forwards ports
+//	confine  applies no_new_privs and Landlock, then execs the command
+//
+// Init and confine are selected by stageEnv; RunStageIfRequested must run
+// at the top of main so they never reach the CLI.
+const (
+	stageEnv = "GT_SANDBOX_STAGE"
+	specEnv  = "GT_SANDBOX_SPEC"
+
+	stageInit    = "init"
+	stageConfine = "confine"
+	stageProbe   = "probe"
+
+	// ProfileEnv is set in the sandboxed command's environment to the name
+	// of the active profile.
+	ProfileEnv = "GT_SANDBOX_PROFILE"
+
+	// brokerFD is where init receives its end of the dial broker socket.
+	brokerFD = 3
+
+	// setupFailedExit is the exit status when a stage cannot build the
+	// sandbox, distinct from the command's own failures.
+	setupFailedExit = 125
+)
+
+// Exec runs opts.Command inside the sandbox and returns its exit status.
+// Errors are returned only when the sandbox could not be started.
+func Exec(opts Options) (int, error) {
+	s, err := newSpec(opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := Check(); err != nil {
+		return 0, err
+	}
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd, err := stageCommand(stageInit, s.Network != NetworkHost)
+	if err != nil {
+		return 0, err
+	}
+	cmd.Env = append(cmd.Env, specEnv+"="+string(encoded))
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = opts.Stdin, opts.Stdout, opts.Stderr
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	hostEnd := -1
+	if s.Network == NetworkLoopback && len(s.Ports) > 0 {
+		pair, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_SEQPACKET|unix.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return 0, fmt.Errorf("creating dial broker socket: %w", err)
+		}
+		hostEnd = pair[0]
+		sandboxEnd := os.NewFile(uintptr(pair[1]), "broker")
+		defer sandboxEnd.Close()
+		cmd.ExtraFiles = []*os.File{sandboxEnd}
+	}
+
+	if err := cmd.Start(); err != nil {
+		if hostEnd >= 0 {
+			_ = unix.Close(hostEnd)
+		}
+		return 0, fmt.Errorf("starting sandbox: %w", err)
+	}
+	if hostEnd >= 0 {
+		go func() {
+			serveDials(hostEnd, s.Ports)
+			_ = unix.Close(hostEnd)
+		}()
+	}
+	stop := relaySignals(cmd.Process)
+	defer stop()
+	return waitStatus(cmd.Wait())
+}
+
+// Check reports whether this kernel can run the sandbox: Landlock must be
+// enabled and unprivileged user namespaces allowed.
+func Check() error {
+	if landlockABI() < 1 {
+		return fmt.Errorf("%w: Landlock is not enabled in this kernel (needs Linux 5.13+ with lsm=landlock)", ErrUnsupported)
+	}
+	cmd, err := stageCommand(stageProbe, true)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: cannot create user namespaces: %v %s", ErrUnsupported, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// LandlockABI returns the kernel's Landlock ABI version, 0 if unavailable.
+func LandlockABI() int {
+	return landlockABI()
+}
+
+// stageCommand returns a command that re-executes this binary as the given
+// stage inside new user and mount namespaces, and a new network namespace
+// when isolateNet is set. The caller's uid and gid map to themselves, so
+// files created inside belong to the invoking user.
+func stageCommand(stage string, isolateNet bool) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating gt binary: %w", err)
+	}
+	flags := uintptr(unix.CLONE_NEWUSER | unix.CLONE_NEWNS)
+	if isolateNet {
+		flags |= unix.CLONE_NEWNET
+	}
+	uid, gid := os.Getuid(), os.Getgid()
+	cmd := exec.Command(self)
+	cmd.Env = append(os.Environ(), stageEnv+"="+stage)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:                 flags,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		// Init is not root inside the namespace; ambient capabilities let
+		// it mount and configure lo. Confine clears them before exec.
+		AmbientCaps: []uintptr{unix.CAP_SYS_ADMIN, unix.CAP_NET_ADMIN, unix.CAP_NET_BIND_SERVICE},
+		Pdeathsig:   syscall.SIGKILL,
+	}
+	return cmd, nil
+}
+
+// RunStageIfRequested runs the sandbox stage named in the environment and
+// exits. It returns immediately in ordinary invocations.
+func RunStageIfRequested() {
+	stage := os.Getenv(stageEnv)
+	if stage == "" {
+		return
+	}
+	if stage == stageProbe {
+		os.Exit(0)
+	}
+	var s spec
+	if err := json.Unmarshal([]byte(os.Getenv(specEnv)), &s); err != nil {
+		stageFail(fmt.Errorf("reading sandbox spec: %w", err))
+	}
+	switch stage {
+	case stageInit:
+		os.Exit(runInit(&s))
+	case stageConfine:
+		stageFail(runConfine(&s))
+	default:
+		stageFail(fmt.Errorf("unknown sandbox stage %q", stage))
+	}
+}
+
+func stageFail(err error) {
+	fmt.Fprintf(os.Stderr, "gt sandbox: %v\n", err)
+	os.Exit(setupFailedExit)
+}
+
+// runInit prepares the namespaces, starts the port forwarders, and runs the
+// confine stage as its child.
+func runInit(s *spec) int {
+	// Hold the binary open: a private /tmp may hide the path it runs from.
+	self, err := os.Open("/proc/self/exe")
+	if err != nil {
+		stageFail(fmt.Errorf("opening gt binary: %w", err))
+	}
+	defer self.Close()
+
+	// Keep mounts made here out of the host's mount tree.
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		stageFail(fmt.Errorf("making mounts private: %w", err))
+	}
+	if s.PrivateTmp {
+		if err := unix.Mount("tmpfs", "/tmp", "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=1777"); err != nil {
+			stageFail(fmt.Errorf("mounting private /tmp: %w", err))
+		}
+	}
+	if s.Network != NetworkHost {
+		if err := loopbackUp(); err != nil {
+			stageFail(err)
+		}
+	}
+	if s.Network == NetworkLoopback && len(s.Ports) > 0 {
+		unix.CloseOnExec(brokerFD)
+		broker := &brokerClient{fd: brokerFD}
+		for _, port := range s.Ports {
+			ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+			if err != nil {
+				stageFail(fmt.Errorf("listening on loopback port %d: %w", port, err))
+			}
+			go forwardPort(ln, port, broker)
+			// Best effort: clients resolving localhost may try ::1 first.
+			if ln6, err := net.Listen("tcp", net.JoinHostPort("::1", strconv.Itoa(port))); err == nil {
+				go forwardPort(ln6, port, broker)
+			}
+		}
+	}
+
+	cmd := exec.Command(fmt.Sprintf("/proc/self/fd/%d", self.Fd()))
+	cmd.Env = replaceEnv(os.Environ(), stageEnv, stageConfine)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	if err := cmd.Start(); err != nil {
+		stageFail(fmt.Errorf("starting command: %w", err))
+	}
+	stop := relaySignals(cmd.Process)
+	defer stop()
+	code, err := waitStatus(cmd.Wait())
+	if err != nil {
+		stageFail(err)
+	}
+	return code
+}
+
+// runConfine drops capabilities, enforces the write restrictions, and
+// replaces itself with the command. It only returns on failure.
+func runConfine(s *spec) error {
+	// Landlock and no_new_privs apply to the calling thread; exec from the
+	// same thread so the command inherits both.
+	runtime.LockOSThread()
+
+	if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+		return fmt.Errorf("clearing ambient capabilities: %w", err)
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("setting no_new_privs: %w", err)
+	}
+	if err := restrictWrites(s.Writable); err != nil {
+		return err
+	}
+
+	path, err := exec.LookPath(s.Command[0])
+	if err != nil {
+		return err
+	}
+	env := os.Environ()
+	env = removeEnv(env, stageEnv)
+	env = removeEnv(env, specEnv)
+	env = replaceEnv(env, ProfileEnv, s.Profile)
+	return syscall.Exec(path, s.Command, env) //nolint:gosec // G204: the command is what the user asked to sandbox
+}
+
+// loopbackUp brings up lo in a fresh network namespace.
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("opening control socket: %w", err)
+	}
+	defer unix.Close(fd)
+	ifr, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("reading lo flags: %w", err)
+	}
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+	if err := unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("bringing up lo: %w", err)
+	}
+	return nil
+}
+
+// relaySignals forwards termination signals to p. SIGINT is swallowed
+// rather than forwarded: the terminal already delivers it to the whole
+// foreground process group, and the wrapper must outlive it.
+func relaySignals(p *os.Process) (stop func()) {
+	ch := make(chan os.Signal, 4)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				if sig != syscall.SIGINT {
+					_ = p.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// waitStatus converts a Wait result into a shell-style exit status.
+func waitStatus(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, err
+	}
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal()), nil
+	}
+	return exitErr.ExitCode(), nil
+}
+
+func replaceEnv(env []string, key, value string) []string {
+	return append(removeEnv(env, key), key+"="+value)
+}
+
+func removeEnv(env []string, key string) []string {
+	out := env[:0:0]
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, key+"=") {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
@@ -0,0 +1,260 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// These tests mirror the macOS sandbox-exec tests in
+// internal/config/sandbox_integration_test.go against the built-in Linux
+// sandbox:
+//
+//   - File writes restricted to the worktree and tmp
+//   - File reads allowed broadly
+//   - Network restricted to the forwarded loopback ports
+//   - External network access denied
+
+func skipIfNoSandbox(t *testing.T) {
+	t.Helper()
+	if err := Check(); err != nil {
+		t.Skipf("sandbox unavailable: %v", err)
+	}
+}
+
+func skipIfNoCurl(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl not found in PATH")
+	}
+}
+
+// runInSandbox executes a shell command inside the sandbox and returns
+// stdout, stderr and the exit status.
+func runInSandbox(t *testing.T, profile, worktree string, ports []int, shellCmd string) (stdout, stderr string, code int) {
+	t.Helper()
+	p, err := LookupProfile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outBuf, errBuf strings.Builder
+	code, err = Exec(Options{
+		Profile:  p,
+		Worktree: worktree,
+		Ports:    ports,
+		Command:  []string{"/bin/sh", "-c", shellCmd},
+		Stdin:    strings.NewReader(""),
+		Stdout:   &outBuf,
+		Stderr:   &errBuf,
+	})
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	return outBuf.String(), errBuf.String(), code
+}
+
+// outsidePath returns a path in the home directory, outside the worktree
+// and tmp, removing it after the test.
+func outsidePath(t *testing.T, name string) string {
+	t.Helper()
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("get home dir: %v", err)
+	}
+	path := filepath.Join(home, fmt.Sprintf(".%s-%d", name, os.Getpid()))
+	t.Cleanup(func() { _ = os.Remove(path) })
+	return path
+}
+
+// startLoopbackServer serves a fixed body on 127.0.0.1 and returns its port.
+func startLoopbackServer(t *testing.T, body string) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("start test server: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})}
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestSandbox_WriteInsideWorktree(t *testing.T) {
+	skipIfNoSandbox(t)
+	t.Parallel()
+
+	worktree := t.TempDir()
+	testFile := filepath.Join(worktree, "test-write.txt")
+	shellCmd := fmt.Sprintf("echo 'sandbox write test' > %q && cat %q", testFile, testFile)
+
+	stdout, stderr, code := runInSandbox(t, "polecat", worktree, nil, shellCmd)
+	if code != 0 {
+		t.Fatalf("write inside worktree should succeed: code=%d stderr=%q", code, stderr)
+	}
+	if !strings.Contains(stdout, "sandbox write test") {
+		t.Errorf("expected written content in stdout, got: %q", stdout)
+	}
+}
+
+func TestSandbox_ReadOutsideWorktree(t *testing.T) {
+	skipIfNoSandbox(t)
+	t.Parallel()
+
+	stdout, stderr, code := runInSandbox(t, "polecat", t.TempDir(), nil, "head -c 4 /etc/passwd")
+	if code != 0 || stdout == "" {
+		t.Fatalf("reads outside the worktree should succeed: code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+}
+
+func TestSandbox_DenyWriteOutsideWorktree(t *testing.T) {
+	skipIfNoSandbox(t)
+	t.Parallel()
+
+	forbiddenFile := outsidePath(t, "sandbox-test-should-not-exist")
+	shellCmd := fmt.Sprintf("echo 'breach' > %q 2>&1; echo exit=$?", forbiddenFile)
+	stdout, _, _ := runInSandbox(t, "polecat", t.TempDir(), nil, shellCmd)
+
+	if _, err := os.Stat(forbiddenFile); err == nil {
+		t.Fatal("sandbox allowed write outside worktree — file was created")
+	}
+	if strings.Contains(stdout, "exit=0") {
+		t.Error("expected non-zero exit from write attempt outside worktree")
+	}
+}
+
+func TestSandbox_DenyRenameOutOfWorktree(t *testing.T) {
+	skipIfNoSandbox(t)
+	t.Parallel()
+
+	worktree := t.TempDir()
+	target := outsidePath(t, "sandbox-rename-target")
+	shellCmd := fmt.Sprintf("echo x > %q/f && mv %q/f %q 2>/dev/null; echo exit=$?", worktree, worktree, target)
+	stdout, _, _ := runInSandbox(t, "polecat", worktree, nil, shellCmd)
+
+	if _, err := os.Stat(target); err == nil {
+		t.Fatal("sandbox allowed moving a file out of the worktree")
+	}
+	if strings.Contains(stdout, "exit=0") {
+		t.Error("expected non-zero exit from rename out of worktree")
+	}
+}
+
+func TestSandbox_DenyExternalNetwork(t *testing.T) {
+	skipIfNoSandbox(t)
+	skipIfNoCurl(t)
+	t.Parallel()
+
+	shellCmd := `curl -s --connect-timeout 3 http://1.1.1.1/ 2>&1; echo "exit=$?"`
+	stdout, _, _ := runInSandbox(t, "polecat", t.TempDir(), nil, shellCmd)
+	if strings.Contains(stdout, "exit=0") {
+		t.Fatal("sandbox allowed external network connection — expected denial")
+	}
+}
+
+func TestSandbox_DenyDNSExfiltration(t *testing.T) {
+	skipIfNoSandbox(t)
+	skipIfNoCurl(t)
+	t.Parallel()
+
+	shellCmd := `curl -s --connect-timeout 3 http://example.com/ 2>&1; echo "exit=$?"`
+	stdout, _, _ := runInSandbox(t, "polecat", t.TempDir(), nil, shellCmd)
+	if strings.Contains(stdout, "exit=0") {
+		t.Fatal("sandbox allowed external DNS resolution + connection")
+	}
+}
+
+func TestSandbox_AllowForwardedLoopbackPort(t *testing.T) {
+	skipIfNoSandbox(t)
+	skipIfNoCurl(t)
+	t.Parallel()
+
+	port := startLoopbackServer(t, "sandbox-loopback-ok")
+	shellCmd := fmt.Sprintf("curl -s http://127.0.0.1:%d/health 2>&1", port)
+	stdout, stderr, code := runInSandbox(t, "polecat", t.TempDir(), []int{port}, shellCmd)
+	if code != 0 {
+		t.Fatalf("loopback connection should succeed: code=%d stderr=%q stdout=%q", code, stderr, stdout)
+	}
+	if !strings.Contains(stdout, "sandbox-loopback-ok") {
+		t.Errorf("expected loopback response, got: %q", stdout)
+	}
+}
+
+func TestSandbox_DenyUnlistedLoopbackPort(t *testing.T) {
+	skipIfNoSandbox(t)
+	skipIfNoCurl(t)
+	t.Parallel()
+
+	allowed := startLoopbackServer(t, "allowed")
+	other := startLoopbackServer(t, "should-not-reach")
+	shellCmd := fmt.Sprintf(`curl -s --connect-timeout 3 http://127.0.0.1:%d/ 2>&1; echo "exit=$?"`, other)
+	stdout, _, _ := runInSandbox(t, "polecat", t.TempDir(), []int{allowed}, shellCmd)
+	if strings.Contains(stdout, "should-not-reach") || strings.Contains(stdout, "exit=0") {
+		t.Fatalf("sandbox reached a loopback port outside the allowlist: %q", stdout)
+	}
+}
+
+func TestSandbox_OfflineProfileHasNoLoopbackForwarding(t *testing.T) {
+	skipIfNoSandbox(t)
+	skipIfNoCurl(t)
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("get home dir: %v", err)
+	}
+	// The offline profile mounts a private /tmp, so the worktree must live
+	// elsewhere.
+	worktree, err := os.MkdirTemp(home, ".sandbox-offline-")
+	if err != nil {
+		t.Fatalf("create worktree: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(worktree) })
+
+	port := startLoopbackServer(t, "should-not-reach")
+	shellCmd := fmt.Sprintf(`curl -s --connect-timeout 3 http://127.0.0.1:%d/ 2>&1; echo "exit=$?"; ls /tmp | wc -l`, port)
+	stdout, _, _ := runInSandbox(t, "offline", worktree, []int{port}, shellCmd)
+	if strings.Contains(stdout, "should-not-reach") || strings.Contains(stdout, "exit=0") {
+		t.Fatalf("offline profile reached the host loopback: %q", stdout)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "0" {
+		t.Errorf("private /tmp is not empty: %q", stdout)
+	}
+}
+
+func TestSandbox_ExitStatusAndProfileEnv(t *testing.T) {
+	skipIfNoSandbox(t)
+	t.Parallel()
+
+	stdout, _, code := runInSandbox(t, "network", t.TempDir(), nil, `echo "$GT_SANDBOX_PROFILE $GT_SANDBOX_STAGE"; exit 7`)
+	if code != 7 {
+		t.Errorf("exit status = %d, want 7", code)
+	}
+	if strings.TrimSpace(stdout) != "network" {
+		t.Errorf("sandbox env = %q, want profile name and no stage marker", stdout)
+	}
+}
+
+// TestSandbox_NoCapabilitiesInside verifies the command does not inherit the
+// namespace capabilities init uses, so it cannot undo the mounts.
+func TestSandbox_NoCapabilitiesInside(t *testing.T) {
+	skipIfNoSandbox(t)
+	if os.Getuid() == 0 {
+		t.Skip("root keeps capabilities inside its user namespace")
+	}
+	t.Parallel()
+
+	stdout, _, _ := runInSandbox(t, "polecat", t.TempDir(), nil, `grep -E '^Cap(Eff|Amb)' /proc/self/status`)
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		if !strings.HasSuffix(line, "0000000000000000") {
+			t.Errorf("capabilities leaked into sandbox: %q", line)
+		}
+	}
+}
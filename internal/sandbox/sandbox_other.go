@@ -0,0 +1,30 @@
+//go:build !linux
+
+package sandbox
+
+import "fmt"
+
+// ProfileEnv is set in the sandboxed command's environment to the name of
+// the active profile.
+const ProfileEnv = "GT_SANDBOX_PROFILE"
+
+// Exec is unavailable off Linux; use an exec-wrapper plugin such as exitbox.
+func Exec(opts Options) (int, error) {
+	if _, err := newSpec(opts); err != nil {
+		return 0, err
+	}
+	return 0, Check()
+}
+
+// Check reports that the built-in sandbox needs Linux.
+func Check() error {
+	return fmt.Errorf("%w: requires Linux namespaces and Landlock; use an exec-wrapper plugin such as exitbox on this platform", ErrUnsupported)
+}
+
+// LandlockABI returns 0: Landlock is Linux-only.
+func LandlockABI() int {
+	return 0
+}
+
+// RunStageIfRequested is a no-op off Linux.
+func RunStageIfRequested() {}
@@ -0,0 +1,98 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Exec re-executes the running binary as its sandbox stages; in tests
+	// that binary is the test binary.
+	RunStageIfRequested()
+	os.Exit(m.Run())
+}
+
+func TestLookupProfile(t *testing.T) {
+	p, err := LookupProfile("")
+	if err != nil || p.Name != DefaultProfile || p.Network != NetworkLoopback {
+		t.Fatalf("LookupProfile(\"\") = %+v, %v", p, err)
+	}
+	if _, err := LookupProfile("nope"); err == nil || !strings.Contains(err.Error(), "polecat") {
+		t.Errorf("unknown profile error = %v, want list of available profiles", err)
+	}
+}
+
+func TestDefaultPorts(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []int
+	}{
+		{"defaults", nil, []int{DefaultDoltPort}},
+		{"dolt override", map[string]string{"GT_DOLT_PORT": "3400"}, []int{3400}},
+		{"bad dolt port", map[string]string{"GT_DOLT_PORT": "x"}, []int{DefaultDoltPort}},
+		{"loopback proxy", map[string]string{"GT_PROXY_URL": "https://127.0.0.1:9876"}, []int{DefaultDoltPort, 9876}},
+		{"localhost proxy", map[string]string{"GT_PROXY_URL": "https://localhost:9876"}, []int{DefaultDoltPort, 9876}},
+		{"remote proxy", map[string]string{"GT_PROXY_URL": "https://build.local:9876"}, []int{DefaultDoltPort}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultPorts(func(k string) string { return tt.env[k] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultPorts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSpec(t *testing.T) {
+	worktree := t.TempDir()
+	extra := t.TempDir()
+	p, _ := LookupProfile("polecat")
+
+	s, err := newSpec(Options{
+		Profile:  p,
+		Worktree: worktree,
+		Writable: []string{extra, filepath.Join(extra, "missing")},
+		Ports:    []int{9876, 3307, 9876},
+		Command:  []string{"true"},
+	})
+	if err != nil {
+		t.Fatalf("newSpec: %v", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(worktree)
+	if !containsString(s.Writable, resolved) {
+		t.Errorf("writable %v missing worktree %s", s.Writable, resolved)
+	}
+	for _, w := range s.Writable {
+		if strings.HasSuffix(w, "missing") {
+			t.Errorf("nonexistent path %s kept in writable list", w)
+		}
+	}
+	if !reflect.DeepEqual(s.Ports, []int{3307, 9876}) {
+		t.Errorf("ports = %v, want deduplicated and sorted", s.Ports)
+	}
+
+	if _, err := newSpec(Options{Profile: p, Worktree: worktree}); err == nil {
+		t.Error("newSpec accepted an empty command")
+	}
+	if _, err := newSpec(Options{Profile: p, Worktree: worktree, Ports: []int{70000}, Command: []string{"true"}}); err == nil {
+		t.Error("newSpec accepted an out-of-range port")
+	}
+	offline, _ := LookupProfile("offline")
+	if _, err := newSpec(Options{Profile: offline, Worktree: worktree, Command: []string{"true"}}); err == nil && isWithin(resolved, "/tmp") {
+		t.Error("private-tmp profile accepted a worktree under /tmp")
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -105,6 +105,11 @@ type recorderInstruments struct {
 
 	// Histograms
 	bdDurationHist metric.Float64Histogram
+
+	// Gauges
+	sessionCPUGauge    metric.Float64Gauge
+	sessionMemoryGauge metric.Int64Gauge
+	sessionPidsGauge   metric.Int64Gauge
 }
 
 var (
@@ -222,6 +227,19 @@ func initInstruments() {
 			metric.WithDescription("bd CLI call round-trip latency in milliseconds"),
 			metric.WithUnit("ms"),
 		)
+
+		// Gauges
+		inst.sessionCPUGauge, _ = m.Float64Gauge("gastown.session.cpu.seconds",
+			metric.WithDescription("CPU time consumed by an agent session's cgroup"),
+			metric.WithUnit("s"),
+		)
+		inst.sessionMemoryGauge, _ = m.Int64Gauge("gastown.session.memory.bytes",
+			metric.WithDescription("Current memory of an agent session's cgroup"),
+			metric.WithUnit("By"),
+		)
+		inst.sessionPidsGauge, _ = m.Int64Gauge("gastown.session.pids",
+			metric.WithDescription("Processes and threads in an agent session's cgroup"),
+		)
 	})
 }
 
@@ -628,6 +646,20 @@ func RecordDaemonRestart(ctx context.Context, agentType string) {
 	)
 }
 
+// RecordSessionResources records a session cgroup's resource usage (metrics
+// only). Called by the daemon on each heartbeat; rig and session name the
+// cgroup, e.g. "gastown" and "polecat-Toast".
+func RecordSessionResources(ctx context.Context, rig, session string, cpuSeconds float64, memoryBytes int64, pids int) {
+	initInstruments()
+	attrs := metric.WithAttributes(
+		attribute.String("rig", rig),
+		attribute.String("session", session),
+	)
+	inst.sessionCPUGauge.Record(ctx, cpuSeconds, attrs)
+	inst.sessionMemoryGauge.Record(ctx, memoryBytes, attrs)
+	inst.sessionPidsGauge.Record(ctx, int64(pids), attrs)
+}
+
 // RecordFormulaInstantiate records a formula→wisp instantiation (metrics + log event).
 func RecordFormulaInstantiate(ctx context.Context, formulaName, beadID string, err error) {
 	initInstruments()
@@ -0,0 +1,82 @@
+package replay
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the key bindings for the replay TUI.
+type KeyMap struct {
+	Next        key.Binding
+	Prev        key.Binding
+	First       key.Binding
+	Last        key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
+	ChangedOnly key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Next: key.NewBinding(
+			key.WithKeys("right", "l", "n"),
+			key.WithHelp("→/n", "next call"),
+		),
+		Prev: key.NewBinding(
+			key.WithKeys("left", "h", "p"),
+			key.WithHelp("←/p", "previous call"),
+		),
+		First: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g", "first call"),
+		),
+		Last: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G", "last call"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "scroll up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "scroll down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "ctrl+u"),
+			key.WithHelp("pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "ctrl+d", " "),
+			key.WithHelp("pgdn", "page down"),
+		),
+		ChangedOnly: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "changes only"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to show in the help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Prev, k.Next, k.Down, k.ChangedOnly, k.Quit, k.Help}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Prev, k.Next, k.First, k.Last},
+		{k.Up, k.Down, k.PageUp, k.PageDown},
+		{k.ChangedOnly, k.Help, k.Quit},
+	}
+}
@@ -0,0 +1,185 @@
+package replay
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+)
+
+// listRows is how many steps the step list shows around the cursor.
+const listRows = 7
+
+// DiffFunc returns the diff a recorded step produced.
+type DiffFunc func(step checkpoint.Step) (string, error)
+
+// Model is the bubbletea model for stepping through a session recording.
+type Model struct {
+	rec  *checkpoint.Recording
+	diff DiffFunc
+
+	cursor      int            // index into rec.Steps
+	changedOnly bool           // skip steps that changed nothing
+	diffs       map[int]string // rendered diff per step index
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	diffView viewport.Model
+	width    int
+	height   int
+
+	// mu protects all fields read by View() from concurrent access.
+	// Write lock is held during Update mutations; read lock during View/render.
+	mu sync.RWMutex
+}
+
+// New creates a replay model for a recording. diff is called lazily, once
+// per step, when the step is first shown.
+func New(rec *checkpoint.Recording, diff DiffFunc) *Model {
+	m := &Model{
+		rec:      rec,
+		diff:     diff,
+		diffs:    make(map[int]string),
+		keys:     DefaultKeyMap(),
+		help:     help.New(),
+		diffView: viewport.New(0, 0),
+	}
+	// Open on the first call that changed something; reads before it are
+	// context, not the interesting part.
+	for i, s := range rec.Steps {
+		if s.Changed() {
+			m.cursor = i
+			break
+		}
+	}
+	m.showStepLocked()
+	return m
+}
+
+// Init initializes the model.
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.mu.Lock()
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.layoutLocked()
+		m.mu.Unlock()
+		return m, nil
+
+	case tea.KeyMsg:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = !m.showHelp
+			m.layoutLocked()
+		case key.Matches(msg, m.keys.Next):
+			m.moveLocked(1)
+		case key.Matches(msg, m.keys.Prev):
+			m.moveLocked(-1)
+		case key.Matches(msg, m.keys.First):
+			m.jumpLocked(0, 1)
+		case key.Matches(msg, m.keys.Last):
+			m.jumpLocked(len(m.rec.Steps)-1, -1)
+		case key.Matches(msg, m.keys.ChangedOnly):
+			m.changedOnly = !m.changedOnly
+			if m.changedOnly && !m.visibleLocked(m.cursor) {
+				if !m.moveLocked(1) {
+					m.moveLocked(-1)
+				}
+			}
+		case key.Matches(msg, m.keys.Up):
+			m.diffView.ScrollUp(1)
+		case key.Matches(msg, m.keys.Down):
+			m.diffView.ScrollDown(1)
+		case key.Matches(msg, m.keys.PageUp):
+			m.diffView.PageUp()
+		case key.Matches(msg, m.keys.PageDown):
+			m.diffView.PageDown()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// View renders the model.
+// Acquires read lock to safely access all View-visible fields.
+func (m *Model) View() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.renderView()
+}
+
+// visibleLocked reports whether step i is shown under the current filter.
+// Caller must hold m.mu.
+func (m *Model) visibleLocked(i int) bool {
+	return !m.changedOnly || m.rec.Steps[i].Changed()
+}
+
+// moveLocked moves the cursor to the next visible step in direction dir.
+// Returns false if there is none. Caller must hold m.mu write lock.
+func (m *Model) moveLocked(dir int) bool {
+	for i := m.cursor + dir; i >= 0 && i < len(m.rec.Steps); i += dir {
+		if m.visibleLocked(i) {
+			m.cursor = i
+			m.showStepLocked()
+			return true
+		}
+	}
+	return false
+}
+
+// jumpLocked moves to the first visible step at or after from in direction
+// dir. Caller must hold m.mu write lock.
+func (m *Model) jumpLocked(from, dir int) {
+	if from < 0 || from >= len(m.rec.Steps) {
+		return
+	}
+	m.cursor = from - dir
+	if !m.moveLocked(dir) {
+		m.cursor = from
+		m.showStepLocked()
+	}
+}
+
+// showStepLocked loads the current step's diff into the viewport.
+// Caller must hold m.mu write lock.
+func (m *Model) showStepLocked() {
+	if len(m.rec.Steps) == 0 {
+		return
+	}
+	content, ok := m.diffs[m.cursor]
+	if !ok {
+		content = m.renderDiff(m.rec.Steps[m.cursor])
+		m.diffs[m.cursor] = content
+	}
+	m.layoutLocked()
+	m.diffView.SetContent(content)
+	m.diffView.GotoTop()
+}
+
+// layoutLocked sizes the diff viewport to the space left below the step
+// list and details. Caller must hold m.mu write lock.
+func (m *Model) layoutLocked() {
+	m.diffView.Width = m.width
+	h := m.height - m.chromeHeight()
+	if h < 3 {
+		h = 3
+	}
+	m.diffView.Height = h
+}
@@ -0,0 +1,227 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/steveyegge/gastown/internal/checkpoint"
+)
+
+// Styles for the replay TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	changedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	labelStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("15"))
+
+	addStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")) // green
+
+	delStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+
+	hunkStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("14")) // cyan
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// Lines of tool input and output shown above the diff.
+const (
+	inputLines  = 4
+	outputLines = 3
+)
+
+// renderView renders the entire view.
+// Caller must hold m.mu.
+func (m *Model) renderView() string {
+	if len(m.rec.Steps) == 0 {
+		return titleStyle.Render("Replay "+m.rec.ID) + "\n\nRecording has no tool calls.\n"
+	}
+	return m.renderTop() + m.diffView.View() + "\n" + m.renderBottom()
+}
+
+// chromeHeight is the number of lines around the diff viewport.
+// Caller must hold m.mu.
+func (m *Model) chromeHeight() int {
+	if len(m.rec.Steps) == 0 {
+		return 0
+	}
+	return strings.Count(m.renderTop(), "\n") + strings.Count(m.renderBottom(), "\n") + 1
+}
+
+// renderTop renders the title, step list, and current step details.
+// Caller must hold m.mu.
+func (m *Model) renderTop() string {
+	var b strings.Builder
+
+	title := "Replay " + m.rec.ID
+	if s := m.rec.Session(); s != "" {
+		title += "  " + s
+	}
+	b.WriteString(titleStyle.Render(title))
+	if !m.rec.Started.IsZero() {
+		b.WriteString(dimStyle.Render("  started " + m.rec.Started.Format("2006-01-02 15:04")))
+	}
+	if m.changedOnly {
+		b.WriteString(changedStyle.Render("  [changes only]"))
+	}
+	b.WriteString("\n\n")
+
+	// Step list, a window of listRows around the cursor.
+	start := m.cursor - listRows/2
+	if start > len(m.rec.Steps)-listRows {
+		start = len(m.rec.Steps) - listRows
+	}
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(m.rec.Steps) && i < start+listRows; i++ {
+		s := m.rec.Steps[i]
+		marker := " "
+		if s.Changed() {
+			marker = "●"
+		}
+		line := fmt.Sprintf("%s %4d  %-10s %s", marker, s.Seq+1, s.Tool, oneLine(s.Input))
+		line = truncate(line, m.width)
+		switch {
+		case i == m.cursor:
+			b.WriteString(selectedStyle.Render(line))
+		case !m.visibleLocked(i):
+			b.WriteString(dimStyle.Render(line))
+		case s.Changed():
+			b.WriteString(changedStyle.Render(line))
+		default:
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(dimStyle.Render(strings.Repeat("─", max(m.width, 1))))
+	b.WriteString("\n")
+
+	// Current step details.
+	s := m.rec.Steps[m.cursor]
+	header := fmt.Sprintf("Call %d/%d  %s  %s", m.cursor+1, len(m.rec.Steps), s.Tool, s.Time.Format("15:04:05"))
+	if s.Head != "" {
+		header += "  HEAD " + shortHash(s.Head)
+	}
+	b.WriteString(labelStyle.Render(header))
+	b.WriteString("\n")
+	writeExcerpt(&b, "in ", s.Input, inputLines, m.width)
+	writeExcerpt(&b, "out", s.Output, outputLines, m.width)
+	b.WriteString(dimStyle.Render(strings.Repeat("─", max(m.width, 1))))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderBottom renders the help line.
+// Caller must hold m.mu.
+func (m *Model) renderBottom() string {
+	if m.showHelp {
+		return m.help.FullHelpView(m.keys.FullHelp())
+	}
+	return m.help.ShortHelpView(m.keys.ShortHelp())
+}
+
+// renderDiff renders a step's diff with colors, or why there is none.
+func (m *Model) renderDiff(s checkpoint.Step) string {
+	if !s.Changed() {
+		return dimStyle.Render("(no changes to the worktree)")
+	}
+	if m.diff == nil {
+		return ""
+	}
+	out, err := m.diff(s)
+	if err != nil {
+		return errorStyle.Render(fmt.Sprintf("Error: %v", err))
+	}
+	if out == "" {
+		return dimStyle.Render("(no changes to the worktree)")
+	}
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+			lines[i] = labelStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = addStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = delStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = hunkStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeExcerpt writes the first n lines of text under a label.
+func writeExcerpt(b *strings.Builder, label, text string, n, width int) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	lines := strings.Split(text, "\n")
+	more := len(lines) > n
+	if more {
+		lines = lines[:n]
+	}
+	for i, line := range lines {
+		prefix := "    "
+		if i == 0 {
+			prefix = label + " "
+		}
+		b.WriteString(dimStyle.Render(prefix))
+		b.WriteString(truncate(line, width-len(prefix)))
+		b.WriteString("\n")
+	}
+	if more {
+		b.WriteString(dimStyle.Render("    …"))
+		b.WriteString("\n")
+	}
+}
+
+// oneLine collapses text to its first line.
+func oneLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " …"
+	}
+	return s
+}
+
+func shortHash(h string) string {
+	if len(h) > 8 {
+		return h[:8]
+	}
+	return h
+}
+
+// truncate shortens a string to at most maxLen runes. A maxLen of zero or
+// less (width not yet known) leaves it alone.
+func truncate(s string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return string([]rune(s)[:maxLen])
+	}
+	return string([]rune(s)[:maxLen-3]) + "..."
+}
@@ -0,0 +1,154 @@
+package warrant
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+// DefaultGrace is the veto window for policy-filed warrants.
+const DefaultGrace = 15 * time.Minute
+
+// Policy declares when an agent is stuck enough to file a warrant. Every
+// condition that is set must hold; unset conditions are ignored. Example
+// (witness section of the operational config):
+//
+//	"warrant_policies": [
+//	  {"name": "silent", "idle_for": "45m", "no_git_change_for": "45m"},
+//	  {"name": "tool-loop", "repeated_tool_error": 10},
+//	  {"name": "burn", "tokens_without_commit": 2000000, "grace": "30m"}
+//	]
+type Policy struct {
+	// Name identifies the policy in warrants and logs.
+	Name string `json:"name"`
+
+	// IdleFor requires no pane output for at least this long.
+	IdleFor string `json:"idle_for,omitempty"`
+
+	// NoGitChangeFor requires no commit and no worktree edit for at least
+	// this long.
+	NoGitChangeFor string `json:"no_git_change_for,omitempty"`
+
+	// RepeatedToolError requires the same tool error at least this many
+	// times in the current agent session.
+	RepeatedToolError int `json:"repeated_tool_error,omitempty"`
+
+	// TokensWithoutCommit requires at least this many tokens spent since
+	// the last commit.
+	TokensWithoutCommit int `json:"tokens_without_commit,omitempty"`
+
+	// Grace is the veto window before the warrant may execute (default 15m).
+	Grace string `json:"grace,omitempty"`
+}
+
+// Observation is what the witness measured about one agent.
+// Zero values mean the signal was unavailable; conditions on an
+// unavailable signal never hold.
+type Observation struct {
+	LastOutput         time.Time `json:"last_output,omitzero"`
+	LastGitChange      time.Time `json:"last_git_change,omitzero"`
+	RepeatedError      string    `json:"repeated_error,omitempty"`
+	RepeatedErrorCount int       `json:"repeated_error_count,omitempty"`
+	TokensSinceCommit  int       `json:"tokens_since_commit,omitempty"`
+}
+
+// Validate reports whether the policy is usable.
+func (p Policy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("warrant policy needs a name")
+	}
+	for _, f := range []struct{ field, value string }{
+		{"idle_for", p.IdleFor}, {"no_git_change_for", p.NoGitChangeFor}, {"grace", p.Grace},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(f.value); err != nil || d < 0 {
+			return fmt.Errorf("warrant policy %s: invalid %s %q", p.Name, f.field, f.value)
+		}
+	}
+	if p.RepeatedToolError < 0 || p.TokensWithoutCommit < 0 {
+		return fmt.Errorf("warrant policy %s: thresholds must not be negative", p.Name)
+	}
+	if p.IdleFor == "" && p.NoGitChangeFor == "" && p.RepeatedToolError == 0 && p.TokensWithoutCommit == 0 {
+		return fmt.Errorf("warrant policy %s: no conditions set", p.Name)
+	}
+	return nil
+}
+
+// GraceDuration returns the policy's veto window.
+func (p Policy) GraceDuration() time.Duration {
+	if d, err := time.ParseDuration(p.Grace); err == nil && p.Grace != "" && d >= 0 {
+		return d
+	}
+	return DefaultGrace
+}
+
+// NeedsTranscript reports whether evaluating the policy requires reading
+// the agent's conversation log.
+func (p Policy) NeedsTranscript() bool {
+	return p.RepeatedToolError > 0 || p.TokensWithoutCommit > 0
+}
+
+// Match reports whether o satisfies every condition of the policy, and if
+// so, a reason listing what was observed.
+func (p Policy) Match(o Observation, now time.Time) (string, bool) {
+	var reasons []string
+	if p.IdleFor != "" {
+		limit, _ := time.ParseDuration(p.IdleFor)
+		if o.LastOutput.IsZero() || now.Sub(o.LastOutput) < limit {
+			return "", false
+		}
+		reasons = append(reasons, "no pane output for "+now.Sub(o.LastOutput).Round(time.Minute).String())
+	}
+	if p.NoGitChangeFor != "" {
+		limit, _ := time.ParseDuration(p.NoGitChangeFor)
+		if o.LastGitChange.IsZero() || now.Sub(o.LastGitChange) < limit {
+			return "", false
+		}
+		reasons = append(reasons, "no git change for "+now.Sub(o.LastGitChange).Round(time.Minute).String())
+	}
+	if p.RepeatedToolError > 0 {
+		if o.RepeatedErrorCount < p.RepeatedToolError {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("same tool error %d times: %s", o.RepeatedErrorCount, o.RepeatedError))
+	}
+	if p.TokensWithoutCommit > 0 {
+		if o.TokensSinceCommit < p.TokensWithoutCommit {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("%d tokens since last commit", o.TokensSinceCommit))
+	}
+	return fmt.Sprintf("policy %s: %s", p.Name, strings.Join(reasons, "; ")), true
+}
+
+// Evaluate returns the first policy o matches and its reason, or nil.
+func Evaluate(policies []Policy, o Observation, now time.Time) (*Policy, string) {
+	for i := range policies {
+		if reason, ok := policies[i].Match(o, now); ok {
+			return &policies[i], reason
+		}
+	}
+	return nil, ""
+}
+
+// MostRepeatedError groups tool error messages by signature (see
+// agentlog.ErrorSignature) and returns the most frequent one with its count.
+func MostRepeatedError(messages []string) (string, int) {
+	counts := make(map[string]int)
+	var best string
+	for _, m := range messages {
+		key := agentlog.ErrorSignature(m)
+		if key == "" {
+			continue
+		}
+		counts[key]++
+		if counts[key] > counts[best] || (counts[key] == counts[best] && key < best) {
+			best = key
+		}
+	}
+	return best, counts[best]
+}
@@ -0,0 +1,99 @@
+package warrant
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"valid", Policy{Name: "silent", IdleFor: "45m", NoGitChangeFor: "45m"}, ""},
+		{"no name", Policy{IdleFor: "45m"}, "needs a name"},
+		{"no conditions", Policy{Name: "empty", Grace: "5m"}, "no conditions"},
+		{"bad duration", Policy{Name: "bad", IdleFor: "a while"}, "invalid idle_for"},
+		{"negative", Policy{Name: "neg", RepeatedToolError: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyMatch(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	silent := Policy{Name: "silent", IdleFor: "45m", NoGitChangeFor: "45m"}
+
+	obs := Observation{LastOutput: now.Add(-50 * time.Minute), LastGitChange: now.Add(-2 * time.Hour)}
+	reason, ok := silent.Match(obs, now)
+	if !ok {
+		t.Fatal("silent policy should match")
+	}
+	if reason != "policy silent: no pane output for 50m0s; no git change for 2h0m0s" {
+		t.Errorf("reason = %q", reason)
+	}
+
+	// Every condition must hold.
+	obs.LastGitChange = now.Add(-10 * time.Minute)
+	if _, ok := silent.Match(obs, now); ok {
+		t.Error("recent git change should prevent a match")
+	}
+
+	// Unavailable signals never satisfy a condition.
+	if _, ok := silent.Match(Observation{}, now); ok {
+		t.Error("zero observation should not match")
+	}
+}
+
+func TestEvaluatePicksFirstMatch(t *testing.T) {
+	now := time.Now()
+	policies := []Policy{
+		{Name: "burn", TokensWithoutCommit: 1_000_000},
+		{Name: "tool-loop", RepeatedToolError: 10, Grace: "5m"},
+	}
+	obs := Observation{RepeatedError: "exit status N", RepeatedErrorCount: 12, TokensSinceCommit: 40_000}
+
+	p, reason := Evaluate(policies, obs, now)
+	if p == nil || p.Name != "tool-loop" {
+		t.Fatalf("Evaluate = %v", p)
+	}
+	if !strings.Contains(reason, "same tool error 12 times: exit status N") {
+		t.Errorf("reason = %q", reason)
+	}
+	if p.GraceDuration() != 5*time.Minute || policies[0].GraceDuration() != DefaultGrace {
+		t.Error("grace durations wrong")
+	}
+	if p, _ := Evaluate(policies, Observation{}, now); p != nil {
+		t.Errorf("empty observation matched %s", p.Name)
+	}
+}
+
+func TestMostRepeatedError(t *testing.T) {
+	msg, n := MostRepeatedError([]string{
+		"Error: file.go:12: undefined: foo\nmore detail",
+		"Error: file.go:48: undefined: foo",
+		"permission denied",
+		"",
+		"Error: file.go:7: undefined: foo",
+	})
+	if msg != "Error: file.go:N: undefined: foo" || n != 3 {
+		t.Errorf("MostRepeatedError = %q, %d", msg, n)
+	}
+	if msg, n := MostRepeatedError(nil); msg != "" || n != 0 {
+		t.Errorf("empty = %q, %d", msg, n)
+	}
+}
@@ -0,0 +1,172 @@
+// Package warrant stores death warrants for stuck agents and evaluates the
+// declarative policies the witness uses to file them automatically.
+//
+// Warrants live in <town>/warrants/ as one JSON file per target. A warrant
+// filed by policy carries the evidence that tripped it and an execution
+// deadline; until then a human can veto it with gt warrant veto.
+package warrant
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirName is the warrants directory under the town root.
+const DirName = "warrants"
+
+// VetoHold is how long a vetoed warrant keeps policies from filing a new
+// warrant against the same target.
+const VetoHold = 4 * time.Hour
+
+// fileSuffix marks warrant files in the warrants directory.
+const fileSuffix = ".warrant.json"
+
+// Warrant represents a death warrant for an agent.
+type Warrant struct {
+	ID         string     `json:"id"`
+	Target     string     `json:"target"` // e.g., "gastown/polecats/alpha", "deacon/dogs/bravo"
+	Reason     string     `json:"reason"`
+	FiledBy    string     `json:"filed_by"`
+	FiledAt    time.Time  `json:"filed_at"`
+	Executed   bool       `json:"executed,omitempty"`
+	ExecutedAt *time.Time `json:"executed_at,omitempty"`
+
+	// Policy names the policy that filed the warrant; empty for manual filings.
+	Policy string `json:"policy,omitempty"`
+
+	// Evidence is what the filer saw when it decided the agent was stuck.
+	Evidence *Evidence `json:"evidence,omitempty"`
+
+	// ExecuteAfter is the end of the veto window. Boot will not execute the
+	// warrant before then. Nil means the warrant is due immediately.
+	ExecuteAfter *time.Time `json:"execute_after,omitempty"`
+
+	Vetoed     bool       `json:"vetoed,omitempty"`
+	VetoedBy   string     `json:"vetoed_by,omitempty"`
+	VetoedAt   *time.Time `json:"vetoed_at,omitempty"`
+	VetoReason string     `json:"veto_reason,omitempty"`
+}
+
+// Evidence is the snapshot attached to a policy-filed warrant.
+type Evidence struct {
+	Observation  Observation `json:"observation"`
+	PaneCapture  string      `json:"pane_capture,omitempty"`
+	AgentLogTail string      `json:"agentlog_tail,omitempty"`
+	GitStatus    string      `json:"git_status,omitempty"`
+}
+
+// Pending reports whether the warrant still awaits execution.
+func (w *Warrant) Pending() bool {
+	return !w.Executed && !w.Vetoed
+}
+
+// Due reports whether a pending warrant's veto window has passed.
+func (w *Warrant) Due(now time.Time) bool {
+	return w.Pending() && (w.ExecuteAfter == nil || !now.Before(*w.ExecuteAfter))
+}
+
+// Holds reports whether the warrant keeps a new warrant from being filed
+// against its target: while it is pending, and for VetoHold after a veto.
+func (w *Warrant) Holds(now time.Time) bool {
+	if w.Pending() {
+		return true
+	}
+	return w.Vetoed && w.VetoedAt != nil && now.Before(w.VetoedAt.Add(VetoHold))
+}
+
+// Veto cancels a pending warrant.
+func (w *Warrant) Veto(by, reason string, at time.Time) {
+	w.Vetoed = true
+	w.VetoedBy = by
+	w.VetoedAt = &at
+	w.VetoReason = reason
+}
+
+// Dir returns the warrants directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, DirName)
+}
+
+// FilePath returns the path for a target's warrant file.
+func FilePath(dir, target string) string {
+	// Replace / with _ for filename safety
+	safe := strings.ReplaceAll(target, "/", "_")
+	return filepath.Join(dir, safe+fileSuffix)
+}
+
+// Load reads a warrant file.
+func Load(path string) (*Warrant, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is inside the warrants dir
+	if err != nil {
+		return nil, err
+	}
+	var w Warrant
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("parsing warrant %s: %w", filepath.Base(path), err)
+	}
+	return &w, nil
+}
+
+// Save writes a warrant file, creating the warrants directory if needed.
+func Save(path string, w *Warrant) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating warrants directory: %w", err)
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling warrant: %w", err)
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: warrants are non-sensitive
+}
+
+// Entry is a warrant together with the file it was loaded from.
+type Entry struct {
+	Path    string
+	Warrant *Warrant
+}
+
+// List loads every warrant in dir, sorted by filing time. A missing
+// directory yields no warrants. Unreadable files are reported in errs and
+// skipped.
+func List(dir string) (entries []Entry, errs []error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("reading warrants directory: %w", err)}
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), fileSuffix) {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		w, err := Load(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		entries = append(entries, Entry{Path: path, Warrant: w})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Warrant.FiledAt.Before(entries[j].Warrant.FiledAt)
+	})
+	return entries, errs
+}
+
+// IsHeld reports whether target's warrant in dir holds off a new filing
+// (see Warrant.Holds).
+func IsHeld(dir, target string, now time.Time) bool {
+	w, err := Load(FilePath(dir, target))
+	return err == nil && w.Holds(now)
+}
+
+// NewID returns a warrant ID for a filing at t.
+func NewID(t time.Time) string {
+	return fmt.Sprintf("warrant-%d", t.UnixMilli())
+}
@@ -0,0 +1,63 @@
+package warrant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), DirName)
+	now := time.Now().Truncate(time.Second)
+
+	older := &Warrant{ID: "w1", Target: "gastown/polecats/alpha", Reason: "stuck", FiledAt: now.Add(-time.Hour)}
+	newer := &Warrant{ID: "w2", Target: "beads/polecats/bravo", Reason: "loop", FiledAt: now, Policy: "tool-loop",
+		Evidence: &Evidence{GitStatus: "## main", Observation: Observation{RepeatedErrorCount: 12}}}
+	for _, w := range []*Warrant{newer, older} {
+		if err := Save(FilePath(dir, w.Target), w); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, errs := List(dir)
+	if len(errs) != 0 || len(entries) != 2 {
+		t.Fatalf("List = %d entries, errs %v", len(entries), errs)
+	}
+	if entries[0].Warrant.ID != "w1" || entries[1].Warrant.Evidence.Observation.RepeatedErrorCount != 12 {
+		t.Errorf("entries not sorted or evidence lost: %+v", entries)
+	}
+	if filepath.Base(entries[0].Path) != "gastown_polecats_alpha.warrant.json" {
+		t.Errorf("path = %s", entries[0].Path)
+	}
+	if !IsHeld(dir, "gastown/polecats/alpha", now) || IsHeld(dir, "gastown/polecats/nobody", now) {
+		t.Error("IsHeld mismatch")
+	}
+
+	if entries, errs := List(filepath.Join(dir, "missing")); entries != nil || errs != nil {
+		t.Errorf("missing dir: %v %v", entries, errs)
+	}
+}
+
+func TestWarrantDueAndVeto(t *testing.T) {
+	now := time.Now()
+	later := now.Add(10 * time.Minute)
+	w := &Warrant{ExecuteAfter: &later}
+
+	if !w.Pending() || w.Due(now) {
+		t.Fatal("warrant in grace should be pending but not due")
+	}
+	if !w.Due(later) {
+		t.Fatal("warrant should be due once grace expires")
+	}
+	w.Veto("mayor", "still working", now)
+	if w.Pending() || w.Due(later) || w.VetoedAt == nil {
+		t.Errorf("vetoed warrant = %+v", w)
+	}
+	if !w.Holds(now.Add(VetoHold-time.Minute)) || w.Holds(now.Add(VetoHold)) {
+		t.Error("veto should hold off re-filing for VetoHold")
+	}
+}
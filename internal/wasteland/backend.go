@@ -0,0 +1,228 @@
+package wasteland
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Backend kinds, recorded in Config.Backend.
+const (
+	BackendDoltHub    = "dolthub"
+	BackendFile       = "file"
+	BackendRemotesAPI = "remotesapi"
+)
+
+// Backend hosts commons databases. It names their dolt remotes and creates
+// forks, so the join flow works the same against DoltHub, a directory of
+// file:// remotes, or a self-hosted dolt remotesapi server.
+type Backend interface {
+	// Kind returns one of BackendDoltHub, BackendFile or BackendRemotesAPI.
+	Kind() string
+
+	// RemoteURL returns the dolt remote URL of the database org/db.
+	RemoteURL(org, db string) string
+
+	// Fork makes toOrg/db a copy of fromOrg/db. Forking onto an existing
+	// database is a no-op.
+	Fork(fromOrg, db, toOrg string) error
+}
+
+// ParseUpstream parses an upstream commons into org and db. The upstream is
+// a DoltHub path like "steveyegge/wl-commons", or a remote URL whose last
+// two path segments are the org and database, e.g.
+// "file:///srv/commons/hop/wl-commons" or "https://dolt.corp/hop/wl-commons".
+func ParseUpstream(upstream string) (org, db string, err error) {
+	if !strings.Contains(upstream, "://") {
+		parts := strings.SplitN(upstream, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", fmt.Errorf("invalid upstream path %q: expected format 'org/database'", upstream)
+		}
+		return parts[0], parts[1], nil
+	}
+	_, org, db, err = splitRemoteURL(upstream)
+	return org, db, err
+}
+
+// ResolveBackend returns the backend hosting upstream. DoltHub paths use
+// api with token; file:// and http(s):// URLs need neither.
+func ResolveBackend(upstream string, api DoltHubAPI, token string) (Backend, error) {
+	if !strings.Contains(upstream, "://") {
+		if _, _, err := ParseUpstream(upstream); err != nil {
+			return nil, err
+		}
+		return &DoltHubBackend{API: api, Token: token}, nil
+	}
+	base, _, _, err := splitRemoteURL(upstream)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(base, "file://") {
+		return &FileBackend{Root: strings.TrimPrefix(base, "file://")}, nil
+	}
+	return &RemotesAPIBackend{BaseURL: base}, nil
+}
+
+// splitRemoteURL splits ".../<org>/<db>" into the base URL and the last two
+// path segments.
+func splitRemoteURL(raw string) (base, org, db string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid upstream URL %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" || !filepath.IsAbs(u.Path) {
+			return "", "", "", fmt.Errorf("invalid upstream URL %q: file remotes need an absolute path (file:///path/org/db)", raw)
+		}
+	case "http", "https":
+		if u.Host == "" {
+			return "", "", "", fmt.Errorf("invalid upstream URL %q: missing host", raw)
+		}
+	default:
+		return "", "", "", fmt.Errorf("invalid upstream URL %q: scheme must be file, http or https", raw)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-2] == "" || segments[len(segments)-1] == "" {
+		return "", "", "", fmt.Errorf("invalid upstream URL %q: expected .../org/database", raw)
+	}
+	org = segments[len(segments)-2]
+	db = segments[len(segments)-1]
+	u.Path = "/" + strings.Join(segments[:len(segments)-2], "/")
+	return strings.TrimSuffix(u.String(), "/"), org, db, nil
+}
+
+// DoltHubBackend hosts commons on DoltHub and forks through its REST API.
+type DoltHubBackend struct {
+	API   DoltHubAPI
+	Token string
+}
+
+func (b *DoltHubBackend) Kind() string { return BackendDoltHub }
+
+func (b *DoltHubBackend) RemoteURL(org, db string) string {
+	return fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db)
+}
+
+func (b *DoltHubBackend) Fork(fromOrg, db, toOrg string) error {
+	return b.API.ForkRepo(fromOrg, db, toOrg, b.Token)
+}
+
+// FileBackend hosts commons as dolt file remotes under Root/<org>/<db>. A
+// fork is a copy of the upstream's storage directory, so the whole flow
+// works offline against a shared or local filesystem.
+type FileBackend struct {
+	Root string
+}
+
+func (b *FileBackend) Kind() string { return BackendFile }
+
+func (b *FileBackend) RemoteURL(org, db string) string {
+	return "file://" + filepath.ToSlash(filepath.Join(b.Root, org, db))
+}
+
+func (b *FileBackend) Fork(fromOrg, db, toOrg string) error {
+	src := filepath.Join(b.Root, fromOrg, db)
+	dst := filepath.Join(b.Root, toOrg, db)
+	if src == dst {
+		return nil
+	}
+	if entries, err := os.ReadDir(dst); err == nil && len(entries) > 0 {
+		return nil // already forked
+	}
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("upstream commons %s: %w", src, err)
+	}
+	return copyDir(src, dst)
+}
+
+// RemotesAPIBackend hosts commons on a dolt remotesapi server (a dolt
+// sql-server with --remotesapi-port, or DoltLab) at BaseURL/<org>/<db>.
+// remotesapi has no fork operation, so Fork clones the upstream and pushes
+// it to the fork's URL; the server must accept pushes that create it.
+type RemotesAPIBackend struct {
+	BaseURL string
+}
+
+func (b *RemotesAPIBackend) Kind() string { return BackendRemotesAPI }
+
+func (b *RemotesAPIBackend) RemoteURL(org, db string) string {
+	return fmt.Sprintf("%s/%s/%s", b.BaseURL, org, db)
+}
+
+func (b *RemotesAPIBackend) Fork(fromOrg, db, toOrg string) error {
+	if fromOrg == toOrg {
+		return nil
+	}
+	return forkByPush(b.RemoteURL(fromOrg, db), b.RemoteURL(toOrg, db))
+}
+
+// forkByPush copies a dolt remote to another URL through a scratch clone.
+func forkByPush(fromURL, toURL string) error {
+	tmp, err := os.MkdirTemp("", "wl-fork-")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	dir := filepath.Join(tmp, "commons")
+	for _, args := range [][]string{
+		{"clone", fromURL, dir},
+		{"remote", "add", "fork", toURL},
+		{"push", "fork", "main"},
+	} {
+		cmd := exec.Command("dolt", args...)
+		if args[0] != "clone" {
+			cmd.Dir = dir
+		}
+		util.SetDetachedProcessGroup(cmd)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("dolt %s: %w (%s)", args[0], err, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// copyDir recursively copies a directory of regular files.
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src) //nolint:gosec // G304: path comes from walking the upstream remote
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm) //nolint:gosec // G304: path is under the fork directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package wasteland
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestJoin_FileRemoteOffline joins a commons served from a file:// remote
+// and checks the registration reaches the fork without any network access.
+func TestJoin_FileRemoteOffline(t *testing.T) {
+	doltPath, err := exec.LookPath("dolt")
+	if err != nil {
+		t.Skip("dolt not found in PATH — skipping integration test")
+	}
+
+	base := t.TempDir()
+	homeDir := filepath.Join(base, "home")
+	seedDir := filepath.Join(base, "seed")
+	remotes := filepath.Join(base, "remotes")
+	townRoot := filepath.Join(base, "town")
+	for _, dir := range []string{homeDir, seedDir, remotes, filepath.Join(townRoot, "mayor")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The service shells out to dolt with the inherited environment.
+	t.Setenv("DOLT_ROOT_PATH", homeDir)
+	t.Setenv("HOME", homeDir)
+
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(doltPath, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("dolt %v failed: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+
+	run(homeDir, "config", "--global", "--add", "user.email", "test@test.com")
+	run(homeDir, "config", "--global", "--add", "user.name", "test")
+	run(seedDir, "init")
+	run(seedDir, "sql", "-q", `CREATE TABLE rigs (
+		handle VARCHAR(255) PRIMARY KEY,
+		display_name VARCHAR(255),
+		dolthub_org VARCHAR(255),
+		owner_email VARCHAR(255),
+		gt_version VARCHAR(32),
+		trust_level INT DEFAULT 0,
+		registered_at TIMESTAMP,
+		last_seen TIMESTAMP
+	)`)
+	run(seedDir, "add", ".")
+	run(seedDir, "commit", "-m", "seed commons")
+	upstream := "file://" + filepath.ToSlash(filepath.Join(remotes, "hop", "wl-commons"))
+	run(seedDir, "remote", "add", "origin", upstream)
+	run(seedDir, "push", "origin", "main")
+
+	cfg, err := NewService().Join(upstream, "alice-dev", "", "alice", "Alice", "alice@example.com", "dev", townRoot)
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if cfg.Backend != BackendFile {
+		t.Errorf("Backend = %q, want %q", cfg.Backend, BackendFile)
+	}
+
+	check := filepath.Join(base, "check")
+	run(base, "clone", cfg.ForkRemote, check)
+	if out := run(check, "sql", "-q", "SELECT handle FROM rigs", "-r", "csv"); !strings.Contains(out, "alice") {
+		t.Errorf("fork rigs table = %q, want alice registered", out)
+	}
+	if out := run(cfg.LocalDir, "remote", "-v"); !strings.Contains(out, upstream) {
+		t.Errorf("local clone remotes = %q, want upstream %s", out, upstream)
+	}
+}
@@ -0,0 +1,130 @@
+package wasteland
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUpstream_URLs(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantOrg string
+		wantDB  string
+	}{
+		{"file:///srv/commons/hop/wl-commons", "hop", "wl-commons"},
+		{"https://dolt.example.com/hop/wl-commons", "hop", "wl-commons"},
+		{"http://localhost:50051/remotes/hop/wl-commons/", "hop", "wl-commons"},
+	}
+	for _, tt := range tests {
+		org, db, err := ParseUpstream(tt.input)
+		if err != nil || org != tt.wantOrg || db != tt.wantDB {
+			t.Errorf("ParseUpstream(%q) = %q, %q, %v; want %q, %q", tt.input, org, db, err, tt.wantOrg, tt.wantDB)
+		}
+	}
+	for _, bad := range []string{
+		"file://relative/hop/wl-commons",
+		"ftp://host/hop/wl-commons",
+		"https:///hop/wl-commons",
+		"https://host/wl-commons",
+	} {
+		if _, _, err := ParseUpstream(bad); err == nil {
+			t.Errorf("ParseUpstream(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestResolveBackend(t *testing.T) {
+	tests := []struct {
+		upstream string
+		kind     string
+		fork     string
+	}{
+		{"steveyegge/wl-commons", BackendDoltHub, "https://doltremoteapi.dolthub.com/alice-dev/wl-commons"},
+		{"file:///srv/commons/steveyegge/wl-commons", BackendFile, "file:///srv/commons/alice-dev/wl-commons"},
+		{"https://dolt.example.com:50051/steveyegge/wl-commons", BackendRemotesAPI, "https://dolt.example.com:50051/alice-dev/wl-commons"},
+	}
+	for _, tt := range tests {
+		b, err := ResolveBackend(tt.upstream, NewFakeDoltHubAPI(), "")
+		if err != nil {
+			t.Fatalf("ResolveBackend(%q): %v", tt.upstream, err)
+		}
+		if b.Kind() != tt.kind {
+			t.Errorf("ResolveBackend(%q).Kind() = %q, want %q", tt.upstream, b.Kind(), tt.kind)
+		}
+		if got := b.RemoteURL("alice-dev", "wl-commons"); got != tt.fork {
+			t.Errorf("ResolveBackend(%q) fork URL = %q, want %q", tt.upstream, got, tt.fork)
+		}
+	}
+}
+
+func TestFileBackend_Fork(t *testing.T) {
+	root := t.TempDir()
+	upstream := filepath.Join(root, "hop", "wl-commons")
+	if err := os.MkdirAll(filepath.Join(upstream, "oldgen"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"manifest": "v1", "oldgen/chunk": "data"} {
+		if err := os.WriteFile(filepath.Join(upstream, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := &FileBackend{Root: root}
+	if err := b.Fork("hop", "wl-commons", "alice-dev"); err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "alice-dev", "wl-commons", "oldgen", "chunk"))
+	if err != nil || string(data) != "data" {
+		t.Fatalf("forked chunk = %q, %v", data, err)
+	}
+
+	// A second fork leaves the existing one alone.
+	if err := os.WriteFile(filepath.Join(upstream, "manifest"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Fork("hop", "wl-commons", "alice-dev"); err != nil {
+		t.Fatalf("second Fork: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "alice-dev", "wl-commons", "manifest")); string(data) != "v1" {
+		t.Errorf("existing fork overwritten: manifest = %q", data)
+	}
+
+	if err := b.Fork("nobody", "wl-commons", "alice-dev2"); err == nil {
+		t.Error("Fork of a missing upstream succeeded")
+	}
+}
+
+func TestJoin_FileBackend(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "hop", "wl-commons"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "hop", "wl-commons", "manifest"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	api := NewFakeDoltHubAPI()
+	cli := NewFakeDoltCLI()
+	svc := &Service{API: api, CLI: cli, Config: NewFakeConfigStore()}
+
+	upstream := "file://" + filepath.ToSlash(filepath.Join(root, "hop", "wl-commons"))
+	cfg, err := svc.Join(upstream, "alice-dev", "", "alice-rig", "Alice", "alice@example.com", "dev", "/tmp/town")
+	if err != nil {
+		t.Fatalf("Join() error: %v", err)
+	}
+
+	if len(api.Forked) != 0 {
+		t.Errorf("DoltHub API used for a file commons: %v", api.Forked)
+	}
+	forkURL := "file://" + filepath.ToSlash(filepath.Join(root, "alice-dev", "wl-commons"))
+	localDir := LocalCloneDir("/tmp/town", "hop", "wl-commons")
+	if !cli.Cloned[forkURL+"->"+localDir] {
+		t.Errorf("clones = %v, want %s cloned to %s", cli.Cloned, forkURL, localDir)
+	}
+	if !cli.Remotes[localDir+"->"+upstream] {
+		t.Errorf("remotes = %v, want upstream %s", cli.Remotes, upstream)
+	}
+	if cfg.Backend != BackendFile || cfg.ForkRemote != forkURL || cfg.ForkDB != "wl-commons" {
+		t.Errorf("config = %+v", cfg)
+	}
+}
@@ -1,7 +1,9 @@
 // Package wasteland implements the Wasteland federation protocol for Gas Town.
 //
 // The Wasteland is a federation of Gas Towns via DoltHub. Each rig has a
-// sovereign fork of a shared commons database. Rigs register by writing
+// sovereign fork of a shared commons database. The commons can also live on
+// a self-hosted dolt remotesapi server or in a directory of file:// remotes;
+// see Backend. Rigs register by writing
 // to the commons' rigs table, and contribute wanted work items and
 // completions through DoltHub's fork/PR/merge primitives.
 //
@@ -28,12 +30,20 @@ var ErrNotJoined = errors.New("rig has not joined a wasteland")
 
 // Config holds the wasteland configuration for a rig.
 type Config struct {
-	// Upstream is the DoltHub path of the upstream commons (e.g., "steveyegge/wl-commons").
+	// Upstream is the upstream commons: a DoltHub path (e.g., "steveyegge/wl-commons")
+	// or a remote URL (e.g., "file:///srv/commons/hop/wl-commons").
 	Upstream string `json:"upstream"`
 
-	// ForkOrg is the DoltHub org where the fork lives (e.g., "alice-dev").
+	// Backend is the kind of host serving the commons (BackendDoltHub,
+	// BackendFile or BackendRemotesAPI). Empty means DoltHub.
+	Backend string `json:"backend,omitempty"`
+
+	// ForkOrg is the org where the fork lives (e.g., "alice-dev").
 	ForkOrg string `json:"fork_org"`
 
+	// ForkRemote is the dolt remote URL of the fork.
+	ForkRemote string `json:"fork_remote,omitempty"`
+
 	// ForkDB is the database name of the fork (e.g., "wl-commons").
 	ForkDB string `json:"fork_db"`
 
@@ -85,15 +95,6 @@ var dolthubAPIBase = "https://www.dolthub.com/api/v1alpha1"
 // dolthubRemoteBase is the Dolt remote API base URL.
 const dolthubRemoteBase = "https://doltremoteapi.dolthub.com"
 
-// ParseUpstream parses an upstream path like "steveyegge/wl-commons" into org and db.
-func ParseUpstream(upstream string) (org, db string, err error) {
-	parts := strings.SplitN(upstream, "/", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return "", "", fmt.Errorf("invalid upstream path %q: expected format 'org/database'", upstream)
-	}
-	return parts[0], parts[1], nil
-}
-
 // ForkDoltHubRepo forks a DoltHub database to the target org.
 // Uses the DoltHub fork API endpoint.
 func ForkDoltHubRepo(fromOrg, fromDB, toOrg, token string) error {
@@ -141,11 +142,8 @@ func ForkDoltHubRepo(fromOrg, fromDB, toOrg, token string) error {
 	return fmt.Errorf("DoltHub fork API error (HTTP %d)", resp.StatusCode)
 }
 
-// CloneLocally clones a DoltHub database to a local directory.
-// Returns the absolute path to the clone.
-func CloneLocally(org, db, targetDir string) error {
-	remoteURL := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db)
-
+// CloneLocally clones a dolt remote to a local directory.
+func CloneLocally(remoteURL, targetDir string) error {
 	if err := os.MkdirAll(filepath.Dir(targetDir), 0755); err != nil {
 		return fmt.Errorf("creating parent directory: %w", err)
 	}
@@ -224,9 +222,7 @@ func PushToOrigin(localDir string) error {
 }
 
 // AddUpstreamRemote adds the upstream commons as a remote named "upstream".
-func AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
-	url := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, upstreamOrg, upstreamDB)
-
+func AddUpstreamRemote(localDir, url string) error {
 	// Check if upstream remote already exists
 	checkCmd := exec.Command("dolt", "remote", "-v")
 	checkCmd.Dir = localDir
@@ -290,10 +286,10 @@ type DoltHubAPI interface {
 
 // DoltCLI abstracts dolt CLI subprocess operations.
 type DoltCLI interface {
-	Clone(org, db, targetDir string) error
+	Clone(remoteURL, targetDir string) error
 	RegisterRig(localDir, handle, dolthubOrg, displayName, ownerEmail, gtVersion string) error
 	Push(localDir string) error
-	AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error
+	AddUpstreamRemote(localDir, remoteURL string) error
 }
 
 // ConfigStore abstracts wasteland config persistence.
@@ -311,12 +307,18 @@ type Service struct {
 }
 
 // Join orchestrates the wasteland join workflow: fork -> clone -> add upstream -> register -> push -> save config.
+// The upstream's form selects the backend (see ResolveBackend); token is only
+// used for DoltHub.
 // Returns the saved Config on success, or the existing Config if already joined.
 func (s *Service) Join(upstream, forkOrg, token, handle, displayName, ownerEmail, gtVersion, townRoot string) (*Config, error) {
 	upstreamOrg, upstreamDB, err := ParseUpstream(upstream)
 	if err != nil {
 		return nil, err
 	}
+	backend, err := ResolveBackend(upstream, s.API, token)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if already joined
 	if existing, err := s.Config.Load(townRoot); err == nil {
@@ -335,17 +337,18 @@ func (s *Service) Join(upstream, forkOrg, token, handle, displayName, ownerEmail
 	}
 
 	progress("Forking commons...")
-	if err := s.API.ForkRepo(upstreamOrg, upstreamDB, forkOrg, token); err != nil {
+	if err := backend.Fork(upstreamOrg, upstreamDB, forkOrg); err != nil {
 		return nil, fmt.Errorf("forking commons: %w", err)
 	}
 
 	progress("Cloning fork locally...")
-	if err := s.CLI.Clone(forkOrg, upstreamDB, localDir); err != nil {
+	forkRemote := backend.RemoteURL(forkOrg, upstreamDB)
+	if err := s.CLI.Clone(forkRemote, localDir); err != nil {
 		return nil, fmt.Errorf("cloning fork: %w", err)
 	}
 
 	progress("Adding upstream remote...")
-	if err := s.CLI.AddUpstreamRemote(localDir, upstreamOrg, upstreamDB); err != nil {
+	if err := s.CLI.AddUpstreamRemote(localDir, backend.RemoteURL(upstreamOrg, upstreamDB)); err != nil {
 		return nil, fmt.Errorf("adding upstream remote: %w", err)
 	}
 
@@ -360,12 +363,14 @@ func (s *Service) Join(upstream, forkOrg, token, handle, displayName, ownerEmail
 	}
 
 	cfg := &Config{
-		Upstream:  upstream,
-		ForkOrg:   forkOrg,
-		ForkDB:    upstreamDB,
-		LocalDir:  localDir,
-		RigHandle: handle,
-		JoinedAt:  time.Now(),
+		Upstream:   upstream,
+		Backend:    backend.Kind(),
+		ForkOrg:    forkOrg,
+		ForkDB:     upstreamDB,
+		ForkRemote: forkRemote,
+		LocalDir:   localDir,
+		RigHandle:  handle,
+		JoinedAt:   time.Now(),
 	}
 	if err := s.Config.Save(townRoot, cfg); err != nil {
 		return nil, fmt.Errorf("saving wasteland config: %w", err)
@@ -384,8 +389,8 @@ func (h *httpDoltHubAPI) ForkRepo(fromOrg, fromDB, toOrg, token string) error {
 // execDoltCLI implements DoltCLI using real dolt subprocess calls.
 type execDoltCLI struct{}
 
-func (e *execDoltCLI) Clone(org, db, targetDir string) error {
-	return CloneLocally(org, db, targetDir)
+func (e *execDoltCLI) Clone(remoteURL, targetDir string) error {
+	return CloneLocally(remoteURL, targetDir)
 }
 func (e *execDoltCLI) RegisterRig(localDir, handle, dolthubOrg, displayName, ownerEmail, gtVersion string) error {
 	return RegisterRig(localDir, handle, dolthubOrg, displayName, ownerEmail, gtVersion)
@@ -393,8 +398,8 @@ func (e *execDoltCLI) RegisterRig(localDir, handle, dolthubOrg, displayName, own
 func (e *execDoltCLI) Push(localDir string) error {
 	return PushToOrigin(localDir)
 }
-func (e *execDoltCLI) AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
-	return AddUpstreamRemote(localDir, upstreamOrg, upstreamDB)
+func (e *execDoltCLI) AddUpstreamRemote(localDir, remoteURL string) error {
+	return AddUpstreamRemote(localDir, remoteURL)
 }
 
 // fileConfigStore implements ConfigStore using filesystem persistence.
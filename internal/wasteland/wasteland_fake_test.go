@@ -53,10 +53,10 @@ func (f *FakeDoltHubAPI) ForkRepo(fromOrg, fromDB, toOrg, token string) error {
 // FakeDoltCLI is a test double for DoltCLI.
 type FakeDoltCLI struct {
 	mu         sync.Mutex
-	Cloned     map[string]bool // "org/db -> targetDir"
+	Cloned     map[string]bool // "remoteURL->targetDir"
 	Registered map[string]bool // "handle"
 	Pushed     map[string]bool // "localDir"
-	Remotes    map[string]bool // "localDir -> upstreamOrg/upstreamDB"
+	Remotes    map[string]bool // "localDir->remoteURL"
 	Calls      []string
 	Log        *CallLog // shared ordered log (optional)
 
@@ -75,10 +75,10 @@ func NewFakeDoltCLI() *FakeDoltCLI {
 	}
 }
 
-func (f *FakeDoltCLI) Clone(org, db, targetDir string) error {
+func (f *FakeDoltCLI) Clone(remoteURL, targetDir string) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	call := fmt.Sprintf("Clone(%s, %s, %s)", org, db, targetDir)
+	call := fmt.Sprintf("Clone(%s, %s)", remoteURL, targetDir)
 	f.Calls = append(f.Calls, call)
 	if f.Log != nil {
 		f.Log.Record(call)
@@ -86,7 +86,7 @@ func (f *FakeDoltCLI) Clone(org, db, targetDir string) error {
 	if f.CloneErr != nil {
 		return f.CloneErr
 	}
-	f.Cloned[fmt.Sprintf("%s/%s->%s", org, db, targetDir)] = true
+	f.Cloned[remoteURL+"->"+targetDir] = true
 	return nil
 }
 
@@ -120,10 +120,10 @@ func (f *FakeDoltCLI) Push(localDir string) error {
 	return nil
 }
 
-func (f *FakeDoltCLI) AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
+func (f *FakeDoltCLI) AddUpstreamRemote(localDir, remoteURL string) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	call := fmt.Sprintf("AddUpstreamRemote(%s, %s, %s)", localDir, upstreamOrg, upstreamDB)
+	call := fmt.Sprintf("AddUpstreamRemote(%s, %s)", localDir, remoteURL)
 	f.Calls = append(f.Calls, call)
 	if f.Log != nil {
 		f.Log.Record(call)
@@ -131,7 +131,7 @@ func (f *FakeDoltCLI) AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string
 	if f.RemoteErr != nil {
 		return f.RemoteErr
 	}
-	f.Remotes[fmt.Sprintf("%s->%s/%s", localDir, upstreamOrg, upstreamDB)] = true
+	f.Remotes[localDir+"->"+remoteURL] = true
 	return nil
 }
 
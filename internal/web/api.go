@@ -12,12 +12,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/graph"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -100,15 +102,17 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate CSRF token on all POST requests.
-	if r.Method == http.MethodPost && h.csrfToken != "" {
+	path := strings.TrimPrefix(r.URL.Path, "/api")
+
+	// Validate CSRF token on all POST requests. The inbound webhook is called
+	// by external services and authenticates with its own shared secret.
+	if r.Method == http.MethodPost && h.csrfToken != "" && path != inboundWebhookPath {
 		if r.Header.Get("X-Dashboard-Token") != h.csrfToken {
 			h.sendError(w, "Invalid or missing dashboard token", http.StatusForbidden)
 			return
 		}
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/api")
 	switch {
 	case path == "/run" && r.Method == http.MethodPost:
 		h.handleRun(w, r)
@@ -122,6 +126,8 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleMailThreads(w, r)
 	case path == "/mail/read" && r.Method == http.MethodGet:
 		h.handleMailRead(w, r)
+	case path == "/mail/search" && r.Method == http.MethodGet:
+		h.handleMailSearch(w, r)
 	case path == "/mail/send" && r.Method == http.MethodPost:
 		h.handleMailSend(w, r)
 	case path == "/issues/show" && r.Method == http.MethodGet:
@@ -144,6 +150,10 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleSSE(w, r)
 	case path == "/session/preview" && r.Method == http.MethodGet:
 		h.handleSessionPreview(w, r)
+	case path == "/graph" && r.Method == http.MethodGet:
+		h.handleGraph(w, r)
+	case path == inboundWebhookPath && r.Method == http.MethodPost:
+		h.handleInboundWebhook(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -229,6 +239,32 @@ func (h *APIHandler) handleCommands(w http.ResponseWriter, _ *http.Request) {
 
 // runGtCommand executes a gt command with the given args.
 func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
+	stdout, stderr, err := h.execGtCommand(ctx, timeout, args)
+
+	// Combine stdout and stderr for output
+	output := stdout
+	if stderr != "" {
+		if output != "" {
+			output += "\n"
+		}
+		output += stderr
+	}
+	return output, err
+}
+
+// runGtCommandStdout executes a gt command and returns only its stdout, for
+// callers serving machine output (e.g. SVG) that stderr warnings would
+// corrupt. On failure the error carries stderr.
+func (h *APIHandler) runGtCommandStdout(ctx context.Context, timeout time.Duration, args []string) (string, error) {
+	stdout, stderr, err := h.execGtCommand(ctx, timeout, args)
+	if err != nil && stderr != "" {
+		return stdout, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return stdout, err
+}
+
+// execGtCommand runs gt with the given args under the command semaphore.
+func (h *APIHandler) execGtCommand(ctx context.Context, timeout time.Duration, args []string) (string, string, error) {
 	// Apply timeout first so it bounds both semaphore wait and command execution.
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -238,7 +274,7 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 	case h.cmdSem <- struct{}{}:
 		defer func() { <-h.cmdSem }()
 	case <-ctx.Done():
-		return "", fmt.Errorf("command slot unavailable: %w", ctx.Err())
+		return "", "", fmt.Errorf("command slot unavailable: %w", ctx.Err())
 	}
 
 	cmd := exec.CommandContext(ctx, h.gtPath, args...)
@@ -254,24 +290,15 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 
 	err := cmd.Run()
 
-	// Combine stdout and stderr for output
-	output := stdout.String()
-	if stderr.Len() > 0 {
-		if output != "" {
-			output += "\n"
-		}
-		output += stderr.String()
-	}
-
 	if ctx.Err() == context.DeadlineExceeded {
-		return output, fmt.Errorf("command timed out after %v", timeout)
+		return stdout.String(), stderr.String(), fmt.Errorf("command timed out after %v", timeout)
 	}
 
 	if err != nil {
-		return output, fmt.Errorf("command failed: %v", err)
+		return stdout.String(), stderr.String(), fmt.Errorf("command failed: %v", err)
 	}
 
-	return output, nil
+	return stdout.String(), stderr.String(), nil
 }
 
 // sendError sends a JSON error response.
@@ -522,6 +549,69 @@ func (h *APIHandler) handleMailRead(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(msg)
 }
 
+// MailSearchResponse is the response for /api/mail/search.
+type MailSearchResponse struct {
+	Query    string        `json:"query"`
+	Town     bool          `json:"town"`
+	Messages []MailMessage `json:"messages"`
+	Total    int           `json:"total"`
+}
+
+// maxMailSearchQueryLen bounds the search query accepted from the dashboard.
+const maxMailSearchQueryLen = 500
+
+// handleMailSearch runs a full-text mail search.
+// Query parameters: q (search syntax, see gt mail search --help),
+// town=1 to search every mailbox, limit=N to cap results.
+func (h *APIHandler) handleMailSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if len(query) > maxMailSearchQueryLen {
+		h.sendError(w, "Search query too long", http.StatusBadRequest)
+		return
+	}
+	town := r.URL.Query().Get("town") == "1" || r.URL.Query().Get("town") == "true"
+
+	// "--" ends flag parsing so queries like "--help" are searched literally.
+	args := []string{"mail", "search", "--json"}
+	if town {
+		args = append(args, "--town")
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			h.sendError(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		args = append(args, "--limit", strconv.Itoa(n))
+	}
+	args = append(args, "--", query)
+
+	output, err := h.runGtCommand(r.Context(), 15*time.Second, args)
+	if err != nil {
+		h.sendError(w, "Search failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var messages []MailMessage
+	if trimmed := strings.TrimSpace(output); trimmed != "" && trimmed != "null" {
+		if err := json.Unmarshal([]byte(trimmed), &messages); err != nil {
+			h.sendError(w, "Failed to parse search results: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if messages == nil {
+		messages = []MailMessage{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(MailSearchResponse{
+		Query:    query,
+		Town:     town,
+		Messages: messages,
+		Total:    len(messages),
+	})
+}
+
 // MailSendRequest is the request body for /api/mail/send.
 type MailSendRequest struct {
 	To      string `json:"to"`
@@ -2093,6 +2183,49 @@ type SessionPreviewResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// graphCommands maps /api/graph kinds to the gt subcommand that renders them.
+var graphCommands = map[string][]string{
+	"convoy": {"convoy", "graph"},
+	"mol":    {"mol", "dag"},
+}
+
+// handleGraph renders a convoy's waves or a molecule/epic DAG as an image
+// (or DOT/Mermaid text) so the dashboard can embed it with a plain <img>.
+// Query: kind=convoy|mol, id=<bead-id>, format=svg|dot|mermaid (default svg).
+func (h *APIHandler) handleGraph(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	sub, ok := graphCommands[q.Get("kind")]
+	if !ok {
+		h.sendError(w, "Invalid graph kind (want convoy or mol)", http.StatusBadRequest)
+		return
+	}
+	id := q.Get("id")
+	if !isValidID(id) {
+		h.sendError(w, "Invalid or missing id", http.StatusBadRequest)
+		return
+	}
+	formatName := q.Get("format")
+	if formatName == "" {
+		formatName = string(graph.FormatSVG)
+	}
+	format, err := graph.ParseFormat(formatName)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	args := append(append([]string(nil), sub...), id, "--format", string(format))
+	output, err := h.runGtCommandStdout(r.Context(), 30*time.Second, args)
+	if err != nil {
+		h.sendError(w, "Failed to render graph: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write([]byte(output))
+}
+
 // handleSessionPreview returns the last N lines of tmux capture-pane output for a session.
 func (h *APIHandler) handleSessionPreview(w http.ResponseWriter, r *http.Request) {
 	sessionName := r.URL.Query().Get("session")
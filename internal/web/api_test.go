@@ -1457,3 +1457,124 @@ func TestPaneCurrentCommandIsAgent(t *testing.T) {
 		}
 	}
 }
+
+func TestAPIHandler_MailSearch(t *testing.T) {
+	binDir := t.TempDir()
+	gtPath := filepath.Join(binDir, "gt")
+
+	gtScript := `#!/usr/bin/env sh
+set -eu
+case "$*" in
+  "mail search --json --town --limit 5 -- from:witness \"merge failed\"")
+    printf '[{"id":"hq-1","from":"gastown/witness","to":"mayor/","subject":"Merge failed","timestamp":"2026-01-02T00:00:00Z","read":false}]\n'
+    ;;
+  *)
+    printf 'unexpected gt args: %s\n' "$*" >&2
+    exit 2
+    ;;
+esac
+`
+	if err := os.WriteFile(gtPath, []byte(gtScript), 0o755); err != nil {
+		t.Fatalf("write fake gt: %v", err)
+	}
+
+	h := &APIHandler{
+		gtPath:            gtPath,
+		workDir:           t.TempDir(),
+		defaultRunTimeout: 5 * time.Second,
+		maxRunTimeout:     10 * time.Second,
+		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, `/api/mail/search?town=1&limit=5&q=from%3Awitness+%22merge+failed%22`, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("mail search returned status %d: %s", w.Code, w.Body.String())
+	}
+	var resp MailSearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response: %v\nbody=%s", err, w.Body.String())
+	}
+	if !resp.Town || resp.Total != 1 || resp.Messages[0].ID != "hq-1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAPIHandler_MailSearch_InvalidLimit(t *testing.T) {
+	h := NewAPIHandler(5*time.Second, 10*time.Second, "tok")
+	req := httptest.NewRequest(http.MethodGet, "/api/mail/search?q=x&limit=-1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleGraph(t *testing.T) {
+	binDir := t.TempDir()
+	gtPath := filepath.Join(binDir, "gt")
+	gtScript := `#!/usr/bin/env sh
+set -eu
+case "$*" in
+  "convoy graph hq-cv-abc --format svg")
+    echo "warning: noise" >&2
+    printf '<svg></svg>\n'
+    ;;
+  "mol dag gt-epic --format dot")
+    printf 'digraph G {}\n'
+    ;;
+  *)
+    printf 'unexpected gt args: %s\n' "$*" >&2
+    exit 2
+    ;;
+esac
+`
+	if err := os.WriteFile(gtPath, []byte(gtScript), 0o755); err != nil {
+		t.Fatalf("write fake gt: %v", err)
+	}
+
+	h := &APIHandler{
+		gtPath:            gtPath,
+		workDir:           t.TempDir(),
+		defaultRunTimeout: 5 * time.Second,
+		maxRunTimeout:     10 * time.Second,
+		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{"convoy svg default", "kind=convoy&id=hq-cv-abc", http.StatusOK, "image/svg+xml", "<svg></svg>\n"},
+		{"mol dot", "kind=mol&id=gt-epic&format=dot", http.StatusOK, "text/vnd.graphviz; charset=utf-8", "digraph G {}\n"},
+		{"bad kind", "kind=rig&id=gt-epic", http.StatusBadRequest, "", ""},
+		{"bad id", "kind=mol&id=;rm", http.StatusBadRequest, "", ""},
+		{"bad format", "kind=mol&id=gt-epic&format=png", http.StatusBadRequest, "", ""},
+		{"command failure", "kind=mol&id=gt-other", http.StatusInternalServerError, "", "unexpected gt args"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/graph?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantType != "" && w.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", w.Header().Get("Content-Type"), tt.wantType)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q (stderr must not leak into images)", w.Body.String(), tt.wantBody)
+			}
+			if tt.wantStatus != http.StatusOK && tt.wantBody != "" && !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("error body = %q, want it to mention %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
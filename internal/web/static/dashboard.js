@@ -1592,6 +1592,14 @@
         document.getElementById('convoy-issues-empty').style.display = 'none';
         document.getElementById('convoy-add-issue-form').style.display = 'none';
 
+        // Wave graph is rendered server-side as SVG
+        var graphUrl = '/api/graph?kind=convoy&id=' + encodeURIComponent(convoyId);
+        var graphImg = document.getElementById('convoy-graph-img');
+        graphImg.style.display = '';
+        graphImg.onerror = function() { graphImg.style.display = 'none'; };
+        graphImg.src = graphUrl;
+        document.getElementById('convoy-graph-link').href = graphUrl;
+
         // Show detail, hide list and create form
         convoyList.style.display = 'none';
         convoyCreateForm.style.display = 'none';
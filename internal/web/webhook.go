@@ -0,0 +1,177 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// inboundWebhookPath is exempt from the dashboard CSRF token: external chat
+// bridges authenticate with the shared secret instead.
+const inboundWebhookPath = "/webhooks/inbound"
+
+// maxInboundWebhookBytes bounds the inbound request body.
+const maxInboundWebhookBytes = 256 * 1024
+
+// InboundWebhookRequest is the JSON body for POST /api/webhooks/inbound.
+// It posts a reply from external chat back into a bridged mail thread.
+type InboundWebhookRequest struct {
+	// To is the bridged address: channel:<name> or announce:<name>. It must
+	// have outbound webhooks configured in messaging.json.
+	To string `json:"to"`
+	// ThreadID is the thread to reply into (included in outbound payloads).
+	ThreadID string `json:"thread_id"`
+	// ReplyTo is the message ID being replied to (optional).
+	ReplyTo string `json:"reply_to,omitempty"`
+	// Author is the external user's display name (optional).
+	Author string `json:"author,omitempty"`
+	// Source names the external system, e.g. "slack" (optional).
+	Source  string `json:"source,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body"`
+}
+
+// handleInboundWebhook relays a reply from external chat into mail. The
+// caller must present the shared secret from messaging.json inbound_webhook,
+// as a bearer token or as an HMAC-SHA256 signature of the body.
+func (h *APIHandler) handleInboundWebhook(w http.ResponseWriter, r *http.Request) {
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil || cfg.InboundWebhook == nil {
+		// Not configured: behave as if the endpoint does not exist.
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	secret := cfg.InboundWebhook.ResolveSecret()
+	if secret == "" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxInboundWebhookBytes+1))
+	if err != nil {
+		h.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(raw) > maxInboundWebhookBytes {
+		h.sendError(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !verifyInboundWebhook(r, raw, secret) {
+		h.sendError(w, "Invalid or missing webhook secret", http.StatusUnauthorized)
+		return
+	}
+
+	var req InboundWebhookRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		h.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.To == "" || req.ThreadID == "" || strings.TrimSpace(req.Body) == "" {
+		h.sendError(w, "Missing required fields (to, thread_id, body)", http.StatusBadRequest)
+		return
+	}
+	if _, bridged := cfg.Webhooks[req.To]; !bridged {
+		h.sendError(w, "Address is not bridged: "+req.To, http.StatusForbidden)
+		return
+	}
+	if !isValidID(req.ThreadID) || (req.ReplyTo != "" && !isValidID(req.ReplyTo)) {
+		h.sendError(w, "Invalid thread or reply-to ID format", http.StatusBadRequest)
+		return
+	}
+	const maxSubjectLen = 500
+	const maxBodyLen = 100_000
+	if len(req.Subject) > maxSubjectLen || len(req.Body) > maxBodyLen {
+		h.sendError(w, "Subject or body too long", http.StatusBadRequest)
+		return
+	}
+	if strings.ContainsAny(req.Subject+req.Body+req.Author+req.Source, "\x00") {
+		h.sendError(w, "Fields cannot contain null bytes", http.StatusBadRequest)
+		return
+	}
+
+	from := cfg.InboundWebhook.From
+	if from == "" {
+		from = "overseer"
+	}
+	subject := req.Subject
+	if subject == "" {
+		subject = "Reply in " + req.ThreadID
+	}
+	body := req.Body
+	if attribution := inboundAttribution(req.Author, req.Source); attribution != "" {
+		body = attribution + ": " + body
+	}
+
+	// Tag the reply as relayed so the router doesn't mirror it back out to
+	// the outbound webhooks it came from.
+	source := req.Source
+	if source == "" {
+		source = "webhook"
+	}
+	args := []string{"mail", "send", "--from", from, "--thread", req.ThreadID, "--relayed-from", source, "-s", subject, "-m", body}
+	if req.ReplyTo != "" {
+		args = append(args, "--reply-to", req.ReplyTo)
+	}
+	args = append(args, "--", req.To)
+
+	output, err := h.runGtCommand(r.Context(), 30*time.Second, args)
+	if err != nil {
+		h.sendError(w, "Failed to post reply: "+err.Error()+"\n"+output, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Reply posted",
+	})
+}
+
+// verifyInboundWebhook checks the shared secret, accepted either as
+// "Authorization: Bearer <secret>" or "X-Gastown-Signature: sha256=<hex>"
+// where hex is the HMAC-SHA256 of the raw body keyed by the secret.
+func verifyInboundWebhook(r *http.Request, body []byte, secret string) bool {
+	if sig := r.Header.Get("X-Gastown-Signature"); sig != "" {
+		got, err := hex.DecodeString(strings.TrimPrefix(sig, "sha256="))
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		return hmac.Equal(got, mac.Sum(nil))
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+	return false
+}
+
+// inboundAttribution formats "author (via source)" for relayed replies.
+func inboundAttribution(author, source string) string {
+	author = strings.TrimSpace(author)
+	source = strings.TrimSpace(source)
+	switch {
+	case author != "" && source != "":
+		return fmt.Sprintf("%s (via %s)", author, source)
+	case author != "":
+		return author
+	case source != "":
+		return "via " + source
+	}
+	return ""
+}
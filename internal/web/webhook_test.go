@@ -0,0 +1,168 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// newInboundWebhookTown creates a town with a bridged channel and an inbound
+// secret, plus a fake gt that records its arguments.
+func newInboundWebhookTown(t *testing.T) (*APIHandler, string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewMessagingConfig()
+	cfg.Webhooks["channel:alerts"] = []config.WebhookSink{{URL: "https://example.com/hook", Format: config.WebhookFormatSlack}}
+	cfg.InboundWebhook = &config.InboundWebhookConfig{Secret: "s3cret"}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatalf("SaveMessagingConfig: %v", err)
+	}
+
+	binDir := t.TempDir()
+	gtPath := filepath.Join(binDir, "gt")
+	argsLog := filepath.Join(binDir, "args.log")
+	script := "#!/usr/bin/env sh\nprintf '%s\\n' \"$@\" > " + argsLog + "\necho sent\n"
+	if err := os.WriteFile(gtPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake gt: %v", err)
+	}
+
+	h := &APIHandler{
+		gtPath:            gtPath,
+		workDir:           townRoot,
+		defaultRunTimeout: 5 * time.Second,
+		maxRunTimeout:     10 * time.Second,
+		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+		csrfToken:         "dashboard-token",
+	}
+	return h, argsLog
+}
+
+func TestAPIHandler_InboundWebhook(t *testing.T) {
+	const body = `{"to":"channel:alerts","thread_id":"thread-abc","reply_to":"hq-1","author":"alice","source":"slack","body":"on it"}`
+
+	t.Run("bearer secret", func(t *testing.T) {
+		h, argsLog := newInboundWebhookTown(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		data, err := os.ReadFile(argsLog)
+		if err != nil {
+			t.Fatalf("fake gt not invoked: %v", err)
+		}
+		args := strings.Split(strings.TrimSpace(string(data)), "\n")
+		want := []string{"mail", "send", "--from", "overseer", "--thread", "thread-abc", "--relayed-from", "slack",
+			"-s", "Reply in thread-abc", "-m", "alice (via slack): on it", "--reply-to", "hq-1", "--", "channel:alerts"}
+		if strings.Join(args, "|") != strings.Join(want, "|") {
+			t.Errorf("gt args = %q\nwant      %q", args, want)
+		}
+	})
+
+	t.Run("hmac signature", func(t *testing.T) {
+		h, _ := newInboundWebhookTown(t)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound", strings.NewReader(body))
+		req.Header.Set("X-Gastown-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		h, argsLog := newInboundWebhookTown(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer guess")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status %d, want 401", w.Code)
+		}
+		if _, err := os.Stat(argsLog); !os.IsNotExist(err) {
+			t.Error("gt invoked despite bad secret")
+		}
+	})
+
+	t.Run("tampered signature", func(t *testing.T) {
+		h, _ := newInboundWebhookTown(t)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(`{"other":"body"}`))
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound", strings.NewReader(body))
+		req.Header.Set("X-Gastown-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("status %d, want 401", w.Code)
+		}
+	})
+
+	t.Run("address not bridged", func(t *testing.T) {
+		h, _ := newInboundWebhookTown(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound",
+			strings.NewReader(`{"to":"mayor/","thread_id":"thread-abc","body":"hi"}`))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("status %d, want 403", w.Code)
+		}
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		h, _ := newInboundWebhookTown(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound",
+			strings.NewReader(`{"to":"channel:alerts","body":"hi"}`))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status %d, want 400", w.Code)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		h, _ := newInboundWebhookTown(t)
+		if err := config.SaveMessagingConfig(config.MessagingConfigPath(h.workDir), config.NewMessagingConfig()); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/inbound", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status %d, want 404", w.Code)
+		}
+	})
+}
+
+func TestAPIHandler_CSRFStillRequiredForOtherPosts(t *testing.T) {
+	h, _ := newInboundWebhookTown(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/mail/send", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status %d, want 403", w.Code)
+	}
+}
@@ -0,0 +1,257 @@
+package witness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/atomicfile"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// thrashTailBytes caps how much of a conversation log is read per patrol.
+// The lookback window is minutes, so the tail is enough.
+const thrashTailBytes = 4 << 20
+
+// Thrash ladder actions.
+const (
+	thrashActionNone     = ""
+	thrashActionNudge    = "nudge"
+	thrashActionEscalate = "escalate"
+	thrashActionClear    = "clear"
+)
+
+// ThrashDetection is a polecat caught looping.
+type ThrashDetection struct {
+	PolecatName string
+	Finding     agentlog.ThrashFinding
+	Action      string // "nudged", "escalated", or "already escalated"
+	Error       error
+}
+
+// DetectThrashResult holds aggregate results.
+type DetectThrashResult struct {
+	Checked  int               // Number of live polecats with a conversation log
+	Detected []ThrashDetection // Polecats caught looping this pass
+	Errors   []error           // Transient errors
+}
+
+// thrashRecord is the ladder state for one polecat session.
+type thrashRecord struct {
+	Transcript  string    `json:"transcript"` // conversation log the loop was seen in
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	NudgedAt    time.Time `json:"nudged_at"`
+	EscalatedAt time.Time `json:"escalated_at,omitzero"`
+}
+
+func thrashStateFile(townRoot, rigName string) string {
+	return filepath.Join(townRoot, "witness", "thrash-"+rigName+".json")
+}
+
+func loadThrashState(path string) map[string]*thrashRecord {
+	state := make(map[string]*thrashRecord)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path from trusted townRoot
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	if state == nil {
+		state = make(map[string]*thrashRecord)
+	}
+	return state
+}
+
+func saveThrashState(path string, state map[string]*thrashRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating witness dir: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling thrash state: %w", err)
+	}
+	return atomicfile.WriteFile(path, data, 0600)
+}
+
+// DetectThrashingPolecats scans the recent conversation of every live
+// polecat for loops: the same tool call repeated, a file edited back and
+// forth, or the same error over and over.
+//
+// The response is a ladder. The first time a polecat is caught it is
+// nudged with a hint aimed at the specific loop. If it is caught again in
+// activity after the nudge, the witness files an escalation bead via
+// gt escalate. A polecat that stays clean for the lookback window, or
+// starts a new conversation, goes back to the bottom of the ladder.
+func DetectThrashingPolecats(workDir, rigName string) *DetectThrashResult {
+	result := &DetectThrashResult{}
+
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		townRoot = workDir
+	}
+	initRegistryFromTownRoot(townRoot)
+
+	wc := config.LoadOperationalConfig(townRoot).GetWitnessConfig()
+	limits := agentlog.ThrashLimits{
+		RepeatedCalls:  wc.ThrashRepeatedCallsV(),
+		Oscillations:   wc.ThrashOscillationsV(),
+		RepeatedErrors: wc.ThrashRepeatedErrorsV(),
+	}
+	if limits.RepeatedCalls <= 0 && limits.Oscillations <= 0 && limits.RepeatedErrors <= 0 {
+		return result
+	}
+	lookback := wc.ThrashLookbackD()
+
+	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
+	entries, err := os.ReadDir(polecatsDir)
+	if err != nil {
+		return result
+	}
+
+	statePath := thrashStateFile(townRoot, rigName)
+	state := loadThrashState(statePath)
+	live := make(map[string]bool)
+	t := tmux.NewTmux()
+	now := time.Now()
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		polecatName := entry.Name()
+		sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
+
+		alive, err := t.HasSession(sessionName)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("checking session %s: %w", sessionName, err))
+			continue
+		}
+		if !alive {
+			continue
+		}
+		live[sessionName] = true
+
+		transcript, ok := agentlog.NewestClaudeCodeSession(filepath.Join(polecatsDir, polecatName, rigName))
+		if !ok {
+			continue
+		}
+		result.Checked++
+
+		rec := state[sessionName]
+		if rec != nil && rec.Transcript != transcript {
+			rec = nil // new conversation, fresh start
+			delete(state, sessionName)
+		}
+
+		since := now.Add(-lookback)
+		if rec != nil && rec.NudgedAt.After(since) {
+			since = rec.NudgedAt // only activity after the hint counts against it
+		}
+		events, err := agentlog.ReadClaudeCodeSessionTail(transcript, sessionName, thrashTailBytes)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("reading conversation for %s: %w", polecatName, err))
+			continue
+		}
+		finding, found := detectThrash(events, since, limits)
+
+		switch nextThrashAction(rec, found, now, lookback) {
+		case thrashActionClear:
+			delete(state, sessionName)
+		case thrashActionNudge:
+			d := ThrashDetection{PolecatName: polecatName, Finding: finding, Action: "nudged"}
+			if err := t.NudgeSession(sessionName, "THRASH_DETECTED: "+finding.Hint()); err != nil {
+				d.Error = fmt.Errorf("nudging %s: %w", sessionName, err)
+			} else {
+				state[sessionName] = &thrashRecord{Transcript: transcript, Kind: finding.Kind, Subject: finding.Subject, NudgedAt: now}
+			}
+			result.Detected = append(result.Detected, d)
+		case thrashActionEscalate:
+			d := ThrashDetection{PolecatName: polecatName, Finding: finding, Action: "escalated"}
+			if err := escalateThrash(townRoot, rigName, polecatName, finding, rec); err != nil {
+				d.Error = err
+			} else {
+				rec.EscalatedAt = now
+			}
+			result.Detected = append(result.Detected, d)
+		default:
+			if found {
+				result.Detected = append(result.Detected, ThrashDetection{PolecatName: polecatName, Finding: finding, Action: "already escalated"})
+			}
+		}
+	}
+
+	// Forget sessions that have gone away.
+	for name := range state {
+		if !live[name] {
+			delete(state, name)
+		}
+	}
+	if err := saveThrashState(statePath, state); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	return result
+}
+
+// detectThrash runs the detector over the events after since and returns
+// the first loop it finds.
+func detectThrash(events []agentlog.AgentEvent, since time.Time, limits agentlog.ThrashLimits) (agentlog.ThrashFinding, bool) {
+	d := agentlog.NewThrashDetector(limits)
+	for _, ev := range events {
+		if !ev.Timestamp.After(since) {
+			continue
+		}
+		if found := d.Observe(ev); len(found) > 0 {
+			return found[0], true
+		}
+	}
+	return agentlog.ThrashFinding{}, false
+}
+
+// nextThrashAction decides the ladder step for a polecat given its record
+// and whether a loop was found in activity since the last nudge.
+func nextThrashAction(rec *thrashRecord, found bool, now time.Time, lookback time.Duration) string {
+	switch {
+	case rec == nil && found:
+		return thrashActionNudge
+	case rec == nil:
+		return thrashActionNone
+	case found && rec.EscalatedAt.IsZero():
+		return thrashActionEscalate
+	case found:
+		return thrashActionNone // already escalated; the bead tracks it
+	case now.Sub(rec.NudgedAt) >= lookback && (rec.EscalatedAt.IsZero() || now.Sub(rec.EscalatedAt) >= lookback):
+		return thrashActionClear // clean for a full window: recovered
+	}
+	return thrashActionNone
+}
+
+// escalateThrash files an escalation bead for a polecat that kept looping
+// after being nudged. The fingerprint dedups repeats for the same polecat.
+func escalateThrash(townRoot, rigName, polecatName string, finding agentlog.ThrashFinding, rec *thrashRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	target := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+	reason := fmt.Sprintf("Nudged at %s about %s (%s); still looping: %s %q x%d. Inspect with: gt peek %s",
+		rec.NudgedAt.Format("15:04"), rec.Kind, rec.Subject, finding.Kind, finding.Subject, finding.Count, target)
+	cmd := exec.CommandContext(ctx, "gt", "escalate", //nolint:gosec // G204: args are internal identifiers
+		"--severity", "medium",
+		"--source", "witness:"+rigName,
+		"--fingerprint", "thrash:"+target,
+		"--reason", reason,
+		fmt.Sprintf("Polecat %s is thrashing (%s)", target, finding.Kind))
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gt escalate failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package witness
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+func TestNextThrashAction(t *testing.T) {
+	now := time.Now()
+	lookback := 30 * time.Minute
+	recent := &thrashRecord{NudgedAt: now.Add(-5 * time.Minute)}
+	stale := &thrashRecord{NudgedAt: now.Add(-time.Hour)}
+	escalated := &thrashRecord{NudgedAt: now.Add(-20 * time.Minute), EscalatedAt: now.Add(-10 * time.Minute)}
+
+	tests := []struct {
+		name  string
+		rec   *thrashRecord
+		found bool
+		want  string
+	}{
+		{"first loop nudges", nil, true, thrashActionNudge},
+		{"clean stays clean", nil, false, thrashActionNone},
+		{"loop after nudge escalates", recent, true, thrashActionEscalate},
+		{"quiet after nudge waits", recent, false, thrashActionNone},
+		{"clean for a window clears", stale, false, thrashActionClear},
+		{"escalated is not repeated", escalated, true, thrashActionNone},
+		{"escalated waits out the window", escalated, false, thrashActionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextThrashAction(tt.rec, tt.found, now, lookback); got != tt.want {
+				t.Errorf("nextThrashAction = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectThrash_IgnoresEventsBeforeSince(t *testing.T) {
+	since := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	call := func(at time.Time) agentlog.AgentEvent {
+		return agentlog.AgentEvent{EventType: "tool_use", Content: `Bash: {"command":"go test ./..."}`, Timestamp: at}
+	}
+	limits := agentlog.ThrashLimits{RepeatedCalls: 3}
+
+	before := []agentlog.AgentEvent{call(since.Add(-3 * time.Minute)), call(since.Add(-2 * time.Minute)), call(since.Add(time.Minute))}
+	if _, found := detectThrash(before, since, limits); found {
+		t.Error("calls before since counted")
+	}
+
+	after := append(before, call(since.Add(2*time.Minute)), call(since.Add(3*time.Minute)))
+	f, found := detectThrash(after, since, limits)
+	if !found || f.Kind != agentlog.ThrashRepeatedCall || f.Count != 3 {
+		t.Errorf("detectThrash = %+v, %v", f, found)
+	}
+}
+
+func TestThrashStateRoundTrip(t *testing.T) {
+	path := thrashStateFile(t.TempDir(), "gastown")
+	if filepath.Base(path) != "thrash-gastown.json" {
+		t.Errorf("state file = %s", path)
+	}
+	if state := loadThrashState(path); len(state) != 0 {
+		t.Fatalf("missing file loaded %v", state)
+	}
+	nudged := time.Now().UTC().Truncate(time.Second)
+	state := map[string]*thrashRecord{"gt-gastown-toast": {Transcript: "/x.jsonl", Kind: agentlog.ThrashOscillation, NudgedAt: nudged}}
+	if err := saveThrashState(path, state); err != nil {
+		t.Fatal(err)
+	}
+	got := loadThrashState(path)["gt-gastown-toast"]
+	if got == nil || !got.NudgedAt.Equal(nudged) || !got.EscalatedAt.IsZero() {
+		t.Errorf("loaded %+v", got)
+	}
+}
@@ -0,0 +1,219 @@
+package witness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/warrant"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Evidence sizes for policy-filed warrants.
+const (
+	warrantPaneLines     = 60
+	warrantTailEvents    = 40
+	warrantTailBudget    = 4000
+	warrantRecentErrorsN = 200 // tool errors considered for repetition
+)
+
+// FiledWarrant is a warrant the witness filed by policy.
+type FiledWarrant struct {
+	PolecatName  string
+	Target       string
+	Policy       string
+	Reason       string
+	ExecuteAfter time.Time
+}
+
+// FileWarrantsResult holds aggregate results.
+type FileWarrantsResult struct {
+	Checked int            // Number of live polecats evaluated
+	Filed   []FiledWarrant // Warrants filed this pass
+	Errors  []error        // Transient errors
+}
+
+// FileWarrantsByPolicy evaluates the witness warrant policies against every
+// live polecat in the rig and files a warrant, with evidence, for each one a
+// policy matches. Polecats that already have a pending warrant are skipped,
+// as are polecats whose last warrant was vetoed within warrant.VetoHold:
+// re-filing would overwrite the veto and restart the countdown.
+//
+// Filed warrants carry an execute-after deadline (the policy's grace period)
+// so a human can veto them with gt warrant veto before Boot executes them.
+// With no policies configured this is a no-op.
+func FileWarrantsByPolicy(workDir, rigName string) *FileWarrantsResult {
+	result := &FileWarrantsResult{}
+
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		townRoot = workDir
+	}
+	initRegistryFromTownRoot(townRoot)
+
+	var policies []warrant.Policy
+	for _, p := range config.LoadOperationalConfig(townRoot).GetWitnessConfig().WarrantPolicies {
+		if err := p.Validate(); err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	if len(policies) == 0 {
+		return result
+	}
+	needTranscript := false
+	for _, p := range policies {
+		needTranscript = needTranscript || p.NeedsTranscript()
+	}
+
+	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
+	entries, err := os.ReadDir(polecatsDir)
+	if err != nil {
+		return result
+	}
+
+	warrantDir := warrant.Dir(townRoot)
+	t := tmux.NewTmux()
+	now := time.Now()
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		polecatName := entry.Name()
+		target := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+		sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
+
+		alive, err := t.HasSession(sessionName)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("checking session %s: %w", sessionName, err))
+			continue
+		}
+		if !alive {
+			continue // Dead sessions are zombie detection's job
+		}
+		result.Checked++
+		if warrant.IsHeld(warrantDir, target, now) {
+			continue
+		}
+
+		clonePath := filepath.Join(polecatsDir, polecatName, rigName)
+		obs, transcript := observePolecat(t, sessionName, clonePath, needTranscript)
+		policy, reason := warrant.Evaluate(policies, obs, now)
+		if policy == nil {
+			continue
+		}
+
+		executeAfter := now.Add(policy.GraceDuration())
+		w := &warrant.Warrant{
+			ID:           warrant.NewID(now),
+			Target:       target,
+			Reason:       reason,
+			FiledBy:      rigName + "/witness",
+			FiledAt:      now,
+			Policy:       policy.Name,
+			Evidence:     collectWarrantEvidence(t, sessionName, clonePath, obs, transcript),
+			ExecuteAfter: &executeAfter,
+		}
+		if err := warrant.Save(warrant.FilePath(warrantDir, target), w); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("filing warrant for %s: %w", target, err))
+			continue
+		}
+		result.Filed = append(result.Filed, FiledWarrant{
+			PolecatName:  polecatName,
+			Target:       target,
+			Policy:       policy.Name,
+			Reason:       reason,
+			ExecuteAfter: executeAfter,
+		})
+	}
+
+	return result
+}
+
+// observePolecat measures the signals warrant policies test. Signals that
+// cannot be read are left zero, which policies treat as "condition not met".
+func observePolecat(t *tmux.Tmux, sessionName, clonePath string, needTranscript bool) (warrant.Observation, []agentlog.AgentEvent) {
+	var obs warrant.Observation
+	if activity, err := t.GetSessionActivity(sessionName); err == nil {
+		obs.LastOutput = activity
+	}
+
+	g := git.NewGit(clonePath)
+	lastCommit, _ := g.LastCommitTime()
+	obs.LastGitChange = lastCommit
+	if status, err := g.Status(); err == nil {
+		for _, group := range [][]string{status.Modified, status.Added, status.Untracked} {
+			for _, f := range group {
+				if info, err := os.Stat(filepath.Join(clonePath, f)); err == nil && info.ModTime().After(obs.LastGitChange) {
+					obs.LastGitChange = info.ModTime()
+				}
+			}
+		}
+	}
+
+	if !needTranscript {
+		return obs, nil
+	}
+	path, ok := agentlog.NewestClaudeCodeSession(clonePath)
+	if !ok {
+		return obs, nil
+	}
+	events, err := agentlog.ReadClaudeCodeSession(path, sessionName)
+	if err != nil {
+		return obs, nil
+	}
+	obs.RepeatedError, obs.RepeatedErrorCount, obs.TokensSinceCommit = transcriptSignals(events, lastCommit)
+	return obs, events
+}
+
+// transcriptSignals extracts the most repeated recent tool error and the
+// tokens spent after since from a conversation log. Cache reads are not
+// counted: they are cheap and would swamp the signal on long sessions.
+func transcriptSignals(events []agentlog.AgentEvent, since time.Time) (string, int, int) {
+	var toolErrors []string
+	tokens := 0
+	for _, ev := range events {
+		switch {
+		case ev.EventType == "tool_result" && ev.IsError:
+			toolErrors = append(toolErrors, ev.Content)
+		case ev.EventType == "usage" && ev.Timestamp.After(since):
+			tokens += ev.InputTokens + ev.OutputTokens + ev.CacheCreationTokens
+		}
+	}
+	if len(toolErrors) > warrantRecentErrorsN {
+		toolErrors = toolErrors[len(toolErrors)-warrantRecentErrorsN:]
+	}
+	errKey, errCount := warrant.MostRepeatedError(toolErrors)
+	return errKey, errCount, tokens
+}
+
+// collectWarrantEvidence snapshots the pane, the tail of the agent's
+// conversation, and the worktree state for the warrant record.
+func collectWarrantEvidence(t *tmux.Tmux, sessionName, clonePath string, obs warrant.Observation, transcript []agentlog.AgentEvent) *warrant.Evidence {
+	ev := &warrant.Evidence{Observation: obs}
+	if pane, err := t.CapturePane(sessionName, warrantPaneLines); err == nil {
+		ev.PaneCapture = strings.TrimRight(pane, "\n")
+	}
+	if transcript == nil {
+		if path, ok := agentlog.NewestClaudeCodeSession(clonePath); ok {
+			transcript, _ = agentlog.ReadClaudeCodeSession(path, sessionName)
+		}
+	}
+	if len(transcript) > warrantTailEvents {
+		transcript = transcript[len(transcript)-warrantTailEvents:]
+	}
+	ev.AgentLogTail = agentlog.CondenseTranscript(transcript, warrantTailBudget)
+	if status, err := git.NewGit(clonePath).StatusSummary(); err == nil {
+		ev.GitStatus = status
+	}
+	return ev
+}
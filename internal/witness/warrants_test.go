@@ -0,0 +1,100 @@
+package witness
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/warrant"
+)
+
+func TestTranscriptSignals(t *testing.T) {
+	commit := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	events := []agentlog.AgentEvent{
+		{EventType: "usage", Timestamp: commit.Add(-time.Minute), InputTokens: 9000},
+		{EventType: "tool_result", IsError: true, Content: "exit status 1: go test failed at line 12"},
+		{EventType: "tool_result", Content: "ok"},
+		{EventType: "usage", Timestamp: commit.Add(time.Minute), InputTokens: 100, OutputTokens: 50, CacheCreationTokens: 10, CacheReadTokens: 5000},
+		{EventType: "tool_result", IsError: true, Content: "exit status 1: go test failed at line 40"},
+		{EventType: "tool_result", IsError: true, Content: "file not found"},
+	}
+
+	errKey, errCount, tokens := transcriptSignals(events, commit)
+	if errKey != "exit status N: go test failed at line N" || errCount != 2 {
+		t.Errorf("repeated error = %q x%d", errKey, errCount)
+	}
+	if tokens != 160 {
+		t.Errorf("tokens = %d, want 160 (post-commit, excluding cache reads)", tokens)
+	}
+}
+
+// installFakeTmuxIdleSession puts a tmux on PATH that reports every session
+// alive and idle for two hours.
+func installFakeTmuxIdleSession(t *testing.T) {
+	t.Helper()
+
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do
+	case "$arg" in
+	display-message) echo $(( $(date +%s) - 7200 )); exit 0 ;;
+	capture-pane) echo "waiting for input"; exit 0 ;;
+	esac
+done
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "tmux"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake tmux: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestFileWarrantsByPolicyRespectsVeto(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tmux is a shell script")
+	}
+	installFakeTmuxIdleSession(t)
+
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "settings", "gastown/polecats/alpha"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settings := `{"operational": {"witness": {"warrant_policies": [{"name": "silent", "idle_for": "1h"}]}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := warrant.FilePath(warrant.Dir(townRoot), "gastown/polecats/alpha")
+
+	if result := FileWarrantsByPolicy(townRoot, "gastown"); len(result.Filed) != 1 {
+		t.Fatalf("first pass filed %d warrants (errors %v), want 1", len(result.Filed), result.Errors)
+	}
+	w, err := warrant.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Veto("mayor", "still working", time.Now())
+	if err := warrant.Save(path, w); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := FileWarrantsByPolicy(townRoot, "gastown"); len(result.Filed) != 0 {
+		t.Errorf("pass after veto filed %d warrants, want 0", len(result.Filed))
+	}
+	if w, err := warrant.Load(path); err != nil || !w.Vetoed {
+		t.Fatalf("veto overwritten: %+v, %v", w, err)
+	}
+
+	expired := time.Now().Add(-warrant.VetoHold - time.Minute)
+	w.VetoedAt = &expired
+	if err := warrant.Save(path, w); err != nil {
+		t.Fatal(err)
+	}
+	if result := FileWarrantsByPolicy(townRoot, "gastown"); len(result.Filed) != 1 {
+		t.Errorf("pass after veto hold filed %d warrants, want 1", len(result.Filed))
+	}
+}
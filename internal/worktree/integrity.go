@@ -12,6 +12,11 @@ import (
 // that depend on hook/worktree state should fail closed when this is returned.
 var ErrIntegrityViolation = errors.New("worktree integrity violation")
 
+// ErrMaterializationIncomplete marks a worktree whose files were still being
+// copied in from a template when materialization stopped. Always reported
+// together with ErrIntegrityViolation.
+var ErrMaterializationIncomplete = errors.New("worktree materialization incomplete")
+
 // IntegrityOptions controls worktree validation.
 type IntegrityOptions struct {
 	// TownRoot bounds the upward search for .git metadata. Empty means search to
@@ -26,7 +31,9 @@ type IntegrityOptions struct {
 
 // Validate checks the nearest worktree metadata for path. It accepts regular
 // clones (.git directory) and validates linked worktree .git files by ensuring
-// they are well formed and point at usable git metadata.
+// they are well formed and point at usable git metadata. Reflink-materialized
+// worktrees are linked worktrees too; one whose copy never finished is
+// rejected.
 func Validate(path string, opts IntegrityOptions) error {
 	if path == "" {
 		cwd, err := os.Getwd()
@@ -82,10 +89,22 @@ func Validate(path string, opts IntegrityOptions) error {
 	if err := validateGitDir(target, marker); err != nil {
 		return err
 	}
+	if err := validateMaterialized(target, marker); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// validateMaterialized rejects a worktree whose files were still being
+// copied in from a template when materialization stopped (see Materializer).
+func validateMaterialized(gitdir, marker string) error {
+	if _, err := os.Stat(filepath.Join(gitdir, pendingFile)); err == nil {
+		return fmt.Errorf("%w: %w for %s (files may be missing)", ErrIntegrityViolation, ErrMaterializationIncomplete, marker)
+	}
+	return nil
+}
+
 func validateGitDir(gitdir, marker string) error {
 	if _, err := os.Stat(filepath.Join(gitdir, "HEAD")); err != nil {
 		return fmt.Errorf("%w: gitdir metadata incomplete for %s: missing HEAD in %s", ErrIntegrityViolation, marker, gitdir)
@@ -0,0 +1,399 @@
+package worktree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Strategy selects how a new worktree's files are written.
+type Strategy string
+
+const (
+	// StrategyWorktree is a plain `git worktree add` checkout.
+	StrategyWorktree Strategy = "worktree"
+	// StrategyReflink registers the worktree with git and copies its files
+	// from a prepared template checkout using reflinks (FICLONE on btrfs/xfs,
+	// clonefile on APFS). Unchanged files share extents with the template, so
+	// a new worktree costs metadata, not gigabytes. Falls back to a plain
+	// worktree, with a warning, when the filesystem cannot reflink.
+	StrategyReflink Strategy = "reflink"
+	// StrategyAuto uses reflink when the filesystem supports it and a plain
+	// worktree otherwise, without warning.
+	StrategyAuto Strategy = "auto"
+)
+
+// ParseStrategy parses a worktree_strategy config value. Empty means
+// StrategyWorktree.
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(strings.TrimSpace(s)) {
+	case "", StrategyWorktree:
+		return StrategyWorktree, nil
+	case StrategyReflink:
+		return StrategyReflink, nil
+	case StrategyAuto:
+		return StrategyAuto, nil
+	}
+	return "", fmt.Errorf("unknown worktree_strategy %q (want worktree, reflink, or auto)", s)
+}
+
+// TemplateDirName is the rig-level directory holding template checkouts for
+// reflink worktrees, one subdirectory per source repository.
+const TemplateDirName = ".worktree-template"
+
+const (
+	// infoFile, in a worktree's gitdir, records how it was materialized.
+	infoFile = "gt-materialized"
+	// pendingFile, in a worktree's gitdir, exists while files are being
+	// copied in. A worktree that still has it is incomplete.
+	pendingFile = "gt-materialize.pending"
+)
+
+// Info records how a worktree was materialized. Plain worktrees have none.
+type Info struct {
+	Strategy       Strategy  `json:"strategy"`
+	TemplateCommit string    `json:"template_commit"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Materializer creates linked worktrees of one repository.
+type Materializer struct {
+	Strategy Strategy
+	// TemplateDir is the prepared checkout reflink copies are taken from.
+	// It is itself a linked worktree of the repository, created on first use
+	// and moved to each requested commit. One per repository.
+	TemplateDir string
+	// Warn receives fallback notices for StrategyReflink and Sparse. Nil
+	// discards them.
+	Warn func(format string, args ...any)
+	// Sparse, when set, is a cone-mode sparse checkout applied before any
+	// file is written: the worktree is added without a checkout, narrowed,
+	// then checked out. Reflinking is skipped, since it copies the whole
+	// template. If the cone cannot be set the worktree gets a full checkout.
+	Sparse []string
+}
+
+// AddFromRef creates a worktree at path on a new branch from startPoint.
+// Returns the strategy actually used.
+func (m Materializer) AddFromRef(g *git.Git, path, branch, startPoint string) (Strategy, error) {
+	return m.add(g, path, branch, startPoint, func() error {
+		return g.WorktreeAddFromRef(path, branch, startPoint)
+	})
+}
+
+// AddExistingForce creates a worktree at path on an existing branch, even if
+// the branch is checked out elsewhere. Returns the strategy actually used.
+func (m Materializer) AddExistingForce(g *git.Git, path, branch string) (Strategy, error) {
+	return m.add(g, path, branch, "", func() error {
+		return g.WorktreeAddExistingForce(path, branch)
+	})
+}
+
+func (m Materializer) add(g *git.Git, path, branch, startPoint string, plain func() error) (Strategy, error) {
+	if len(m.Sparse) > 0 {
+		if err := m.addSparse(g, path, branch, startPoint); err != nil {
+			return "", err
+		}
+		return StrategyWorktree, nil
+	}
+	if m.Strategy == StrategyReflink || m.Strategy == StrategyAuto {
+		err := m.addReflink(g, path, branch, startPoint)
+		if err == nil {
+			return StrategyReflink, nil
+		}
+		if m.Strategy == StrategyReflink && m.Warn != nil {
+			m.Warn("reflink worktree unavailable, using plain worktree: %v", err)
+		}
+	}
+	if err := plain(); err != nil {
+		return "", err
+	}
+	return StrategyWorktree, nil
+}
+
+// addSparse adds the worktree without a checkout, sets the sparse cone and
+// then checks out, so files outside the cone are never written.
+func (m Materializer) addSparse(g *git.Git, path, branch, startPoint string) error {
+	if err := g.WorktreeAddNoCheckout(path, branch, startPoint); err != nil {
+		return err
+	}
+	if err := git.InitSparseCheckout(path, m.Sparse); err != nil {
+		if m.Warn != nil {
+			m.Warn("could not set sparse checkout (%v); using a full checkout", err)
+		}
+		_ = git.RemoveSparseCheckout(path)
+	}
+	if err := g.WorktreeCheckout(path, "HEAD"); err != nil {
+		_ = g.WorktreeRemove(path, true)
+		_ = os.RemoveAll(path)
+		if startPoint != "" {
+			_ = g.DeleteBranch(branch, true)
+		}
+		return fmt.Errorf("checking out worktree: %w", err)
+	}
+	return nil
+}
+
+// errNoReflink reports a filesystem without reflink support.
+var errNoReflink = errors.New("filesystem does not support reflinks")
+
+func (m Materializer) addReflink(g *git.Git, path, branch, startPoint string) error {
+	if m.TemplateDir == "" {
+		return errors.New("no template directory configured")
+	}
+	if err := os.MkdirAll(filepath.Dir(m.TemplateDir), 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if !reflinkSupported(filepath.Dir(m.TemplateDir), filepath.Dir(path)) {
+		return errNoReflink
+	}
+
+	ref := startPoint
+	if ref == "" {
+		ref = branch
+	}
+	commit, err := g.Rev(ref + "^{commit}")
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", ref, err)
+	}
+
+	// One materialization at a time per template: the template is moved to
+	// the requested commit and must not move again until the copy finishes.
+	fl := flock.New(m.TemplateDir + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking worktree template: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if err := m.prepareTemplate(g, commit); err != nil {
+		return fmt.Errorf("preparing template: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(m.TemplateDir, ".gitmodules")); err == nil {
+		// Submodule checkouts point at the template's own gitdir; a copy
+		// would share them. Not worth the bookkeeping — use a worktree.
+		return errors.New("repository has submodules")
+	}
+
+	if err := g.WorktreeAddNoCheckout(path, branch, startPoint); err != nil {
+		return err
+	}
+	gitdir, err := worktreeGitDir(path)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(gitdir, pendingFile), []byte(commit+"\n"), 0644)
+	}
+	if err == nil {
+		err = m.copyTemplate(path, gitdir)
+	}
+	if err == nil {
+		err = git.NewGit(path).RefreshIndex()
+	}
+	if err == nil {
+		err = writeInfo(gitdir, Info{Strategy: StrategyReflink, TemplateCommit: commit, CreatedAt: time.Now()})
+	}
+	if err == nil {
+		err = os.Remove(filepath.Join(gitdir, pendingFile))
+	}
+	if err != nil {
+		_ = g.WorktreeRemove(path, true)
+		_ = os.RemoveAll(path)
+		if startPoint != "" {
+			// The branch was created above; leaving it would make the
+			// plain-worktree fallback fail with "branch already exists".
+			_ = g.DeleteBranch(branch, true)
+		}
+		return err
+	}
+	return nil
+}
+
+// prepareTemplate creates the template checkout or moves it to commit.
+func (m Materializer) prepareTemplate(g *git.Git, commit string) error {
+	if err := VerifyTemplate(m.TemplateDir); err != nil {
+		_ = g.WorktreeRemove(m.TemplateDir, true)
+		_ = os.RemoveAll(m.TemplateDir)
+		_ = g.WorktreePrune()
+		return g.WorktreeAddDetached(m.TemplateDir, commit)
+	}
+	tg := git.NewGit(m.TemplateDir)
+	if head, err := tg.Rev("HEAD"); err == nil && head == commit {
+		if status, err := tg.Status(); err == nil && status.Clean {
+			return nil
+		}
+	}
+	if err := g.WorktreeCheckout(m.TemplateDir, commit); err != nil {
+		return err
+	}
+	return tg.CleanForce()
+}
+
+// VerifyTemplate checks that dir is a usable template checkout.
+func VerifyTemplate(dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+	return Validate(dir, IntegrityOptions{TownRoot: dir, Require: true})
+}
+
+// copyTemplate reflinks every file of the template into path, and the
+// template's index into the new worktree's gitdir so git sees a clean tree.
+func (m Materializer) copyTemplate(path, gitdir string) error {
+	err := filepath.WalkDir(m.TemplateDir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(m.TemplateDir, src)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		dst := filepath.Join(path, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(dst, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(src)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dst)
+		case info.Mode().IsRegular():
+			if err := cloneFile(src, dst, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("reflinking %s: %w", rel, err)
+			}
+			// Keep the template's mtime: DiskUsage counts files modified
+			// after materialization as no longer shared.
+			return os.Chtimes(dst, info.ModTime(), info.ModTime())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	templateGitDir, err := worktreeGitDir(m.TemplateDir)
+	if err != nil {
+		return err
+	}
+	index, err := os.ReadFile(filepath.Join(templateGitDir, "index"))
+	if err != nil {
+		return fmt.Errorf("reading template index: %w", err)
+	}
+	return os.WriteFile(filepath.Join(gitdir, "index"), index, 0644)
+}
+
+// ReadInfo returns how the worktree at path was materialized, or nil for a
+// plain worktree.
+func ReadInfo(path string) (*Info, error) {
+	gitdir, err := worktreeGitDir(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(gitdir, infoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", infoFile, err)
+	}
+	return &info, nil
+}
+
+func writeInfo(gitdir string, info Info) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gitdir, infoFile), data, 0644)
+}
+
+// worktreeGitDir resolves the gitdir a linked worktree's .git file points at.
+func worktreeGitDir(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", fmt.Errorf("%s/.git is not a linked worktree gitfile", path)
+	}
+	target := strings.TrimSpace(strings.TrimPrefix(line, "gitdir: "))
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(path, target)
+	}
+	return filepath.Clean(target), nil
+}
+
+// Usage is a worktree's disk usage.
+type Usage struct {
+	Strategy Strategy `json:"strategy"`
+	// Allocated is what du reports: every file's allocated blocks, counting
+	// extents shared with the template.
+	Allocated int64 `json:"allocated_bytes"`
+	// Exclusive estimates space this worktree alone holds. For a reflink
+	// worktree it counts files written after materialization (unchanged
+	// files share the template's extents); for a plain worktree it equals
+	// Allocated.
+	Exclusive int64 `json:"exclusive_bytes"`
+	Files     int   `json:"files"`
+}
+
+// DiskUsage walks the worktree at path and reports its disk usage.
+func DiskUsage(path string) (Usage, error) {
+	u := Usage{Strategy: StrategyWorktree}
+	info, err := ReadInfo(path)
+	if err != nil && !os.IsNotExist(err) {
+		return u, err
+	}
+	var since time.Time
+	if info != nil {
+		u.Strategy = info.Strategy
+		since = info.CreatedAt
+	}
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries don't stop the count
+		}
+		if d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		n := allocatedBytes(fi)
+		u.Allocated += n
+		u.Files++
+		if since.IsZero() || fi.ModTime().After(since) {
+			u.Exclusive += n
+		}
+		return nil
+	})
+	return u, err
+}
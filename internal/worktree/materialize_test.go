@@ -0,0 +1,282 @@
+package worktree
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// plainCopy stands in for reflinks so materialization runs on any filesystem.
+func plainCopy(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func stubCloneFile(t *testing.T, fn func(src, dst string, perm os.FileMode) error) {
+	t.Helper()
+	old := cloneFile
+	cloneFile = fn
+	t.Cleanup(func() { cloneFile = old })
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newRepo returns a repository with one commit holding a few files.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	repo := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(filepath.Join(repo, "src", "deep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init", "-q", "-b", "main")
+	files := map[string]string{
+		"README.md":           "hello\n",
+		"src/main.go":         "package main\n",
+		"src/deep/data.txt":   strings.Repeat("x", 10000),
+		"scripts/run.sh":      "#!/bin/sh\necho hi\n",
+		".gitignore":          "node_modules/\n",
+		"src/deep/.keep-file": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(repo, "scripts", "run.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("README.md", filepath.Join(repo, "LINK.md")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-q", "-m", "initial")
+	return repo
+}
+
+func TestParseStrategy(t *testing.T) {
+	for in, want := range map[string]Strategy{"": StrategyWorktree, "worktree": StrategyWorktree, " reflink ": StrategyReflink, "auto": StrategyAuto} {
+		if got, err := ParseStrategy(in); err != nil || got != want {
+			t.Errorf("ParseStrategy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseStrategy("overlay"); err == nil {
+		t.Error("ParseStrategy(overlay) succeeded")
+	}
+}
+
+func TestMaterializerReflink(t *testing.T) {
+	stubCloneFile(t, plainCopy)
+	repo := newRepo(t)
+	g := git.NewGit(repo)
+	rigDir := filepath.Dir(repo)
+	m := Materializer{Strategy: StrategyReflink, TemplateDir: filepath.Join(rigDir, TemplateDirName, "repo")}
+
+	path := filepath.Join(rigDir, "polecats", "nux", "repo")
+	used, err := m.AddFromRef(g, path, "polecat/nux", "main")
+	if err != nil {
+		t.Fatalf("AddFromRef: %v", err)
+	}
+	if used != StrategyReflink {
+		t.Fatalf("strategy = %q, want reflink", used)
+	}
+
+	if got := runGit(t, path, "status", "--porcelain"); got != "" {
+		t.Errorf("materialized worktree not clean:\n%s", got)
+	}
+	if got := runGit(t, path, "rev-parse", "--abbrev-ref", "HEAD"); got != "polecat/nux" {
+		t.Errorf("branch = %q", got)
+	}
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(filepath.Join(path, "scripts", "run.sh")); err != nil || fi.Mode().Perm()&0100 == 0 {
+			t.Errorf("run.sh mode lost: %v %v", fi, err)
+		}
+		if target, err := os.Readlink(filepath.Join(path, "LINK.md")); err != nil || target != "README.md" {
+			t.Errorf("symlink = %q, %v", target, err)
+		}
+	}
+	if err := Validate(path, IntegrityOptions{Require: true}); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	info, err := ReadInfo(path)
+	if err != nil || info == nil || info.Strategy != StrategyReflink {
+		t.Fatalf("ReadInfo = %+v, %v", info, err)
+	}
+
+	// Writing a file makes it exclusive to the worktree.
+	before, err := DiskUsage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Strategy != StrategyReflink || before.Exclusive >= before.Allocated {
+		t.Errorf("fresh usage = %+v, want mostly shared", before)
+	}
+	if err := os.WriteFile(filepath.Join(path, "src", "deep", "data.txt"), []byte(strings.Repeat("y", 10000)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	after, err := DiskUsage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Exclusive <= before.Exclusive {
+		t.Errorf("exclusive did not grow after write: %d -> %d", before.Exclusive, after.Exclusive)
+	}
+
+	// A second worktree reuses the template; the first is untouched.
+	second := filepath.Join(rigDir, "polecats", "slit", "repo")
+	if _, err := m.AddFromRef(g, second, "polecat/slit", "main"); err != nil {
+		t.Fatalf("second AddFromRef: %v", err)
+	}
+	if got := runGit(t, second, "status", "--porcelain"); got != "" {
+		t.Errorf("second worktree not clean:\n%s", got)
+	}
+}
+
+func TestMaterializerFallsBackToWorktree(t *testing.T) {
+	stubCloneFile(t, func(src, dst string, perm os.FileMode) error { return errors.New("EOPNOTSUPP") })
+	repo := newRepo(t)
+	g := git.NewGit(repo)
+
+	for _, strategy := range []Strategy{StrategyAuto, StrategyReflink} {
+		var warned []string
+		m := Materializer{
+			Strategy:    strategy,
+			TemplateDir: filepath.Join(filepath.Dir(repo), TemplateDirName, string(strategy)),
+			Warn:        func(f string, a ...any) { warned = append(warned, f) },
+		}
+		path := filepath.Join(filepath.Dir(repo), "wt-"+string(strategy))
+		used, err := m.AddFromRef(g, path, "b-"+string(strategy), "main")
+		if err != nil {
+			t.Fatalf("%s: AddFromRef: %v", strategy, err)
+		}
+		if used != StrategyWorktree {
+			t.Errorf("%s: strategy = %q, want worktree", strategy, used)
+		}
+		if (len(warned) > 0) != (strategy == StrategyReflink) {
+			t.Errorf("%s: warnings = %v", strategy, warned)
+		}
+		if info, _ := ReadInfo(path); info != nil {
+			t.Errorf("%s: plain worktree has materialization info %+v", strategy, info)
+		}
+	}
+}
+
+func TestMaterializerFallsBackAfterFailedCopy(t *testing.T) {
+	// The reflink probe succeeds but copying the template fails, after the
+	// worktree and its branch were created.
+	stubCloneFile(t, func(src, dst string, perm os.FileMode) error {
+		if strings.Contains(filepath.Base(src), ".reflink-probe-") {
+			return plainCopy(src, dst, perm)
+		}
+		return errors.New("ENOSPC")
+	})
+	repo := newRepo(t)
+	g := git.NewGit(repo)
+	var warned []string
+	m := Materializer{
+		Strategy:    StrategyReflink,
+		TemplateDir: filepath.Join(filepath.Dir(repo), TemplateDirName, "repo"),
+		Warn:        func(f string, a ...any) { warned = append(warned, f) },
+	}
+
+	path := filepath.Join(filepath.Dir(repo), "wt-copyfail")
+	used, err := m.AddFromRef(g, path, "polecat/copyfail", "main")
+	if err != nil {
+		t.Fatalf("AddFromRef: %v", err)
+	}
+	if used != StrategyWorktree {
+		t.Errorf("strategy = %q, want worktree", used)
+	}
+	if len(warned) == 0 {
+		t.Error("no fallback warning")
+	}
+	if got := runGit(t, path, "rev-parse", "--abbrev-ref", "HEAD"); got != "polecat/copyfail" {
+		t.Errorf("branch = %q", got)
+	}
+	if got := runGit(t, path, "status", "--porcelain"); got != "" {
+		t.Errorf("fallback worktree not clean:\n%s", got)
+	}
+}
+
+func TestMaterializerSparseNeverChecksOutOutsideCone(t *testing.T) {
+	// Any file copy would be a checkout outside git's sparse handling.
+	stubCloneFile(t, func(src, dst string, perm os.FileMode) error {
+		t.Errorf("sparse worktree copied %s", src)
+		return plainCopy(src, dst, perm)
+	})
+	repo := newRepo(t)
+	g := git.NewGit(repo)
+	m := Materializer{
+		Strategy:    StrategyReflink,
+		TemplateDir: filepath.Join(filepath.Dir(repo), TemplateDirName, "repo"),
+		Sparse:      []string{"src"},
+	}
+
+	path := filepath.Join(filepath.Dir(repo), "wt-sparse")
+	used, err := m.AddFromRef(g, path, "polecat/sparse", "main")
+	if err != nil {
+		t.Fatalf("AddFromRef: %v", err)
+	}
+	if used != StrategyWorktree {
+		t.Errorf("strategy = %q, want worktree", used)
+	}
+	for rel, want := range map[string]bool{"src/main.go": true, "src/deep/data.txt": true, "README.md": true, "scripts/run.sh": false} {
+		if _, err := os.Stat(filepath.Join(path, rel)); (err == nil) != want {
+			t.Errorf("%s checked out = %v, want %v", rel, err == nil, want)
+		}
+	}
+	if got := runGit(t, path, "rev-parse", "--abbrev-ref", "HEAD"); got != "polecat/sparse" {
+		t.Errorf("branch = %q", got)
+	}
+	if got := runGit(t, path, "status", "--porcelain"); got != "" {
+		t.Errorf("sparse worktree not clean:\n%s", got)
+	}
+}
+
+func TestValidateRejectsIncompleteMaterialization(t *testing.T) {
+	root := t.TempDir()
+	gitdir := filepath.Join(root, "repo.git", "worktrees", "alpha")
+	writeLinkedWorktree(t, root, gitdir, true)
+	if err := os.WriteFile(filepath.Join(gitdir, pendingFile), []byte("abc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Validate(root, IntegrityOptions{Require: true})
+	if !errors.Is(err, ErrIntegrityViolation) || !errors.Is(err, ErrMaterializationIncomplete) {
+		t.Fatalf("Validate() error = %v, want incomplete materialization", err)
+	}
+}
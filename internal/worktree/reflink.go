@@ -0,0 +1,40 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cloneFile creates dst sharing src's data. Tests substitute a plain copy to
+// exercise materialization on filesystems without reflinks.
+var cloneFile = reflinkFile
+
+var reflinkProbes sync.Map // "srcDir\x00dstDir" -> bool
+
+// reflinkSupported reports whether files in srcDir can be reflinked into
+// dstDir, by cloning a probe file. Cached per directory pair.
+func reflinkSupported(srcDir, dstDir string) bool {
+	key := srcDir + "\x00" + dstDir
+	if ok, found := reflinkProbes.Load(key); found {
+		return ok.(bool)
+	}
+	ok := probeReflink(srcDir, dstDir)
+	reflinkProbes.Store(key, ok)
+	return ok
+}
+
+func probeReflink(srcDir, dstDir string) bool {
+	src, err := os.CreateTemp(srcDir, ".reflink-probe-*")
+	if err != nil {
+		return false
+	}
+	defer os.Remove(src.Name())
+	_, err = src.WriteString("reflink probe\n")
+	if closeErr := src.Close(); err != nil || closeErr != nil {
+		return false
+	}
+	dst := filepath.Join(dstDir, filepath.Base(src.Name())+".clone")
+	defer os.Remove(dst)
+	return cloneFile(src.Name(), dst, 0600) == nil
+}
@@ -0,0 +1,16 @@
+package worktree
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflinkFile creates dst as an APFS clone of src. Fails on filesystems
+// without clone support rather than copying.
+func reflinkFile(src, dst string, perm os.FileMode) error {
+	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW); err != nil {
+		return err
+	}
+	return os.Chmod(dst, perm)
+}
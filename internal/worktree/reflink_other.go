@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package worktree
+
+import (
+	"errors"
+	"os"
+)
+
+func reflinkFile(src, dst string, perm os.FileMode) error {
+	return errors.New("reflinks are not supported on this platform")
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package worktree
+
+import (
+	"os"
+	"syscall"
+)
+
+// allocatedBytes returns the disk space allocated to a file.
+func allocatedBytes(fi os.FileInfo) int64 {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return int64(st.Blocks) * 512 //nolint:unconvert // Blocks is int32 on some platforms
+	}
+	return fi.Size()
+}
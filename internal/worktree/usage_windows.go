@@ -0,0 +1,9 @@
+package worktree
+
+import "os"
+
+// allocatedBytes returns the file size; Windows does not expose allocated
+// blocks through os.FileInfo.
+func allocatedBytes(fi os.FileInfo) int64 {
+	return fi.Size()
+}